@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/pkg/advise"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+var ulogAdvise = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.advise")
+
+func newAdviseCmd() *cobra.Command {
+	cmd := cli.NewStandardCommand("advise", "Suggest how to run agents more economically")
+	cmd.Long = `Commands that read a session and suggest changes to prompts and tool
+configuration.`
+	cmd.AddCommand(newAdviseContextCmd())
+	return cmd
+}
+
+func newAdviseContextCmd() *cobra.Command {
+	var jsonOutput bool
+	var llmCommand string
+
+	cmd := cli.NewStandardCommand("context", "Suggest how a session could have put less into its context")
+	cmd.Use = "context <spec>"
+	cmd.Long = `Combines the tool-call sizes behind 'aglogs stats' with the session's token
+data into concrete suggestions, such as:
+
+  Reads of vendor/ accounted for 41% of input tokens in this session
+  A single Read of build.log added ~520k tokens to the context
+  src/server.go was read 6 times
+
+A call's output is charged for every later turn that carried it in the
+context, so shares are of the session's total input tokens (cache reads
+included). Providers without token data are measured against the tool
+output alone. Subagent (sidechain) activity is excluded.
+
+<spec> can be a plan/job, a session ID, or a direct path to a log file.
+
+The findings are worded heuristically. With --llm, they are passed to an LLM
+command (reading the prompt on stdin, like 'llm -m gpt-4o-mini') to be
+rephrased as advice; the numbers come from aglogs either way. The findings
+pass through the configured redaction first.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		info, err := resolveMetricsSession(spec)
+		if err != nil {
+			return err
+		}
+		startLine, endLine, _ := jobLineRange(info, spec, "")
+		entries, err := provider.SelectSource(info, nil).Read(cmd.Context(), info, provider.ReadOptions{
+			DetailLevel: "full",
+			StartLine:   startLine,
+			EndLine:     endLine,
+		})
+		if err != nil {
+			return fmt.Errorf("error reading transcript: %w", err)
+		}
+		report := advise.Context(entries)
+
+		if jsonOutput {
+			return printJSON(report)
+		}
+		if len(report.Findings) == 0 {
+			ulogAdvise.Info("No findings").
+				Field("session_id", info.SessionID).
+				Pretty(fmt.Sprintf("No context-stuffing patterns found in session %s.", info.SessionID)).
+				PrettyOnly().
+				Emit()
+			return nil
+		}
+		if llmCommand != "" {
+			// Commands and paths in the findings leave aglogs here.
+			redactor, err := loadRedactor()
+			if err != nil {
+				return err
+			}
+			if redactor != nil {
+				for i := range report.Findings {
+					if report.Findings[i].Message, err = redactor.Redact(cmd.Context(), report.Findings[i].Message); err != nil {
+						return err
+					}
+				}
+			}
+			advice, err := phraseAdvice(cmd.Context(), llmCommand, report)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stdout, advice)
+			return nil
+		}
+		printContextAdvice(os.Stdout, info.SessionID, report)
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	cmd.Flags().StringVar(&llmCommand, "llm", "", "Rephrase the findings with this LLM command, which reads the prompt on stdin")
+
+	return cmd
+}
+
+func printContextAdvice(w io.Writer, sessionID string, r advise.ContextReport) {
+	fmt.Fprintf(w, "Context advice for session: %s\n", sessionID)
+	if r.Estimated {
+		fmt.Fprintln(w, "Input tokens:     not recorded (shares are of tool output)")
+	} else {
+		fmt.Fprintf(w, "Input tokens:     %d\n", r.InputTokens)
+	}
+	fmt.Fprintln(w, strings.Repeat("─", 50))
+	for _, f := range r.Findings {
+		fmt.Fprintf(w, "• %s\n", f.Message)
+	}
+}
+
+// phraseAdvice asks an LLM command to rewrite the findings as advice.
+func phraseAdvice(ctx context.Context, command string, r advise.ContextReport) (string, error) {
+	var prompt strings.Builder
+	prompt.WriteString("These findings describe how an AI coding agent filled its context window in one session. " +
+		"Rewrite them as a short list of concrete, actionable suggestions for the person prompting and configuring the agent. " +
+		"Keep every number exactly as given and do not invent new findings.\n\n")
+	for _, f := range r.Findings {
+		prompt.WriteString("- " + f.Message + "\n")
+	}
+	summarizer, err := transcript.NewSummarizer(transcript.SummaryConfig{LLMCommand: command})
+	if err != nil {
+		return "", fmt.Errorf("invalid --llm command")
+	}
+	return summarizer.Summarize(ctx, prompt.String())
+}
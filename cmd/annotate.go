@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/meta"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+var ulogAnnotate = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.annotate")
+
+// annotationOutput is what 'annotate --json' prints.
+type annotationOutput struct {
+	SessionID string         `json:"sessionId"`
+	Tags      []string       `json:"tags"`
+	Notes     []session.Note `json:"notes"`
+}
+
+func newAnnotateCmd() *cobra.Command {
+	var message string
+	var tags, untags []string
+	var clearNotes, jsonOutput bool
+
+	cmd := cli.NewStandardCommand("annotate", "Attach notes and tags to a session")
+	cmd.Use = "annotate <session> [-m note] [-t tag]..."
+	cmd.Long = `Attaches a free-form note (-m) and tags (-t) to a session:
+
+  aglogs annotate 3f2a9c1e -m "this run introduced the regression" -t regression
+
+Tags select sessions in 'aglogs list --tag' and in --where expressions
+(tag=regression), and 'aglogs list --json' prints each session's tags and
+notes. Notes are kept in the order they were added; --clear-notes deletes
+them. Without flags, annotate prints the session's tags and notes.
+
+Annotations are kept with the other session metadata (see 'aglogs meta').`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		info, err := session.ResolveSessionInfo(args[0])
+		if err != nil {
+			return fmt.Errorf("could not resolve session for '%s': %w", args[0], err)
+		}
+		stores, err := meta.LoadStores(meta.DefaultPath())
+		if err != nil {
+			return err
+		}
+
+		message = strings.TrimSpace(message)
+		if message != "" || len(tags) > 0 || len(untags) > 0 || clearNotes {
+			store, err := stores.For(*info)
+			if err != nil {
+				return err
+			}
+			if clearNotes {
+				store.ClearNotes(info.SessionID)
+			}
+			if message != "" {
+				store.AddNote(info.SessionID, message, time.Now())
+			}
+			store.RemoveTags(info.SessionID, untags)
+			store.AddTags(info.SessionID, tags)
+			if err := store.Save(); err != nil {
+				return fmt.Errorf("failed to save annotations: %w", err)
+			}
+		}
+
+		out := annotationOutput{SessionID: info.SessionID, Tags: []string{}, Notes: []session.Note{}}
+		if m := stores.Get(*info); m != nil {
+			out.Tags = append(out.Tags, m.Tags...)
+			out.Notes = append(out.Notes, m.Notes...)
+		}
+		if jsonOutput {
+			return printJSON(out)
+		}
+		tagList := "none"
+		if len(out.Tags) > 0 {
+			tagList = strings.Join(out.Tags, ", ")
+		}
+		ulogAnnotate.Info("Session annotations").
+			Field("session_id", info.SessionID).
+			Field("tags", out.Tags).
+			Field("notes", len(out.Notes)).
+			Pretty(fmt.Sprintf("Session %s\nTags: %s", info.SessionID, tagList)).
+			Emit()
+		for _, n := range out.Notes {
+			fmt.Fprintf(os.Stdout, "  %s  %s\n", n.CreatedAt.Local().Format("2006-01-02 15:04"), n.Text)
+		}
+		return nil
+	}
+
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Add a note to the session")
+	cmd.Flags().StringSliceVarP(&tags, "tag", "t", nil, "Tag the session (repeatable or comma-separated)")
+	cmd.Flags().StringSliceVar(&untags, "untag", nil, "Remove tags from the session (repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&clearNotes, "clear-notes", false, "Delete the session's notes (before adding -m)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the session's tags and notes as JSON")
+	return cmd
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/archivestore"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+var ulogArchive = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.archive")
+
+func newArchiveCmd() *cobra.Command {
+	var force, compress, jsonOutput bool
+	var providerFlag, whereFlag, dest string
+
+	cmd := cli.NewStandardCommand("archive", "Snapshot a session's transcript into its plan's artifacts")
+	cmd.Use = "archive <plan/job|session> | --where <expr>"
+	cmd.Long = `Copies a session's transcript, with a generated metadata.json, into the
+plan's .artifacts/<job>/ directory, so the job's log survives the provider
+deleting old sessions. Archived sessions are found, listed and read like
+live ones.
+
+Each job of the session gets its own directory holding only its part of the
+transcript. Once a session is archived it is read from its archives instead
+of the live log, so archiving one job of a session archives all of them.
+Pi transcripts are copied whole; OpenCode sessions cannot be archived (use
+'aglogs export').
+
+An archive is a snapshot: re-run with --force to refresh it from a session
+that has continued since. --compress writes the transcript gzipped
+(transcript.jsonl.gz); compressed archives are read like any other.
+
+With --where, every matching session linked to plan jobs is archived.
+Sessions without jobs and jobs archived before (unless --force) are
+skipped; a job that fails to archive is reported and the rest go on.
+
+--dest uploads the archives to object storage instead, for retention
+beyond the plan directory: s3://bucket/prefix (through the aws CLI),
+gs://bucket/prefix (through gcloud) or file:///dir. Each job goes to
+<session-id>/<job-id>/ under the prefix, with its metadata.json uploaded
+last. Sessions already archived locally are uploaded from their archives.
+List the remote archive in the aglogs config (archive.remotes) and 'aglogs
+list --include-archived' lists its sessions; reading one fetches its
+transcript to a local cache.
+
+` + whereHelp
+	cmd.Args = cobra.RangeArgs(0, 1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if (len(args) == 1) == (whereFlag != "") {
+			return fmt.Errorf("give either a session or --where")
+		}
+		opts := session.ArchiveOptions{Force: force, Compress: compress}
+		var store archivestore.Store
+		if dest != "" {
+			var err error
+			if store, err = archivestore.Open(dest); err != nil {
+				return err
+			}
+		}
+		if whereFlag != "" {
+			return archiveWhere(cmd.Context(), whereFlag, opts, store, jsonOutput)
+		}
+
+		var providers []string
+		if providerFlag != "" {
+			var err error
+			if providers, err = parseProviderFlag(providerFlag); err != nil {
+				return err
+			}
+		}
+		info, err := session.ResolveSessionInfoWithOptions(args[0], session.ResolveOptions{Providers: providers})
+		if err != nil {
+			return fmt.Errorf("could not resolve session for '%s': %w", args[0], err)
+		}
+		if len(info.Jobs) == 0 {
+			return fmt.Errorf("session %s is not linked to a plan job; only plan jobs can be archived", info.SessionID)
+		}
+
+		results := make([]session.ArchiveResult, 0, len(info.Jobs))
+		for i := range info.Jobs {
+			res, err := archiveJob(cmd.Context(), *info, i, opts, store)
+			if err != nil {
+				return err
+			}
+			results = append(results, res)
+		}
+
+		if jsonOutput {
+			return printJSON(results)
+		}
+		for _, res := range results {
+			logArchived(info.SessionID, res)
+		}
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing archives")
+	cmd.Flags().BoolVar(&compress, "compress", false, "Gzip the archived transcripts")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the archived jobs as JSON")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Resolve the spec to a session from this provider")
+	cmd.Flags().StringVar(&whereFlag, "where", "", "Archive every session matching this filter expression instead of one session")
+	cmd.Flags().StringVar(&dest, "dest", "", "Upload the archives to this object storage URL (s3://bucket/prefix, gs://bucket/prefix, file:///dir)")
+
+	return cmd
+}
+
+// archiveJob archives a job to store, or to its plan's artifacts when store
+// is nil.
+func archiveJob(ctx context.Context, info session.SessionInfo, i int, opts session.ArchiveOptions, store archivestore.Store) (session.ArchiveResult, error) {
+	if store != nil {
+		return session.UploadJob(ctx, info, i, store, opts)
+	}
+	return session.ArchiveJob(info, i, opts)
+}
+
+// remoteArchiveSessions lists the sessions in the configured remote
+// archives that are not among known, leaving out those started outside
+// [since, until] (zero bounds are open).
+func remoteArchiveSessions(ctx context.Context, known []session.SessionInfo, since, until time.Time) ([]session.SessionInfo, error) {
+	stores, err := session.RemoteStores()
+	if err != nil {
+		return nil, err
+	}
+	if len(stores) == 0 {
+		return nil, fmt.Errorf("no remote archives configured; list them under archive.remotes in the aglogs config")
+	}
+	seen := make(map[string]bool, len(known))
+	for _, s := range known {
+		seen[s.SessionID] = true
+	}
+	var remote []session.SessionInfo
+	for _, store := range stores {
+		sessions, err := session.RemoteSessions(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sessions {
+			if seen[s.SessionID] || (!since.IsZero() && s.StartedAt.Before(since)) || (!until.IsZero() && s.StartedAt.After(until)) {
+				continue
+			}
+			remote = append(remote, s)
+		}
+	}
+	return remote, nil
+}
+
+func logArchived(sessionID string, res session.ArchiveResult) {
+	ulogArchive.Info("Archived job transcript").
+		Field("session_id", sessionID).
+		Field("job", res.Job.Plan+"/"+res.Job.Job).
+		Field("dir", res.Dir).
+		Field("lines", res.Lines).
+		Pretty(fmt.Sprintf("Archived %s/%s (%d lines) to %s", res.Job.Plan, res.Job.Job, res.Lines, res.Dir)).
+		Emit()
+}
+
+// archiveWhere archives the jobs of every session matching a --where
+// expression, to store when it is not nil.
+func archiveWhere(ctx context.Context, where string, opts session.ArchiveOptions, store archivestore.Store, jsonOutput bool) error {
+	if jsonOutput {
+		grovelogging.SetGlobalOutput(os.Stderr)
+	}
+	sessions, err := whereSessions(ctx, where)
+	if err != nil {
+		return err
+	}
+	results := []session.ArchiveResult{}
+	bar := newProgress("Archiving sessions")
+	bar.SetTotal(len(sessions))
+	var skipped, failed int
+	for _, info := range sessions {
+		if err := ctx.Err(); err != nil {
+			bar.Finish()
+			return fmt.Errorf("interrupted after archiving %d job(s): %w", len(results), err)
+		}
+		bar.Add(1)
+		if len(info.Jobs) == 0 {
+			continue
+		}
+		if info.Archived() && store == nil {
+			skipped += len(info.Jobs)
+			continue
+		}
+		for i := range info.Jobs {
+			res, err := archiveJob(ctx, info, i, opts, store)
+			if errors.Is(err, session.ErrAlreadyArchived) {
+				skipped++
+				continue
+			}
+			bar.Clear()
+			if err != nil {
+				failed++
+				ulogArchive.Warn("Failed to archive job").Err(err).Field("session_id", info.SessionID).
+					Pretty(fmt.Sprintf("Failed to archive %s: %v", info.SessionID, err)).Emit()
+				continue
+			}
+			results = append(results, res)
+			if !jsonOutput {
+				logArchived(info.SessionID, res)
+			}
+		}
+	}
+	bar.Finish()
+
+	if jsonOutput {
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		ulogArchive.Info("Archived matching sessions").
+			Field("sessions", len(sessions)).
+			Field("archived", len(results)).
+			Field("skipped", skipped).
+			Pretty(fmt.Sprintf("Archived %d job(s) from %d matching session(s); %d already archived", len(results), len(sessions), skipped)).
+			Emit()
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to archive %d job(s)", failed)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+var ulogAttachments = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.attachments")
+
+// attachmentFile is an attachment as listed or extracted.
+type attachmentFile struct {
+	transcript.Attachment
+	// Path is where the image was written, with --out.
+	Path string `json:"path,omitempty"`
+}
+
+func newAttachmentsCmd() *cobra.Command {
+	var outDir, jobFlag, providerFlag string
+	var jsonOutput bool
+
+	cmd := cli.NewStandardCommand("attachments", "List or extract the images in a session")
+	cmd.Use = "attachments <spec>"
+	cmd.Long = `Lists the images embedded in a session's transcript: screenshots the user
+pasted and images tools returned (screenshots, image file reads). With --out
+they are written to that directory as 001.png, 002.jpg, ... in transcript
+order. Elsewhere (read, export) images appear as placeholders such as
+[image: 1.2MB png].
+
+Only images whose data is recorded in the transcript can be extracted;
+Claude is the only provider that records them.
+
+<spec> is a session ID (or unique prefix), a plan/job, or a log file path.
+A plan/job spec (or --job) covers only that job's part of the transcript.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		info, entries, err := loadScopedTranscript(cmd, args[0], jobFlag, providerFlag)
+		if err != nil {
+			return err
+		}
+		found := transcript.Attachments(entries)
+		files := make([]attachmentFile, len(found))
+		for i, a := range found {
+			files[i] = attachmentFile{Attachment: a}
+		}
+
+		if outDir != "" && len(files) > 0 {
+			if err := os.MkdirAll(outDir, 0o755); err != nil {
+				return fmt.Errorf("failed to create output directory: %w", err)
+			}
+			written := 0
+			for i := range files {
+				data, err := files[i].Image.Decode()
+				if err != nil {
+					ulogAttachments.Warn("Skipping image").Err(err).Field("index", i+1).
+						Pretty(fmt.Sprintf("Skipping image %d: %v", i+1, err)).Emit()
+					continue
+				}
+				path := filepath.Join(outDir, fmt.Sprintf("%03d.%s", i+1, files[i].Image.Ext()))
+				if err := os.WriteFile(path, data, 0o644); err != nil {
+					return fmt.Errorf("failed to write image: %w", err)
+				}
+				files[i].Path = path
+				written++
+			}
+			ulogAttachments.Info("Extracted images").
+				Field("session_id", info.SessionID).
+				Field("dir", outDir).
+				Field("images", written).
+				Pretty(fmt.Sprintf("Wrote %d image(s) to %s", written, outDir)).
+				PrettyOnly().
+				Emit()
+		}
+
+		if jsonOutput {
+			return printJSON(files)
+		}
+		if len(files) == 0 {
+			ulogAttachments.Info("No images found").
+				Field("session_id", info.SessionID).
+				Pretty("No images were found in this session.").
+				PrettyOnly().
+				Emit()
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "#\tTIME\tFROM\tIMAGE\tFILE")
+		for i, f := range files {
+			from := f.Role
+			if f.Tool != "" {
+				from = "tool " + f.Tool
+			}
+			when := ""
+			if !f.Timestamp.IsZero() {
+				when = f.Timestamp.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", i+1, when, from, f.Image.Placeholder(), f.Path)
+		}
+		return tw.Flush()
+	}
+
+	cmd.Flags().StringVarP(&outDir, "out", "o", "", "Write the images to this directory")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the images as JSON")
+	cmd.Flags().StringVar(&jobFlag, "job", "", "Only include this plan/job's part of the session")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Resolve the spec to a session from this provider")
+
+	return cmd
+}
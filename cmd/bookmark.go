@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/meta"
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/display"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+var ulogBookmark = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.bookmark")
+
+func newBookmarkCmd() *cobra.Command {
+	cmd := cli.NewStandardCommand("bookmark", "Name moments in session transcripts to come back to")
+	cmd.Long = `Bookmarks name a line of a session's transcript, such as where a bug was
+reproduced, so you can return to it later. A bookmark is written
+<session>#<name>, which is also what to send a teammate:
+
+  aglogs bookmark add 3f2a9c1e --line 412 --name "bug repro"
+  aglogs bookmark read "3f2a9c1e#bug repro"
+
+Lines are 1-based lines of the transcript file, as 'grep -n' prints them.
+Bookmarks are kept with the other session metadata (see 'aglogs meta').`
+	cmd.AddCommand(newBookmarkAddCmd())
+	cmd.AddCommand(newBookmarkListCmd())
+	cmd.AddCommand(newBookmarkReadCmd())
+	cmd.AddCommand(newBookmarkRemoveCmd())
+	return cmd
+}
+
+func newBookmarkAddCmd() *cobra.Command {
+	var line int
+	var name, note string
+	var replace bool
+
+	cmd := cli.NewStandardCommand("add", "Bookmark a line of a session's transcript")
+	cmd.Use = "add <session> --line N --name NAME"
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		info, err := session.ResolveSessionInfo(args[0])
+		if err != nil {
+			return fmt.Errorf("could not resolve session for '%s': %w", args[0], err)
+		}
+		if info.LogFilePath != "" {
+			if n, err := countTranscriptLines(info.LogFilePath); err == nil && line > n {
+				return fmt.Errorf("--line %d is past the end of the transcript (%d lines)", line, n)
+			}
+		}
+		stores, err := meta.LoadStores(meta.DefaultPath())
+		if err != nil {
+			return err
+		}
+		store, err := stores.For(*info)
+		if err != nil {
+			return err
+		}
+		b := meta.Bookmark{Name: strings.TrimSpace(name), Line: line, Note: note, CreatedAt: time.Now().UTC()}
+		if err := store.AddBookmark(info.SessionID, b, replace); err != nil {
+			return err
+		}
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save bookmark: %w", err)
+		}
+		ulogBookmark.Info("Added bookmark").
+			Field("session_id", info.SessionID).
+			Field("name", b.Name).
+			Field("line", b.Line).
+			Pretty(fmt.Sprintf("Bookmarked line %d as %s", b.Line, bookmarkSpec(info.SessionID, b.Name))).
+			Emit()
+		return nil
+	}
+
+	cmd.Flags().IntVar(&line, "line", 0, "Line of the transcript file to bookmark (1-based)")
+	cmd.Flags().StringVar(&name, "name", "", "Name of the bookmark")
+	cmd.Flags().StringVar(&note, "note", "", "A note to keep with the bookmark")
+	cmd.Flags().BoolVar(&replace, "replace", false, "Replace an existing bookmark of the same name")
+	_ = cmd.MarkFlagRequired("line")
+	_ = cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+// bookmarkRow is one bookmark listed by 'bookmark list'.
+type bookmarkRow struct {
+	Spec      string `json:"spec"`
+	SessionID string `json:"sessionId"`
+	meta.Bookmark
+}
+
+func newBookmarkListCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := cli.NewStandardCommand("list", "List bookmarks")
+	cmd.Use = "list [session]"
+	cmd.Long = `Lists the bookmarks of a session, or without one, every bookmark on this
+machine and in the current project's metadata file.`
+	cmd.Args = cobra.MaximumNArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		stores, err := meta.LoadStores(meta.DefaultPath())
+		if err != nil {
+			return err
+		}
+		rows := []bookmarkRow{}
+		if len(args) == 1 {
+			info, err := session.ResolveSessionInfo(args[0])
+			if err != nil {
+				return fmt.Errorf("could not resolve session for '%s': %w", args[0], err)
+			}
+			if m := stores.Get(*info); m != nil {
+				rows = appendBookmarkRows(rows, info.SessionID, m)
+			}
+		} else {
+			all := []*meta.Store{stores.Home}
+			if dir := findWorkspace(); dir != "" {
+				if ws, err := stores.Workspace(dir); err == nil && ws != nil {
+					all = append(all, ws)
+				}
+			}
+			for _, s := range all {
+				for id, m := range s.Sessions {
+					rows = appendBookmarkRows(rows, id, m)
+				}
+			}
+			sort.SliceStable(rows, func(i, j int) bool { return rows[i].CreatedAt.After(rows[j].CreatedAt) })
+		}
+
+		if jsonOutput {
+			return printJSON(rows)
+		}
+		if len(rows) == 0 {
+			ulogBookmark.Info("No bookmarks").Pretty("No bookmarks.").PrettyOnly().Emit()
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(tw, "BOOKMARK\tLINE\tCREATED\tNOTE")
+		for _, r := range rows {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", r.Spec, r.Line, r.CreatedAt.Local().Format("2006-01-02 15:04"), r.Note)
+		}
+		return tw.Flush()
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the bookmarks as JSON")
+	return cmd
+}
+
+func appendBookmarkRows(rows []bookmarkRow, sessionID string, m *meta.SessionMeta) []bookmarkRow {
+	for _, b := range m.Bookmarks {
+		rows = append(rows, bookmarkRow{Spec: bookmarkSpec(sessionID, b.Name), SessionID: sessionID, Bookmark: b})
+	}
+	return rows
+}
+
+func newBookmarkReadCmd() *cobra.Command {
+	var lines int
+	var detailLevel, styleFlag string
+
+	cmd := cli.NewStandardCommand("read", "Read a session's transcript from a bookmark")
+	cmd.Use = "read <session>#<name>"
+	cmd.Long = `Prints the part of a session's transcript starting at a bookmarked line:
+--lines lines of the transcript file (0 reads to the end).`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		style, err := display.ParseRenderStyle(styleFlag)
+		if err != nil {
+			return err
+		}
+		info, b, err := resolveBookmark(args[0])
+		if err != nil {
+			return err
+		}
+		opts := provider.ReadOptions{DetailLevel: detailLevel, StartLine: b.Line - 1, EndLine: -1}
+		if lines > 0 {
+			opts.EndLine = opts.StartLine + lines
+		}
+		entries, err := provider.SelectSource(info, nil).Read(cmd.Context(), info, opts)
+		if err != nil {
+			return fmt.Errorf("failed to read transcript: %w", err)
+		}
+
+		title := fmt.Sprintf("%s (line %d)", bookmarkSpec(info.SessionID, b.Name), b.Line)
+		if b.Note != "" {
+			title += ": " + b.Note
+		}
+		if style == display.StyleMarkdown {
+			fmt.Fprintf(os.Stdout, "**%s**\n\n", title)
+		} else {
+			fmt.Fprintf(os.Stdout, "%s\n\n", title)
+		}
+		renderOpts := display.RenderOptions{Style: style, DetailLevel: detailLevel}
+		return display.RenderUnifiedTranscript(os.Stdout, entries, renderOpts, display.DefaultToolFormatters())
+	}
+
+	cmd.Flags().IntVar(&lines, "lines", 40, "Lines of the transcript file to read from the bookmark (0 = to the end)")
+	cmd.Flags().StringVar(&detailLevel, "detail", "summary", "Detail level for tool blocks ('summary' or 'full')")
+	cmd.Flags().StringVar(&styleFlag, "style", "terminal", "Output style: 'terminal' (colors/icons) or 'markdown' (environment-independent)")
+	return cmd
+}
+
+func newBookmarkRemoveCmd() *cobra.Command {
+	cmd := cli.NewStandardCommand("remove", "Delete a bookmark")
+	cmd.Use = "remove <session>#<name>"
+	cmd.Aliases = []string{"rm"}
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec, name, err := parseBookmarkSpec(args[0])
+		if err != nil {
+			return err
+		}
+		info, err := session.ResolveSessionInfo(spec)
+		if err != nil {
+			return fmt.Errorf("could not resolve session for '%s': %w", spec, err)
+		}
+		stores, err := meta.LoadStores(meta.DefaultPath())
+		if err != nil {
+			return err
+		}
+		store, err := stores.For(*info)
+		if err != nil {
+			return err
+		}
+		if !store.RemoveBookmark(info.SessionID, name) {
+			return fmt.Errorf("session %s has no bookmark named %q", info.SessionID, name)
+		}
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save bookmarks: %w", err)
+		}
+		ulogBookmark.Info("Removed bookmark").
+			Field("session_id", info.SessionID).
+			Field("name", name).
+			Pretty(fmt.Sprintf("Removed bookmark %s", bookmarkSpec(info.SessionID, name))).
+			Emit()
+		return nil
+	}
+	return cmd
+}
+
+// bookmarkSpec writes a bookmark the way the bookmark commands read it.
+func bookmarkSpec(sessionID, name string) string {
+	return sessionID + "#" + name
+}
+
+// parseBookmarkSpec splits "<session>#<name>" at the first '#': session
+// specs have none, bookmark names may.
+func parseBookmarkSpec(arg string) (spec, name string, err error) {
+	spec, name, ok := strings.Cut(arg, "#")
+	if !ok || spec == "" || name == "" {
+		return "", "", fmt.Errorf("invalid bookmark %q: expected <session>#<name>", arg)
+	}
+	return spec, name, nil
+}
+
+// resolveBookmark finds the session and bookmark a spec names.
+func resolveBookmark(arg string) (*session.SessionInfo, *meta.Bookmark, error) {
+	spec, name, err := parseBookmarkSpec(arg)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := session.ResolveSessionInfo(spec)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not resolve session for '%s': %w", spec, err)
+	}
+	stores, err := meta.LoadStores(meta.DefaultPath())
+	if err != nil {
+		return nil, nil, err
+	}
+	b := stores.Get(*info).Bookmark(name)
+	if b == nil {
+		return nil, nil, fmt.Errorf("session %s has no bookmark named %q", info.SessionID, name)
+	}
+	return info, b, nil
+}
+
+// findWorkspace returns the project directory at or above the current one
+// that keeps its own session metadata, or "".
+func findWorkspace() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(meta.WorkspacePath(dir)); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// countTranscriptLines counts the lines of a transcript file.
+func countTranscriptLines(path string) (int, error) {
+	f, err := transcript.OpenTranscript(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	sc := transcript.NewLineScanner(f)
+	n := 0
+	for sc.Scan() {
+		n++
+	}
+	return n, sc.Err()
+}
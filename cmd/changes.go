@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/changes"
+)
+
+var ulogChanges = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.changes")
+
+func newChangesCmd() *cobra.Command {
+	var jsonOutput, showDiff bool
+	var outputFile, jobFlag, providerFlag string
+
+	cmd := cli.NewStandardCommand("changes", "List the files a session created, modified or deleted")
+	cmd.Use = "changes <spec>"
+	cmd.Long = `Walks a session's tool calls — Claude Write/Edit/MultiEdit, pi and OpenCode
+write/edit, Codex apply_patch, OpenCode snapshot patches — and reports every
+file the agent created, modified or deleted, with added/removed line counts.
+Failed tool calls are ignored; file changes made by shell commands are not
+seen, except for Codex apply_patch heredocs and OpenCode snapshots.
+
+--diff prints the changes as one unified diff, and --output writes it to a
+patch file to replay the session's edits elsewhere:
+
+  aglogs changes plan/01-api.md -o api.patch
+  git apply --unidiff-zero api.patch    # or: patch -p1 < api.patch
+
+Transcripts record edits rather than files, so edit hunks carry no line
+numbers or surrounding context and are located by content when applied
+(hence --unidiff-zero, which lets git search for them). Changes whose
+content was not recorded (deletions, snapshot-only changes) are marked "?"
+in the list and left as comments in the patch.
+
+<spec> is a session ID (or unique prefix), a plan/job, or a log file path.
+A plan/job spec (or --job) covers only that job's part of the transcript.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		info, entries, err := loadScopedTranscript(cmd, args[0], jobFlag, providerFlag)
+		if err != nil {
+			return err
+		}
+		files := changes.Extract(entries, session.ReadWorkingDirectory(info.LogFilePath, info.Provider))
+
+		if outputFile != "" {
+			var buf bytes.Buffer
+			if err := changes.WritePatch(&buf, files); err != nil {
+				return err
+			}
+			if err := os.WriteFile(outputFile, buf.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("failed to write patch: %w", err)
+			}
+			ulogChanges.Info("Wrote patch").
+				Field("session_id", info.SessionID).
+				Field("path", outputFile).
+				Field("files", len(files)).
+				Pretty(fmt.Sprintf("Wrote changes to %d file(s) to %s", len(files), outputFile)).
+				Emit()
+			return nil
+		}
+		if jsonOutput {
+			return printJSON(files)
+		}
+		if showDiff {
+			return changes.WritePatch(os.Stdout, files)
+		}
+		if len(files) == 0 {
+			ulogChanges.Info("No file changes found").
+				Field("session_id", info.SessionID).
+				Pretty("No file changes were recorded in this session.").
+				PrettyOnly().
+				Emit()
+			return nil
+		}
+		return printChanges(files)
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the changes, with per-edit hunks, as JSON")
+	cmd.Flags().BoolVar(&showDiff, "diff", false, "Print the changes as a unified diff")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write the changes as a patch file")
+	cmd.Flags().StringVar(&jobFlag, "job", "", "Only include this plan/job's part of the session")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Resolve the spec to a session from this provider")
+
+	return cmd
+}
+
+func printChanges(files []changes.FileChange) error {
+	var added, removed int
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, fc := range files {
+		added += fc.Added
+		removed += fc.Removed
+		stats := fmt.Sprintf("+%d\t-%d", fc.Added, fc.Removed)
+		if fc.Incomplete {
+			stats = "?\t"
+			if fc.Added+fc.Removed > 0 {
+				stats = fmt.Sprintf("+%d?\t-%d?", fc.Added, fc.Removed)
+			}
+		}
+		note := ""
+		if len(fc.Edits) > 1 {
+			note = fmt.Sprintf("%d edits", len(fc.Edits))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", fc.Kind, fc.Path, stats, note)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d file(s) changed, +%d -%d\n", len(files), added, removed)
+	return nil
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/grovetools/core/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/pkg/readiness"
+)
+
+func newCheckCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := cli.NewStandardCommand("check", "Check whether a session looks ready for a pull request")
+	cmd.Use = "check <spec>"
+	cmd.Long = `Checks a session's transcript against common finish criteria and prints a
+pass/fail line for each:
+
+  tests     the last test run after the agent's last file edit passed
+            (go test, cargo test, npm test, pytest, make test, ...)
+  todos     every item of the agent's last todo list (TodoWrite,
+            update_plan) is completed or cancelled
+  failures  no tool call failed after the last file edit
+
+The checks are heuristics over what the transcript records. Exits non-zero
+when any check fails, so it can gate a plan job before review.
+
+<spec> can be a plan/job, a session ID, or a direct path to a log file.
+Subagent (sidechain) activity is excluded.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		sessionInfo, err := resolveMetricsSession(spec)
+		if err != nil {
+			return err
+		}
+		startLine, endLine, _ := jobLineRange(sessionInfo, spec, "")
+
+		src := provider.SelectSource(sessionInfo, nil)
+		entries, err := src.Read(cmd.Context(), sessionInfo, provider.ReadOptions{
+			DetailLevel: "full",
+			StartLine:   startLine,
+			EndLine:     endLine,
+		})
+		if err != nil {
+			return fmt.Errorf("error reading transcript: %w", err)
+		}
+
+		report := readiness.Evaluate(entries)
+		if jsonOutput {
+			if err := printJSON(report); err != nil {
+				return err
+			}
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, c := range report.Checks {
+				result := "pass"
+				if !c.Passed {
+					result = "FAIL"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", result, c.Name, c.Detail)
+			}
+			w.Flush()
+		}
+
+		if failed := report.Failed(); len(failed) > 0 {
+			return fmt.Errorf("%d of %d checks failed for session %s", len(failed), len(report.Checks), sessionInfo.SessionID)
+		}
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the checks as JSON")
+
+	return cmd
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/shellcmds"
+)
+
+var ulogCommands = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.commands")
+
+func newCommandsCmd() *cobra.Command {
+	var jsonOutput, failedOnly bool
+	var exportFile, jobFlag, providerFlag string
+
+	cmd := cli.NewStandardCommand("commands", "List the shell commands a session ran, with exit codes and durations")
+	cmd.Use = "commands <spec>"
+	cmd.Long = `Lists every shell tool invocation in a session — Claude Bash, Codex shell and
+exec_command, OpenCode and pi bash — in order, with its exit code and run time.
+
+Exit codes come from the log where it records them (Codex, OpenCode) and
+from the tool output otherwise; "failed" means the provider only reported
+an error, "ok" only success, and "no result" that the command was still
+running or interrupted when the log ends.
+
+--export writes the commands as a bash script that replays them in order,
+with a cd into the session's working directory (and into each command's own
+directory when the call names one) and a comment recording how each ended.
+Read it before running it.
+
+<spec> is a session ID (or unique prefix), a plan/job, or a log file path.
+A plan/job spec (or --job) covers only that job's part of the transcript.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		info, entries, err := loadScopedTranscript(cmd, args[0], jobFlag, providerFlag)
+		if err != nil {
+			return err
+		}
+		cmds := shellcmds.Extract(entries)
+		if failedOnly {
+			cmds = shellcmds.Failed(cmds)
+		}
+
+		if exportFile != "" {
+			header := fmt.Sprintf("Shell commands from %s session %s", info.Provider, info.SessionID)
+			if failedOnly {
+				header += " (failed only)"
+			}
+			var buf bytes.Buffer
+			root := session.ReadWorkingDirectory(info.LogFilePath, info.Provider)
+			if err := shellcmds.WriteScript(&buf, cmds, header, root); err != nil {
+				return err
+			}
+			if err := os.WriteFile(exportFile, buf.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("failed to write script: %w", err)
+			}
+			ulogCommands.Info("Exported commands").
+				Field("session_id", info.SessionID).
+				Field("path", exportFile).
+				Field("commands", len(cmds)).
+				Pretty(fmt.Sprintf("Wrote %d command(s) to %s", len(cmds), exportFile)).
+				Emit()
+			return nil
+		}
+		if jsonOutput {
+			if cmds == nil {
+				cmds = []shellcmds.Command{}
+			}
+			return printJSON(cmds)
+		}
+		if len(cmds) == 0 {
+			msg := "No shell commands were run in this session."
+			if failedOnly {
+				msg = "No shell commands failed in this session."
+			}
+			ulogCommands.Info("No shell commands found").
+				Field("session_id", info.SessionID).
+				Pretty(msg).
+				PrettyOnly().
+				Emit()
+			return nil
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(tw, "SEQ\tTIME\tSTATUS\tDURATION\tCOMMAND")
+		failed := 0
+		for _, c := range cmds {
+			when, took := "-", "-"
+			if !c.Timestamp.IsZero() {
+				when = c.Timestamp.Local().Format("15:04:05")
+			}
+			if c.DurationMs > 0 {
+				took = formatSeconds(float64(c.DurationMs) / 1000)
+			}
+			if c.Failed {
+				failed++
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", c.Seq, when, c.Status(), took, truncateCommand(c.Command, 100))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if !failedOnly {
+			fmt.Printf("\n%d command(s), %d failed\n", len(cmds), failed)
+		}
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the commands, with their output, as JSON")
+	cmd.Flags().BoolVar(&failedOnly, "failed-only", false, "Only include commands that failed")
+	cmd.Flags().StringVar(&exportFile, "export", "", "Write the commands as a bash script to this file")
+	cmd.Flags().StringVar(&jobFlag, "job", "", "Only include this plan/job's part of the session")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Resolve the spec to a session from this provider")
+
+	return cmd
+}
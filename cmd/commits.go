@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/gitlog"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+var ulogCommits = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.commits")
+
+// commitGrace extends a session's window past its last entry, so a commit
+// the agent's final command made is still included.
+const commitGrace = 2 * time.Minute
+
+// sessionCommits is what 'aglogs commits --json' prints.
+type sessionCommits struct {
+	Session  string          `json:"session"`
+	Worktree string          `json:"worktree"`
+	Branch   string          `json:"branch,omitempty"` // empty when every branch was searched
+	Since    time.Time       `json:"since"`
+	Until    time.Time       `json:"until"`
+	Commits  []gitlog.Commit `json:"commits"`
+}
+
+func newCommitsCmd() *cobra.Command {
+	var jsonOutput bool
+	var jobFlag, providerFlag string
+
+	cmd := cli.NewStandardCommand("commits", "List the git commits made during a session")
+	cmd.Use = "commits <spec>"
+	cmd.Long = `Lists the commits made on a session's branch, in the directory it ran in,
+between its first and last transcript entries (plus a couple of minutes for
+a commit made by the last command), newest first. It ties what an agent did
+to what actually landed in the repository, whether the agent committed
+itself or the user did while it ran.
+
+The branch is the one the transcript records the session starting on
+(Claude and Codex record it), else the one checked out in the directory
+now. When that branch no longer exists, every branch is searched instead.
+Commit times are committer dates, so rebased commits count when they were
+rebased.
+
+<spec> is a session ID (or unique prefix), a plan/job, or a log file path.
+A plan/job spec (or --job) covers only that job's part of the transcript.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		info, entries, err := loadScopedTranscript(cmd, args[0], jobFlag, providerFlag)
+		if err != nil {
+			return err
+		}
+		dir := session.ReadWorkingDirectory(info.LogFilePath, info.Provider)
+		if dir == "" {
+			dir = info.ProjectPath
+		}
+		if dir == "" {
+			return fmt.Errorf("session %s does not record the directory it ran in", info.SessionID)
+		}
+		if _, err := os.Stat(dir); err != nil {
+			return fmt.Errorf("session %s ran in %s, which no longer exists", info.SessionID, dir)
+		}
+		since, until := transcriptWindow(info, entries)
+		if since.IsZero() {
+			return fmt.Errorf("session %s has no timestamps to bound its commits", info.SessionID)
+		}
+
+		branch := info.GitBranch
+		if branch == "" {
+			branch, _ = session.ReadGitInfo(info.LogFilePath, info.Provider)
+		}
+		if branch == "" {
+			branch = "HEAD"
+		}
+		if !gitlog.RefExists(cmd.Context(), dir, branch) {
+			ulogCommits.Warn("Branch not found").
+				Field("session_id", info.SessionID).
+				Field("branch", branch).
+				Pretty(fmt.Sprintf("Branch %s no longer exists in %s; searching every branch.", branch, dir)).
+				PrettyOnly().
+				Emit()
+			branch = ""
+		}
+		commits, err := gitlog.Between(cmd.Context(), dir, branch, since, until.Add(commitGrace))
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(sessionCommits{Session: info.SessionID, Worktree: dir, Branch: branch, Since: since, Until: until, Commits: commits})
+		}
+		if len(commits) == 0 {
+			ulogCommits.Info("No commits found").
+				Field("session_id", info.SessionID).
+				Pretty(fmt.Sprintf("No commits were made in %s between %s and %s.", dir, since.Local().Format("2006-01-02 15:04"), until.Local().Format("15:04"))).
+				PrettyOnly().
+				Emit()
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, c := range commits {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Short(), c.Time.Local().Format("2006-01-02 15:04"), c.Author, c.Subject)
+		}
+		return w.Flush()
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the window and commits as JSON")
+	cmd.Flags().StringVar(&jobFlag, "job", "", "Only include commits made during this plan/job's part of the session")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Resolve the spec to a session from this provider")
+
+	return cmd
+}
+
+// transcriptWindow is the span of the entries' timestamps, falling back to
+// the session's start and last activity for entries without any.
+func transcriptWindow(info *session.SessionInfo, entries []transcript.UnifiedEntry) (since, until time.Time) {
+	for _, e := range entries {
+		if e.Timestamp.IsZero() {
+			continue
+		}
+		if since.IsZero() || e.Timestamp.Before(since) {
+			since = e.Timestamp
+		}
+		if e.Timestamp.After(until) {
+			until = e.Timestamp
+		}
+	}
+	if since.IsZero() {
+		since, until = info.StartedAt, info.EndedAt
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+	return since, until
+}
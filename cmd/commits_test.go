@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestTranscriptWindow(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	info := &session.SessionInfo{StartedAt: start, EndedAt: start.Add(3 * time.Hour)}
+
+	entries := []transcript.UnifiedEntry{
+		{Timestamp: start.Add(time.Hour)},
+		{}, // entries without a time are skipped
+		{Timestamp: start.Add(90 * time.Minute)},
+	}
+	since, until := transcriptWindow(info, entries)
+	if !since.Equal(start.Add(time.Hour)) || !until.Equal(start.Add(90*time.Minute)) {
+		t.Errorf("window = %v..%v, want the entries' span", since, until)
+	}
+
+	// Without timestamps the session's own span stands in.
+	since, until = transcriptWindow(info, []transcript.UnifiedEntry{{}})
+	if !since.Equal(info.StartedAt) || !until.Equal(info.EndedAt) {
+		t.Errorf("window = %v..%v, want the session's span", since, until)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/index"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+// sessionCatalog returns the cached session catalog, rescanning when it is
+// missing or stale.
+func sessionCatalog() *index.Catalog {
+	now := time.Now()
+	if c := index.LoadCatalog(index.CatalogPath()); c != nil && !c.Stale(now) {
+		return c
+	}
+	sessions, err := session.NewScanner().Scan()
+	if err != nil {
+		return nil
+	}
+	c := index.NewCatalog(sessions, now)
+	_ = c.Save(index.CatalogPath())
+	return c
+}
+
+// rememberSessions refreshes the completion catalog after a full scan.
+func rememberSessions(sessions []session.SessionInfo) {
+	if err := index.NewCatalog(sessions, time.Now()).Save(index.CatalogPath()); err != nil {
+		ulogList.Debug("Failed to save session catalog").Err(err).Emit()
+	}
+}
+
+// completeSessionIDs completes the first argument with known session IDs,
+// described by project and start time.
+func completeSessionIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeFromCatalog(args, toComplete, false)
+}
+
+// completeSessionSpecs completes the first argument with session IDs and
+// plan/job specs.
+func completeSessionSpecs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeFromCatalog(args, toComplete, true)
+}
+
+// completeSessionSpecPair completes both arguments of a command that
+// compares two sessions.
+func completeSessionSpecPair(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) >= 2 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeFromCatalog(nil, toComplete, true)
+}
+
+func completeFromCatalog(args []string, toComplete string, withJobs bool) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	c := sessionCatalog()
+	if c == nil {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return catalogCompletions(c, toComplete, withJobs), cobra.ShellCompDirectiveNoFileComp
+}
+
+// catalogCompletions lists the catalog's session IDs (and, with withJobs,
+// plan/job specs) starting with prefix, each with a tab-separated
+// description.
+func catalogCompletions(c *index.Catalog, prefix string, withJobs bool) []string {
+	var out []string
+	seenJobs := make(map[string]bool)
+	for _, s := range c.Sessions {
+		desc := strings.TrimSpace(fmt.Sprintf("%s %s", s.Provider, s.Project))
+		if !s.StartedAt.IsZero() {
+			desc += " · " + s.StartedAt.Local().Format("2006-01-02 15:04")
+		}
+		if strings.HasPrefix(s.SessionID, prefix) {
+			out = append(out, s.SessionID+"\t"+desc)
+		}
+		if !withJobs {
+			continue
+		}
+		for _, job := range s.Jobs {
+			if !seenJobs[job] && strings.HasPrefix(job, prefix) {
+				seenJobs[job] = true
+				out = append(out, job+"\tjob in "+desc)
+			}
+		}
+	}
+	return out
+}
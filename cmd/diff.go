@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grovetools/core/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/display"
+	"github.com/grovetools/agentlogs/pkg/sessiondiff"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func newDiffCmd() *cobra.Command {
+	var jsonOutput, showSame bool
+	var styleFlag, jobFlag, providerA, providerB string
+
+	cmd := cli.NewStandardCommand("diff", "Compare what two sessions did, turn by turn")
+	cmd.Use = "diff <spec_a> <spec_b>"
+	cmd.Long = `Aligns two transcripts by their user prompts and shows where the agents
+diverged in tool calls and edits. Tool calls are compared by effect — the
+command run, or the file read or edited — so a Claude Edit and a Codex
+apply_patch of the same file match. Paths are made relative to each
+session's working directory, so runs in different worktrees line up.
+
+Each spec is anything 'aglogs read' accepts. A plan/job spec compares only
+that job's part of the transcript; --job does the same for session IDs.
+To compare one job run by two providers, name the job twice:
+
+  aglogs diff plan/01-api.md plan/01-api.md --provider-a claude --provider-b codex`
+	cmd.Args = cobra.ExactArgs(2)
+	cmd.ValidArgsFunction = completeSessionSpecPair
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		style, err := display.ParseRenderStyle(styleFlag)
+		if err != nil {
+			return err
+		}
+
+		infoA, entriesA, err := loadScopedTranscript(cmd, args[0], jobFlag, providerA)
+		if err != nil {
+			return err
+		}
+		infoB, entriesB, err := loadScopedTranscript(cmd, args[1], jobFlag, providerB)
+		if err != nil {
+			return err
+		}
+
+		res := sessiondiff.Compare(entriesA, entriesB, sessiondiff.Options{
+			RootA: session.ReadWorkingDirectory(infoA.LogFilePath, infoA.Provider),
+			RootB: session.ReadWorkingDirectory(infoB.LogFilePath, infoB.Provider),
+		})
+		if jsonOutput {
+			return printJSON(res)
+		}
+		return display.RenderSessionDiff(os.Stdout, res, diffLabel(infoA, args[0]), diffLabel(infoB, args[1]), style, showSame)
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the comparison as JSON")
+	cmd.Flags().BoolVar(&showSame, "all", false, "List the actions of turns that did not diverge too")
+	cmd.Flags().StringVar(&styleFlag, "style", "terminal", "Output style: 'terminal' or 'markdown'")
+	cmd.Flags().StringVar(&jobFlag, "job", "", "Compare only this plan/job's part of each session")
+	cmd.Flags().StringVar(&providerA, "provider-a", "", "Resolve the first spec to a session from this provider")
+	cmd.Flags().StringVar(&providerB, "provider-b", "", "Resolve the second spec to a session from this provider")
+
+	return cmd
+}
+
+// loadScopedTranscript resolves a spec (one side of a diff, or the session
+// of changes/commands) and reads its transcript, scoped to a job when the
+// spec (or --job) names one.
+func loadScopedTranscript(cmd *cobra.Command, spec, jobSpec, providerFlag string) (*session.SessionInfo, []transcript.UnifiedEntry, error) {
+	var info *session.SessionInfo
+	var err error
+	if isLogFilePath(spec) {
+		info, err = resolveMetricsSession(spec)
+	} else {
+		var providers []string
+		if providerFlag != "" {
+			if providers, err = parseProviderFlag(providerFlag); err != nil {
+				return nil, nil, err
+			}
+		}
+		info, err = session.ResolveSessionInfoWithOptions(spec, session.ResolveOptions{Providers: providers})
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not resolve session for '%s': %w", spec, err)
+	}
+
+	opts := provider.ReadOptions{DetailLevel: "full", EndLine: -1}
+	if jobSpec == "" {
+		jobSpec = spec
+	}
+	if start, end, ok := jobLineRange(info, jobSpec, ""); ok {
+		opts.StartLine, opts.EndLine = start, end
+	}
+	entries, err := provider.SelectSource(info, nil).Read(cmd.Context(), info, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read transcript for '%s': %w", spec, err)
+	}
+	return info, entries, nil
+}
+
+func diffLabel(info *session.SessionInfo, spec string) string {
+	label := fmt.Sprintf("%s %s", info.Provider, info.SessionID)
+	if info.SessionID != spec {
+		label += fmt.Sprintf(" (%s)", spec)
+	}
+	return label
+}
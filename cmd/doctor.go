@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+var ulogDoctor = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.doctor")
+
+func newDoctorCmd() *cobra.Command {
+	var providerFlag string
+	var jsonOutput bool
+
+	cmd := cli.NewStandardCommand("doctor", "Report transcript files aglogs cannot fully parse")
+	cmd.Use = "doctor [file...]"
+	cmd.Long = `Reads every Claude, Codex and pi transcript file in full (or only the files
+given) and reports the problems that otherwise make aglogs silently skip
+lines or list a session as "unknown":
+
+  invalid-json  a line is not valid JSON and is skipped
+  truncated     the last line was cut off mid-write
+  no-session    no session id was found, so the session lists as "unknown"
+  unreadable    the file cannot be opened or decompressed
+
+Each problem is reported with its file and line, followed by suggested fixes.
+Nothing is changed on disk.`
+	cmd.Args = cobra.ArbitraryArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		paths := args
+		if len(paths) == 0 {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to get home directory: %w", err)
+			}
+			paths = session.FindTranscriptFiles(homeDir)
+		}
+		if providerFlag != "" {
+			providers, err := parseProviderFlag(providerFlag)
+			if err != nil {
+				return err
+			}
+			paths = slices.DeleteFunc(paths, func(p string) bool {
+				return !slices.Contains(providers, session.ProviderForPath(p))
+			})
+		}
+
+		reports := session.CheckTranscripts(paths)
+		var failed []session.TranscriptReport
+		problems := 0
+		for _, r := range reports {
+			if len(r.Problems) > 0 {
+				failed = append(failed, r)
+				problems += len(r.Problems)
+			}
+		}
+		if jsonOutput {
+			if failed == nil {
+				failed = []session.TranscriptReport{}
+			}
+			return printJSON(failed)
+		}
+
+		out := cmd.OutOrStdout()
+		var kinds []string
+		for _, r := range failed {
+			fmt.Fprintf(out, "%s (%s, %d lines)\n", r.Path, r.Provider, r.Lines)
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			for _, p := range r.Problems {
+				where := "file"
+				if p.Line > 0 {
+					where = fmt.Sprintf("line %d", p.Line)
+				}
+				fmt.Fprintf(tw, "  %s\t%s\t%s\n", where, p.Kind, p.Reason)
+				if !slices.Contains(kinds, p.Kind) {
+					kinds = append(kinds, p.Kind)
+				}
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+		}
+		if len(kinds) > 0 {
+			fmt.Fprintln(out, "\nSuggested fixes:")
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			for _, kind := range kinds {
+				fmt.Fprintf(tw, "  %s\t%s\n", kind, session.TranscriptProblem{Kind: kind}.Suggestion())
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintln(out)
+		}
+
+		ulogDoctor.Info("Checked transcripts").
+			Field("checked", len(reports)).
+			Field("with_problems", len(failed)).
+			Field("problems", problems).
+			Pretty(fmt.Sprintf("Checked %d transcript(s): %d problem(s) in %d file(s)", len(reports), problems, len(failed))).
+			Emit()
+		return nil
+	}
+
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Only check these providers (comma-separated: claude, codex, pi)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the files with problems as JSON")
+	return cmd
+}
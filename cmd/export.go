@@ -0,0 +1,549 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/export"
+	"github.com/grovetools/agentlogs/pkg/redact"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+var ulogExport = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.export")
+
+func newExportCmd() *cobra.Command {
+	var formatFlag string
+	var outputPath string
+	var whereFlag string
+	var sinceFlag, untilFlag string
+	var chain bool
+
+	formats := make([]string, 0, len(export.Formats()))
+	for _, f := range export.Formats() {
+		formats = append(formats, string(f))
+	}
+
+	cmd := cli.NewStandardCommand("export", "Export a session transcript in a provider-neutral format")
+	cmd.Use = "export [--chain] <spec> | --where <expr> --output <dir> | --format ics|sqlite [--since <time>]"
+	cmd.Long = `Exports a session transcript after normalization, so downstream tooling
+sees the same schema whether the source was Claude, Codex, pi, or OpenCode.
+
+<spec> can be a plan/job, a session ID, or a direct path to a log file.
+
+Formats:
+  unified-jsonl   One UnifiedEntry JSON object per line (role, timestamp,
+                  messageID, parts, tokens, provider, ...).
+  ipynb           Jupyter notebook: markdown cells for prompts and responses,
+                  %%bash cells for shell commands, %%writefile cells for file
+                  writes, with recorded tool output attached.
+  html            Self-contained HTML page with a session metadata header.
+  pdf             The HTML page converted to PDF for archival. Requires
+                  wkhtmltopdf or Chromium/Chrome on PATH.
+  ics             iCalendar event spanning the session, titled with its
+                  conversation title (or job, or project).
+  sqlite          SQLite database with tables of sessions, session_jobs,
+                  messages, tool_calls and token_usage, for ad-hoc SQL.
+                  Requires the sqlite3 CLI on PATH, and --output: the
+                  database is never written to stdout.
+
+Output goes to stdout unless --output is given. Text is redacted first when
+aglogs.redaction is configured (patterns, a filter command, or a WASM
+module); if a redaction filter fails, nothing is exported.
+
+--chain follows a resumed Claude session's chain of transcript files (see
+'aglogs read') and exports them as one transcript, each file from where it
+goes past the history it copied. A "Resumed as session <id>" marker, a
+system entry carrying a summary of kind "resume", starts each resumed
+file's part; html and pdf show it as a rule and ipynb as a markdown cell.
+The header lists the chain's sessions and prices the usage of all of them.
+For a plan/job spec, the job's part of the chain is exported. A session
+that was never resumed exports as itself.
+
+With --where, every matching session is exported into the --output
+directory as <session-id>.<ext> (.jsonl for unified-jsonl); a session that
+fails to export is reported and the rest go on.
+
+With --format ics and no <spec>, every session active within --since and
+--until (all sessions by default), narrowed by --where when given, becomes
+one event of a single calendar, so agent working time can be laid out next
+to meetings:
+
+  aglogs export --format ics --since 30d -o agents.ics
+
+With --format sqlite and no <spec>, every session chosen the same way goes
+into one database at --output (also spelled --out):
+
+  aglogs export --format sqlite --out sessions.db
+
+Agent activity can then be queried without parsing transcripts, e.g. the
+most used tools:
+
+  sqlite3 sessions.db 'SELECT name, count(*) FROM tool_calls GROUP BY name ORDER BY 2 DESC'
+
+` + whereHelp
+	cmd.Args = cobra.RangeArgs(0, 1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		format, err := export.ParseFormat(formatFlag)
+		if err != nil {
+			return err
+		}
+		if format == export.FormatSQLite && (outputPath == "" || outputPath == "-") {
+			return fmt.Errorf("--format sqlite writes a database file; name it with --output")
+		}
+		if chain && len(args) == 0 {
+			return fmt.Errorf("--chain exports one session; give its <spec>")
+		}
+		redactor, err := loadRedactor()
+		if err != nil {
+			return err
+		}
+		if format == export.FormatICS && len(args) == 0 {
+			return exportCalendar(cmd.Context(), whereFlag, sinceFlag, untilFlag, outputPath, redactor)
+		}
+		if format == export.FormatSQLite && len(args) == 0 {
+			return exportDatabase(cmd.Context(), whereFlag, sinceFlag, untilFlag, outputPath, redactor)
+		}
+		if sinceFlag != "" || untilFlag != "" {
+			return fmt.Errorf("--since and --until need --format ics or sqlite without a session")
+		}
+		if (len(args) == 1) == (whereFlag != "") {
+			return fmt.Errorf("give either a session or --where")
+		}
+		if whereFlag != "" {
+			if outputPath == "" || outputPath == "-" {
+				return fmt.Errorf("--where needs --output naming a directory")
+			}
+			return exportWhere(cmd.Context(), whereFlag, format, redactor, outputPath)
+		}
+
+		spec := args[0]
+		sessionInfo, err := resolveMetricsSession(spec)
+		if err != nil {
+			return err
+		}
+
+		write := exportSession
+		if chain {
+			write = exportChain
+		}
+		if outputPath == "" || outputPath == "-" {
+			_, err := write(cmd.Context(), os.Stdout, sessionInfo, spec, format, redactor)
+			return err
+		}
+		var n int
+		err = writeOutputFile(outputPath, func(w io.Writer) error {
+			n, err = write(cmd.Context(), w, sessionInfo, spec, format, redactor)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		ulogExport.Info("Exported transcript").
+			Field("session_id", sessionInfo.SessionID).
+			Field("format", string(format)).
+			Field("entry_count", n).
+			Field("output", outputPath).
+			Pretty(fmt.Sprintf("Exported %d entries to %s", n, outputPath)).
+			Emit()
+		return nil
+	}
+
+	cmd.Flags().StringVarP(&formatFlag, "format", "f", string(export.FormatUnifiedJSONL), "Export format ("+strings.Join(formats, ", ")+")")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write to this file instead of stdout (a directory with --where)")
+	cmd.Flags().StringVar(&whereFlag, "where", "", "Export every session matching this filter expression")
+	cmd.Flags().BoolVar(&chain, "chain", false, "Stitch a resumed session's transcript files into one export, marking where each resumed")
+	cmd.Flags().StringVar(&sinceFlag, "since", "", "With --format ics or sqlite, only sessions active since this time: a duration (24h, 30d) or a date (2025-01-01)")
+	cmd.Flags().StringVar(&untilFlag, "until", "", "With --format ics or sqlite, only sessions active until this time")
+	cmd.Flags().StringVar(&outputPath, "out", "", "Same as --output")
+	_ = cmd.Flags().MarkHidden("out")
+
+	return cmd
+}
+
+// exportSession writes one session, limited to the job spec names, to w
+// and returns the number of entries exported.
+func exportSession(ctx context.Context, w io.Writer, sessionInfo *session.SessionInfo, spec string, format export.Format, redactor redact.Redactor) (int, error) {
+	meta, entries, err := readSessionExport(ctx, sessionInfo, spec, redactor)
+	if err != nil {
+		return 0, err
+	}
+	if err := export.Write(ctx, w, format, meta, entries); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// readSessionExport reads the part of a session the job spec names,
+// redacted, with its export header.
+func readSessionExport(ctx context.Context, sessionInfo *session.SessionInfo, spec string, redactor redact.Redactor) (export.Session, []transcript.UnifiedEntry, error) {
+	startLine, endLine, _ := jobLineRange(sessionInfo, spec, "")
+
+	src := provider.SelectSource(sessionInfo, nil)
+	entries, err := src.Read(ctx, sessionInfo, provider.ReadOptions{
+		DetailLevel: "full",
+		StartLine:   startLine,
+		EndLine:     endLine,
+	})
+	if err != nil {
+		return export.Session{}, nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	if entries, err = redact.Entries(ctx, redactor, entries); err != nil {
+		return export.Session{}, nil, err
+	}
+	meta, err := sessionExportMeta(ctx, sessionInfo, redactor)
+	if err != nil {
+		return export.Session{}, nil, err
+	}
+	return meta, entries, nil
+}
+
+// sessionExportMeta is the export header of a session, its title redacted
+// like the transcript since it can be the first prompt.
+func sessionExportMeta(ctx context.Context, sessionInfo *session.SessionInfo, redactor redact.Redactor) (export.Session, error) {
+	title, err := redactTitle(ctx, redactor, sessionInfo.Title)
+	if err != nil {
+		return export.Session{}, err
+	}
+	header := loadSessionHeader(sessionInfo)
+	meta := export.Session{
+		SessionID:   sessionInfo.SessionID,
+		Title:       title,
+		Provider:    sessionInfo.Provider,
+		Project:     sessionInfo.ProjectName,
+		LogFilePath: sessionInfo.LogFilePath,
+		StartedAt:   sessionInfo.StartedAt,
+		EndedAt:     sessionInfo.EndedAt,
+		Worktree:    sessionInfo.Worktree,
+		Branch:      header.Branch,
+		Models:      header.Models,
+		Tokens:      header.Tokens,
+		CostUSD:     header.CostUSD,
+	}
+	for _, job := range sessionInfo.Jobs {
+		meta.Jobs = append(meta.Jobs, job.Plan+"/"+job.Job)
+	}
+	return meta, nil
+}
+
+// redactTitle redacts a session title, which falls back to the session's
+// first prompt.
+func redactTitle(ctx context.Context, redactor redact.Redactor, title string) (string, error) {
+	if redactor == nil || title == "" {
+		return title, nil
+	}
+	return redactor.Redact(ctx, title)
+}
+
+// exportChain writes the resume chain the session belongs to as one
+// transcript, limited to the job spec names, with a resume marker before
+// each file after the first. It returns the number of entries exported,
+// markers included. A session with no chain is exported alone.
+func exportChain(ctx context.Context, w io.Writer, sessionInfo *session.SessionInfo, spec string, format export.Format, redactor redact.Redactor) (int, error) {
+	chain := resumeChain(sessionInfo)
+	if chain == nil {
+		return exportSession(ctx, w, sessionInfo, spec, format, redactor)
+	}
+	segs := chain.Segments()
+	if startLine, _, isJob := jobLineRange(sessionInfo, spec, ""); isJob {
+		segs = chain.JobSegments(sessionInfo.LogFilePath, startLine)
+	}
+	idOf := make(map[string]string, len(chain))
+	for _, f := range chain {
+		idOf[f.Path] = f.SessionID
+	}
+
+	src := provider.SelectSource(sessionInfo, nil)
+	var entries []transcript.UnifiedEntry
+	var windows []chainWindow
+	var ids []string
+	for _, seg := range segs {
+		got, segWindows, err := readChainSegments(ctx, src, sessionInfo, provider.ReadOptions{DetailLevel: "full"}, []session.ChainSegment{seg})
+		if err != nil {
+			return 0, fmt.Errorf("failed to read transcript %s: %w", seg.Path, err)
+		}
+		windows = append(windows, segWindows...)
+		ids = append(ids, idOf[seg.Path])
+		if len(ids) > 1 && len(got) > 0 {
+			entries = append(entries, transcript.UnifiedEntry{
+				Role:      "system",
+				Timestamp: got[0].Timestamp,
+				Provider:  sessionInfo.Provider,
+				Parts: []transcript.UnifiedPart{{Type: "summary", Content: transcript.UnifiedSummary{
+					Kind: transcript.SummaryResume,
+					Text: "Resumed as session " + idOf[seg.Path],
+				}}},
+			})
+		}
+		entries = append(entries, got...)
+	}
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("no part of the chain of %s matches '%s'", sessionInfo.SessionID, spec)
+	}
+
+	// The header covers the whole chain: its sessions, time span, jobs
+	// and usage, each file priced over its own stretch.
+	meta, err := sessionExportMeta(ctx, sessionInfo, redactor)
+	if err != nil {
+		return 0, err
+	}
+	meta.Chain = ids
+	meta.StartedAt, meta.EndedAt = time.Time{}, time.Time{}
+	for _, e := range entries {
+		if e.Timestamp.IsZero() {
+			continue
+		}
+		if meta.StartedAt.IsZero() || e.Timestamp.Before(meta.StartedAt) {
+			meta.StartedAt = e.Timestamp
+		}
+		if e.Timestamp.After(meta.EndedAt) {
+			meta.EndedAt = e.Timestamp
+		}
+	}
+	for _, f := range chain {
+		for _, job := range f.Jobs {
+			if name := job.Plan + "/" + job.Job; !slices.Contains(meta.Jobs, name) {
+				meta.Jobs = append(meta.Jobs, name)
+			}
+		}
+	}
+	if cost, err := jobUsage(sessionInfo, entries, windows...); err != nil {
+		ulogExport.Debug("Could not price the chain's usage").Err(err).Emit()
+	} else if cost != nil {
+		meta.Tokens = cost.Usage.Total()
+		meta.CostUSD = cost.CostUSD
+	}
+
+	if entries, err = redact.Entries(ctx, redactor, entries); err != nil {
+		return 0, err
+	}
+	if err := export.Write(ctx, w, format, meta, entries); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// exportWhere exports every session matching a --where expression into
+// dir, one file per session.
+func exportWhere(ctx context.Context, where string, format export.Format, redactor redact.Redactor, dir string) error {
+	sessions, err := whereSessions(ctx, where)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	bar := newProgress("Exporting sessions")
+	bar.SetTotal(len(sessions))
+	var exported, failed int
+	for i := range sessions {
+		if err := ctx.Err(); err != nil {
+			bar.Finish()
+			return fmt.Errorf("interrupted after exporting %d of %d session(s): %w", exported, len(sessions), err)
+		}
+		bar.Add(1)
+		info := &sessions[i]
+		path := filepath.Join(dir, info.SessionID+"."+format.Ext())
+		err := writeOutputFile(path, func(w io.Writer) error {
+			_, err := exportSession(ctx, w, info, info.SessionID, format, redactor)
+			return err
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				continue // reported at the top of the loop
+			}
+			failed++
+			bar.Clear()
+			ulogExport.Warn("Failed to export session").Err(err).Field("session_id", info.SessionID).
+				Pretty(fmt.Sprintf("Failed to export %s: %v", info.SessionID, err)).Emit()
+			continue
+		}
+		exported++
+	}
+	bar.Finish()
+	ulogExport.Info("Exported matching sessions").
+		Field("format", string(format)).
+		Field("sessions", exported).
+		Field("output", dir).
+		Pretty(fmt.Sprintf("Exported %d session(s) to %s", exported, dir)).
+		Emit()
+	if failed > 0 {
+		return fmt.Errorf("failed to export %d session(s)", failed)
+	}
+	return nil
+}
+
+// activeSessions returns the sessions active between the --since and
+// --until times and matching where, for exports of many sessions into one
+// file.
+func activeSessions(ctx context.Context, where, sinceFlag, untilFlag string) ([]session.SessionInfo, error) {
+	now := time.Now()
+	since, err := parseTimeFlag(sinceFlag, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --since: %w", err)
+	}
+	until, err := parseTimeFlag(untilFlag, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --until: %w", err)
+	}
+	var filter whereExpr
+	if where != "" {
+		if filter, err = parseWhere(where, now); err != nil {
+			return nil, fmt.Errorf("invalid --where: %w", err)
+		}
+	}
+
+	sessions, err := session.NewScannerWithOptions(session.ScanOptions{Since: since, Until: until, Progress: newProgress("Scanning transcripts")}).ScanContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for sessions: %w", err)
+	}
+	var matched []session.SessionInfo
+	for _, s := range filterTestSessions(sessions, false) {
+		if filter == nil || filter.match(s) {
+			matched = append(matched, s)
+		}
+	}
+	return matched, nil
+}
+
+// exportCalendar writes one calendar event per session active between the
+// --since and --until times and matching where, to outputPath or stdout.
+func exportCalendar(ctx context.Context, where, sinceFlag, untilFlag, outputPath string, redactor redact.Redactor) error {
+	sessions, err := activeSessions(ctx, where, sinceFlag, untilFlag)
+	if err != nil {
+		return err
+	}
+	var events []export.Session
+	for _, s := range sessions {
+		title, err := redactTitle(ctx, redactor, s.Title)
+		if err != nil {
+			return err
+		}
+		event := export.Session{
+			SessionID: s.SessionID,
+			Title:     title,
+			Provider:  s.Provider,
+			Project:   s.ProjectName,
+			StartedAt: s.StartedAt,
+			EndedAt:   s.EndedAt,
+			Worktree:  s.Worktree,
+		}
+		for _, job := range s.Jobs {
+			event.Jobs = append(event.Jobs, job.Plan+"/"+job.Job)
+		}
+		events = append(events, event)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].StartedAt.Before(events[j].StartedAt) })
+
+	if outputPath == "" || outputPath == "-" {
+		return export.WriteCalendar(os.Stdout, events)
+	}
+	if err := writeOutputFile(outputPath, func(w io.Writer) error { return export.WriteCalendar(w, events) }); err != nil {
+		return err
+	}
+	ulogExport.Info("Exported calendar").
+		Field("sessions", len(events)).
+		Field("output", outputPath).
+		Pretty(fmt.Sprintf("Exported %d session(s) to %s", len(events), outputPath)).
+		Emit()
+	return nil
+}
+
+// exportDatabase writes every session active between the --since and
+// --until times and matching where into one SQLite database at outputPath.
+// The database is built beside it and renamed into place, so an
+// interrupted export leaves an earlier one intact. A session that fails to
+// read is reported and the rest go on.
+func exportDatabase(ctx context.Context, where, sinceFlag, untilFlag, outputPath string, redactor redact.Redactor) error {
+	sessions, err := activeSessions(ctx, where, sinceFlag, untilFlag)
+	if err != nil {
+		return err
+	}
+	sortSessions(sessions, "started", false, nil)
+	sessions = uniqueSessions(sessions)
+
+	tmp := filepath.Join(filepath.Dir(outputPath), fmt.Sprintf(".%s.tmp-%d", filepath.Base(outputPath), os.Getpid()))
+	os.Remove(tmp)
+	defer os.Remove(tmp)
+	db, err := export.CreateDatabase(tmp)
+	if err != nil {
+		return err
+	}
+	bar := newProgress("Exporting sessions")
+	bar.SetTotal(len(sessions))
+	var exported, failed int
+	for i := range sessions {
+		if err := ctx.Err(); err != nil {
+			bar.Finish()
+			db.Abort()
+			return fmt.Errorf("interrupted after exporting %d of %d session(s): %w", exported, len(sessions), err)
+		}
+		bar.Add(1)
+		info := &sessions[i]
+		meta, entries, err := readSessionExport(ctx, info, info.SessionID, redactor)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue // reported at the top of the loop
+			}
+			failed++
+			bar.Clear()
+			ulogExport.Warn("Failed to export session").Err(err).Field("session_id", info.SessionID).
+				Pretty(fmt.Sprintf("Failed to export %s: %v", info.SessionID, err)).Emit()
+			continue
+		}
+		// A failed write is the database's, not the session's.
+		if err := db.Add(meta, entries); err != nil {
+			bar.Finish()
+			db.Abort()
+			return err
+		}
+		exported++
+	}
+	bar.Finish()
+	if err := db.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, outputPath); err != nil {
+		return err
+	}
+	ulogExport.Info("Exported sessions to database").
+		Field("sessions", exported).
+		Field("output", outputPath).
+		Pretty(fmt.Sprintf("Exported %d session(s) to %s", exported, outputPath)).
+		Emit()
+	if failed > 0 {
+		return fmt.Errorf("failed to export %d session(s)", failed)
+	}
+	return nil
+}
+
+// uniqueSessions keeps one entry per session ID, the first with a
+// transcript, since a scan can list a session from more than one source.
+func uniqueSessions(sessions []session.SessionInfo) []session.SessionInfo {
+	index := make(map[string]int)
+	var unique []session.SessionInfo
+	for _, s := range sessions {
+		if i, ok := index[s.SessionID]; ok {
+			if unique[i].LogFilePath == "" && s.LogFilePath != "" {
+				unique[i] = s
+			}
+			continue
+		}
+		index[s.SessionID] = len(unique)
+		unique = append(unique, s)
+	}
+	return unique
+}
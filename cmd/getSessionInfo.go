@@ -44,10 +44,13 @@ func newGetSessionInfoCmd() *cobra.Command {
 
 					registry, err := sessions.NewFileSystemRegistry()
 					if err == nil {
-						session, err := registry.Find(jobID)
-						if err == nil && session.ClaudeSessionID != "" {
-							agentSessionID = session.ClaudeSessionID
-							provider = session.Provider
+						metadata, err := registry.Find(jobID)
+						if err == nil && metadata.ClaudeSessionID != "" {
+							agentSessionID = metadata.ClaudeSessionID
+							provider = metadata.Provider
+							if provider == "" && metadata.TranscriptPath != "" {
+								provider = session.ProviderForPath(metadata.TranscriptPath)
+							}
 						}
 					}
 				}
@@ -70,11 +73,7 @@ func newGetSessionInfoCmd() *cobra.Command {
 					for _, job := range s.Jobs {
 						if job.Plan == planName && job.Job == jobFilename {
 							agentSessionID = s.SessionID
-							if strings.Contains(s.LogFilePath, "/.codex/") {
-								provider = "codex"
-							} else {
-								provider = "claude"
-							}
+							provider = s.Provider
 							break
 						}
 					}
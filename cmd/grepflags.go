@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// grepFlags are the --grep filter flags shared by read and query.
+type grepFlags struct {
+	pattern string
+	invert  bool
+	before  int
+	after   int
+	context int
+}
+
+// register adds the flags to cmd. noun names what is filtered
+// ("entries") and matched what the pattern is tried against ("text").
+func (g *grepFlags) register(cmd *cobra.Command, noun, matched string) {
+	cmd.Flags().StringVar(&g.pattern, "grep", "", fmt.Sprintf("Show only %s whose %s matches this regular expression", noun, matched))
+	cmd.Flags().BoolVar(&g.invert, "invert", false, fmt.Sprintf("With --grep, show the %s that don't match", noun))
+	cmd.Flags().IntVarP(&g.before, "before-context", "B", 0, fmt.Sprintf("With --grep, also show this many %s before each match", noun))
+	cmd.Flags().IntVarP(&g.after, "after-context", "A", 0, fmt.Sprintf("With --grep, also show this many %s after each match", noun))
+	cmd.Flags().IntVarP(&g.context, "context", "C", 0, fmt.Sprintf("With --grep, also show this many %s around each match (-A and -B override it)", noun))
+}
+
+// compile checks the flags and compiles the pattern. The regexp is nil
+// when no --grep was given.
+func (g *grepFlags) compile(cmd *cobra.Command) (*regexp.Regexp, transcript.GrepOptions, error) {
+	opts := transcript.GrepOptions{Invert: g.invert, Before: g.before, After: g.after}
+	if !cmd.Flags().Changed("before-context") {
+		opts.Before = g.context
+	}
+	if !cmd.Flags().Changed("after-context") {
+		opts.After = g.context
+	}
+	if opts.Before < 0 || opts.After < 0 {
+		return nil, opts, fmt.Errorf("context must not be negative")
+	}
+	if g.pattern == "" {
+		if g.invert || opts.Before > 0 || opts.After > 0 {
+			return nil, opts, fmt.Errorf("--invert and -A/-B/-C need a --grep pattern")
+		}
+		return nil, opts, nil
+	}
+	re, err := regexp.Compile(g.pattern)
+	if err != nil {
+		return nil, opts, fmt.Errorf("invalid --grep pattern: %w", err)
+	}
+	return re, opts, nil
+}
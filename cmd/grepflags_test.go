@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGrepFlagsContext(t *testing.T) {
+	tests := []struct {
+		args          []string
+		before, after int
+		wantErr       bool
+	}{
+		{args: []string{"--grep", "go test", "-C", "2"}, before: 2, after: 2},
+		{args: []string{"--grep", "go test", "-C", "2", "-A", "0"}, before: 2, after: 0},
+		{args: []string{"--grep", "go test", "-B", "1"}, before: 1, after: 0},
+		{args: []string{"-C", "1"}, wantErr: true},
+		{args: []string{"--invert"}, wantErr: true},
+		{args: []string{"--grep", "("}, wantErr: true},
+	}
+	for _, tt := range tests {
+		var g grepFlags
+		cmd := &cobra.Command{}
+		g.register(cmd, "entries", "text")
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatalf("ParseFlags(%v): %v", tt.args, err)
+		}
+		_, opts, err := g.compile(cmd)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("compile(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (opts.Before != tt.before || opts.After != tt.after) {
+			t.Errorf("compile(%v) context = -B %d -A %d, want -B %d -A %d", tt.args, opts.Before, opts.After, tt.before, tt.after)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	aglogs_config "github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/changes"
+	"github.com/grovetools/agentlogs/pkg/display"
+	"github.com/grovetools/agentlogs/pkg/formatters"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+	"github.com/grovetools/agentlogs/pkg/usage"
+)
+
+var ulogHandoff = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.handoff")
+
+// handoffRun is one run of the job in 'aglogs handoff'.
+type handoffRun struct {
+	Label     string                    `json:"label"`
+	SessionID string                    `json:"sessionId"`
+	Provider  string                    `json:"provider"`
+	StartLine int                       `json:"startLine"`
+	EndLine   int                       `json:"endLine"` // -1 = end of transcript
+	Usage     *usage.Summary            `json:"usage,omitempty"`
+	Changes   []changes.FileChange      `json:"changes"`
+	Entries   []transcript.UnifiedEntry `json:"entries,omitempty"`
+
+	info *session.SessionInfo
+}
+
+// handoffComparison compares the file changes of two consecutive runs.
+type handoffComparison struct {
+	A     string                   `json:"a"`
+	B     string                   `json:"b"`
+	Files []changes.FileComparison `json:"files"`
+}
+
+type handoffOutput struct {
+	Job         string              `json:"job"`
+	Runs        []handoffRun        `json:"runs"`
+	Comparisons []handoffComparison `json:"comparisons"`
+}
+
+func newHandoffCmd() *cobra.Command {
+	var jsonOutput bool
+	var planPath, providerFlag, detailFlag, styleFlag string
+
+	cmd := cli.NewStandardCommand("handoff", "Show every run of a job back to back, with how their file changes differ")
+	cmd.Use = "handoff <plan/job>"
+	cmd.Long = `When a job was run more than once, say started with Claude and retried with
+Codex, shows each run's part of its transcript in the order they ran,
+followed by a comparison of the files each run changed: files only one run
+touched, files both changed alike, and a unified diff between the runs'
+versions of the files they changed differently. Where both runs recorded a
+file's full content the diff is between the resulting files; otherwise it
+is between the runs' edits.
+
+<plan/job> is a plan/job spec or a job file path. --provider limits the
+runs to some providers.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		style, err := display.ParseRenderStyle(styleFlag)
+		if err != nil {
+			return err
+		}
+		var providers []string
+		if providerFlag != "" {
+			if providers, err = parseProviderFlag(providerFlag); err != nil {
+				return err
+			}
+		}
+
+		infos, err := session.FindJobRuns(spec, session.ResolveOptions{
+			PlanPath:        planPath,
+			RejectAmbiguous: true,
+			Providers:       providers,
+		})
+		if err != nil {
+			return fmt.Errorf("could not find runs of '%s': %w", spec, err)
+		}
+		if len(infos) == 0 {
+			return fmt.Errorf("no session ran '%s'", spec)
+		}
+
+		runs := make([]handoffRun, 0, len(infos))
+		for i := range infos {
+			run, err := loadHandoffRun(cmd, &infos[i], spec, planPath, i+1)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, run)
+		}
+		out := handoffOutput{Job: spec, Runs: runs, Comparisons: []handoffComparison{}}
+		for i := 1; i < len(runs); i++ {
+			out.Comparisons = append(out.Comparisons, handoffComparison{
+				A:     runs[i-1].Label,
+				B:     runs[i].Label,
+				Files: changes.Compare(runs[i-1].Changes, runs[i].Changes),
+			})
+		}
+
+		if jsonOutput {
+			return printJSON(out)
+		}
+		return renderHandoff(out, style, detailFlag)
+	}
+
+	cmd.Flags().StringVar(&planPath, "plan-path", "", "Plan directory that a plan/job spec refers to, when plan names are ambiguous")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Only include runs from these providers (comma-separated)")
+	cmd.Flags().StringVar(&detailFlag, "detail", "", "Set detail level for the transcripts ('summary' or 'full'). Overrides config.")
+	cmd.Flags().StringVar(&styleFlag, "style", "terminal", "Output style: 'terminal' or 'markdown'")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the runs, their entries and the comparisons as JSON")
+
+	return cmd
+}
+
+// loadHandoffRun reads the job's part of one run's transcript and what it
+// changed and cost.
+func loadHandoffRun(cmd *cobra.Command, info *session.SessionInfo, spec, planPath string, n int) (handoffRun, error) {
+	run := handoffRun{
+		Label:     fmt.Sprintf("%d-%s", n, info.Provider),
+		SessionID: info.SessionID,
+		Provider:  info.Provider,
+		info:      info,
+	}
+	run.StartLine, run.EndLine, _ = jobLineRange(info, spec, planPath)
+	entries, err := provider.SelectSource(info, nil).Read(cmd.Context(), info, provider.ReadOptions{
+		DetailLevel: "full",
+		StartLine:   run.StartLine,
+		EndLine:     run.EndLine,
+	})
+	if err != nil {
+		return run, fmt.Errorf("failed to read transcript of session %s: %w", info.SessionID, err)
+	}
+	run.Entries = entries
+	run.Changes = changes.Extract(entries, session.ReadWorkingDirectory(info.LogFilePath, info.Provider))
+	if run.Changes == nil {
+		run.Changes = []changes.FileChange{}
+	}
+	if run.Usage, err = jobUsage(info, entries); err != nil {
+		ulogHandoff.Debug("Could not compute job usage").Err(err).Field("session_id", info.SessionID).Emit()
+	}
+	return run, nil
+}
+
+func renderHandoff(out handoffOutput, style display.RenderStyle, detailLevel string) error {
+	cfg := aglogs_config.Load().Transcript
+	if detailLevel == "" {
+		detailLevel = cfg.DetailLevel
+	}
+	if detailLevel == "" {
+		detailLevel = "summary"
+	}
+	toolFormatters := map[string]formatters.ToolFormatter{
+		"Write":     formatters.MakeWriteFormatter(cfg.MaxDiffLines),
+		"Edit":      formatters.MakeWriteFormatter(cfg.MaxDiffLines),
+		"Read":      formatters.FormatReadTool,
+		"TodoWrite": formatters.FormatTodoWriteTool,
+	}
+	w := os.Stdout
+
+	heading := func(title string) {
+		if style == display.StyleMarkdown {
+			fmt.Fprintf(w, "\n## %s\n\n", title)
+			return
+		}
+		fmt.Fprintf(w, "\n%s\n%s\n", title, strings.Repeat("═", 50))
+	}
+
+	fmt.Fprintf(w, "%d run(s) of %s\n\n", len(out.Runs), out.Job)
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "RUN\tSESSION ID\tSTARTED\tFILES\tTOKENS\tCOST")
+	for _, run := range out.Runs {
+		started, tokens, cost := "-", "-", "-"
+		if !run.info.StartedAt.IsZero() {
+			started = run.info.StartedAt.Local().Format("2006-01-02 15:04")
+		}
+		if run.Usage != nil {
+			tokens = formatNumber(run.Usage.Usage.Total())
+			cost = fmt.Sprintf("$%.4f", run.Usage.CostUSD)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n", run.Label, run.SessionID, started, len(run.Changes), tokens, cost)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, run := range out.Runs {
+		heading(fmt.Sprintf("Run %s", run.Label))
+		if err := display.RenderSessionHeader(w, loadSessionHeader(run.info), style); err != nil {
+			return fmt.Errorf("failed to render session header: %w", err)
+		}
+		renderOpts := display.RenderOptions{Style: style, DetailLevel: detailLevel}
+		if err := display.RenderUnifiedTranscript(w, run.Entries, renderOpts, toolFormatters); err != nil {
+			return fmt.Errorf("failed to render transcript: %w", err)
+		}
+		if run.Usage != nil {
+			printJobUsageFooter(w, run.Usage, style)
+		}
+	}
+
+	if len(out.Comparisons) == 0 {
+		ulogHandoff.Info("Single run").Field("job", out.Job).
+			Pretty(fmt.Sprintf("\n%s ran once; there is nothing to compare.", out.Job)).PrettyOnly().Emit()
+		return nil
+	}
+	for _, c := range out.Comparisons {
+		heading(fmt.Sprintf("File changes: %s vs %s", c.A, c.B))
+		if len(c.Files) == 0 {
+			fmt.Fprintln(w, "Neither run changed any files.")
+			continue
+		}
+		if style == display.StyleMarkdown {
+			fmt.Fprintln(w, "```diff")
+		}
+		if err := changes.WriteComparison(w, c.Files, c.A, c.B); err != nil {
+			return err
+		}
+		if style == display.StyleMarkdown {
+			fmt.Fprintln(w, "```")
+		}
+	}
+	return nil
+}
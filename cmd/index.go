@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/index"
+	"github.com/grovetools/agentlogs/internal/progress"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/internal/tabular"
+)
+
+var ulogIndex = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.index")
+
+func newIndexCmd() *cobra.Command {
+	cmd := cli.NewStandardCommand("index", "Work with the session index")
+	cmd.Long = `Commands for the session index, the cache of facts (issue keys, token
+totals) aglogs derives by reading whole transcripts.`
+	cmd.AddCommand(newIndexExportCmd())
+	return cmd
+}
+
+func newIndexExportCmd() *cobra.Command {
+	var outPath, tablesFlag, sinceFlag, providerFlag string
+
+	cmd := cli.NewStandardCommand("export", "Export the session and message tables as CSV or Parquet")
+	cmd.Long = `Writes the session index as flat tables for analysis in pandas, DuckDB or a
+spreadsheet. The format follows the --out extension: .csv or .parquet.
+
+Two tables are available:
+  sessions  one row per session: ids, project, times, plan jobs, linked
+            issue keys and total tokens
+  messages  one row per transcript message: role, timestamp, part types,
+            text size, tool calls and per-message token usage
+
+With a single --table the file is written to --out as given. With both (the
+default) they are written next to each other as <out>.sessions.<ext> and
+<out>.messages.<ext>, e.g. index.sessions.parquet and index.messages.parquet.
+
+Examples:
+  aglogs index export --out index.parquet
+  aglogs index export --out sessions.csv --table sessions --since 30d
+  duckdb -c "select role, count(*) from 'index.messages.parquet' group by 1"`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if outPath == "" {
+			return fmt.Errorf("--out is required (e.g. --out index.parquet)")
+		}
+		format, err := tabular.FormatForPath(outPath)
+		if err != nil {
+			return err
+		}
+		tables, err := parseIndexTables(tablesFlag)
+		if err != nil {
+			return err
+		}
+		since, err := parseTimeFlag(sinceFlag, time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		var providers []string
+		if providerFlag != "" {
+			if providers, err = parseProviderFlag(providerFlag); err != nil {
+				return err
+			}
+		}
+
+		sessions, err := session.NewScannerWithOptions(session.ScanOptions{Since: since, Progress: newProgress("Scanning transcripts")}).ScanContext(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to scan for sessions: %w", err)
+		}
+		if providers != nil {
+			sessions = slices.DeleteFunc(sessions, func(s session.SessionInfo) bool {
+				return !slices.Contains(providers, s.Provider)
+			})
+		}
+
+		for _, name := range tables {
+			var t *tabular.Table
+			switch name {
+			case "sessions":
+				ix, err := refreshSessionIndex(cmd.Context(), sessions)
+				if err != nil {
+					return err
+				}
+				t = index.SessionsTable(sessions, ix)
+			case "messages":
+				if t, err = index.MessagesTable(cmd.Context(), sessions, newProgress("Reading messages")); err != nil {
+					return err
+				}
+			}
+			path := outPath
+			if len(tables) > 1 {
+				path = indexTablePath(outPath, name)
+			}
+			if err := writeTableFile(path, t, format); err != nil {
+				return err
+			}
+			ulogIndex.Info("Exported table").
+				Field("table", name).
+				Field("rows", len(t.Rows)).
+				Field("path", path).
+				Pretty(fmt.Sprintf("Wrote %d %s to %s", len(t.Rows), name, path)).
+				Emit()
+		}
+		return nil
+	}
+
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Output file; .csv or .parquet picks the format")
+	cmd.Flags().StringVar(&tablesFlag, "table", "sessions,messages", "Tables to export: sessions, messages, or both as a comma list")
+	cmd.Flags().StringVar(&sinceFlag, "since", "", "Only export sessions active since this time: a duration (24h, 7d) or a date (2025-01-01)")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Only export these providers: a comma list of claude,codex,opencode,pi")
+	return cmd
+}
+
+// parseIndexTables parses --table into the known table names, in export
+// order.
+func parseIndexTables(value string) ([]string, error) {
+	var tables []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "":
+			continue
+		case "sessions", "messages":
+			if !slices.Contains(tables, name) {
+				tables = append(tables, name)
+			}
+		default:
+			return nil, fmt.Errorf("unknown table %q (supported: sessions, messages)", name)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("--table names no tables")
+	}
+	return tables, nil
+}
+
+// indexTablePath inserts the table name before the extension of out:
+// index.parquet becomes index.sessions.parquet.
+func indexTablePath(out, table string) string {
+	ext := filepath.Ext(out)
+	return strings.TrimSuffix(out, ext) + "." + table + ext
+}
+
+// writeTableFile writes t to path.
+func writeTableFile(path string, t *tabular.Table, format tabular.Format) error {
+	return writeOutputFile(path, func(w io.Writer) error {
+		if err := tabular.Write(w, t, format); err != nil {
+			return fmt.Errorf("failed to write %s: %w", t.Name, err)
+		}
+		return nil
+	})
+}
+
+// newProgress returns a progress reporter on stderr for an operation over
+// many transcripts: a bar on a terminal, periodic log lines otherwise.
+func newProgress(label string) *progress.Bar {
+	return progress.New(os.Stderr, label)
+}
+
+// writeOutputFile writes path through a temporary file, so an interrupted
+// export never leaves a truncated file behind.
+func writeOutputFile(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/grovetools/core/pkg/daemon"
+	"github.com/grovetools/core/pkg/paths"
+	"github.com/spf13/cobra"
+
+	aglogs_config "github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/index"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+var ulogInit = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.init")
+
+// agentCheck is the onboarding status of one supported agent CLI.
+type agentCheck struct {
+	Name       string
+	Binary     string
+	BinaryPath string // empty when not on PATH
+	LogDir     string
+	LogDirErr  error // nil when the log directory exists and is readable
+}
+
+// errNoSource marks an agent whose transcript roots are all disabled in
+// the sources config.
+var errNoSource = errors.New("no transcript source")
+
+// detectAgents looks up each supported agent CLI on PATH and checks that
+// its transcript directories among sources can be read, one check per
+// directory.
+func detectAgents(sources []session.Source, lookPath func(string) (string, error)) []agentCheck {
+	agents := []struct{ name, binary, provider string }{
+		{"Claude Code", "claude", "claude"},
+		{"Codex", "codex", "codex"},
+		{"pi", "pi", "pi"},
+		{"OpenCode", "opencode", "opencode"},
+	}
+	var checks []agentCheck
+	for _, a := range agents {
+		c := agentCheck{Name: a.name, Binary: a.binary}
+		if path, err := lookPath(a.binary); err == nil {
+			c.BinaryPath = path
+		}
+		n := 0
+		for _, src := range sources {
+			if src.Provider != a.provider {
+				continue
+			}
+			c.LogDir, c.LogDirErr = src.Path, nil
+			if _, err := os.ReadDir(src.Path); err != nil {
+				c.LogDirErr = err
+			}
+			checks = append(checks, c)
+			n++
+		}
+		if n == 0 {
+			c.LogDirErr = errNoSource
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+// starterConfigBlock is appended to grove.yml by `aglogs init`. Values are
+// the defaults, written out so they are easy to discover and edit.
+const starterConfigBlock = `
+# aglogs: agent transcript viewer settings (written by 'aglogs init')
+aglogs:
+  transcript:
+    # summary or full
+    detail_level: summary
+    # 0 = show whole diffs
+    max_diff_lines: 0
+  issues:
+    # Regular expression for issue keys found in prompts and commits
+    pattern: '` + aglogs_config.DefaultIssuePattern + `'
+`
+
+var aglogsKeyPattern = regexp.MustCompile(`(?m)^aglogs:`)
+
+// writeStarterConfig appends the starter aglogs block to a YAML grove config,
+// creating the file if needed. It reports false without writing when the
+// file already has a top-level aglogs key.
+func writeStarterConfig(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	if aglogsKeyPattern.Match(data) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	block := starterConfigBlock
+	if len(data) == 0 {
+		block = strings.TrimPrefix(block, "\n")
+	} else if !strings.HasSuffix(string(data), "\n") {
+		block = "\n" + block
+	}
+	if _, err := f.WriteString(block); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// prompter asks yes/no questions on an input stream. With assumeYes set it
+// answers every question with its default without reading input.
+type prompter struct {
+	in        *bufio.Reader
+	out       io.Writer
+	assumeYes bool
+}
+
+func (p *prompter) confirm(question string, def bool) bool {
+	if p.assumeYes {
+		return def
+	}
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	fmt.Fprintf(p.out, "%s %s ", question, hint)
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(p.out)
+		return def
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	default:
+		return def
+	}
+}
+
+func newInitCmd() *cobra.Command {
+	var assumeYes bool
+	var configPath string
+
+	cmd := cli.NewStandardCommand("init", "Detect agent CLIs and set up aglogs")
+	cmd.Long = `Walks through first-time setup:
+
+  1. Detects which agent CLIs (Claude Code, Codex, pi, OpenCode) are installed
+     and checks that their transcript directories are readable.
+  2. Optionally builds the session index used by 'aglogs list --issue'.
+  3. Optionally starts the grove daemon, which tracks live sessions.
+  4. Writes a starter aglogs block into the global grove.yml
+     (~/.config/grove/grove.yml), unless one is already there.
+
+Use --yes to accept every default without prompting.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		out := cmd.OutOrStdout()
+		p := &prompter{in: bufio.NewReader(cmd.InOrStdin()), out: out, assumeYes: assumeYes}
+
+		// --- Agent CLIs and log locations ---
+		fmt.Fprintln(out, "Agent CLIs:")
+		found := 0
+		for _, c := range detectAgents(session.Sources(homeDir), exec.LookPath) {
+			binary := "not installed"
+			if c.BinaryPath != "" {
+				binary = c.BinaryPath
+			}
+			logs := "readable"
+			switch {
+			case c.LogDirErr == nil:
+				found++
+			case os.IsNotExist(c.LogDirErr):
+				logs = "no transcripts yet"
+			case errors.Is(c.LogDirErr, errNoSource):
+				logs = "disabled in the sources config"
+			default:
+				logs = fmt.Sprintf("unreadable: %v", c.LogDirErr)
+			}
+			fmt.Fprintf(out, "  %-12s %-40s %s (%s)\n", c.Name, binary, c.LogDir, logs)
+		}
+		fmt.Fprintln(out)
+		if found == 0 {
+			fmt.Fprintln(out, "No agent transcripts found yet; aglogs will pick them up once an agent has run.")
+			fmt.Fprintln(out)
+		}
+
+		// --- Session index ---
+		if found > 0 && p.confirm("Build the session index now?", true) {
+			if err := buildInitIndex(cmd); err != nil {
+				ulogInit.Warn("Failed to build session index").Err(err).
+					Pretty(fmt.Sprintf("Failed to build session index: %v", err)).Emit()
+			}
+		}
+
+		// --- Daemon ---
+		client := daemon.New()
+		running := client.IsRunning()
+		client.Close()
+		if running {
+			fmt.Fprintln(out, "grove daemon: running")
+		} else if grovePath, err := exec.LookPath("grove"); err != nil {
+			fmt.Fprintln(out, "grove daemon: not running (install grove to track live sessions)")
+		} else if p.confirm("grove daemon is not running. Start it now?", false) {
+			start := exec.CommandContext(cmd.Context(), grovePath, "daemon", "start")
+			start.Stdout, start.Stderr = out, cmd.ErrOrStderr()
+			if err := start.Run(); err != nil {
+				ulogInit.Warn("Failed to start grove daemon").Err(err).
+					Pretty(fmt.Sprintf("Failed to start grove daemon: %v", err)).Emit()
+			}
+		}
+		fmt.Fprintln(out)
+
+		// --- Config ---
+		if configPath == "" {
+			configPath = filepath.Join(paths.ConfigDir(), "grove.yml")
+		}
+		if filepath.Ext(configPath) != ".yml" && filepath.Ext(configPath) != ".yaml" {
+			fmt.Fprintf(out, "Add this block to %s by hand (only YAML configs are edited automatically):\n%s", configPath, starterConfigBlock)
+			return nil
+		}
+		if !p.confirm(fmt.Sprintf("Write a starter aglogs config block to %s?", configPath), true) {
+			return nil
+		}
+		written, err := writeStarterConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to write config: %w", err)
+		}
+		if written {
+			fmt.Fprintf(out, "Wrote aglogs config to %s\n", configPath)
+		} else {
+			fmt.Fprintf(out, "%s already has an aglogs block; left unchanged\n", configPath)
+		}
+		return nil
+	}
+
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Accept all defaults without prompting")
+	cmd.Flags().StringVar(&configPath, "config", "", "grove config file to write (default ~/.config/grove/grove.yml)")
+
+	return cmd
+}
+
+// buildInitIndex scans all sessions and writes the session index.
+func buildInitIndex(cmd *cobra.Command) error {
+	sessions, err := session.NewScannerWithOptions(session.ScanOptions{Progress: newProgress("Scanning transcripts")}).ScanContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+	opts, err := index.OptionsFromConfig(aglogs_config.Load())
+	if err != nil {
+		return err
+	}
+	opts.Progress = newProgress("Indexing transcripts")
+	ix, err := index.Load(index.DefaultPath())
+	if err != nil {
+		return err
+	}
+	if err := ix.Refresh(cmd.Context(), sessions, opts); err != nil {
+		return err
+	}
+	if err := ix.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Indexed %d sessions into %s\n\n", len(ix.Records), index.DefaultPath())
+	return nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func TestDetectAgents(t *testing.T) {
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, ".claude", "projects"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	lookPath := func(name string) (string, error) {
+		if name == "claude" {
+			return "/usr/local/bin/claude", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	checks := detectAgents(session.ResolveSources(home, nil), lookPath)
+	if len(checks) != 4 {
+		t.Fatalf("got %d checks, want 4", len(checks))
+	}
+	claude, codex := checks[0], checks[1]
+	if claude.BinaryPath != "/usr/local/bin/claude" || claude.LogDirErr != nil {
+		t.Errorf("claude check = %+v", claude)
+	}
+	if codex.BinaryPath != "" || !os.IsNotExist(codex.LogDirErr) {
+		t.Errorf("codex check = %+v", codex)
+	}
+
+	off := false
+	checks = detectAgents(session.ResolveSources(home, []config.SourceConfig{
+		{Provider: "codex", Enabled: &off},
+		{Provider: "claude", Path: filepath.Join(home, "synced")},
+	}), lookPath)
+	if len(checks) != 5 || checks[1].LogDir != filepath.Join(home, "synced") || !errors.Is(checks[2].LogDirErr, errNoSource) {
+		t.Errorf("checks with configured sources = %+v", checks)
+	}
+}
+
+func TestWriteStarterConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grove.yml")
+	if err := os.WriteFile(path, []byte("groves:\n  work:\n    path: ~/work"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := writeStarterConfig(path)
+	if err != nil || !written {
+		t.Fatalf("first write: written=%v err=%v", written, err)
+	}
+	data, _ := os.ReadFile(path)
+	var parsed struct {
+		Groves map[string]any `yaml:"groves"`
+		Aglogs struct {
+			Transcript struct {
+				DetailLevel string `yaml:"detail_level"`
+			} `yaml:"transcript"`
+			Issues struct {
+				Pattern string `yaml:"pattern"`
+			} `yaml:"issues"`
+		} `yaml:"aglogs"`
+	}
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("result is not valid YAML: %v\n%s", err, data)
+	}
+	if parsed.Groves["work"] == nil || parsed.Aglogs.Transcript.DetailLevel != "summary" || parsed.Aglogs.Issues.Pattern == "" {
+		t.Errorf("unexpected config:\n%s", data)
+	}
+
+	written, err = writeStarterConfig(path)
+	if err != nil || written {
+		t.Fatalf("second write: written=%v err=%v", written, err)
+	}
+	again, _ := os.ReadFile(path)
+	if strings.Count(string(again), "\naglogs:\n") != 1 {
+		t.Errorf("aglogs block duplicated:\n%s", again)
+	}
+}
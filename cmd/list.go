@@ -1,40 +1,184 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	grovelogging "github.com/grovetools/core/logging"
 	"github.com/spf13/cobra"
 
+	aglogs_config "github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/index"
+	"github.com/grovetools/agentlogs/internal/meta"
 	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/codelang"
 	"github.com/grovetools/agentlogs/pkg/display"
+	"github.com/grovetools/agentlogs/pkg/sessionstate"
+	"github.com/grovetools/agentlogs/pkg/transcript"
 )
 
 var ulogList = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.list")
 
+// defaultListLimit caps the session table so a long history doesn't scroll
+// past on every call.
+const defaultListLimit = 50
+
 func newListCmd() *cobra.Command {
 	var jsonOutput bool
 	var projectFilter string
+	var worktreeFilter string
+	var ecosystemFilter string
+	var exact bool
+	var issueFilter string
+	var langFilter string
+	var statusFlag string
+	var userFilter string
+	var sinceFlag string
+	var untilFlag string
+	var limit int
+	var offset int
+	var showAll bool
+	var sortKey string
+	var providerFlag string
+	var modelFilter string
+	var reverse bool
+	var columnsFlag string
+	var preview bool
+	var includeTest bool
+	var whereFlag string
+	var tagFilter []string
+	var includeArchived bool
 
 	cmd := &cobra.Command{
 		Use:   "list [flags]",
 		Short: "List available session transcripts",
-		Long:  "List available session transcripts, optionally filtered by project name",
+		Long: `List available session transcripts, optionally filtered by project name.
+
+--columns picks the table columns. The summary column shows the latest
+current-activity line of each session's AI summary, when the transcript
+monitor or summarize has published one. The title column shows the
+conversation title the agent generated, or else the first line of the
+session's first prompt; --preview adds a column with the start of that
+prompt itself, for telling ad-hoc sessions apart. The status column
+classifies each session from the end of its transcript and how recently it
+was written:
+
+  running         the agent is working
+  idle            quiet in the middle of a turn
+  awaiting_input  waiting on the user for an answer, approval or prompt
+  completed       ended its turn over an hour ago
+  errored         stopped on an error (max tokens, a failed tool call)
+
+--status lists only sessions in the given states.
+
+--model lists only sessions whose model contains the given text, so
+--model sonnet matches every Sonnet version. The MODEL column is the model
+of the session's first assistant turn, as its transcript records it. The
+GIT BRANCH column is the branch the session's working directory was on
+when it started, from the session registry or the transcript; the JSON
+output also carries the HEAD commit when the agent recorded it (Codex).
+
+Test and demo runs are hidden unless --include-test is given: sessions
+marked with 'aglogs mark --test', and sessions the tend e2e harness ran in
+its grove-tend-* scenario directories. 'aglogs mark --not-test' keeps a
+detected session listed.
+
+--include-archived also lists the sessions kept in the remote archives the
+aglogs config names (archive.remotes; see 'aglogs archive --dest') that
+are not on this machine, marked "(remote)" after their ID. Only their
+metadata is downloaded; a session's transcript is fetched when it is read.
+
+--tag lists only sessions tagged with 'aglogs annotate'; the JSON output
+carries each session's tags and notes. Sessions pinned with 'aglogs pin',
+which prune keeps, are marked "(pinned)" after their ID.
+
+` + whereHelp + "\n\n" + `With --where, test runs are listed like any other session unless the
+expression excludes them, so list shows exactly what archive, prune and
+export would act on with the same expression.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// For JSON output, redirect all logging to stderr to keep stdout clean
 			if jsonOutput {
 				grovelogging.SetGlobalOutput(os.Stderr)
 			}
+			var providers []string
+			if providerFlag != "" {
+				var err error
+				if providers, err = parseProviderFlag(providerFlag); err != nil {
+					return err
+				}
+			}
+			if !validListSortKeys[sortKey] {
+				return fmt.Errorf("invalid --sort %q: must be one of started, project, duration, tokens", sortKey)
+			}
+			columns, err := parseListColumns(columnsFlag)
+			if err != nil {
+				return err
+			}
+			if preview && !slices.Contains(columns, "preview") {
+				columns = append(slices.Clone(columns), "preview")
+			}
+			var states []string
+			if statusFlag != "" {
+				if states, err = parseStatusFlag(statusFlag); err != nil {
+					return err
+				}
+			}
+			var lang string
+			if langFilter != "" {
+				if lang = codelang.Normalize(langFilter); lang == "" {
+					return fmt.Errorf("invalid --lang %q: unknown language", langFilter)
+				}
+			}
+			var where whereExpr
+			if whereFlag != "" {
+				if where, err = parseWhere(whereFlag, time.Now()); err != nil {
+					return fmt.Errorf("invalid --where: %w", err)
+				}
+			}
 
-			scanner := session.NewScanner()
-			sessions, err := scanner.Scan()
+			now := time.Now()
+			since, err := parseTimeFlag(sinceFlag, now)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			until, err := parseTimeFlag(untilFlag, now)
+			if err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+
+			// The time window is applied during scanning so transcripts
+			// untouched since --since are never parsed.
+			scanner := session.NewScannerWithOptions(session.ScanOptions{Since: since, Until: until, Progress: newProgress("Scanning transcripts")})
+			sessions, err := scanner.ScanContext(cmd.Context())
 			if err != nil {
 				return fmt.Errorf("failed to scan for sessions: %w", err)
 			}
+			if sinceFlag == "" && untilFlag == "" {
+				// A full scan keeps shell completion current for free.
+				rememberSessions(sessions)
+			}
+			if includeArchived {
+				remote, err := remoteArchiveSessions(cmd.Context(), sessions, since, until)
+				if err != nil {
+					return err
+				}
+				sessions = append(sessions, remote...)
+			}
+			if len(sessions) == 0 && (sinceFlag != "" || untilFlag != "") {
+				ulogList.Info("No sessions found").
+					Field("since", sinceFlag).
+					Field("until", untilFlag).
+					Pretty("No session transcripts found in the given time range.").
+					PrettyOnly().
+					Emit()
+				return nil
+			}
 			if len(sessions) == 0 {
 				ulogList.Info("No sessions found").
 					Pretty("No session transcripts found.").
@@ -43,34 +187,159 @@ func newListCmd() *cobra.Command {
 				return nil
 			}
 
-			// Filter by project if specified
-			if projectFilter != "" {
+			// Filter by project, worktree and ecosystem
+			loc := locationFilter{Project: projectFilter, Worktree: worktreeFilter, Ecosystem: ecosystemFilter, Exact: exact}
+			if !loc.empty() {
+				var filtered []session.SessionInfo
+				for _, s := range sessions {
+					if loc.matches(s) {
+						filtered = append(filtered, s)
+					}
+				}
+				sessions = filtered
+			}
+
+			// Filter by provider
+			if len(providers) > 0 {
+				var filtered []session.SessionInfo
+				for _, s := range sessions {
+					if slices.Contains(providers, s.Provider) {
+						filtered = append(filtered, s)
+					}
+				}
+				sessions = filtered
+			}
+
+			// Filter by model (substring, case-insensitive), so "sonnet"
+			// finds every Sonnet version.
+			if modelFilter != "" {
 				var filtered []session.SessionInfo
 				for _, s := range sessions {
-					if strings.Contains(strings.ToLower(s.ProjectName), strings.ToLower(projectFilter)) ||
-						strings.Contains(strings.ToLower(s.Worktree), strings.ToLower(projectFilter)) {
+					if strings.Contains(strings.ToLower(s.Model), strings.ToLower(modelFilter)) {
 						filtered = append(filtered, s)
-						continue
 					}
+				}
+				sessions = filtered
+			}
 
-					for _, job := range s.Jobs {
-						if strings.Contains(strings.ToLower(job.Plan), strings.ToLower(projectFilter)) ||
-							strings.Contains(strings.ToLower(job.Job), strings.ToLower(projectFilter)) {
-							filtered = append(filtered, s)
-							break
-						}
+			// Filter by user (exact, case-insensitive)
+			if userFilter != "" {
+				var filtered []session.SessionInfo
+				for _, s := range sessions {
+					if strings.EqualFold(s.User, userFilter) {
+						filtered = append(filtered, s)
+					}
+				}
+				sessions = filtered
+			}
+
+			// Hide test runs, or flag them when they're included.
+			sessions = filterTestSessions(sessions, includeTest || where != nil)
+			if len(tagFilter) > 0 {
+				var filtered []session.SessionInfo
+				for _, s := range sessions {
+					if hasTags(s, tagFilter) {
+						filtered = append(filtered, s)
+					}
+				}
+				sessions = filtered
+			}
+			if where != nil {
+				var filtered []session.SessionInfo
+				for _, s := range sessions {
+					if where.match(s) {
+						filtered = append(filtered, s)
+					}
+				}
+				sessions = filtered
+			}
+
+			// Filter by linked issue key. This needs full transcript reads, so
+			// it goes through the persistent index to avoid re-reading
+			// unchanged transcripts on every call.
+			if issueFilter != "" && len(sessions) > 0 {
+				sessions, err = filterSessionsByIssue(cmd.Context(), sessions, issueFilter)
+				if err != nil {
+					return err
+				}
+			}
+			// Likewise for code language, which the index tags per entry.
+			if lang != "" && len(sessions) > 0 {
+				ix, err := refreshSessionIndex(cmd.Context(), sessions)
+				if err != nil {
+					return err
+				}
+				sessions = ix.SessionsWithLanguage(sessions, lang)
+			}
+			if len(states) > 0 && len(sessions) > 0 {
+				if err := attachStatuses(cmd.Context(), sessions); err != nil {
+					return err
+				}
+				var filtered []session.SessionInfo
+				for _, s := range sessions {
+					if slices.Contains(states, s.Status) {
+						filtered = append(filtered, s)
 					}
 				}
 				sessions = filtered
 			}
 
 			if len(sessions) == 0 {
-				if projectFilter != "" {
+				if issueFilter != "" {
+					ulogList.Info("No sessions found").
+						Field("issue_filter", issueFilter).
+						Pretty(fmt.Sprintf("No session transcripts found mentioning issue '%s'\n", issueFilter)).
+						PrettyOnly().
+						Emit()
+				} else if len(tagFilter) > 0 {
+					ulogList.Info("No sessions found").
+						Field("tag_filter", strings.Join(tagFilter, ",")).
+						Pretty(fmt.Sprintf("No session transcripts found tagged %s\n", strings.Join(tagFilter, " and "))).
+						PrettyOnly().
+						Emit()
+				} else if len(states) > 0 {
+					ulogList.Info("No sessions found").
+						Field("status_filter", statusFlag).
+						Pretty(fmt.Sprintf("No session transcripts found with status %s\n", strings.Join(states, " or "))).
+						PrettyOnly().
+						Emit()
+				} else if lang != "" {
+					ulogList.Info("No sessions found").
+						Field("lang_filter", lang).
+						Pretty(fmt.Sprintf("No session transcripts found with %s code\n", lang)).
+						PrettyOnly().
+						Emit()
+				} else if modelFilter != "" {
+					ulogList.Info("No sessions found").
+						Field("model_filter", modelFilter).
+						Pretty(fmt.Sprintf("No session transcripts found for model matching '%s'\n", modelFilter)).
+						PrettyOnly().
+						Emit()
+				} else if providerFlag != "" {
+					ulogList.Info("No sessions found").
+						Field("provider_filter", providerFlag).
+						Pretty(fmt.Sprintf("No session transcripts found for provider '%s'\n", providerFlag)).
+						PrettyOnly().
+						Emit()
+				} else if userFilter != "" {
+					ulogList.Info("No sessions found").
+						Field("user_filter", userFilter).
+						Pretty(fmt.Sprintf("No session transcripts found for user '%s'\n", userFilter)).
+						PrettyOnly().
+						Emit()
+				} else if projectFilter != "" {
 					ulogList.Info("No sessions found").
 						Field("project_filter", projectFilter).
 						Pretty(fmt.Sprintf("No session transcripts found for project matching '%s'\n", projectFilter)).
 						PrettyOnly().
 						Emit()
+				} else if !loc.empty() {
+					ulogList.Info("No sessions found").
+						Field("worktree_filter", worktreeFilter).
+						Field("ecosystem_filter", ecosystemFilter).
+						Pretty("No session transcripts found for the given worktree/ecosystem\n").
+						PrettyOnly().
+						Emit()
 				} else {
 					ulogList.Info("No sessions found").
 						Pretty("No session transcripts found").
@@ -80,10 +349,41 @@ func newListCmd() *cobra.Command {
 				return nil
 			}
 
-			// Sort sessions by started time, most recent first
-			sort.Slice(sessions, func(i, j int) bool {
-				return sessions[i].StartedAt.After(sessions[j].StartedAt)
-			})
+			// Token totals need full transcript reads; like --issue they come
+			// from the session index.
+			var tokens func(session.SessionInfo) int64
+			if sortKey == "tokens" {
+				ix, err := refreshSessionIndex(cmd.Context(), sessions)
+				if err != nil {
+					return err
+				}
+				tokens = ix.Tokens
+			}
+			sortSessions(sessions, sortKey, reverse, tokens)
+
+			// Paginate. The default cap only applies to the table; JSON
+			// consumers get every session unless they ask for a page.
+			if offset < 0 {
+				return fmt.Errorf("--offset must not be negative")
+			}
+			total := len(sessions)
+			if offset > 0 {
+				sessions = sessions[min(offset, total):]
+			}
+			capped := !showAll && (!jsonOutput || cmd.Flags().Changed("limit"))
+			if capped && limit > 0 && len(sessions) > limit {
+				sessions = sessions[:limit]
+			}
+			remaining := total - min(offset, total) - len(sessions)
+
+			if jsonOutput || slices.Contains(columns, "summary") {
+				attachSummaries(sessions)
+			}
+			if len(states) == 0 && slices.Contains(columns, "status") && len(sessions) > 0 {
+				if err := attachStatuses(cmd.Context(), sessions); err != nil {
+					return err
+				}
+			}
 
 			if jsonOutput {
 				data, err := json.MarshalIndent(sessions, "", "  ")
@@ -93,7 +393,17 @@ func newListCmd() *cobra.Command {
 				// Write JSON directly to stdout for machine-readable output
 				fmt.Fprintln(os.Stdout, string(data))
 			} else {
-				display.PrintSessionsTable(sessions, os.Stdout)
+				if err := display.PrintSessionsTableColumns(sessions, os.Stdout, columns); err != nil {
+					return err
+				}
+				if remaining > 0 {
+					ulogList.Info("More sessions available").
+						Field("shown", len(sessions)).
+						Field("remaining", remaining).
+						Pretty(fmt.Sprintf("(+%d more, use --all or --offset %d)", remaining, offset+len(sessions))).
+						PrettyOnly().
+						Emit()
+				}
 			}
 
 			return nil
@@ -102,6 +412,282 @@ func newListCmd() *cobra.Command {
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
 	cmd.Flags().StringVarP(&projectFilter, "project", "p", "", "Filter sessions by project, worktree, plan, or job name (case-insensitive substring match)")
+	cmd.Flags().StringVar(&worktreeFilter, "worktree", "", "Filter sessions by worktree name")
+	cmd.Flags().StringVar(&ecosystemFilter, "ecosystem", "", "Filter sessions by ecosystem name")
+	cmd.Flags().BoolVar(&exact, "exact", false, "Match --project, --worktree and --ecosystem exactly (case-insensitive); --project then only matches the project name")
+
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Only show sessions from these providers (comma-separated: claude, codex, pi, opencode)")
+	cmd.Flags().StringVar(&modelFilter, "model", "", "Only show sessions whose model contains this text (case-insensitive, e.g. sonnet, gpt-5-codex)")
+	cmd.Flags().StringVar(&userFilter, "user", "", "Only show sessions run by this user (registry user or local OS user)")
+	cmd.Flags().StringVar(&statusFlag, "status", "", "Only show sessions in these states (comma-separated: running, idle, awaiting_input, completed, errored)")
+	cmd.Flags().StringVar(&langFilter, "lang", "", "Only show sessions with code in this language (e.g. go, python, ts)")
+	cmd.Flags().StringVar(&issueFilter, "issue", "", "Only show sessions that mention this issue key (e.g. PROJ-123) in prompts or commits")
+	cmd.Flags().StringVar(&sinceFlag, "since", "", "Only show sessions active since this time: a duration (24h, 7d) or a date (2025-01-01)")
+	cmd.Flags().StringVar(&untilFlag, "until", "", "Only show sessions started before this time: a duration (24h, 7d) or a date (2025-01-01)")
+	cmd.Flags().IntVar(&limit, "limit", defaultListLimit, "Maximum number of sessions to show, in sort order")
+	cmd.Flags().IntVar(&offset, "offset", 0, "Skip this many sessions before listing (for paging)")
+	cmd.Flags().StringVar(&whereFlag, "where", "", "Only show sessions matching this filter expression (e.g. 'project=api AND started<30d')")
+	cmd.Flags().StringSliceVar(&tagFilter, "tag", nil, "Only show sessions with these tags (repeatable or comma-separated; all must match)")
+	cmd.Flags().BoolVar(&includeTest, "include-test", false, "Also list test and demo sessions (marked or detected tend runs)")
+	cmd.Flags().BoolVar(&includeArchived, "include-archived", false, "Also list sessions kept in the configured remote archives")
+	cmd.Flags().BoolVar(&showAll, "all", false, "Show all sessions, ignoring --limit")
+	cmd.Flags().StringVar(&sortKey, "sort", "started", "Sort by 'started' (newest first), 'project' (A-Z), 'duration' or 'tokens' (largest first)")
+	cmd.Flags().BoolVar(&reverse, "reverse", false, "Reverse the sort order")
+	cmd.Flags().StringVar(&columnsFlag, "columns", "", "Table columns, comma-separated ("+strings.Join(listColumnNames(), ", ")+"); a list of +name adds to the defaults")
+	cmd.Flags().BoolVar(&preview, "preview", false, "Add a column with the start of each session's first prompt (same as --columns +preview)")
 
 	return cmd
 }
+
+// locationFilter selects sessions by where they ran. By default each field
+// is a case-insensitive substring match, and Project also matches the
+// worktree, plan and job names. Exact requires whole-name matches and limits
+// Project to the project name.
+type locationFilter struct {
+	Project   string
+	Worktree  string
+	Ecosystem string
+	Exact     bool
+}
+
+func (f locationFilter) empty() bool {
+	return f.Project == "" && f.Worktree == "" && f.Ecosystem == ""
+}
+
+func (f locationFilter) match(value, filter string) bool {
+	if f.Exact {
+		return strings.EqualFold(value, filter)
+	}
+	return strings.Contains(strings.ToLower(value), strings.ToLower(filter))
+}
+
+func (f locationFilter) matches(s session.SessionInfo) bool {
+	if f.Worktree != "" && !f.match(s.Worktree, f.Worktree) {
+		return false
+	}
+	if f.Ecosystem != "" && !f.match(s.Ecosystem, f.Ecosystem) {
+		return false
+	}
+	if f.Project == "" || f.match(s.ProjectName, f.Project) {
+		return true
+	}
+	if f.Exact {
+		return false
+	}
+	if f.match(s.Worktree, f.Project) {
+		return true
+	}
+	for _, job := range s.Jobs {
+		if f.match(job.Plan, f.Project) || f.match(job.Job, f.Project) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseListColumns turns --columns into table column names. An empty flag
+// selects the default columns, and a list whose names all start with "+"
+// (e.g. "+summary") adds them to the defaults.
+func parseListColumns(flag string) ([]string, error) {
+	if strings.TrimSpace(flag) == "" {
+		return display.DefaultSessionColumns, nil
+	}
+	var columns []string
+	extend := true
+	for _, name := range strings.Split(flag, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		var plus bool
+		if name, plus = strings.CutPrefix(name, "+"); !plus {
+			extend = false
+		}
+		if _, ok := display.SessionColumns[name]; !ok {
+			return nil, fmt.Errorf("invalid --columns %q: unknown column %q (valid: %s)", flag, name, strings.Join(listColumnNames(), ", "))
+		}
+		columns = append(columns, name)
+	}
+	if extend {
+		columns = append(slices.Clone(display.DefaultSessionColumns), columns...)
+	}
+	return columns, nil
+}
+
+// listColumnNames are the valid --columns names, defaults first.
+func listColumnNames() []string {
+	names := slices.Clone(display.DefaultSessionColumns)
+	var extra []string
+	for name := range display.SessionColumns {
+		if !slices.Contains(names, name) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+// attachSummaries fills in each session's Activity from the AI summaries
+// published by the monitor or summarize. Summaries are optional, so failing
+// to read them is only logged.
+func attachSummaries(sessions []session.SessionInfo) {
+	summaries, err := transcript.LoadSummaries(transcript.SummaryDir())
+	if err != nil {
+		ulogList.Warn("Failed to read session summaries").Err(err).Emit()
+		return
+	}
+	for i := range sessions {
+		sessions[i].Activity = summaries[sessions[i].SessionID].LatestActivity()
+	}
+}
+
+// sessionStates are the states attachStatuses assigns, in --status order.
+var sessionStates = []string{sessionstate.StateRunning, sessionstate.StateIdle, sessionstate.StateAwaitingInput, sessionstate.StateCompleted, sessionstate.StateErrored}
+
+// parseStatusFlag splits a comma-separated --status value, accepting
+// "awaiting-input" for awaiting_input.
+func parseStatusFlag(flag string) ([]string, error) {
+	var states []string
+	for _, name := range strings.Split(flag, ",") {
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(sessionStates, name) {
+			return nil, fmt.Errorf("invalid --status %q: unknown state %q (valid: %s)", flag, name, strings.Join(sessionStates, ", "))
+		}
+		states = append(states, name)
+	}
+	return states, nil
+}
+
+// attachStatuses sets each session's Status from the end of its transcript
+// (see sessionstate.Classify), read through the session index. It replaces
+// the daemon's coarser status where the transcript gives one.
+func attachStatuses(ctx context.Context, sessions []session.SessionInfo) error {
+	ix, err := refreshSessionIndex(ctx, sessions)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range sessions {
+		if st := ix.Status(sessions[i], now); st != "" {
+			sessions[i].Status = st
+		}
+	}
+	return nil
+}
+
+// filterTestSessions drops test and demo runs from sessions, or with
+// include keeps them and sets their Test flag. It also fills in the tags,
+// notes and pin of the sessions kept. Unreadable marks are only logged;
+// detection still applies without them.
+func filterTestSessions(sessions []session.SessionInfo, include bool) []session.SessionInfo {
+	marks, err := meta.LoadStores(meta.DefaultPath())
+	if err != nil {
+		ulogList.Warn("Failed to read session marks").Err(err).Emit()
+		marks = &meta.Stores{Home: &meta.Store{}}
+	}
+	return applyMarks(sessions, marks, include)
+}
+
+// applyMarks is filterTestSessions with the marks already loaded.
+func applyMarks(sessions []session.SessionInfo, marks *meta.Stores, include bool) []session.SessionInfo {
+	var kept []session.SessionInfo
+	for _, s := range sessions {
+		test, _ := marks.IsTest(s)
+		if test && !include {
+			continue
+		}
+		s.Test = test
+		if m := marks.Get(s); m != nil {
+			s.Tags, s.Notes, s.Pinned = m.Tags, m.Notes, m.IsPinned()
+		}
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+// hasTags reports whether a session carries every one of tags.
+func hasTags(s session.SessionInfo, tags []string) bool {
+	for _, tag := range tags {
+		if !slices.ContainsFunc(s.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			return false
+		}
+	}
+	return true
+}
+
+var validListSortKeys = map[string]bool{"started": true, "project": true, "duration": true, "tokens": true}
+
+// sortSessions orders sessions by key: started and project fall back to
+// newest first for ties, duration and tokens put the largest first. tokens
+// is only consulted for the "tokens" key.
+func sortSessions(sessions []session.SessionInfo, key string, reverse bool, tokens func(session.SessionInfo) int64) {
+	newer := func(a, b session.SessionInfo) bool { return a.StartedAt.After(b.StartedAt) }
+	var less func(a, b session.SessionInfo) bool
+	switch key {
+	case "project":
+		less = func(a, b session.SessionInfo) bool {
+			pa, pb := strings.ToLower(a.ProjectName), strings.ToLower(b.ProjectName)
+			if pa != pb {
+				return pa < pb
+			}
+			return newer(a, b)
+		}
+	case "duration":
+		less = func(a, b session.SessionInfo) bool {
+			if da, db := a.Duration(), b.Duration(); da != db {
+				return da > db
+			}
+			return newer(a, b)
+		}
+	case "tokens":
+		less = func(a, b session.SessionInfo) bool {
+			if ta, tb := tokens(a), tokens(b); ta != tb {
+				return ta > tb
+			}
+			return newer(a, b)
+		}
+	default:
+		less = newer
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if reverse {
+			return less(sessions[j], sessions[i])
+		}
+		return less(sessions[i], sessions[j])
+	})
+}
+
+// refreshSessionIndex loads the session index and brings it up to date for
+// sessions. Failing to save it is only logged, since a stale cache just costs
+// time on the next run.
+func refreshSessionIndex(ctx context.Context, sessions []session.SessionInfo) (*index.Index, error) {
+	opts, err := index.OptionsFromConfig(aglogs_config.Load())
+	if err != nil {
+		return nil, err
+	}
+	ix, err := index.Load(index.DefaultPath())
+	if err != nil {
+		return nil, err
+	}
+	opts.Progress = newProgress("Indexing transcripts")
+	if err := ix.Refresh(ctx, sessions, opts); err != nil {
+		return nil, err
+	}
+	if err := ix.Save(); err != nil {
+		ulogList.Warn("Failed to save session index").Err(err).Emit()
+	}
+	return ix, nil
+}
+
+// filterSessionsByIssue refreshes the session index and keeps only the
+// sessions linked to the given issue key.
+func filterSessionsByIssue(ctx context.Context, sessions []session.SessionInfo, key string) ([]session.SessionInfo, error) {
+	ix, err := refreshSessionIndex(ctx, sessions)
+	if err != nil {
+		return nil, err
+	}
+	return ix.SessionsWithIssue(sessions, key), nil
+}
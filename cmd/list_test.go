@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/display"
+)
+
+func TestSortSessions(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sessions := []session.SessionInfo{
+		{SessionID: "a", ProjectName: "web", StartedAt: base, EndedAt: base.Add(time.Hour)},
+		{SessionID: "b", ProjectName: "api", StartedAt: base.Add(2 * time.Hour), EndedAt: base.Add(2*time.Hour + time.Minute)},
+		{SessionID: "c", ProjectName: "Web", StartedAt: base.Add(time.Hour), EndedAt: base.Add(4 * time.Hour)},
+	}
+	tokens := map[string]int64{"a": 500, "b": 9000, "c": 10}
+	tokensOf := func(s session.SessionInfo) int64 { return tokens[s.SessionID] }
+
+	tests := []struct {
+		key     string
+		reverse bool
+		want    []string
+	}{
+		{"started", false, []string{"b", "c", "a"}},
+		{"started", true, []string{"a", "c", "b"}},
+		{"project", false, []string{"b", "c", "a"}},
+		{"duration", false, []string{"c", "a", "b"}},
+		{"tokens", false, []string{"b", "a", "c"}},
+		{"tokens", true, []string{"c", "a", "b"}},
+	}
+	for _, tt := range tests {
+		sorted := append([]session.SessionInfo(nil), sessions...)
+		sortSessions(sorted, tt.key, tt.reverse, tokensOf)
+		var got []string
+		for _, s := range sorted {
+			got = append(got, s.SessionID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sortSessions(%q, reverse=%v) = %v, want %v", tt.key, tt.reverse, got, tt.want)
+		}
+	}
+}
+
+func TestLocationFilter(t *testing.T) {
+	s := session.SessionInfo{
+		ProjectName: "grove-core",
+		Worktree:    "feature-x",
+		Ecosystem:   "grove",
+		Jobs:        []session.JobInfo{{Plan: "refactor", Job: "01-core.md"}},
+	}
+	tests := []struct {
+		name   string
+		filter locationFilter
+		want   bool
+	}{
+		{"substring project", locationFilter{Project: "core"}, true},
+		{"substring via job", locationFilter{Project: "refactor"}, true},
+		{"exact project", locationFilter{Project: "Grove-Core", Exact: true}, true},
+		{"exact rejects substring", locationFilter{Project: "core", Exact: true}, false},
+		{"exact ignores jobs", locationFilter{Project: "refactor", Exact: true}, false},
+		{"worktree", locationFilter{Worktree: "feature"}, true},
+		{"exact worktree", locationFilter{Worktree: "feature", Exact: true}, false},
+		{"ecosystem and project", locationFilter{Ecosystem: "grove", Project: "grove-core", Exact: true}, true},
+		{"wrong ecosystem", locationFilter{Ecosystem: "other"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.matches(s); got != tt.want {
+			t.Errorf("%s: matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseListColumns(t *testing.T) {
+	tests := []struct {
+		flag string
+		want []string
+	}{
+		{"", display.DefaultSessionColumns},
+		{"id, Project,summary", []string{"id", "project", "summary"}},
+		{"+summary", append(append([]string(nil), display.DefaultSessionColumns...), "summary")},
+	}
+	for _, tt := range tests {
+		got, err := parseListColumns(tt.flag)
+		if err != nil {
+			t.Fatalf("parseListColumns(%q): %v", tt.flag, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseListColumns(%q) = %v, want %v", tt.flag, got, tt.want)
+		}
+	}
+	if _, err := parseListColumns("id,bogus"); err == nil {
+		t.Error("parseListColumns accepted an unknown column")
+	}
+}
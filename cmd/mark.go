@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/meta"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+var ulogMark = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.mark")
+
+func newMarkCmd() *cobra.Command {
+	var test, notTest, clearMark bool
+
+	cmd := cli.NewStandardCommand("mark", "Mark a session as a test run")
+	cmd.Use = "mark <session> --test|--not-test|--clear"
+	cmd.Long = `Records that a session is a test or demo run (--test), so 'aglogs list'
+hides it unless --include-test is given.
+
+Sessions the tend e2e harness ran (in grove-tend-* directories) are treated
+as test runs without a mark; --not-test keeps such a session listed.
+--clear removes the mark, returning the session to auto-detection.
+
+Marks of sessions in a project with its own metadata file (see 'aglogs meta
+init') are saved there.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		set := 0
+		for _, b := range []bool{test, notTest, clearMark} {
+			if b {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("give exactly one of --test, --not-test or --clear")
+		}
+		info, err := session.ResolveSessionInfo(args[0])
+		if err != nil {
+			return fmt.Errorf("could not resolve session for '%s': %w", args[0], err)
+		}
+
+		stores, err := meta.LoadStores(meta.DefaultPath())
+		if err != nil {
+			return err
+		}
+		store, err := stores.For(*info)
+		if err != nil {
+			return err
+		}
+		switch {
+		case test:
+			store.SetTest(info.SessionID, true)
+		case notTest:
+			store.SetTest(info.SessionID, false)
+		case clearMark:
+			store.ClearTest(info.SessionID)
+		}
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save session marks: %w", err)
+		}
+
+		isTest, reason := stores.IsTest(*info)
+		state := "not a test run"
+		if isTest {
+			state = "a test run"
+		}
+		if reason != "" && reason != "marked" {
+			state += " (" + reason + ")"
+		}
+		ulogMark.Info("Marked session").
+			Field("session_id", info.SessionID).
+			Field("test", isTest).
+			Pretty(fmt.Sprintf("Session %s is %s", info.SessionID, state)).
+			Emit()
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&test, "test", false, "Mark the session as a test or demo run")
+	cmd.Flags().BoolVar(&notTest, "not-test", false, "Mark the session as a real run, overriding detection")
+	cmd.Flags().BoolVar(&clearMark, "clear", false, "Remove the session's mark")
+	return cmd
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/meta"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+var ulogMeta = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.meta")
+
+func newMetaCmd() *cobra.Command {
+	cmd := cli.NewStandardCommand("meta", "Export and import session metadata")
+	cmd.Long = `Commands for the session metadata aglogs keeps beside the transcripts:
+what you record about sessions with 'aglogs mark' and similar commands. It
+lives in one file on this machine; export and import move it between
+machines, into a repository, or to teammates.
+
+A project can instead keep its own sessions' metadata in
+` + meta.WorkspaceFile + ` (see 'aglogs meta init'), so it travels with the
+repository.`
+	cmd.AddCommand(newMetaInitCmd())
+	cmd.AddCommand(newMetaExportCmd())
+	cmd.AddCommand(newMetaImportCmd())
+	return cmd
+}
+
+func newMetaExportCmd() *cobra.Command {
+	var outPath, workspace string
+
+	cmd := cli.NewStandardCommand("export", "Write session metadata as JSON")
+	cmd.Use = "export [session...]"
+	cmd.Long = `Writes the metadata of the given sessions, or of every session, as a JSON
+document that 'aglogs meta import' reads back. Sessions are keyed by full
+session ID and written in a stable order, so the file diffs well under
+version control.
+
+Without --out the document is written to stdout. With --workspace, the
+metadata comes from that project's own file instead of this machine's.`
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		store, err := loadMetaStore(workspace)
+		if err != nil {
+			return err
+		}
+		var ids []string
+		for _, spec := range args {
+			info, err := session.ResolveSessionInfo(spec)
+			if err != nil {
+				return fmt.Errorf("could not resolve session for '%s': %w", spec, err)
+			}
+			ids = append(ids, info.SessionID)
+		}
+		export := store.Export(ids, time.Now())
+
+		write := func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(export)
+		}
+		if outPath == "" {
+			return write(os.Stdout)
+		}
+		if err := writeOutputFile(outPath, write); err != nil {
+			return err
+		}
+		ulogMeta.Info("Exported session metadata").
+			Field("sessions", len(export.Sessions)).
+			Field("path", outPath).
+			Pretty(fmt.Sprintf("Exported metadata of %d session(s) to %s", len(export.Sessions), outPath)).
+			Emit()
+		return nil
+	}
+
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Write to this file instead of stdout")
+	cmd.Flags().StringVar(&workspace, "workspace", "", "Export the metadata file of this project instead")
+	return cmd
+}
+
+func newMetaImportCmd() *cobra.Command {
+	var overwrite, dryRun bool
+	var workspace string
+
+	cmd := cli.NewStandardCommand("import", "Merge exported session metadata into this machine's")
+	cmd.Use = "import <file|->"
+	cmd.Long = `Merges a document written by 'aglogs meta export' into the local metadata.
+Use - to read it from stdin.
+
+Values recorded only in the export are added. Where both record a value and
+they differ, the local one is kept unless --overwrite is given. Nothing is
+ever removed. The sessions need not exist on this machine; their metadata
+applies once their transcripts arrive.
+
+With --workspace, the document is merged into that project's own metadata
+file instead, which is created if need be.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		in := io.Reader(os.Stdin)
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+		export, err := meta.ReadExport(in)
+		if err != nil {
+			return err
+		}
+		store, err := loadMetaStore(workspace)
+		if err != nil {
+			return err
+		}
+		res := store.Import(export, overwrite)
+		if !dryRun && res.Added+res.Updated > 0 {
+			if err := store.Save(); err != nil {
+				return fmt.Errorf("failed to save session metadata: %w", err)
+			}
+		}
+
+		verb := "Imported"
+		if dryRun {
+			verb = "Would import"
+		}
+		ulogMeta.Info("Imported session metadata").
+			Field("added", res.Added).
+			Field("updated", res.Updated).
+			Field("unchanged", res.Unchanged).
+			Field("dry_run", dryRun).
+			Pretty(fmt.Sprintf("%s metadata: %d session(s) added, %d updated, %d unchanged", verb, res.Added, res.Updated, res.Unchanged)).
+			Emit()
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace differing local values with the imported ones")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report what would change without saving")
+	cmd.Flags().StringVar(&workspace, "workspace", "", "Merge into the metadata file of this project instead")
+	return cmd
+}
+
+// loadMetaStore loads this machine's metadata store, or with a workspace the
+// project's own.
+func loadMetaStore(workspace string) (*meta.Store, error) {
+	if workspace == "" {
+		return meta.Load(meta.DefaultPath())
+	}
+	return meta.Load(meta.WorkspacePath(workspace))
+}
+
+func newMetaInitCmd() *cobra.Command {
+	cmd := cli.NewStandardCommand("init", "Keep a project's session metadata in the project")
+	cmd.Use = "init [project-dir]"
+	cmd.Long = `Creates ` + meta.WorkspaceFile + ` in a project (the current directory by
+default) and moves what this machine records about the project's sessions
+into it. From then on, metadata of sessions run in the project is read from
+and saved to that file; commit it to share it with everyone working on the
+repository.
+
+Sessions belong to the project they ran in, by their working directory.
+Running init again moves metadata recorded on this machine since.`
+	cmd.Args = cobra.MaximumNArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		if fi, err := os.Stat(dir); err != nil {
+			return err
+		} else if !fi.IsDir() {
+			return fmt.Errorf("%s is not a directory", dir)
+		}
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+
+		sessions, err := session.NewScannerWithOptions(session.ScanOptions{Progress: newProgress("Scanning transcripts")}).ScanContext(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to scan for sessions: %w", err)
+		}
+		var ids []string
+		for _, s := range sessions {
+			if s.ProjectPath == dir {
+				ids = append(ids, s.SessionID)
+			}
+		}
+		stores, err := meta.LoadStores(meta.DefaultPath())
+		if err != nil {
+			return err
+		}
+		moved, err := stores.InitWorkspace(dir, ids)
+		if err != nil {
+			return fmt.Errorf("failed to set up workspace metadata: %w", err)
+		}
+		path := meta.WorkspacePath(dir)
+		ulogMeta.Info("Initialized workspace metadata").
+			Field("path", path).
+			Field("moved", moved).
+			Pretty(fmt.Sprintf("Session metadata of %s is kept in %s (moved %d session(s))", dir, path, moved)).
+			Emit()
+		return nil
+	}
+	return cmd
+}
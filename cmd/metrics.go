@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -14,7 +13,6 @@ import (
 	"github.com/grovetools/agentlogs/internal/provider"
 	"github.com/grovetools/agentlogs/internal/session"
 	"github.com/grovetools/agentlogs/pkg/metrics"
-	"github.com/grovetools/agentlogs/pkg/transcript"
 )
 
 func newMetricsCmd() *cobra.Command {
@@ -83,7 +81,7 @@ whether the grove daemon is running.`
 		// while still getting the normalizer Flush() those sources perform.
 		src := provider.SelectSource(sessionInfo, nil)
 
-		entries, err := src.Read(context.Background(), sessionInfo, provider.ReadOptions{
+		entries, err := src.Read(cmd.Context(), sessionInfo, provider.ReadOptions{
 			DetailLevel: "full",
 			EndLine:     -1,
 		})
@@ -145,19 +143,7 @@ func resolveMetricsSession(spec string) (*session.SessionInfo, error) {
 		return info, nil
 	}
 
-	prov := "claude"
-	if strings.Contains(spec, "/.codex/") || strings.Contains(spec, "/codex/sessions/") {
-		prov = "codex"
-	} else if strings.Contains(spec, "/opencode/storage/") {
-		prov = "opencode"
-	} else if transcript.IsPiSessionPath(spec) {
-		// Was strings.Contains(spec, "/pi/sessions/"), which never matches a
-		// real pi transcript: the layout is ~/.pi/agent/sessions/. Every pi
-		// session silently resolved as claude and was folded through the claude
-		// normalizer. The layout predicate lives in pkg/transcript beside the
-		// rest of the pi path vocabulary.
-		prov = "pi"
-	}
+	prov := session.ProviderForPath(spec)
 
 	sessionID := "unknown"
 	if prov == "opencode" {
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	aglogs_config "github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/sessionstate"
+)
+
+// Job outcomes counted by overview.
+const (
+	outcomePassed  = "passed"
+	outcomeFailed  = "failed"
+	outcomeRunning = "running"
+)
+
+// overviewReport is the output of 'aglogs overview'.
+type overviewReport struct {
+	Ecosystem string            `json:"ecosystem,omitempty"`
+	Since     time.Time         `json:"since"`
+	Totals    overviewCounts    `json:"totals"`
+	Projects  []overviewProject `json:"projects"`
+	Failing   []overviewPlan    `json:"failingPlans"`
+	Stalled   []overviewStalled `json:"stalled"`
+}
+
+// overviewCounts is the activity of a project, or of all of them.
+type overviewCounts struct {
+	Sessions int     `json:"sessions"`
+	Jobs     int     `json:"jobs"`
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	Running  int     `json:"running"`
+	Tokens   int64   `json:"tokens"`
+	CostUSD  float64 `json:"costUsd"`
+}
+
+type overviewProject struct {
+	Project string `json:"project"`
+	overviewCounts
+}
+
+type overviewPlan struct {
+	Plan   string `json:"plan"`
+	Jobs   int    `json:"jobs"`
+	Failed int    `json:"failed"`
+}
+
+type overviewStalled struct {
+	SessionID string             `json:"sessionId"`
+	Project   string             `json:"project"`
+	Job       string             `json:"job,omitempty"`
+	Wait      *sessionstate.Wait `json:"wait"`
+}
+
+// overviewFacts is what the index knows about a session.
+type overviewFacts struct {
+	Status  string
+	Tail    *sessionstate.Tail
+	Tokens  int64
+	CostUSD float64
+}
+
+func newOverviewCmd() *cobra.Command {
+	var ecosystem, sinceFlag string
+	var top int
+	var jsonOutput bool
+
+	cmd := cli.NewStandardCommand("overview", "Summarize recent agent activity across projects")
+	cmd.Long = `Prints a one-screen report of the agent activity since --since, for a lead
+reviewing a team's sessions: per project, the sessions, plan jobs and how
+they went, tokens and estimated cost; the plans with the most failed jobs;
+and the sessions stalled waiting on their user.
+
+A job passed when its agent finished its turn, failed when its session
+stopped on an error (see 'aglogs list --help' for the states), and is
+running otherwise. Only a session's last job can fail or still run; the
+jobs before it ended when the next one started.
+
+A session counts as stalled once it has waited on a question or an approval
+for notifications.stalled_after_minutes (10 by default), as in 'aglogs
+status'. Costs are estimates at list prices.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		now := time.Now()
+		since, err := parseTimeFlag(sinceFlag, now)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		stalledAfter := defaultStalledAfter
+		if m := aglogs_config.Load().Notifications.StalledAfterMinutes; m > 0 {
+			stalledAfter = time.Duration(m) * time.Minute
+		}
+
+		sessions, err := session.NewScannerWithOptions(session.ScanOptions{Since: since, Progress: newProgress("Scanning transcripts")}).ScanContext(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to scan for sessions: %w", err)
+		}
+		if ecosystem != "" {
+			loc := locationFilter{Ecosystem: ecosystem}
+			var filtered []session.SessionInfo
+			for _, s := range sessions {
+				if loc.matches(s) {
+					filtered = append(filtered, s)
+				}
+			}
+			sessions = filtered
+		}
+		sessions = filterTestSessions(sessions, false)
+
+		ix, err := refreshSessionIndex(cmd.Context(), sessions)
+		if err != nil {
+			return err
+		}
+		facts := func(s session.SessionInfo) overviewFacts {
+			return overviewFacts{Status: ix.Status(s, now), Tail: ix.Tail(s), Tokens: ix.Tokens(s), CostUSD: ix.Cost(s)}
+		}
+		report := buildOverview(sessions, facts, now, stalledAfter, top)
+		report.Ecosystem, report.Since = ecosystem, since
+
+		if jsonOutput {
+			return printJSON(report)
+		}
+		printOverview(os.Stdout, report, now)
+		return nil
+	}
+
+	cmd.Flags().StringVar(&ecosystem, "ecosystem", "", "Only include sessions in this ecosystem")
+	cmd.Flags().StringVar(&sinceFlag, "since", "7d", "Report activity since this time: a duration (24h, 7d) or a date (2025-01-01)")
+	cmd.Flags().IntVar(&top, "top", 5, "How many failing plans to list")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the report as JSON")
+	return cmd
+}
+
+// jobOutcome is how a session's last job went, by the session's state.
+func jobOutcome(status string, tail *sessionstate.Tail) string {
+	switch status {
+	case sessionstate.StateErrored:
+		return outcomeFailed
+	case sessionstate.StateCompleted:
+		return outcomePassed
+	case sessionstate.StateAwaitingInput:
+		if tail != nil && tail.Wait != nil && tail.Wait.Reason == sessionstate.ReasonTurnEnded {
+			return outcomePassed
+		}
+	}
+	return outcomeRunning
+}
+
+// buildOverview aggregates sessions into the overview, listing at most top
+// failing plans.
+func buildOverview(sessions []session.SessionInfo, facts func(session.SessionInfo) overviewFacts, now time.Time, stalledAfter time.Duration, top int) overviewReport {
+	report := overviewReport{Projects: []overviewProject{}, Failing: []overviewPlan{}, Stalled: []overviewStalled{}}
+	projects := make(map[string]*overviewProject)
+	plans := make(map[string]*overviewPlan)
+	for _, s := range sessions {
+		f := facts(s)
+		p := projects[s.ProjectName]
+		if p == nil {
+			p = &overviewProject{Project: s.ProjectName}
+			projects[s.ProjectName] = p
+		}
+		counts := []*overviewCounts{&p.overviewCounts, &report.Totals}
+		for _, c := range counts {
+			c.Sessions++
+			c.Tokens += f.Tokens
+			c.CostUSD += f.CostUSD
+		}
+		for i, job := range s.Jobs {
+			outcome := outcomePassed
+			if i == len(s.Jobs)-1 {
+				outcome = jobOutcome(f.Status, f.Tail)
+			}
+			for _, c := range counts {
+				c.Jobs++
+				switch outcome {
+				case outcomePassed:
+					c.Passed++
+				case outcomeFailed:
+					c.Failed++
+				default:
+					c.Running++
+				}
+			}
+			pl := plans[job.Plan]
+			if pl == nil {
+				pl = &overviewPlan{Plan: job.Plan}
+				plans[job.Plan] = pl
+			}
+			pl.Jobs++
+			if outcome == outcomeFailed {
+				pl.Failed++
+			}
+		}
+		if f.Tail != nil && f.Tail.Wait != nil && f.Tail.Wait.Reason != sessionstate.ReasonTurnEnded && f.Tail.Wait.Stalled(now, stalledAfter) {
+			st := overviewStalled{SessionID: s.SessionID, Project: s.ProjectName, Wait: f.Tail.Wait}
+			if len(s.Jobs) > 0 {
+				st.Job = s.Jobs[len(s.Jobs)-1].Plan + "/" + s.Jobs[len(s.Jobs)-1].Job
+			}
+			report.Stalled = append(report.Stalled, st)
+		}
+	}
+
+	for _, p := range projects {
+		report.Projects = append(report.Projects, *p)
+	}
+	sort.Slice(report.Projects, func(i, j int) bool {
+		a, b := report.Projects[i], report.Projects[j]
+		if a.Sessions != b.Sessions {
+			return a.Sessions > b.Sessions
+		}
+		return a.Project < b.Project
+	})
+	for _, pl := range plans {
+		if pl.Failed > 0 {
+			report.Failing = append(report.Failing, *pl)
+		}
+	}
+	sort.Slice(report.Failing, func(i, j int) bool {
+		a, b := report.Failing[i], report.Failing[j]
+		if a.Failed != b.Failed {
+			return a.Failed > b.Failed
+		}
+		return a.Plan < b.Plan
+	})
+	if top >= 0 && len(report.Failing) > top {
+		report.Failing = report.Failing[:top]
+	}
+	sort.Slice(report.Stalled, func(i, j int) bool { return report.Stalled[i].Wait.Since.Before(report.Stalled[j].Wait.Since) })
+	return report
+}
+
+func printOverview(w io.Writer, r overviewReport, now time.Time) {
+	scope := "All ecosystems"
+	if r.Ecosystem != "" {
+		scope = "Ecosystem " + r.Ecosystem
+	}
+	if r.Since.IsZero() {
+		fmt.Fprintf(w, "%s, all time\n\n", scope)
+	} else {
+		fmt.Fprintf(w, "%s, since %s\n\n", scope, r.Since.Local().Format("2006-01-02 15:04"))
+	}
+	t := r.Totals
+	fmt.Fprintf(w, "%d sessions, %d jobs (%d passed, %d failed, %d running), %s tokens, $%.2f\n\n",
+		t.Sessions, t.Jobs, t.Passed, t.Failed, t.Running, formatNumber(t.Tokens), t.CostUSD)
+	if t.Sessions == 0 {
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "PROJECT\tSESSIONS\tJOBS\tPASSED\tFAILED\tRUNNING\tTOKENS\tCOST")
+	for _, p := range r.Projects {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%s\t$%.2f\n", p.Project, p.Sessions, p.Jobs, p.Passed, p.Failed, p.Running, formatNumber(p.Tokens), p.CostUSD)
+	}
+	tw.Flush()
+
+	if len(r.Failing) > 0 {
+		fmt.Fprintln(w, "\nTop failing plans:")
+		tw = tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		for _, pl := range r.Failing {
+			fmt.Fprintf(tw, "  %s\t%d of %d jobs failed\n", pl.Plan, pl.Failed, pl.Jobs)
+		}
+		tw.Flush()
+	}
+	if len(r.Stalled) > 0 {
+		fmt.Fprintln(w, "\nStalled sessions:")
+		tw = tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		for _, st := range r.Stalled {
+			where := st.Project
+			if st.Job != "" {
+				where = st.Job
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s for %s\t%s\n", st.SessionID, where, st.Wait.Reason,
+				formatSeconds(now.Sub(st.Wait.Since).Seconds()), truncateCommand(st.Wait.Message, 60))
+		}
+		tw.Flush()
+	}
+}
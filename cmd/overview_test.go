@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/sessionstate"
+)
+
+func TestBuildOverview(t *testing.T) {
+	now := time.Date(2026, 1, 8, 9, 0, 0, 0, time.UTC)
+	jobs := func(plan string, names ...string) []session.JobInfo {
+		var js []session.JobInfo
+		for _, n := range names {
+			js = append(js, session.JobInfo{Plan: plan, Job: n})
+		}
+		return js
+	}
+	sessions := []session.SessionInfo{
+		{SessionID: "a", ProjectName: "api", Jobs: jobs("auth", "01-spec.md", "02-impl.md")},
+		{SessionID: "b", ProjectName: "api", Jobs: jobs("auth", "03-review.md")},
+		{SessionID: "c", ProjectName: "web", Jobs: jobs("theme", "01-spec.md")},
+		{SessionID: "d", ProjectName: "web"},
+	}
+	asking := &sessionstate.Wait{Reason: sessionstate.ReasonQuestion, Since: now.Add(-time.Hour), Message: "Which DB?"}
+	facts := map[string]overviewFacts{
+		"a": {Status: sessionstate.StateErrored, Tokens: 1000, CostUSD: 1.5},
+		"b": {Status: sessionstate.StateAwaitingInput, Tail: &sessionstate.Tail{Wait: &sessionstate.Wait{Reason: sessionstate.ReasonTurnEnded, Since: now}}, Tokens: 500},
+		"c": {Status: sessionstate.StateErrored, Tokens: 200, CostUSD: 0.25},
+		"d": {Status: sessionstate.StateAwaitingInput, Tail: &sessionstate.Tail{Wait: asking}, Tokens: 10},
+	}
+	r := buildOverview(sessions, func(s session.SessionInfo) overviewFacts { return facts[s.SessionID] }, now, 10*time.Minute, 1)
+
+	want := overviewCounts{Sessions: 4, Jobs: 4, Passed: 2, Failed: 2, Tokens: 1710, CostUSD: 1.75}
+	if r.Totals != want {
+		t.Errorf("Totals = %+v, want %+v", r.Totals, want)
+	}
+	if len(r.Projects) != 2 || r.Projects[0].Project != "api" || r.Projects[0].Jobs != 3 || r.Projects[0].Failed != 1 {
+		t.Errorf("Projects = %+v", r.Projects)
+	}
+	// Both plans have one failed job; --top 1 keeps the first by name.
+	if len(r.Failing) != 1 || r.Failing[0] != (overviewPlan{Plan: "auth", Jobs: 3, Failed: 1}) {
+		t.Errorf("Failing = %+v", r.Failing)
+	}
+	if len(r.Stalled) != 1 || r.Stalled[0].SessionID != "d" {
+		t.Errorf("Stalled = %+v", r.Stalled)
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/meta"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+var ulogPin = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.pin")
+
+// pinRow is one pinned session listed by 'aglogs pin'.
+type pinRow struct {
+	SessionID string `json:"sessionId"`
+	meta.Pin
+}
+
+func newPinCmd() *cobra.Command {
+	var reason string
+	var remove, jsonOutput bool
+
+	cmd := cli.NewStandardCommand("pin", "Keep a session's transcripts from being pruned")
+	cmd.Use = "pin [session] [--reason TEXT | --remove]"
+	cmd.Long = `Pins a session that must be kept, as a long-term reference or as audit
+evidence: 'aglogs prune' never deletes or compresses its transcripts, however
+old they are, and 'aglogs list' marks it "(pinned)". --reason records why.
+--remove unpins the session.
+
+Without a session, lists the pinned sessions on this machine and in the
+current project's metadata file. Pins are kept with the other session
+metadata (see 'aglogs meta').`
+	cmd.Args = cobra.MaximumNArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		stores, err := meta.LoadStores(meta.DefaultPath())
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			if remove || reason != "" {
+				return fmt.Errorf("--reason and --remove need a session")
+			}
+			return listPins(stores, jsonOutput)
+		}
+		if remove && reason != "" {
+			return fmt.Errorf("give --reason or --remove, not both")
+		}
+
+		info, err := session.ResolveSessionInfo(args[0])
+		if err != nil {
+			return fmt.Errorf("could not resolve session for '%s': %w", args[0], err)
+		}
+		store, err := stores.For(*info)
+		if err != nil {
+			return err
+		}
+		msg := "Pinned session " + info.SessionID
+		if remove {
+			if !store.Unpin(info.SessionID) {
+				return fmt.Errorf("session %s is not pinned", info.SessionID)
+			}
+			msg = "Unpinned session " + info.SessionID
+		} else {
+			store.SetPin(info.SessionID, reason, time.Now())
+		}
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save pin: %w", err)
+		}
+		ulogPin.Info("Updated pin").
+			Field("session_id", info.SessionID).
+			Field("pinned", !remove).
+			Pretty(msg).
+			Emit()
+		return nil
+	}
+
+	cmd.Flags().StringVar(&reason, "reason", "", "Why the session is kept")
+	cmd.Flags().BoolVar(&remove, "remove", false, "Unpin the session")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the pinned sessions as JSON")
+	return cmd
+}
+
+func listPins(stores *meta.Stores, jsonOutput bool) error {
+	all := []*meta.Store{stores.Home}
+	if dir := findWorkspace(); dir != "" {
+		if ws, err := stores.Workspace(dir); err == nil && ws != nil {
+			all = append(all, ws)
+		}
+	}
+	rows := []pinRow{}
+	for _, s := range all {
+		for id, m := range s.Sessions {
+			if m.IsPinned() {
+				rows = append(rows, pinRow{SessionID: id, Pin: *m.Pin})
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].PinnedAt.After(rows[j].PinnedAt) })
+
+	if jsonOutput {
+		return printJSON(rows)
+	}
+	if len(rows) == 0 {
+		ulogPin.Info("No pinned sessions").Pretty("No pinned sessions.").PrettyOnly().Emit()
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "SESSION ID\tPINNED\tREASON")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.SessionID, r.PinnedAt.Local().Format("2006-01-02 15:04"), r.Reason)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/sessionstate"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+var ulogPlan = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.plan")
+
+// planJob is one job of a plan, as run in one session.
+type planJob struct {
+	Job       string    `json:"job"`
+	PlanPath  string    `json:"planPath,omitempty"`
+	SessionID string    `json:"sessionId"`
+	Provider  string    `json:"provider,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	StartedAt time.Time `json:"startedAt,omitzero"`
+	EndedAt   time.Time `json:"endedAt,omitzero"`
+	Tokens    int64     `json:"tokens"`
+	// FollowUps are the sessions that look like follow-up work on the job;
+	// see linkFollowUps.
+	FollowUps []followUp `json:"followUps,omitempty"`
+
+	session *session.SessionInfo
+	// index is the job's position in the session's Jobs.
+	index int
+}
+
+// followUp is a session without plan jobs opened in an errored job's
+// worktree soon after the job, typically to fix up what it left.
+type followUp struct {
+	SessionID string    `json:"sessionId"`
+	Provider  string    `json:"provider,omitempty"`
+	StartedAt time.Time `json:"startedAt,omitzero"`
+	Title     string    `json:"title,omitempty"`
+}
+
+// followUpWindow is how long after a job ends a session in its worktree
+// still counts as follow-up work on it.
+const followUpWindow = 2 * time.Hour
+
+// Duration is the job's wall-clock span, or 0 when unknown.
+func (j planJob) Duration() time.Duration {
+	if j.StartedAt.IsZero() || j.EndedAt.Before(j.StartedAt) {
+		return 0
+	}
+	return j.EndedAt.Sub(j.StartedAt)
+}
+
+func newPlanCmd() *cobra.Command {
+	var planPath, providerFlag string
+	var jsonOutput bool
+
+	cmd := cli.NewStandardCommand("plan", "Show every job of a plan with its status, duration and tokens")
+	cmd.Use = "plan <plan> [job]"
+	cmd.Long = `Gathers the sessions that ran the jobs of one plan and lists each job in the
+order it started: its session, provider, status, duration and token total.
+Several jobs can share one session; each gets the part of the transcript
+from its start to the next job's.
+
+<plan> is a plan name, or a plan directory. When plans in several
+directories share the name, pick one with --plan-path.
+
+Sessions without plan jobs that were opened in an errored job's project and
+worktree within 2 hours after it ended are listed under it as follow-up
+work, each with the latest such job; they are found by time and place
+alone.
+
+Give a job, by row number or name (with or without .md), to read its
+transcript as 'aglogs read <plan>/<job>' would.`
+	cmd.Args = cobra.RangeArgs(1, 2)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		name := args[0]
+		if fi, err := os.Stat(name); err == nil && fi.IsDir() && planPath == "" {
+			planPath = name
+		}
+		if planPath != "" {
+			abs, err := filepath.Abs(planPath)
+			if err != nil {
+				return err
+			}
+			planPath, name = abs, filepath.Base(abs)
+		}
+		var providers []string
+		if providerFlag != "" {
+			var err error
+			if providers, err = parseProviderFlag(providerFlag); err != nil {
+				return err
+			}
+		}
+
+		sessions, err := session.NewScannerWithOptions(session.ScanOptions{Progress: newProgress("Scanning transcripts")}).ScanContext(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to scan for sessions: %w", err)
+		}
+		jobs := planJobs(sessions, name, planPath, providers)
+		if len(jobs) == 0 {
+			return fmt.Errorf("no sessions found for plan '%s'", name)
+		}
+		if dirs := planDirs(jobs); len(dirs) > 1 {
+			return fmt.Errorf("plan '%s' is ambiguous; pick one with --plan-path:\n  %s", name, strings.Join(dirs, "\n  "))
+		}
+		if err := measurePlanJobs(cmd.Context(), jobs, time.Now()); err != nil {
+			return err
+		}
+		linkFollowUps(jobs, sessions, providers)
+
+		if len(args) == 2 {
+			job, err := pickPlanJob(jobs, args[1])
+			if err != nil {
+				return err
+			}
+			read := newReadCmd()
+			read.SetContext(cmd.Context())
+			if job.PlanPath != "" {
+				_ = read.Flags().Set("plan-path", job.PlanPath)
+			}
+			return read.RunE(read, []string{name + "/" + job.Job})
+		}
+
+		if jsonOutput {
+			return printJSON(jobs)
+		}
+		printPlanJobs(os.Stdout, name, jobs)
+		ulogPlan.Info("Plan jobs").
+			Field("plan", name).
+			Field("jobs", len(jobs)).
+			Pretty(fmt.Sprintf("\nRead a job's transcript with 'aglogs plan %s <#|job>'.", name)).
+			PrettyOnly().
+			Emit()
+		return nil
+	}
+
+	cmd.Flags().StringVar(&planPath, "plan-path", "", "Plan directory, when plan names are ambiguous")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Only include sessions from these providers (comma-separated)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the jobs as JSON")
+	return cmd
+}
+
+// planJobs returns the jobs of the named plan across sessions, in no
+// particular order. A non-empty planPath excludes jobs recorded in other
+// plan directories.
+func planJobs(sessions []session.SessionInfo, plan, planPath string, providers []string) []planJob {
+	var jobs []planJob
+	for i := range sessions {
+		s := &sessions[i]
+		if len(providers) > 0 && !slices.Contains(providers, s.Provider) {
+			continue
+		}
+		for j, job := range s.Jobs {
+			if job.Plan != plan || (planPath != "" && job.PlanPath != "" && filepath.Clean(job.PlanPath) != planPath) {
+				continue
+			}
+			jobs = append(jobs, planJob{
+				Job:       job.Job,
+				PlanPath:  job.PlanPath,
+				SessionID: s.SessionID,
+				Provider:  s.Provider,
+				StartedAt: s.StartedAt,
+				session:   s,
+				index:     j,
+			})
+		}
+	}
+	return jobs
+}
+
+// planDirs lists the distinct known plan directories of jobs.
+func planDirs(jobs []planJob) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, j := range jobs {
+		if j.PlanPath != "" && !seen[j.PlanPath] {
+			seen[j.PlanPath] = true
+			dirs = append(dirs, j.PlanPath)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// measurePlanJobs reads each job's part of its transcript for its times,
+// tokens and status, then orders the jobs by start.
+func measurePlanJobs(ctx context.Context, jobs []planJob, now time.Time) error {
+	for i := range jobs {
+		j := &jobs[i]
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		info := j.session
+		if info.LogFilePath == "" {
+			continue
+		}
+		startLine, endLine := info.Jobs[j.index].LineIndex, -1
+		if j.index+1 < len(info.Jobs) {
+			endLine = info.Jobs[j.index+1].LineIndex
+		}
+		entries, err := provider.SelectSource(info, nil).Read(ctx, info, provider.ReadOptions{DetailLevel: "full", StartLine: startLine, EndLine: endLine})
+		if err != nil {
+			ulogPlan.Debug("Failed to read transcript").Err(err).Field("session_id", info.SessionID).Emit()
+			continue
+		}
+		measurePlanJob(j, entries, now)
+	}
+	sort.SliceStable(jobs, func(a, b int) bool {
+		if !jobs[a].StartedAt.Equal(jobs[b].StartedAt) {
+			return jobs[a].StartedAt.Before(jobs[b].StartedAt)
+		}
+		return jobs[a].Job < jobs[b].Job
+	})
+	return nil
+}
+
+// measurePlanJob fills in a job from its entries. Only a session's last job
+// can still be going; an earlier one ended when the next began.
+func measurePlanJob(j *planJob, entries []transcript.UnifiedEntry, now time.Time) {
+	var started time.Time
+	counted := make(map[string]bool)
+	for _, e := range entries {
+		if !e.Timestamp.IsZero() {
+			if started.IsZero() || e.Timestamp.Before(started) {
+				started = e.Timestamp
+			}
+			if e.Timestamp.After(j.EndedAt) {
+				j.EndedAt = e.Timestamp
+			}
+		}
+		if e.Tokens != nil && (e.MessageID == "" || !counted[e.MessageID]) {
+			counted[e.MessageID] = true
+			t := e.Tokens
+			j.Tokens += int64(t.Input + t.Output + t.CacheRead + t.CacheWrite)
+		}
+	}
+	if !started.IsZero() {
+		j.StartedAt = started
+	}
+	tail := sessionstate.Inspect(entries)
+	switch {
+	case j.index == len(j.session.Jobs)-1:
+		var lastWrite time.Time
+		if fi, err := os.Stat(j.session.LogFilePath); err == nil {
+			lastWrite = fi.ModTime()
+		}
+		j.Status = sessionstate.Classify(tail, lastWrite, now)
+	case tail.Error != "":
+		j.Status = sessionstate.StateErrored
+	default:
+		j.Status = sessionstate.StateCompleted
+	}
+}
+
+// linkFollowUps attaches to errored jobs the sessions that look like work
+// to fix them up: sessions without plan jobs, from providers (all when
+// empty), in the same project and worktree, started once a job ended and
+// no later than followUpWindow after. A session follows the latest such
+// job. jobs must be ordered by start.
+func linkFollowUps(jobs []planJob, sessions []session.SessionInfo, providers []string) {
+	for _, s := range sessions {
+		if len(s.Jobs) > 0 || s.StartedAt.IsZero() || s.ProjectPath == "" || s.ProjectPath == "unknown" {
+			continue
+		}
+		if len(providers) > 0 && !slices.Contains(providers, s.Provider) {
+			continue
+		}
+		for i := len(jobs) - 1; i >= 0; i-- {
+			j := &jobs[i]
+			js := j.session
+			if js == nil || j.Status != sessionstate.StateErrored || js.SessionID == s.SessionID || js.ProjectPath != s.ProjectPath || js.Worktree != s.Worktree {
+				continue
+			}
+			end := j.EndedAt
+			if end.Before(j.StartedAt) {
+				end = j.StartedAt
+			}
+			if end.IsZero() || s.StartedAt.Before(end) || s.StartedAt.After(end.Add(followUpWindow)) {
+				continue
+			}
+			j.FollowUps = append(j.FollowUps, followUp{SessionID: s.SessionID, Provider: s.Provider, StartedAt: s.StartedAt, Title: s.Title})
+			break
+		}
+	}
+	for i := range jobs {
+		f := jobs[i].FollowUps
+		sort.SliceStable(f, func(a, b int) bool { return f[a].StartedAt.Before(f[b].StartedAt) })
+	}
+}
+
+// pickPlanJob finds a job by 1-based row number or name.
+func pickPlanJob(jobs []planJob, arg string) (*planJob, error) {
+	if n, err := strconv.Atoi(arg); err == nil {
+		if n < 1 || n > len(jobs) {
+			return nil, fmt.Errorf("no job #%d; the plan has %d", n, len(jobs))
+		}
+		return &jobs[n-1], nil
+	}
+	var match *planJob
+	for i := range jobs {
+		if jobs[i].Job == arg || strings.TrimSuffix(jobs[i].Job, ".md") == arg {
+			// The latest run wins when a job ran more than once.
+			match = &jobs[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no job '%s' in the plan", arg)
+	}
+	return match, nil
+}
+
+func printPlanJobs(w io.Writer, plan string, jobs []planJob) {
+	var total int64
+	var span time.Duration
+	first, last := time.Time{}, time.Time{}
+	for _, j := range jobs {
+		total += j.Tokens
+		if !j.StartedAt.IsZero() && (first.IsZero() || j.StartedAt.Before(first)) {
+			first = j.StartedAt
+		}
+		if j.EndedAt.After(last) {
+			last = j.EndedAt
+		}
+	}
+	if !first.IsZero() && last.After(first) {
+		span = last.Sub(first)
+	}
+	fmt.Fprintf(w, "Plan: %s (%d jobs, %s tokens, %s)\n\n", plan, len(jobs), formatNumber(total), formatSeconds(span.Seconds()))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "#\tJOB\tPROVIDER\tSESSION ID\tSTATUS\tSTARTED\tDURATION\tTOKENS")
+	for i, j := range jobs {
+		started, duration := "-", "-"
+		if !j.StartedAt.IsZero() {
+			started = j.StartedAt.Local().Format("2006-01-02 15:04")
+		}
+		if d := j.Duration(); d > 0 {
+			duration = formatSeconds(d.Seconds())
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, j.Job, j.Provider, j.SessionID, j.Status, started, duration, formatNumber(j.Tokens))
+	}
+	tw.Flush()
+
+	var linked bool
+	for i, j := range jobs {
+		if len(j.FollowUps) == 0 {
+			continue
+		}
+		if !linked {
+			fmt.Fprintln(w, "\nFollow-up work:")
+			linked = true
+		}
+		fmt.Fprintf(w, "  #%d %s\n", i+1, j.Job)
+		for _, f := range j.FollowUps {
+			title := truncateCommand(f.Title, 60)
+			if title == "" {
+				title = "-"
+			}
+			fmt.Fprintf(w, "    %s  %s  %s  %s\n", f.StartedAt.Local().Format("2006-01-02 15:04"), f.Provider, f.SessionID, title)
+		}
+	}
+}
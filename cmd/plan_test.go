@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/sessionstate"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestPlanJobs(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	sessions := []session.SessionInfo{
+		{SessionID: "b", Provider: "codex", StartedAt: t0.Add(time.Hour), Jobs: []session.JobInfo{
+			{Plan: "feat", Job: "03-review.md", PlanPath: "/repo/plans/feat"},
+		}},
+		{SessionID: "a", Provider: "claude", StartedAt: t0, Jobs: []session.JobInfo{
+			{Plan: "feat", Job: "01-spec.md", PlanPath: "/repo/plans/feat"},
+			{Plan: "feat", Job: "02-impl.md", PlanPath: "/repo/plans/feat", LineIndex: 5},
+		}},
+		{SessionID: "c", Provider: "claude", StartedAt: t0, Jobs: []session.JobInfo{
+			{Plan: "other", Job: "01-spec.md"},
+			{Plan: "feat", Job: "01-spec.md", PlanPath: "/elsewhere/feat"},
+		}},
+	}
+
+	jobs := planJobs(sessions, "feat", "", nil)
+	if len(jobs) != 4 {
+		t.Fatalf("got %d jobs, want 4", len(jobs))
+	}
+	if dirs := planDirs(jobs); len(dirs) != 2 {
+		t.Errorf("planDirs = %v, want two directories", dirs)
+	}
+	jobs = planJobs(sessions, "feat", "/repo/plans/feat", nil)
+	if len(jobs) != 3 {
+		t.Fatalf("with plan path: got %d jobs, want 3", len(jobs))
+	}
+	if got := planJobs(sessions, "feat", "/repo/plans/feat", []string{"codex"}); len(got) != 1 || got[0].SessionID != "b" {
+		t.Errorf("codex only: got %+v", got)
+	}
+
+	// The first job of session a ended when the second began.
+	msg := func(at time.Duration, id string, tokens int, stop string) transcript.UnifiedEntry {
+		return transcript.UnifiedEntry{Role: "assistant", Timestamp: t0.Add(at), MessageID: id, StopReason: stop,
+			Tokens: &transcript.UnifiedTokens{Input: tokens},
+			Parts:  []transcript.UnifiedPart{{Type: "text", Content: transcript.UnifiedTextContent{Text: "ok"}}}}
+	}
+	now := t0.Add(3 * time.Hour)
+	spec := &jobs[1]
+	measurePlanJob(spec, []transcript.UnifiedEntry{msg(time.Minute, "m1", 100, ""), msg(2*time.Minute, "m1", 100, ""), msg(10*time.Minute, "m2", 50, "end_turn")}, now)
+	if spec.Job != "01-spec.md" || spec.Tokens != 150 || spec.Duration() != 9*time.Minute || spec.Status != sessionstate.StateCompleted {
+		t.Errorf("spec job = %+v", *spec)
+	}
+	impl := &jobs[2]
+	measurePlanJob(impl, []transcript.UnifiedEntry{msg(20*time.Minute, "m3", 10, "max_tokens")}, now)
+	if impl.Status != sessionstate.StateErrored || !impl.StartedAt.Equal(t0.Add(20*time.Minute)) {
+		t.Errorf("impl job = %+v", *impl)
+	}
+
+	for _, tt := range []struct{ arg, want string }{{"2", "01-spec.md"}, {"02-impl", "02-impl.md"}, {"03-review.md", "03-review.md"}} {
+		got, err := pickPlanJob(jobs, tt.arg)
+		if err != nil || got.Job != tt.want {
+			t.Errorf("pickPlanJob(%q) = %v, %v; want %s", tt.arg, got, err, tt.want)
+		}
+	}
+	if _, err := pickPlanJob(jobs, "9"); err == nil {
+		t.Error("pickPlanJob(9): want an error")
+	}
+}
+
+func TestLinkFollowUps(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	run := &session.SessionInfo{SessionID: "run", ProjectPath: "/repo", Worktree: "feat", Jobs: []session.JobInfo{{Plan: "p", Job: "01.md"}, {Plan: "p", Job: "02.md"}}}
+	jobs := []planJob{
+		{Job: "01.md", SessionID: "run", Status: sessionstate.StateCompleted, StartedAt: t0, EndedAt: t0.Add(time.Hour), session: run},
+		{Job: "02.md", SessionID: "run", Status: sessionstate.StateErrored, StartedAt: t0.Add(time.Hour), EndedAt: t0.Add(2 * time.Hour), session: run, index: 1},
+	}
+	at := func(d time.Duration) time.Time { return t0.Add(d) }
+	sessions := []session.SessionInfo{
+		*run,
+		{SessionID: "fix", Provider: "claude", ProjectPath: "/repo", Worktree: "feat", StartedAt: at(3 * time.Hour), Title: "fix the tests"},
+		{SessionID: "after-completed", Provider: "codex", ProjectPath: "/repo", Worktree: "feat", StartedAt: at(61 * time.Minute)},
+		{SessionID: "during", Provider: "codex", ProjectPath: "/repo", Worktree: "feat", StartedAt: at(90 * time.Minute)},
+		{SessionID: "late", ProjectPath: "/repo", Worktree: "feat", StartedAt: at(5 * time.Hour)},
+		{SessionID: "elsewhere", ProjectPath: "/repo", StartedAt: at(3 * time.Hour)},
+		{SessionID: "before", ProjectPath: "/repo", Worktree: "feat", StartedAt: at(-time.Minute)},
+		{SessionID: "other-job", ProjectPath: "/repo", Worktree: "feat", StartedAt: at(3 * time.Hour), Jobs: []session.JobInfo{{Plan: "q", Job: "01.md"}}},
+	}
+
+	linkFollowUps(jobs, sessions, nil)
+	ids := func(j planJob) []string {
+		var out []string
+		for _, f := range j.FollowUps {
+			out = append(out, f.SessionID)
+		}
+		return out
+	}
+	if got := ids(jobs[0]); len(got) != 0 {
+		t.Errorf("01 follow-ups = %v, want none for a completed job", got)
+	}
+	if got := ids(jobs[1]); len(got) != 1 || got[0] != "fix" {
+		t.Errorf("02 follow-ups = %v, want [fix]", got)
+	}
+
+	for i := range jobs {
+		jobs[i].FollowUps = nil
+	}
+	linkFollowUps(jobs, sessions, []string{"codex"})
+	if got := ids(jobs[1]); len(got) != 0 {
+		t.Errorf("codex only: 02 follow-ups = %v, want none", got)
+	}
+}
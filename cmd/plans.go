@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/display"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+var ulogPlans = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.plans")
+
+func newPlansCmd() *cobra.Command {
+	var outputDir string
+	var toStdout, jsonOutput bool
+
+	cmd := cli.NewStandardCommand("plans", "Extract the plans proposed in a session into Markdown files")
+	cmd.Use = "plans <spec>"
+	cmd.Long = `Extracts every plan an agent proposed during a session into a standalone
+Markdown file: Claude's plan-mode proposals (ExitPlanMode, with whether the
+user approved them) and Codex's update_plan checklists (successive updates of
+the same steps are collapsed into their final state).
+
+<spec> is a session ID (or unique prefix), a plan/job, or a log file path.
+Files are written to ./<session-id>-plans/plan-NN.md unless --output-dir or
+--stdout is given.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		var info *session.SessionInfo
+		var err error
+		if isLogFilePath(spec) {
+			info, err = resolveMetricsSession(spec)
+		} else {
+			info, err = session.ResolveSessionInfo(spec)
+		}
+		if err != nil {
+			return fmt.Errorf("could not resolve session for '%s': %w", spec, err)
+		}
+
+		entries, err := provider.SelectSource(info, nil).Read(cmd.Context(), info, provider.ReadOptions{DetailLevel: "full", EndLine: -1})
+		if err != nil {
+			return fmt.Errorf("failed to read transcript: %w", err)
+		}
+		plans := transcript.ExtractPlans(entries)
+
+		if jsonOutput {
+			return printJSON(plans)
+		}
+		if len(plans) == 0 {
+			ulogPlans.Info("No plans found").
+				Field("session_id", info.SessionID).
+				Pretty("No plans were proposed in this session.").
+				PrettyOnly().
+				Emit()
+			return nil
+		}
+		if toStdout {
+			for i, plan := range plans {
+				if i > 0 {
+					fmt.Fprintln(os.Stdout)
+				}
+				if err := display.RenderPlanDocument(os.Stdout, plan, i+1, *info); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if outputDir == "" {
+			outputDir = info.SessionID + "-plans"
+		}
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		for i, plan := range plans {
+			path := filepath.Join(outputDir, fmt.Sprintf("plan-%02d.md", i+1))
+			f, err := os.Create(path)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", path, err)
+			}
+			err = display.RenderPlanDocument(f, plan, i+1, *info)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			ulogPlans.Info("Wrote plan").
+				Field("session_id", info.SessionID).
+				Field("source", plan.Source).
+				Field("outcome", plan.Outcome).
+				Field("output", path).
+				Pretty(path).
+				Emit()
+		}
+		return nil
+	}
+
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Directory to write plan files to (default ./<session-id>-plans)")
+	cmd.Flags().BoolVar(&toStdout, "stdout", false, "Print the plans to stdout instead of writing files")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the plans as JSON")
+
+	return cmd
+}
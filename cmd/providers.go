@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func newProvidersCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := cli.NewStandardCommand("providers", "List the agent providers aglogs reads and what each supports")
+	cmd.Long = `Lists every provider aglogs reads transcripts from, where it looks for
+them, what their transcripts support, and how many sessions a scan finds:
+
+  STREAMING  stream and tail follow a live session
+  JOBS       grove plan jobs are detected in its sessions
+  TOKENS     token usage and cost are reported
+  REASONING  the model's reasoning is kept in transcripts
+
+STORAGE lists the directories read: the provider's usual one and any added
+in the sources section of the aglogs config. It doubles as a diagnostic: a
+provider whose storage is missing, or present but yielding no sessions, is
+called out below the table.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		sessions, err := session.NewScannerWithOptions(session.ScanOptions{Progress: newProgress("Scanning transcripts")}).ScanContext(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to scan for sessions: %w", err)
+		}
+		counts := make(map[string]int)
+		for _, s := range sessions {
+			counts[s.Provider]++
+		}
+
+		rows := providerRows(provider.Capabilities, session.Sources(homeDir), counts)
+		if jsonOutput {
+			return printJSON(rows)
+		}
+		return printProviders(os.Stdout, rows, homeDir)
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the providers as JSON")
+	return cmd
+}
+
+// providerRow is one provider's line in 'aglogs providers'.
+type providerRow struct {
+	Name      string            `json:"name"`
+	Storage   []providerStorage `json:"storage"`
+	Streaming bool              `json:"streaming"`
+	Jobs      bool              `json:"jobs"`
+	Tokens    bool              `json:"tokens"`
+	Reasoning bool              `json:"reasoning"`
+	Sessions  int               `json:"sessions"`
+}
+
+type providerStorage struct {
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+}
+
+// problem describes what looks wrong with the provider's integration, or
+// is "" when nothing does.
+func (r providerRow) problem() string {
+	if r.Sessions > 0 {
+		return ""
+	}
+	for _, s := range r.Storage {
+		if s.Exists {
+			return "storage found but no sessions in it could be read; try 'aglogs doctor'"
+		}
+	}
+	if len(r.Storage) == 0 {
+		return "disabled in the sources section of the aglogs config"
+	}
+	return "no storage found; not installed, or keeping transcripts elsewhere (see the sources section of the aglogs config)"
+}
+
+// providerRows pairs each capability with its transcript sources, and
+// whether they exist, and its session count.
+func providerRows(caps []provider.Capability, sources []session.Source, counts map[string]int) []providerRow {
+	rows := make([]providerRow, 0, len(caps))
+	for _, c := range caps {
+		row := providerRow{Name: c.Name, Streaming: c.Streaming, Jobs: c.Jobs, Tokens: c.Tokens, Reasoning: c.Reasoning, Sessions: counts[c.Name]}
+		for _, src := range sources {
+			if src.Provider == c.Name {
+				_, err := os.Stat(src.Path)
+				row.Storage = append(row.Storage, providerStorage{Path: src.Path, Exists: err == nil})
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func printProviders(w io.Writer, rows []providerRow, homeDir string) error {
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "-"
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tSESSIONS\tSTREAMING\tJOBS\tTOKENS\tREASONING\tSTORAGE")
+	for _, r := range rows {
+		var storage []string
+		for _, s := range r.Storage {
+			path := s.Path
+			if rel, ok := strings.CutPrefix(path, homeDir+string(os.PathSeparator)); ok {
+				path = "~/" + rel
+			}
+			if !s.Exists {
+				path += " (missing)"
+			}
+			storage = append(storage, path)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", r.Name, r.Sessions, yesNo(r.Streaming), yesNo(r.Jobs), yesNo(r.Tokens), yesNo(r.Reasoning), strings.Join(storage, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	first := true
+	for _, r := range rows {
+		if p := r.problem(); p != "" {
+			if first {
+				fmt.Fprintln(w)
+				first = false
+			}
+			fmt.Fprintf(w, "%s: %s\n", r.Name, p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func TestProvidersReportsMissingIntegrations(t *testing.T) {
+	home := t.TempDir()
+	for _, dir := range []string{".claude/projects", ".codex/sessions"} {
+		if err := os.MkdirAll(filepath.Join(home, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sources := session.ResolveSources(home, []config.SourceConfig{
+		{Provider: "claude", Path: "/mnt/other-host/claude"},
+		{Provider: "opencode", Enabled: new(bool)},
+	})
+	rows := providerRows(provider.Capabilities, sources, map[string]int{"claude": 3})
+
+	var out bytes.Buffer
+	if err := printProviders(&out, rows, home); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"~/.claude/projects, /mnt/other-host/claude (missing)",
+		"~/.pi/agent/sessions (missing)",
+		"opencode: disabled in the sources section",
+		"codex: storage found but no sessions",
+		"pi: no storage found",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "claude:") {
+		t.Errorf("claude has sessions but was reported as a problem:\n%s", got)
+	}
+}
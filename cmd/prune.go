@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/meta"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+var ulogPrune = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.prune")
+
+func newPruneCmd() *cobra.Command {
+	var olderThan, providerFlag, whereFlag string
+	var dryRun, compress, assumeYes, jsonOutput bool
+
+	cmd := cli.NewStandardCommand("prune", "Delete or compress old Claude and Codex transcripts")
+	cmd.Long = `Deletes Claude and Codex transcript files last written before --older-than
+(a duration like 60d or 12w, or a date like 2025-01-01) and reports the disk
+space reclaimed.
+
+Transcripts of plan jobs are kept until every job of the session has been
+archived with 'aglogs archive'; archived copies are never touched.
+Transcripts of sessions pinned with 'aglogs pin' are always kept. Claude
+sub-agent (agent-*) files are kept or pruned along with their session.
+
+--compress gzips each transcript in place (<file>.jsonl.gz) instead of
+deleting it. Compressed transcripts are still listed and read by aglogs;
+a later prune without --compress deletes them once they are old enough.
+
+--where limits pruning to the transcripts of matching sessions; with it
+--older-than is optional. Prune only touches Claude and Codex transcripts,
+and keeps pinned sessions and unarchived plan jobs, whatever the expression
+matches.
+
+Use --dry-run to see what would be pruned. Without --yes, prune asks before
+changing anything.
+
+` + whereHelp
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if olderThan == "" && whereFlag == "" {
+			return fmt.Errorf("--older-than or --where is required (e.g. --older-than 60d)")
+		}
+		before := time.Now()
+		if olderThan != "" {
+			var err error
+			if before, err = parseTimeFlag(olderThan, before); err != nil {
+				return fmt.Errorf("invalid --older-than: %w", err)
+			}
+		}
+		var where whereExpr
+		if whereFlag != "" {
+			var err error
+			if where, err = parseWhere(whereFlag, time.Now()); err != nil {
+				return fmt.Errorf("invalid --where: %w", err)
+			}
+		}
+		var providers []string
+		if providerFlag != "" {
+			var err error
+			if providers, err = parseProviderFlag(providerFlag); err != nil {
+				return err
+			}
+		}
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+
+		// A full scan is needed to know which transcripts belong to plan
+		// jobs, however old they are.
+		sessions, err := session.NewScannerWithOptions(session.ScanOptions{Progress: newProgress("Scanning transcripts")}).ScanContext(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to scan for sessions: %w", err)
+		}
+		if sessions, err = pruneMarks(sessions); err != nil {
+			return err
+		}
+		candidates, err := session.FindPruneCandidates(homeDir, sessions, session.PruneOptions{Before: before, Providers: providers, Compress: compress})
+		if err != nil {
+			return err
+		}
+		if where != nil {
+			candidates = whereCandidates(candidates, sessions, where)
+		}
+		var prune []session.PruneCandidate
+		var total int64
+		for _, c := range candidates {
+			if !c.Keep {
+				prune = append(prune, c)
+				total += c.Size
+			}
+		}
+
+		if jsonOutput && dryRun {
+			if candidates == nil {
+				candidates = []session.PruneCandidate{}
+			}
+			return printJSON(candidates)
+		}
+		if len(candidates) == 0 {
+			msg := fmt.Sprintf("No transcripts last written before %s.", before.Local().Format("2006-01-02"))
+			if olderThan == "" {
+				msg = "No transcripts of matching sessions."
+			}
+			ulogPrune.Info("Nothing to prune").
+				Field("before", before).
+				Pretty(msg).
+				PrettyOnly().
+				Emit()
+			return nil
+		}
+
+		verb := "Delete"
+		if compress {
+			verb = "Compress"
+		}
+		out := cmd.OutOrStdout()
+		if !jsonOutput {
+			tw := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(tw, "ACTION\tLAST WRITTEN\tSIZE\tPROVIDER\tSESSION ID\tPATH")
+			for _, c := range candidates {
+				action := strings.ToLower(verb)
+				if c.Keep {
+					action = "keep (" + c.KeepReason + ")"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", action, c.ModTime.Local().Format("2006-01-02"),
+					formatBytes(c.Size), c.Provider, c.SessionID, c.Path)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "\n%d transcript(s), %s; %d kept\n", len(prune), formatBytes(total), len(candidates)-len(prune))
+		}
+		if dryRun || len(prune) == 0 {
+			return nil
+		}
+
+		p := &prompter{in: bufio.NewReader(cmd.InOrStdin()), out: out}
+		if !assumeYes && !p.confirm(fmt.Sprintf("%s %d transcript(s)?", verb, len(prune)), false) {
+			return fmt.Errorf("aborted")
+		}
+
+		var reclaimed int64
+		var pruned []session.PruneCandidate
+		for _, c := range prune {
+			// Each transcript is pruned whole, so stopping between them
+			// leaves nothing half done.
+			if err := cmd.Context().Err(); err != nil {
+				return fmt.Errorf("interrupted after pruning %d of %d transcript(s), reclaiming %s: %w", len(pruned), len(prune), formatBytes(reclaimed), err)
+			}
+			n, err := session.PruneTranscript(c, compress)
+			if err != nil {
+				ulogPrune.Warn("Failed to prune transcript").Err(err).Field("path", c.Path).
+					Pretty(fmt.Sprintf("Failed to prune %s: %v", c.Path, err)).Emit()
+				continue
+			}
+			reclaimed += n
+			pruned = append(pruned, c)
+		}
+		if jsonOutput {
+			if pruned == nil {
+				pruned = []session.PruneCandidate{}
+			}
+			return printJSON(pruned)
+		}
+		ulogPrune.Info("Pruned transcripts").
+			Field("pruned", len(pruned)).
+			Field("compress", compress).
+			Field("reclaimed_bytes", reclaimed).
+			Pretty(fmt.Sprintf("Pruned %d transcript(s), reclaimed %s", len(pruned), formatBytes(reclaimed))).
+			Emit()
+		if len(pruned) < len(prune) {
+			return fmt.Errorf("failed to prune %d transcript(s)", len(prune)-len(pruned))
+		}
+		return nil
+	}
+
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Prune transcripts last written before this: a duration (60d, 12w) or a date (2025-01-01)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only list what would be pruned")
+	cmd.Flags().BoolVar(&compress, "compress", false, "Gzip transcripts instead of deleting them")
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Prune without asking for confirmation")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the candidates (with --dry-run) or the pruned transcripts as JSON")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Only prune transcripts from these providers (comma-separated: claude, codex)")
+	cmd.Flags().StringVar(&whereFlag, "where", "", "Only prune transcripts of sessions matching this filter expression")
+
+	return cmd
+}
+
+// formatBytes renders a byte count with a binary unit: 512 B, 3.4 MiB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// pruneMarks fills in the marks of sessions, which say which ones are
+// pinned. Unlike listing, pruning without them could delete a pinned
+// transcript, so any store that can't be read is an error.
+func pruneMarks(sessions []session.SessionInfo) ([]session.SessionInfo, error) {
+	marks, err := meta.LoadStores(meta.DefaultPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session marks: %w", err)
+	}
+	for _, s := range sessions {
+		if _, err := marks.Workspace(s.ProjectPath); err != nil {
+			return nil, fmt.Errorf("failed to read session marks for %s: %w", s.ProjectPath, err)
+		}
+	}
+	return applyMarks(sessions, marks, true), nil
+}
+
+// whereCandidates keeps the prune candidates belonging to sessions that
+// match where; sessions must already carry their marks. Transcripts whose
+// session is unknown never match.
+func whereCandidates(candidates []session.PruneCandidate, sessions []session.SessionInfo, where whereExpr) []session.PruneCandidate {
+	matched := make(map[string]bool)
+	for _, s := range sessions {
+		if where.match(s) {
+			matched[s.SessionID] = true
+		}
+	}
+	var kept []session.PruneCandidate
+	for _, c := range candidates {
+		if c.SessionID != "" && matched[c.SessionID] {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
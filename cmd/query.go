@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -18,32 +20,28 @@ import (
 var ulogQuery = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.query")
 
 func newQueryCmd() *cobra.Command {
+	var grep grepFlags
 	cmd := &cobra.Command{
-		Use:   "query <session_id>",
-		Short: "Query messages from a transcript",
-		Args:  cobra.ExactArgs(1),
+		Use:               "query <session_id>",
+		Short:             "Query messages from a transcript",
+		Long:              "Lists the messages of a session. The session ID may be abbreviated to any unique prefix of at least 4 characters.\n\n--grep lists only the messages whose text matches a regular expression; --invert lists the messages that don't. -A, -B and -C add the messages after, before or around each match, as with grep.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			sessionID := args[0]
 			role, _ := cmd.Flags().GetString("role")
 			jsonOutput, _ := cmd.Flags().GetBool("json")
+			grepRe, grepOpts, err := grep.compile(cmd)
+			if err != nil {
+				return err
+			}
 
-			// The historical Claude path-glob lookup runs first, unchanged;
-			// only when it misses is the tiered multi-provider resolver
-			// consulted (codex/pi/opencode session ids, flow job ids).
-			provider := "claude"
-			transcriptPath, err := transcript.GetTranscriptPathLegacy(sessionID)
+			transcriptPath, provider, err := resolveTranscript(sessionID)
 			if err != nil {
-				info, rerr := session.ResolveSessionInfo(sessionID)
-				if rerr != nil || info.LogFilePath == "" {
-					return fmt.Errorf("failed to find transcript: %w", err)
-				}
-				transcriptPath = info.LogFilePath
-				if info.Provider != "" {
-					provider = info.Provider
-				}
+				return err
 			}
 
-			messages, err := queryMessages(transcriptPath, provider)
+			messages, err := queryMessages(cmd.Context(), transcriptPath, provider)
 			if err != nil {
 				return fmt.Errorf("failed to parse transcript: %w", err)
 			}
@@ -54,6 +52,14 @@ func newQueryCmd() *cobra.Command {
 					filtered = append(filtered, msg)
 				}
 			}
+			if grepRe != nil {
+				match := func(i int) bool { return grepRe.MatchString(filtered[i].Content) != grepOpts.Invert }
+				var grepped []transcript.ExtractedMessage
+				for _, i := range transcript.WithContext(len(filtered), match, grepOpts.Before, grepOpts.After) {
+					grepped = append(grepped, filtered[i])
+				}
+				filtered = grepped
+			}
 
 			if jsonOutput {
 				data, err := json.MarshalIndent(filtered, "", "  ")
@@ -100,18 +106,43 @@ func newQueryCmd() *cobra.Command {
 	}
 
 	cmd.Flags().String("role", "", "Filter by message role (user, assistant)")
+	grep.register(cmd, "messages", "text")
 	cmd.Flags().Bool("json", false, "Output in JSON format")
 
 	return cmd
 }
 
+// resolveTranscript finds the transcript file and provider for a session ID,
+// full or abbreviated. The historical Claude path-glob lookup runs first,
+// unchanged; only when it misses is the tiered multi-provider resolver
+// consulted (codex/pi/opencode session ids, flow job ids, ID prefixes).
+func resolveTranscript(sessionID string) (path, provider string, err error) {
+	path, err = transcript.GetTranscriptPathLegacy(sessionID)
+	if err == nil {
+		return path, "claude", nil
+	}
+	info, rerr := session.ResolveSessionInfo(sessionID)
+	var amb *session.AmbiguousSessionError
+	if errors.As(rerr, &amb) {
+		return "", "", amb
+	}
+	if rerr != nil || info.LogFilePath == "" {
+		return "", "", fmt.Errorf("failed to find transcript: %w", err)
+	}
+	provider = info.Provider
+	if provider == "" {
+		provider = "claude"
+	}
+	return info.LogFilePath, provider, nil
+}
+
 // queryMessages extracts the messages of a resolved transcript, routed by
 // provider. Claude keeps the historical Parser.ParseFile chain; codex uses
 // the codex-shaped parser; pi and opencode go through their normalizers
 // (linearized active branch for pi, fragment assembly for opencode — path is
 // the session info file there) and flatten to the same ExtractedMessage
 // shape.
-func queryMessages(path, provider string) ([]transcript.ExtractedMessage, error) {
+func queryMessages(ctx context.Context, path, provider string) ([]transcript.ExtractedMessage, error) {
 	switch provider {
 	case "codex":
 		parser := transcript.NewParser()
@@ -132,7 +163,7 @@ func queryMessages(path, provider string) ([]transcript.ExtractedMessage, error)
 		return opencodeQueryMessages(path)
 	default:
 		parser := transcript.NewParser()
-		return parser.ParseFile(path)
+		return parser.ParseFileContext(ctx, path)
 	}
 }
 
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/grovetools/core/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/pkg/display"
+)
+
+func newQuoteCmd() *cobra.Command {
+	var rangeFlag int
+	var detailLevel string
+
+	cmd := cli.NewStandardCommand("quote", "Print a transcript excerpt as an attributed Markdown blockquote")
+	cmd.Use = "quote <spec>#<index>"
+	cmd.Long = `Prints one or more transcript entries as a Markdown blockquote followed by an
+attribution line (session link, provider, project, timestamp, and entry range),
+ready to paste into docs.
+
+<spec> can be a plan/job, a session ID, or a direct path to a log file.
+<index> is the 1-based entry number; omit "#<index>" to list the entries with
+their numbers and a one-line preview.
+
+Examples:
+  aglogs quote 3f2a9c1e#12
+  aglogs quote 3f2a9c1e#12 --range 3   # entries 12, 13 and 14
+  aglogs quote 3f2a9c1e                # list entries to pick from`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec, index, hasIndex, err := parseQuoteSpec(args[0])
+		if err != nil {
+			return err
+		}
+		if rangeFlag < 1 {
+			return fmt.Errorf("--range must be at least 1")
+		}
+
+		sessionInfo, err := resolveMetricsSession(spec)
+		if err != nil {
+			return err
+		}
+		startLine, endLine, _ := jobLineRange(sessionInfo, spec, "")
+
+		src := provider.SelectSource(sessionInfo, nil)
+		entries, err := src.Read(cmd.Context(), sessionInfo, provider.ReadOptions{
+			DetailLevel: detailLevel,
+			StartLine:   startLine,
+			EndLine:     endLine,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read transcript: %w", err)
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("transcript for '%s' has no entries", spec)
+		}
+
+		if !hasIndex {
+			for i, entry := range entries {
+				fmt.Fprintf(os.Stdout, "%4d  %-9s  %s\n", i+1, entry.Role, display.QuotePreview(entry, 100))
+			}
+			return nil
+		}
+
+		if index > len(entries) {
+			return fmt.Errorf("index %d out of range: transcript has %d entries", index, len(entries))
+		}
+		last := min(index+rangeFlag-1, len(entries))
+
+		return display.RenderQuote(os.Stdout, entries[index-1:last], display.QuoteSource{
+			SessionID:   sessionInfo.SessionID,
+			Provider:    sessionInfo.Provider,
+			Project:     sessionInfo.ProjectName,
+			LogFilePath: sessionInfo.LogFilePath,
+			First:       index,
+			Last:        last,
+		}, detailLevel)
+	}
+
+	cmd.Flags().IntVar(&rangeFlag, "range", 1, "Number of entries to quote, starting at <index>")
+	cmd.Flags().StringVar(&detailLevel, "detail", "summary", "Detail level for tool blocks ('summary' or 'full')")
+
+	return cmd
+}
+
+// parseQuoteSpec splits "<spec>#<index>" at the last '#'. A spec without a
+// '#' (or whose suffix is not a number, as in a path containing '#') is
+// returned whole with hasIndex false.
+func parseQuoteSpec(arg string) (spec string, index int, hasIndex bool, err error) {
+	i := strings.LastIndexByte(arg, '#')
+	if i < 0 {
+		return arg, 0, false, nil
+	}
+	n, convErr := strconv.Atoi(arg[i+1:])
+	if convErr != nil {
+		if _, statErr := os.Stat(arg); statErr == nil {
+			return arg, 0, false, nil
+		}
+		return "", 0, false, fmt.Errorf("invalid entry index %q: expected <spec>#<number>", arg[i+1:])
+	}
+	if n < 1 {
+		return "", 0, false, fmt.Errorf("entry index must be at least 1, got %d", n)
+	}
+	return arg[:i], n, true, nil
+}
@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	core_config "github.com/grovetools/core/config"
 	grovelogging "github.com/grovetools/core/logging"
@@ -17,17 +24,50 @@ import (
 	"github.com/grovetools/agentlogs/pkg/display"
 	"github.com/grovetools/agentlogs/pkg/formatters"
 	"github.com/grovetools/agentlogs/pkg/transcript"
+	"github.com/grovetools/agentlogs/pkg/usage"
 )
 
 var ulogRead = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.read")
 
 func newReadCmd() *cobra.Command {
 	var jsonOutput bool
+	var planPath string
+	var providerFlag string
+	var noHeader bool
+	var plansOnly bool
+	var includeSubagents bool
+	var rawEscapes bool
+	var noChain bool
+	var grep grepFlags
 	cmd := &cobra.Command{
 		Use:   "read <spec>",
 		Short: "Read logs for a specific job, session, or log file",
-		Long:  "Reads logs for a job execution. <spec> can be a plan/job, a session ID, or a direct path to a job or log file.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Reads logs for a job execution. <spec> can be a plan/job, a session ID (or
+a unique prefix of one), or a direct path to a job or log file.
+
+When a plan/job spec matches jobs in more than one plan directory (two plans
+named alike in different repos, say), pass the job file path or --plan-path
+to pick one. In a terminal, read lists the candidate plans and asks.
+
+Transcripts compressed by 'aglogs prune --compress' or 'aglogs archive
+--compress' (.jsonl.gz) are read like any other.
+
+--include-subagents shows what Claude's Task sub-agents did: each
+sub-agent's sidechain transcript (agent-*.jsonl) is read and shown
+indented under the Task call that spawned it.
+
+A Claude session resumed with 'claude --resume' or '--continue' goes on in
+a new transcript file. read stitches the files of such a chain into one
+transcript, leaving out the history each new file repeats, and a job's
+transcript runs on into later files until the next job starts. --no-chain
+reads only the one file.
+
+--grep shows only the entries whose text, tool names, tool inputs (commands,
+file paths) or tool output match a regular expression; --invert shows the
+entries that don't. As with grep, -A, -B and -C add the entries after,
+before or around each match for context.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionSpecs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			spec := args[0]
 			detailFlag, _ := cmd.Flags().GetString("detail")
@@ -37,6 +77,17 @@ func newReadCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			grepRe, grepOpts, err := grep.compile(cmd)
+			if err != nil {
+				return err
+			}
+
+			var providers []string
+			if providerFlag != "" {
+				if providers, err = parseProviderFlag(providerFlag); err != nil {
+					return err
+				}
+			}
 
 			var sessionInfo *session.SessionInfo
 
@@ -45,9 +96,9 @@ func newReadCmd() *cobra.Command {
 			// files that happen to exist in the cwd.
 			if isLogFilePath(spec) {
 				// Construct minimal SessionInfo from the file path
-				prov := "claude"
-				if strings.Contains(spec, "/.codex/") {
-					prov = "codex"
+				prov := session.ProviderForPath(spec)
+				if len(providers) > 0 && !slices.Contains(providers, prov) {
+					return fmt.Errorf("%s is a %s transcript, not %s", spec, prov, providerFlag)
 				}
 
 				// Extract session ID and project name from path if possible
@@ -75,29 +126,25 @@ func newReadCmd() *cobra.Command {
 				}
 			} else {
 				// Slow path: resolve session from spec
-				sessionInfo, err = session.ResolveSessionInfo(spec)
+				sessionInfo, err = session.ResolveSessionInfoWithOptions(spec, session.ResolveOptions{
+					PlanPath:        planPath,
+					RejectAmbiguous: true,
+					Providers:       providers,
+				})
+				var amb *session.AmbiguousJobError
+				if errors.As(err, &amb) && isInteractive(os.Stdin) {
+					sessionInfo, err = chooseAmbiguousJob(os.Stdin, os.Stderr, amb)
+					if err == nil {
+						planPath = session.CandidatePlanPath(*sessionInfo, spec)
+					}
+				}
 				if err != nil {
 					return fmt.Errorf("could not resolve session for '%s': %w", spec, err)
 				}
 			}
 
 			// Find the specific job within the session if the spec was a plan/job
-			startLine := 0
-			endLine := -1 // -1 = read to end
-			parts := strings.Split(spec, "/")
-			if len(parts) == 2 {
-				planName := parts[0]
-				jobName := parts[1]
-				for i, job := range sessionInfo.Jobs {
-					if job.Plan == planName && job.Job == jobName {
-						startLine = job.LineIndex
-						if i+1 < len(sessionInfo.Jobs) {
-							endLine = sessionInfo.Jobs[i+1].LineIndex
-						}
-						break
-					}
-				}
-			}
+			startLine, endLine, isJobRead := jobLineRange(sessionInfo, spec, planPath)
 
 			// --- Configuration Loading ---
 			var detailLevel string
@@ -128,46 +175,112 @@ func newReadCmd() *cobra.Command {
 
 			src := provider.SelectSource(sessionInfo, daemonClient)
 			opts := provider.ReadOptions{
-				DetailLevel:  detailLevel,
-				MaxDiffLines: maxDiffLines,
-				StartLine:    startLine,
-				EndLine:      endLine,
+				DetailLevel:      detailLevel,
+				MaxDiffLines:     maxDiffLines,
+				StartLine:        startLine,
+				EndLine:          endLine,
+				IncludeSubagents: includeSubagents,
 			}
 
-			entries, err := src.Read(cmd.Context(), sessionInfo, opts)
+			var chain session.Chain
+			if !noChain {
+				chain = resumeChain(sessionInfo)
+			}
+			var entries []transcript.UnifiedEntry
+			var windows []chainWindow
+			lastPath := sessionInfo.LogFilePath
+			if len(chain) > 1 {
+				segs := chain.Segments()
+				if isJobRead {
+					segs = chain.JobSegments(sessionInfo.LogFilePath, startLine)
+				}
+				entries, windows, err = readChainSegments(cmd.Context(), src, sessionInfo, opts, segs)
+				if n := len(segs); n > 0 {
+					lastPath, endLine = segs[n-1].Path, segs[n-1].EndLine
+				}
+			} else {
+				entries, err = src.Read(cmd.Context(), sessionInfo, opts)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to read transcript: %w", err)
 			}
+			pending := endLine < 0 && lastPath != "" && transcript.PendingLine(lastPath)
+
+			if plansOnly {
+				plans := transcript.ExtractPlans(entries)
+				if jsonOutput {
+					return printJSON(plans)
+				}
+				if len(plans) == 0 {
+					ulogRead.Info("No plans found").
+						Field("session_id", sessionInfo.SessionID).
+						Pretty("No plans were proposed in this session.").
+						PrettyOnly().
+						Emit()
+					return nil
+				}
+				return display.RenderPlans(os.Stdout, plans, style)
+			}
+
+			// Per-job token/cost attribution, only for plan/job reads.
+			var jobCost *usage.Summary
+			if isJobRead && sessionInfo.LogFilePath != "" {
+				jobCost, err = jobUsage(sessionInfo, entries, windows...)
+				if err != nil {
+					ulogRead.Debug("Could not compute job usage").Err(err).Emit()
+				}
+			}
+
+			if grepRe != nil {
+				entries = transcript.GrepEntries(entries, grepRe, grepOpts)
+			}
 
 			// --- Output ---
 			if jsonOutput {
-				output := struct {
-					Entries     []transcript.UnifiedEntry `json:"entries"`
-					LogFilePath string                    `json:"log_file_path"`
-					Provider    string                    `json:"provider"`
-					SessionID   string                    `json:"session_id"`
-				}{
+				output := readJSONOutput{
 					Entries:     entries,
 					LogFilePath: sessionInfo.LogFilePath,
 					Provider:    sessionInfo.Provider,
 					SessionID:   sessionInfo.SessionID,
+					StartLine:   startLine,
+					EndLine:     endLine,
+					JobUsage:    jobCost,
+					Pending:     pending,
+				}
+				if len(chain) > 1 {
+					output.Chain = chain
+				}
+				if isJobRead {
+					js, _ := session.ParseJobSpec(spec, planPath)
+					output.Plan, output.Job = js.Plan, js.Job
 				}
 				jsonData, err := json.Marshal(output)
 				if err != nil {
 					return fmt.Errorf("failed to marshal to JSON: %w", err)
 				}
-				ulogRead.Info("Read log content").
+				ulogRead.Debug("Read log content").
 					Field("session_id", sessionInfo.SessionID).
 					Field("provider", sessionInfo.Provider).
 					Field("entry_count", len(entries)).
-					Pretty(string(jsonData)).
-					PrettyOnly().
 					Emit()
+				// Write JSON directly to stdout for machine-readable output
+				fmt.Fprintln(os.Stdout, string(jsonData))
 			} else {
-				renderOpts := display.RenderOptions{Style: style, DetailLevel: detailLevel}
+				if !noHeader {
+					if err := display.RenderSessionHeader(os.Stdout, loadSessionHeader(sessionInfo), style); err != nil {
+						return fmt.Errorf("failed to render session header: %w", err)
+					}
+				}
+				renderOpts := display.RenderOptions{Style: style, DetailLevel: detailLevel, RawEscapes: rawEscapes}
 				if err := display.RenderUnifiedTranscript(os.Stdout, entries, renderOpts, toolFormatters); err != nil {
 					return fmt.Errorf("failed to render transcript: %w", err)
 				}
+				if pending {
+					printPendingNote(os.Stdout, style)
+				}
+				if jobCost != nil {
+					printJobUsageFooter(os.Stdout, jobCost, style)
+				}
 			}
 
 			return nil
@@ -176,6 +289,218 @@ func newReadCmd() *cobra.Command {
 
 	cmd.Flags().String("detail", "", "Set detail level for output ('summary' or 'full'). Overrides config.")
 	cmd.Flags().String("style", "terminal", "Output style: 'terminal' (colors/icons) or 'markdown' (environment-independent)")
-	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format with additional metadata")
+	cmd.Flags().StringVar(&planPath, "plan-path", "", "Plan directory that a plan/job spec refers to, when plan names are ambiguous")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Only match sessions from these providers (comma-separated), e.g. when a plan/job ran under several agents")
+	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Omit the session metadata header before the transcript")
+	cmd.Flags().BoolVar(&plansOnly, "plans-only", false, "Show only the plans proposed in plan mode (ExitPlanMode), with their outcome")
+	cmd.Flags().BoolVar(&includeSubagents, "include-subagents", false, "Show Claude Task sub-agent transcripts nested under the calls that spawned them")
+	cmd.Flags().BoolVar(&rawEscapes, "raw-escapes", false, "Print escape sequences and control characters in transcript text as is instead of stripping them")
+	cmd.Flags().BoolVar(&noChain, "no-chain", false, "Read only the given transcript file, not the files of the sessions it resumed or was resumed in")
+	grep.register(cmd, "entries", "text, tool name, tool input or output")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output normalized entries (for a plan/job, just that job's line range) as JSON")
 	return cmd
 }
+
+// readJSONOutput is the document printed by `read --json`. For plan/job
+// specs, Entries cover only that job's line range and Plan/Job name it.
+type readJSONOutput struct {
+	Entries     []transcript.UnifiedEntry `json:"entries"`
+	LogFilePath string                    `json:"log_file_path"`
+	Provider    string                    `json:"provider"`
+	SessionID   string                    `json:"session_id"`
+	Plan        string                    `json:"plan,omitempty"`
+	Job         string                    `json:"job,omitempty"`
+	StartLine   int                       `json:"start_line"`
+	EndLine     int                       `json:"end_line"` // -1 = end of transcript
+	JobUsage    *usage.Summary            `json:"job_usage,omitempty"`
+	// Chain lists the transcript files of a resumed session that Entries
+	// were stitched from, oldest first; absent for a single file.
+	Chain session.Chain `json:"chain,omitempty"`
+	// Pending is set when the transcript ends in a line the agent is still
+	// writing, which is left out of Entries.
+	Pending bool `json:"pending,omitempty"`
+}
+
+// jobLineRange returns the transcript line range covering a plan/job spec
+// (or job file path) within a session, and whether the spec named one of the
+// session's jobs. planPath qualifies a bare plan/job spec as in --plan-path.
+// Other specs cover the whole transcript (0, -1).
+func jobLineRange(info *session.SessionInfo, spec, planPath string) (startLine, endLine int, found bool) {
+	endLine = -1 // -1 = read to end
+	js, ok := session.ParseJobSpec(spec, planPath)
+	if !ok {
+		return 0, endLine, false
+	}
+	for i, job := range info.Jobs {
+		if js.Matches(job) {
+			startLine = job.LineIndex
+			if i+1 < len(info.Jobs) {
+				endLine = info.Jobs[i+1].LineIndex
+			}
+			return startLine, endLine, true
+		}
+	}
+	return 0, endLine, false
+}
+
+// chooseAmbiguousJob lists the plans an ambiguous plan/job spec matched and
+// reads the user's choice from in. It returns the chosen candidate.
+func chooseAmbiguousJob(in io.Reader, out io.Writer, amb *session.AmbiguousJobError) (*session.SessionInfo, error) {
+	fmt.Fprintf(out, "'%s' matches jobs in %d plans:\n", amb.Spec, len(amb.Candidates))
+	for i, c := range amb.Candidates {
+		started := "-"
+		if !c.StartedAt.IsZero() {
+			started = c.StartedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(out, "  %d) %s  (%s, started %s)\n", i+1, session.CandidatePlanPath(c, amb.Spec), c.ProjectName, started)
+	}
+	fmt.Fprintf(out, "Choose a plan [1-%d]: ", len(amb.Candidates))
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(out)
+		return nil, amb
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(amb.Candidates) {
+		return nil, fmt.Errorf("invalid choice %q", strings.TrimSpace(line))
+	}
+	return &amb.Candidates[n-1], nil
+}
+
+// isInteractive reports whether f is a terminal rather than a pipe or file.
+func isInteractive(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// jobUsage prices the usage recorded during a job's slice of the transcript.
+// The slice's line range is mapped to the time span of its entries, since
+// usage records are matched by timestamp rather than line number. windows,
+// for a job of a resumed session, give the span the job covers in each file.
+func jobUsage(info *session.SessionInfo, entries []transcript.UnifiedEntry, windows ...chainWindow) (*usage.Summary, error) {
+	var from, to time.Time
+	for _, e := range entries {
+		if e.Timestamp.IsZero() {
+			continue
+		}
+		if from.IsZero() || e.Timestamp.Before(from) {
+			from = e.Timestamp
+		}
+		if e.Timestamp.After(to) {
+			to = e.Timestamp
+		}
+	}
+	if from.IsZero() {
+		return nil, nil
+	}
+	if len(windows) == 0 {
+		windows = []chainWindow{{path: info.LogFilePath, from: from, to: to}}
+	}
+	// A job of a resumed session spans several files; each is priced over
+	// the stretch the job ran in it, so history a file repeats from the
+	// files before it isn't counted twice.
+	var total *usage.Summary
+	for _, w := range windows {
+		s, err := usage.SummarizeTranscriptWindow(w.path, info.Provider, usage.CostModeCalculate, w.from, w.to)
+		if err != nil {
+			return nil, err
+		}
+		if total == nil {
+			total = &s
+			continue
+		}
+		total.Usage.Add(s.Usage)
+		total.CostUSD += s.CostUSD
+		total.MissingPricing = total.MissingPricing || s.MissingPricing
+		total.MessageCount += s.MessageCount
+	}
+	return total, nil
+}
+
+// resumeChain returns the chain of transcript files a Claude session was
+// written to, or nil when it has just the one file (or the chain can't be
+// worked out).
+func resumeChain(info *session.SessionInfo) session.Chain {
+	if info.LogFilePath == "" || (info.Provider != "" && info.Provider != "claude") {
+		return nil
+	}
+	chain, err := session.ResumeChain(info.LogFilePath)
+	if err != nil {
+		ulogRead.Debug("Could not link resumed transcripts").Err(err).Field("path", info.LogFilePath).Emit()
+		return nil
+	}
+	if len(chain) < 2 {
+		return nil
+	}
+	return chain
+}
+
+// chainWindow is the time span a segment of a chain covers in its file.
+type chainWindow struct {
+	path     string
+	from, to time.Time
+}
+
+// readChainSegments reads the parts of a resumed session's chain in order,
+// with the time span of each.
+func readChainSegments(ctx context.Context, src provider.TranscriptSource, info *session.SessionInfo, opts provider.ReadOptions, segs []session.ChainSegment) ([]transcript.UnifiedEntry, []chainWindow, error) {
+	var entries []transcript.UnifiedEntry
+	var windows []chainWindow
+	for _, seg := range segs {
+		part := *info
+		part.LogFilePath = seg.Path
+		opts.StartLine, opts.EndLine = seg.StartLine, seg.EndLine
+		got, err := src.Read(ctx, &part, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		w := chainWindow{path: seg.Path}
+		for _, e := range got {
+			if e.Timestamp.IsZero() {
+				continue
+			}
+			if w.from.IsZero() || e.Timestamp.Before(w.from) {
+				w.from = e.Timestamp
+			}
+			if e.Timestamp.After(w.to) {
+				w.to = e.Timestamp
+			}
+		}
+		if !w.from.IsZero() {
+			windows = append(windows, w)
+		}
+		entries = append(entries, got...)
+	}
+	return entries, windows, nil
+}
+
+// printPendingNote notes that the transcript's last message is still being
+// written and was left out.
+func printPendingNote(w io.Writer, style display.RenderStyle) {
+	const note = "… a message is still being written; read again to see it"
+	if style == display.StyleMarkdown {
+		fmt.Fprintf(w, "\n_%s_\n", note)
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", note)
+}
+
+// printJobUsageFooter writes the per-job token and cost summary shown after
+// a plan/job transcript.
+func printJobUsageFooter(w io.Writer, s *usage.Summary, style display.RenderStyle) {
+	line := fmt.Sprintf("Job usage: %s tokens (in %s, out %s, cache read %s, cache write %s) · est. $%.4f",
+		formatNumber(s.Usage.Total()),
+		formatNumber(s.Usage.Input),
+		formatNumber(s.Usage.Output),
+		formatNumber(s.Usage.CacheRead),
+		formatNumber(s.Usage.CacheWrite5m+s.Usage.CacheWrite1h),
+		s.CostUSD)
+	if s.MissingPricing {
+		line += " (some models unpriced; cost is a lower bound)"
+	}
+	if style == display.StyleMarkdown {
+		fmt.Fprintf(w, "\n---\n\n_%s_\n", line)
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n%s\n", strings.Repeat("─", 50), line)
+}
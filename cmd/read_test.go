@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func TestJobLineRange(t *testing.T) {
+	info := &session.SessionInfo{Jobs: []session.JobInfo{
+		{Plan: "p", Job: "01-spec.md", LineIndex: 3},
+		{Plan: "p", Job: "02-impl.md", LineIndex: 9},
+	}}
+	tests := []struct {
+		spec       string
+		start, end int
+		found      bool
+	}{
+		{"p/01-spec.md", 3, 9, true},
+		{"/repo/plans/p/01-spec.md", 3, 9, true},
+		{"p/02-impl.md", 9, -1, true},
+		{"p/03-missing.md", 0, -1, false},
+		{"session-id", 0, -1, false},
+	}
+	for _, tt := range tests {
+		start, end, found := jobLineRange(info, tt.spec, "")
+		if start != tt.start || end != tt.end || found != tt.found {
+			t.Errorf("jobLineRange(%q) = (%d, %d, %v), want (%d, %d, %v)", tt.spec, start, end, found, tt.start, tt.end, tt.found)
+		}
+	}
+}
+
+func TestReadJSONOutput(t *testing.T) {
+	path, err := filepath.Abs(claudeFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	cmd := newReadCmd()
+	cmd.SetArgs([]string{path, "--json"})
+	runErr := cmd.Execute()
+	w.Close()
+	os.Stdout = stdout
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	data, _ := io.ReadAll(r)
+
+	var out readJSONOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, data)
+	}
+	if out.Provider != "claude" || out.LogFilePath != path || len(out.Entries) == 0 {
+		t.Errorf("unexpected output: provider=%q path=%q entries=%d", out.Provider, out.LogFilePath, len(out.Entries))
+	}
+	if out.StartLine != 0 || out.EndLine != -1 || out.Plan != "" {
+		t.Errorf("whole-file read reported range %d..%d plan %q", out.StartLine, out.EndLine, out.Plan)
+	}
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"time"
+
+	aglogs_config "github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/pkg/redact"
+)
+
+// loadRedactor builds the redactor configured under aglogs.redaction, or
+// nil when none is configured. Export and serve pass every entry through
+// it before it leaves the process.
+func loadRedactor() (redact.Redactor, error) {
+	cfg := aglogs_config.Load().Redaction
+	return redact.New(redact.Options{
+		Patterns:    cfg.Patterns,
+		Command:     cfg.Command,
+		WASM:        cfg.WASM,
+		WASMRuntime: cfg.WASMRuntime,
+		Timeout:     time.Duration(cfg.TimeoutSeconds) * time.Second,
+	})
+}
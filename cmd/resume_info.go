@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grovetools/core/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func newResumeInfoCmd() *cobra.Command {
+	var jsonOutput, shellOutput bool
+
+	cmd := cli.NewStandardCommand("resume-info", "Print the command that resumes a session in its agent")
+	cmd.Use = "resume-info <spec>"
+	cmd.Long = `Prints the provider-native command that reopens a session, and the
+directory it must be run from:
+
+  claude    claude --resume <id>
+  codex     codex resume <id>
+  pi        pi --session <transcript>
+  opencode  opencode --session <id>
+
+<spec> is a session ID, a plan/job, or a path to a job or log file.
+Use --shell for a single 'cd <dir> && <command>' line suitable for eval, or
+--json for {"provider","sessionId","workDir","argv"}.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		if jsonOutput && shellOutput {
+			return fmt.Errorf("--json and --shell are mutually exclusive")
+		}
+
+		var info *session.SessionInfo
+		var err error
+		if isLogFilePath(spec) {
+			info, err = resolveMetricsSession(spec)
+		} else {
+			info, err = session.ResolveSessionInfo(spec)
+		}
+		if err != nil {
+			return fmt.Errorf("could not resolve session for '%s': %w", spec, err)
+		}
+		resume, err := session.NewResumeInfo(info)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case jsonOutput:
+			return printJSON(resume)
+		case shellOutput:
+			fmt.Fprintln(os.Stdout, resume.ShellCommand())
+		default:
+			dir := resume.WorkDir
+			if dir == "" {
+				dir = "(unknown)"
+			}
+			fmt.Fprintf(os.Stdout, "Provider:  %s\n", resume.Provider)
+			fmt.Fprintf(os.Stdout, "Session:   %s\n", resume.SessionID)
+			fmt.Fprintf(os.Stdout, "Directory: %s\n", dir)
+			fmt.Fprintf(os.Stdout, "Command:   %s\n", strings.Join(resume.Argv, " "))
+		}
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print as JSON")
+	cmd.Flags().BoolVar(&shellOutput, "shell", false, "Print one shell line that changes directory and resumes")
+
+	return cmd
+}
@@ -12,16 +12,54 @@ func NewRootCmd() *cobra.Command {
 		"Agent transcript log parsing and monitoring",
 	)
 
+	rootCmd.AddCommand(newInitCmd())
 	rootCmd.AddCommand(newListCmd())
+	rootCmd.AddCommand(newMarkCmd())
+	rootCmd.AddCommand(newMetaCmd())
+	rootCmd.AddCommand(newBookmarkCmd())
+	rootCmd.AddCommand(newAnnotateCmd())
+	rootCmd.AddCommand(newPinCmd())
+	rootCmd.AddCommand(newStatusCmd())
+	rootCmd.AddCommand(newWatchCmd())
+	rootCmd.AddCommand(newOverviewCmd())
+	rootCmd.AddCommand(newTrendsCmd())
+	rootCmd.AddCommand(newScheduleCmd())
 	rootCmd.AddCommand(newTailCmd())
 	rootCmd.AddCommand(newQueryCmd())
+	rootCmd.AddCommand(newSearchCmd())
 	rootCmd.AddCommand(newReadCmd())
+	rootCmd.AddCommand(newShowCmd())
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newHandoffCmd())
+	rootCmd.AddCommand(newChangesCmd())
+	rootCmd.AddCommand(newCommitsCmd())
+	rootCmd.AddCommand(newAttachmentsCmd())
+	rootCmd.AddCommand(newCommandsCmd())
+	rootCmd.AddCommand(newResumeInfoCmd())
 	rootCmd.AddCommand(newGetSessionInfoCmd())
 	rootCmd.AddCommand(newStreamCmd())
 	rootCmd.AddCommand(newWorkflowCmd())
 	rootCmd.AddCommand(newTokensCmd())
 	rootCmd.AddCommand(newMetricsCmd())
+	rootCmd.AddCommand(newStatsCmd())
+	rootCmd.AddCommand(newTimelineCmd())
+	rootCmd.AddCommand(newCheckCmd())
+	rootCmd.AddCommand(newAdviseCmd())
+	rootCmd.AddCommand(newSummarizeCmd())
 	rootCmd.AddCommand(newUsageCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newSplitCmd())
+	rootCmd.AddCommand(newArchiveCmd())
+	rootCmd.AddCommand(newPruneCmd())
+	rootCmd.AddCommand(newIndexCmd())
+	rootCmd.AddCommand(newPlansCmd())
+	rootCmd.AddCommand(newPlanCmd())
+	rootCmd.AddCommand(newQuoteCmd())
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newTuiCmd())
+	rootCmd.AddCommand(newProvidersCmd())
+	rootCmd.AddCommand(newDoctorCmd())
+	rootCmd.AddCommand(newSelftestCmd())
 	rootCmd.AddCommand(NewVersionCmd())
 
 	return rootCmd
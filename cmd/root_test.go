@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestRootCommandSet guards the command set: every command the binary
+// ships is registered on the one root, once, and can run.
+func TestRootCommandSet(t *testing.T) {
+	root := NewRootCmd()
+
+	want := []string{
+		"init", "list", "mark", "meta", "bookmark", "annotate", "pin", "status",
+		"watch", "overview", "trends", "schedule", "tail", "query", "search",
+		"read", "show", "diff", "handoff", "changes", "commits", "attachments",
+		"commands", "resume-info", "get-session-info", "stream", "workflow",
+		"tokens", "metrics", "stats", "timeline", "check", "advise", "summarize",
+		"usage", "export", "split", "archive", "prune", "index", "plans", "plan",
+		"quote", "serve", "tui", "providers", "doctor", "selftest", "version",
+	}
+	for _, name := range want {
+		if c, _, err := root.Find([]string{name}); err != nil || c == root {
+			t.Errorf("command %q is not registered", name)
+		}
+	}
+
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		seen := map[string]bool{}
+		for _, sub := range c.Commands() {
+			if seen[sub.Name()] {
+				t.Errorf("%s: command %q registered twice", c.CommandPath(), sub.Name())
+			}
+			seen[sub.Name()] = true
+			if !sub.Runnable() && !sub.HasSubCommands() {
+				t.Errorf("%s does nothing: no run function and no subcommands", sub.CommandPath())
+			}
+			walk(sub)
+		}
+	}
+	walk(root)
+}
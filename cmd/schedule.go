@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	aglogs_config "github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/schedule"
+)
+
+var ulogSchedule = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.schedule")
+
+func newScheduleCmd() *cobra.Command {
+	cmd := cli.NewStandardCommand("schedule", "Run aglogs commands on a schedule")
+	cmd.Long = `Runs aglogs commands on cron schedules, such as a daily overview of the
+team's sessions or a weekly prune, and keeps or posts their output. Tasks
+are configured in the schedule section of the aglogs config:
+
+  schedule:
+    output_dir: ~/reports/aglogs
+    webhook_url: https://hooks.example.com/...
+    tasks:
+      - name: daily-overview
+        schedule: "0 7 * * 1-5"
+        command: [overview, --since, 24h]
+      - name: weekly-prune
+        schedule: "@weekly"
+        command: [prune, --older-than, 90d, --compress, --yes]
+        webhook_url: "-"
+
+Each run's standard output is written to output_dir as
+<task>-<YYYYMMDD-HHMM>.txt (.json when the command has --json) and posted
+to webhook_url as the "message" of a task.completed or task.failed event.
+A task's own output_dir or webhook_url replaces the section's; "-" turns it
+off for the task.
+
+'aglogs schedule run' runs the tasks that are due: run it every minute from
+cron, or keep it running under a service manager with --loop. A daemon that
+runs the transcript monitor with its schedule set checks for due tasks
+every minute itself, so with it running nothing else is needed. A lock file
+beside the schedule state keeps overlapping runs from running a task twice.`
+	cmd.AddCommand(newScheduleListCmd())
+	cmd.AddCommand(newScheduleRunCmd())
+	return cmd
+}
+
+// scheduleRow is one task listed by 'schedule list'.
+type scheduleRow struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule"`
+	Command  []string  `json:"command"`
+	LastRun  time.Time `json:"lastRun,omitzero"`
+	NextRun  time.Time `json:"nextRun,omitzero"`
+}
+
+func newScheduleListCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := cli.NewStandardCommand("list", "List the scheduled tasks and when they run next")
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		tasks, err := schedule.LoadTasks(aglogs_config.Load().Schedule)
+		if err != nil {
+			return err
+		}
+		state := schedule.LoadState(schedule.DefaultStatePath())
+		now := time.Now()
+		rows := []scheduleRow{}
+		for _, t := range tasks {
+			rows = append(rows, scheduleRow{
+				Name:     t.Name,
+				Schedule: t.Schedule,
+				Command:  t.Command,
+				LastRun:  state.LastRun[t.Name],
+				NextRun:  state.Next(t.Name, t.Spec, now),
+			})
+		}
+
+		if jsonOutput {
+			return printJSON(rows)
+		}
+		if len(rows) == 0 {
+			ulogSchedule.Info("No scheduled tasks").Pretty("No scheduled tasks. Add them to the schedule section of the aglogs config.").PrettyOnly().Emit()
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(tw, "TASK\tSCHEDULE\tLAST RUN\tNEXT RUN\tCOMMAND")
+		for _, r := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Name, r.Schedule, formatScheduleTime(r.LastRun), formatScheduleTime(r.NextRun), strings.Join(r.Command, " "))
+		}
+		return tw.Flush()
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the tasks as JSON")
+	return cmd
+}
+
+func formatScheduleTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func newScheduleRunCmd() *cobra.Command {
+	var loop bool
+
+	cmd := cli.NewStandardCommand("run", "Run the scheduled tasks that are due")
+	cmd.Use = "run [task...]"
+	cmd.Long = `Runs each scheduled task that is due, once, however many of its runs were
+missed. A newly configured task first runs at its next scheduled time after
+'schedule run' sees it.
+
+Naming tasks runs them now, whatever their schedule.
+
+With --loop, keeps checking for due tasks every minute until interrupted.`
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		tasks, err := schedule.LoadTasks(aglogs_config.Load().Schedule)
+		if err != nil {
+			return err
+		}
+		var names []string
+		for _, t := range tasks {
+			names = append(names, t.Name)
+		}
+		for _, a := range args {
+			if !slices.Contains(names, a) {
+				return fmt.Errorf("no scheduled task named %q", a)
+			}
+		}
+		if loop && len(args) > 0 {
+			return fmt.Errorf("--loop runs tasks when due; it cannot be combined with task names")
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to find the aglogs executable: %w", err)
+		}
+
+		runner := &schedule.Runner{Tasks: tasks, Exe: exe, StatePath: schedule.DefaultStatePath()}
+		if !loop {
+			err := runner.Run(cmd.Context(), time.Now(), args...)
+			if errors.Is(err, schedule.ErrLocked) && len(args) == 0 {
+				// The process holding the lock runs the due tasks.
+				ulogSchedule.Info("Skipped scheduled run").Err(err).Pretty(err.Error() + "; skipping").Emit()
+				return nil
+			}
+			return err
+		}
+		ulogSchedule.Info("Running scheduled tasks").
+			Field("tasks", len(tasks)).
+			Pretty(fmt.Sprintf("Running %d scheduled tasks (Ctrl-C to stop)...", len(tasks))).
+			PrettyOnly().
+			Emit()
+		runner.Loop(cmd.Context())
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&loop, "loop", false, "Keep running, checking for due tasks every minute")
+	return cmd
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/grovetools/core/tui/theme"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/search"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/codelang"
+	"github.com/grovetools/agentlogs/pkg/display"
+)
+
+func newSearchCmd() *cobra.Command {
+	var sinceFlag, untilFlag, providerFlag, projectFilter, langFilter, order string
+	var limit, radius int
+	var includeTest, jsonOutput, rawEscapes bool
+
+	cmd := cli.NewStandardCommand("search", "Search transcript entries across sessions")
+	cmd.Use = "search <text>"
+	cmd.Long = `Finds the entries containing <text> (case-insensitively) in every session's
+transcript: prompts, replies, reasoning, commands and tool output. The
+transcripts are read in parallel; at most 5 hits come from any one session.
+
+--sort relevance (the default) ranks hits by how often the text occurs in
+the entry, weighting user prompts above assistant replies and those above
+hook output, and halving the score for every month a hit is older than the
+newest one. --sort time lists the newest hits first, reading sessions
+newest first and stopping once --limit hits are found.
+
+Each snippet is the entry's text within --snippet-width characters of its
+first match, on one line, with the matched text highlighted when the output
+is a color terminal (NO_COLOR turns it off). For a query of several words, each
+word other than a stop word (the, of, and, ...) is highlighted too. Escape
+sequences and control characters in snippets are stripped unless
+--raw-escapes is given.
+
+--since, --until and --provider narrow the sessions searched as for
+'aglogs list'. --lang keeps only entries whose code is mostly in that
+language; <text> may then be "". Test runs are skipped unless --include-test is given.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		query := args[0]
+		if !slices.Contains(search.Sorts, order) {
+			return fmt.Errorf("invalid --sort %q: must be one of %s", order, strings.Join(search.Sorts, ", "))
+		}
+		if limit <= 0 {
+			return fmt.Errorf("--limit must be positive")
+		}
+		if radius <= 0 {
+			return fmt.Errorf("--snippet-width must be positive")
+		}
+		var lang string
+		if langFilter != "" {
+			if lang = codelang.Normalize(langFilter); lang == "" {
+				return fmt.Errorf("invalid --lang %q: unknown language", langFilter)
+			}
+		}
+		if query == "" && lang == "" {
+			return fmt.Errorf("search text must not be empty without --lang")
+		}
+		var providers []string
+		if providerFlag != "" {
+			var err error
+			if providers, err = parseProviderFlag(providerFlag); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		since, err := parseTimeFlag(sinceFlag, now)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		until, err := parseTimeFlag(untilFlag, now)
+		if err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+
+		sessions, err := session.NewScannerWithOptions(session.ScanOptions{Since: since, Until: until, Progress: newProgress("Scanning transcripts")}).ScanContext(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to scan for sessions: %w", err)
+		}
+		if len(providers) > 0 {
+			sessions = slices.DeleteFunc(sessions, func(s session.SessionInfo) bool { return !slices.Contains(providers, s.Provider) })
+		}
+		if projectFilter != "" {
+			loc := locationFilter{Project: projectFilter}
+			var filtered []session.SessionInfo
+			for _, s := range sessions {
+				if loc.matches(s) {
+					filtered = append(filtered, s)
+				}
+			}
+			sessions = filtered
+		}
+		sessions = filterTestSessions(sessions, includeTest)
+
+		hits, err := search.Run(cmd.Context(), sessions, search.Options{Query: query, Lang: lang, Limit: limit, Sort: order, Radius: radius})
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			return printJSON(hits)
+		}
+		if len(hits) == 0 {
+			fmt.Println("No matches.")
+			return nil
+		}
+		highlight := search.HighlightPattern(query)
+		matchStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.DefaultColors.Yellow)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "SESSION\tENTRY\tPROJECT\tSTARTED\tROLE\tSNIPPET")
+		for _, h := range hits {
+			snippet := h.Snippet
+			if !rawEscapes {
+				snippet = display.SanitizeText(snippet)
+			}
+			if highlight != nil {
+				snippet = highlight.ReplaceAllStringFunc(snippet, func(m string) string { return matchStyle.Render(m) })
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", h.Session, h.Seq, h.Project, h.StartedAt, h.Role, snippet)
+		}
+		return w.Flush()
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 20, "Show at most this many hits")
+	cmd.Flags().IntVar(&radius, "snippet-width", 60, "Keep about this many characters of each snippet on either side of the match")
+	cmd.Flags().StringVar(&order, "sort", search.SortRelevance, "Order hits by: "+strings.Join(search.Sorts, ", "))
+	cmd.Flags().StringVar(&langFilter, "lang", "", "Only match entries whose code is mostly in this language (go, python, ...)")
+	cmd.Flags().StringVar(&sinceFlag, "since", "", "Only search sessions active since this time: a duration (24h, 7d) or a date (2025-01-01)")
+	cmd.Flags().StringVar(&untilFlag, "until", "", "Only search sessions started before this time: a duration (24h, 7d) or a date (2025-01-01)")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Only search sessions from these providers (comma-separated: claude, codex, pi, opencode)")
+	cmd.Flags().StringVarP(&projectFilter, "project", "p", "", "Only search sessions whose project, worktree, plan or job name contains this text")
+	cmd.Flags().BoolVar(&includeTest, "include-test", false, "Also search test and demo runs")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the hits as JSON")
+	cmd.Flags().BoolVar(&rawEscapes, "raw-escapes", false, "Print escape sequences and control characters in snippets as is instead of stripping them")
+	return cmd
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/selftest"
+)
+
+func newSelftestCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := cli.NewStandardCommand("selftest", "Check the scan, normalize and render pipeline for every provider")
+	cmd.Long = `Creates a small synthetic session for each supported provider format
+(Claude, Codex, pi, OpenCode) in a temporary home directory, then runs it
+through the same scan → normalize → render pipeline the other commands use
+and reports pass/fail per provider.
+
+Your real transcripts are not read or modified. Exits non-zero if any
+provider fails, so it can be used as a post-upgrade check.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		results, err := selftest.Run(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, r := range results {
+			if !r.Passed {
+				failed++
+			}
+		}
+
+		if jsonOutput {
+			if err := printJSON(results); err != nil {
+				return err
+			}
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "PROVIDER\tRESULT\tENTRIES\tTIME\tDETAIL")
+			for _, r := range results {
+				result, detail := "pass", ""
+				if !r.Passed {
+					result = "FAIL"
+					detail = fmt.Sprintf("%s: %s", r.Stage, r.Error)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", r.Provider, result, r.Entries, r.Duration.Round(time.Millisecond), detail)
+			}
+			w.Flush()
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("selftest failed for %d of %d providers", failed, len(results))
+		}
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	aglogs_config "github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/serve"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+var ulogServe = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.serve")
+
+func newServeCmd() *cobra.Command {
+	var addr, tokensFile, auditLog string
+	var pollInterval time.Duration
+
+	cmd := cli.NewStandardCommand("serve", "Serve a web UI and live transcript feeds over HTTP")
+	cmd.Long = `Starts an HTTP server with a built-in web UI for browsing sessions, reading
+transcripts as they are written (follow, pause, and jump between errors with
+the toolbar or the f, p and e/E keys), and searching across transcripts —
+enough to review agent runs from a browser on a headless box. Open the
+printed URL.
+
+The UI is backed by a small JSON API that editors and other dashboards can
+use too:
+
+  GET /api/sessions?offset=<n>&limit=<n>
+      Sessions, newest first. X-Total-Count gives the number of sessions.
+
+  GET /api/sessions/<id>
+      One session's metadata.
+
+  GET /api/sessions/<id>/entries?after=<seq>&limit=<n>
+      A page of the session's normalized entries:
+      {"session","entries":[{"session","seq","entry"}],"total","next"}.
+      Pass next as after to fetch the following page; it is omitted on the
+      last one. Pages hold 200 entries by default and at most 1000.
+
+  GET /api/search?q=<text>&lang=<language>&sort=<order>&limit=<n>
+      Entries containing text, across sessions. lang keeps only entries
+      whose code is mostly in that language (go, python, ...); with lang,
+      q may be omitted. sort is relevance (the default) or time, as in
+      'aglogs search'; limit is at most 50.
+
+and two live feeds:
+
+  GET /api/sessions/<id>/events
+      Server-Sent Events for one session. Each "entry" event carries
+      {"session","seq","entry"} and has the entry's seq as its event id, so an
+      EventSource resumes through Last-Event-ID. Pass ?after=<seq> to skip
+      entries already seen. A "ready" event marks the end of the backfill.
+
+  GET /api/ws
+      WebSocket feed for any number of sessions. Subscribe in the URL with
+      ?session=<id> (or <id>:<seq> to resume after seq), or by sending
+      {"type":"subscribe","session":"<id>","after":<seq>} and
+      {"type":"unsubscribe","session":"<id>"}. The server sends
+      {"type":"entry","session","seq","entry"} messages, one
+      {"type":"ready","session","seq"} per subscription once the backfill
+      is done, and {"type":"error","session","error"} on failure.
+
+<id> is a session ID, an ID prefix or a plan/job; only sessions the scan
+finds are served, never a transcript named by its path.
+
+An entry can be re-sent with a seq the client already has when it gained
+content (for example a tool call whose result arrived); clients should
+replace entries by seq. To reconnect, resubscribe with the highest seq seen.
+
+Before exposing transcripts beyond your own machine, set --tokens-file (a
+file of "<name> <token>" lines) and --audit-log, or serve.tokens_file and
+serve.audit_log in the aglogs config. Every request then needs a token —
+"Authorization: Bearer <token>", or open the UI once with ?token=<token> to
+store it in a cookie — and each session listing, read, feed subscription,
+search and rejected request is appended to the audit log as a JSON line
+with the token's name, the client address and the session ID.
+
+Browsers may only open the WebSocket feed from the UI's own origin. Without
+tokens on a loopback address, requests must name a loopback host
+(localhost, 127.0.0.1), which keeps other sites out through DNS rebinding.
+
+When aglogs.redaction is configured, every entry and search snippet passes
+through it before it is sent; a failing redaction filter ends the feed
+instead of sending unredacted text.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		cfg := aglogs_config.Load().Serve
+		if !cmd.Flags().Changed("tokens-file") {
+			tokensFile = cfg.TokensFile
+		}
+		if !cmd.Flags().Changed("audit-log") {
+			auditLog = cfg.AuditLog
+		}
+
+		srv := &serve.Server{
+			PollInterval: pollInterval,
+			Sessions: func() ([]session.SessionInfo, error) {
+				sessions, err := session.NewScanner().Scan()
+				if err == nil {
+					rememberSessions(sessions)
+				}
+				return sessions, err
+			},
+		}
+		var err error
+		if srv.Redactor, err = loadRedactor(); err != nil {
+			return err
+		}
+		if tokensFile != "" {
+			if srv.Tokens, err = serve.LoadTokens(tokensFile); err != nil {
+				return fmt.Errorf("failed to load tokens: %w", err)
+			}
+		}
+		if auditLog != "" {
+			if srv.Audit, err = serve.OpenAuditLog(auditLog); err != nil {
+				return fmt.Errorf("failed to open audit log: %w", err)
+			}
+			defer srv.Audit.Close()
+		}
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		srv.Loopback = isLoopback(ln.Addr())
+		httpServer := &http.Server{
+			Handler:           srv.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		if srv.Tokens == nil && !isLoopback(ln.Addr()) {
+			ulogServe.Warn("Serving transcripts without authentication").
+				Field("addr", ln.Addr().String()).
+				Pretty(fmt.Sprintf("Warning: %s is reachable from other machines and no tokens are configured; anyone who can connect can read every transcript.", ln.Addr())).
+				Emit()
+		}
+		ulogServe.Info("Serving web UI and transcript feeds").
+			Field("addr", ln.Addr().String()).
+			Pretty(fmt.Sprintf("aglogs UI at http://%s/ (Ctrl-C to stop)", ln.Addr())).
+			Emit()
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- httpServer.Serve(ln) }()
+
+		select {
+		case err := <-errCh:
+			if !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		case <-ctx.Done():
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:7420", "Address to listen on")
+	cmd.Flags().DurationVar(&pollInterval, "poll", serve.DefaultPollInterval, "How often followed transcripts are checked for new entries")
+	cmd.Flags().StringVar(&tokensFile, "tokens-file", "", "Require a token from this file of '<name> <token>' lines (default: serve.tokens_file)")
+	cmd.Flags().StringVar(&auditLog, "audit-log", "", "Append a JSON record of every session read to this file (default: serve.audit_log)")
+
+	return cmd
+}
+
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
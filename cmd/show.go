@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/grovetools/core/pkg/daemon"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/display"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+	"github.com/grovetools/agentlogs/pkg/usage"
+)
+
+var ulogShow = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.show")
+
+func newShowCmd() *cobra.Command {
+	var jsonOutput, headerOnly, rawEscapes bool
+	var detailLevel, styleFlag string
+
+	cmd := cli.NewStandardCommand("show", "Show a session's metadata followed by its transcript")
+	cmd.Use = "show <spec>"
+	cmd.Long = `Prints a header describing the session — provider, models, project,
+branch, timing, token usage and cost, jobs and, for Codex, the shell, approval policy, sandbox mode and network
+access the agent ran with — followed by the full transcript.
+
+<spec> is anything 'aglogs read' accepts: a session ID, a plan/job, or a path
+to a job or log file. Archived jobs can be named directly, as
+artifact://<plan>/<job> or a path into the plan's .artifacts directory:
+
+  aglogs show artifact://my-plan/01-spec.md
+  aglogs show plans/my-plan/.artifacts/spec-a1b2
+
+These read only the archive, so they work where no agent ever ran (a CI
+runner with the repository checked out, say).`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		style, err := display.ParseRenderStyle(styleFlag)
+		if err != nil {
+			return err
+		}
+
+		var info *session.SessionInfo
+		if isLogFilePath(spec) {
+			info, err = resolveMetricsSession(spec)
+		} else {
+			info, err = session.ResolveSessionInfo(spec)
+		}
+		if err != nil {
+			return fmt.Errorf("could not resolve session for '%s': %w", spec, err)
+		}
+		header := loadSessionHeader(info)
+
+		if jsonOutput {
+			return printJSON(info)
+		}
+		if err := display.RenderSessionHeader(os.Stdout, header, style); err != nil {
+			return err
+		}
+		if headerOnly {
+			return nil
+		}
+
+		daemonClient := daemon.New()
+		defer daemonClient.Close()
+		entries, err := provider.SelectSource(info, daemonClient).Read(cmd.Context(), info, provider.ReadOptions{
+			DetailLevel: detailLevel,
+			EndLine:     -1,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read transcript: %w", err)
+		}
+		renderOpts := display.RenderOptions{Style: style, DetailLevel: detailLevel, RawEscapes: rawEscapes}
+		if err := display.RenderUnifiedTranscript(os.Stdout, entries, renderOpts, display.DefaultToolFormatters()); err != nil {
+			return err
+		}
+		if info.LogFilePath != "" && transcript.PendingLine(info.LogFilePath) {
+			printPendingNote(os.Stdout, style)
+		}
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the session metadata as JSON instead")
+	cmd.Flags().BoolVar(&headerOnly, "header", false, "Print only the session header")
+	cmd.Flags().StringVar(&detailLevel, "detail", "summary", "Transcript detail level: 'summary' or 'full'")
+	cmd.Flags().StringVar(&styleFlag, "style", "terminal", "Output style: 'terminal' or 'markdown'")
+	cmd.Flags().BoolVar(&rawEscapes, "raw-escapes", false, "Print escape sequences and control characters in transcript text as is instead of stripping them")
+
+	return cmd
+}
+
+// loadSessionHeader gathers the header facts that need a transcript read:
+// branch, models, and token usage. Sessions resolved without a scan (daemon,
+// direct file path) also get their Codex environment filled in here.
+func loadSessionHeader(info *session.SessionInfo) display.SessionHeader {
+	if info.Environment == nil && info.Provider == "codex" && info.LogFilePath != "" {
+		info.Environment = session.ReadCodexEnvironment(info.LogFilePath)
+	}
+	if info.GitBranch == "" && info.GitCommit == "" && info.LogFilePath != "" {
+		info.GitBranch, info.GitCommit = session.ReadGitInfo(info.LogFilePath, info.Provider)
+	}
+	h := display.SessionHeader{Info: *info, Branch: info.GitBranch}
+	if info.LogFilePath == "" {
+		return h
+	}
+	summary, err := usage.SummarizeSessionTranscript(info.LogFilePath, info.Provider, usage.CostModeCalculate)
+	if err != nil {
+		ulogShow.Debug("Could not summarize session usage").Err(err).Emit()
+		return h
+	}
+	h.Models = summary.Models
+	h.Tokens = summary.Usage.Total()
+	h.CostUSD = summary.CostUSD
+	h.MissingPricing = summary.MissingPricing
+	return h
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/split"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+var ulogSplit = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.split")
+
+func newSplitCmd() *cobra.Command {
+	var byFlag string
+	var outputDir string
+
+	cmd := cli.NewStandardCommand("split", "Split a session transcript into per-job or per-gap files")
+	cmd.Use = "split <spec>"
+	cmd.Long = `Materializes separate transcript files from one session, so a single slice
+of a long interactive session can be read, exported, or shared on its own.
+
+<spec> can be a plan/job, a session ID, or a direct path to a log file.
+
+Split modes:
+  --by job        One file per plan job, cut at the line where each job
+                  started. Lines before the first job go to a "preamble" file.
+  --by gap=30m    Start a new file wherever consecutive entries are more than
+                  the given duration apart.
+
+Each file is a raw line range of the original transcript, so it stays in the
+provider's native format and works with read, export, stats, and metrics.
+Codex and pi session headers are repeated at the top of every file.
+
+Files are written to ./<session-id>-split/ unless --output-dir is given.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		sessionInfo, err := resolveMetricsSession(args[0])
+		if err != nil {
+			return err
+		}
+		if sessionInfo.Provider == "opencode" {
+			return fmt.Errorf("split is not supported for opencode sessions; use 'aglogs export' instead")
+		}
+
+		lines, err := split.ReadLines(sessionInfo.LogFilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read transcript: %w", err)
+		}
+
+		var segs []split.Segment
+		switch {
+		case byFlag == "job":
+			segs, err = split.ByJobs(sessionInfo.Jobs, len(lines))
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(byFlag, "gap="):
+			gap, err := time.ParseDuration(strings.TrimPrefix(byFlag, "gap="))
+			if err != nil || gap <= 0 {
+				return fmt.Errorf("invalid gap %q: expected a positive duration like gap=30m", byFlag)
+			}
+			segs = split.ByGap(lines, gap)
+		default:
+			return fmt.Errorf("invalid --by value %q: expected 'job' or 'gap=<duration>'", byFlag)
+		}
+
+		base := strings.TrimSuffix(filepath.Base(sessionInfo.LogFilePath), transcript.CompressedExt)
+		prefix := strings.TrimSuffix(base, filepath.Ext(base))
+		if outputDir == "" {
+			name := sessionInfo.SessionID
+			if name == "" || name == "unknown" {
+				name = prefix
+			}
+			outputDir = name + "-split"
+		}
+		header := split.HeaderLine(lines, sessionInfo.Provider)
+
+		written, err := split.Write(outputDir, prefix, lines, segs, header)
+		if err != nil {
+			return err
+		}
+
+		for i, path := range written {
+			seg := segs[i]
+			ulogSplit.Info("Wrote split transcript").
+				Field("session_id", sessionInfo.SessionID).
+				Field("segment", seg.Name).
+				Field("start_line", seg.Start).
+				Field("end_line", seg.End).
+				Field("output", path).
+				Pretty(fmt.Sprintf("%s  (lines %d-%d, %s)", path, seg.Start+1, seg.End, seg.Name)).
+				Emit()
+		}
+		return nil
+	}
+
+	cmd.Flags().StringVar(&byFlag, "by", "job", "How to split: 'job' or 'gap=<duration>' (e.g. gap=30m)")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Directory to write split files to (default ./<session-id>-split)")
+
+	return cmd
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/pkg/stats"
+)
+
+func newStatsCmd() *cobra.Command {
+	var jsonOutput bool
+	var top, topOutliers int
+	var outlierKB int64
+	var idleThreshold time.Duration
+
+	cmd := cli.NewStandardCommand("stats", "Show tool usage statistics for a session")
+	cmd.Use = "stats <spec>"
+	cmd.Long = `Reports how a session spent its tool calls: calls, failures, and time per
+tool (Bash, Edit, Read, WebFetch, ...), the number of failed tool results,
+the files touched, and the longest-running shell commands. It also reports
+the bytes each tool's inputs and outputs added to the context, and lists the
+outlier calls of at least --outlier-kb (a 2 MiB file read, a noisy build),
+largest first. It also reports
+the session's wall-clock span and the idle gaps between messages (longer than
+--idle-threshold), so active working time can be told apart from waiting.
+
+<spec> can be a plan/job, a session ID, or a direct path to a log file.
+
+Durations run from the message that issued a tool call to the message that
+returned its result, so they include tool execution but not model thinking
+time. Providers that log results inline (OpenCode) have no durations.
+Subagent (sidechain) activity is excluded.`
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		sessionInfo, err := resolveMetricsSession(spec)
+		if err != nil {
+			return err
+		}
+		startLine, endLine, _ := jobLineRange(sessionInfo, spec, "")
+
+		src := provider.SelectSource(sessionInfo, nil)
+		entries, err := src.Read(cmd.Context(), sessionInfo, provider.ReadOptions{
+			DetailLevel: "full",
+			StartLine:   startLine,
+			EndLine:     endLine,
+		})
+		if err != nil {
+			return fmt.Errorf("error reading transcript: %w", err)
+		}
+
+		result := stats.Compute(entries, stats.Options{
+			TopCommands:   top,
+			IdleThreshold: idleThreshold,
+			OutlierBytes:  outlierKB * 1024,
+			TopOutliers:   topOutliers,
+		})
+		result.SessionID = sessionInfo.SessionID
+		if result.Provider == "" {
+			result.Provider = sessionInfo.Provider
+		}
+
+		if jsonOutput {
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal stats: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		printStats(os.Stdout, result)
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	cmd.Flags().IntVar(&top, "top", stats.DefaultTopCommands, "Number of longest-running commands to show")
+	cmd.Flags().DurationVar(&idleThreshold, "idle-threshold", stats.DefaultIdleThreshold, "Gap between messages counted as idle time")
+	cmd.Flags().Int64Var(&outlierKB, "outlier-kb", stats.DefaultOutlierBytes/1024, "Input plus output size (KiB) from which a tool call is listed as an outlier")
+	cmd.Flags().IntVar(&topOutliers, "top-outliers", stats.DefaultTopOutliers, "Number of outlier tool calls to show")
+
+	return cmd
+}
+
+func printStats(w io.Writer, r stats.Result) {
+	fmt.Fprintf(w, "Tool Statistics for Session: %s\n", r.SessionID)
+	fmt.Fprintf(w, "Provider: %s\n", r.Provider)
+	fmt.Fprintln(w, strings.Repeat("─", 50))
+	fmt.Fprintf(w, "Tool calls:       %d\n", r.ToolCalls)
+	fmt.Fprintf(w, "Failed results:   %d\n", r.FailedResults)
+	if r.FilesUnsupported {
+		fmt.Fprintln(w, "Files touched:    not measured")
+	} else {
+		fmt.Fprintf(w, "Files touched:    %d (%d edited)\n", len(r.FilesTouched), len(r.FilesEdited))
+	}
+	if len(r.Languages) > 0 {
+		langs := make([]string, len(r.Languages))
+		for i, l := range r.Languages {
+			langs[i] = fmt.Sprintf("%s %d", l.Language, l.Entries)
+		}
+		fmt.Fprintf(w, "Languages:        %s\n", strings.Join(langs, ", "))
+	}
+
+	if tm := r.Timing; !tm.StartedAt.IsZero() {
+		fmt.Fprintf(w, "Started:          %s\n", tm.StartedAt.Local().Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(w, "Ended:            %s\n", tm.EndedAt.Local().Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(w, "Wall clock:       %s\n", formatSeconds(tm.WallClockSeconds))
+		fmt.Fprintf(w, "Active:           %s\n", formatSeconds(tm.ActiveSeconds))
+		fmt.Fprintf(w, "Idle:             %s in %d gap(s) over %s\n",
+			formatSeconds(tm.IdleSeconds), len(tm.IdleGaps), formatSeconds(tm.IdleThresholdSeconds))
+	}
+
+	if len(r.Tools) > 0 {
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(tw, "TOOL\tCALLS\tFAILED\tTOTAL TIME\tAVG\tMAX\tIN\tOUT\tMAX OUT")
+		for _, t := range r.Tools {
+			total, avg, maxTime := "-", "-", "-"
+			if t.Timed > 0 {
+				total = formatSeconds(t.TotalSeconds)
+				avg = formatSeconds(t.TotalSeconds / float64(t.Timed))
+				maxTime = formatSeconds(t.MaxSeconds)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", t.Name, t.Calls, t.Failed, total, avg, maxTime,
+				formatBytes(t.InputBytes), formatBytes(t.OutputBytes), formatBytes(t.MaxOutputBytes))
+		}
+		tw.Flush()
+	}
+
+	if len(r.LongestRuns) > 0 {
+		fmt.Fprintln(w, "\nLongest-running commands:")
+		for _, run := range r.LongestRuns {
+			status := ""
+			if run.Failed {
+				status = " (failed)"
+			}
+			fmt.Fprintf(w, "  %8s  %s%s\n", formatSeconds(run.Seconds), truncateCommand(run.Command, 80), status)
+		}
+	}
+
+	if len(r.Outliers) > 0 {
+		fmt.Fprintln(w, "\nLargest tool calls:")
+		for _, c := range r.Outliers {
+			label := c.Tool
+			if c.Target != "" {
+				label += " " + truncateCommand(c.Target, 70)
+			}
+			fmt.Fprintf(w, "  %10s  %s (in %s, out %s)\n", formatBytes(c.Bytes()), label, formatBytes(c.InputBytes), formatBytes(c.OutputBytes))
+		}
+	}
+}
+
+// formatSeconds renders a duration compactly: 850ms, 42s, 3m12s, 1h05m.
+func formatSeconds(secs float64) string {
+	d := time.Duration(secs * float64(time.Second))
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.0fs", d.Seconds())
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// truncateCommand collapses a command to one line of at most n runes.
+func truncateCommand(cmd string, n int) string {
+	cmd = strings.Join(strings.Fields(cmd), " ")
+	if r := []rune(cmd); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return cmd
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	aglogs_config "github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/notify"
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/sessionstate"
+)
+
+var ulogStatus = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.status")
+
+// defaultStalledAfter is how long a session waits on its user before it
+// counts as stalled, unless notifications.stalled_after_minutes says
+// otherwise.
+const defaultStalledAfter = 10 * time.Minute
+
+// Session states reported by status.
+const (
+	stateActive  = "active"  // wrote to its transcript within the idle threshold
+	stateIdle    = "idle"    // quiet, but not waiting on its user
+	stateWaiting = "waiting" // waiting on its user, not yet for long
+	stateStalled = "stalled" // waiting on its user for longer than the threshold
+)
+
+// sessionStatus is one row of 'aglogs status'.
+type sessionStatus struct {
+	session.SessionInfo
+	State        string             `json:"state"`
+	LastActivity time.Time          `json:"lastActivity,omitzero"`
+	Wait         *sessionstate.Wait `json:"wait,omitempty"`
+}
+
+func newStatusCmd() *cobra.Command {
+	var stalledOnly, notifyFlag, jsonOutput bool
+	var idle, within time.Duration
+	var providerFlag string
+
+	cmd := cli.NewStandardCommand("status", "Show what recently active sessions are doing, and which are stalled")
+	cmd.Long = `Shows the sessions active within --within and what each is doing:
+
+  active   wrote to its transcript within --idle
+  idle     quiet, but not waiting on its user
+  waiting  its last message asks the user a question, or its last tool call
+           is awaiting approval (or still running)
+  stalled  waiting like that for longer than --idle
+
+--stalled lists only stalled sessions. With --notify, each stalled session
+is also posted to notifications.webhook_url; a stall is announced once, so
+'aglogs status --stalled --notify' can run from cron. Messages in the
+webhook payload pass through the configured redaction. 'aglogs watch
+--notify' reports sessions as they start waiting, on the desktop.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		cfg := aglogs_config.Load().Notifications
+		if !cmd.Flags().Changed("idle") && cfg.StalledAfterMinutes > 0 {
+			idle = time.Duration(cfg.StalledAfterMinutes) * time.Minute
+		}
+		if notifyFlag && cfg.WebhookURL == "" {
+			return fmt.Errorf("--notify needs notifications.webhook_url in the aglogs config")
+		}
+		var providers []string
+		if providerFlag != "" {
+			var err error
+			if providers, err = parseProviderFlag(providerFlag); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		sessions, err := session.NewScannerWithOptions(session.ScanOptions{Since: now.Add(-within)}).Scan()
+		if err != nil {
+			return fmt.Errorf("failed to scan for sessions: %w", err)
+		}
+		var statuses []sessionStatus
+		for i := range sessions {
+			info := &sessions[i]
+			if info.LogFilePath == "" || (len(providers) > 0 && !slices.Contains(providers, info.Provider)) {
+				continue
+			}
+			st := statusOf(cmd.Context(), info, now, idle)
+			if stalledOnly && st.State != stateStalled {
+				continue
+			}
+			statuses = append(statuses, st)
+		}
+		sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].LastActivity.After(statuses[j].LastActivity) })
+
+		if notifyFlag {
+			if err := notifyStalled(cmd.Context(), cfg.WebhookURL, statuses, now); err != nil {
+				return err
+			}
+		}
+		if jsonOutput {
+			if statuses == nil {
+				statuses = []sessionStatus{}
+			}
+			return printJSON(statuses)
+		}
+		if len(statuses) == 0 {
+			msg := fmt.Sprintf("No sessions active in the last %s.", within)
+			if stalledOnly {
+				msg = "No stalled sessions."
+			}
+			ulogStatus.Info("No sessions").Pretty(msg).PrettyOnly().Emit()
+			return nil
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(tw, "SESSION ID\tPROVIDER\tPROJECT\tJOB\tSTATE\tLAST ACTIVITY\tWAITING FOR")
+		for _, st := range statuses {
+			job, last, waiting := "", "-", ""
+			if len(st.Jobs) > 0 {
+				job = st.Jobs[len(st.Jobs)-1].Plan + "/" + st.Jobs[len(st.Jobs)-1].Job
+			}
+			if !st.LastActivity.IsZero() {
+				last = formatSeconds(now.Sub(st.LastActivity).Seconds()) + " ago"
+			}
+			if st.Wait != nil {
+				waiting = string(st.Wait.Reason) + ": " + truncateCommand(st.Wait.Message, 60)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", st.SessionID, st.Provider, st.ProjectName, job, st.State, last, waiting)
+		}
+		return tw.Flush()
+	}
+
+	cmd.Flags().BoolVar(&stalledOnly, "stalled", false, "Only list sessions stalled waiting on their user")
+	cmd.Flags().DurationVar(&idle, "idle", defaultStalledAfter, "How long a session may wait on its user before it counts as stalled (default from notifications.stalled_after_minutes)")
+	cmd.Flags().DurationVar(&within, "within", 24*time.Hour, "Only consider sessions active within this long")
+	cmd.Flags().BoolVar(&notifyFlag, "notify", false, "Post stalled sessions to notifications.webhook_url")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the statuses as JSON")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Only show sessions from these providers (comma-separated)")
+
+	return cmd
+}
+
+// statusOf reads a session's transcript and classifies it. An unreadable
+// transcript is reported by its last recorded activity alone.
+func statusOf(ctx context.Context, info *session.SessionInfo, now time.Time, idle time.Duration) sessionStatus {
+	st := sessionStatus{SessionInfo: *info, LastActivity: info.EndedAt}
+	entries, err := provider.SelectSource(info, nil).Read(ctx, info, provider.ReadOptions{DetailLevel: "full", EndLine: -1})
+	if err != nil {
+		ulogStatus.Debug("Failed to read transcript").Err(err).Field("session_id", info.SessionID).Emit()
+	}
+	if n := len(entries); n > 0 && entries[n-1].Timestamp.After(st.LastActivity) {
+		st.LastActivity = entries[n-1].Timestamp
+	}
+	st.Wait = sessionstate.Waiting(entries)
+	switch {
+	case st.Wait.Stalled(now, idle):
+		st.State = stateStalled
+	case st.Wait != nil:
+		st.State = stateWaiting
+	case !st.LastActivity.IsZero() && now.Sub(st.LastActivity) < idle:
+		st.State = stateActive
+	default:
+		st.State = stateIdle
+	}
+	return st
+}
+
+// notifyStalled posts each stalled session not announced before to the
+// webhook. Delivery failures are logged and retried on the next run.
+func notifyStalled(ctx context.Context, url string, statuses []sessionStatus, now time.Time) error {
+	redactor, err := loadRedactor()
+	if err != nil {
+		return err
+	}
+	hook := &notify.Webhook{URL: url}
+	ledger := notify.LoadLedger(notify.DefaultLedgerPath())
+	sent := 0
+	for _, st := range statuses {
+		if st.State != stateStalled {
+			continue
+		}
+		ev := notify.Event{
+			Event:     notify.EventStalled,
+			SessionID: st.SessionID,
+			Provider:  st.Provider,
+			Project:   st.ProjectName,
+			Reason:    string(st.Wait.Reason),
+			Since:     st.Wait.Since,
+			Message:   st.Wait.Message,
+		}
+		if ledger.Seen(ev) {
+			continue
+		}
+		if len(st.Jobs) > 0 {
+			ev.Job = st.Jobs[len(st.Jobs)-1].Plan + "/" + st.Jobs[len(st.Jobs)-1].Job
+		}
+		if redactor != nil {
+			if ev.Message, err = redactor.Redact(ctx, ev.Message); err != nil {
+				return err
+			}
+		}
+		where := ev.Project
+		if ev.Job != "" {
+			where = ev.Job
+		}
+		ev.Text = fmt.Sprintf("Session %s (%s) has waited %s for %s: %s",
+			st.SessionID, where, formatSeconds(now.Sub(ev.Since).Seconds()), ev.Reason, ev.Message)
+		if err := hook.Send(ctx, ev); err != nil {
+			ulogStatus.Warn("Failed to send notification").Err(err).Field("session_id", st.SessionID).
+				Pretty(fmt.Sprintf("Failed to notify about session %s: %v", st.SessionID, err)).Emit()
+			continue
+		}
+		ledger.Mark(ev, now)
+		sent++
+	}
+	if err := ledger.Save(now); err != nil {
+		ulogStatus.Warn("Failed to save notification ledger").Err(err).Emit()
+	}
+	if sent > 0 {
+		ulogStatus.Info("Sent stalled-session notifications").Field("sent", sent).
+			Pretty(fmt.Sprintf("Notified about %d stalled session(s)", sent)).PrettyOnly().Emit()
+	}
+	return nil
+}
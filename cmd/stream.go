@@ -14,21 +14,27 @@ import (
 
 	"github.com/grovetools/agentlogs/internal/provider"
 	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/internal/spool"
 	"github.com/grovetools/agentlogs/pkg/display"
 	"github.com/grovetools/agentlogs/pkg/formatters"
+	"github.com/grovetools/agentlogs/pkg/transcript"
 )
 
 // isLogFilePath returns true if the spec looks like a direct log file path
 // rather than a plan/job spec. This prevents plan markdown files from being
 // accidentally matched by os.Stat when the cwd happens to be the plans directory.
 func isLogFilePath(spec string) bool {
+	// Paths into plan artifacts resolve with their archive's metadata.
+	if session.IsArtifactSpec(spec) {
+		return false
+	}
 	// Absolute paths are always treated as file paths
 	if filepath.IsAbs(spec) {
 		_, err := os.Stat(spec)
 		return err == nil
 	}
 	// Relative paths must have a log-like extension to be treated as file paths
-	ext := filepath.Ext(spec)
+	ext := filepath.Ext(strings.TrimSuffix(spec, transcript.CompressedExt))
 	if ext == ".jsonl" || ext == ".log" {
 		_, err := os.Stat(spec)
 		return err == nil
@@ -42,12 +48,14 @@ func newStreamCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:    "stream <spec>",
 		Short:  "Stream logs for a specific job, session, or log file",
-		Long:   "Finds and tails the agent transcript log. <spec> can be a plan/job, a session ID, or a direct path to a log file.",
+		Long:   "Finds and tails the agent transcript log. <spec> can be a plan/job, a session ID, or a direct path to a log file. A compressed (.jsonl.gz) transcript is printed whole, since it never grows.",
 		Args:   cobra.ExactArgs(1),
 		Hidden: true, // Internal command for now
 		RunE: func(cmd *cobra.Command, args []string) error {
 			spec := args[0]
 			jsonOutput, _ := cmd.Flags().GetBool("json")
+			rawEscapes, _ := cmd.Flags().GetBool("raw-escapes")
+			bufferMB, _ := cmd.Flags().GetInt64("buffer-mb")
 
 			var sessionInfo *session.SessionInfo
 			var err error
@@ -57,10 +65,7 @@ func newStreamCmd() *cobra.Command {
 			// os.Stat if the cwd is the plans directory, so we require the path
 			// to look like a log file (absolute path, or .jsonl/.log extension).
 			if isLogFilePath(spec) {
-				prov := "claude"
-				if strings.Contains(spec, "/.codex/") {
-					prov = "codex"
-				}
+				prov := session.ProviderForPath(spec)
 				sessionInfo = &session.SessionInfo{
 					LogFilePath: spec,
 					Provider:    prov,
@@ -140,18 +145,39 @@ func newStreamCmd() *cobra.Command {
 				return fmt.Errorf("failed to stream transcript: %w", err)
 			}
 
-			jsonEncoder := json.NewEncoder(os.Stdout)
+			if jsonOutput {
+				return streamJSON(ch, bufferMB<<20)
+			}
 
+			renderOpts := display.RenderOptions{Style: display.StyleTerminal, DetailLevel: "full", RawEscapes: rawEscapes}
 			for entry := range ch {
-				if jsonOutput {
-					_ = jsonEncoder.Encode(entry)
-				} else {
-					display.DisplayUnifiedEntry(entry, "full", toolFormatters)
-				}
+				_ = display.RenderUnifiedEntry(os.Stdout, entry, renderOpts, toolFormatters)
 			}
 
 			return nil
 		},
 	}
+	cmd.Flags().Int64("buffer-mb", spool.DefaultMaxBytes>>20, "Disk space (MiB) for buffering --json output while the reader falls behind; lines past it are dropped")
+	cmd.Flags().Bool("raw-escapes", false, "Print escape sequences and control characters in transcript text as is instead of stripping them")
 	return cmd
 }
+
+// streamJSON writes entries to stdout as JSON lines. A reader that falls
+// behind gets the lines from a bounded disk buffer instead of stalling
+// the tail, and how many lines were buffered or dropped is reported on
+// stderr at exit.
+func streamJSON(ch <-chan transcript.UnifiedEntry, maxBufferBytes int64) error {
+	out := spool.New(os.Stdout, maxBufferBytes)
+	enc := json.NewEncoder(out)
+	for entry := range ch {
+		_ = enc.Encode(entry)
+	}
+	stats, err := out.Close()
+	if stats.Buffered > 0 || stats.Dropped > 0 {
+		fmt.Fprintf(os.Stderr, "stream: %d lines written, %d buffered to disk, %d dropped\n", stats.Written, stats.Buffered, stats.Dropped)
+	}
+	if err != nil {
+		return fmt.Errorf("writing stream output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/changes"
+	"github.com/grovetools/agentlogs/pkg/digest"
+	"github.com/grovetools/agentlogs/pkg/redact"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+var ulogSummarize = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.summarize")
+
+func newSummarizeCmd() *cobra.Command {
+	var jsonOutput, cached bool
+	var llmCommand string
+	var maxTokens int
+
+	cmd := cli.NewStandardCommand("summarize", "Summarize a session or job with an LLM: goal, decisions, files changed, open questions")
+	cmd.Use = "summarize <spec>"
+	cmd.Long = `Feeds a session's transcript to an LLM command and prints a structured
+summary of it: the goal, the key decisions, the files it changed and the
+questions it left open. The monitor summarizes live sessions as they go;
+this works on any session, on demand, whether or not that is enabled.
+
+<spec> can be a plan/job, a session ID, or a direct path to a log file. For
+a plan/job, just the job's part of the transcript is summarized.
+
+The LLM and the input's token budget come from the
+conversation_summarization section of ~/.config/tmux-claude-hud/config.yaml,
+as for the monitor's summaries. Its backend is "command" (the default) to
+run llm_command, which reads the prompt on stdin like 'llm -m gpt-4o-mini';
+"anthropic" or "openai" to call those APIs directly, with the key in
+ANTHROPIC_API_KEY or OPENAI_API_KEY (or the variable api_key_env names); or
+"ollama" for a local ollama server. model and endpoint override each
+backend's defaults. --llm runs the given command instead, and --max-tokens
+overrides max_input_tokens.
+
+The prompt holds
+the user's messages, the agent's replies and a line per tool call; when
+they don't fit, the first message and the latest ones are kept. The
+transcript passes through the configured redaction first. The files
+changed are read from the tool calls, not left to the LLM.
+
+Each summary is saved in aglogs' state directory (aglogs/digests);
+--cached prints the saved one instead of asking the LLM again.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.ValidArgsFunction = completeSessionSpecs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		info, err := resolveMetricsSession(spec)
+		if err != nil {
+			return err
+		}
+		startLine, endLine, _ := jobLineRange(info, spec, "")
+		job := specJob(info, spec)
+		key := digest.Key(info.SessionID, job.Job)
+
+		var d *digest.Digest
+		if cached {
+			if d, err = digest.Load(digest.Dir(), key); err != nil {
+				return err
+			}
+			if d == nil {
+				return fmt.Errorf("no saved summary of '%s'; run without --cached to make one", spec)
+			}
+		} else {
+			cfg := transcript.LoadSummaryConfig()
+			if llmCommand != "" {
+				cfg.Backend, cfg.LLMCommand = transcript.BackendCommand, llmCommand
+			}
+			if maxTokens <= 0 {
+				maxTokens = cfg.MaxInputTokens
+			}
+			summarizer, err := transcript.NewSummarizer(cfg)
+			if err != nil {
+				return err
+			}
+			entries, err := provider.SelectSource(info, nil).Read(cmd.Context(), info, provider.ReadOptions{
+				DetailLevel: "full",
+				StartLine:   startLine,
+				EndLine:     endLine,
+			})
+			if err != nil {
+				return fmt.Errorf("error reading transcript: %w", err)
+			}
+			if len(entries) == 0 {
+				return fmt.Errorf("'%s' has nothing to summarize", spec)
+			}
+			// The transcript leaves aglogs here.
+			redactor, err := loadRedactor()
+			if err != nil {
+				return err
+			}
+			if entries, err = redact.Entries(cmd.Context(), redactor, entries); err != nil {
+				return err
+			}
+			if d, err = summarizeEntries(cmd.Context(), summarizer, maxTokens, info, entries); err != nil {
+				return err
+			}
+			if job.Job != "" {
+				d.Job = job.Plan + "/" + job.Job
+			}
+			if err := digest.Save(digest.Dir(), key, *d); err != nil {
+				ulogSummarize.Warn("Could not save the summary").Err(err).Field("session_id", info.SessionID).Emit()
+			}
+		}
+
+		if jsonOutput {
+			return printJSON(d)
+		}
+		printDigest(os.Stdout, d)
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVar(&cached, "cached", false, "Print the saved summary instead of making a new one")
+	cmd.Flags().StringVar(&llmCommand, "llm", "", "Summarize with this LLM command, which reads the prompt on stdin, instead of the configured backend")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Token budget for the transcript in the prompt (default: the configured max_input_tokens)")
+
+	return cmd
+}
+
+// specJob returns the job of the session a plan/job spec names, or a zero
+// JobInfo for any other spec.
+func specJob(info *session.SessionInfo, spec string) session.JobInfo {
+	js, ok := session.ParseJobSpec(spec, "")
+	if !ok {
+		return session.JobInfo{}
+	}
+	for _, job := range info.Jobs {
+		if js.Matches(job) {
+			return job
+		}
+	}
+	return session.JobInfo{}
+}
+
+// summarizeEntries asks summarizer for a digest of entries.
+func summarizeEntries(ctx context.Context, summarizer transcript.Summarizer, maxTokens int, info *session.SessionInfo, entries []transcript.UnifiedEntry) (*digest.Digest, error) {
+	var files []string
+	for _, fc := range changes.Extract(entries, session.ReadWorkingDirectory(info.LogFilePath, info.Provider)) {
+		files = append(files, fc.Path)
+	}
+	prompt, omitted := digest.Prompt(entries, files, maxTokens)
+	out, err := summarizer.Summarize(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	d, err := digest.Parse(out)
+	if err != nil {
+		return nil, err
+	}
+	d.SessionID = info.SessionID
+	d.Provider = info.Provider
+	d.FilesChanged = files
+	if d.FilesChanged == nil {
+		d.FilesChanged = []string{}
+	}
+	d.Omitted = omitted
+	d.Summarizer = fmt.Sprint(summarizer)
+	d.CreatedAt = time.Now()
+	return &d, nil
+}
+
+func printDigest(w io.Writer, d *digest.Digest) {
+	if d.Job != "" {
+		fmt.Fprintf(w, "Summary of %s (session %s)\n", d.Job, d.SessionID)
+	} else {
+		fmt.Fprintf(w, "Summary of session %s\n", d.SessionID)
+	}
+	fmt.Fprintln(w, strings.Repeat("─", 50))
+	fmt.Fprintf(w, "Goal: %s\n", d.Goal)
+	list := func(title string, items []string) {
+		fmt.Fprintf(w, "\n%s:\n", title)
+		if len(items) == 0 {
+			fmt.Fprintln(w, "  (none)")
+		}
+		for _, item := range items {
+			fmt.Fprintf(w, "  • %s\n", item)
+		}
+	}
+	list("Key decisions", d.KeyDecisions)
+	list("Files changed", d.FilesChanged)
+	list("Open questions", d.OpenQuestions)
+	if d.Omitted > 0 {
+		fmt.Fprintf(w, "\n%d messages in the middle were left out to fit the token budget.\n", d.Omitted)
+	}
+}
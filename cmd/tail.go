@@ -5,27 +5,26 @@ import (
 
 	grovelogging "github.com/grovetools/core/logging"
 	"github.com/spf13/cobra"
-
-	"github.com/grovetools/agentlogs/pkg/transcript"
 )
 
 var ulogTail = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.tail")
 
 func newTailCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "tail <session_id>",
-		Short: "Tail and parse messages from a specific transcript",
-		Args:  cobra.ExactArgs(1),
+		Use:               "tail <session_id>",
+		Long:              "Shows the last messages of a session. The session ID may be abbreviated to any unique prefix of at least 4 characters.",
+		Short:             "Tail and parse messages from a specific transcript",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			sessionID := args[0]
 
-			transcriptPath, err := transcript.GetTranscriptPathLegacy(sessionID)
+			transcriptPath, provider, err := resolveTranscript(sessionID)
 			if err != nil {
-				return fmt.Errorf("failed to find transcript: %w", err)
+				return err
 			}
 
-			parser := transcript.NewParser()
-			messages, err := parser.ParseFile(transcriptPath)
+			messages, err := queryMessages(cmd.Context(), transcriptPath, provider)
 			if err != nil {
 				return fmt.Errorf("failed to parse transcript: %w", err)
 			}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeFlagLayouts are the absolute forms accepted by parseTimeFlag, tried in
+// order. Layouts without a zone are interpreted in local time.
+var timeFlagLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTimeFlag parses a --since/--until value: either a duration before now
+// ("24h", "90m", and the day/week shorthands "7d", "2w") or an absolute date
+// or timestamp ("2025-01-01", "2025-01-01T09:00", RFC 3339). Empty input
+// yields the zero time.
+func parseTimeFlag(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if n := len(value) - 1; n > 0 && (value[n] == 'd' || value[n] == 'w') {
+		if count, err := strconv.Atoi(value[:n]); err == nil && count >= 0 {
+			days := count
+			if value[n] == 'w' {
+				days *= 7
+			}
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range timeFlagLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a duration like 24h or 7d, or a date like 2025-01-01", value)
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeFlag(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"", time.Time{}},
+		{"24h", now.Add(-24 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"7d", time.Date(2026, 3, 3, 12, 0, 0, 0, time.Local)},
+		{"2w", time.Date(2026, 2, 24, 12, 0, 0, 0, time.Local)},
+		{"2025-01-01", time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)},
+		{"2025-01-01T09:30", time.Date(2025, 1, 1, 9, 30, 0, 0, time.Local)},
+		{"2025-01-01T09:30:00Z", time.Date(2025, 1, 1, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeFlag(tt.in, now)
+		if err != nil {
+			t.Errorf("parseTimeFlag(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseTimeFlag(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"yesterday", "2025-13-01", "d"} {
+		if _, err := parseTimeFlag(bad, now); err == nil {
+			t.Errorf("parseTimeFlag(%q) succeeded, want error", bad)
+		}
+	}
+}
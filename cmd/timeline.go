@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/pkg/stats"
+)
+
+func newTimelineCmd() *cobra.Command {
+	var jsonOutput bool
+	var calls bool
+	var bucket, idleThreshold time.Duration
+
+	cmd := cli.NewStandardCommand("timeline", "Show what a session was doing when")
+	cmd.Use = "timeline <spec>"
+	cmd.Long = `Renders a compact timeline of a session, for post-mortems of a job that
+took far longer than it should have. Each row covers one --bucket of time
+(a minute by default) and lists the prompts, replies and tool calls in it; a
+row whose only activity is a call still running from an earlier row (a long
+build or test run) shows that call, and a stretch with nothing going on is
+collapsed into one idle row.
+
+--calls prints one row per tool call instead, with how long it ran and what
+it ran on, along with the prompts and the idle gaps longer than
+--idle-threshold.
+
+<spec> can be a plan/job, a session ID, or a direct path to a log file.
+Subagent (sidechain) activity is excluded.`
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		sessionInfo, err := resolveMetricsSession(spec)
+		if err != nil {
+			return err
+		}
+		startLine, endLine, _ := jobLineRange(sessionInfo, spec, "")
+
+		src := provider.SelectSource(sessionInfo, nil)
+		entries, err := src.Read(cmd.Context(), sessionInfo, provider.ReadOptions{
+			DetailLevel: "full",
+			StartLine:   startLine,
+			EndLine:     endLine,
+		})
+		if err != nil {
+			return fmt.Errorf("error reading transcript: %w", err)
+		}
+
+		tl := stats.BuildTimeline(entries, stats.TimelineOptions{Bucket: bucket, IdleThreshold: idleThreshold})
+
+		if jsonOutput {
+			data, err := json.MarshalIndent(tl, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal timeline: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(tl.Events) == 0 {
+			fmt.Fprintf(os.Stdout, "No timestamped activity in session %s\n", sessionInfo.SessionID)
+			return nil
+		}
+		if calls {
+			printTimelineCalls(os.Stdout, tl)
+		} else {
+			printTimelineBuckets(os.Stdout, tl)
+		}
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the events and buckets as JSON")
+	cmd.Flags().BoolVar(&calls, "calls", false, "One row per tool call instead of per time bucket")
+	cmd.Flags().DurationVar(&bucket, "bucket", stats.DefaultBucket, "Time covered by each row")
+	cmd.Flags().DurationVar(&idleThreshold, "idle-threshold", stats.DefaultIdleThreshold, "Gap between messages shown as idle time")
+
+	return cmd
+}
+
+// printTimelineBuckets writes one row per bucket of the timeline.
+func printTimelineBuckets(w io.Writer, tl stats.Timeline) {
+	layout := "15:04"
+	if tl.BucketSeconds < 60 {
+		layout = "15:04:05"
+	}
+	for _, b := range tl.Buckets {
+		start := b.Start.Local().Format(layout)
+		if b.Idle {
+			end := b.End.Local().Format(layout)
+			fmt.Fprintf(w, "%s–%s  idle %s\n", start, end, formatSeconds(b.End.Sub(b.Start).Seconds()))
+			continue
+		}
+		var what []string
+		if b.Prompts > 0 {
+			what = append(what, countLabel(b.Prompts, "prompt", "prompts"))
+		}
+		if b.Replies > 0 {
+			what = append(what, countLabel(b.Replies, "reply", "replies"))
+		}
+		for _, tc := range b.Tools {
+			label := tc.Tool
+			if tc.Calls > 1 {
+				label += fmt.Sprintf("×%d", tc.Calls)
+			}
+			what = append(what, label)
+		}
+		line := strings.Join(what, ", ")
+		if b.Failed > 0 {
+			line += fmt.Sprintf(" (%d failed)", b.Failed)
+		}
+		if b.Running != "" {
+			if line != "" {
+				line += "  "
+			}
+			line += "… " + truncateCommand(b.Running, 60)
+		}
+		fmt.Fprintf(w, "%s  %s\n", start, line)
+	}
+}
+
+// printTimelineCalls writes one row per tool call, prompt and idle gap.
+func printTimelineCalls(w io.Writer, tl stats.Timeline) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, ev := range tl.Events {
+		at := ev.At.Local().Format("15:04:05")
+		switch ev.Kind {
+		case stats.EventPrompt:
+			fmt.Fprintf(tw, "%s\t\tprompt\t\n", at)
+		case stats.EventIdle:
+			fmt.Fprintf(tw, "%s\t%s\tidle\t\n", at, formatSeconds(ev.Seconds))
+		case stats.EventTool:
+			took := "-"
+			if ev.Seconds > 0 {
+				took = formatSeconds(ev.Seconds)
+			}
+			target := truncateCommand(ev.Target, 80)
+			if ev.Failed {
+				target += " (failed)"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", at, took, ev.Tool, target)
+		}
+	}
+	tw.Flush()
+}
+
+// countLabel is one for a single item ("prompt") and counts the plural
+// many otherwise ("3 prompts").
+func countLabel(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
@@ -49,15 +49,10 @@ showing both cumulative totals and the latest context window size.`
 
 		// Fast path: if spec is a file path, read it directly
 		if fileInfo, statErr := os.Stat(spec); statErr == nil && !fileInfo.IsDir() {
-			provider := "claude"
-			if strings.Contains(spec, "/.codex/") {
-				provider = "codex"
-			} else if strings.Contains(spec, "/opencode/storage/") {
-				// An opencode session info file
-				// (<storage>/session/<projectID>/<ses_*>.json); tokens are
-				// read through the fragment assembler.
-				provider = "opencode"
-			}
+			// An opencode spec is a session info file
+			// (<storage>/session/<projectID>/<ses_*>.json); its tokens are
+			// read through the fragment assembler.
+			provider := session.ProviderForPath(spec)
 
 			sessionID := "unknown"
 			if provider == "opencode" {
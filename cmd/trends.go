@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/index"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+// Trend periods.
+const (
+	trendDay   = "day"
+	trendWeek  = "week"
+	trendMonth = "month"
+)
+
+func newTrendsCmd() *cobra.Command {
+	var sinceFlag, by, project string
+	var jsonOutput bool
+
+	cmd := cli.NewStandardCommand("trends", "Show activity trends per project over days, weeks or months")
+	cmd.Long = `Prints the sessions, messages, plan jobs, failed tool calls, tokens and
+estimated cost per project and period since --since.
+
+The numbers come from daily per-project rollups kept in the session index
+rather than from the transcripts, so long windows stay fast and still cover
+sessions whose transcripts have since been pruned or cleaned up by their
+agent. Running the command first brings the rollups up to date with the
+transcripts on disk in the window; a session counts on the day it started.
+
+--by groups the days into weeks (starting Monday) or calendar months.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			grovelogging.SetGlobalOutput(os.Stderr)
+		}
+		switch by {
+		case trendDay, trendWeek, trendMonth:
+		default:
+			return fmt.Errorf("invalid --by %q: use day, week or month", by)
+		}
+		since, err := parseTimeFlag(sinceFlag, time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+
+		sessions, err := session.NewScannerWithOptions(session.ScanOptions{Since: since, Progress: newProgress("Scanning transcripts")}).ScanContext(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to scan for sessions: %w", err)
+		}
+		ix, err := refreshSessionIndex(cmd.Context(), filterTestSessions(sessions, false))
+		if err != nil {
+			return err
+		}
+
+		var rollups []index.Rollup
+		for _, r := range ix.Trend(since) {
+			if project == "" || r.Project == project {
+				rollups = append(rollups, r)
+			}
+		}
+		rows := groupTrends(rollups, by)
+		if jsonOutput {
+			return printJSON(rows)
+		}
+		printTrends(os.Stdout, rows)
+		return nil
+	}
+
+	cmd.Flags().StringVar(&sinceFlag, "since", "90d", "Report activity since this time: a duration (90d, 12w) or a date (2025-01-01)")
+	cmd.Flags().StringVar(&by, "by", trendDay, "Period to group by: day, week or month")
+	cmd.Flags().StringVar(&project, "project", "", "Only include this project")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the rows as JSON")
+	return cmd
+}
+
+// trendRow is the activity of one project in one period. Period is the
+// period's first day, or its month (2006-01) when grouping by month.
+type trendRow struct {
+	Period   string  `json:"period"`
+	Project  string  `json:"project"`
+	Sessions int     `json:"sessions"`
+	Messages int     `json:"messages"`
+	Jobs     int     `json:"jobs"`
+	Failures int     `json:"failures"`
+	Tokens   int64   `json:"tokens"`
+	CostUSD  float64 `json:"costUsd"`
+}
+
+func (row *trendRow) add(r trendRow) {
+	row.Sessions += r.Sessions
+	row.Messages += r.Messages
+	row.Jobs += r.Jobs
+	row.Failures += r.Failures
+	row.Tokens += r.Tokens
+	row.CostUSD += r.CostUSD
+}
+
+// groupTrends sums daily rollups into rows per period and project, oldest
+// period first.
+func groupTrends(rollups []index.Rollup, by string) []trendRow {
+	rows := []trendRow{}
+	byKey := make(map[string]int) // period/project -> index into rows
+	for _, r := range rollups {
+		period := trendPeriod(r.Day, by)
+		key := period + "/" + r.Project
+		i, ok := byKey[key]
+		if !ok {
+			i = len(rows)
+			byKey[key] = i
+			rows = append(rows, trendRow{Period: period, Project: r.Project})
+		}
+		rows[i].add(trendRow{Sessions: r.Sessions, Messages: r.Messages, Jobs: r.Jobs, Failures: r.Failures, Tokens: r.Tokens, CostUSD: r.CostUSD})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Period != rows[j].Period {
+			return rows[i].Period < rows[j].Period
+		}
+		return rows[i].Project < rows[j].Project
+	})
+	return rows
+}
+
+// trendPeriod maps a day (2006-01-02) to the period it falls in.
+func trendPeriod(day, by string) string {
+	t, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return day
+	}
+	switch by {
+	case trendWeek:
+		offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+		return t.AddDate(0, 0, -offset).Format("2006-01-02")
+	case trendMonth:
+		return t.Format("2006-01")
+	}
+	return day
+}
+
+func printTrends(w io.Writer, rows []trendRow) {
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No activity in this window.")
+		return
+	}
+	var total trendRow
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "PERIOD\tPROJECT\tSESSIONS\tMESSAGES\tJOBS\tFAILURES\tTOKENS\tCOST")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%s\t$%.2f\n", r.Period, r.Project, r.Sessions, r.Messages, r.Jobs, r.Failures, formatNumber(r.Tokens), r.CostUSD)
+		total.add(r)
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t%d\t%d\t%d\t%d\t%s\t$%.2f\n", total.Sessions, total.Messages, total.Jobs, total.Failures, formatNumber(total.Tokens), total.CostUSD)
+	tw.Flush()
+}
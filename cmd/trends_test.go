@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grovetools/agentlogs/internal/index"
+)
+
+func TestGroupTrends(t *testing.T) {
+	rollups := []index.Rollup{
+		{Day: "2026-01-04", Project: "api", Sessions: 1, Messages: 10, Tokens: 100}, // Sunday
+		{Day: "2026-01-05", Project: "api", Sessions: 2, Messages: 20, Jobs: 1, Tokens: 200},
+		{Day: "2026-01-05", Project: "web", Sessions: 1, Messages: 5, Failures: 2, CostUSD: 0.5},
+		{Day: "2026-01-11", Project: "api", Sessions: 1, Messages: 4},
+		{Day: "2026-02-01", Project: "api", Sessions: 1, Messages: 1},
+	}
+
+	tests := []struct {
+		by   string
+		want []trendRow
+	}{
+		{trendWeek, []trendRow{
+			{Period: "2025-12-29", Project: "api", Sessions: 1, Messages: 10, Tokens: 100},
+			{Period: "2026-01-05", Project: "api", Sessions: 3, Messages: 24, Jobs: 1, Tokens: 200},
+			{Period: "2026-01-05", Project: "web", Sessions: 1, Messages: 5, Failures: 2, CostUSD: 0.5},
+			{Period: "2026-01-26", Project: "api", Sessions: 1, Messages: 1},
+		}},
+		{trendMonth, []trendRow{
+			{Period: "2026-01", Project: "api", Sessions: 4, Messages: 34, Jobs: 1, Tokens: 300},
+			{Period: "2026-01", Project: "web", Sessions: 1, Messages: 5, Failures: 2, CostUSD: 0.5},
+			{Period: "2026-02", Project: "api", Sessions: 1, Messages: 1},
+		}},
+	}
+	for _, tt := range tests {
+		if got := groupTrends(rollups, tt.by); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("groupTrends(%s) =\n%+v\nwant\n%+v", tt.by, got, tt.want)
+		}
+	}
+	if got := groupTrends(rollups, trendDay); len(got) != len(rollups) {
+		t.Errorf("groupTrends(day) = %d rows, want %d", len(got), len(rollups))
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/grovetools/core/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/tui"
+)
+
+func newTuiCmd() *cobra.Command {
+	var detailLevel string
+
+	cmd := cli.NewStandardCommand("tui", "Browse sessions and transcripts interactively")
+	cmd.Long = `Opens a full-screen session browser. The left pane lists sessions (newest
+first), the right pane shows the selected transcript and follows it live while
+the session is still being written.
+
+Keys:
+  ↑/↓, j/k    Select a session (or scroll, when the transcript pane is focused)
+  tab         Switch between the session list and the transcript
+  /           Filter sessions; use project:<name> and plan:<name> to narrow,
+              bare words match project, plan, session ID, or provider
+  d           Toggle summary/full detail
+  s           Toggle Claude sub-agent transcripts nested under their Task calls
+  f           Toggle following the end of the transcript
+  g/G         Jump to top/bottom
+  r           Rescan sessions
+  q           Quit`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return tui.Run(detailLevel)
+	}
+
+	cmd.Flags().StringVar(&detailLevel, "detail", "summary", "Initial detail level: 'summary' or 'full'")
+
+	return cmd
+}
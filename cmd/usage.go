@@ -208,6 +208,8 @@ Claude only, regardless of --provider.`
 	cmd.Flags().Int64Var(&limit, "limit", 0, "Config-defined token denominator for the block projection (no live limits API)")
 	cmd.Flags().StringVar(&providerCSV, "provider", "all", "Providers to scan: all, or a comma list of claude,codex,opencode,pi")
 
+	cmd.AddCommand(newUsageExportCmd())
+
 	return cmd
 }
 
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/pkg/usage"
+)
+
+func newUsageExportCmd() *cobra.Command {
+	var (
+		format      string
+		groupBy     string
+		sinceDur    string
+		providerCSV string
+		outputPath  string
+	)
+
+	cmd := cli.NewStandardCommand("export", "Export a grouped usage rollup for central merging")
+	cmd.Use = "export [flags]"
+	cmd.Long = `Aggregates this machine's token usage and cost into one row per group and
+writes it as CSV (or JSON). Run it on each machine and concatenate the
+outputs centrally to get an org-wide view of agent adoption and spend.
+
+--group-by takes a comma list of: user, host, provider, project, model,
+day, week, month. "user" and "host" are the local login and hostname, since
+transcripts do not record who ran them. Time buckets are UTC; weeks are ISO
+weeks (e.g. 2025-W10).`
+	cmd.Args = cobra.NoArgs
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		providers, err := parseProviderFlag(providerCSV)
+		if err != nil {
+			return err
+		}
+		dims, err := usage.ParseRollupGroupBy(groupBy)
+		if err != nil {
+			return err
+		}
+		if format != "csv" && format != "json" {
+			return fmt.Errorf("unknown format %q (supported: csv, json)", format)
+		}
+
+		opts := usage.RollupOptions{GroupBy: dims, Mode: usage.CostModeCalculate}
+		if sinceDur != "" {
+			d, err := time.ParseDuration(sinceDur)
+			if err != nil {
+				return fmt.Errorf("invalid --since duration %q: %w", sinceDur, err)
+			}
+			opts.Since = time.Now().Add(-d)
+		}
+		if u, err := user.Current(); err == nil {
+			opts.User = u.Username
+		}
+		if h, err := os.Hostname(); err == nil {
+			opts.Host = h
+		}
+
+		rows, err := usage.RollupUsage(providers, opts)
+		if err != nil {
+			return fmt.Errorf("could not roll up usage: %w", err)
+		}
+
+		write := func(w io.Writer) error {
+			if format == "json" {
+				data, err := json.MarshalIndent(struct {
+					GroupBy []string          `json:"group_by"`
+					Rows    []usage.RollupRow `json:"rows"`
+				}{dims, rows}, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal: %w", err)
+				}
+				_, err = fmt.Fprintln(w, string(data))
+				return err
+			}
+			return usage.WriteRollupCSV(w, dims, rows)
+		}
+		if outputPath == "" || outputPath == "-" {
+			return write(os.Stdout)
+		}
+		return writeOutputFile(outputPath, write)
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "csv", "Output format: csv or json")
+	cmd.Flags().StringVar(&groupBy, "group-by", "user,project,week", "Comma list of dimensions to group by")
+	cmd.Flags().StringVar(&sinceDur, "since", "", "Only count entries newer than this duration (e.g. 720h)")
+	cmd.Flags().StringVar(&providerCSV, "provider", "all", "Providers to scan: all, or a comma list of claude,codex,opencode,pi")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write to this file instead of stdout")
+
+	return cmd
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grovetools/core/cli"
+	grovelogging "github.com/grovetools/core/logging"
+	"github.com/spf13/cobra"
+
+	"github.com/grovetools/agentlogs/internal/notify"
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/display"
+	"github.com/grovetools/agentlogs/pkg/redact"
+	"github.com/grovetools/agentlogs/pkg/sessionstate"
+)
+
+var ulogWatch = grovelogging.NewUnifiedLogger("grove-agent-logs.cmd.watch")
+
+func newWatchCmd() *cobra.Command {
+	var notifyFlag, dashboard bool
+	var interval, after, within time.Duration
+	var providerFlag string
+
+	cmd := cli.NewStandardCommand("watch", "Watch live sessions and report those awaiting input")
+	cmd.Long = `Polls the sessions active within --within and reports each one that starts
+waiting on its user:
+
+  question    its last message asks the user something
+  permission  its last tool call is awaiting approval (or still running)
+  turn_ended  the agent finished its turn (stop reason end_turn) and sits
+              at its prompt
+
+A session is reported once it has waited for --after, and once per wait.
+Sessions already waiting when watch starts are not reported.
+
+With --notify, each report is also shown as a desktop notification
+(notify-send on Linux, osascript on macOS). Messages pass through the
+configured redaction.
+
+With --dashboard, the watched sessions are redrawn as a table after every
+check, with the status of each (running, idle, awaiting_input, completed or
+errored; see 'aglogs list --help'). Stop watching with Ctrl-C.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		var providers []string
+		if providerFlag != "" {
+			var err error
+			if providers, err = parseProviderFlag(providerFlag); err != nil {
+				return err
+			}
+		}
+		var desktop *notify.Desktop
+		if notifyFlag {
+			var err error
+			if desktop, err = notify.NewDesktop(); err != nil {
+				return err
+			}
+		}
+		redactor, err := loadRedactor()
+		if err != nil {
+			return err
+		}
+
+		w := &watcher{
+			start:     time.Now(),
+			after:     after,
+			within:    within,
+			providers: providers,
+			desktop:   desktop,
+			redactor:  redactor,
+			dashboard: dashboard,
+			seen:      make(map[string]bool),
+			cache:     make(map[string]watchedFile),
+		}
+		ulogWatch.Info("Watching sessions").
+			Field("interval", interval.String()).
+			Field("notify", notifyFlag).
+			Pretty(fmt.Sprintf("Watching for sessions awaiting input (every %s, Ctrl-C to stop)...", interval)).
+			PrettyOnly().
+			Emit()
+
+		ctx := cmd.Context()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := w.check(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				ulogWatch.Warn("Watch check failed").Err(err).Emit()
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+
+	cmd.Flags().BoolVar(&notifyFlag, "notify", false, "Also show a desktop notification for each session awaiting input")
+	cmd.Flags().BoolVar(&dashboard, "dashboard", false, "Redraw a table of the watched sessions and their status after every check")
+	cmd.Flags().DurationVar(&interval, "interval", 10*time.Second, "How often to check the sessions")
+	cmd.Flags().DurationVar(&after, "after", 30*time.Second, "How long a session must wait before it is reported")
+	cmd.Flags().DurationVar(&within, "within", time.Hour, "Only watch sessions active within this long")
+	cmd.Flags().StringVar(&providerFlag, "provider", "", "Only watch sessions from these providers (comma-separated)")
+
+	return cmd
+}
+
+// watcher remembers what watch has seen between checks.
+type watcher struct {
+	start     time.Time
+	after     time.Duration
+	within    time.Duration
+	providers []string
+	desktop   *notify.Desktop
+	redactor  redact.Redactor
+	dashboard bool
+	// seen holds the keys of the waits already reported.
+	seen map[string]bool
+	// cache holds each transcript's tail as of its last modification, so
+	// unchanged transcripts are not re-read on every check.
+	cache map[string]watchedFile
+}
+
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	tail    sessionstate.Tail
+}
+
+// check scans the recently active sessions and reports each newly waiting
+// one.
+func (w *watcher) check(ctx context.Context, now time.Time) error {
+	sessions, err := session.NewScannerWithOptions(session.ScanOptions{Since: now.Add(-w.within)}).ScanContext(ctx)
+	if err != nil {
+		return err
+	}
+	var watched []session.SessionInfo
+	var reports []notify.Event
+	for i := range sessions {
+		info := &sessions[i]
+		if info.LogFilePath == "" || (len(w.providers) > 0 && !slices.Contains(w.providers, info.Provider)) {
+			continue
+		}
+		file, ok := w.tailOf(ctx, info)
+		if !ok {
+			continue
+		}
+		info.Status = sessionstate.Classify(file.tail, file.modTime, now)
+		watched = append(watched, *info)
+		wait := file.tail.Wait
+		if wait == nil || wait.Since.Before(w.start) || now.Sub(wait.Since) < w.after {
+			continue
+		}
+		ev := notify.Event{
+			Event:     notify.EventAwaitingInput,
+			SessionID: info.SessionID,
+			Provider:  info.Provider,
+			Project:   info.ProjectName,
+			Reason:    string(wait.Reason),
+			Since:     wait.Since,
+			Message:   wait.Message,
+		}
+		if w.seen[ev.Key()] {
+			continue
+		}
+		w.seen[ev.Key()] = true
+		if len(info.Jobs) > 0 {
+			ev.Job = info.Jobs[len(info.Jobs)-1].Plan + "/" + info.Jobs[len(info.Jobs)-1].Job
+		}
+		reports = append(reports, ev)
+	}
+
+	if w.dashboard {
+		w.drawDashboard(watched, now)
+	}
+	for _, ev := range reports {
+		if err := w.report(ctx, ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dashboardColumns are the table columns of watch --dashboard.
+var dashboardColumns = []string{"id", "provider", "project", "jobs", "status", "started", "duration"}
+
+// drawDashboard clears the terminal and prints the watched sessions, the
+// most recently active first.
+func (w *watcher) drawDashboard(sessions []session.SessionInfo, now time.Time) {
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].EndedAt.After(sessions[j].EndedAt) })
+	fmt.Fprint(os.Stdout, "\033[H\033[2J")
+	fmt.Fprintf(os.Stdout, "Sessions active in the last %s (as of %s)\n\n", w.within, now.Format("15:04:05"))
+	if len(sessions) == 0 {
+		fmt.Fprintln(os.Stdout, "No sessions.")
+		return
+	}
+	_ = display.PrintSessionsTableColumns(sessions, os.Stdout, dashboardColumns)
+	fmt.Fprintln(os.Stdout)
+}
+
+// tailOf returns what the end of a session's transcript says, re-reading it
+// only when it has changed since the last check.
+func (w *watcher) tailOf(ctx context.Context, info *session.SessionInfo) (watchedFile, bool) {
+	fi, err := os.Stat(info.LogFilePath)
+	if err != nil {
+		return watchedFile{}, false
+	}
+	if c, ok := w.cache[info.LogFilePath]; ok && c.modTime.Equal(fi.ModTime()) && c.size == fi.Size() {
+		return c, true
+	}
+	entries, err := provider.SelectSource(info, nil).Read(ctx, info, provider.ReadOptions{DetailLevel: "full", EndLine: -1})
+	if err != nil {
+		ulogWatch.Debug("Failed to read transcript").Err(err).Field("session_id", info.SessionID).Emit()
+		return watchedFile{}, false
+	}
+	file := watchedFile{modTime: fi.ModTime(), size: fi.Size(), tail: sessionstate.Inspect(entries)}
+	w.cache[info.LogFilePath] = file
+	return file, true
+}
+
+// report prints a session awaiting input, and shows it on the desktop with
+// --notify. A failed notification is logged, not fatal.
+func (w *watcher) report(ctx context.Context, ev notify.Event) error {
+	if w.redactor != nil {
+		var err error
+		if ev.Message, err = w.redactor.Redact(ctx, ev.Message); err != nil {
+			return err
+		}
+	}
+	where := ev.Project
+	if ev.Job != "" {
+		where = ev.Job
+	}
+	ev.Text = fmt.Sprintf("Session %s (%s) is awaiting input (%s)", ev.SessionID, where, ev.Reason)
+	if ev.Message != "" {
+		ev.Text += ": " + ev.Message
+	}
+	ulogWatch.Info("Session awaiting input").
+		Field("session_id", ev.SessionID).
+		Field("reason", ev.Reason).
+		Field("since", ev.Since).
+		Pretty(fmt.Sprintf("[%s] %s", ev.Since.Local().Format("15:04:05"), ev.Text)).
+		Emit()
+	if w.desktop != nil {
+		if err := w.desktop.Send(ctx, ev); err != nil {
+			ulogWatch.Warn("Failed to send notification").Err(err).Field("session_id", ev.SessionID).
+				Pretty(fmt.Sprintf("Failed to notify about session %s: %v", ev.SessionID, err)).Emit()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,467 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+// whereHelp documents --where expressions for the commands that take them.
+const whereHelp = `--where selects sessions with a filter expression: comparisons joined by
+AND, OR and NOT, with parentheses for grouping, e.g.
+  project=api AND started<30d
+  (provider=codex OR plan~auth) AND NOT test=true
+Fields: id, project, path, worktree, branch, ecosystem, provider, model,
+user, status, plan, job, tag, started, ended, duration, test and pinned.
+Text fields compare case-insensitively with = and !=, and ~ / !~ test for a
+substring; plan and job match when any of the session's jobs does, tag when
+any of its tags does. started and ended take <, <=, > and >= against a time
+written like --since: started<30d is more than 30 days ago,
+started>=2025-01-01 is on or after that date. duration compares against a
+length like 90m or 2h. Quote values with spaces or operator characters
+('my project').`
+
+// whereFields lists the fields a --where expression can compare, with the
+// kind of value each holds.
+var whereFields = map[string]whereKind{
+	"id":        whereText,
+	"project":   whereText,
+	"path":      whereText,
+	"worktree":  whereText,
+	"branch":    whereText,
+	"ecosystem": whereText,
+	"provider":  whereText,
+	"model":     whereText,
+	"user":      whereText,
+	"status":    whereText,
+	"plan":      whereText,
+	"job":       whereText,
+	"tag":       whereText,
+	"started":   whereTime,
+	"ended":     whereTime,
+	"duration":  whereDuration,
+	"test":      whereBool,
+	"pinned":    whereBool,
+}
+
+type whereKind int
+
+const (
+	whereText whereKind = iota
+	whereTime
+	whereDuration
+	whereBool
+)
+
+// whereExpr is a parsed --where expression.
+type whereExpr interface {
+	match(s session.SessionInfo) bool
+}
+
+type whereAnd []whereExpr
+
+func (e whereAnd) match(s session.SessionInfo) bool {
+	for _, sub := range e {
+		if !sub.match(s) {
+			return false
+		}
+	}
+	return true
+}
+
+type whereOr []whereExpr
+
+func (e whereOr) match(s session.SessionInfo) bool {
+	for _, sub := range e {
+		if sub.match(s) {
+			return true
+		}
+	}
+	return false
+}
+
+type whereNot struct{ expr whereExpr }
+
+func (e whereNot) match(s session.SessionInfo) bool { return !e.expr.match(s) }
+
+// whereCmp is one field comparison. Only the value matching the field's
+// kind is set.
+type whereCmp struct {
+	field string
+	op    string
+	text  string
+	time  time.Time
+	dur   time.Duration
+	b     bool
+}
+
+func (c whereCmp) match(s session.SessionInfo) bool {
+	switch whereFields[c.field] {
+	case whereTime:
+		t := s.StartedAt
+		if c.field == "ended" {
+			t = s.EndedAt
+		}
+		return !t.IsZero() && compareOrdered(t.Compare(c.time), c.op)
+	case whereDuration:
+		return compareOrdered(cmpDuration(s.Duration(), c.dur), c.op)
+	case whereBool:
+		v := s.Test
+		if c.field == "pinned" {
+			v = s.Pinned
+		}
+		return (v == c.b) == (c.op == "=")
+	}
+	values := whereTextValues(s, c.field)
+	matched := slices.ContainsFunc(values, func(v string) bool {
+		if c.op == "~" || c.op == "!~" {
+			return strings.Contains(strings.ToLower(v), strings.ToLower(c.text))
+		}
+		return strings.EqualFold(v, c.text)
+	})
+	return matched == (c.op == "=" || c.op == "~")
+}
+
+// whereTextValues returns the values of a text field; plan and job have one
+// per job of the session.
+func whereTextValues(s session.SessionInfo, field string) []string {
+	switch field {
+	case "id":
+		return []string{s.SessionID}
+	case "project":
+		return []string{s.ProjectName}
+	case "path":
+		return []string{s.ProjectPath}
+	case "worktree":
+		return []string{s.Worktree}
+	case "branch":
+		return []string{s.GitBranch}
+	case "ecosystem":
+		return []string{s.Ecosystem}
+	case "provider":
+		return []string{s.Provider}
+	case "model":
+		return []string{s.Model}
+	case "user":
+		return []string{s.User}
+	case "status":
+		return []string{s.Status}
+	case "tag":
+		return s.Tags
+	}
+	var values []string
+	for _, job := range s.Jobs {
+		if field == "plan" {
+			values = append(values, job.Plan)
+		} else {
+			values = append(values, job.Job)
+		}
+	}
+	return values
+}
+
+// compareOrdered applies an ordering operator to a three-way comparison.
+func compareOrdered(cmp int, op string) bool {
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "!=":
+		return cmp != 0
+	}
+	return cmp == 0
+}
+
+func cmpDuration(a, b time.Duration) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// parseWhere parses a --where expression. Relative times are resolved
+// against now.
+func parseWhere(expr string, now time.Time) (whereExpr, error) {
+	tokens, err := lexWhere(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+	p := &whereParser{tokens: tokens, now: now}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return e, nil
+}
+
+type whereTokenKind int
+
+const (
+	tokWord whereTokenKind = iota
+	tokOp
+	tokOpen
+	tokClose
+)
+
+type whereToken struct {
+	kind whereTokenKind
+	text string
+	// quoted is set for words written in quotes, which are never keywords.
+	quoted bool
+}
+
+// whereOps are the comparison operators, longest first so "<=" wins over
+// "<".
+var whereOps = []string{"!=", "!~", "<=", ">=", "=", "~", "<", ">"}
+
+func lexWhere(expr string) ([]whereToken, error) {
+	var tokens []whereToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, whereToken{kind: tokOpen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, whereToken{kind: tokClose, text: ")"})
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in filter expression")
+			}
+			tokens = append(tokens, whereToken{kind: tokWord, text: expr[i+1 : i+1+end], quoted: true})
+			i += end + 2
+		default:
+			if op := whereOpAt(expr[i:]); op != "" {
+				tokens = append(tokens, whereToken{kind: tokOp, text: op})
+				i += len(op)
+				continue
+			}
+			start := i
+			for i < len(expr) && !unicode.IsSpace(rune(expr[i])) && !strings.ContainsRune("()'\"", rune(expr[i])) && whereOpAt(expr[i:]) == "" {
+				i++
+			}
+			tokens = append(tokens, whereToken{kind: tokWord, text: expr[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+func whereOpAt(s string) string {
+	for _, op := range whereOps {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// whereParser is a recursive-descent parser over the grammar
+//
+//	or   = and { OR and }
+//	and  = not { AND not }
+//	not  = NOT not | "(" or ")" | field op value
+type whereParser struct {
+	tokens []whereToken
+	pos    int
+	now    time.Time
+}
+
+func (p *whereParser) peek() (whereToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return whereToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// keyword consumes the next token when it is the unquoted keyword kw.
+func (p *whereParser) keyword(kw string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == tokWord && !t.quoted && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *whereParser) or() (whereExpr, error) {
+	e, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	terms := whereOr{e}
+	for p.keyword("OR") {
+		if e, err = p.and(); err != nil {
+			return nil, err
+		}
+		terms = append(terms, e)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *whereParser) and() (whereExpr, error) {
+	e, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	terms := whereAnd{e}
+	for p.keyword("AND") {
+		if e, err = p.not(); err != nil {
+			return nil, err
+		}
+		terms = append(terms, e)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *whereParser) not() (whereExpr, error) {
+	if p.keyword("NOT") {
+		e, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return whereNot{e}, nil
+	}
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("filter expression ends early")
+	}
+	if t.kind == tokOpen {
+		p.pos++
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.kind != tokClose {
+			return nil, fmt.Errorf("missing ) in filter expression")
+		}
+		p.pos++
+		return e, nil
+	}
+	return p.comparison()
+}
+
+func (p *whereParser) comparison() (whereExpr, error) {
+	if p.pos+3 > len(p.tokens) {
+		return nil, fmt.Errorf("filter expression ends early")
+	}
+	field, op, value := p.tokens[p.pos], p.tokens[p.pos+1], p.tokens[p.pos+2]
+	if field.kind != tokWord {
+		return nil, fmt.Errorf("expected a field name, got %q", field.text)
+	}
+	if op.kind != tokOp {
+		return nil, fmt.Errorf("expected an operator after %q, got %q", field.text, op.text)
+	}
+	if value.kind != tokWord {
+		return nil, fmt.Errorf("expected a value after %s%s", field.text, op.text)
+	}
+	p.pos += 3
+
+	name := strings.ToLower(field.text)
+	kind, known := whereFields[name]
+	if !known {
+		names := make([]string, 0, len(whereFields))
+		for n := range whereFields {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("unknown field %q (known: %s)", field.text, strings.Join(names, ", "))
+	}
+	c := whereCmp{field: name, op: op.text}
+	ordered := op.text == "<" || op.text == "<=" || op.text == ">" || op.text == ">="
+	var err error
+	switch kind {
+	case whereText:
+		if ordered {
+			return nil, fmt.Errorf("%s compares with =, !=, ~ or !~", name)
+		}
+		c.text = value.text
+	case whereTime:
+		if !ordered {
+			return nil, fmt.Errorf("%s compares with <, <=, > or >=", name)
+		}
+		if c.time, err = parseTimeFlag(value.text, p.now); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	case whereDuration:
+		if op.text == "~" || op.text == "!~" {
+			return nil, fmt.Errorf("duration compares with =, !=, <, <=, > or >=")
+		}
+		if c.dur, err = parseWhereDuration(value.text); err != nil {
+			return nil, err
+		}
+	case whereBool:
+		if op.text != "=" && op.text != "!=" {
+			return nil, fmt.Errorf("%s compares with = or !=", name)
+		}
+		if c.b, err = strconv.ParseBool(value.text); err != nil {
+			return nil, fmt.Errorf("%s: invalid value %q: use true or false", name, value.text)
+		}
+	}
+	return c, nil
+}
+
+// parseWhereDuration parses a session length: a Go duration (90m, 1h30m)
+// or whole days (2d).
+func parseWhereDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("duration: invalid value %q: use a length like 90m or 2h", value)
+	}
+	return d, nil
+}
+
+// whereSessions scans every session, with test runs flagged, and returns
+// those matching the --where expression, newest first.
+func whereSessions(ctx context.Context, expr string) ([]session.SessionInfo, error) {
+	filter, err := parseWhere(expr, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("invalid --where: %w", err)
+	}
+	sessions, err := session.NewScannerWithOptions(session.ScanOptions{Progress: newProgress("Scanning transcripts")}).ScanContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for sessions: %w", err)
+	}
+	var matched []session.SessionInfo
+	for _, s := range filterTestSessions(sessions, true) {
+		if filter.match(s) {
+			matched = append(matched, s)
+		}
+	}
+	sortSessions(matched, "started", false, nil)
+	return matched, nil
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func TestParseWhere(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := session.SessionInfo{
+		SessionID:   "abc123",
+		ProjectName: "api",
+		Provider:    "claude",
+		Model:       "claude-sonnet-4-5-20250929",
+		GitBranch:   "feat/auth",
+		StartedAt:   now.AddDate(0, 0, -40),
+		EndedAt:     now.AddDate(0, 0, -40).Add(2 * time.Hour),
+		Jobs:        []session.JobInfo{{Plan: "auth-rework", Job: "01-spec.md"}},
+		Tags:        []string{"regression", "flaky"},
+		Pinned:      true,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"project=api", true},
+		{"project=API", true},
+		{"project!=api", false},
+		{"project~p", true},
+		{"project!~p", false},
+		{"project=api AND started<30d", true},
+		{"project=api AND started>30d", false},
+		{"started>=2026-01-01", true},
+		{"ended<2026-01-01", false},
+		{"provider=codex OR plan~auth", true},
+		{"(provider=codex OR plan~auth) AND NOT test=true", true},
+		{"not project=api", false},
+		{"job='01-spec.md'", true},
+		{"duration>1h", true},
+		{"duration<=90m", false},
+		{"test=false", true},
+		{"worktree=''", true},
+		{"tag=Regression", true},
+		{"tag!=flaky", false},
+		{"tag=auth", false},
+		{"model~sonnet", true},
+		{"model=gpt-5-codex", false},
+		{"branch~auth", true},
+		{"pinned=true", true},
+		{"pinned=true AND test=true", false},
+	}
+	for _, tt := range tests {
+		e, err := parseWhere(tt.expr, now)
+		if err != nil {
+			t.Errorf("parseWhere(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := e.match(s); got != tt.want {
+			t.Errorf("%q matches = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseWhereErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"outcome=pass",
+		"project<api",
+		"started=7d",
+		"started<soon",
+		"test=maybe",
+		"(project=api",
+		"project=api AND",
+		"project=api provider=codex",
+		"project='api",
+		"a OR project=api",
+	} {
+		if _, err := parseWhere(expr, time.Now()); err == nil {
+			t.Errorf("parseWhere(%q) = nil error", expr)
+		}
+	}
+}
@@ -30,6 +30,7 @@ func newWorkflowCmd() *cobra.Command {
 			}
 			jsonOutput, _ := cmd.Flags().GetBool("json")
 			styleFlag, _ := cmd.Flags().GetString("style")
+			rawEscapes, _ := cmd.Flags().GetBool("raw-escapes")
 			style, err := display.ParseRenderStyle(styleFlag)
 			if err != nil {
 				return err
@@ -43,7 +44,7 @@ func newWorkflowCmd() *cobra.Command {
 			jsonEncoder := json.NewEncoder(os.Stdout)
 			toolFormatters := display.DefaultToolFormatters()
 			agentStyle := lipgloss.NewStyle().Foreground(theme.DefaultColors.MutedText)
-			renderOpts := display.RenderOptions{Style: style, DetailLevel: "full"}
+			renderOpts := display.RenderOptions{Style: style, DetailLevel: "full", RawEscapes: rawEscapes}
 			lastAgent := ""
 
 			for entry := range ch {
@@ -68,6 +69,7 @@ func newWorkflowCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().String("style", "terminal", "Output style: 'terminal' (colors/icons) or 'markdown' (environment-independent)")
+	cmd.Flags().Bool("raw-escapes", false, "Print escape sequences and control characters in transcript text as is instead of stripping them")
 	return cmd
 }
 
@@ -2,6 +2,8 @@ package config
 
 //go:generate go run ../tools/schema-generator
 
+import core_config "github.com/grovetools/core/config"
+
 // TranscriptConfig defines settings for transcript viewing.
 type TranscriptConfig struct {
 	// DetailLevel controls the verbosity of transcript output.
@@ -15,7 +17,174 @@ type TranscriptConfig struct {
 	MaxDiffLines int `yaml:"max_diff_lines,omitempty" jsonschema:"description=Lines of diff to show before truncating (0=unlimited),default=0" jsonschema_extras:"x-layer=global,x-priority=61"`
 }
 
+// IssuesConfig defines how issue-tracker keys are detected in sessions.
+type IssuesConfig struct {
+	// Pattern is the regular expression used to find issue keys (Jira,
+	// Linear, ...) in user prompts and commit messages.
+	// Empty (default): DefaultIssuePattern, which matches keys like PROJ-123.
+	Pattern string `yaml:"pattern,omitempty" jsonschema:"description=Regular expression matching issue keys in prompts and commits (default matches keys like PROJ-123)" jsonschema_extras:"x-layer=global,x-priority=70"`
+}
+
+// JobsConfig defines how sessions are attributed to the flow plan jobs they
+// run. A structured job marker in the prompt always counts; these settings
+// govern the fallback of matching the prompt's wording.
+type JobsConfig struct {
+	// Patterns are regular expressions matched against user prompts. Each
+	// must capture the job file in a group named "path", or the plan and job
+	// names in groups named "plan" and "job".
+	// Empty (default): grove-flow's "Read the file <path> and execute the
+	// agent job" prompt.
+	Patterns []string `yaml:"patterns,omitempty" jsonschema:"description=Regular expressions finding the job a prompt starts; capture (?P<path>) or (?P<plan>) and (?P<job>)" jsonschema_extras:"x-layer=global,x-priority=75"`
+
+	// MarkersOnly attributes jobs by job markers (and the session registry)
+	// alone, ignoring prompt wording.
+	MarkersOnly bool `yaml:"markers_only,omitempty" jsonschema:"description=Attribute jobs only by structured job markers, never by prompt wording,default=false" jsonschema_extras:"x-layer=global,x-priority=76"`
+}
+
+// ServeConfig defines settings for 'aglogs serve'.
+type ServeConfig struct {
+	// TokensFile lists the access tokens for the server, one "<name> <token>"
+	// pair per line. When set, every request must present a token, and its
+	// name is recorded as the reader in the audit log.
+	// Empty (default): no authentication.
+	TokensFile string `yaml:"tokens_file,omitempty" jsonschema:"description=File of '<name> <token>' lines; when set the server requires a token" jsonschema_extras:"x-layer=global,x-priority=80"`
+
+	// AuditLog is the file each session read through the server is appended
+	// to, one JSON record per line.
+	// Empty (default): reads are not audited.
+	AuditLog string `yaml:"audit_log,omitempty" jsonschema:"description=File to append a JSON record of every session read to" jsonschema_extras:"x-layer=global,x-priority=81"`
+}
+
+// RedactionConfig defines how transcript text is redacted before it is
+// exported or served. Configured redactors run in the order listed here.
+type RedactionConfig struct {
+	// Patterns are regular expressions whose matches are replaced by
+	// "[REDACTED]".
+	Patterns []string `yaml:"patterns,omitempty" jsonschema:"description=Regular expressions whose matches are replaced by [REDACTED]" jsonschema_extras:"x-layer=global,x-priority=90"`
+
+	// Command is an external filter, as an argv list, run on each piece of
+	// text: text on stdin, redacted text on stdout, exit status 0.
+	Command []string `yaml:"command,omitempty" jsonschema:"description=Filter command (argv) that reads text on stdin and writes redacted text to stdout" jsonschema_extras:"x-layer=global,x-priority=91"`
+
+	// WASM is a WASI module that filters stdin to stdout like Command.
+	WASM string `yaml:"wasm,omitempty" jsonschema:"description=WASI module run as a stdin/stdout redaction filter" jsonschema_extras:"x-layer=global,x-priority=92"`
+
+	// WASMRuntime is the runtime CLI that runs WASM as "<runtime> run <module>".
+	// Empty (default): wasmtime.
+	WASMRuntime string `yaml:"wasm_runtime,omitempty" jsonschema:"description=Runtime used to run the WASM module (default wasmtime),default=wasmtime" jsonschema_extras:"x-layer=global,x-priority=93"`
+
+	// TimeoutSeconds bounds each run of Command or WASM.
+	// 0 (default): 10 seconds.
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty" jsonschema:"description=Seconds each filter run may take (default 10),default=10" jsonschema_extras:"x-layer=global,x-priority=94"`
+}
+
+// NotificationsConfig defines where session notifications are sent.
+type NotificationsConfig struct {
+	// WebhookURL receives a JSON POST for each notification, such as a
+	// session stalled waiting on its user ('aglogs status --stalled
+	// --notify'). The payload has a "text" field, so chat incoming webhooks
+	// work as is.
+	// Empty (default): no webhook.
+	WebhookURL string `yaml:"webhook_url,omitempty" jsonschema:"description=URL receiving a JSON POST per session notification" jsonschema_extras:"x-layer=global,x-priority=100"`
+
+	// StalledAfterMinutes is how long a session must wait on its user
+	// before it counts as stalled.
+	// 0 (default): 10 minutes.
+	StalledAfterMinutes int `yaml:"stalled_after_minutes,omitempty" jsonschema:"description=Minutes a session waits on its user before it counts as stalled (default 10),default=10" jsonschema_extras:"x-layer=global,x-priority=101"`
+}
+
+// ScheduleConfig defines the tasks 'aglogs schedule run', or a daemon
+// running the transcript monitor with its schedule set, runs on a
+// schedule, such as a daily overview or a weekly prune.
+type ScheduleConfig struct {
+	// OutputDir is the directory each task's output is written to, as
+	// <task>-<time>.txt (.json for tasks run with --json).
+	// Empty (default): output is not kept.
+	OutputDir string `yaml:"output_dir,omitempty" jsonschema:"description=Directory scheduled task output is written to" jsonschema_extras:"x-layer=global,x-priority=110"`
+
+	// WebhookURL receives a JSON POST with each task's output, in the
+	// payload of notifications.webhook_url.
+	// Empty (default): output is not posted.
+	WebhookURL string `yaml:"webhook_url,omitempty" jsonschema:"description=URL receiving a JSON POST with each scheduled task's output" jsonschema_extras:"x-layer=global,x-priority=111"`
+
+	// Tasks are the scheduled tasks.
+	Tasks []ScheduledTask `yaml:"tasks,omitempty" jsonschema:"description=Scheduled tasks" jsonschema_extras:"x-layer=global,x-priority=112"`
+}
+
+// ScheduledTask is an aglogs command run on a cron schedule.
+type ScheduledTask struct {
+	// Name identifies the task and names its output files.
+	Name string `yaml:"name" jsonschema:"description=Task name"`
+
+	// Schedule is a five-field cron expression (minute hour day month
+	// weekday) or @hourly, @daily, @weekly, @monthly.
+	Schedule string `yaml:"schedule" jsonschema:"description=Cron expression or @daily-style shorthand"`
+
+	// Command is the aglogs command line to run, without "aglogs", e.g.
+	// [overview, --since, 24h].
+	Command []string `yaml:"command" jsonschema:"description=aglogs arguments to run (without 'aglogs')"`
+
+	// OutputDir and WebhookURL override the section's for this task; "-"
+	// turns the section's off.
+	OutputDir  string `yaml:"output_dir,omitempty" jsonschema:"description=Output directory for this task (- for none)"`
+	WebhookURL string `yaml:"webhook_url,omitempty" jsonschema:"description=Webhook for this task (- for none)"`
+}
+
+// SourceConfig is a directory aglogs reads one provider's transcripts from,
+// for transcripts kept outside the provider's usual location: a custom
+// XDG_DATA_HOME, logs synced from other machines, a custom agent install.
+type SourceConfig struct {
+	// Provider is the agent that wrote the transcripts: claude, codex,
+	// opencode or pi.
+	Provider string `yaml:"provider" jsonschema:"description=Agent that wrote the transcripts,enum=claude,enum=codex,enum=opencode,enum=pi"`
+
+	// Path is laid out like the provider's own transcript root: Claude's
+	// projects directory, Codex's or pi's sessions directory, or OpenCode's
+	// storage directory. A leading ~ is the home directory.
+	// Empty: the provider's built-in location, e.g. ~/.claude/projects.
+	Path string `yaml:"path,omitempty" jsonschema:"description=Transcript root laid out like the provider's own (empty for its built-in location)"`
+
+	// Enabled set to false skips the source; on a source without a path it
+	// stops aglogs reading the provider's built-in location.
+	// Unset (default): enabled.
+	Enabled *bool `yaml:"enabled,omitempty" jsonschema:"description=Set to false to skip this source,default=true"`
+}
+
+// ArchiveConfig defines the remote archives job transcripts are kept in
+// beyond the plan directory, e.g. for compliance retention.
+type ArchiveConfig struct {
+	// Remotes are archives uploaded to with 'aglogs archive --dest':
+	// s3://bucket/prefix, gs://bucket/prefix or file:///dir. 'aglogs list
+	// --include-archived' lists their sessions, and reading one fetches its
+	// transcript.
+	// Empty (default): no remote archives.
+	Remotes []string `yaml:"remotes,omitempty" jsonschema:"description=Remote archives (s3://bucket/prefix or gs://bucket/prefix or file:///dir) listed by list --include-archived" jsonschema_extras:"x-layer=global,x-priority=130"`
+}
+
+// DefaultIssuePattern matches Jira/Linear style keys such as PROJ-123 or ENG-42.
+const DefaultIssuePattern = `\b[A-Z][A-Z0-9]+-[0-9]+\b`
+
 // Config is the top-level configuration structure for aglogs.
 type Config struct {
-	Transcript TranscriptConfig `yaml:"transcript,omitempty" jsonschema:"description=Transcript viewing settings" jsonschema_extras:"x-layer=global,x-priority=60"`
+	Transcript    TranscriptConfig    `yaml:"transcript,omitempty" jsonschema:"description=Transcript viewing settings" jsonschema_extras:"x-layer=global,x-priority=60"`
+	Issues        IssuesConfig        `yaml:"issues,omitempty" jsonschema:"description=Issue-tracker key detection settings" jsonschema_extras:"x-layer=global,x-priority=70"`
+	Jobs          JobsConfig          `yaml:"jobs,omitempty" jsonschema:"description=Flow job detection settings" jsonschema_extras:"x-layer=global,x-priority=75"`
+	Serve         ServeConfig         `yaml:"serve,omitempty" jsonschema:"description=Web UI and API server settings" jsonschema_extras:"x-layer=global,x-priority=80"`
+	Redaction     RedactionConfig     `yaml:"redaction,omitempty" jsonschema:"description=Redaction applied to exported and served transcripts" jsonschema_extras:"x-layer=global,x-priority=90"`
+	Notifications NotificationsConfig `yaml:"notifications,omitempty" jsonschema:"description=Session notification settings" jsonschema_extras:"x-layer=global,x-priority=100"`
+	Schedule      ScheduleConfig      `yaml:"schedule,omitempty" jsonschema:"description=Scheduled task settings" jsonschema_extras:"x-layer=global,x-priority=110"`
+	Sources       []SourceConfig      `yaml:"sources,omitempty" jsonschema:"description=Transcript directories read in addition to (or instead of) each provider's built-in one" jsonschema_extras:"x-layer=global,x-priority=120"`
+	Archive       ArchiveConfig       `yaml:"archive,omitempty" jsonschema:"description=Remote transcript archive settings" jsonschema_extras:"x-layer=global,x-priority=130"`
+}
+
+// Load reads the aglogs extension from the grove configuration. A missing or
+// unreadable config yields the zero Config so callers can fall back to defaults.
+func Load() Config {
+	var cfg Config
+	coreCfg, err := core_config.LoadDefault()
+	if err != nil || coreCfg == nil {
+		return cfg
+	}
+	_ = coreCfg.UnmarshalExtension("aglogs", &cfg)
+	return cfg
 }
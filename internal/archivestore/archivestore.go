@@ -0,0 +1,305 @@
+// Package archivestore keeps archived job transcripts outside the plan
+// directory: in Amazon S3 (s3://bucket/prefix), Google Cloud Storage
+// (gs://bucket/prefix) or a plain directory (file:///path), such as a
+// mounted share. S3 and GCS are reached through their CLIs, aws and gcloud,
+// so the credentials and profiles those are set up with apply unchanged.
+package archivestore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Store is a flat namespace of objects under one root, addressed by
+// slash-separated keys relative to it.
+type Store interface {
+	// URL is the store's root, as given to Open without a trailing slash.
+	URL() string
+	// Put uploads the local file to key, replacing any object there.
+	Put(ctx context.Context, key, localPath string) error
+	// Get downloads key to the local file, creating its directory.
+	Get(ctx context.Context, key, localPath string) error
+	// Delete removes key.
+	Delete(ctx context.Context, key string) error
+	// List returns the keys starting with prefix, sorted.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// schemes are the URL schemes Open accepts.
+var schemes = []string{"s3", "gs", "file"}
+
+// IsURL reports whether s names a store rather than a local path.
+func IsURL(s string) bool {
+	for _, scheme := range schemes {
+		if strings.HasPrefix(s, scheme+"://") {
+			return true
+		}
+	}
+	return false
+}
+
+// Open returns the store at rawURL: s3://bucket[/prefix],
+// gs://bucket[/prefix] or file:///dir.
+func Open(rawURL string) (Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive URL %q: %w", rawURL, err)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case "s3", "gs":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid archive URL %q: no bucket", rawURL)
+		}
+		root := u.Scheme + "://" + u.Host
+		if prefix != "" {
+			root += "/" + prefix
+		}
+		return &cliStore{root: root, scheme: u.Scheme, bucket: u.Host, prefix: prefix, run: runCommand}, nil
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, fmt.Errorf("invalid archive URL %q: file URLs name a local directory (file:///path)", rawURL)
+		}
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid archive URL %q: no directory", rawURL)
+		}
+		return &dirStore{dir: filepath.Clean(filepath.FromSlash(u.Path))}, nil
+	default:
+		return nil, fmt.Errorf("invalid archive URL %q: scheme must be one of %s", rawURL, strings.Join(schemes, ", "))
+	}
+}
+
+// cliStore is an S3 or GCS bucket prefix, reached through the aws or gcloud
+// CLI.
+type cliStore struct {
+	root   string
+	scheme string
+	bucket string
+	prefix string
+	// run executes the CLI and returns its stdout; tests replace it.
+	run func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+func (s *cliStore) URL() string { return s.root }
+
+func (s *cliStore) object(key string) string { return s.root + "/" + key }
+
+// cli returns the command and arguments running a storage subcommand.
+func (s *cliStore) cli(sub string, args ...string) (string, []string) {
+	if s.scheme == "s3" {
+		return "aws", append([]string{"s3", sub, "--only-show-errors"}, args...)
+	}
+	return "gcloud", append([]string{"storage", sub, "--no-user-output-enabled"}, args...)
+}
+
+func (s *cliStore) Put(ctx context.Context, key, localPath string) error {
+	name, args := s.cli("cp", localPath, s.object(key))
+	_, err := s.run(ctx, name, args...)
+	return err
+}
+
+func (s *cliStore) Get(ctx context.Context, key, localPath string) error {
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return err
+	}
+	// Download beside the target, so an interrupted copy is never taken
+	// for the object.
+	part := localPath + ".part"
+	name, args := s.cli("cp", s.object(key), part)
+	if _, err := s.run(ctx, name, args...); err != nil {
+		os.Remove(part)
+		return err
+	}
+	return os.Rename(part, localPath)
+}
+
+func (s *cliStore) Delete(ctx context.Context, key string) error {
+	name, args := s.cli("rm", s.object(key))
+	_, err := s.run(ctx, name, args...)
+	return err
+}
+
+func (s *cliStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	if s.scheme == "s3" {
+		out, err := s.run(ctx, "aws", "s3", "ls", "--recursive", s.object(prefix))
+		// aws exits 1, silently, when nothing matches.
+		if err != nil && !isSilentExit(err, 1) {
+			return nil, err
+		}
+		keys = parseS3List(out)
+	} else {
+		out, err := s.run(ctx, "gcloud", "storage", "ls", s.object(prefix)+"**")
+		if err != nil && !strings.Contains(err.Error(), "matched no objects") {
+			return nil, err
+		}
+		keys = parseGCSList(out)
+	}
+	// Listings are from the bucket root; keys are from the store's.
+	var rel []string
+	for _, k := range keys {
+		if s.scheme == "gs" {
+			k = strings.TrimPrefix(k, "gs://"+s.bucket+"/")
+		}
+		if s.prefix != "" {
+			var ok bool
+			if k, ok = strings.CutPrefix(k, s.prefix+"/"); !ok {
+				continue
+			}
+		}
+		if strings.HasPrefix(k, prefix) {
+			rel = append(rel, k)
+		}
+	}
+	sort.Strings(rel)
+	return rel, nil
+}
+
+// s3ListLine matches a line of 'aws s3 ls --recursive': date, time, size
+// and the key, which may contain spaces.
+var s3ListLine = regexp.MustCompile(`^\S+\s+\S+\s+\d+\s+(.+)$`)
+
+func parseS3List(out []byte) []string {
+	var keys []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if m := s3ListLine.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil {
+			keys = append(keys, m[1])
+		}
+	}
+	return keys
+}
+
+// parseGCSList reads 'gcloud storage ls' output: one object URL per line.
+func parseGCSList(out []byte) []string {
+	var keys []string
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "gs://") && !strings.HasSuffix(line, "/") {
+			keys = append(keys, line)
+		}
+	}
+	return keys
+}
+
+// commandError is a failed CLI run, with what it printed to stderr.
+type commandError struct {
+	name   string
+	err    error
+	stderr string
+}
+
+func (e *commandError) Error() string {
+	if e.stderr == "" {
+		return fmt.Sprintf("%s failed: %v", e.name, e.err)
+	}
+	return fmt.Sprintf("%s failed: %v: %s", e.name, e.err, e.stderr)
+}
+
+func (e *commandError) Unwrap() error { return e.err }
+
+// isSilentExit reports whether err is a run that exited with code and
+// printed nothing to stderr.
+func isSilentExit(err error, code int) bool {
+	var ce *commandError
+	var ee *exec.ExitError
+	return errors.As(err, &ce) && ce.stderr == "" && errors.As(err, &ee) && ee.ExitCode() == code
+}
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.CommandContext(ctx, name, args...)
+	c.Stdout, c.Stderr = &stdout, &stderr
+	if err := c.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("archives in object storage need the %s CLI: %w", name, err)
+		}
+		return stdout.Bytes(), &commandError{name: name, err: err, stderr: strings.TrimSpace(stderr.String())}
+	}
+	return stdout.Bytes(), nil
+}
+
+// dirStore is a local directory.
+type dirStore struct {
+	dir string
+}
+
+func (s *dirStore) URL() string { return "file://" + filepath.ToSlash(s.dir) }
+
+func (s *dirStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+func (s *dirStore) Put(_ context.Context, key, localPath string) error {
+	return copyFile(localPath, s.path(key))
+}
+
+func (s *dirStore) Get(_ context.Context, key, localPath string) error {
+	return copyFile(s.path(key), localPath)
+}
+
+func (s *dirStore) Delete(_ context.Context, key string) error {
+	return os.Remove(s.path(key))
+}
+
+func (s *dirStore) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == s.dir {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(p, ".part") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	sort.Strings(keys)
+	return keys, err
+}
+
+// copyFile copies src to dst through a temporary file beside dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	part := dst + ".part"
+	out, err := os.Create(part)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(part)
+		return err
+	}
+	return os.Rename(part, dst)
+}
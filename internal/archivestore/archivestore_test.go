@@ -0,0 +1,122 @@
+package archivestore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	for _, tt := range []struct{ url, want string }{
+		{"s3://bucket/logs/aglogs/", "s3://bucket/logs/aglogs"},
+		{"gs://bucket", "gs://bucket"},
+		{"file:///srv/archive/", "file:///srv/archive"},
+	} {
+		s, err := Open(tt.url)
+		if err != nil {
+			t.Errorf("Open(%q): %v", tt.url, err)
+			continue
+		}
+		if s.URL() != tt.want {
+			t.Errorf("Open(%q).URL() = %q, want %q", tt.url, s.URL(), tt.want)
+		}
+	}
+	for _, bad := range []string{"s3:///prefix", "http://example.com/x", "file://host/x", "/srv/archive"} {
+		if _, err := Open(bad); err == nil {
+			t.Errorf("Open(%q): want an error", bad)
+		}
+	}
+	if !IsURL("gs://b/p") || IsURL("/srv/archive") {
+		t.Error("IsURL misclassified")
+	}
+}
+
+func TestCLIStore(t *testing.T) {
+	ctx := context.Background()
+	var calls []string
+	fake := func(out string) func(context.Context, string, ...string) ([]byte, error) {
+		return func(_ context.Context, name string, args ...string) ([]byte, error) {
+			calls = append(calls, name+" "+strings.Join(args, " "))
+			return []byte(out), nil
+		}
+	}
+
+	s3, _ := Open("s3://bucket/team")
+	s3.(*cliStore).run = fake("2026-01-02 10:00:00       1234 team/s1/01-spec/metadata.json\n" +
+		"2026-01-02 10:00:00      99999 team/s1/01-spec/transcript.jsonl.gz\n" +
+		"2026-01-02 10:00:00         12 teammate/other.json\n")
+	keys, err := s3.List(ctx, "s1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(keys, " "); got != "s1/01-spec/metadata.json s1/01-spec/transcript.jsonl.gz" {
+		t.Errorf("s3 List = %s", got)
+	}
+	if err := s3.Put(ctx, "s1/01-spec/metadata.json", "/tmp/metadata.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	gs, _ := Open("gs://bucket")
+	gs.(*cliStore).run = fake("gs://bucket/s2/job/metadata.json\ngs://bucket/s2/job/transcript.jsonl\n")
+	keys, err = gs.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(keys, " "); got != "s2/job/metadata.json s2/job/transcript.jsonl" {
+		t.Errorf("gs List = %s", got)
+	}
+
+	want := []string{
+		"aws s3 ls --recursive s3://bucket/team/s1/",
+		"aws s3 cp --only-show-errors /tmp/metadata.json s3://bucket/team/s1/01-spec/metadata.json",
+		"gcloud storage ls gs://bucket/**",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands run:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDirStore(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	s, err := Open("file://" + filepath.ToSlash(filepath.Join(root, "archive")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys, err := s.List(ctx, ""); err != nil || len(keys) != 0 {
+		t.Fatalf("List of a missing directory = %v, %v; want nothing", keys, err)
+	}
+
+	src := filepath.Join(root, "transcript.jsonl")
+	if err := os.WriteFile(src, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"s1/job/transcript.jsonl", "s1/job/metadata.json", "s2/job/metadata.json"} {
+		if err := s.Put(ctx, key, src); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys, err := s.List(ctx, "s1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(keys, " "); got != "s1/job/metadata.json s1/job/transcript.jsonl" {
+		t.Errorf("List = %s", got)
+	}
+
+	dst := filepath.Join(root, "cache", "s1", "transcript.jsonl")
+	if err := s.Get(ctx, "s1/job/transcript.jsonl", dst); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "{}\n" {
+		t.Errorf("fetched %q", data)
+	}
+	if err := s.Delete(ctx, "s2/job/metadata.json"); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := s.List(ctx, "s2/"); len(keys) != 0 {
+		t.Errorf("after Delete, List = %v", keys)
+	}
+}
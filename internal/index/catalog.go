@@ -0,0 +1,102 @@
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/grovetools/core/pkg/paths"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+// CatalogMaxAge is how long a session catalog is trusted before callers
+// should rescan.
+const CatalogMaxAge = time.Hour
+
+// CatalogEntry is one known session, with just enough metadata to offer it
+// as a shell completion.
+type CatalogEntry struct {
+	SessionID string    `json:"sessionId"`
+	Provider  string    `json:"provider,omitempty"`
+	Project   string    `json:"project,omitempty"`
+	StartedAt time.Time `json:"startedAt,omitzero"`
+	Jobs      []string  `json:"jobs,omitempty"` // "plan/job.md"
+}
+
+// Catalog is a small cache of every session found by the last full scan.
+// Unlike the index it needs no transcript reads to build, so it is rewritten
+// whenever a command has scanned anyway, and read where a scan would be too
+// slow (shell completion).
+type Catalog struct {
+	UpdatedAt time.Time      `json:"updatedAt"`
+	Sessions  []CatalogEntry `json:"sessions"`
+}
+
+// CatalogPath returns the location of the shared session catalog.
+func CatalogPath() string {
+	return filepath.Join(paths.StateDir(), "aglogs", "catalog.json")
+}
+
+// NewCatalog builds a catalog from scanned sessions, newest first.
+func NewCatalog(sessions []session.SessionInfo, now time.Time) *Catalog {
+	c := &Catalog{UpdatedAt: now, Sessions: make([]CatalogEntry, 0, len(sessions))}
+	seen := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		if s.SessionID == "" || s.SessionID == "unknown" || seen[s.SessionID] {
+			continue
+		}
+		seen[s.SessionID] = true
+		entry := CatalogEntry{SessionID: s.SessionID, Provider: s.Provider, Project: s.ProjectName, StartedAt: s.StartedAt}
+		for _, job := range s.Jobs {
+			entry.Jobs = append(entry.Jobs, job.Plan+"/"+job.Job)
+		}
+		c.Sessions = append(c.Sessions, entry)
+	}
+	sort.SliceStable(c.Sessions, func(i, j int) bool {
+		return c.Sessions[i].StartedAt.After(c.Sessions[j].StartedAt)
+	})
+	return c
+}
+
+// Stale reports whether the catalog is older than CatalogMaxAge.
+func (c *Catalog) Stale(now time.Time) bool {
+	return now.Sub(c.UpdatedAt) > CatalogMaxAge
+}
+
+// LoadCatalog reads the catalog at path. A missing or unreadable catalog
+// yields nil and no error, since it can always be rebuilt.
+func LoadCatalog(path string) *Catalog {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil
+	}
+	return &c
+}
+
+// Save writes the catalog to path atomically.
+func (c *Catalog) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create catalog directory: %w", err)
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write catalog: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(fmt.Errorf("failed to replace catalog: %w", err), os.Remove(tmp))
+	}
+	return nil
+}
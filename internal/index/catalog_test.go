@@ -0,0 +1,52 @@
+package index
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func TestCatalogRoundTrip(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	sessions := []session.SessionInfo{
+		{SessionID: "old", Provider: "claude", StartedAt: now.Add(-2 * time.Hour)},
+		{SessionID: "unknown"},
+		{SessionID: "new", Provider: "codex", ProjectName: "web", StartedAt: now.Add(-time.Minute),
+			Jobs: []session.JobInfo{{Plan: "auth", Job: "01-login.md"}}},
+		{SessionID: "old", Provider: "claude", StartedAt: now.Add(-2 * time.Hour)},
+	}
+
+	c := NewCatalog(sessions, now)
+	var ids []string
+	for _, e := range c.Sessions {
+		ids = append(ids, e.SessionID)
+	}
+	if want := []string{"new", "old"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("session order = %v, want %v", ids, want)
+	}
+	if want := []string{"auth/01-login.md"}; !reflect.DeepEqual(c.Sessions[0].Jobs, want) {
+		t.Errorf("jobs = %v, want %v", c.Sessions[0].Jobs, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "aglogs", "catalog.json")
+	if LoadCatalog(path) != nil {
+		t.Fatal("missing catalog should load as nil")
+	}
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded := LoadCatalog(path)
+	if loaded == nil || !reflect.DeepEqual(loaded.Sessions, c.Sessions) {
+		t.Fatalf("round trip mismatch: %+v", loaded)
+	}
+
+	if loaded.Stale(now.Add(CatalogMaxAge / 2)) {
+		t.Error("fresh catalog reported stale")
+	}
+	if !loaded.Stale(now.Add(CatalogMaxAge + time.Second)) {
+		t.Error("old catalog not reported stale")
+	}
+}
@@ -0,0 +1,133 @@
+package index
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grovetools/core/logging"
+
+	"github.com/grovetools/agentlogs/internal/progress"
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/internal/tabular"
+	"github.com/grovetools/agentlogs/pkg/codelang"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// SessionsTable flattens sessions into one row each, joined with their
+// index records. ix may be nil, leaving the issues, tokens and languages
+// columns empty.
+func SessionsTable(sessions []session.SessionInfo, ix *Index) *tabular.Table {
+	t := &tabular.Table{Name: "sessions", Columns: []tabular.Column{
+		{Name: "session_id", Type: tabular.String},
+		{Name: "provider", Type: tabular.String},
+		{Name: "project", Type: tabular.String},
+		{Name: "project_path", Type: tabular.String},
+		{Name: "worktree", Type: tabular.String},
+		{Name: "ecosystem", Type: tabular.String},
+		{Name: "user", Type: tabular.String},
+		{Name: "started_at", Type: tabular.Time},
+		{Name: "ended_at", Type: tabular.Time},
+		{Name: "log_file_path", Type: tabular.String},
+		{Name: "jobs", Type: tabular.String},   // "plan/job.md", ";"-separated
+		{Name: "issues", Type: tabular.String}, // ";"-separated
+		{Name: "tokens", Type: tabular.Int},
+		{Name: "languages", Type: tabular.String}, // most entries first, ";"-separated
+	}}
+	for _, s := range sessions {
+		jobs := make([]string, len(s.Jobs))
+		for i, job := range s.Jobs {
+			jobs[i] = job.Plan + "/" + job.Job
+		}
+		var issues, tokens, languages any
+		if ix != nil {
+			if rec, ok := ix.Records[s.LogFilePath]; ok {
+				issues = strings.Join(rec.Issues, ";")
+				tokens = rec.Tokens
+				var langs []string
+				for _, b := range codelang.Sorted(rec.Languages) {
+					langs = append(langs, b.Language)
+				}
+				languages = strings.Join(langs, ";")
+			}
+		}
+		t.Append(s.SessionID, s.Provider, s.ProjectName, s.ProjectPath, s.Worktree, s.Ecosystem, s.User,
+			s.StartedAt, s.EndedAt, s.LogFilePath, strings.Join(jobs, ";"), issues, tokens, languages)
+	}
+	return t
+}
+
+// MessagesTable reads every session's transcript and flattens it into one
+// row per message. Sessions whose transcripts cannot be read are skipped
+// and logged, as in Refresh. bar, which may be nil, reports progress.
+func MessagesTable(ctx context.Context, sessions []session.SessionInfo, bar *progress.Bar) (*tabular.Table, error) {
+	logger := logging.NewLogger("aglogs-index")
+	t := &tabular.Table{Name: "messages", Columns: []tabular.Column{
+		{Name: "session_id", Type: tabular.String},
+		{Name: "seq", Type: tabular.Int},
+		{Name: "timestamp", Type: tabular.Time},
+		{Name: "role", Type: tabular.String},
+		{Name: "provider", Type: tabular.String},
+		{Name: "agent_id", Type: tabular.String},
+		{Name: "is_sidechain", Type: tabular.Bool},
+		{Name: "part_types", Type: tabular.String}, // ";"-separated
+		{Name: "text_chars", Type: tabular.Int},
+		{Name: "tool_calls", Type: tabular.Int},
+		{Name: "tool_names", Type: tabular.String}, // ";"-separated
+		{Name: "input_tokens", Type: tabular.Int},
+		{Name: "output_tokens", Type: tabular.Int},
+		{Name: "cache_read_tokens", Type: tabular.Int},
+		{Name: "cache_write_tokens", Type: tabular.Int},
+		{Name: "reasoning_tokens", Type: tabular.Int},
+		{Name: "language", Type: tabular.String}, // of the entry's code, see codelang.Entry
+	}}
+	bar.SetTotal(len(sessions))
+	defer bar.Finish()
+	for i := range sessions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		bar.Add(1)
+		info := &sessions[i]
+		if info.LogFilePath == "" {
+			continue
+		}
+		entries, err := provider.SelectSource(info, nil).Read(ctx, info, provider.ReadOptions{DetailLevel: "full", EndLine: -1})
+		if err != nil {
+			logger.WithError(err).WithField("path", info.LogFilePath).Debug("Skipping transcript during export")
+			continue
+		}
+		for seq, entry := range entries {
+			appendMessage(t, info, seq, entry)
+		}
+	}
+	return t, nil
+}
+
+func appendMessage(t *tabular.Table, info *session.SessionInfo, seq int, entry transcript.UnifiedEntry) {
+	var types, tools []string
+	textChars, toolCalls := 0, 0
+	for _, part := range entry.Parts {
+		types = append(types, part.Type)
+		switch part.Type {
+		case "text":
+			textChars += len(transcript.TextOf(part))
+		case "tool_call":
+			toolCalls++
+			if name := transcript.ToolCallOf(part).Name; name != "" {
+				tools = append(tools, name)
+			}
+		}
+	}
+	provider := entry.Provider
+	if provider == "" {
+		provider = info.Provider
+	}
+	tokens := []any{nil, nil, nil, nil, nil}
+	if tk := entry.Tokens; tk != nil {
+		tokens = []any{tk.Input, tk.Output, tk.CacheRead, tk.CacheWrite, tk.Reasoning}
+	}
+	row := append([]any{info.SessionID, seq, entry.Timestamp, entry.Role, provider, entry.AgentID,
+		entry.IsSidechain, strings.Join(types, ";"), textChars, toolCalls, strings.Join(tools, ";")}, tokens...)
+	t.Append(append(row, codelang.Entry(entry))...)
+}
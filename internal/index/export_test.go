@@ -0,0 +1,59 @@
+package index
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func TestExportTables(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "session.jsonl")
+	writeTranscript(t, logPath, "please handle PROJ-1")
+	sessions := []session.SessionInfo{{
+		SessionID:   "s1",
+		Provider:    "claude",
+		LogFilePath: logPath,
+		Jobs:        []session.JobInfo{{Plan: "feature", Job: "01-spec.md"}},
+	}}
+
+	opts, err := OptionsFromConfig(config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ix, err := Load(filepath.Join(dir, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.Refresh(context.Background(), sessions, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	st := SessionsTable(sessions, ix)
+	if len(st.Rows) != 1 {
+		t.Fatalf("sessions table has %d rows, want 1", len(st.Rows))
+	}
+	row := st.Rows[0]
+	if row[10] != "feature/01-spec.md" || row[11] != "PROJ-1" {
+		t.Errorf("sessions row = %v", row)
+	}
+
+	mt, err := MessagesTable(context.Background(), sessions, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mt.Rows) != 1 {
+		t.Fatalf("messages table has %d rows, want 1", len(mt.Rows))
+	}
+	msg := mt.Rows[0]
+	if msg[0] != "s1" || msg[3] != "user" || msg[8] != len("please handle PROJ-1") {
+		t.Errorf("messages row = %v", msg)
+	}
+	if ts, _ := msg[2].(time.Time); !ts.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("timestamp = %v", msg[2])
+	}
+}
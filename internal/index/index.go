@@ -0,0 +1,347 @@
+// Package index maintains a persistent cache of facts that can only be
+// derived by reading a whole transcript (for example, the issue keys a
+// session mentions). Records are keyed by transcript path and are considered
+// fresh only while the file's size and modification time are unchanged, so
+// growing or rewritten transcripts are re-read automatically.
+//
+// Alongside the records the index keeps daily per-project rollups (see
+// Rollup), which outlive the transcripts they were built from.
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grovetools/core/logging"
+	"github.com/grovetools/core/pkg/paths"
+
+	"github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/progress"
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/codelang"
+	"github.com/grovetools/agentlogs/pkg/sessionstate"
+	"github.com/grovetools/agentlogs/pkg/stats"
+	"github.com/grovetools/agentlogs/pkg/usage"
+)
+
+// indexVersion is bumped whenever Record changes shape; an index written by
+// a different version is discarded and rebuilt.
+const indexVersion = 6
+
+// Record holds the derived facts for one transcript file.
+type Record struct {
+	Path      string    `json:"path"`
+	SessionID string    `json:"sessionId,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"modTime"`
+	Issues    []string  `json:"issues,omitempty"`
+	// Tokens is the session's total token usage (input, output and cache),
+	// or 0 when the transcript records none.
+	Tokens int64 `json:"tokens,omitempty"`
+	// CostUSD is the session's estimated cost at list prices, or 0 when
+	// its models are unpriced.
+	CostUSD float64 `json:"costUsd,omitempty"`
+	// Languages counts the entries whose code is mostly in each language
+	// (see codelang.Entry).
+	Languages map[string]int `json:"languages,omitempty"`
+	// Tail is what the transcript's last entries say about the session
+	// (see Status).
+	Tail *sessionstate.Tail `json:"tail,omitempty"`
+	// Day is the local date the session started (2006-01-02) and Project
+	// the project it ran in; together they pick the session's rollup.
+	Day     string `json:"day,omitempty"`
+	Project string `json:"project,omitempty"`
+	// Messages counts the user and assistant entries of the main session.
+	Messages int `json:"messages,omitempty"`
+	// Jobs counts the plan jobs the session ran.
+	Jobs int `json:"jobs,omitempty"`
+	// Failures counts the tool calls that failed.
+	Failures int `json:"failures,omitempty"`
+}
+
+// Options controls how records are derived from transcripts.
+type Options struct {
+	// IssuePattern matches issue keys in prompts and commit commands.
+	IssuePattern *regexp.Regexp
+	// Progress, when set, reports progress through the sessions.
+	Progress *progress.Bar
+}
+
+// OptionsFromConfig compiles the index options from the aglogs config,
+// falling back to defaults for unset values.
+func OptionsFromConfig(cfg config.Config) (Options, error) {
+	pattern := cfg.Issues.Pattern
+	if pattern == "" {
+		pattern = config.DefaultIssuePattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Options{}, fmt.Errorf("invalid issues.pattern %q: %w", pattern, err)
+	}
+	return Options{IssuePattern: re}, nil
+}
+
+// Index is the on-disk session index.
+type Index struct {
+	Version      int                `json:"version"`
+	IssuePattern string             `json:"issuePattern,omitempty"`
+	Records      map[string]*Record `json:"records"`
+	Rollups      map[string]*Rollup `json:"rollups,omitempty"`
+
+	path  string
+	dirty bool
+}
+
+// DefaultPath returns the location of the shared index file.
+func DefaultPath() string {
+	return filepath.Join(paths.StateDir(), "aglogs", "index.json")
+}
+
+// Load reads the index at path. A missing, unreadable, or outdated index
+// yields an empty one rather than an error, since it can always be rebuilt.
+func Load(path string) (*Index, error) {
+	ix := &Index{Version: indexVersion, Records: make(map[string]*Record), Rollups: make(map[string]*Rollup), path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ix, nil
+		}
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var stored Index
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != indexVersion {
+		logging.NewLogger("aglogs-index").WithField("path", path).Debug("Discarding unreadable or outdated index")
+		ix.dirty = true
+		return ix, nil
+	}
+	if stored.Records != nil {
+		ix.Records = stored.Records
+	}
+	if stored.Rollups != nil {
+		ix.Rollups = stored.Rollups
+	}
+	ix.IssuePattern = stored.IssuePattern
+	return ix, nil
+}
+
+// Save writes the index back to disk if it changed since Load.
+func (ix *Index) Save() error {
+	if !ix.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+	// Write to a temp file and rename so concurrent readers never see a
+	// partially written index.
+	tmp := ix.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Rename(tmp, ix.path); err != nil {
+		return fmt.Errorf("failed to replace index: %w", err)
+	}
+	ix.dirty = false
+	return nil
+}
+
+// Lookup returns the record for a transcript path if it is still fresh.
+func (ix *Index) Lookup(path string) (*Record, bool) {
+	rec, ok := ix.Records[path]
+	if !ok {
+		return nil, false
+	}
+	st, err := os.Stat(path)
+	if err != nil || st.Size() != rec.Size || !st.ModTime().Equal(rec.ModTime) {
+		return nil, false
+	}
+	return rec, true
+}
+
+// Refresh makes sure every session with a transcript file has a fresh
+// record, re-reading only the transcripts that changed. Sessions whose
+// transcripts cannot be read are skipped and logged. Records of transcripts
+// that no longer exist are dropped; their rollups are kept.
+func (ix *Index) Refresh(ctx context.Context, sessions []session.SessionInfo, opts Options) error {
+	logger := logging.NewLogger("aglogs-index")
+
+	pattern := ""
+	if opts.IssuePattern != nil {
+		pattern = opts.IssuePattern.String()
+	}
+	if pattern != ix.IssuePattern {
+		// Issue keys were extracted with a different pattern; mark every
+		// record stale so it is re-read. Records are replaced rather than
+		// cleared so their rollups are not counted twice.
+		for _, rec := range ix.Records {
+			rec.ModTime = time.Time{}
+			rec.Issues = nil
+		}
+		ix.IssuePattern = pattern
+		ix.dirty = true
+	}
+
+	opts.Progress.SetTotal(len(sessions))
+	defer opts.Progress.Finish()
+	for i := range sessions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		opts.Progress.Add(1)
+		info := &sessions[i]
+		if info.LogFilePath == "" {
+			continue
+		}
+		if _, ok := ix.Lookup(info.LogFilePath); ok {
+			continue
+		}
+		rec, err := buildRecord(ctx, info, opts)
+		if err != nil {
+			logger.WithError(err).WithField("path", info.LogFilePath).Debug("Skipping transcript during indexing")
+			continue
+		}
+		if old, ok := ix.Records[rec.Path]; ok {
+			ix.addRollup(old, -1)
+		}
+		ix.Records[rec.Path] = rec
+		ix.addRollup(rec, 1)
+		ix.dirty = true
+	}
+
+	for path := range ix.Records {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			delete(ix.Records, path)
+			ix.dirty = true
+		}
+	}
+	return nil
+}
+
+// SessionsWithIssue returns the sessions whose record mentions issue key.
+// Keys are compared case-insensitively.
+func (ix *Index) SessionsWithIssue(sessions []session.SessionInfo, key string) []session.SessionInfo {
+	var matched []session.SessionInfo
+	for _, s := range sessions {
+		rec, ok := ix.Records[s.LogFilePath]
+		if !ok {
+			continue
+		}
+		for _, issue := range rec.Issues {
+			if strings.EqualFold(issue, key) {
+				matched = append(matched, s)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// SessionsWithLanguage returns the sessions with code in lang, a canonical
+// language name (see codelang.Normalize).
+func (ix *Index) SessionsWithLanguage(sessions []session.SessionInfo, lang string) []session.SessionInfo {
+	var matched []session.SessionInfo
+	for _, s := range sessions {
+		if rec, ok := ix.Records[s.LogFilePath]; ok && rec.Languages[lang] > 0 {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
+
+// Status classifies a session as of now (see sessionstate.Classify), or
+// returns "" when the session has no record.
+func (ix *Index) Status(s session.SessionInfo, now time.Time) string {
+	rec, ok := ix.Records[s.LogFilePath]
+	if !ok || rec.Tail == nil {
+		return ""
+	}
+	return sessionstate.Classify(*rec.Tail, rec.ModTime, now)
+}
+
+// Tokens returns the indexed token total for a session's transcript, or 0
+// when the session has no record.
+func (ix *Index) Tokens(s session.SessionInfo) int64 {
+	if rec, ok := ix.Records[s.LogFilePath]; ok {
+		return rec.Tokens
+	}
+	return 0
+}
+
+// Cost returns the indexed cost estimate for a session's transcript, or 0
+// when the session has no record.
+func (ix *Index) Cost(s session.SessionInfo) float64 {
+	if rec, ok := ix.Records[s.LogFilePath]; ok {
+		return rec.CostUSD
+	}
+	return 0
+}
+
+// Tail returns what the end of a session's transcript says, or nil when the
+// session has no record.
+func (ix *Index) Tail(s session.SessionInfo) *sessionstate.Tail {
+	if rec, ok := ix.Records[s.LogFilePath]; ok {
+		return rec.Tail
+	}
+	return nil
+}
+
+// buildRecord reads a transcript in full and derives its record.
+func buildRecord(ctx context.Context, info *session.SessionInfo, opts Options) (*Record, error) {
+	st, err := os.Stat(info.LogFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	source := provider.SelectSource(info, nil)
+	entries, err := source.Read(ctx, info, provider.ReadOptions{DetailLevel: "full", EndLine: -1})
+	if err != nil {
+		return nil, err
+	}
+
+	started := info.StartedAt
+	if started.IsZero() {
+		started = st.ModTime()
+	}
+	rec := &Record{
+		Path:      info.LogFilePath,
+		SessionID: info.SessionID,
+		Provider:  info.Provider,
+		Size:      st.Size(),
+		ModTime:   st.ModTime(),
+		Day:       started.Local().Format(dayLayout),
+		Project:   info.ProjectName,
+		Jobs:      len(info.Jobs),
+		Failures:  stats.Compute(entries, stats.Options{}).FailedResults,
+	}
+	for _, entry := range entries {
+		if !entry.IsSidechain && (entry.Role == "user" || entry.Role == "assistant") {
+			rec.Messages++
+		}
+	}
+	if opts.IssuePattern != nil {
+		rec.Issues = ExtractIssues(entries, opts.IssuePattern)
+	}
+	rec.Languages = codelang.Counts(entries)
+	tail := sessionstate.Inspect(entries)
+	rec.Tail = &tail
+	if summary, err := usage.SummarizeSessionTranscript(info.LogFilePath, info.Provider, usage.CostModeCalculate); err == nil {
+		rec.Tokens = summary.Usage.Total()
+		rec.CostUSD = summary.CostUSD
+	}
+	return rec, nil
+}
@@ -0,0 +1,178 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestExtractIssues(t *testing.T) {
+	re := regexp.MustCompile(config.DefaultIssuePattern)
+	entries := []transcript.UnifiedEntry{
+		{Role: "user", Parts: []transcript.UnifiedPart{
+			{Type: "text", Content: transcript.UnifiedTextContent{Text: "Fix the login bug from PROJ-123, see also ENG-7"}},
+		}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{
+			// Assistant prose is not a prompt and must not link issues.
+			{Type: "text", Content: transcript.UnifiedTextContent{Text: "Looking at OTHER-1 now"}},
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{
+				Name:  "Bash",
+				Input: map[string]interface{}{"command": `git commit -m "PROJ-123: fix login"`},
+			}},
+			// Non-commit shell commands are ignored.
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{
+				Name:  "Bash",
+				Input: map[string]interface{}{"command": "grep -r NOPE-9 ."},
+			}},
+		}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{
+			// Codex argv form after a JSON round-trip.
+			{Type: "tool_call", Content: map[string]interface{}{
+				"name":  "shell",
+				"input": map[string]interface{}{"command": []interface{}{"bash", "-lc", "git commit -am 'LIN-42 tidy'"}},
+			}},
+		}},
+	}
+
+	got := ExtractIssues(entries, re)
+	want := []string{"PROJ-123", "ENG-7", "LIN-42"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractIssues() = %v, want %v", got, want)
+	}
+}
+
+func TestOptionsFromConfigRejectsBadPattern(t *testing.T) {
+	cfg := config.Config{Issues: config.IssuesConfig{Pattern: "("}}
+	if _, err := OptionsFromConfig(cfg); err == nil {
+		t.Fatal("expected error for invalid pattern")
+	}
+}
+
+func TestRefreshCachesAndInvalidates(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "session.jsonl")
+	writeTranscript(t, logPath, "please handle PROJ-1")
+
+	opts, err := OptionsFromConfig(config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := []session.SessionInfo{{SessionID: "s1", Provider: "claude", LogFilePath: logPath}}
+	indexPath := filepath.Join(dir, "index.json")
+
+	ix, err := Load(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.Refresh(context.Background(), sessions, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := ix.SessionsWithIssue(sessions, "proj-1"); len(got) != 1 {
+		t.Fatalf("SessionsWithIssue(proj-1) = %d sessions, want 1", len(got))
+	}
+
+	// Reloading must serve the record from disk.
+	ix, err = Load(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ix.Lookup(logPath); !ok {
+		t.Fatal("expected fresh record after reload")
+	}
+
+	// Rewriting the transcript invalidates the record.
+	writeTranscript(t, logPath, "now working on PROJ-2 instead of the old ticket")
+	if _, ok := ix.Lookup(logPath); ok {
+		t.Fatal("expected stale record after transcript changed")
+	}
+	if err := ix.Refresh(context.Background(), sessions, opts); err != nil {
+		t.Fatal(err)
+	}
+	if got := ix.SessionsWithIssue(sessions, "PROJ-1"); len(got) != 0 {
+		t.Errorf("SessionsWithIssue(PROJ-1) = %d sessions after rewrite, want 0", len(got))
+	}
+	if got := ix.SessionsWithIssue(sessions, "PROJ-2"); len(got) != 1 {
+		t.Errorf("SessionsWithIssue(PROJ-2) = %d sessions after rewrite, want 1", len(got))
+	}
+}
+
+func TestRollupsOutlivePrunedTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "session.jsonl")
+	writeTranscript(t, logPath, "first prompt")
+
+	started := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	sessions := []session.SessionInfo{{
+		SessionID:   "s1",
+		Provider:    "claude",
+		LogFilePath: logPath,
+		ProjectName: "proj",
+		StartedAt:   started,
+		Jobs:        []session.JobInfo{{Plan: "p", Job: "j.md"}},
+	}}
+	indexPath := filepath.Join(dir, "index.json")
+	ix, err := Load(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh := func(sessions []session.SessionInfo) {
+		t.Helper()
+		if err := ix.Refresh(context.Background(), sessions, Options{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []Rollup{{Day: "2025-01-01", Project: "proj", Sessions: 1, Messages: 1, Jobs: 1}}
+
+	refresh(sessions)
+	if got := ix.Trend(time.Time{}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Trend = %+v, want %+v", got, want)
+	}
+
+	// Re-reading a changed transcript replaces its contribution.
+	writeTranscript(t, logPath, "a rewritten and longer first prompt")
+	refresh(sessions)
+	if got := ix.Trend(time.Time{}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Trend after rewrite = %+v, want %+v", got, want)
+	}
+
+	// Pruning the transcript drops its record but keeps the rollup, also
+	// across a save and reload.
+	if err := os.Remove(logPath); err != nil {
+		t.Fatal(err)
+	}
+	refresh(nil)
+	if len(ix.Records) != 0 {
+		t.Errorf("Records = %d after prune, want 0", len(ix.Records))
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if ix, err = Load(indexPath); err != nil {
+		t.Fatal(err)
+	}
+	if got := ix.Trend(time.Time{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Trend after prune = %+v, want %+v", got, want)
+	}
+	if got := ix.Trend(started.AddDate(0, 0, 1)); len(got) != 0 {
+		t.Errorf("Trend(next day) = %+v, want none", got)
+	}
+}
+
+func writeTranscript(t *testing.T, path, prompt string) {
+	t.Helper()
+	line := `{"type":"user","uuid":"u1","sessionId":"s1","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"` + prompt + `"}}` + "\n"
+	if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
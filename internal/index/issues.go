@@ -0,0 +1,43 @@
+package index
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/shellcmds"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// ExtractIssues returns the unique issue keys mentioned in a transcript.
+// Two places are searched: the text of user prompts, and shell tool calls
+// that run `git commit` (so keys that only appear in commit messages the
+// agent wrote are still linked). Keys are returned in first-seen order.
+func ExtractIssues(entries []transcript.UnifiedEntry, re *regexp.Regexp) []string {
+	seen := make(map[string]bool)
+	var issues []string
+	add := func(text string) {
+		for _, key := range re.FindAllString(text, -1) {
+			if !seen[key] {
+				seen[key] = true
+				issues = append(issues, key)
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		for _, part := range entry.Parts {
+			switch part.Type {
+			case "text":
+				if entry.Role == "user" {
+					add(transcript.TextOf(part))
+				}
+			case "tool_call":
+				call := transcript.ToolCallOf(part)
+				if cmd := shellcmds.Script(call.Name, call.Input); strings.Contains(cmd, "git commit") {
+					add(cmd)
+				}
+			}
+		}
+	}
+	return issues
+}
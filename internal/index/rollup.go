@@ -0,0 +1,70 @@
+package index
+
+import (
+	"sort"
+	"time"
+)
+
+// dayLayout is the format of Record.Day and Rollup.Day.
+const dayLayout = "2006-01-02"
+
+// Rollup is the activity of one project on one day: the sum over the
+// sessions that started that day. Rollups are updated as records are added
+// and replaced, and outlive the records themselves, so multi-month trends
+// stay cheap to report and still cover transcripts that have since been
+// pruned from disk.
+type Rollup struct {
+	Day      string  `json:"day"` // local date, 2006-01-02
+	Project  string  `json:"project"`
+	Sessions int     `json:"sessions"`
+	Messages int     `json:"messages"`
+	Jobs     int     `json:"jobs"`
+	Failures int     `json:"failures"`
+	Tokens   int64   `json:"tokens"`
+	CostUSD  float64 `json:"costUsd"`
+}
+
+// addRollup adds a record's activity to its rollup (sign 1) or takes it
+// back out (sign -1). A rollup left without sessions is removed.
+func (ix *Index) addRollup(rec *Record, sign int) {
+	if rec.Day == "" {
+		return
+	}
+	key := rec.Day + "/" + rec.Project
+	r := ix.Rollups[key]
+	if r == nil {
+		r = &Rollup{Day: rec.Day, Project: rec.Project}
+		ix.Rollups[key] = r
+	}
+	r.Sessions += sign
+	r.Messages += sign * rec.Messages
+	r.Jobs += sign * rec.Jobs
+	r.Failures += sign * rec.Failures
+	r.Tokens += int64(sign) * rec.Tokens
+	r.CostUSD += float64(sign) * rec.CostUSD
+	if r.Sessions <= 0 {
+		delete(ix.Rollups, key)
+	}
+}
+
+// Trend returns the rollups for the days on or after since (all of them
+// when since is zero), by day and then project.
+func (ix *Index) Trend(since time.Time) []Rollup {
+	first := ""
+	if !since.IsZero() {
+		first = since.Local().Format(dayLayout)
+	}
+	var out []Rollup
+	for _, r := range ix.Rollups {
+		if r.Day >= first {
+			out = append(out, *r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Day != out[j].Day {
+			return out[i].Day < out[j].Day
+		}
+		return out[i].Project < out[j].Project
+	})
+	return out
+}
@@ -0,0 +1,96 @@
+package meta
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+// HasTag reports whether the session carries tag, compared
+// case-insensitively.
+func (m *SessionMeta) HasTag(tag string) bool {
+	if m == nil {
+		return false
+	}
+	return slices.ContainsFunc(m.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// AddTags tags a session, reporting how many of the tags are new. Tags are
+// trimmed; empty ones are skipped.
+func (s *Store) AddTags(sessionID string, tags []string) int {
+	m := s.entry(sessionID)
+	added := 0
+	for _, t := range tags {
+		if t = strings.TrimSpace(t); t != "" && !m.HasTag(t) {
+			m.Tags = append(m.Tags, t)
+			added++
+		}
+	}
+	slices.Sort(m.Tags)
+	s.prune(sessionID)
+	return added
+}
+
+// RemoveTags removes tags from a session, reporting how many it had.
+func (s *Store) RemoveTags(sessionID string, tags []string) int {
+	m := s.Sessions[sessionID]
+	if m == nil {
+		return 0
+	}
+	before := len(m.Tags)
+	m.Tags = slices.DeleteFunc(m.Tags, func(t string) bool {
+		return slices.ContainsFunc(tags, func(r string) bool { return strings.EqualFold(t, strings.TrimSpace(r)) })
+	})
+	if len(m.Tags) == 0 {
+		m.Tags = nil
+	}
+	removed := before - len(m.Tags)
+	s.prune(sessionID)
+	return removed
+}
+
+// AddNote appends a note to a session.
+func (s *Store) AddNote(sessionID, text string, now time.Time) {
+	m := s.entry(sessionID)
+	m.Notes = append(m.Notes, session.Note{Text: text, CreatedAt: now.UTC()})
+}
+
+// ClearNotes deletes a session's notes, reporting how many it had.
+func (s *Store) ClearNotes(sessionID string) int {
+	m := s.Sessions[sessionID]
+	if m == nil {
+		return 0
+	}
+	n := len(m.Notes)
+	m.Notes = nil
+	s.prune(sessionID)
+	return n
+}
+
+// mergeAnnotations takes the tags and notes of o that m lacks. Both only
+// ever grow on import: there is nothing to overwrite.
+func (m *SessionMeta) mergeAnnotations(o *SessionMeta) bool {
+	changed := false
+	for _, t := range o.Tags {
+		if !m.HasTag(t) {
+			m.Tags = append(m.Tags, t)
+			changed = true
+		}
+	}
+	if changed {
+		slices.Sort(m.Tags)
+	}
+	notesChanged := false
+	for _, n := range o.Notes {
+		if !slices.ContainsFunc(m.Notes, func(have session.Note) bool { return have.Text == n.Text && have.CreatedAt.Equal(n.CreatedAt) }) {
+			m.Notes = append(m.Notes, n)
+			notesChanged = true
+		}
+	}
+	if notesChanged {
+		slices.SortStableFunc(m.Notes, func(a, b session.Note) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	}
+	return changed || notesChanged
+}
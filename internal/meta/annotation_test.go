@@ -0,0 +1,63 @@
+package meta
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func TestAnnotations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meta.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	if n := s.AddTags("a", []string{"regression", " flaky ", "", "Regression"}); n != 2 {
+		t.Errorf("AddTags = %d, want 2", n)
+	}
+	s.AddNote("a", "this run introduced the regression", at)
+	if n := s.AddTags("b", []string{" "}); n != 0 || s.Get("b") != nil {
+		t.Errorf("AddTags(blank) = %d, entry %v", n, s.Get("b"))
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	m := s.Get("a")
+	if !slices.Equal(m.Tags, []string{"flaky", "regression"}) || !m.HasTag("REGRESSION") {
+		t.Errorf("tags = %v", m.Tags)
+	}
+	if len(m.Notes) != 1 || m.Notes[0].Text != "this run introduced the regression" || !m.Notes[0].CreatedAt.Equal(at) {
+		t.Errorf("notes = %+v", m.Notes)
+	}
+
+	// Imports add the tags and notes the store lacks.
+	in := &Export{Version: exportVersion, Sessions: map[string]*SessionMeta{
+		"a": {Tags: []string{"flaky", "auth"}, Notes: []session.Note{m.Notes[0], {Text: "earlier", CreatedAt: at.Add(-time.Hour)}}},
+	}}
+	if res := s.Import(in, false); res.Updated != 1 {
+		t.Errorf("Import = %+v, want 1 updated", res)
+	}
+	if m = s.Get("a"); !slices.Equal(m.Tags, []string{"auth", "flaky", "regression"}) || len(m.Notes) != 2 || m.Notes[0].Text != "earlier" {
+		t.Errorf("after import: tags %v, notes %+v", m.Tags, m.Notes)
+	}
+	if res := s.Import(in, false); res.Unchanged != 1 {
+		t.Errorf("second Import = %+v, want 1 unchanged", res)
+	}
+
+	if n := s.RemoveTags("a", []string{"FLAKY", "auth", "regression", "missing"}); n != 3 {
+		t.Errorf("RemoveTags = %d, want 3", n)
+	}
+	if n := s.ClearNotes("a"); n != 2 {
+		t.Errorf("ClearNotes = %d, want 2", n)
+	}
+	if s.Get("a") != nil {
+		t.Errorf("entry left after removing everything: %+v", s.Get("a"))
+	}
+}
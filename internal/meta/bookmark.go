@@ -0,0 +1,103 @@
+package meta
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Bookmark names a moment in a session's transcript.
+type Bookmark struct {
+	Name string `json:"name"`
+	// Line is the 1-based line of the transcript file the bookmark points
+	// at.
+	Line      int       `json:"line"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Bookmark returns the session's bookmark of that name, or nil.
+func (m *SessionMeta) Bookmark(name string) *Bookmark {
+	if m == nil {
+		return nil
+	}
+	for i := range m.Bookmarks {
+		if m.Bookmarks[i].Name == name {
+			return &m.Bookmarks[i]
+		}
+	}
+	return nil
+}
+
+// AddBookmark records a bookmark in a session. An existing bookmark of the
+// same name is an error unless replace is set.
+func (s *Store) AddBookmark(sessionID string, b Bookmark, replace bool) error {
+	if b.Name == "" {
+		return fmt.Errorf("a bookmark needs a name")
+	}
+	if b.Line < 1 {
+		return fmt.Errorf("bookmark line must be at least 1, got %d", b.Line)
+	}
+	m := s.entry(sessionID)
+	if old := m.Bookmark(b.Name); old != nil {
+		if !replace {
+			s.prune(sessionID)
+			return fmt.Errorf("session %s already has a bookmark named %q", sessionID, b.Name)
+		}
+		*old = b
+		return nil
+	}
+	m.Bookmarks = append(m.Bookmarks, b)
+	sortBookmarks(m.Bookmarks)
+	return nil
+}
+
+// RemoveBookmark deletes a session's bookmark, reporting whether it existed.
+func (s *Store) RemoveBookmark(sessionID, name string) bool {
+	m := s.Sessions[sessionID]
+	if m == nil {
+		return false
+	}
+	for i, b := range m.Bookmarks {
+		if b.Name == name {
+			m.Bookmarks = append(m.Bookmarks[:i], m.Bookmarks[i+1:]...)
+			if len(m.Bookmarks) == 0 {
+				m.Bookmarks = nil
+			}
+			s.prune(sessionID)
+			return true
+		}
+	}
+	return false
+}
+
+// sortBookmarks orders bookmarks as they occur in the transcript.
+func sortBookmarks(bs []Bookmark) {
+	sort.SliceStable(bs, func(i, j int) bool {
+		if bs[i].Line != bs[j].Line {
+			return bs[i].Line < bs[j].Line
+		}
+		return bs[i].Name < bs[j].Name
+	})
+}
+
+// mergeBookmarks takes the bookmarks of o that m lacks, and with overwrite
+// replaces m's differing bookmarks of the same name.
+func (m *SessionMeta) mergeBookmarks(o *SessionMeta, overwrite bool) bool {
+	changed := false
+	for _, b := range o.Bookmarks {
+		old := m.Bookmark(b.Name)
+		switch {
+		case old == nil:
+			m.Bookmarks = append(m.Bookmarks, b)
+			changed = true
+		case overwrite && *old != b:
+			*old = b
+			changed = true
+		}
+	}
+	if changed {
+		sortBookmarks(m.Bookmarks)
+	}
+	return changed
+}
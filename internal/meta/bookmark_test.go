@@ -0,0 +1,67 @@
+package meta
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBookmarks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meta.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	if err := s.AddBookmark("a", Bookmark{Name: "fix", Line: 90, CreatedAt: at}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddBookmark("a", Bookmark{Name: "bug repro", Line: 12, Note: "fails here", CreatedAt: at}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddBookmark("a", Bookmark{Name: "fix", Line: 95}, false); err == nil {
+		t.Error("duplicate name: want an error")
+	}
+	if err := s.AddBookmark("b", Bookmark{Name: "x", Line: 0}, false); err == nil {
+		t.Error("line 0: want an error")
+	}
+	if _, ok := s.Sessions["b"]; ok {
+		t.Error("a rejected bookmark left an entry behind")
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	bs := s.Get("a").Bookmarks
+	if len(bs) != 2 || bs[0].Name != "bug repro" || bs[1].Name != "fix" {
+		t.Fatalf("bookmarks = %+v, want bug repro then fix", bs)
+	}
+	if b := s.Get("a").Bookmark("bug repro"); b == nil || b.Line != 12 || b.Note != "fails here" {
+		t.Errorf("Bookmark(bug repro) = %+v", b)
+	}
+	if err := s.AddBookmark("a", Bookmark{Name: "fix", Line: 95}, true); err != nil || s.Get("a").Bookmark("fix").Line != 95 {
+		t.Errorf("replace: err %v, fix = %+v", err, s.Get("a").Bookmark("fix"))
+	}
+
+	// Imports add bookmarks by name and keep local ones unless overwriting.
+	in := &Export{Version: exportVersion, Sessions: map[string]*SessionMeta{
+		"a": {Bookmarks: []Bookmark{{Name: "fix", Line: 99}, {Name: "start", Line: 1}}},
+	}}
+	if res := s.Import(in, false); res.Updated != 1 {
+		t.Errorf("Import = %+v, want 1 updated", res)
+	}
+	if got := s.Get("a"); len(got.Bookmarks) != 3 || got.Bookmarks[0].Name != "start" || got.Bookmark("fix").Line != 95 {
+		t.Errorf("after import: %+v", got.Bookmarks)
+	}
+
+	for _, name := range []string{"bug repro", "fix", "start"} {
+		if !s.RemoveBookmark("a", name) {
+			t.Errorf("RemoveBookmark(%s) = false", name)
+		}
+	}
+	if _, ok := s.Sessions["a"]; ok {
+		t.Error("entry kept after its last bookmark was removed")
+	}
+}
@@ -0,0 +1,123 @@
+// Package meta stores what the user records about sessions beyond their
+// transcripts, such as marking a session as a test run, tagging it or
+// pinning it.
+// Entries are keyed by session ID and live in one JSON file in the grove
+// state directory, or, for projects that opt in, in the project itself (see
+// Stores).
+package meta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grovetools/core/pkg/paths"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+// SessionMeta is what is recorded about one session.
+type SessionMeta struct {
+	// Test is set when the user marked the session as a test or demo run
+	// (true) or as a real one (false), overriding auto-detection.
+	Test *bool `json:"test,omitempty"`
+	// Bookmarks name moments in the session's transcript, in transcript
+	// order.
+	Bookmarks []Bookmark `json:"bookmarks,omitempty"`
+	// Tags label the session for filtering ('aglogs list --tag'), sorted.
+	Tags []string `json:"tags,omitempty"`
+	// Notes are free-form comments on the session, oldest first.
+	Notes []session.Note `json:"notes,omitempty"`
+	// Pin is set while the session is pinned ('aglogs pin'), exempting its
+	// transcripts from prune.
+	Pin *Pin `json:"pin,omitempty"`
+}
+
+func (m *SessionMeta) empty() bool {
+	return m.Test == nil && len(m.Bookmarks) == 0 && len(m.Tags) == 0 && len(m.Notes) == 0 && m.Pin == nil
+}
+
+// Store is the session metadata file.
+type Store struct {
+	Sessions map[string]*SessionMeta `json:"sessions"`
+
+	path string
+}
+
+// DefaultPath returns the location of the shared metadata store.
+func DefaultPath() string {
+	return filepath.Join(paths.StateDir(), "aglogs", "meta.json")
+}
+
+// Load reads the store at path; a missing file is an empty store.
+func Load(path string) (*Store, error) {
+	s := &Store{Sessions: make(map[string]*SessionMeta), path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if s.Sessions == nil {
+		s.Sessions = make(map[string]*SessionMeta)
+	}
+	return s, nil
+}
+
+// Get returns the metadata of a session, or nil when none is recorded.
+func (s *Store) Get(sessionID string) *SessionMeta {
+	return s.Sessions[sessionID]
+}
+
+// SetTest marks a session as a test run (true) or a real one (false).
+func (s *Store) SetTest(sessionID string, test bool) {
+	s.entry(sessionID).Test = &test
+}
+
+// ClearTest removes a session's test mark, returning it to auto-detection.
+func (s *Store) ClearTest(sessionID string) {
+	if m := s.Sessions[sessionID]; m != nil {
+		m.Test = nil
+		s.prune(sessionID)
+	}
+}
+
+func (s *Store) entry(sessionID string) *SessionMeta {
+	m := s.Sessions[sessionID]
+	if m == nil {
+		m = &SessionMeta{}
+		s.Sessions[sessionID] = m
+	}
+	return m
+}
+
+func (s *Store) prune(sessionID string) {
+	if m := s.Sessions[sessionID]; m != nil && m.empty() {
+		delete(s.Sessions, sessionID)
+	}
+}
+
+// Save writes the store atomically.
+func (s *Store) Save() error {
+	if s.path == "" {
+		return errors.New("meta store has no path")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
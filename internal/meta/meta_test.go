@@ -0,0 +1,48 @@
+package meta
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func TestStoreTestMarks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meta.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tend := session.SessionInfo{SessionID: "a", ProjectPath: "/tmp/grove-tend-list-123/project"}
+	real := session.SessionInfo{SessionID: "b", ProjectPath: "/home/me/code/api"}
+
+	if test, reason := s.IsTest(tend); !test || reason != "tend scenario" {
+		t.Errorf("IsTest(tend) = %v, %q", test, reason)
+	}
+	if test, _ := s.IsTest(real); test {
+		t.Error("IsTest(real) = true")
+	}
+
+	s.SetTest("a", false)
+	s.SetTest("b", true)
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if test, reason := s.IsTest(tend); test || reason != "marked" {
+		t.Errorf("IsTest(tend) after unmarking = %v, %q", test, reason)
+	}
+	if test, _ := s.IsTest(real); !test {
+		t.Error("IsTest(real) after marking = false")
+	}
+
+	s.ClearTest("a")
+	if _, ok := s.Sessions["a"]; ok {
+		t.Error("cleared entry kept in the store")
+	}
+	if test, _ := s.IsTest(tend); !test {
+		t.Error("IsTest(tend) after clearing = false, want auto-detected")
+	}
+}
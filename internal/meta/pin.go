@@ -0,0 +1,47 @@
+package meta
+
+import "time"
+
+// Pin records that a session is kept as a long-term reference or audit
+// evidence: prune leaves its transcripts alone whatever their age.
+type Pin struct {
+	Reason   string    `json:"reason,omitempty"`
+	PinnedAt time.Time `json:"pinnedAt"`
+}
+
+// IsPinned reports whether the session is pinned.
+func (m *SessionMeta) IsPinned() bool {
+	return m != nil && m.Pin != nil
+}
+
+// SetPin pins a session, replacing an earlier pin's reason.
+func (s *Store) SetPin(sessionID, reason string, now time.Time) {
+	s.entry(sessionID).Pin = &Pin{Reason: reason, PinnedAt: now.UTC()}
+}
+
+// Unpin removes a session's pin, reporting whether it had one.
+func (s *Store) Unpin(sessionID string) bool {
+	m := s.Sessions[sessionID]
+	if !m.IsPinned() {
+		return false
+	}
+	m.Pin = nil
+	s.prune(sessionID)
+	return true
+}
+
+// mergePin takes o's pin when m has none, or with overwrite when they
+// differ. An import never unpins.
+func (m *SessionMeta) mergePin(o *SessionMeta, overwrite bool) bool {
+	switch {
+	case o.Pin == nil:
+		return false
+	case m.Pin != nil && !overwrite:
+		return false
+	case m.Pin != nil && m.Pin.Reason == o.Pin.Reason && m.Pin.PinnedAt.Equal(o.Pin.PinnedAt):
+		return false
+	}
+	pin := *o.Pin
+	m.Pin = &pin
+	return true
+}
@@ -0,0 +1,57 @@
+package meta
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPins(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "meta.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetPin("a", "audit: incident 42", now)
+	s.SetPin("b", "", now)
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if m := s.Get("a"); !m.IsPinned() || m.Pin.Reason != "audit: incident 42" || !m.Pin.PinnedAt.Equal(now) {
+		t.Errorf("a = %+v", m)
+	}
+	if s.Get("c").IsPinned() {
+		t.Error("unrecorded session reported pinned")
+	}
+
+	if !s.Unpin("b") || s.Unpin("b") {
+		t.Error("Unpin did not report whether the session was pinned")
+	}
+	if _, ok := s.Sessions["b"]; ok {
+		t.Error("unpinned entry kept in the store")
+	}
+
+	// Imports add pins and, with overwrite, replace their reason, but
+	// never unpin.
+	imported := &Export{Version: exportVersion, Sessions: map[string]*SessionMeta{
+		"a": {Pin: &Pin{Reason: "reference run", PinnedAt: now.Add(time.Hour)}},
+		"b": {Pin: &Pin{Reason: "reference run", PinnedAt: now}},
+	}}
+	if res := s.Import(imported, false); res.Added != 1 || res.Unchanged != 1 {
+		t.Errorf("import = %+v", res)
+	}
+	if reason := s.Get("a").Pin.Reason; reason != "audit: incident 42" {
+		t.Errorf("import without overwrite replaced the reason with %q", reason)
+	}
+	if res := s.Import(imported, true); res.Updated != 1 || s.Get("a").Pin.Reason != "reference run" {
+		t.Errorf("import with overwrite = %+v, reason %q", res, s.Get("a").Pin.Reason)
+	}
+	imported.Sessions["b"].Pin.Reason = "changed"
+	if s.Get("b").Pin.Reason != "reference run" {
+		t.Error("imported pin shares memory with the export")
+	}
+}
@@ -0,0 +1,39 @@
+package meta
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+// tendDirPrefix starts the name of the temporary directory the tend e2e
+// harness creates for each scenario (grove-tend-<scenario>-<random>).
+const tendDirPrefix = "grove-tend-"
+
+// IsTest reports whether a session is a test or demo run, and why: the
+// user's mark when there is one, otherwise DetectTest.
+func (s *Store) IsTest(info session.SessionInfo) (bool, string) {
+	return isTest(s.Get(info.SessionID), info)
+}
+
+func isTest(m *SessionMeta, info session.SessionInfo) (bool, string) {
+	if m != nil && m.Test != nil {
+		return *m.Test, "marked"
+	}
+	reason := DetectTest(info)
+	return reason != "", reason
+}
+
+// DetectTest recognizes sessions run by the tend e2e harness, whose
+// scenarios run agents in grove-tend-* directories. It returns why the
+// session is a test run, or "" when it does not look like one. Plain
+// temporary directories are not enough: people try things out in /tmp too.
+func DetectTest(info session.SessionInfo) string {
+	for _, part := range strings.Split(filepath.ToSlash(info.ProjectPath), "/") {
+		if strings.HasPrefix(part, tendDirPrefix) {
+			return "tend scenario"
+		}
+	}
+	return ""
+}
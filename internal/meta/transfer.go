@@ -0,0 +1,112 @@
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+// exportVersion identifies the export format; ReadExport rejects newer
+// ones rather than silently dropping what it doesn't understand.
+const exportVersion = 1
+
+// Export is a portable copy of session metadata, for versioning it in a
+// repository or sharing it with teammates.
+type Export struct {
+	Version    int                     `json:"version"`
+	ExportedAt time.Time               `json:"exportedAt"`
+	Sessions   map[string]*SessionMeta `json:"sessions"`
+}
+
+// Export copies the metadata of the given sessions, or of all of them when
+// ids is empty.
+func (s *Store) Export(ids []string, now time.Time) *Export {
+	e := &Export{Version: exportVersion, ExportedAt: now.UTC(), Sessions: make(map[string]*SessionMeta)}
+	if len(ids) == 0 {
+		for id, m := range s.Sessions {
+			e.Sessions[id] = m
+		}
+		return e
+	}
+	for _, id := range ids {
+		if m := s.Sessions[id]; m != nil {
+			e.Sessions[id] = m
+		}
+	}
+	return e
+}
+
+// ReadExport parses an export written by Export.
+func ReadExport(r io.Reader) (*Export, error) {
+	var e Export
+	if err := json.NewDecoder(r).Decode(&e); err != nil {
+		return nil, fmt.Errorf("invalid metadata export: %w", err)
+	}
+	if e.Version < 1 || e.Version > exportVersion {
+		return nil, fmt.Errorf("unsupported metadata export version %d (this aglogs reads up to %d)", e.Version, exportVersion)
+	}
+	return &e, nil
+}
+
+// ImportResult counts what an import changed.
+type ImportResult struct {
+	Added     int `json:"added"`     // sessions with no metadata before
+	Updated   int `json:"updated"`   // sessions whose metadata changed
+	Unchanged int `json:"unchanged"` // sessions already up to date
+}
+
+// Import merges an export into the store. Values the store lacks are
+// always taken; with overwrite, imported values also replace differing
+// local ones, which are kept otherwise.
+func (s *Store) Import(e *Export, overwrite bool) ImportResult {
+	var res ImportResult
+	for id, in := range e.Sessions {
+		if in == nil || in.empty() {
+			continue
+		}
+		m := s.Sessions[id]
+		if m == nil {
+			copied := *in
+			copied.Bookmarks = append([]Bookmark(nil), in.Bookmarks...)
+			copied.Tags = append([]string(nil), in.Tags...)
+			copied.Notes = append([]session.Note(nil), in.Notes...)
+			if in.Pin != nil {
+				pin := *in.Pin
+				copied.Pin = &pin
+			}
+			s.Sessions[id] = &copied
+			res.Added++
+			continue
+		}
+		if m.merge(in, overwrite) {
+			res.Updated++
+		} else {
+			res.Unchanged++
+		}
+	}
+	return res
+}
+
+// merge takes o's values into m, field by field, reporting whether m
+// changed.
+func (m *SessionMeta) merge(o *SessionMeta, overwrite bool) bool {
+	changed := false
+	if o.Test != nil && (m.Test == nil || (overwrite && *m.Test != *o.Test)) {
+		test := *o.Test
+		m.Test = &test
+		changed = true
+	}
+	if m.mergeBookmarks(o, overwrite) {
+		changed = true
+	}
+	if m.mergeAnnotations(o) {
+		changed = true
+	}
+	if m.mergePin(o, overwrite) {
+		changed = true
+	}
+	return changed
+}
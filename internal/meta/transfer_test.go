@@ -0,0 +1,59 @@
+package meta
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportImport(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	src, err := Load(filepath.Join(t.TempDir(), "meta.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.SetTest("a", true)
+	src.SetTest("b", false)
+	src.SetTest("c", true)
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(src.Export(nil, now)); err != nil {
+		t.Fatal(err)
+	}
+	e, err := ReadExport(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Sessions) != 3 || !e.ExportedAt.Equal(now) {
+		t.Fatalf("export = %+v", e)
+	}
+	if only := src.Export([]string{"a", "missing"}, now); len(only.Sessions) != 1 {
+		t.Errorf("export of a = %+v", only.Sessions)
+	}
+
+	dst, err := Load(filepath.Join(t.TempDir(), "meta.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst.SetTest("a", true)
+	dst.SetTest("b", true) // conflicts with the export
+	if res := dst.Import(e, false); res != (ImportResult{Added: 1, Unchanged: 2}) {
+		t.Errorf("merge = %+v", res)
+	}
+	if !*dst.Get("b").Test || !*dst.Get("c").Test {
+		t.Errorf("after merge b=%v c=%v, want local b kept and c added", *dst.Get("b").Test, *dst.Get("c").Test)
+	}
+	if res := dst.Import(e, true); res != (ImportResult{Updated: 1, Unchanged: 2}) {
+		t.Errorf("overwrite = %+v", res)
+	}
+	if *dst.Get("b").Test {
+		t.Error("overwrite kept the local value")
+	}
+
+	if _, err := ReadExport(strings.NewReader(`{"version": 99, "sessions": {}}`)); err == nil {
+		t.Error("ReadExport accepted a future version")
+	}
+}
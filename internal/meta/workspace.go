@@ -0,0 +1,126 @@
+package meta
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+// WorkspaceFile is where a project keeps the metadata of its own sessions,
+// relative to the project root. Committed, it travels with the repository.
+const WorkspaceFile = ".grove/aglogs-meta.json"
+
+// WorkspacePath returns the workspace store file of a project.
+func WorkspacePath(projectPath string) string {
+	return filepath.Join(projectPath, filepath.FromSlash(WorkspaceFile))
+}
+
+// Stores is the metadata of all sessions: the home store, plus the workspace
+// store of each project that has opted in by having one. A session's
+// metadata lives in its project's workspace store when there is one, and in
+// the home store otherwise.
+type Stores struct {
+	Home *Store
+
+	// workspaces caches the workspace store of each project looked up; nil
+	// for projects without one.
+	workspaces map[string]*Store
+}
+
+// LoadStores reads the home store at homePath. Workspace stores are read as
+// sessions from their projects are looked up.
+func LoadStores(homePath string) (*Stores, error) {
+	home, err := Load(homePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Stores{Home: home}, nil
+}
+
+// Workspace returns the workspace store of a project, or nil when the
+// project has none.
+func (s *Stores) Workspace(projectPath string) (*Store, error) {
+	if projectPath == "" {
+		return nil, nil
+	}
+	if ws, ok := s.workspaces[projectPath]; ok {
+		return ws, nil
+	}
+	if s.workspaces == nil {
+		s.workspaces = make(map[string]*Store)
+	}
+	path := WorkspacePath(projectPath)
+	var ws *Store
+	if _, err := os.Stat(path); err == nil {
+		if ws, err = Load(path); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	s.workspaces[projectPath] = ws
+	return ws, nil
+}
+
+// For returns the store that holds a session's metadata, and that changes to
+// it are saved to.
+func (s *Stores) For(info session.SessionInfo) (*Store, error) {
+	ws, err := s.Workspace(info.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	if ws != nil {
+		return ws, nil
+	}
+	return s.Home, nil
+}
+
+// Get returns the metadata of a session, or nil when none is recorded. An
+// unreadable workspace store is passed over for the home store.
+func (s *Stores) Get(info session.SessionInfo) *SessionMeta {
+	if ws, err := s.Workspace(info.ProjectPath); err == nil && ws != nil {
+		if m := ws.Get(info.SessionID); m != nil {
+			return m
+		}
+	}
+	return s.Home.Get(info.SessionID)
+}
+
+// IsTest is Store.IsTest across the stores.
+func (s *Stores) IsTest(info session.SessionInfo) (bool, string) {
+	return isTest(s.Get(info), info)
+}
+
+// InitWorkspace creates a project's workspace store, if it has none, and
+// moves what the home store records about the given sessions into it. The
+// workspace's own values win over moved ones. Both stores are saved.
+func (s *Stores) InitWorkspace(projectPath string, sessionIDs []string) (moved int, err error) {
+	ws, err := s.Workspace(projectPath)
+	if err != nil {
+		return 0, err
+	}
+	if ws == nil {
+		ws = &Store{Sessions: make(map[string]*SessionMeta), path: WorkspacePath(projectPath)}
+	}
+	for _, id := range sessionIDs {
+		m := s.Home.Sessions[id]
+		if m == nil {
+			continue
+		}
+		ws.entry(id).merge(m, false)
+		delete(s.Home.Sessions, id)
+		moved++
+	}
+	if err := ws.Save(); err != nil {
+		return 0, err
+	}
+	s.workspaces[projectPath] = ws
+	if moved > 0 {
+		if err := s.Home.Save(); err != nil {
+			return 0, err
+		}
+	}
+	return moved, nil
+}
@@ -0,0 +1,75 @@
+package meta
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func TestStoresWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	homePath := filepath.Join(dir, "home", "meta.json")
+	project := filepath.Join(dir, "api")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	inProject := session.SessionInfo{SessionID: "a", ProjectPath: project}
+	elsewhere := session.SessionInfo{SessionID: "b", ProjectPath: filepath.Join(dir, "web")}
+
+	home, err := Load(homePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	home.SetTest("a", true)
+	home.SetTest("b", true)
+	if err := home.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	stores, err := LoadStores(homePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := stores.For(inProject); s != stores.Home {
+		t.Error("For: a project without a workspace store should use the home store")
+	}
+
+	moved, err := stores.InitWorkspace(project, []string{"a", "missing"})
+	if err != nil || moved != 1 {
+		t.Fatalf("InitWorkspace = %d, %v; want 1 moved", moved, err)
+	}
+	if _, err := os.Stat(WorkspacePath(project)); err != nil {
+		t.Fatalf("workspace store not written: %v", err)
+	}
+
+	// A fresh load sees the move: a's mark is in the project, b's at home.
+	if stores, err = LoadStores(homePath); err != nil {
+		t.Fatal(err)
+	}
+	if stores.Home.Get("a") != nil || stores.Home.Get("b") == nil {
+		t.Errorf("home store after init = %v", stores.Home.Sessions)
+	}
+	ws, err := stores.For(inProject)
+	if err != nil || ws == stores.Home || ws.Get("a") == nil {
+		t.Fatalf("For(in project) = %v, %v; want the workspace store with a", ws, err)
+	}
+	if test, reason := stores.IsTest(inProject); !test || reason != "marked" {
+		t.Errorf("IsTest(in project) = %v, %q", test, reason)
+	}
+	if test, _ := stores.IsTest(elsewhere); !test {
+		t.Error("IsTest(elsewhere) = false; want the home mark")
+	}
+
+	ws.SetTest("a", false)
+	if err := ws.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if stores, err = LoadStores(homePath); err != nil {
+		t.Fatal(err)
+	}
+	if test, _ := stores.IsTest(inProject); test {
+		t.Error("IsTest(in project) after unmarking in the workspace = true")
+	}
+}
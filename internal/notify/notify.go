@@ -0,0 +1,219 @@
+// Package notify delivers session notifications, such as a session stalled
+// on a question, to a webhook or the desktop. A ledger remembers what was
+// sent so that a periodic check announces each event once.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/grovetools/core/pkg/paths"
+)
+
+// Event names.
+const (
+	// EventStalled is sent for a session waiting on its user.
+	EventStalled = "session.stalled"
+	// EventAwaitingInput is sent when a session starts waiting on its user.
+	EventAwaitingInput = "session.awaiting_input"
+	// EventTaskCompleted and EventTaskFailed carry the output of a
+	// scheduled task.
+	EventTaskCompleted = "task.completed"
+	EventTaskFailed    = "task.failed"
+)
+
+// ledgerRetention is how long the ledger remembers a sent event.
+const ledgerRetention = 7 * 24 * time.Hour
+
+// Event is one notification. Text is a one-line summary, so the payload can
+// be posted to chat webhooks (Slack, Mattermost) that display "text".
+type Event struct {
+	Event     string    `json:"event"`
+	Text      string    `json:"text"`
+	SessionID string    `json:"sessionId"`
+	Provider  string    `json:"provider,omitempty"`
+	Project   string    `json:"project,omitempty"`
+	Job       string    `json:"job,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Since     time.Time `json:"since,omitzero"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// Key identifies an event in the ledger: the same session waiting since the
+// same moment is the same event.
+func (e Event) Key() string {
+	return e.Event + " " + e.SessionID + " " + e.Since.UTC().Format(time.RFC3339Nano)
+}
+
+// Webhook posts events as JSON to a URL.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// Send posts ev and fails unless the endpoint answers 2xx.
+func (w *Webhook) Send(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Desktop shows events as OS notifications, through notify-send on Linux
+// and the BSDs and osascript on macOS.
+type Desktop struct {
+	goos string
+	// run executes the notifier; tests replace it.
+	run func(ctx context.Context, name string, args ...string) error
+}
+
+// NewDesktop returns a desktop notifier, or an error when this system has
+// no way to show notifications.
+func NewDesktop() (*Desktop, error) {
+	d := &Desktop{goos: runtime.GOOS, run: runCommand}
+	name, _, err := desktopCommand(d.goos, "", "")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, fmt.Errorf("desktop notifications need %s: %w", name, err)
+	}
+	return d, nil
+}
+
+// Send shows ev, titled with its project (or job) and worded by its Text.
+func (d *Desktop) Send(ctx context.Context, ev Event) error {
+	title := "aglogs"
+	if ev.Job != "" {
+		title += ": " + ev.Job
+	} else if ev.Project != "" {
+		title += ": " + ev.Project
+	}
+	name, args, err := desktopCommand(d.goos, title, ev.Text)
+	if err != nil {
+		return err
+	}
+	if err := d.run(ctx, name, args...); err != nil {
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	return nil
+}
+
+// desktopCommand returns the command that shows a notification on goos.
+func desktopCommand(goos, title, body string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return "osascript", []string{"-e", script}, nil
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return "notify-send", []string{"--app-name=aglogs", "--", title, body}, nil
+	default:
+		return "", nil, fmt.Errorf("desktop notifications are not supported on %s", goos)
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func runCommand(ctx context.Context, name string, args ...string) error {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil && len(out) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return err
+}
+
+// Ledger records sent events by Key.
+type Ledger struct {
+	Sent map[string]time.Time `json:"sent"`
+
+	path string
+}
+
+// DefaultLedgerPath returns the location of the shared ledger.
+func DefaultLedgerPath() string {
+	return filepath.Join(paths.StateDir(), "aglogs", "notified.json")
+}
+
+// LoadLedger reads the ledger at path. A missing or unreadable ledger is
+// empty: at worst an event is announced again.
+func LoadLedger(path string) *Ledger {
+	l := &Ledger{Sent: make(map[string]time.Time), path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return l
+	}
+	var stored Ledger
+	if json.Unmarshal(data, &stored) == nil && stored.Sent != nil {
+		l.Sent = stored.Sent
+	}
+	return l
+}
+
+// Seen reports whether ev was already sent.
+func (l *Ledger) Seen(ev Event) bool {
+	_, ok := l.Sent[ev.Key()]
+	return ok
+}
+
+// Mark records ev as sent at now.
+func (l *Ledger) Mark(ev Event, now time.Time) {
+	l.Sent[ev.Key()] = now
+}
+
+// Save writes the ledger, forgetting events sent more than a week before
+// now.
+func (l *Ledger) Save(now time.Time) error {
+	for key, sent := range l.Sent {
+		if now.Sub(sent) > ledgerRetention {
+			delete(l.Sent, key)
+		}
+	}
+	if l.path == "" {
+		return errors.New("ledger has no path")
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
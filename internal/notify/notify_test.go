@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWebhookAndLedger(t *testing.T) {
+	var got []Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev Event
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if ev.SessionID == "fail" {
+			http.Error(w, "nope", http.StatusInternalServerError)
+			return
+		}
+		got = append(got, ev)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ev := Event{Event: EventStalled, Text: "s1 waits", SessionID: "s1", Reason: "question", Since: now.Add(-time.Hour)}
+	hook := &Webhook{URL: srv.URL}
+	if err := hook.Send(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Text != "s1 waits" || !got[0].Since.Equal(ev.Since) {
+		t.Errorf("received %+v", got)
+	}
+	if err := hook.Send(context.Background(), Event{SessionID: "fail"}); err == nil {
+		t.Error("Send ignored a 500 response")
+	}
+
+	path := filepath.Join(t.TempDir(), "notified.json")
+	ledger := LoadLedger(path)
+	if ledger.Seen(ev) {
+		t.Fatal("empty ledger has seen the event")
+	}
+	ledger.Mark(ev, now)
+	old := Event{Event: EventStalled, SessionID: "old", Since: now}
+	ledger.Mark(old, now.Add(-8*24*time.Hour))
+	if err := ledger.Save(now); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := LoadLedger(path)
+	if !reloaded.Seen(ev) || reloaded.Seen(old) {
+		t.Errorf("reloaded ledger = %v", reloaded.Sent)
+	}
+	later := ev
+	later.Since = now
+	if reloaded.Seen(later) {
+		t.Error("a new stall of the same session counts as seen")
+	}
+}
+
+func TestDesktop(t *testing.T) {
+	var got []string
+	d := &Desktop{goos: "darwin", run: func(_ context.Context, name string, args ...string) error {
+		got = append([]string{name}, args...)
+		return nil
+	}}
+	ev := Event{Event: EventAwaitingInput, Project: "api", Text: `Session s1 asks: use "v2"?`}
+	if err := d.Send(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+	want := `display notification "Session s1 asks: use \"v2\"?" with title "aglogs: api"`
+	if len(got) != 3 || got[0] != "osascript" || got[2] != want {
+		t.Errorf("darwin command = %q", got)
+	}
+
+	d.goos = "linux"
+	ev.Job = "plan/job-1"
+	if err := d.Send(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 5 || got[0] != "notify-send" || got[3] != "aglogs: plan/job-1" || got[4] != ev.Text {
+		t.Errorf("linux command = %q", got)
+	}
+
+	d.goos = "plan9"
+	if err := d.Send(context.Background(), ev); err == nil {
+		t.Error("Send succeeded on an unsupported OS")
+	}
+}
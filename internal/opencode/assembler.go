@@ -63,6 +63,10 @@ type ToolPart struct {
 	Output string                 `json:"output"`
 	Title  string                 `json:"title,omitempty"`
 	Diff   string                 `json:"diff,omitempty"`
+	// Exit is a bash command's exit status; nil for other tools.
+	Exit *int `json:"exit,omitempty"`
+	// DurationMs is the tool's run time from its state timestamps.
+	DurationMs int64 `json:"durationMs,omitempty"`
 }
 
 // PatchPart represents a VCS patch part: opencode records the snapshot
@@ -262,11 +266,16 @@ func (a *Assembler) parsePart(data []byte) (Part, error) {
 				Title    string                 `json:"title"`
 				Metadata struct {
 					Diff string `json:"diff"`
+					Exit *int   `json:"exit"`
 				} `json:"metadata"`
+				Time struct {
+					Start int64 `json:"start"`
+					End   int64 `json:"end"`
+				} `json:"time"`
 			} `json:"state"`
 		}
 		if err := json.Unmarshal(data, &toolPart); err == nil {
-			part.Content = ToolPart{
+			tp := ToolPart{
 				CallID: toolPart.CallID,
 				Tool:   toolPart.Tool,
 				Status: toolPart.State.Status,
@@ -274,7 +283,12 @@ func (a *Assembler) parsePart(data []byte) (Part, error) {
 				Output: toolPart.State.Output,
 				Title:  toolPart.State.Title,
 				Diff:   toolPart.State.Metadata.Diff,
+				Exit:   toolPart.State.Metadata.Exit,
 			}
+			if t := toolPart.State.Time; t.Start > 0 && t.End >= t.Start {
+				tp.DurationMs = t.End - t.Start
+			}
+			part.Content = tp
 		}
 
 	case "step-start":
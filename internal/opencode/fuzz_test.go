@@ -0,0 +1,40 @@
+package opencode
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// FuzzParsePart feeds the part parser the fixture parts, whole and torn
+// in half, and mutations of them. A malformed part file must come back
+// as an error, never a panic, and a parsed part must stay encodable.
+// Run it with:
+//
+//	go test ./internal/opencode -run '^$' -fuzz FuzzParsePart
+func FuzzParsePart(f *testing.F) {
+	paths, err := filepath.Glob("testdata/storage/part/*/*.json")
+	if err != nil || len(paths) == 0 {
+		f.Fatal("no part fixtures")
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+		f.Add(data[:len(data)/2])
+	}
+
+	a := &Assembler{}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		part, err := a.parsePart(data)
+		if err != nil {
+			return
+		}
+		if _, err := json.Marshal(part); err != nil {
+			t.Errorf("parsed part does not encode: %v", err)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+// Package progress reports progress through long operations: a bar with an
+// ETA redrawn in place on a terminal, and a log line now and then
+// otherwise. Nothing is shown for operations that finish quickly.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// showAfter is how long an operation runs before progress shows.
+	showAfter = 500 * time.Millisecond
+	// redrawEvery throttles terminal redraws.
+	redrawEvery = 100 * time.Millisecond
+	// logEvery is the interval between log lines when not on a terminal.
+	logEvery = 10 * time.Second
+
+	barWidth = 30
+)
+
+// Bar tracks progress through a number of items. It is safe for
+// concurrent use, and a nil *Bar is valid and reports nothing, so
+// operations can take one optionally.
+type Bar struct {
+	mu       sync.Mutex
+	w        io.Writer
+	tty      bool
+	label    string
+	total    int
+	done     int
+	start    time.Time
+	last     time.Time // of the last redraw or log line
+	drawn    bool      // a bar is on screen
+	now      func() time.Time
+	finished bool
+}
+
+// New returns a bar writing to w, labelled with what is being processed
+// ("indexing transcripts"). It draws on w when w is a terminal and logs
+// otherwise.
+func New(w io.Writer, label string) *Bar {
+	return &Bar{w: w, tty: isTerminal(w), label: label, start: time.Now(), now: time.Now}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// SetTotal sets the number of items, once it is known.
+func (b *Bar) SetTotal(total int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total = total
+}
+
+// Add records n more items done.
+func (b *Bar) Add(n int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done += n
+	now := b.now()
+	elapsed := now.Sub(b.start)
+	if b.finished || elapsed < showAfter {
+		return
+	}
+	if b.tty {
+		if now.Sub(b.last) >= redrawEvery || b.done == b.total {
+			fmt.Fprintf(b.w, "\r\033[K%s", b.line(elapsed, true))
+			b.drawn, b.last = true, now
+		}
+		return
+	}
+	if b.last.IsZero() {
+		b.last = b.start
+	}
+	if now.Sub(b.last) >= logEvery {
+		fmt.Fprintln(b.w, b.line(elapsed, false))
+		b.last = now
+	}
+}
+
+// Clear erases the bar so other output can be written; the next Add
+// redraws it.
+func (b *Bar) Clear() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clear()
+}
+
+func (b *Bar) clear() {
+	if b.drawn {
+		fmt.Fprint(b.w, "\r\033[K")
+		b.drawn = false
+		b.last = time.Time{}
+	}
+}
+
+// Finish clears the bar. Calling it more than once is harmless.
+func (b *Bar) Finish() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clear()
+	b.finished = true
+}
+
+// line renders the current progress, with a bar when bar is set.
+func (b *Bar) line(elapsed time.Duration, bar bool) string {
+	var s strings.Builder
+	s.WriteString(b.label + " ")
+	if b.total <= 0 {
+		fmt.Fprintf(&s, "%d", b.done)
+		return s.String()
+	}
+	done := min(b.done, b.total)
+	frac := float64(done) / float64(b.total)
+	if bar {
+		filled := int(frac * barWidth)
+		s.WriteString("[" + strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled) + "] ")
+	}
+	fmt.Fprintf(&s, "%d/%d (%d%%)", done, b.total, int(frac*100))
+	if done > 0 && done < b.total {
+		eta := time.Duration(float64(elapsed) / frac * (1 - frac))
+		fmt.Fprintf(&s, ", %s left", formatETA(eta))
+	}
+	return s.String()
+}
+
+// formatETA rounds a remaining time for display: seconds under a minute,
+// then minutes and seconds.
+func formatETA(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()+0.5))
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
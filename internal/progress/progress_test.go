@@ -0,0 +1,43 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBarLogsPeriodically(t *testing.T) {
+	var buf bytes.Buffer
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New(&buf, "indexing transcripts")
+	b.start, b.now = clock, func() time.Time { return clock }
+	b.SetTotal(400)
+
+	b.Add(100) // right away: nothing shown
+	if buf.Len() != 0 {
+		t.Fatalf("quick progress logged: %q", buf.String())
+	}
+	clock = clock.Add(11 * time.Second)
+	b.Add(100)
+	clock = clock.Add(time.Second)
+	b.Add(1) // within logEvery of the last line
+	b.Finish()
+
+	want := "indexing transcripts 200/400 (50%), 11s left\n"
+	if buf.String() != want {
+		t.Errorf("log = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestBarLine(t *testing.T) {
+	b := &Bar{label: "scanning", total: 10, done: 5}
+	got := b.line(2*time.Minute, true)
+	if !strings.HasPrefix(got, "scanning [===============               ] 5/10 (50%)") || !strings.HasSuffix(got, "2m00s left") {
+		t.Errorf("line = %q", got)
+	}
+	var nilBar *Bar
+	nilBar.SetTotal(1)
+	nilBar.Add(1)
+	nilBar.Finish()
+}
@@ -0,0 +1,24 @@
+package provider
+
+// Capability describes one provider aglogs reads and what its transcripts
+// support. Where its transcripts are read from is session.Sources.
+type Capability struct {
+	Name string `json:"name"`
+	// Streaming is true when 'aglogs stream' and 'tail' follow a live
+	// session as it is written (OpenCode is polled every second).
+	Streaming bool `json:"streaming"`
+	// Jobs is true when grove plan jobs are detected in its sessions.
+	Jobs bool `json:"jobs"`
+	// Tokens is true when its token usage and cost are reported.
+	Tokens bool `json:"tokens"`
+	// Reasoning is true when the model's reasoning is kept in transcripts.
+	Reasoning bool `json:"reasoning"`
+}
+
+// Capabilities lists every provider SelectSource has a source for.
+var Capabilities = []Capability{
+	{Name: "claude", Streaming: true, Jobs: true, Tokens: true, Reasoning: true},
+	{Name: "codex", Streaming: true, Jobs: true, Tokens: true, Reasoning: true},
+	{Name: "opencode", Streaming: true, Tokens: true},
+	{Name: "pi", Streaming: true, Jobs: true, Tokens: true, Reasoning: true},
+}
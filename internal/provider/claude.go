@@ -1,7 +1,6 @@
 package provider
 
 import (
-	"bufio"
 	"context"
 	"io"
 	"os"
@@ -19,7 +18,8 @@ func NewClaudeSource() *ClaudeSource {
 }
 
 func (s *ClaudeSource) Read(ctx context.Context, info *session.SessionInfo, opts ReadOptions) ([]transcript.UnifiedEntry, error) {
-	file, err := os.Open(info.LogFilePath)
+	// A line still being written is left out; the next read picks it up.
+	file, _, err := transcript.OpenTranscriptSnapshot(info.LogFilePath)
 	if err != nil {
 		return nil, err
 	}
@@ -33,10 +33,16 @@ func (s *ClaudeSource) Read(ctx context.Context, info *session.SessionInfo, opts
 		entries = append(entries, *entry)
 	}
 
+	if opts.IncludeSubagents {
+		entries = transcript.NestSubagents(entries, transcript.ReadClaudeSubagents(info.LogFilePath))
+	}
 	return entries, nil
 }
 
 func (s *ClaudeSource) Stream(ctx context.Context, info *session.SessionInfo) (<-chan transcript.UnifiedEntry, error) {
+	if transcript.IsCompressedPath(info.LogFilePath) {
+		return replayCompressed(ctx, s, info)
+	}
 	file, err := os.Open(info.LogFilePath)
 	if err != nil {
 		return nil, err
@@ -55,9 +61,9 @@ func (s *ClaudeSource) Stream(ctx context.Context, info *session.SessionInfo) (<
 		defer close(ch)
 		defer file.Close()
 
-		reader := bufio.NewReader(file)
+		reader := transcript.NewTailReader(file)
 		for {
-			line, err := reader.ReadBytes('\n')
+			line, err := reader.ReadLine()
 			if err == io.EOF {
 				// Flush any buffered entries (e.g. tool calls waiting for results).
 				// In streaming mode we emit eagerly rather than waiting for tool results.
@@ -102,10 +108,7 @@ func (s *ClaudeSource) Stream(ctx context.Context, info *session.SessionInfo) (<
 // scanNormalizeRange reads lines from a reader within a line range and normalizes them.
 // startLine and endLine are zero-based line indices. endLine < 0 means read to end.
 func scanNormalizeRange(r io.Reader, normalizer transcript.Normalizer, startLine, endLine int) []transcript.UnifiedEntry {
-	scanner := bufio.NewScanner(r)
-	const maxScanTokenSize = 1024 * 1024 // 1MB
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, maxScanTokenSize)
+	scanner := transcript.NewLineScanner(r)
 
 	var entries []transcript.UnifiedEntry
 	lineIndex := 0
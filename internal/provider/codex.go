@@ -1,7 +1,6 @@
 package provider
 
 import (
-	"bufio"
 	"context"
 	"io"
 	"os"
@@ -19,7 +18,8 @@ func NewCodexSource() *CodexSource {
 }
 
 func (s *CodexSource) Read(ctx context.Context, info *session.SessionInfo, opts ReadOptions) ([]transcript.UnifiedEntry, error) {
-	file, err := os.Open(info.LogFilePath)
+	// A line still being written is left out; the next read picks it up.
+	file, _, err := transcript.OpenTranscriptSnapshot(info.LogFilePath)
 	if err != nil {
 		return nil, err
 	}
@@ -31,6 +31,9 @@ func (s *CodexSource) Read(ctx context.Context, info *session.SessionInfo, opts
 }
 
 func (s *CodexSource) Stream(ctx context.Context, info *session.SessionInfo) (<-chan transcript.UnifiedEntry, error) {
+	if transcript.IsCompressedPath(info.LogFilePath) {
+		return replayCompressed(ctx, s, info)
+	}
 	file, err := os.Open(info.LogFilePath)
 	if err != nil {
 		return nil, err
@@ -49,9 +52,9 @@ func (s *CodexSource) Stream(ctx context.Context, info *session.SessionInfo) (<-
 		defer close(ch)
 		defer file.Close()
 
-		reader := bufio.NewReader(file)
+		reader := transcript.NewTailReader(file)
 		for {
-			line, err := reader.ReadBytes('\n')
+			line, err := reader.ReadLine()
 			if err == io.EOF {
 				if _, statErr := os.Stat(info.LogFilePath); statErr != nil {
 					return
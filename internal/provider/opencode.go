@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/grovetools/agentlogs/internal/opencode"
@@ -17,8 +18,20 @@ func NewOpenCodeSource() *OpenCodeSource {
 	return &OpenCodeSource{}
 }
 
+// openCodeAssembler assembles the session's transcript from the storage
+// directory holding its session info file (<storage>/session/<project>/
+// <id>.json), so sessions found under a configured source are read from
+// there, else from OpenCode's default storage.
+func openCodeAssembler(info *session.SessionInfo) (*opencode.Assembler, error) {
+	sessionDir := filepath.Dir(filepath.Dir(info.LogFilePath))
+	if info.LogFilePath != "" && filepath.Base(sessionDir) == "session" {
+		return opencode.NewAssemblerWithDir(filepath.Dir(sessionDir))
+	}
+	return opencode.NewAssembler()
+}
+
 func (s *OpenCodeSource) Read(ctx context.Context, info *session.SessionInfo, opts ReadOptions) ([]transcript.UnifiedEntry, error) {
-	assembler, err := opencode.NewAssembler()
+	assembler, err := openCodeAssembler(info)
 	if err != nil {
 		return nil, fmt.Errorf("creating OpenCode assembler: %w", err)
 	}
@@ -33,7 +46,7 @@ func (s *OpenCodeSource) Read(ctx context.Context, info *session.SessionInfo, op
 }
 
 func (s *OpenCodeSource) Stream(ctx context.Context, info *session.SessionInfo) (<-chan transcript.UnifiedEntry, error) {
-	assembler, err := opencode.NewAssembler()
+	assembler, err := openCodeAssembler(info)
 	if err != nil {
 		return nil, fmt.Errorf("creating OpenCode assembler: %w", err)
 	}
@@ -1,7 +1,6 @@
 package provider
 
 import (
-	"bufio"
 	"context"
 	"io"
 	"os"
@@ -25,7 +24,8 @@ func NewPiSource() *PiSource {
 }
 
 func (s *PiSource) Read(ctx context.Context, info *session.SessionInfo, opts ReadOptions) ([]transcript.UnifiedEntry, error) {
-	file, err := os.Open(info.LogFilePath)
+	// A line still being written is left out; the next read picks it up.
+	file, _, err := transcript.OpenTranscriptSnapshot(info.LogFilePath)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +56,9 @@ func (s *PiSource) Read(ctx context.Context, info *session.SessionInfo, opts Rea
 }
 
 func (s *PiSource) Stream(ctx context.Context, info *session.SessionInfo) (<-chan transcript.UnifiedEntry, error) {
+	if transcript.IsCompressedPath(info.LogFilePath) {
+		return replayCompressed(ctx, s, info)
+	}
 	file, err := os.Open(info.LogFilePath)
 	if err != nil {
 		return nil, err
@@ -74,9 +77,9 @@ func (s *PiSource) Stream(ctx context.Context, info *session.SessionInfo) (<-cha
 		defer close(ch)
 		defer file.Close()
 
-		reader := bufio.NewReader(file)
+		reader := transcript.NewTailReader(file)
 		for {
-			line, err := reader.ReadBytes('\n')
+			line, err := reader.ReadLine()
 			if err == io.EOF {
 				if _, statErr := os.Stat(info.LogFilePath); statErr != nil {
 					return
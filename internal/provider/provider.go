@@ -13,6 +13,10 @@ type ReadOptions struct {
 	MaxDiffLines int    // 0 = unlimited
 	StartLine    int    // Skip lines before this index (for job-scoped reads)
 	EndLine      int    // Stop at this line index (-1 = read to end)
+	// IncludeSubagents nests Claude Task sub-agent transcripts under the
+	// calls that spawned them (see transcript.NestSubagents). Other
+	// providers have no sub-agent transcripts and ignore it.
+	IncludeSubagents bool
 }
 
 // TranscriptSource provides read and stream access to agent transcripts
@@ -26,3 +30,25 @@ type TranscriptSource interface {
 	// The channel closes when the context is cancelled or the session ends.
 	Stream(ctx context.Context, info *session.SessionInfo) (<-chan transcript.UnifiedEntry, error)
 }
+
+// replayCompressed streams a compressed transcript. It is never appended
+// to, so there is nothing to tail: all of its entries are sent and the
+// channel closed.
+func replayCompressed(ctx context.Context, src TranscriptSource, info *session.SessionInfo) (<-chan transcript.UnifiedEntry, error) {
+	entries, err := src.Read(ctx, info, ReadOptions{DetailLevel: "full", EndLine: -1})
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan transcript.UnifiedEntry, 100)
+	go func() {
+		defer close(ch)
+		for _, entry := range entries {
+			select {
+			case ch <- entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
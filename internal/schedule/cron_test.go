@@ -0,0 +1,108 @@
+package schedule
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded; want an error", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	// 2026-10-16 is a Friday.
+	from := at("2026-10-16 10:30")
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"* * * * *", "2026-10-16 10:31"},
+		{"@hourly", "2026-10-16 11:00"},
+		{"@daily", "2026-10-17 00:00"},
+		{"0 7 * * 1-5", "2026-10-19 07:00"},
+		{"*/15 * * * *", "2026-10-16 10:45"},
+		{"30 10 * * *", "2026-10-17 10:30"},
+		{"0 9 1 * *", "2026-11-01 09:00"},
+		{"0 0 * * 7", "2026-10-18 00:00"},
+		{"0 0 13 * 5", "2026-10-23 00:00"},
+		{"0 0 29 2 *", "2028-02-29 00:00"},
+	}
+	for _, tt := range tests {
+		spec, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		if got := spec.Next(from); !got.Equal(at(tt.want)) {
+			t.Errorf("Next(%q) = %s; want %s", tt.expr, got.Format("2006-01-02 15:04"), tt.want)
+		}
+	}
+
+	never, _ := Parse("0 0 30 2 *")
+	if got := never.Next(from); !got.IsZero() {
+		t.Errorf("Next(Feb 30) = %s; want zero", got)
+	}
+}
+
+func TestStateDue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	spec, _ := Parse("0 7 * * *")
+	start := time.Date(2026, 10, 16, 6, 0, 0, 0, time.Local)
+
+	s := LoadState(path)
+	if s.Due("digest", spec, start) {
+		t.Error("a task seen for the first time should not be due")
+	}
+	if s.Due("digest", spec, start.Add(59*time.Minute)) {
+		t.Error("due before its scheduled time")
+	}
+	// Two days of missed runs are caught up with one.
+	late := start.Add(50 * time.Hour)
+	if !s.Due("digest", spec, late) {
+		t.Fatal("not due after its scheduled time")
+	}
+	s.Ran("digest", late)
+	s.Ran("gone", late)
+	if err := s.Save([]string{"digest"}); err != nil {
+		t.Fatal(err)
+	}
+
+	s = LoadState(path)
+	if _, ok := s.LastRun["gone"]; ok {
+		t.Error("Save kept a task that is no longer configured")
+	}
+	if s.Due("digest", spec, late.Add(time.Minute)) {
+		t.Error("due again right after running")
+	}
+	if want := time.Date(2026, 10, 19, 7, 0, 0, 0, time.Local); !s.Next("digest", spec, late).Equal(want) {
+		t.Errorf("Next = %s; want %s", s.Next("digest", spec, late), want)
+	}
+}
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "schedule.json")
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Lock(path); err != ErrLocked {
+		t.Fatalf("second Lock: got %v, want ErrLocked", err)
+	}
+	unlock()
+	unlock, err = Lock(path)
+	if err != nil {
+		t.Fatalf("Lock after unlock: %v", err)
+	}
+	unlock()
+}
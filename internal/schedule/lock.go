@@ -0,0 +1,35 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// ErrLocked is returned by Lock while another process holds the lock.
+var ErrLocked = errors.New("another scheduled run is in progress")
+
+// Lock takes the lock file beside the state at statePath, so schedulers in
+// overlapping processes (cron, 'schedule run --loop', the monitor daemon)
+// don't each load the state and run a task twice. It fails with ErrLocked
+// rather than wait. The returned function releases the lock.
+func Lock(statePath string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(statePath), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(statePath+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the schedule lock: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("failed to take the schedule lock: %w", err)
+	}
+	// Closing the file releases the lock.
+	return func() { f.Close() }, nil
+}
@@ -0,0 +1,256 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	grovelogging "github.com/grovetools/core/logging"
+
+	aglogs_config "github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/notify"
+)
+
+var ulog = grovelogging.NewUnifiedLogger("grove-agent-logs.schedule")
+
+// maxWebhookOutput caps the task output posted to a webhook.
+const maxWebhookOutput = 32 * 1024
+
+var taskNameRe = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Task is a configured task with its schedule parsed and its outputs
+// resolved.
+type Task struct {
+	aglogs_config.ScheduledTask
+	Spec       *Spec
+	OutputDir  string
+	WebhookURL string
+}
+
+// LoadTasks validates the configured tasks.
+func LoadTasks(cfg aglogs_config.ScheduleConfig) ([]Task, error) {
+	var tasks []Task
+	seen := make(map[string]bool)
+	for i, c := range cfg.Tasks {
+		if !taskNameRe.MatchString(c.Name) {
+			return nil, fmt.Errorf("schedule.tasks[%d]: invalid name %q (use letters, digits, '.', '_' and '-')", i, c.Name)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("schedule.tasks: duplicate task name %q", c.Name)
+		}
+		seen[c.Name] = true
+		if len(c.Command) == 0 {
+			return nil, fmt.Errorf("schedule task %s: command is empty", c.Name)
+		}
+		spec, err := Parse(c.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("schedule task %s: %w", c.Name, err)
+		}
+		tasks = append(tasks, Task{
+			ScheduledTask: c,
+			Spec:          spec,
+			OutputDir:     taskSetting(c.OutputDir, cfg.OutputDir),
+			WebhookURL:    taskSetting(c.WebhookURL, cfg.WebhookURL),
+		})
+	}
+	return tasks, nil
+}
+
+// taskSetting returns a task's own setting, the section's when the task has
+// none, or "" when the task's is "-".
+func taskSetting(own, section string) string {
+	switch own {
+	case "-":
+		return ""
+	case "":
+		return section
+	}
+	return own
+}
+
+// Runner runs scheduled tasks as aglogs commands, recording their runs in
+// the state at StatePath.
+type Runner struct {
+	Tasks []Task
+	// Exe is the aglogs executable the tasks' commands are run with.
+	Exe       string
+	StatePath string
+}
+
+// Run runs the tasks due at now, or, when names are given, those tasks
+// whatever their schedule. It holds the schedule lock while it runs, and
+// fails with ErrLocked when another process holds it.
+func (r *Runner) Run(ctx context.Context, now time.Time, names ...string) error {
+	unlock, err := Lock(r.StatePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state := LoadState(r.StatePath)
+	var all, failed []string
+	for _, t := range r.Tasks {
+		all = append(all, t.Name)
+		run := slices.Contains(names, t.Name)
+		if len(names) == 0 {
+			run = state.Due(t.Name, t.Spec, now)
+		}
+		if !run {
+			continue
+		}
+		if err := r.runTask(ctx, t, now); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failed = append(failed, t.Name)
+		}
+		// A failed run counts as a run, so a broken task waits for its
+		// next scheduled time rather than retrying every minute.
+		state.Ran(t.Name, now)
+	}
+	if err := state.Save(all); err != nil {
+		return fmt.Errorf("failed to save the schedule state: %w", err)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("scheduled tasks failed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// RunConfigured runs the tasks in the schedule section of the aglogs config
+// as they fall due, until ctx is cancelled, for a daemon standing in for
+// cron or 'aglogs schedule run --loop'. A daemon isn't aglogs itself, so
+// the tasks' commands run the aglogs binary from PATH. It returns at once
+// when no tasks are configured.
+func RunConfigured(ctx context.Context) error {
+	tasks, err := LoadTasks(aglogs_config.Load().Schedule)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	exe, err := exec.LookPath("aglogs")
+	if err != nil {
+		return fmt.Errorf("failed to find the aglogs executable: %w", err)
+	}
+	ulog.Info("Running scheduled tasks").Field("tasks", len(tasks)).Emit()
+	runner := &Runner{Tasks: tasks, Exe: exe, StatePath: DefaultStatePath()}
+	runner.Loop(ctx)
+	return nil
+}
+
+// Loop runs the due tasks every minute until ctx is cancelled. A minute
+// another process is running them is skipped.
+func (r *Runner) Loop(ctx context.Context) {
+	for {
+		err := r.Run(ctx, time.Now())
+		switch {
+		case errors.Is(err, ErrLocked):
+			ulog.Debug("Skipped scheduled run").Err(err).Emit()
+		case err != nil && ctx.Err() == nil:
+			ulog.Warn("Scheduled run failed").Err(err).Emit()
+		}
+		// Wake at the start of the next minute, when the next tasks fall
+		// due.
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// runTask runs t and delivers its output.
+func (r *Runner) runTask(ctx context.Context, t Task, now time.Time) error {
+	var stdout, stderr bytes.Buffer
+	c := exec.CommandContext(ctx, r.Exe, t.Command...)
+	c.Stdout, c.Stderr = &stdout, &stderr
+	runErr := c.Run()
+	if runErr != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			runErr = fmt.Errorf("%w: %s", runErr, lastLine(msg))
+		}
+	}
+
+	var deliverErr error
+	if t.OutputDir != "" {
+		if path, err := writeTaskOutput(t, stdout.Bytes(), now); err != nil {
+			deliverErr = fmt.Errorf("failed to write output: %w", err)
+		} else {
+			ulog.Debug("Wrote task output").Field("task", t.Name).Field("path", path).Emit()
+		}
+	}
+	if t.WebhookURL != "" {
+		ev := notify.Event{Event: notify.EventTaskCompleted, Text: fmt.Sprintf("Scheduled task %s finished", t.Name), Since: now}
+		if runErr != nil {
+			ev.Event = notify.EventTaskFailed
+			ev.Text = fmt.Sprintf("Scheduled task %s failed: %v", t.Name, runErr)
+		}
+		ev.Message = stdout.String()
+		if len(ev.Message) > maxWebhookOutput {
+			ev.Message = ev.Message[:maxWebhookOutput] + "\n[output truncated]"
+		}
+		if err := (&notify.Webhook{URL: t.WebhookURL}).Send(ctx, ev); err != nil && deliverErr == nil {
+			deliverErr = fmt.Errorf("failed to post output: %w", err)
+		}
+	}
+
+	err := runErr
+	if err == nil {
+		err = deliverErr
+	}
+	if err != nil {
+		ulog.Warn("Scheduled task failed").
+			Field("task", t.Name).
+			Err(err).
+			Pretty(fmt.Sprintf("Task %s failed: %v", t.Name, err)).
+			Emit()
+		return err
+	}
+	ulog.Info("Ran scheduled task").
+		Field("task", t.Name).
+		Pretty(fmt.Sprintf("Ran task %s", t.Name)).
+		Emit()
+	return nil
+}
+
+// writeTaskOutput writes a run's output to the task's output directory.
+func writeTaskOutput(t Task, out []byte, now time.Time) (string, error) {
+	dir := t.OutputDir
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, rest)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	ext := ".txt"
+	if slices.Contains(t.Command, "--json") {
+		ext = ".json"
+	}
+	path := filepath.Join(dir, t.Name+"-"+now.Format("20060102-1504")+ext)
+	return path, os.WriteFile(path, out, 0o644)
+}
+
+// lastLine returns the last line of s, where commands put their error.
+func lastLine(s string) string {
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grovetools/core/pkg/paths"
+)
+
+// State records when each task last ran, so a task runs once per scheduled
+// time however often the scheduler checks, and once to catch up on the runs
+// it missed while nothing checked.
+type State struct {
+	LastRun map[string]time.Time `json:"lastRun"`
+
+	path string
+}
+
+// DefaultStatePath returns the location of the shared state.
+func DefaultStatePath() string {
+	return filepath.Join(paths.StateDir(), "aglogs", "schedule.json")
+}
+
+// LoadState reads the state at path. A missing or unreadable state is
+// empty.
+func LoadState(path string) *State {
+	s := &State{LastRun: make(map[string]time.Time), path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	var stored State
+	if json.Unmarshal(data, &stored) == nil && stored.LastRun != nil {
+		s.LastRun = stored.LastRun
+	}
+	return s
+}
+
+// Due reports whether the task named name is due at now. A task the state
+// has never seen is not due: it starts counting from now, so adding a
+// task does not run it at once.
+func (s *State) Due(name string, spec *Spec, now time.Time) bool {
+	last, ok := s.LastRun[name]
+	if !ok {
+		s.LastRun[name] = now
+		return false
+	}
+	next := spec.Next(last)
+	return !next.IsZero() && !next.After(now)
+}
+
+// Next returns when the task named name runs next, or the zero time.
+func (s *State) Next(name string, spec *Spec, now time.Time) time.Time {
+	last, ok := s.LastRun[name]
+	if !ok || last.After(now) {
+		last = now
+	}
+	return spec.Next(last)
+}
+
+// Ran records that the task named name ran at now.
+func (s *State) Ran(name string, now time.Time) {
+	s.LastRun[name] = now
+}
+
+// Save writes the state, forgetting tasks not in names.
+func (s *State) Save(names []string) error {
+	keep := make(map[string]bool, len(names))
+	for _, n := range names {
+		keep[n] = true
+	}
+	for name := range s.LastRun {
+		if !keep[name] {
+			delete(s.LastRun, name)
+		}
+	}
+	if s.path == "" {
+		return errors.New("schedule state has no path")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,338 @@
+// Package search finds transcript entries containing a query across many
+// sessions. Transcripts are read in parallel and the hits ranked by how
+// well they match, or listed newest first.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/codelang"
+	"github.com/grovetools/agentlogs/pkg/redact"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// Sort orders.
+const (
+	SortRelevance = "relevance"
+	SortTime      = "time"
+)
+
+// Sorts lists the accepted sort orders.
+var Sorts = []string{SortRelevance, SortTime}
+
+// Search limits and ranking weights.
+const (
+	DefaultLimit      = 50
+	maxHitsPerSession = 5
+	// DefaultRadius is how much text a snippet keeps on each side of its
+	// match, in bytes.
+	DefaultRadius = 80
+	// recencyHalfLife halves the score of a hit for every month its
+	// session is older than the newest hit's.
+	recencyHalfLife = 30 * 24 * time.Hour
+)
+
+// roleWeights scale a hit's score by who wrote the entry: a user prompt
+// naming the query says more about a session than a tool's output
+// mentioning it.
+var roleWeights = map[string]float64{
+	"user":      2,
+	"assistant": 1,
+	"system":    0.5,
+}
+
+// Options controls a search.
+type Options struct {
+	// Query is matched case-insensitively as a substring.
+	Query string
+	// Lang, when set, keeps only entries whose code is mostly in that
+	// language (see codelang.Normalize); Query may then be empty.
+	Lang string
+	// Limit caps the hits returned; 0 means DefaultLimit.
+	Limit int
+	// Sort is SortRelevance (the default) or SortTime.
+	Sort string
+	// Radius is how much text each snippet keeps on each side of the
+	// entry's first match; 0 means DefaultRadius.
+	Radius int
+	// Redactor, when set, redacts every snippet. A hit whose match was
+	// redacted away is dropped, so search cannot confirm a secret.
+	Redactor redact.Redactor
+}
+
+// Hit is one transcript entry containing the query.
+type Hit struct {
+	Session   string `json:"session"`
+	Provider  string `json:"provider,omitempty"`
+	Project   string `json:"project,omitempty"`
+	Seq       int    `json:"seq"`
+	Role      string `json:"role"`
+	Language  string `json:"language,omitempty"`
+	Snippet   string `json:"snippet"`
+	StartedAt string `json:"startedAt,omitempty"`
+	// Matches counts the query's occurrences in the entry.
+	Matches int `json:"matches"`
+	// Score ranks the hit under SortRelevance; higher is better.
+	Score float64 `json:"score"`
+
+	at time.Time // the entry's time, else its session's start
+}
+
+// Run searches sessions and returns the best opts.Limit hits, at most
+// maxHitsPerSession from any one session. With SortTime the newest hits
+// come first; sessions are then read in batches, newest first, stopping
+// once a batch fills the limit.
+func Run(ctx context.Context, sessions []session.SessionInfo, opts Options) ([]Hit, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if opts.Sort == SortTime {
+		sessions = append([]session.SessionInfo(nil), sessions...)
+		sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].StartedAt.After(sessions[j].StartedAt) })
+	}
+
+	batch := len(sessions)
+	if opts.Sort == SortTime {
+		batch = max(4*runtime.GOMAXPROCS(0), 1)
+	}
+	hits := []Hit{}
+	for start := 0; start < len(sessions); start += batch {
+		chunk := sessions[start:min(start+batch, len(sessions))]
+		found := make([][]Hit, len(chunk))
+		errs := make([]error, len(chunk))
+		session.ParallelEach(len(chunk), func(i int) {
+			if ctx.Err() == nil {
+				found[i], errs[i] = searchSession(ctx, &chunk[i], opts)
+			}
+		})
+		for i := range chunk {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			hits = append(hits, found[i]...)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if opts.Sort == SortTime && len(hits) >= limit {
+			break
+		}
+	}
+
+	rank(hits, opts.Sort)
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// searchSession returns a session's maxHitsPerSession best hits, or its
+// latest under SortTime. Unreadable transcripts yield no hits.
+func searchSession(ctx context.Context, info *session.SessionInfo, opts Options) ([]Hit, error) {
+	if info.LogFilePath == "" {
+		return nil, nil
+	}
+	entries, err := provider.SelectSource(info, nil).Read(ctx, info, provider.ReadOptions{DetailLevel: "full", EndLine: -1})
+	if err != nil {
+		return nil, nil
+	}
+	needle := strings.ToLower(opts.Query)
+	var hits []Hit
+	for seq, entry := range entries {
+		entryLang := codelang.Entry(entry)
+		if opts.Lang != "" && entryLang != opts.Lang {
+			continue
+		}
+		text := EntryText(entry)
+		lower := strings.ToLower(text)
+		at := strings.Index(lower, needle)
+		if at < 0 {
+			continue
+		}
+		hit := Hit{
+			Session:  info.SessionID,
+			Provider: info.Provider,
+			Project:  info.ProjectName,
+			Seq:      seq,
+			Role:     entry.Role,
+			Language: entryLang,
+			Snippet:  Snippet(text, at, len(needle), opts.Radius),
+			Matches:  1,
+			at:       entry.Timestamp,
+		}
+		if needle != "" {
+			hit.Matches = strings.Count(lower, needle)
+		}
+		if opts.Redactor != nil {
+			if hit.Snippet, err = opts.Redactor.Redact(ctx, hit.Snippet); err != nil {
+				return nil, err
+			}
+			if !strings.Contains(strings.ToLower(hit.Snippet), needle) {
+				continue
+			}
+		}
+		if !info.StartedAt.IsZero() {
+			hit.StartedAt = info.StartedAt.Format("2006-01-02 15:04")
+			if hit.at.IsZero() {
+				hit.at = info.StartedAt
+			}
+		}
+		hit.Score = matchScore(hit)
+		hits = append(hits, hit)
+	}
+	if opts.Sort == SortTime {
+		slices.Reverse(hits)
+	} else {
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	}
+	if len(hits) > maxHitsPerSession {
+		hits = hits[:maxHitsPerSession]
+	}
+	return hits, nil
+}
+
+// matchScore weighs a hit by how often the query occurs, with diminishing
+// returns, and by its entry's role.
+func matchScore(h Hit) float64 {
+	weight, ok := roleWeights[h.Role]
+	if !ok {
+		weight = 1
+	}
+	return weight * (1 + math.Log(float64(h.Matches)))
+}
+
+// rank orders hits newest first (SortTime) or by score (SortRelevance).
+// Relevance decays with a hit's age relative to the newest hit, so a
+// strong old match can still outrank a weak recent one.
+func rank(hits []Hit, order string) {
+	if order == SortTime {
+		sort.SliceStable(hits, func(i, j int) bool {
+			if !hits[i].at.Equal(hits[j].at) {
+				return hits[i].at.After(hits[j].at)
+			}
+			return hits[i].Seq > hits[j].Seq
+		})
+		return
+	}
+	var newest time.Time
+	for _, h := range hits {
+		if h.at.After(newest) {
+			newest = h.at
+		}
+	}
+	for i := range hits {
+		if !hits[i].at.IsZero() {
+			age := newest.Sub(hits[i].at)
+			hits[i].Score *= math.Pow(0.5, float64(age)/float64(recencyHalfLife))
+		}
+		hits[i].Score = math.Round(hits[i].Score*1000) / 1000
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+}
+
+// Snippet returns the text within radius bytes of a match, on one line. A
+// radius of 0 means DefaultRadius.
+func Snippet(text string, at, n, radius int) string {
+	if radius <= 0 {
+		radius = DefaultRadius
+	}
+	// at indexes the lowercased text, which can differ in length from text
+	// for a few scripts; clamp rather than trust it.
+	start, end := min(max(0, at-radius), len(text)), min(len(text), at+n+radius)
+	// Stay on rune boundaries.
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	s := strings.Join(strings.Fields(text[start:end]), " ")
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(text) {
+		s += "…"
+	}
+	return s
+}
+
+// stopWords are the words of a query not worth highlighting on their own.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "from": true, "if": true,
+	"in": true, "into": true, "is": true, "it": true, "not": true, "of": true,
+	"on": true, "or": true, "so": true, "that": true, "the": true, "then": true,
+	"this": true, "to": true, "was": true, "were": true, "with": true,
+}
+
+// HighlightPattern matches what to highlight in a snippet for query: the
+// query itself and, for a query of several words, each word that is not
+// a stop word, case-insensitively and preferring the longest match. It is
+// nil for an empty query.
+func HighlightPattern(query string) *regexp.Regexp {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	terms := []string{query}
+	if words := strings.Fields(query); len(words) > 1 {
+		for _, w := range words {
+			if !stopWords[strings.ToLower(w)] && !slices.Contains(terms, w) {
+				terms = append(terms, w)
+			}
+		}
+	}
+	sort.SliceStable(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	for i, t := range terms {
+		terms[i] = regexp.QuoteMeta(t)
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(terms, "|"))
+}
+
+// EntryText is the searchable text of an entry: prose, reasoning,
+// commands, tool inputs and outputs.
+func EntryText(entry transcript.UnifiedEntry) string {
+	var b strings.Builder
+	for _, part := range entry.Parts {
+		switch c := part.Content.(type) {
+		case transcript.UnifiedTextContent:
+			b.WriteString(c.Text)
+		case transcript.UnifiedReasoning:
+			b.WriteString(c.Text)
+		case transcript.UnifiedToolCall:
+			b.WriteString(c.Name)
+			if input, err := json.Marshal(c.Input); err == nil {
+				b.WriteString(" ")
+				b.Write(input)
+			}
+			b.WriteString(" ")
+			b.WriteString(c.Output)
+		case transcript.UnifiedToolResult:
+			b.WriteString(c.Output)
+		case transcript.UnifiedCommand:
+			b.WriteString(c.Name + " " + c.Args)
+		case transcript.UnifiedCommandOutput:
+			b.WriteString(c.Output)
+		case transcript.UnifiedHook:
+			b.WriteString(c.Event + " " + c.Command + " " + c.Output)
+		default:
+			if data, err := json.Marshal(c); err == nil {
+				b.Write(data)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRank(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	hits := []Hit{
+		{Session: "old-prompt", Role: "user", Matches: 3, at: now.Add(-60 * 24 * time.Hour)},
+		{Session: "new-tool", Role: "system", Matches: 1, at: now},
+		{Session: "new-reply", Role: "assistant", Matches: 2, at: now.Add(-time.Hour)},
+		{Session: "new-prompt", Role: "user", Matches: 1, at: now.Add(-2 * time.Hour)},
+	}
+	for i := range hits {
+		hits[i].Score = matchScore(hits[i])
+	}
+
+	byScore := append([]Hit(nil), hits...)
+	rank(byScore, SortRelevance)
+	want := []string{"new-prompt", "new-reply", "old-prompt", "new-tool"}
+	for i, h := range byScore {
+		if h.Session != want[i] {
+			t.Errorf("relevance[%d] = %s (score %v), want %s", i, h.Session, h.Score, want[i])
+		}
+	}
+
+	byTime := append([]Hit(nil), hits...)
+	rank(byTime, SortTime)
+	want = []string{"new-tool", "new-reply", "new-prompt", "old-prompt"}
+	for i, h := range byTime {
+		if h.Session != want[i] {
+			t.Errorf("time[%d] = %s, want %s", i, h.Session, want[i])
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := "alpha\n\nthe needle  is\there"
+	if got, want := Snippet(text, strings.Index(text, "needle"), len("needle"), 0), "alpha the needle is here"; got != want {
+		t.Errorf("Snippet = %q, want %q", got, want)
+	}
+	long := strings.Repeat("é", 100) + " needle"
+	got := Snippet(long, strings.Index(long, "needle"), len("needle"), 0)
+	if !strings.HasPrefix(got, "…é") || !strings.HasSuffix(got, " needle") {
+		t.Errorf("Snippet of a late match = %q, want it cut on a rune boundary after an ellipsis", got)
+	}
+}
+
+func TestHighlightPattern(t *testing.T) {
+	mark := func(s string) string { return "[" + s + "]" }
+	tests := []struct {
+		query, text, want string
+	}{
+		{"needle", "a Needle and a needle", "a [Needle] and a [needle]"},
+		{"flaky test", "the flaky test is a test that flakes", "the [flaky test] is a [test] that flakes"},
+		{"fix the bug", "fix the bug in the parser, then the bug tracker", "[fix the bug] in the parser, then the [bug] tracker"},
+		{"a.b", "a.b axb", "[a.b] axb"},
+	}
+	for _, tt := range tests {
+		if got := HighlightPattern(tt.query).ReplaceAllStringFunc(tt.text, mark); got != tt.want {
+			t.Errorf("highlight %q in %q = %q, want %q", tt.query, tt.text, got, tt.want)
+		}
+	}
+	if HighlightPattern("  ") != nil {
+		t.Error("HighlightPattern of a blank query should be nil")
+	}
+}
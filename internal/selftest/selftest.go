@@ -0,0 +1,228 @@
+// Package selftest runs the scan → normalize → render pipeline against a
+// synthetic session for every supported provider format. The sessions live
+// in an embedded home directory that mirrors each agent's on-disk layout;
+// Run copies it to a temp dir and points HOME (and the grove dirs) at it for
+// the duration of the test.
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/display"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+//go:embed all:home
+var homeFS embed.FS
+
+// Every synthetic session has one user prompt and one assistant reply
+// containing these markers.
+const (
+	promptMarker = "aglogs selftest prompt"
+	replyMarker  = "aglogs selftest reply"
+)
+
+// fixture is one synthetic session in the embedded home directory.
+type fixture struct {
+	Provider  string
+	SessionID string
+}
+
+var fixtures = []fixture{
+	{Provider: "claude", SessionID: "7d1c9a52-0000-4000-8000-5e1f7e570001"},
+	{Provider: "codex", SessionID: "7d1c9a52-0000-4000-8000-5e1f7e570002"},
+	{Provider: "pi", SessionID: "7d1c9a52-0000-4000-8000-5e1f7e570003"},
+	{Provider: "opencode", SessionID: "ses_selftest0001"},
+}
+
+// Stage names the pipeline step a result failed at.
+type Stage string
+
+const (
+	StageScan      Stage = "scan"
+	StageNormalize Stage = "normalize"
+	StageRender    Stage = "render"
+)
+
+// Result is the outcome for one provider.
+type Result struct {
+	Provider string        `json:"provider"`
+	Passed   bool          `json:"passed"`
+	Stage    Stage         `json:"stage,omitempty"` // failing stage; empty on success
+	Error    string        `json:"error,omitempty"`
+	Entries  int           `json:"entries"`
+	Duration time.Duration `json:"durationNs"`
+}
+
+// isolatedEnv lists the variables Run overrides so that the scanner and the
+// provider readers only see the synthetic home.
+var isolatedEnv = []string{"HOME", "GROVE_HOME", "PI_CODING_AGENT_DIR", "XDG_DATA_HOME", "XDG_STATE_HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME"}
+
+// Run materializes the synthetic sessions and checks each provider. The
+// process environment is modified while Run executes and restored before it
+// returns, so it must not run concurrently with other work that reads HOME.
+func Run(ctx context.Context) ([]Result, error) {
+	tmp, err := os.MkdirTemp("", "aglogs-selftest-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	home := filepath.Join(tmp, "home")
+	if err := writeHome(home); err != nil {
+		return nil, fmt.Errorf("failed to write synthetic sessions: %w", err)
+	}
+
+	// A global config with one empty grove keeps workspace discovery quiet.
+	groveHome := filepath.Join(tmp, "grove")
+	configDir := filepath.Join(groveHome, "config", "grove")
+	workspaces := filepath.Join(tmp, "workspaces")
+	for _, dir := range []string{configDir, workspaces} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	groveConfig := fmt.Sprintf("groves:\n  selftest:\n    path: %s\n", workspaces)
+	if err := os.WriteFile(filepath.Join(configDir, "grove.yml"), []byte(groveConfig), 0o644); err != nil {
+		return nil, err
+	}
+
+	restore := isolate(home, groveHome)
+	defer restore()
+
+	start := time.Now()
+	sessions, scanErr := session.NewScannerWithoutDaemon().Scan()
+	scanTime := time.Since(start)
+
+	results := make([]Result, 0, len(fixtures))
+	for _, fx := range fixtures {
+		res := check(ctx, fx, sessions, scanErr)
+		res.Duration += scanTime
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func writeHome(dest string) error {
+	return fs.WalkDir(homeFS, "home", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, strings.TrimPrefix(path, "home"))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		data, err := homeFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+}
+
+func isolate(home, groveHome string) (restore func()) {
+	saved := make(map[string]*string, len(isolatedEnv))
+	for _, key := range isolatedEnv {
+		if v, ok := os.LookupEnv(key); ok {
+			saved[key] = &v
+		} else {
+			saved[key] = nil
+		}
+		os.Unsetenv(key)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("GROVE_HOME", groveHome)
+
+	return func() {
+		for key, v := range saved {
+			if v == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *v)
+			}
+		}
+	}
+}
+
+func check(ctx context.Context, fx fixture, sessions []session.SessionInfo, scanErr error) Result {
+	res := Result{Provider: fx.Provider}
+	start := time.Now()
+
+	fail := func(stage Stage, format string, args ...interface{}) Result {
+		res.Stage = stage
+		res.Error = fmt.Sprintf(format, args...)
+		res.Duration = time.Since(start)
+		return res
+	}
+
+	// --- Scan ---
+	if scanErr != nil {
+		return fail(StageScan, "scan failed: %v", scanErr)
+	}
+	var info *session.SessionInfo
+	for i := range sessions {
+		if sessions[i].SessionID == fx.SessionID {
+			info = &sessions[i]
+			break
+		}
+	}
+	if info == nil {
+		return fail(StageScan, "session %s not found by scanner", fx.SessionID)
+	}
+	if info.Provider != fx.Provider {
+		return fail(StageScan, "session scanned as provider %q", info.Provider)
+	}
+
+	// --- Normalize ---
+	entries, err := provider.SelectSource(info, nil).Read(ctx, info, provider.ReadOptions{DetailLevel: "full", EndLine: -1})
+	if err != nil {
+		return fail(StageNormalize, "read failed: %v", err)
+	}
+	res.Entries = len(entries)
+	if !hasText(entries, "user", promptMarker) {
+		return fail(StageNormalize, "user prompt missing from %d normalized entries", len(entries))
+	}
+	if !hasText(entries, "assistant", replyMarker) {
+		return fail(StageNormalize, "assistant reply missing from %d normalized entries", len(entries))
+	}
+
+	// --- Render ---
+	for _, style := range []display.RenderStyle{display.StyleTerminal, display.StyleMarkdown} {
+		var buf bytes.Buffer
+		opts := display.RenderOptions{Style: style, DetailLevel: "full"}
+		if err := display.RenderUnifiedTranscript(&buf, entries, opts, display.DefaultToolFormatters()); err != nil {
+			return fail(StageRender, "%s render failed: %v", style, err)
+		}
+		if !strings.Contains(buf.String(), promptMarker) || !strings.Contains(buf.String(), replyMarker) {
+			return fail(StageRender, "%s render is missing transcript text", style)
+		}
+	}
+
+	res.Passed = true
+	res.Duration = time.Since(start)
+	return res
+}
+
+func hasText(entries []transcript.UnifiedEntry, role, marker string) bool {
+	for _, entry := range entries {
+		if entry.Role != role {
+			continue
+		}
+		for _, part := range entry.Parts {
+			if part.Type == "text" && strings.Contains(transcript.TextOf(part), marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
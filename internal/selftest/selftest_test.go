@@ -0,0 +1,28 @@
+package selftest
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestRunPassesForEveryProvider(t *testing.T) {
+	home := os.Getenv("HOME")
+
+	results, err := Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(fixtures) {
+		t.Fatalf("got %d results, want %d", len(results), len(fixtures))
+	}
+	for _, r := range results {
+		if !r.Passed {
+			t.Errorf("%s failed at %s: %s", r.Provider, r.Stage, r.Error)
+		}
+	}
+
+	if got := os.Getenv("HOME"); got != home {
+		t.Errorf("HOME not restored: got %q, want %q", got, home)
+	}
+}
@@ -0,0 +1,260 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grovetools/core/logging"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/search"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/codelang"
+	"github.com/grovetools/agentlogs/pkg/redact"
+)
+
+// sessionsTTL is how long a session listing is reused before rescanning.
+const sessionsTTL = 15 * time.Second
+
+// Entry pages hold defaultPageSize entries unless the client asks for
+// fewer, or more up to maxPageSize.
+const (
+	defaultPageSize = 200
+	maxPageSize     = 1000
+)
+
+// listSessions returns the known sessions newest first, rescanning at most
+// every sessionsTTL.
+func (s *Server) listSessions() ([]session.SessionInfo, error) {
+	if s.Sessions == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions != nil && time.Since(s.sessionsAt) < sessionsTTL {
+		return s.sessions, nil
+	}
+	sessions, err := s.Sessions()
+	if err != nil {
+		return nil, err
+	}
+	sorted := make([]session.SessionInfo, 0, len(sessions))
+	for _, info := range sessions {
+		if info.SessionID != "" && info.SessionID != "unknown" {
+			sorted = append(sorted, info)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartedAt.After(sorted[j].StartedAt) })
+	s.sessions, s.sessionsAt = sorted, time.Now()
+	return sorted, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.listSessions()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// X-Total-Count lets clients page with offset and limit.
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(sessions)))
+	if n, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && n > 0 {
+		sessions = sessions[min(n, len(sessions)):]
+	}
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n >= 0 && n < len(sessions) {
+		sessions = sessions[:n]
+	}
+	// The listing is cached; redact copies of the sessions sent.
+	page := make([]session.SessionInfo, len(sessions))
+	for i, info := range sessions {
+		if page[i], err = s.redactSession(r, info); err != nil {
+			logging.NewLogger("aglogs-serve").WithError(err).Error("Session redaction failed")
+			http.Error(w, "redaction failed", http.StatusInternalServerError)
+			return
+		}
+	}
+	if !s.recordAccess(w, r, AuditRecord{Action: "list"}) {
+		return
+	}
+	writeJSON(w, page)
+}
+
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	info, err := s.resolve(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	redacted, err := s.redactSession(r, *info)
+	if err != nil {
+		logging.NewLogger("aglogs-serve").WithError(err).Error("Session redaction failed")
+		http.Error(w, "redaction failed", http.StatusInternalServerError)
+		return
+	}
+	if !s.recordAccess(w, r, AuditRecord{Action: "session", Session: info.SessionID}) {
+		return
+	}
+	writeJSON(w, redacted)
+}
+
+// redactSession redacts the transcript text a session's metadata carries:
+// its preview of the first prompt, and its title, which falls back to it.
+func (s *Server) redactSession(r *http.Request, info session.SessionInfo) (session.SessionInfo, error) {
+	if s.Redactor == nil {
+		return info, nil
+	}
+	for _, text := range []*string{&info.Title, &info.Preview} {
+		if *text == "" {
+			continue
+		}
+		var err error
+		if *text, err = s.Redactor.Redact(r.Context(), *text); err != nil {
+			return info, err
+		}
+	}
+	return info, nil
+}
+
+// entriesPage is one page of a session's transcript.
+type entriesPage struct {
+	Session string  `json:"session"`
+	Entries []Event `json:"entries"`
+	// Total is the number of entries in the transcript.
+	Total int `json:"total"`
+	// Next is the after value that fetches the following page; it is
+	// omitted on the last page.
+	Next *int `json:"next,omitempty"`
+}
+
+// handleEntries returns the entries after Seq ?after= (all by default), at
+// most ?limit= of them.
+func (s *Server) handleEntries(w http.ResponseWriter, r *http.Request) {
+	after, err := parseAfter(r.URL.Query().Get("after"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit := defaultPageSize
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			http.Error(w, fmt.Sprintf("invalid limit %q", v), http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, maxPageSize)
+	}
+	info, err := s.resolve(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	entries, err := provider.SelectSource(info, nil).Read(r.Context(), info, provider.ReadOptions{DetailLevel: "full", EndLine: -1})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	page := entriesPage{Session: info.SessionID, Entries: []Event{}, Total: len(entries)}
+	start := min(after+1, len(entries))
+	end := min(start+limit, len(entries))
+	for seq := start; seq < end; seq++ {
+		entry, err := redact.Entry(r.Context(), s.Redactor, entries[seq])
+		if err != nil {
+			logging.NewLogger("aglogs-serve").WithError(err).Error("Entry redaction failed")
+			http.Error(w, "redaction failed", http.StatusInternalServerError)
+			return
+		}
+		page.Entries = append(page.Entries, Event{Session: info.SessionID, Seq: seq, Entry: entry})
+	}
+	if end < len(entries) {
+		next := end - 1
+		page.Next = &next
+	}
+	if !s.recordAccess(w, r, AuditRecord{Action: "entries", Session: info.SessionID}) {
+		return
+	}
+	writeJSON(w, page)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	var lang string
+	if l := r.URL.Query().Get("lang"); l != "" {
+		if lang = codelang.Normalize(l); lang == "" {
+			http.Error(w, fmt.Sprintf("unknown language %q", l), http.StatusBadRequest)
+			return
+		}
+	}
+	if len(query) < 2 && lang == "" {
+		http.Error(w, "query must be at least 2 characters", http.StatusBadRequest)
+		return
+	}
+	limit := search.DefaultLimit
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 && n < limit {
+		limit = n
+	}
+	order := r.URL.Query().Get("sort")
+	if order == "" {
+		order = search.SortRelevance
+	}
+	if !slices.Contains(search.Sorts, order) {
+		http.Error(w, fmt.Sprintf("unknown sort %q", order), http.StatusBadRequest)
+		return
+	}
+	sessions, err := s.listSessions()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	hits, err := search.Run(r.Context(), sessions, search.Options{Query: query, Lang: lang, Limit: limit, Sort: order, Redactor: s.Redactor})
+	if err != nil {
+		logging.NewLogger("aglogs-serve").WithError(err).Error("Search redaction failed")
+		http.Error(w, "redaction failed", http.StatusInternalServerError)
+		return
+	}
+	var matched []string
+	for _, hit := range hits {
+		if !slices.Contains(matched, hit.Session) {
+			matched = append(matched, hit.Session)
+		}
+	}
+	if !s.recordAccess(w, r, AuditRecord{Action: "search", Query: query, Matched: matched}) {
+		return
+	}
+	writeJSON(w, hits)
+}
+
+// recordAccess audits a request about to be served, answering it with an
+// error instead if the record cannot be written.
+func (s *Server) recordAccess(w http.ResponseWriter, r *http.Request, rec AuditRecord) bool {
+	if err := s.audit(r, rec); err != nil {
+		logging.NewLogger("aglogs-serve").WithError(err).Error("Failed to write audit record")
+		http.Error(w, "audit log unavailable", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// resolve finds the session id names in the session listing: a session
+// ID, an ID prefix, or a plan/job spec. Clients can't name a file path, so
+// the server only ever reads transcripts the scan found.
+func (s *Server) resolve(id string) (*session.SessionInfo, error) {
+	sessions, err := s.listSessions()
+	if err != nil {
+		return nil, err
+	}
+	info, err := session.MatchSpec(sessions, id)
+	if err != nil {
+		return nil, err
+	}
+	found := *info
+	return &found, nil
+}
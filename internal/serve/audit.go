@@ -0,0 +1,88 @@
+package serve
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditRecord is one line of the audit log: who read what, and when.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// Identity is the name of the token used, "" without authentication.
+	Identity string `json:"identity"`
+	Remote   string `json:"remote"`
+	// Action is "list" (session listing), "session" (metadata), "entries"
+	// (a page of transcript entries), "events" (SSE transcript feed),
+	// "subscribe" (WebSocket transcript feed), "search", or "denied" (a
+	// request without a valid token).
+	Action  string `json:"action"`
+	Session string `json:"session,omitempty"`
+	// Query and Matched are the search text and the sessions whose
+	// content was returned for it.
+	Query   string   `json:"query,omitempty"`
+	Matched []string `json:"matched,omitempty"`
+	Path    string   `json:"path,omitempty"`
+}
+
+// AuditLog appends AuditRecords as JSON lines. A nil *AuditLog records
+// nothing.
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+	c  io.Closer
+}
+
+// OpenAuditLog opens (or creates) an audit log file for appending.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{w: f, c: f}, nil
+}
+
+// NewAuditLog returns an audit log writing to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// Record appends one record. Each record is a single write, so concurrent
+// servers appending to the same file do not interleave lines.
+func (a *AuditLog) Record(rec AuditRecord) error {
+	if a == nil {
+		return nil
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err = a.w.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the underlying file, if the log owns one.
+func (a *AuditLog) Close() error {
+	if a == nil || a.c == nil {
+		return nil
+	}
+	return a.c.Close()
+}
+
+// audit records an access by the client of r. Handlers call it before
+// sending any transcript data and refuse the request if it fails, so
+// nothing is served that was not logged.
+func (s *Server) audit(r *http.Request, rec AuditRecord) error {
+	if s.Audit == nil {
+		return nil
+	}
+	rec.Time = time.Now().UTC()
+	rec.Identity = identity(r)
+	rec.Remote = r.RemoteAddr
+	return s.Audit.Record(rec)
+}
@@ -0,0 +1,159 @@
+package serve
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// tokenCookie carries the token for the web UI, whose EventSource and
+	// WebSocket connections cannot set an Authorization header.
+	tokenCookie = "aglogs_token"
+	// minTokenLength rejects tokens short enough to guess.
+	minTokenLength = 16
+)
+
+// LoadTokens reads a tokens file: one "<name> <token>" pair per line, with
+// blank lines and "#" comments ignored. It returns token -> name.
+func LoadTokens(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tokens := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected '<name> <token>'", path, n)
+		}
+		name, token := fields[0], fields[1]
+		if len(token) < minTokenLength {
+			return nil, fmt.Errorf("%s:%d: token for %s is shorter than %d characters", path, n, name, minTokenLength)
+		}
+		if other, ok := tokens[token]; ok {
+			return nil, fmt.Errorf("%s:%d: token for %s is already used by %s", path, n, name, other)
+		}
+		tokens[token] = name
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%s: no tokens", path)
+	}
+	return tokens, nil
+}
+
+type identityKey struct{}
+
+// identity is the name of the token a request was authenticated with, or
+// "" when the server has no tokens.
+func identity(r *http.Request) string {
+	name, _ := r.Context().Value(identityKey{}).(string)
+	return name
+}
+
+// authenticate requires one of s.Tokens on every request, from an
+// "Authorization: Bearer" header, the UI cookie, or a ?token= parameter.
+// A valid ?token= on a page load is moved into the cookie and stripped
+// from the URL, so a shared link does not linger in the address bar.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.Tokens == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromQuery := r.URL.Query().Get("token")
+		token := fromQuery
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		} else if c, err := r.Cookie(tokenCookie); err == nil && token == "" {
+			token = c.Value
+		}
+
+		name, ok := s.lookupToken(token)
+		if !ok {
+			if !s.recordAccess(w, r, AuditRecord{Action: "denied", Path: r.URL.Path}) {
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="aglogs"`)
+			http.Error(w, "unauthorized: pass a token as 'Authorization: Bearer <token>' or open the UI with ?token=<token>", http.StatusUnauthorized)
+			return
+		}
+
+		if fromQuery != "" && token == fromQuery && r.Method == http.MethodGet && !strings.HasPrefix(r.URL.Path, "/api/") {
+			http.SetCookie(w, &http.Cookie{
+				Name:     tokenCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteStrictMode,
+				MaxAge:   int((30 * 24 * time.Hour).Seconds()),
+			})
+			u := *r.URL
+			q := u.Query()
+			q.Del("token")
+			u.RawQuery = q.Encode()
+			http.Redirect(w, r, u.RequestURI(), http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityKey{}, name)))
+	})
+}
+
+// checkHost rejects requests to an unauthenticated loopback server whose
+// Host header is not a loopback name. A DNS-rebinding page reaches such a
+// server under its own domain name, which is still in the Host header.
+func (s *Server) checkHost(next http.Handler) http.Handler {
+	if s.Tokens != nil || !s.Loopback {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !loopbackHost(r.Host) {
+			http.Error(w, "forbidden: unexpected Host header", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// loopbackHost reports whether host, with or without a port, names the
+// local machine.
+func loopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// lookupToken compares token against every configured token in constant
+// time.
+func (s *Server) lookupToken(token string) (string, bool) {
+	var name string
+	found := false
+	for candidate, who := range s.Tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			name, found = who, true
+		}
+	}
+	return name, found && token != ""
+}
@@ -0,0 +1,143 @@
+// Package serve exposes live session transcripts over HTTP, as Server-Sent
+// Events and as a WebSocket feed, for the web dashboard.
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"os"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// DefaultPollInterval is how often a followed transcript is checked for
+// changes.
+const DefaultPollInterval = time.Second
+
+// Event is one normalized entry and its position in the session transcript.
+// Seq is stable across reconnects, so a client that remembers the last Seq
+// it saw can ask for only what came after it. An Event may be sent again
+// with a Seq the client already has, when a buffered entry gained content
+// (typically a tool call whose result arrived); it replaces the earlier one.
+type Event struct {
+	Session string                  `json:"session"`
+	Seq     int                     `json:"seq"`
+	Entry   transcript.UnifiedEntry `json:"entry"`
+}
+
+// follower re-reads one transcript whenever its file changes and reports the
+// entries that are new or changed since the previous read.
+type follower struct {
+	info *session.SessionInfo
+	src  provider.TranscriptSource
+
+	size    int64
+	modTime time.Time
+	sums    []uint64 // content hash per Seq already reported
+}
+
+func newFollower(info *session.SessionInfo) *follower {
+	return &follower{info: info, src: provider.SelectSource(info, nil)}
+}
+
+// changed reports whether the transcript may have grown since the last
+// poll. Anything that cannot be stat'ed cheaply (directories, missing
+// files) is always re-read.
+func (f *follower) changed() bool {
+	st, err := os.Stat(f.info.LogFilePath)
+	if err != nil || st.IsDir() {
+		return true
+	}
+	if st.Size() == f.size && st.ModTime().Equal(f.modTime) {
+		return false
+	}
+	f.size, f.modTime = st.Size(), st.ModTime()
+	return true
+}
+
+// poll returns the events after `after` that have not been reported yet,
+// plus any earlier events whose content changed.
+func (f *follower) poll(ctx context.Context, after int) ([]Event, error) {
+	if !f.changed() {
+		return nil, nil
+	}
+	entries, err := f.src.Read(ctx, f.info, provider.ReadOptions{DetailLevel: "full", EndLine: -1})
+	if err != nil {
+		f.size = -1 // force a re-read on the next poll
+		return nil, err
+	}
+
+	var events []Event
+	for i, entry := range entries {
+		sum := entrySum(entry)
+		if i < len(f.sums) {
+			if f.sums[i] == sum {
+				continue
+			}
+			f.sums[i] = sum
+		} else {
+			f.sums = append(f.sums, sum)
+		}
+		if i > after {
+			events = append(events, Event{Session: f.info.SessionID, Seq: i, Entry: entry})
+		}
+	}
+	return events, nil
+}
+
+func entrySum(entry transcript.UnifiedEntry) uint64 {
+	data, _ := json.Marshal(entry)
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
+// Follow sends every entry of the session after Seq `after` (-1 for the
+// whole transcript), calls caughtUp once that backfill is done, and then
+// keeps polling for new entries until ctx is cancelled or send fails.
+func Follow(ctx context.Context, info *session.SessionInfo, after int, interval time.Duration, send func(Event) error, caughtUp func(lastSeq int) error) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	f := newFollower(info)
+
+	events, err := f.poll(ctx, after)
+	if err != nil {
+		return err
+	}
+	for _, ev := range events {
+		if err := send(ev); err != nil {
+			return err
+		}
+	}
+	if caughtUp != nil {
+		if err := caughtUp(len(f.sums) - 1); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		events, err := f.poll(ctx, after)
+		if err != nil {
+			// A transcript being rewritten can fail to read mid-write;
+			// the next tick retries.
+			continue
+		}
+		for _, ev := range events {
+			if err := send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,6 @@
+package serve
+
+import "github.com/grovetools/agentlogs/internal/search"
+
+// SearchHit is one transcript entry containing a search query.
+type SearchHit = search.Hit
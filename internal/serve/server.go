@@ -0,0 +1,329 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grovetools/core/logging"
+
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/redact"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// keepaliveInterval is how often idle feeds are pinged so that proxies keep
+// the connection open and dead clients are noticed.
+const keepaliveInterval = 30 * time.Second
+
+// Server serves the web UI, a small JSON API, and live transcript feeds.
+//
+//	GET /                           the embedded web UI
+//	GET /api/sessions               sessions, newest first
+//	GET /api/sessions/{id}          one session's metadata
+//	GET /api/sessions/{id}/entries  a page of one session's entries
+//	GET /api/sessions/{id}/events   Server-Sent Events for one session
+//	GET /api/search?q=<text>        entries containing text, across sessions
+//	                                (&lang=<language> keeps entries with code in it)
+//	GET /api/ws                     WebSocket feed for any number of sessions
+//
+// Both feeds send the transcript from the start (or after a given Seq) and
+// then follow it. The SSE event id is the entry's Seq, so a browser
+// EventSource resumes where it left off through Last-Event-ID; WebSocket
+// clients resubscribe with the last Seq they saw.
+type Server struct {
+	// PollInterval is how often followed transcripts are checked for new
+	// entries. Zero means DefaultPollInterval.
+	PollInterval time.Duration
+	// Sessions lists every known session, for the session list, search,
+	// and the session IDs, prefixes and plan/job specs clients name. Its
+	// result is cached for sessionsTTL. Nil leaves the server empty.
+	Sessions func() ([]session.SessionInfo, error)
+	// Tokens maps each accepted access token to the name it is audited
+	// under. Nil serves without authentication.
+	Tokens map[string]string
+	// Loopback marks a server that listens only on a loopback address.
+	// Without Tokens it then answers only requests for a loopback host
+	// name, so a page whose domain is rebound to 127.0.0.1 can't read it.
+	Loopback bool
+	// Audit, when set, records every session listing, read, feed, and
+	// search, and every rejected request.
+	Audit *AuditLog
+	// Redactor, when set, rewrites every entry and search snippet before
+	// it is sent. A redaction failure ends the feed or fails the request
+	// rather than sending unredacted text.
+	Redactor redact.Redactor
+
+	mu         sync.Mutex
+	sessions   []session.SessionInfo
+	sessionsAt time.Time
+}
+
+// Handler returns the HTTP handler for the server's routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /", webHandler())
+	mux.HandleFunc("GET /api/sessions", s.handleSessions)
+	mux.HandleFunc("GET /api/sessions/{id}", s.handleSession)
+	mux.HandleFunc("GET /api/sessions/{id}/entries", s.handleEntries)
+	mux.HandleFunc("GET /api/sessions/{id}/events", s.handleEvents)
+	mux.HandleFunc("GET /api/search", s.handleSearch)
+	mux.HandleFunc("GET /api/ws", s.handleWebSocket)
+	return s.checkHost(s.authenticate(mux))
+}
+
+// feedMessage is one message on a WebSocket feed.
+type feedMessage struct {
+	Type    string                   `json:"type"` // "entry", "ready", or "error"
+	Session string                   `json:"session"`
+	Seq     int                      `json:"seq"` // entry position; for "ready", the last Seq of the backfill
+	Entry   *transcript.UnifiedEntry `json:"entry,omitempty"`
+	Error   string                   `json:"error,omitempty"`
+}
+
+// clientMessage is a request from a WebSocket client.
+type clientMessage struct {
+	Type    string `json:"type"` // "subscribe" or "unsubscribe"
+	Session string `json:"session"`
+	After   *int   `json:"after,omitempty"` // last Seq already seen; omit for the whole transcript
+}
+
+// parseAfter reads a last-seen Seq, defaulting to -1 (send everything).
+func parseAfter(value string) (int, error) {
+	if value == "" {
+		return -1, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < -1 {
+		return 0, fmt.Errorf("invalid sequence number %q", value)
+	}
+	return n, nil
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	logger := logging.NewLogger("aglogs-serve")
+
+	after, err := parseAfter(r.URL.Query().Get("after"))
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		after, err = parseAfter(id)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	info, err := s.resolve(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if !s.recordAccess(w, r, AuditRecord{Action: "events", Session: info.SessionID}) {
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	var mu sync.Mutex
+	write := func(format string, args ...interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(keepaliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if write(": keepalive\n\n") != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	send := func(ev Event) error {
+		var err error
+		if ev.Entry, err = redact.Entry(ctx, s.Redactor, ev.Entry); err != nil {
+			return err
+		}
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		return write("id: %d\nevent: entry\ndata: %s\n\n", ev.Seq, data)
+	}
+	ready := func(lastSeq int) error {
+		return write("event: ready\ndata: {\"seq\":%d}\n\n", lastSeq)
+	}
+	if err := Follow(ctx, info, after, s.PollInterval, send, ready); err != nil && ctx.Err() == nil {
+		logger.WithError(err).WithField("session_id", info.SessionID).Debug("SSE feed ended")
+	}
+}
+
+// wsFeed is one WebSocket connection and its active subscriptions.
+type wsFeed struct {
+	server *Server
+	req    *http.Request // the handshake, for auditing subscriptions
+	conn   *wsConn
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu   sync.Mutex
+	subs map[string]context.CancelFunc
+}
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	logger := logging.NewLogger("aglogs-serve")
+
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		logger.WithError(err).Debug("WebSocket upgrade failed")
+		return
+	}
+	// The request context is not cancelled when a hijacked connection
+	// drops, so the feed owns its own.
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &wsFeed{server: s, req: r, conn: conn, ctx: ctx, cancel: cancel, subs: make(map[string]context.CancelFunc)}
+	defer f.close()
+
+	// Sessions named in the URL are subscribed right away:
+	// /api/ws?session=<id>&session=<id>:<after>
+	for _, spec := range r.URL.Query()["session"] {
+		id, after := spec, -1
+		if i := strings.LastIndex(spec, ":"); i > 0 {
+			if n, err := parseAfter(spec[i+1:]); err == nil {
+				id, after = spec[:i], n
+			}
+		}
+		f.subscribe(id, after)
+	}
+
+	go f.keepalive()
+
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			if !errors.Is(err, errWebSocketClosed) && ctx.Err() == nil {
+				logger.WithError(err).Debug("WebSocket read failed")
+			}
+			return
+		}
+		var msg clientMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Session == "" {
+			f.send(feedMessage{Type: "error", Session: msg.Session, Seq: -1, Error: "expected {\"type\":\"subscribe\"|\"unsubscribe\",\"session\":...}"})
+			continue
+		}
+		switch msg.Type {
+		case "subscribe":
+			after := -1
+			if msg.After != nil {
+				after = *msg.After
+			}
+			f.subscribe(msg.Session, after)
+		case "unsubscribe":
+			f.unsubscribe(msg.Session)
+		default:
+			f.send(feedMessage{Type: "error", Session: msg.Session, Seq: -1, Error: fmt.Sprintf("unknown message type %q", msg.Type)})
+		}
+	}
+}
+
+// send writes one message, tearing the whole feed down if the client is
+// gone.
+func (f *wsFeed) send(msg feedMessage) error {
+	if err := f.conn.WriteJSON(msg); err != nil {
+		f.cancel()
+		return err
+	}
+	return nil
+}
+
+// subscribe starts following a session, replacing any earlier
+// subscription to it.
+func (f *wsFeed) subscribe(id string, after int) {
+	info, err := f.server.resolve(id)
+	if err != nil {
+		f.send(feedMessage{Type: "error", Session: id, Seq: -1, Error: err.Error()})
+		return
+	}
+	if err := f.server.audit(f.req, AuditRecord{Action: "subscribe", Session: info.SessionID}); err != nil {
+		logging.NewLogger("aglogs-serve").WithError(err).Error("Failed to write audit record")
+		f.send(feedMessage{Type: "error", Session: id, Seq: -1, Error: "audit log unavailable"})
+		return
+	}
+
+	f.mu.Lock()
+	if stop, ok := f.subs[id]; ok {
+		stop()
+	}
+	ctx, stop := context.WithCancel(f.ctx)
+	f.subs[id] = stop
+	f.mu.Unlock()
+
+	go func() {
+		send := func(ev Event) error {
+			entry, err := redact.Entry(ctx, f.server.Redactor, ev.Entry)
+			if err != nil {
+				return err
+			}
+			return f.send(feedMessage{Type: "entry", Session: id, Seq: ev.Seq, Entry: &entry})
+		}
+		ready := func(lastSeq int) error {
+			return f.send(feedMessage{Type: "ready", Session: id, Seq: lastSeq})
+		}
+		if err := Follow(ctx, info, after, f.server.PollInterval, send, ready); err != nil && ctx.Err() == nil {
+			f.send(feedMessage{Type: "error", Session: id, Seq: -1, Error: err.Error()})
+		}
+	}()
+}
+
+func (f *wsFeed) unsubscribe(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if stop, ok := f.subs[id]; ok {
+		stop()
+		delete(f.subs, id)
+	}
+}
+
+func (f *wsFeed) keepalive() {
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.ctx.Done():
+			// A failed write cancels the feed; closing the connection
+			// unblocks the read loop too.
+			f.conn.conn.Close()
+			return
+		case <-ticker.C:
+			if err := f.conn.writeFrame(opPing, nil); err != nil {
+				f.cancel()
+			}
+		}
+	}
+}
+
+func (f *wsFeed) close() {
+	f.cancel()
+	f.conn.Close(1000, "")
+}
@@ -0,0 +1,21 @@
+package serve
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// The web UI is plain HTML, CSS and JavaScript with no build step, so that
+// the binary alone is enough to review sessions from a browser.
+//
+//go:embed web
+var webFS embed.FS
+
+func webHandler() http.Handler {
+	sub, err := fs.Sub(webFS, "web")
+	if err != nil {
+		panic(err) // the embedded tree is fixed at build time
+	}
+	return http.FileServerFS(sub)
+}
@@ -0,0 +1,390 @@
+// aglogs web UI: session list, live transcript viewer, and search.
+// Routes live in the URL hash: #/s/<id>, #/s/<id>/<seq>, #/search/<query>.
+// Keys in an open transcript: f follow, p pause/resume, e/E next/previous error.
+"use strict";
+
+let sessions = [];
+let feed = null; // EventSource of the open transcript
+
+// el builds a DOM node. Strings become text nodes, so transcript content is
+// never interpreted as HTML.
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    if (v === undefined || v === null || v === false) continue;
+    if (k === "class") node.className = v;
+    else if (k.startsWith("on")) node.addEventListener(k.slice(2), v);
+    else node.setAttribute(k, v === true ? "" : v);
+  }
+  for (const child of children.flat()) {
+    if (child === undefined || child === null || child === false) continue;
+    node.append(child instanceof Node ? child : String(child));
+  }
+  return node;
+}
+
+function fmtTime(ts) {
+  if (!ts || ts.startsWith("0001-")) return "";
+  const d = new Date(ts);
+  return isNaN(d) ? "" : d.toLocaleString(undefined, { dateStyle: "short", timeStyle: "short" });
+}
+
+function truncate(s, n) {
+  s = String(s || "");
+  return s.length > n ? s.slice(0, n - 1) + "…" : s;
+}
+
+function jobNames(info) {
+  return (info.jobs || []).map((j) => j.plan + "/" + j.job);
+}
+
+// --- Session list ---
+
+async function loadSessions() {
+  const list = document.getElementById("sessions");
+  try {
+    const resp = await fetch("api/sessions");
+    if (!resp.ok) throw new Error(await resp.text());
+    sessions = await resp.json();
+  } catch (err) {
+    list.replaceChildren(el("li", { class: "error" }, "Failed to load sessions: " + err.message));
+    return;
+  }
+  renderSessions();
+}
+
+function renderSessions() {
+  const terms = document.getElementById("filter").value.toLowerCase().split(/\s+/).filter(Boolean);
+  const current = currentSessionID();
+  const items = sessions
+    .filter((s) => {
+      const hay = [s.sessionId, s.projectName, s.worktree, s.provider, ...jobNames(s)].join(" ").toLowerCase();
+      return terms.every((t) => hay.includes(t));
+    })
+    .map((s) =>
+      el("li", {},
+        el("a", { href: "#/s/" + encodeURIComponent(s.sessionId), class: s.sessionId === current ? "active" : null },
+          el("div", { class: "title" }, s.projectName && s.projectName !== "unknown" ? s.projectName : s.sessionId.slice(0, 8)),
+          el("div", { class: "meta" },
+            el("span", { class: "badge" }, s.provider || "claude"),
+            s.status === "running" ? el("span", { class: "badge running" }, "running") : null,
+            fmtTime(s.startedAt), " · ", s.sessionId.slice(0, 8)),
+          jobNames(s).length ? el("div", { class: "meta" }, truncate(jobNames(s).join(", "), 60)) : null)));
+  document.getElementById("sessions").replaceChildren(...(items.length ? items : [el("li", { class: "muted" }, "No sessions")]));
+}
+
+// --- Transcript ---
+
+function partNode(part) {
+  const c = part.content || {};
+  switch (part.type) {
+    case "text":
+      return el("div", { class: "text" }, c.text);
+    case "reasoning":
+      return el("details", { class: "reasoning" }, el("summary", {}, "thinking"), el("div", { class: "text muted" }, c.text));
+    case "tool_call": {
+      if (c.name === "EnterPlanMode") return el("div", { class: "divider" }, "── planning ──");
+      const input = c.input || {};
+      const brief = input.command || input.file_path || input.filePath || input.path || input.pattern || input.description || "";
+      const failed = c.status === "error";
+      return el("details", { class: "tool" + (failed ? " error" : "") },
+        el("summary", {}, el("span", { class: "name" }, c.name), " ", truncate(Array.isArray(brief) ? brief.join(" ") : brief, 120),
+          c.durationMs ? el("span", { class: "muted" }, " · " + (c.durationMs / 1000).toFixed(1) + "s") : null),
+        el("pre", {}, JSON.stringify(input, null, 2)),
+        c.diff ? el("pre", {}, c.diff) : null,
+        c.output ? el("pre", {}, c.output) : null);
+    }
+    case "tool_result":
+      return el("details", { class: "result" + (c.isError ? " error" : "") },
+        el("summary", {}, c.isError ? "error output" : "output"), el("pre", {}, c.output));
+    case "command":
+      return el("div", { class: "event" }, c.name + (c.args ? " " + c.args : ""));
+    case "command_output":
+      return el("pre", { class: c.isError ? "error" : "event" }, c.output);
+    case "hook":
+      return el("details", { class: "event" + (c.isError ? " error" : "") },
+        el("summary", {}, "hook " + c.event + (c.command ? ": " + c.command : "")),
+        c.output ? el("pre", {}, c.output) : null);
+    case "plan_mode":
+      return el("div", { class: "divider" }, c.active ? "── planning ──" : "── planning ended ──");
+    default:
+      return el("pre", {}, JSON.stringify(c, null, 2));
+  }
+}
+
+function isErrorPart(part) {
+  const c = part.content || {};
+  return (part.type === "tool_call" && c.status === "error") || c.isError === true;
+}
+
+function entryNode(seq, entry) {
+  const failed = (entry.parts || []).some(isErrorPart);
+  return el("div", { class: "entry " + entry.role + (failed ? " has-error" : ""), id: "seq-" + seq },
+    el("div", { class: "who" },
+      el("span", { class: "role" }, entry.agentID ? entry.role + " (" + entry.agentID + ")" : entry.role),
+      " ", fmtTime(entry.timestamp)),
+    (entry.parts || []).map(partNode));
+}
+
+function headerNode(info) {
+  const rows = [
+    ["Provider", info.provider || "claude"],
+    ["Project", info.projectName],
+    ["Worktree", info.worktree],
+    ["Status", info.status],
+    ["Started", fmtTime(info.startedAt)],
+    ["Ended", fmtTime(info.endedAt)],
+    ["Jobs", jobNames(info).join(", ")],
+    ["Log file", info.logFilePath],
+  ].filter(([, v]) => v && v !== "unknown");
+  return el("div", { class: "session-header" },
+    el("h2", {}, info.sessionId),
+    el("dl", {}, rows.map(([k, v]) => [el("dt", {}, k), el("dd", {}, v)])));
+}
+
+function toolbarNode() {
+  return el("div", { class: "toolbar" },
+    el("span", { class: "live", id: "live" }, "connecting…"),
+    el("button", { id: "follow", title: "Scroll to new entries as they arrive (f)", onclick: () => setFollow(!view.follow) }, "Follow"),
+    el("button", { id: "pause", title: "Hold new entries back while reading (p)", onclick: togglePause }, "Pause"),
+    el("button", { id: "prev-error", title: "Previous error (E)", onclick: () => jumpToError(-1) }, "Prev error"),
+    el("button", { id: "next-error", title: "Next error (e)", onclick: () => jumpToError(1) }, "Next error"),
+    el("span", { class: "muted", id: "error-count" }));
+}
+
+// --- Live view controls ---
+//
+// view is the state of the open transcript. Follow keeps the newest entry
+// in view and switches off when the reader scrolls up; pause holds entries
+// back (the feed keeps running) until resumed.
+let view = null;
+
+function newView(targetSeq) {
+  return {
+    nodes: new Map(), // seq -> node; a re-sent seq replaces its entry
+    pending: new Map(), // seq -> entry held back while paused
+    ready: false,
+    paused: false,
+    targetSeq,
+    follow: targetSeq === undefined && localStorage.getItem("aglogs.follow") !== "off",
+  };
+}
+
+function scrollToEnd() {
+  const main = document.getElementById("main");
+  main.scrollTop = main.scrollHeight;
+}
+
+function setFollow(on) {
+  view.follow = on;
+  localStorage.setItem("aglogs.follow", on ? "on" : "off");
+  if (on && view.ready) scrollToEnd();
+  updateControls();
+}
+
+function togglePause() {
+  view.paused = !view.paused;
+  if (!view.paused) {
+    for (const [seq, entry] of view.pending) showEntry(seq, entry);
+    view.pending.clear();
+    if (view.follow) scrollToEnd();
+  }
+  updateControls();
+}
+
+function errorNodes() {
+  return [...view.nodes.values()].filter((n) => n.classList.contains("has-error")).sort((a, b) => a.offsetTop - b.offsetTop);
+}
+
+// jumpToError scrolls to the next (dir 1) or previous (dir -1) entry with a
+// failed tool call or error output, wrapping around, and opens its errors.
+function jumpToError(dir) {
+  const errors = errorNodes();
+  if (!errors.length) return;
+  const main = document.getElementById("main");
+  const current = errors.findIndex((n) => n.classList.contains("current-error"));
+  let next;
+  if (current >= 0) {
+    next = (current + dir + errors.length) % errors.length;
+  } else {
+    const top = main.scrollTop + main.clientHeight / 3;
+    next = dir > 0 ? errors.findIndex((n) => n.offsetTop > top) : errors.findLastIndex((n) => n.offsetTop < top);
+    if (next < 0) next = dir > 0 ? 0 : errors.length - 1;
+  }
+  errors.forEach((n) => n.classList.remove("current-error"));
+  const node = errors[next];
+  node.classList.add("current-error");
+  node.querySelectorAll("details.error").forEach((d) => (d.open = true));
+  view.follow = false;
+  updateControls();
+  node.scrollIntoView({ block: "center" });
+}
+
+function updateControls() {
+  const follow = document.getElementById("follow");
+  if (!follow) return;
+  follow.classList.toggle("on", view.follow);
+  follow.setAttribute("aria-pressed", view.follow);
+  const pause = document.getElementById("pause");
+  pause.classList.toggle("on", view.paused);
+  pause.textContent = view.paused ? "Resume" + (view.pending.size ? " (" + view.pending.size + " new)" : "") : "Pause";
+  const errors = errorNodes().length;
+  document.getElementById("error-count").textContent = errors ? errors + " error" + (errors === 1 ? "" : "s") : "";
+  document.getElementById("next-error").disabled = !errors;
+  document.getElementById("prev-error").disabled = !errors;
+}
+
+function showEntry(seq, entry) {
+  const node = entryNode(seq, entry);
+  if (seq === view.targetSeq) node.classList.add("target");
+  const old = view.nodes.get(seq);
+  if (old) {
+    if (old.classList.contains("current-error")) node.classList.add("current-error");
+    old.replaceWith(node);
+  } else {
+    document.getElementById("entries").append(node);
+  }
+  view.nodes.set(seq, node);
+}
+
+async function openSession(id, targetSeq) {
+  if (feed) feed.close();
+  const main = document.getElementById("main");
+  main.replaceChildren(el("p", { class: "muted" }, "Loading…"));
+  renderSessions();
+
+  let info;
+  try {
+    const resp = await fetch("api/sessions/" + encodeURIComponent(id));
+    if (!resp.ok) throw new Error(await resp.text());
+    info = await resp.json();
+  } catch (err) {
+    main.replaceChildren(el("p", { class: "error" }, "Could not open session: " + err.message));
+    return;
+  }
+
+  view = newView(targetSeq);
+  main.replaceChildren(headerNode(info), toolbarNode(), el("div", { id: "entries" }));
+  updateControls();
+
+  feed = new EventSource("api/sessions/" + encodeURIComponent(id) + "/events");
+  feed.addEventListener("entry", (ev) => {
+    const { seq, entry } = JSON.parse(ev.data);
+    if (view.paused && view.ready) {
+      view.pending.set(seq, entry);
+    } else {
+      showEntry(seq, entry);
+      if (view.ready && view.follow) scrollToEnd();
+    }
+    if (view.ready) updateControls();
+  });
+  feed.addEventListener("ready", () => {
+    view.ready = true;
+    const live = document.getElementById("live");
+    live.textContent = "live";
+    live.classList.add("on");
+    if (targetSeq !== undefined && view.nodes.has(targetSeq)) view.nodes.get(targetSeq).scrollIntoView({ block: "center" });
+    else if (view.follow) scrollToEnd();
+    updateControls();
+  });
+  feed.onerror = () => {
+    const live = document.getElementById("live");
+    if (live) {
+      live.textContent = "reconnecting…";
+      live.classList.remove("on");
+    }
+  };
+}
+
+// --- Search ---
+
+function highlight(text, query) {
+  const out = [];
+  const lower = text.toLowerCase();
+  const q = query.toLowerCase();
+  let i = 0;
+  for (let at = lower.indexOf(q); at >= 0; at = lower.indexOf(q, i)) {
+    out.push(text.slice(i, at), el("mark", {}, text.slice(at, at + q.length)));
+    i = at + q.length;
+  }
+  out.push(text.slice(i));
+  return out;
+}
+
+async function runSearch(query) {
+  if (feed) feed.close();
+  const main = document.getElementById("main");
+  document.getElementById("search").value = query;
+  main.replaceChildren(el("p", { class: "muted" }, "Searching for “" + query + "”…"));
+  let hits;
+  try {
+    const resp = await fetch("api/search?q=" + encodeURIComponent(query));
+    if (!resp.ok) throw new Error(await resp.text());
+    hits = await resp.json();
+  } catch (err) {
+    main.replaceChildren(el("p", { class: "error" }, "Search failed: " + err.message));
+    return;
+  }
+  main.replaceChildren(
+    el("h2", {}, hits.length + " match" + (hits.length === 1 ? "" : "es") + " for “" + query + "”"),
+    el("ul", { class: "hits" }, hits.map((h) =>
+      el("li", {},
+        el("a", { href: "#/s/" + encodeURIComponent(h.session) + "/" + h.seq },
+          (h.project || h.session.slice(0, 8)) + " · " + h.role),
+        el("span", { class: "muted" }, " ", h.provider || "", " ", h.startedAt || ""),
+        el("div", { class: "snippet" }, highlight(h.snippet, query))))));
+}
+
+// --- Routing ---
+
+function currentSessionID() {
+  const m = location.hash.match(/^#\/s\/([^/]+)/);
+  return m ? decodeURIComponent(m[1]) : null;
+}
+
+function route() {
+  const hash = location.hash;
+  let m;
+  if ((m = hash.match(/^#\/s\/([^/]+)(?:\/(\d+))?$/))) {
+    openSession(decodeURIComponent(m[1]), m[2] === undefined ? undefined : Number(m[2]));
+  } else if ((m = hash.match(/^#\/search\/(.+)$/))) {
+    runSearch(decodeURIComponent(m[1]));
+  } else {
+    if (feed) feed.close();
+    renderSessions();
+    document.getElementById("main").replaceChildren(el("p", { class: "muted" }, "Select a session, or search across transcripts."));
+  }
+}
+
+// Scrolling up by hand stops following; scrolling back to the end resumes.
+document.getElementById("main").addEventListener("scroll", () => {
+  if (!view || !view.ready || !document.getElementById("follow")) return;
+  const main = document.getElementById("main");
+  const atEnd = main.scrollHeight - main.scrollTop - main.clientHeight < 40;
+  if (view.follow !== atEnd) {
+    view.follow = atEnd;
+    updateControls();
+  }
+});
+document.addEventListener("keydown", (ev) => {
+  if (!view || !document.getElementById("follow") || ev.ctrlKey || ev.metaKey || ev.altKey) return;
+  if (ev.target.matches("input, textarea")) return;
+  switch (ev.key) {
+    case "f": setFollow(!view.follow); break;
+    case "p": togglePause(); break;
+    case "e": jumpToError(1); break;
+    case "E": jumpToError(-1); break;
+    default: return;
+  }
+  ev.preventDefault();
+});
+document.getElementById("filter").addEventListener("input", renderSessions);
+document.getElementById("search-form").addEventListener("submit", (ev) => {
+  ev.preventDefault();
+  const q = document.getElementById("search").value.trim();
+  if (q.length >= 2) location.hash = "#/search/" + encodeURIComponent(q);
+});
+window.addEventListener("hashchange", route);
+
+loadSessions().then(route);
+setInterval(loadSessions, 30000);
@@ -0,0 +1,225 @@
+package session
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/core/pkg/sessions"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// ErrAlreadyArchived is returned by ArchiveJob when the job's artifact
+// directory already holds an archive and Force is not set.
+var ErrAlreadyArchived = errors.New("already archived")
+
+// ArchiveOptions controls ArchiveJob.
+type ArchiveOptions struct {
+	// Force overwrites an existing archive.
+	Force bool
+	// Compress writes the transcript gzipped, as transcript.jsonl.gz.
+	Compress bool
+}
+
+// ArchiveResult describes one archived job.
+type ArchiveResult struct {
+	Job JobInfo `json:"job"`
+	// Dir is <plan>/.artifacts/<job-id>, holding transcript.jsonl (or
+	// transcript.jsonl.gz) and metadata.json in the layout
+	// scanForArchivedSessions reads.
+	Dir   string `json:"dir"`
+	Lines int    `json:"lines"`
+	// Transcript is the archived transcript file.
+	Transcript string `json:"transcript"`
+}
+
+// ArchiveJob snapshots the part of a session belonging to info.Jobs[i]
+// into the plan's artifact directory, so the job's transcript survives the
+// provider's log rotation. Claude and Codex transcripts are cut to the
+// job's line range (Codex keeps its session_meta line); pi transcripts are
+// trees and are copied whole. OpenCode sessions are not single files and
+// cannot be archived.
+//
+// Once a session has an archive, the scanner reads it from its archives
+// instead of the live log, so callers should archive every job of a
+// session together.
+func ArchiveJob(info SessionInfo, i int, opts ArchiveOptions) (ArchiveResult, error) {
+	job := info.Jobs[i]
+	res := ArchiveResult{Job: job}
+	if info.Provider == "opencode" {
+		return res, fmt.Errorf("OpenCode sessions are stored as directories, not a transcript file; use 'aglogs export' instead")
+	}
+	if job.PlanPath == "" {
+		return res, fmt.Errorf("plan directory of %s/%s is unknown", job.Plan, job.Job)
+	}
+	if info.Archived() {
+		return res, fmt.Errorf("%s is already an archived transcript", info.LogFilePath)
+	}
+
+	res.Dir = filepath.Join(job.PlanPath, ".artifacts", jobArtifactID(job.PlanPath, job.Job))
+	if _, err := os.Stat(filepath.Join(res.Dir, "metadata.json")); err == nil && !opts.Force {
+		return res, fmt.Errorf("%s/%s: %w in %s", job.Plan, job.Job, ErrAlreadyArchived, res.Dir)
+	}
+	if err := os.MkdirAll(res.Dir, 0o755); err != nil {
+		return res, err
+	}
+	return writeJobArchive(res, info, i, opts)
+}
+
+// writeJobArchive writes the archive of info.Jobs[i] into res.Dir, which
+// must exist: the job's part of the transcript and its metadata.json.
+func writeJobArchive(res ArchiveResult, info SessionInfo, i int, opts ArchiveOptions) (ArchiveResult, error) {
+	job := info.Jobs[i]
+	start, end := job.LineIndex, -1
+	if i+1 < len(info.Jobs) {
+		end = info.Jobs[i+1].LineIndex
+	}
+	keepFirst := info.Provider == "codex" && start > 0
+	if info.Provider == "pi" {
+		start, end = 0, -1
+	}
+	plainPath := filepath.Join(res.Dir, "transcript.jsonl")
+	stale := plainPath + transcript.CompressedExt
+	res.Transcript = plainPath
+	if opts.Compress {
+		res.Transcript, stale = stale, plainPath
+	}
+	n, err := writeFileAtomic(res.Transcript, func(w io.Writer) (int, error) {
+		if !opts.Compress {
+			return copyLineRange(w, info.LogFilePath, start, end, keepFirst)
+		}
+		zw := gzip.NewWriter(w)
+		zw.Name = "transcript.jsonl"
+		n, err := copyLineRange(zw, info.LogFilePath, start, end, keepFirst)
+		if cerr := zw.Close(); err == nil {
+			err = cerr
+		}
+		return n, err
+	})
+	if err != nil {
+		return res, fmt.Errorf("failed to archive transcript: %w", err)
+	}
+	res.Lines = n
+	// A forced re-archive may switch between compressed and not; the
+	// scanner prefers transcript.jsonl, so the old copy must go.
+	if err := os.Remove(stale); err != nil && !os.IsNotExist(err) {
+		return res, err
+	}
+
+	metadata := sessions.SessionMetadata{
+		SessionID:        info.SessionID,
+		ClaudeSessionID:  info.SessionID,
+		Provider:         info.Provider,
+		WorkingDirectory: ReadWorkingDirectory(info.LogFilePath, info.Provider),
+		User:             info.User,
+		StartedAt:        info.StartedAt,
+		TranscriptPath:   info.LogFilePath,
+		PlanName:         job.Plan,
+		JobFilePath:      filepath.Join(job.PlanPath, job.Job),
+	}
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return res, err
+	}
+	if _, err := writeFileAtomic(filepath.Join(res.Dir, "metadata.json"), func(w io.Writer) (int, error) {
+		_, err := w.Write(append(data, '\n'))
+		return 0, err
+	}); err != nil {
+		return res, fmt.Errorf("failed to write metadata: %w", err)
+	}
+	return res, nil
+}
+
+// Archived reports whether the session is read from its plan archives, or
+// a copy fetched from a remote archive, rather than a live transcript.
+func (s SessionInfo) Archived() bool {
+	return isArchivePath(s.LogFilePath) || isRemoteCachePath(s.LogFilePath)
+}
+
+// isArchivePath reports whether path is a transcript inside a plan's
+// .artifacts directory.
+func isArchivePath(path string) bool {
+	return strings.Contains(filepath.ToSlash(path), "/.artifacts/")
+}
+
+// jobArtifactID is the artifact directory name flow uses for a job: the
+// `id:` in the job file's frontmatter, or the file name without ".md" when
+// the job has none.
+func jobArtifactID(planPath, jobFile string) string {
+	fallback := strings.TrimSuffix(jobFile, ".md")
+	f, err := os.Open(filepath.Join(planPath, jobFile))
+	if err != nil {
+		return fallback
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for n := 0; sc.Scan() && n <= 40; n++ {
+		line := strings.TrimSpace(sc.Text())
+		if n > 0 && line == "---" {
+			break
+		}
+		if v, ok := strings.CutPrefix(line, "id:"); ok {
+			if id := strings.Trim(strings.TrimSpace(v), `"'`); id != "" && !strings.ContainsAny(id, `/\`) {
+				return id
+			}
+			break
+		}
+	}
+	return fallback
+}
+
+// copyLineRange copies lines [start, end) of path to w (end -1 = to EOF),
+// preceded by line 0 when keepFirst is set. It returns the lines written.
+func copyLineRange(w io.Writer, path string, start, end int, keepFirst bool) (int, error) {
+	f, err := transcript.OpenTranscript(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	written := 0
+	for n := 0; end < 0 || n < end; n++ {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 && (n >= start || (keepFirst && n == 0)) {
+			if line[len(line)-1] != '\n' {
+				line = append(line, '\n')
+			}
+			if _, werr := w.Write(line); werr != nil {
+				return written, werr
+			}
+			written++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// writeFileAtomic writes path through a temporary file in the same
+// directory, so a failed archive never leaves a truncated transcript.
+func writeFileAtomic(path string, write func(io.Writer) (int, error)) (int, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	n, err := write(tmp)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return n, err
+	}
+	return n, os.Rename(tmp.Name(), path)
+}
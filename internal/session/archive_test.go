@@ -0,0 +1,142 @@
+package session
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/core/pkg/sessions"
+)
+
+func TestArchiveJob(t *testing.T) {
+	dir := t.TempDir()
+	plan := filepath.Join(dir, "plans", "feature")
+	if err := os.MkdirAll(plan, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(plan, "01-spec.md"), []byte("---\nid: spec-a1b2\ntitle: Spec\n---\nbody\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(plan, "02-impl.md"), []byte("no frontmatter\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "c1.jsonl")
+	lines := []string{
+		`{"type":"user","cwd":"/work/app","sessionId":"c1"}`,
+		`{"type":"assistant","n":1}`,
+		`{"type":"user","n":2}`,
+		`{"type":"assistant","n":3}`,
+	}
+	if err := os.WriteFile(logPath, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	info := SessionInfo{
+		SessionID: "c1", Provider: "claude", LogFilePath: logPath, User: "dev", StartedAt: started,
+		Jobs: []JobInfo{
+			{Plan: "feature", Job: "01-spec.md", LineIndex: 0, PlanPath: plan},
+			{Plan: "feature", Job: "02-impl.md", LineIndex: 2, PlanPath: plan},
+		},
+	}
+
+	first, err := ArchiveJob(info, 0, ArchiveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ArchiveJob(info, 1, ArchiveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(plan, ".artifacts", "spec-a1b2"); first.Dir != want || first.Lines != 2 {
+		t.Errorf("first = %+v, want dir %s with 2 lines", first, want)
+	}
+	if want := filepath.Join(plan, ".artifacts", "02-impl"); second.Dir != want || second.Lines != 2 {
+		t.Errorf("second = %+v, want dir %s with 2 lines", second, want)
+	}
+	data, err := os.ReadFile(filepath.Join(second.Dir, "transcript.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := lines[2] + "\n" + lines[3] + "\n"; string(data) != want {
+		t.Errorf("transcript = %q, want %q", data, want)
+	}
+
+	data, err = os.ReadFile(filepath.Join(first.Dir, "metadata.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var meta sessions.SessionMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.ClaudeSessionID != "c1" || meta.Provider != "claude" || meta.WorkingDirectory != "/work/app" ||
+		meta.PlanName != "feature" || planPathOf(meta.JobFilePath) != plan || filepath.Base(meta.JobFilePath) != "01-spec.md" ||
+		!meta.StartedAt.Equal(started) || meta.TranscriptPath != logPath {
+		t.Errorf("metadata = %+v", meta)
+	}
+
+	if _, err := ArchiveJob(info, 0, ArchiveOptions{}); !errors.Is(err, ErrAlreadyArchived) {
+		t.Errorf("re-archive without force: err = %v, want ErrAlreadyArchived", err)
+	}
+	if _, err := ArchiveJob(info, 0, ArchiveOptions{Force: true}); err != nil {
+		t.Errorf("re-archive with force: %v", err)
+	}
+
+	archived := info
+	archived.LogFilePath = filepath.Join(first.Dir, "transcript.jsonl")
+	if _, err := ArchiveJob(archived, 0, ArchiveOptions{Force: true}); err == nil {
+		t.Error("archiving an archived transcript succeeded")
+	}
+}
+
+func TestArchiveJobCompressed(t *testing.T) {
+	dir := t.TempDir()
+	plan := filepath.Join(dir, "plans", "feature")
+	if err := os.MkdirAll(plan, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// The live transcript is itself compressed, as left by prune --compress.
+	logPath := filepath.Join(dir, "c1.jsonl.gz")
+	lines := []string{`{"type":"user","cwd":"/work/app","sessionId":"c1"}`, `{"type":"assistant","n":1}`}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(strings.Join(lines, "\n") + "\n"))
+	zw.Close()
+	if err := os.WriteFile(logPath, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info := SessionInfo{
+		SessionID: "c1", Provider: "claude", LogFilePath: logPath,
+		Jobs: []JobInfo{{Plan: "feature", Job: "01-spec.md", PlanPath: plan}},
+	}
+	if got := ReadWorkingDirectory(logPath, "claude"); got != "/work/app" {
+		t.Errorf("working directory of compressed transcript = %q", got)
+	}
+
+	if _, err := ArchiveJob(info, 0, ArchiveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ArchiveJob(info, 0, ArchiveOptions{Force: true, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Transcript != filepath.Join(res.Dir, "transcript.jsonl.gz") || res.Lines != 2 {
+		t.Errorf("result = %+v", res)
+	}
+	if _, err := os.Stat(filepath.Join(res.Dir, "transcript.jsonl")); !os.IsNotExist(err) {
+		t.Error("uncompressed archive left behind after re-archiving compressed")
+	}
+	var out bytes.Buffer
+	if _, err := copyLineRange(&out, res.Transcript, 0, -1, false); err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Join(lines, "\n") + "\n"; out.String() != want {
+		t.Errorf("archived transcript = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,167 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/core/pkg/sessions"
+)
+
+// ArtifactScheme prefixes a spec naming an archived job directly,
+// artifact://<plan>/<job>, where <plan> is a plan name or directory and
+// <job> the job's file name or id.
+const ArtifactScheme = "artifact://"
+
+// IsArtifactSpec reports whether spec names an archived job without a
+// session scan: an artifact:// URL, or an existing path into a plan's
+// .artifacts directory (the job's directory or a file in it).
+func IsArtifactSpec(spec string) bool {
+	if strings.HasPrefix(spec, ArtifactScheme) {
+		return true
+	}
+	if !isArchivePath("/" + spec) {
+		return false
+	}
+	_, err := os.Stat(spec)
+	return err == nil
+}
+
+// ResolveArtifact reads the session archived in the artifact directory an
+// artifact spec names. Only that directory and its plan are read, never
+// the providers' log directories, so archives resolve on machines that
+// have nothing else, such as CI runners. The job's plan directory is where
+// the archive was found, whatever the metadata recorded.
+func ResolveArtifact(spec string) (*SessionInfo, error) {
+	dir, err := artifactDir(spec)
+	if err != nil {
+		return nil, err
+	}
+	info, err := NewScannerWithoutDaemon().readArchivedSession(dir)
+	if err != nil {
+		return nil, fmt.Errorf("no archived session in %s: %w", dir, err)
+	}
+	planDir := filepath.Dir(filepath.Dir(dir))
+	for i := range info.Jobs {
+		info.Jobs[i].PlanPath = planDir
+	}
+	return &info, nil
+}
+
+// artifactDir returns the job artifact directory spec names.
+func artifactDir(spec string) (string, error) {
+	rest, isURL := strings.CutPrefix(spec, ArtifactScheme)
+	if !isURL {
+		dir := spec
+		if fi, err := os.Stat(dir); err != nil {
+			return "", err
+		} else if !fi.IsDir() {
+			dir = filepath.Dir(dir)
+		}
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return "", err
+		}
+		if filepath.Base(filepath.Dir(dir)) != ".artifacts" {
+			return "", fmt.Errorf("%s is not a job's artifact directory (<plan>/.artifacts/<job>)", spec)
+		}
+		return dir, nil
+	}
+
+	i := strings.LastIndex(rest, "/")
+	if i <= 0 || i == len(rest)-1 {
+		return "", fmt.Errorf("invalid artifact URL '%s': want %s<plan>/<job>", spec, ArtifactScheme)
+	}
+	plan, job := rest[:i], rest[i+1:]
+	planDirs, err := findPlanDirs(plan)
+	if err != nil {
+		return "", err
+	}
+	var found []string
+	for _, planDir := range planDirs {
+		if dir, ok := jobArtifactDir(planDir, job); ok {
+			found = append(found, dir)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no archive of job '%s' in plan '%s'", job, plan)
+	case 1:
+		return found[0], nil
+	}
+	return "", fmt.Errorf("'%s' matches archives in %d plans; name the plan directory instead:\n  %s",
+		spec, len(found), strings.Join(found, "\n  "))
+}
+
+// findPlanDirs returns the directories an artifact URL's plan may name: the
+// plan itself when it is a directory (absolute, or relative to the working
+// directory), then plans/<plan> under the working directory, and only when
+// neither exists the workspace plans of that name.
+func findPlanDirs(plan string) ([]string, error) {
+	for _, dir := range []string{plan, filepath.Join("plans", plan)} {
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				return nil, err
+			}
+			return []string{abs}, nil
+		}
+	}
+	if strings.Contains(plan, "/") {
+		return nil, fmt.Errorf("plan directory %s not found", plan)
+	}
+	all, err := allPlanDirs()
+	if err != nil {
+		return nil, fmt.Errorf("plan '%s' not found here, and workspace plans could not be listed: %w", plan, err)
+	}
+	var dirs []string
+	for _, dir := range all {
+		if filepath.Base(dir) == plan {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("plan '%s' not found", plan)
+	}
+	return dirs, nil
+}
+
+// jobArtifactDir finds job's archive in planDir. job is the artifact
+// directory name (the job's id) or the job file name, with or without
+// ".md". When the job file is gone, the archives' metadata is searched for
+// it.
+func jobArtifactDir(planDir, job string) (string, bool) {
+	artifacts := filepath.Join(planDir, ".artifacts")
+	jobFile := strings.TrimSuffix(job, ".md") + ".md"
+	for _, name := range []string{job, jobArtifactID(planDir, jobFile)} {
+		if dir := filepath.Join(artifacts, name); hasArchive(dir) {
+			return dir, true
+		}
+	}
+	entries, err := os.ReadDir(artifacts)
+	if err != nil {
+		return "", false
+	}
+	for _, e := range entries {
+		dir := filepath.Join(artifacts, e.Name())
+		if !e.IsDir() || !hasArchive(dir) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "metadata.json"))
+		if err != nil {
+			continue
+		}
+		var metadata sessions.SessionMetadata
+		if json.Unmarshal(data, &metadata) == nil && filepath.Base(metadata.JobFilePath) == jobFile {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+func hasArchive(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "metadata.json"))
+	return err == nil
+}
@@ -0,0 +1,67 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveArtifact(t *testing.T) {
+	dir := t.TempDir()
+	plan := filepath.Join(dir, "plans", "feature")
+	if err := os.MkdirAll(plan, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	jobFile := filepath.Join(plan, "01-spec.md")
+	if err := os.WriteFile(jobFile, []byte("---\nid: spec-a1b2\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "c1.jsonl")
+	if err := os.WriteFile(logPath, []byte(`{"type":"user","cwd":"/work/app","sessionId":"c1"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ArchiveJob(SessionInfo{
+		SessionID: "c1", Provider: "claude", LogFilePath: logPath,
+		Jobs: []JobInfo{{Plan: "feature", Job: "01-spec.md", PlanPath: plan}},
+	}, 0, ArchiveOptions{Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	specs := []string{
+		ArtifactScheme + "feature/01-spec.md",
+		ArtifactScheme + "feature/spec-a1b2",
+		ArtifactScheme + plan + "/01-spec",
+		"plans/feature/.artifacts/spec-a1b2",
+		res.Transcript,
+	}
+	for _, spec := range specs {
+		if !IsArtifactSpec(spec) {
+			t.Errorf("IsArtifactSpec(%q) = false", spec)
+			continue
+		}
+		info, err := ResolveArtifact(spec)
+		if err != nil {
+			t.Errorf("ResolveArtifact(%q): %v", spec, err)
+			continue
+		}
+		if info.SessionID != "c1" || info.LogFilePath != res.Transcript || len(info.Jobs) != 1 || info.Jobs[0].PlanPath != plan {
+			t.Errorf("ResolveArtifact(%q) = %+v", spec, info)
+		}
+	}
+
+	// The job file is not needed: archives are also matched by metadata.
+	if err := os.Remove(jobFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ResolveArtifact(ArtifactScheme + "feature/01-spec.md"); err != nil {
+		t.Errorf("without the job file: %v", err)
+	}
+	if _, err := ResolveArtifact(ArtifactScheme + "feature/02-impl.md"); err == nil {
+		t.Error("resolved a job that was never archived")
+	}
+	if IsArtifactSpec("feature/01-spec.md") {
+		t.Error("a plan/job spec is not an artifact spec")
+	}
+}
@@ -0,0 +1,47 @@
+package session
+
+import (
+	"encoding/json"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// ReadGitInfo returns the git branch a session was started on and, when
+// recorded, the HEAD commit, as found near the start of its transcript:
+// Claude stamps gitBranch on each line, and Codex records both in
+// session_meta. Other providers, and transcripts without the fields, yield
+// "".
+func ReadGitInfo(logPath, provider string) (branch, commit string) {
+	if provider != "claude" && provider != "codex" {
+		return "", ""
+	}
+	file, err := transcript.OpenTranscript(logPath)
+	if err != nil {
+		return "", ""
+	}
+	defer file.Close()
+
+	scanner := transcript.NewLineScanner(file)
+	for lines := 0; scanner.Scan() && lines <= 100; lines++ {
+		var entry struct {
+			GitBranch string `json:"gitBranch"`
+			Type      string `json:"type"`
+			Payload   struct {
+				Git struct {
+					Branch     string `json:"branch"`
+					CommitHash string `json:"commit_hash"`
+				} `json:"git"`
+			} `json:"payload"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		if entry.GitBranch != "" {
+			return entry.GitBranch, ""
+		}
+		if entry.Type == "session_meta" && (entry.Payload.Git.Branch != "" || entry.Payload.Git.CommitHash != "") {
+			return entry.Payload.Git.Branch, entry.Payload.Git.CommitHash
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,28 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadGitInfo(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		provider, content, branch, commit string
+	}{
+		{"claude", `{"type":"summary"}` + "\n" + `{"type":"user","gitBranch":"feat/login"}` + "\n", "feat/login", ""},
+		{"codex", `{"type":"session_meta","payload":{"id":"x","git":{"branch":"main","commit_hash":"abc"}}}` + "\n", "main", "abc"},
+		{"codex", `{"type":"session_meta","payload":{"id":"x"}}` + "\n", "", ""},
+		{"pi", `{"type":"session","gitBranch":"ignored"}` + "\n", "", ""},
+	}
+	for i, tt := range tests {
+		path := filepath.Join(dir, tt.provider+string(rune('a'+i))+".jsonl")
+		if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if branch, commit := ReadGitInfo(path, tt.provider); branch != tt.branch || commit != tt.commit {
+			t.Errorf("ReadGitInfo(%s #%d) = %q, %q, want %q, %q", tt.provider, i, branch, commit, tt.branch, tt.commit)
+		}
+	}
+}
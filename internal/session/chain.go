@@ -0,0 +1,304 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// A resumed Claude session ('claude --resume', '--continue') is written to
+// a new transcript file under a new session ID. The new file links back to
+// the one it resumed in one of three ways, depending on the Claude version:
+// its first message's parentUuid names the last message of the old file; a
+// leading summary line's leafUuid does; or it starts with a copy of the old
+// file's messages, which keep their uuids (and sometimes their sessionId).
+// A chain is the sequence of files one logical session was written to.
+
+// ChainFile is one transcript file of a chain.
+type ChainFile struct {
+	Path      string `json:"path"`
+	SessionID string `json:"sessionId"`
+	// Start is the first line the file adds to the chain; the lines before
+	// it repeat the history of the files before it.
+	Start int `json:"start"`
+	// Jobs are the plan jobs started in the file's own lines.
+	Jobs []JobInfo `json:"jobs,omitempty"`
+}
+
+// Chain is the files of a resumed session, oldest first.
+type Chain []ChainFile
+
+// ChainSegment is a line range of one file of a chain, EndLine exclusive
+// (-1 = to the end of the file).
+type ChainSegment struct {
+	Path      string
+	StartLine int
+	EndLine   int
+}
+
+// chainHeader is what linking a transcript into a chain needs from it.
+type chainHeader struct {
+	path      string
+	sessionID string
+	modTime   int64
+	// uuids are the message uuids in the file, in order.
+	uuids []string
+	// lineOf is the line index of each uuid.
+	lineOf map[string]int
+	// links are uuids the file refers to without containing them: the
+	// first message's parentUuid and summaries' leafUuids.
+	links []string
+	// foreign are the other session IDs the file's lines carry.
+	foreign map[string]bool
+}
+
+func readChainHeader(path string) (*chainHeader, error) {
+	f, err := transcript.OpenTranscript(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := &chainHeader{
+		path:      path,
+		sessionID: transcript.TrimTranscriptExt(filepath.Base(path)),
+		lineOf:    make(map[string]int),
+		foreign:   make(map[string]bool),
+	}
+	if fi, err := os.Stat(path); err == nil {
+		h.modTime = fi.ModTime().UnixNano()
+	}
+	var parents, leaves []string
+	sc := transcript.NewLineScanner(f)
+	for line := 0; sc.Scan(); line++ {
+		var e struct {
+			Type       string `json:"type"`
+			UUID       string `json:"uuid"`
+			ParentUUID string `json:"parentUuid"`
+			LeafUUID   string `json:"leafUuid"`
+			SessionID  string `json:"sessionId"`
+		}
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		if e.SessionID != "" && e.SessionID != h.sessionID {
+			h.foreign[e.SessionID] = true
+		}
+		if e.LeafUUID != "" {
+			leaves = append(leaves, e.LeafUUID)
+		}
+		if e.UUID != "" {
+			if len(h.uuids) == 0 && e.ParentUUID != "" {
+				parents = append(parents, e.ParentUUID)
+			}
+			h.uuids = append(h.uuids, e.UUID)
+			h.lineOf[e.UUID] = line
+		}
+	}
+	h.links = appendUnique(nil, append(parents, leaves...), h.lineOf)
+	return h, sc.Err()
+}
+
+// appendUnique appends the uuids not in own.
+func appendUnique(dst, uuids []string, own map[string]int) []string {
+	for _, u := range uuids {
+		if _, ok := own[u]; !ok && !slices.Contains(dst, u) {
+			dst = append(dst, u)
+		}
+	}
+	return dst
+}
+
+// resumes reports how strongly b resumes a: the number of its links and
+// copied messages found in a, or 0 when b doesn't resume a.
+func (b *chainHeader) resumes(a *chainHeader) int {
+	if a == b {
+		return 0
+	}
+	score := 0
+	if b.foreign[a.sessionID] {
+		score++
+	}
+	for _, u := range b.links {
+		if _, ok := a.lineOf[u]; ok {
+			score++
+		}
+	}
+	// A copied history only counts when b goes on past it; otherwise a
+	// could as well have copied b.
+	copied := 0
+	for _, u := range b.uuids {
+		if _, ok := a.lineOf[u]; !ok {
+			break
+		}
+		copied++
+	}
+	if copied < len(b.uuids) {
+		score += copied
+	}
+	return score
+}
+
+// ResumeChain returns the chain of Claude transcripts that the one at path
+// belongs to: the files it resumed and the files resuming it, oldest first.
+// A transcript that was never resumed is a chain of one. Transcripts of
+// other providers are always chains of one.
+func ResumeChain(path string) (Chain, error) {
+	if ProviderForPath(path) != "claude" {
+		return Chain{{Path: path, SessionID: transcript.TrimTranscriptExt(filepath.Base(path))}}, nil
+	}
+	matches, err := transcript.GlobTranscripts(filepath.Join(filepath.Dir(path), "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	var headers []*chainHeader
+	var self *chainHeader
+	for _, m := range matches {
+		if strings.HasPrefix(filepath.Base(m), "agent-") {
+			continue
+		}
+		h, err := readChainHeader(m)
+		if err != nil {
+			if m == path {
+				return nil, err
+			}
+			continue
+		}
+		if m == path {
+			self = h
+		}
+		headers = append(headers, h)
+	}
+	if self == nil {
+		h, err := readChainHeader(path)
+		if err != nil {
+			return nil, err
+		}
+		self = h
+		headers = append(headers, h)
+	}
+
+	// Each file resumes at most one other: the one it links to most
+	// strongly, or, when it copied the history of several, the newest.
+	pred := make(map[*chainHeader]*chainHeader)
+	for _, b := range headers {
+		var best *chainHeader
+		bestScore := 0
+		for _, a := range headers {
+			score := b.resumes(a)
+			if score > bestScore || (score > 0 && score == bestScore && a.modTime > best.modTime) {
+				best, bestScore = a, score
+			}
+		}
+		if best != nil {
+			pred[b] = best
+		}
+	}
+
+	// Walk back to the first file, then forward through the newest file
+	// resuming each.
+	var back []*chainHeader
+	seen := map[*chainHeader]bool{}
+	for h := self; h != nil && !seen[h]; h = pred[h] {
+		seen[h] = true
+		back = append(back, h)
+	}
+	var files []*chainHeader
+	for i := len(back) - 1; i >= 0; i-- {
+		files = append(files, back[i])
+	}
+	for h := self; ; {
+		var next *chainHeader
+		for _, b := range headers {
+			if pred[b] == h && !seen[b] && (next == nil || b.modTime > next.modTime) {
+				next = b
+			}
+		}
+		if next == nil {
+			break
+		}
+		seen[next] = true
+		files = append(files, next)
+		h = next
+	}
+
+	scanner := NewScannerWithoutDaemon()
+	chain := make(Chain, 0, len(files))
+	earlier := make(map[string]bool)
+	for _, h := range files {
+		cf := ChainFile{Path: h.path, SessionID: h.sessionID}
+		for _, u := range h.uuids {
+			if !earlier[u] {
+				break
+			}
+			cf.Start = h.lineOf[u] + 1
+		}
+		if len(files) > 1 {
+			_, _, _, jobs, _, _, _, _ := scanner.parseClaudeLog(h.path)
+			for _, j := range jobs {
+				if j.LineIndex >= cf.Start {
+					cf.Jobs = append(cf.Jobs, j)
+				}
+			}
+		}
+		for _, u := range h.uuids {
+			earlier[u] = true
+		}
+		chain = append(chain, cf)
+	}
+	return chain, nil
+}
+
+// Segments returns the line ranges that make up the chain's logical
+// transcript: each file from its Start on.
+func (c Chain) Segments() []ChainSegment {
+	segs := make([]ChainSegment, 0, len(c))
+	for _, f := range c {
+		segs = append(segs, ChainSegment{Path: f.Path, StartLine: f.Start, EndLine: -1})
+	}
+	return segs
+}
+
+// JobSegments returns the line ranges covering the job that starts at
+// startLine of the file at path: up to the next job started anywhere later
+// in the chain, so a job resumed into newer files ends where it really
+// ended. It returns nil when path is not in the chain.
+func (c Chain) JobSegments(path string, startLine int) []ChainSegment {
+	at := -1
+	for i, f := range c {
+		if f.Path == path {
+			at = i
+			break
+		}
+	}
+	if at == -1 {
+		return nil
+	}
+	var segs []ChainSegment
+	for i := at; i < len(c); i++ {
+		f := c[i]
+		seg := ChainSegment{Path: f.Path, StartLine: f.Start, EndLine: -1}
+		if i == at {
+			seg.StartLine = startLine
+		}
+		next := -1
+		for _, j := range f.Jobs {
+			if j.LineIndex > seg.StartLine || (i > at && j.LineIndex >= seg.StartLine) {
+				next = j.LineIndex
+				break
+			}
+		}
+		if next != -1 {
+			if next > seg.StartLine {
+				seg.EndLine = next
+				segs = append(segs, seg)
+			}
+			return segs
+		}
+		segs = append(segs, seg)
+	}
+	return segs
+}
@@ -0,0 +1,83 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResumeChain(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".claude", "projects", "-repo")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	msg := func(session, uuid, parent, text string) string {
+		p := "null"
+		if parent != "" {
+			p = fmt.Sprintf("%q", parent)
+		}
+		return fmt.Sprintf(`{"type":"user","sessionId":%q,"uuid":%q,"parentUuid":%s,"cwd":"/repo","timestamp":"2026-01-01T10:00:00Z","message":{"role":"user","content":%q}}`,
+			session, uuid, p, text)
+	}
+	job := func(name string) string { return FormatJobMarker("", "", "/repo/plans/p/"+name) }
+	write := func(id string, lines ...string) string {
+		path := filepath.Join(dir, id+".jsonl")
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// a runs job 01; b resumes it by copying a's history, finishes 01 and
+	// starts 02; c resumes b by parentUuid and finishes 02.
+	a := write("a", msg("a", "a1", "", job("01-spec.md")), msg("a", "a2", "a1", "working"))
+	b := write("b",
+		`{"type":"summary","summary":"Spec","leafUuid":"a2"}`,
+		msg("a", "a1", "", job("01-spec.md")), msg("a", "a2", "a1", "working"),
+		msg("b", "b1", "a2", "more spec"), msg("b", "b2", "b1", job("02-impl.md")), msg("b", "b3", "b2", "impl"))
+	c := write("c", msg("c", "c1", "b3", "more impl"), msg("c", "c2", "c1", "done"))
+	lone := write("lone", msg("lone", "l1", "", "hello"))
+
+	chain, err := ResumeChain(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range chain {
+		got = append(got, fmt.Sprintf("%s@%d", f.SessionID, f.Start))
+	}
+	if strings.Join(got, " ") != "a@0 b@3 c@0" {
+		t.Fatalf("chain = %v, want a@0 b@3 c@0", got)
+	}
+	if len(chain[1].Jobs) != 1 || chain[1].Jobs[0].Job != "02-impl.md" {
+		t.Errorf("b's own jobs = %+v, want just 02-impl.md", chain[1].Jobs)
+	}
+	for _, from := range []string{a, c} {
+		if other, _ := ResumeChain(from); len(other) != 3 {
+			t.Errorf("ResumeChain(%s) has %d files, want 3", filepath.Base(from), len(other))
+		}
+	}
+	if solo, _ := ResumeChain(lone); len(solo) != 1 {
+		t.Errorf("ResumeChain(lone) has %d files, want 1", len(solo))
+	}
+
+	seg := func(segs []ChainSegment) string {
+		var parts []string
+		for _, s := range segs {
+			parts = append(parts, fmt.Sprintf("%s[%d:%d]", strings.TrimSuffix(filepath.Base(s.Path), ".jsonl"), s.StartLine, s.EndLine))
+		}
+		return strings.Join(parts, " ")
+	}
+	if got := seg(chain.Segments()); got != "a[0:-1] b[3:-1] c[0:-1]" {
+		t.Errorf("Segments = %s", got)
+	}
+	// Job 01 runs on from a into b, up to where 02 starts.
+	if got := seg(chain.JobSegments(a, 0)); got != "a[0:-1] b[3:4]" {
+		t.Errorf("JobSegments(01) = %s", got)
+	}
+	if got := seg(chain.JobSegments(b, 4)); got != "b[4:-1] c[0:-1]" {
+		t.Errorf("JobSegments(02) = %s", got)
+	}
+}
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// AgentEnvironment is the execution environment an agent reported for a
+// session. Codex records it as an <environment_context> block in the first
+// user turn; fields it did not report are empty.
+type AgentEnvironment struct {
+	Cwd            string `json:"cwd,omitempty"`
+	Shell          string `json:"shell,omitempty"`
+	ApprovalPolicy string `json:"approvalPolicy,omitempty"`
+	SandboxMode    string `json:"sandboxMode,omitempty"`
+	NetworkAccess  string `json:"networkAccess,omitempty"`
+}
+
+// IsZero reports whether no environment field is set.
+func (e AgentEnvironment) IsZero() bool {
+	return e == AgentEnvironment{}
+}
+
+var environmentTagPattern = regexp.MustCompile(`<([a-z_]+)>([^<]*)</([a-z_]+)>`)
+
+// ParseCodexEnvironment extracts the fields of a Codex <environment_context>
+// block. It reports false when text has no such block. Unknown tags, and
+// nested ones such as <writable_roots>, are ignored.
+func ParseCodexEnvironment(text string) (AgentEnvironment, bool) {
+	start := strings.Index(text, "<environment_context>")
+	if start < 0 {
+		return AgentEnvironment{}, false
+	}
+	block := text[start:]
+	if end := strings.Index(block, "</environment_context>"); end >= 0 {
+		block = block[:end]
+	}
+
+	var env AgentEnvironment
+	for _, m := range environmentTagPattern.FindAllStringSubmatch(block, -1) {
+		if m[1] != m[3] {
+			continue
+		}
+		value := strings.TrimSpace(m[2])
+		switch m[1] {
+		case "cwd":
+			env.Cwd = value
+		case "shell":
+			env.Shell = value
+		case "approval_policy":
+			env.ApprovalPolicy = value
+		case "sandbox_mode":
+			env.SandboxMode = value
+		case "network_access":
+			env.NetworkAccess = value
+		}
+	}
+	return env, true
+}
+
+// ReadCodexEnvironment returns the environment recorded near the start of a
+// Codex rollout file, or nil when the file has none.
+func ReadCodexEnvironment(logPath string) *AgentEnvironment {
+	file, err := transcript.OpenTranscript(logPath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	scanner := transcript.NewLineScanner(file)
+	for lines := 0; scanner.Scan() && lines <= 100; lines++ {
+		if !strings.Contains(scanner.Text(), "<environment_context>") {
+			continue
+		}
+		var entry struct {
+			Type    string `json:"type"`
+			Payload struct {
+				Type    string `json:"type"`
+				Role    string `json:"role"`
+				Content []struct {
+					Type string `json:"type"`
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"payload"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Type != "response_item" || entry.Payload.Role != "user" {
+			continue
+		}
+		for _, c := range entry.Payload.Content {
+			if c.Type != "input_text" {
+				continue
+			}
+			if env, ok := ParseCodexEnvironment(c.Text); ok {
+				return &env
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const codexEnvironmentText = `<environment_context>
+  <cwd>/repo/app</cwd>
+  <approval_policy>on-request</approval_policy>
+  <sandbox_mode>workspace-write</sandbox_mode>
+  <writable_roots>
+    <root>/tmp</root>
+  </writable_roots>
+  <network_access>restricted</network_access>
+  <shell>zsh</shell>
+</environment_context>`
+
+func TestParseCodexEnvironment(t *testing.T) {
+	env, ok := ParseCodexEnvironment(codexEnvironmentText)
+	if !ok {
+		t.Fatal("environment_context block not recognized")
+	}
+	want := AgentEnvironment{
+		Cwd:            "/repo/app",
+		Shell:          "zsh",
+		ApprovalPolicy: "on-request",
+		SandboxMode:    "workspace-write",
+		NetworkAccess:  "restricted",
+	}
+	if env != want {
+		t.Errorf("got %+v, want %+v", env, want)
+	}
+
+	if _, ok := ParseCodexEnvironment("plain prompt"); ok {
+		t.Error("plain text parsed as an environment_context block")
+	}
+}
+
+func TestScanCodexEnvironment(t *testing.T) {
+	home := setupScanHome(t)
+	dir := filepath.Join(home, ".codex", "sessions", "2026", "01", "01")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	id := "0000aaaa-0000-4000-8000-00000000e0e0"
+	lines := `{"timestamp":"2026-01-01T00:00:00Z","type":"session_meta","payload":{"id":"` + id + `","timestamp":"2026-01-01T00:00:00Z","cwd":"/repo/app"}}
+{"timestamp":"2026-01-01T00:00:01Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"<environment_context>\n  <cwd>/repo/app</cwd>\n  <sandbox_mode>read-only</sandbox_mode>\n  <shell>bash</shell>\n</environment_context>"}]}}
+`
+	path := filepath.Join(dir, "rollout-2026-01-01T00-00-00-"+id+".jsonl")
+	if err := os.WriteFile(path, []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := NewScannerWithoutDaemon().Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range sessions {
+		if s.SessionID != id {
+			continue
+		}
+		if s.Environment == nil || s.Environment.SandboxMode != "read-only" || s.Environment.Shell != "bash" {
+			t.Errorf("Environment = %+v, want sandbox read-only and shell bash", s.Environment)
+		}
+		if got := ReadCodexEnvironment(path); got == nil || *got != *s.Environment {
+			t.Errorf("ReadCodexEnvironment = %+v, want %+v", got, s.Environment)
+		}
+		return
+	}
+	t.Fatalf("codex session %s not scanned", id)
+}
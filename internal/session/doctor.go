@@ -0,0 +1,163 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// Kinds of transcript problem.
+const (
+	ProblemUnreadable  = "unreadable"   // the file cannot be opened or decompressed
+	ProblemInvalidJSON = "invalid-json" // a line is not valid JSON
+	ProblemTruncated   = "truncated"    // the last line was cut off mid-write
+	ProblemNoSession   = "no-session"   // no session id was found
+)
+
+// TranscriptProblem is one problem found in a transcript file.
+type TranscriptProblem struct {
+	Kind string `json:"kind"`
+	// Line is the 1-based line number, or 0 for problems with the whole file.
+	Line   int    `json:"line,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Suggestion returns a fix for the kind of problem, for display.
+func (p TranscriptProblem) Suggestion() string {
+	switch p.Kind {
+	case ProblemUnreadable:
+		return "check the file's permissions; a .gz file that fails to decompress was likely cut off while compressing and can be restored from a backup or deleted"
+	case ProblemInvalidJSON:
+		return "the line is skipped when reading; if the file was edited by hand, restore the line or delete it"
+	case ProblemTruncated:
+		return "the agent stopped mid-write (crash, kill or full disk); the partial line is skipped and can be deleted safely"
+	case ProblemNoSession:
+		return "the session lists as \"unknown\"; the file may be empty, from an unsupported agent version, or have its header line damaged"
+	}
+	return ""
+}
+
+// TranscriptReport is the result of checking one transcript file.
+type TranscriptReport struct {
+	Path     string              `json:"path"`
+	Provider string              `json:"provider"`
+	Lines    int                 `json:"lines"`
+	Problems []TranscriptProblem `json:"problems,omitempty"`
+}
+
+// FindTranscriptFiles lists every JSONL transcript of the providers that
+// keep one, including Claude sub-agent files and compressed transcripts,
+// under the transcript roots (see Sources).
+func FindTranscriptFiles(homeDir string) []string {
+	var paths []string
+	for _, src := range Sources(homeDir) {
+		if pattern := src.TranscriptGlob(); pattern != "" {
+			matches, _ := transcript.GlobTranscripts(pattern)
+			paths = append(paths, matches...)
+		}
+	}
+	return paths
+}
+
+// CheckTranscripts checks paths concurrently, returning reports in the
+// order of paths.
+func CheckTranscripts(paths []string) []TranscriptReport {
+	reports := make([]TranscriptReport, len(paths))
+	scanner := NewScannerWithoutDaemon()
+	ParallelEach(len(paths), func(i int) {
+		reports[i] = scanner.checkTranscript(paths[i])
+	})
+	return reports
+}
+
+// CheckTranscript reads a whole transcript file and reports the lines the
+// scanner and parsers would skip, and whether the scanner can identify its
+// session.
+func CheckTranscript(path string) TranscriptReport {
+	return NewScannerWithoutDaemon().checkTranscript(path)
+}
+
+func (s *Scanner) checkTranscript(path string) TranscriptReport {
+	report := TranscriptReport{Path: path, Provider: ProviderForPath(path)}
+	f, err := transcript.OpenTranscript(path)
+	if err != nil {
+		report.Problems = append(report.Problems, TranscriptProblem{Kind: ProblemUnreadable, Reason: err.Error()})
+		return report
+	}
+	defer f.Close()
+
+	r := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, complete, err := readLine(r)
+		if len(line) == 0 && err != nil {
+			if err != io.EOF {
+				report.Problems = append(report.Problems, TranscriptProblem{Kind: ProblemUnreadable, Line: report.Lines + 1, Reason: err.Error()})
+			}
+			break
+		}
+		report.Lines++
+		if p, ok := checkLine(line, complete); ok {
+			p.Line = report.Lines
+			report.Problems = append(report.Problems, p)
+		}
+		if err != nil {
+			if err != io.EOF {
+				report.Problems = append(report.Problems, TranscriptProblem{Kind: ProblemUnreadable, Line: report.Lines, Reason: err.Error()})
+			}
+			break
+		}
+	}
+
+	// Sub-agent files are never listed as sessions of their own.
+	if strings.HasPrefix(filepath.Base(path), "agent-") {
+		return report
+	}
+	if !s.parseLog(path, report.Provider).found {
+		report.Problems = append(report.Problems, TranscriptProblem{
+			Kind:   ProblemNoSession,
+			Reason: "no session id and working directory in the first 100 lines",
+		})
+	}
+	return report
+}
+
+// readLine reads one line, of any length, without its newline. complete is
+// false when the line ended at EOF without a newline.
+func readLine(r *bufio.Reader) (line []byte, complete bool, err error) {
+	line, err = r.ReadBytes('\n')
+	if err == nil {
+		return bytes.TrimSuffix(line, []byte("\n")), true, nil
+	}
+	return line, false, err
+}
+
+// checkLine reports the problem with one line, if any.
+func checkLine(line []byte, complete bool) (TranscriptProblem, bool) {
+	if len(strings.TrimSpace(string(line))) == 0 {
+		return TranscriptProblem{}, false
+	}
+	var v json.RawMessage
+	err := json.Unmarshal(line, &v)
+	if err == nil {
+		return TranscriptProblem{}, false
+	}
+	if !complete {
+		return TranscriptProblem{
+			Kind:   ProblemTruncated,
+			Reason: fmt.Sprintf("last line is cut off after %d bytes", len(line)),
+		}, true
+	}
+	reason := err.Error()
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		reason = fmt.Sprintf("%s at byte %d", syntax.Error(), syntax.Offset)
+	}
+	return TranscriptProblem{Kind: ProblemInvalidJSON, Reason: reason}, true
+}
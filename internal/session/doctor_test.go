@@ -0,0 +1,63 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckTranscript(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".claude", "projects", "-work")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	header := `{"type":"user","sessionId":"s1","cwd":"/work","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"hi"}}`
+	lines := []string{
+		header,
+		`{"type":"assistant",`,
+		"",
+		// Far longer than bufio.Scanner's default limits, but valid.
+		`{"type":"user","text":"` + strings.Repeat("x", 2*1024*1024) + `"}`,
+		`{"type":"assistant","message":{"role":"assistant","content":"par`,
+	}
+	path := filepath.Join(dir, "s1.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report := CheckTranscript(path)
+	if report.Lines != 5 || report.Provider != "claude" {
+		t.Errorf("report = %d lines, provider %q", report.Lines, report.Provider)
+	}
+	var got []string
+	for _, p := range report.Problems {
+		got = append(got, fmt.Sprintf("%s@%d", p.Kind, p.Line))
+	}
+	want := []string{ProblemInvalidJSON + "@2", ProblemTruncated + "@5"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("problems = %v, want %v", got, want)
+	}
+	for _, p := range report.Problems {
+		if p.Suggestion() == "" {
+			t.Errorf("no suggestion for %s", p.Kind)
+		}
+	}
+}
+
+func TestCheckTranscriptNoSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s2.jsonl")
+	if err := os.WriteFile(path, []byte(`{"type":"summary","summary":"x"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report := CheckTranscript(path)
+	if len(report.Problems) != 1 || report.Problems[0].Kind != ProblemNoSession {
+		t.Errorf("problems = %+v, want one %s", report.Problems, ProblemNoSession)
+	}
+
+	report = CheckTranscript(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if len(report.Problems) != 1 || report.Problems[0].Kind != ProblemUnreadable {
+		t.Errorf("problems = %+v, want one %s", report.Problems, ProblemUnreadable)
+	}
+}
@@ -1,12 +1,27 @@
 package session
 
-import "time"
+import (
+	"path/filepath"
+	"time"
+)
 
 // JobInfo holds information about a grove plan job found in the transcript
 type JobInfo struct {
 	Plan      string `json:"plan"`
 	Job       string `json:"job"`
 	LineIndex int    `json:"lineIndex"`
+	// PlanPath is the absolute plan directory, when known. Plan is only its
+	// base name, which can collide across projects.
+	PlanPath string `json:"planPath,omitempty"`
+}
+
+// planPathOf returns the plan directory of an absolute job file path, or ""
+// for relative or empty paths.
+func planPathOf(jobFilePath string) string {
+	if !filepath.IsAbs(jobFilePath) {
+		return ""
+	}
+	return filepath.Dir(jobFilePath)
 }
 
 // SessionInfo holds structured information about a session transcript
@@ -19,7 +34,59 @@ type SessionInfo struct {
 	Jobs        []JobInfo `json:"jobs,omitempty"`
 	LogFilePath string    `json:"logFilePath"`
 	StartedAt   time.Time `json:"startedAt"`
-	Provider    string    `json:"provider,omitempty"` // "claude", "codex", or "opencode"
+	Provider    string    `json:"provider,omitempty"` // "claude", "codex", "pi", or "opencode"
 	Status      string    `json:"status,omitempty"`   // "running", "idle", "completed", etc.
 	PID         int       `json:"pid,omitempty"`      // Process ID when running
+	User        string    `json:"user,omitempty"`     // Registry user, or the OS user owning a local transcript
+	EndedAt     time.Time `json:"endedAt,omitzero"`   // Last recorded activity; zero when unknown
+	// Title is the conversation title the agent generated (Claude summary
+	// entries, OpenCode session titles), or else the first line of the
+	// first user prompt.
+	Title string `json:"title,omitempty"`
+	// Preview is the start of the first user prompt, on one line.
+	Preview string `json:"preview,omitempty"`
+	// Model is the model the session's first assistant turn ran on
+	// (claude-sonnet-4-5-20250929, gpt-5-codex), as its transcript records it.
+	Model string `json:"model,omitempty"`
+	// GitBranch is the branch the session's working directory was on when
+	// it started, and GitCommit the HEAD commit then, when recorded
+	// (currently Codex only).
+	GitBranch string `json:"gitBranch,omitempty"`
+	GitCommit string `json:"gitCommit,omitempty"`
+	// Environment is the sandbox and shell setup the agent reported, when
+	// its transcript records one (currently Codex only).
+	Environment *AgentEnvironment `json:"environment,omitempty"`
+	// Activity is the latest current-activity line of the session's AI
+	// summary, when one was published; only filled in by callers that ask
+	// for summaries.
+	Activity string `json:"activity,omitempty"`
+	// Test is true for test and demo runs (marked, or detected as a tend
+	// scenario); only filled in by callers that check marks.
+	Test bool `json:"test,omitempty"`
+	// Tags and Notes are what the user recorded about the session with
+	// 'aglogs annotate'; only filled in by callers that check marks.
+	Tags  []string `json:"tags,omitempty"`
+	Notes []Note   `json:"notes,omitempty"`
+	// Pinned is true for sessions pinned with 'aglogs pin', whose
+	// transcripts prune keeps; only filled in by callers that check marks.
+	Pinned bool `json:"pinned,omitempty"`
+	// Remote is the URL of the job's archive for sessions listed from a
+	// remote archive; their LogFilePath is a local copy, fetched on first
+	// read.
+	Remote string `json:"remote,omitempty"`
+}
+
+// Note is a free-form comment the user attached to a session.
+type Note struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Duration is the wall-clock span from StartedAt to EndedAt, or 0 when
+// either end is unknown. For running sessions it is the time so far.
+func (s SessionInfo) Duration() time.Duration {
+	if s.StartedAt.IsZero() || s.EndedAt.IsZero() || s.EndedAt.Before(s.StartedAt) {
+		return 0
+	}
+	return s.EndedAt.Sub(s.StartedAt)
 }
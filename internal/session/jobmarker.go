@@ -0,0 +1,149 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/core/logging"
+)
+
+// jobMarkerOpen starts the structured job marker a job runner embeds in the
+// prompt that starts a job:
+//
+//	<!-- grove-job {"plan":"my-plan","job":"01-spec.md","path":"/repo/plans/my-plan/01-spec.md"} -->
+//
+// Being an HTML comment, it is invisible in rendered markdown, and unlike
+// the prompt's wording it is a contract: runners may reword their prompts
+// freely as long as they keep emitting it.
+const jobMarkerOpen = "<!-- grove-job "
+
+// jobMarker is the JSON payload of a job marker. Path is the job file; Plan
+// and Job default to its directory and file names.
+type jobMarker struct {
+	Plan string `json:"plan,omitempty"`
+	Job  string `json:"job,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// FormatJobMarker returns the marker identifying a job, for a runner to embed
+// in the job's prompt.
+func FormatJobMarker(plan, job, path string) string {
+	data, _ := json.Marshal(jobMarker{Plan: plan, Job: job, Path: path})
+	return jobMarkerOpen + string(data) + " -->"
+}
+
+// parseJobMarker reads the first job marker in content.
+func parseJobMarker(content string) (plan, job, planPath string, ok bool) {
+	i := strings.Index(content, jobMarkerOpen)
+	if i == -1 {
+		return "", "", "", false
+	}
+	var m jobMarker
+	if err := json.NewDecoder(strings.NewReader(content[i+len(jobMarkerOpen):])).Decode(&m); err != nil {
+		return "", "", "", false
+	}
+	return jobFromParts(m.Plan, m.Job, m.Path)
+}
+
+// DefaultJobPatterns match the prompt grove-flow starts agent jobs with,
+// "Read the file <job file> and execute the agent job".
+var DefaultJobPatterns = []string{
+	`Read the file (?P<path>/\S*/plans/[^/\s]+/[^/\s]+\.md)\b[\s\S]*and execute the agent job`,
+}
+
+// JobMatcher finds the flow job a user prompt starts. A structured job marker
+// wins; the patterns are the fallback for prompts without one.
+type JobMatcher struct {
+	patterns []*regexp.Regexp
+	// markersOnly ignores the patterns.
+	markersOnly bool
+}
+
+// NewJobMatcher compiles patterns, or DefaultJobPatterns when there are none.
+// Each pattern must capture the job file in a group named "path", or the plan
+// and job names in groups named "plan" and "job".
+func NewJobMatcher(patterns []string, markersOnly bool) (*JobMatcher, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultJobPatterns
+	}
+	m := &JobMatcher{markersOnly: markersOnly}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job pattern %q: %w", p, err)
+		}
+		if re.SubexpIndex("path") == -1 && (re.SubexpIndex("plan") == -1 || re.SubexpIndex("job") == -1) {
+			return nil, fmt.Errorf("job pattern %q captures neither (?P<path>...) nor (?P<plan>...) and (?P<job>...)", p)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// JobMatcherFromConfig builds the matcher the jobs section of the aglogs
+// config describes.
+func JobMatcherFromConfig(cfg config.Config) (*JobMatcher, error) {
+	return NewJobMatcher(cfg.Jobs.Patterns, cfg.Jobs.MarkersOnly)
+}
+
+// Match returns the plan and job a prompt starts, and the plan directory when
+// the prompt names the job file by absolute path. plan and job are empty when
+// the prompt starts no job.
+func (m *JobMatcher) Match(content string) (plan, job, planPath string) {
+	if plan, job, planPath, ok := parseJobMarker(content); ok {
+		return plan, job, planPath
+	}
+	if m.markersOnly {
+		return "", "", ""
+	}
+	for _, re := range m.patterns {
+		sub := re.FindStringSubmatch(content)
+		if sub == nil {
+			continue
+		}
+		group := func(name string) string {
+			if i := re.SubexpIndex(name); i != -1 {
+				return sub[i]
+			}
+			return ""
+		}
+		if plan, job, planPath, ok := jobFromParts(group("plan"), group("job"), group("path")); ok {
+			return plan, job, planPath
+		}
+	}
+	return "", "", ""
+}
+
+// jobFromParts fills in whichever of plan and job are missing from the job
+// file path.
+func jobFromParts(plan, job, path string) (string, string, string, bool) {
+	if path != "" {
+		if job == "" {
+			job = filepath.Base(path)
+		}
+		if plan == "" {
+			plan = filepath.Base(filepath.Dir(path))
+		}
+	}
+	if plan == "" || job == "" || plan == "." || plan == "/" {
+		return "", "", "", false
+	}
+	return plan, job, planPathOf(path), true
+}
+
+// configuredJobMatcher is the matcher of scanners without
+// ScanOptions.JobMatcher, built from the config on first use. An invalid
+// config falls back to the defaults.
+var configuredJobMatcher = sync.OnceValue(func() *JobMatcher {
+	m, err := JobMatcherFromConfig(config.Load())
+	if err != nil {
+		logging.NewLogger("aglogs-scan").WithError(err).Warn("Ignoring invalid jobs config")
+		m, _ = NewJobMatcher(nil, false)
+	}
+	return m
+})
@@ -0,0 +1,52 @@
+package session
+
+import "testing"
+
+func TestJobMatcher(t *testing.T) {
+	defaults, err := NewJobMatcher(nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom, err := NewJobMatcher([]string{`Start job (?P<plan>[\w-]+)/(?P<job>[\w-]+\.md)`}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	markersOnly, err := NewJobMatcher(nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacy := "Read the file /repo/plans/feat/01-spec.md and execute the agent job."
+	marked := "Please get going.\n" + FormatJobMarker("", "", "/repo/plans/feat/02-impl.md")
+	tests := []struct {
+		name                string
+		m                   *JobMatcher
+		content             string
+		plan, job, planPath string
+	}{
+		{"legacy prompt", defaults, legacy, "feat", "01-spec.md", "/repo/plans/feat"},
+		{"marker", defaults, marked, "feat", "02-impl.md", "/repo/plans/feat"},
+		{"marker names", defaults, FormatJobMarker("p", "03-x.md", ""), "p", "03-x.md", ""},
+		{"marker wins", defaults, legacy + "\n" + marked, "feat", "02-impl.md", "/repo/plans/feat"},
+		{"not a job", defaults, "Read the file /repo/main.go and explain it", "", "", ""},
+		{"outside plans", defaults, "Read the file /repo/docs/a.md and execute the agent job", "", "", ""},
+		{"custom pattern", custom, "Start job feat/04-docs.md now", "feat", "04-docs.md", ""},
+		{"custom drops default", custom, legacy, "", "", ""},
+		{"markers only", markersOnly, legacy, "", "", ""},
+		{"markers only, marked", markersOnly, marked, "feat", "02-impl.md", "/repo/plans/feat"},
+		{"broken marker", defaults, "<!-- grove-job {oops -->", "", "", ""},
+	}
+	for _, tt := range tests {
+		plan, job, planPath := tt.m.Match(tt.content)
+		if plan != tt.plan || job != tt.job || planPath != tt.planPath {
+			t.Errorf("%s: Match = (%q, %q, %q), want (%q, %q, %q)", tt.name, plan, job, planPath, tt.plan, tt.job, tt.planPath)
+		}
+	}
+
+	if _, err := NewJobMatcher([]string{`Run (\S+)`}, false); err == nil {
+		t.Error("pattern without named groups: want an error")
+	}
+	if _, err := NewJobMatcher([]string{`(?P<path>`}, false); err == nil {
+		t.Error("invalid pattern: want an error")
+	}
+}
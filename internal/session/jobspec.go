@@ -0,0 +1,157 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// JobSpec identifies a plan job named on the command line, either as
+// "plan/job.md" or as a path to the job file ("/repo/plans/plan/job.md").
+type JobSpec struct {
+	Plan string
+	Job  string
+	// PlanPath is the absolute plan directory when the spec was a path or
+	// the caller supplied one; empty matches the plan name in any directory.
+	PlanPath string
+}
+
+// ParseJobSpec interprets spec as a job reference. planPath, when non-empty,
+// qualifies a bare "plan/job.md" spec. It reports false for specs that are
+// not job references (session IDs, log file paths).
+func ParseJobSpec(spec, planPath string) (JobSpec, bool) {
+	if !strings.HasSuffix(spec, ".md") || !strings.Contains(spec, "/") || strings.HasPrefix(spec, ArtifactScheme) {
+		return JobSpec{}, false
+	}
+	parts := strings.Split(spec, "/")
+	if len(parts) == 2 && parts[0] != "" && parts[0] != "." && parts[0] != ".." {
+		js := JobSpec{Plan: parts[0], Job: parts[1]}
+		if planPath != "" {
+			if abs, err := filepath.Abs(planPath); err == nil {
+				js.PlanPath = abs
+			}
+		}
+		return js, true
+	}
+	abs, err := filepath.Abs(spec)
+	if err != nil {
+		return JobSpec{}, false
+	}
+	dir := filepath.Dir(abs)
+	return JobSpec{Plan: filepath.Base(dir), Job: filepath.Base(abs), PlanPath: dir}, true
+}
+
+// Matches reports whether a session job is the one the spec names. A job
+// whose plan directory is unknown matches on plan and job name alone.
+func (js JobSpec) Matches(job JobInfo) bool {
+	if job.Plan != js.Plan || job.Job != js.Job {
+		return false
+	}
+	return js.PlanPath == "" || job.PlanPath == "" || filepath.Clean(job.PlanPath) == js.PlanPath
+}
+
+// exactPlanPath reports whether job matches with a known, equal plan
+// directory.
+func (js JobSpec) exactPlanPath(job JobInfo) bool {
+	return js.PlanPath != "" && job.PlanPath != "" && js.Matches(job)
+}
+
+// AmbiguousJobError is returned when a plan/job spec names jobs in more than
+// one plan directory. Candidates holds the most recent session for each.
+type AmbiguousJobError struct {
+	Spec       string
+	Candidates []SessionInfo
+}
+
+func (e *AmbiguousJobError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "'%s' matches jobs in %d plans; pass --plan-path or a full job file path:", e.Spec, len(e.Candidates))
+	for _, c := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s", CandidatePlanPath(c, e.Spec))
+	}
+	return b.String()
+}
+
+// CandidatePlanPath returns the plan directory through which a candidate
+// session matched spec.
+func CandidatePlanPath(info SessionInfo, spec string) string {
+	js, _ := ParseJobSpec(spec, "")
+	for _, job := range info.Jobs {
+		if js.Matches(job) && job.PlanPath != "" {
+			return job.PlanPath
+		}
+	}
+	return ""
+}
+
+// findJobSessions returns the sessions (sorted newest first by the caller)
+// running the job js. When js carries a plan directory, sessions whose job
+// has that exact directory win over ones whose directory is unknown. With
+// rejectAmbiguous set, an unqualified spec matching jobs in several plan
+// directories yields *AmbiguousJobError.
+func findJobSessions(sessions []SessionInfo, spec string, js JobSpec, rejectAmbiguous bool) ([]int, error) {
+	var exact, loose []int
+	for i, s := range sessions {
+		for _, job := range s.Jobs {
+			if js.exactPlanPath(job) {
+				exact = append(exact, i)
+				break
+			}
+			if js.Matches(job) {
+				loose = append(loose, i)
+				break
+			}
+		}
+	}
+	if len(exact) > 0 {
+		return exact, nil
+	}
+
+	if rejectAmbiguous && js.PlanPath == "" {
+		seen := make(map[string]bool)
+		var candidates []SessionInfo
+		for _, i := range loose {
+			dir := CandidatePlanPath(sessions[i], spec)
+			if dir == "" || seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			candidates = append(candidates, sessions[i])
+		}
+		if len(candidates) > 1 {
+			sort.SliceStable(candidates, func(a, b int) bool {
+				return candidates[a].StartedAt.After(candidates[b].StartedAt)
+			})
+			return nil, &AmbiguousJobError{Spec: spec, Candidates: candidates}
+		}
+	}
+	return loose, nil
+}
+
+// FindJobRuns returns every session that ran the job spec names, oldest
+// first, such as a Claude run of a job and a later Codex retry. spec must
+// be a plan/job or job file path; opts narrows it as for
+// ResolveSessionInfoWithOptions.
+func FindJobRuns(spec string, opts ResolveOptions) ([]SessionInfo, error) {
+	js, ok := ParseJobSpec(spec, opts.PlanPath)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a plan/job spec", spec)
+	}
+	sessions, err := NewScanner().Scan()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for sessions: %w", err)
+	}
+	sessions = slices.DeleteFunc(sessions, func(s SessionInfo) bool { return !opts.allows(&s) })
+	idx, err := findJobSessions(sessions, spec, js, opts.RejectAmbiguous)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]SessionInfo, 0, len(idx))
+	for _, i := range idx {
+		runs = append(runs, sessions[i])
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].StartedAt.Before(runs[j].StartedAt) })
+	return runs, nil
+}
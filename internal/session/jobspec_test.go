@@ -0,0 +1,73 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseJobSpec(t *testing.T) {
+	tests := []struct {
+		spec, planPath string
+		want           JobSpec
+		ok             bool
+	}{
+		{"plan/01-setup.md", "", JobSpec{Plan: "plan", Job: "01-setup.md"}, true},
+		{"plan/01-setup.md", "/repo/plans/plan", JobSpec{Plan: "plan", Job: "01-setup.md", PlanPath: "/repo/plans/plan"}, true},
+		{"/repo/plans/plan/01-setup.md", "", JobSpec{Plan: "plan", Job: "01-setup.md", PlanPath: "/repo/plans/plan"}, true},
+		{"session-id", "", JobSpec{}, false},
+		{"/logs/session.jsonl", "", JobSpec{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseJobSpec(tt.spec, tt.planPath)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseJobSpec(%q, %q) = %+v, %v; want %+v, %v", tt.spec, tt.planPath, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFindJobSessions(t *testing.T) {
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	sessions := []SessionInfo{
+		{SessionID: "a", StartedAt: now, Jobs: []JobInfo{{Plan: "plan", Job: "01-setup.md", PlanPath: "/repo-a/plans/plan"}}},
+		{SessionID: "b", StartedAt: now.Add(-time.Hour), Jobs: []JobInfo{{Plan: "plan", Job: "01-setup.md", PlanPath: "/repo-b/plans/plan"}}},
+		{SessionID: "c", StartedAt: now.Add(-2 * time.Hour), Jobs: []JobInfo{{Plan: "plan", Job: "01-setup.md"}}},
+	}
+
+	t.Run("ambiguous", func(t *testing.T) {
+		js, _ := ParseJobSpec("plan/01-setup.md", "")
+		_, err := findJobSessions(sessions, "plan/01-setup.md", js, true)
+		var amb *AmbiguousJobError
+		if !errors.As(err, &amb) {
+			t.Fatalf("err = %v, want *AmbiguousJobError", err)
+		}
+		if len(amb.Candidates) != 2 || amb.Candidates[0].SessionID != "a" || amb.Candidates[1].SessionID != "b" {
+			t.Errorf("candidates = %+v, want sessions a and b", amb.Candidates)
+		}
+	})
+
+	t.Run("ambiguity allowed", func(t *testing.T) {
+		js, _ := ParseJobSpec("plan/01-setup.md", "")
+		got, err := findJobSessions(sessions, "plan/01-setup.md", js, false)
+		if err != nil || len(got) != 3 {
+			t.Errorf("got %v, %v; want all three sessions", got, err)
+		}
+	})
+
+	t.Run("plan path", func(t *testing.T) {
+		js, _ := ParseJobSpec("plan/01-setup.md", "/repo-b/plans/plan")
+		got, err := findJobSessions(sessions, "plan/01-setup.md", js, true)
+		if err != nil || len(got) != 1 || sessions[got[0]].SessionID != "b" {
+			t.Errorf("got %v, %v; want session b", got, err)
+		}
+	})
+
+	t.Run("qualified path falls back to unknown plan dirs", func(t *testing.T) {
+		spec := "/repo-c/plans/plan/01-setup.md"
+		js, _ := ParseJobSpec(spec, "")
+		got, err := findJobSessions(sessions, spec, js, true)
+		if err != nil || len(got) != 1 || sessions[got[0]].SessionID != "c" {
+			t.Errorf("got %v, %v; want session c", got, err)
+		}
+	})
+}
@@ -97,8 +97,9 @@ func resolveOpenCodePointer(spec string) *SessionInfo {
 		var jobs []JobInfo
 		if m.PlanName != "" && m.JobFilePath != "" {
 			jobs = append(jobs, JobInfo{
-				Plan: m.PlanName,
-				Job:  filepath.Base(m.JobFilePath),
+				Plan:     m.PlanName,
+				Job:      filepath.Base(m.JobFilePath),
+				PlanPath: planPathOf(m.JobFilePath),
 			})
 		}
 
@@ -0,0 +1,35 @@
+package session
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelEach calls fn(i) for every i in [0, n) on a pool of GOMAXPROCS
+// workers and returns once all calls have. Callers store results by index,
+// which keeps their order independent of scheduling.
+func ParallelEach(n int, fn func(i int)) {
+	workers := min(runtime.GOMAXPROCS(0), n)
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
@@ -0,0 +1,18 @@
+package session
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelEach(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 1000} {
+		calls := make([]int32, n)
+		ParallelEach(n, func(i int) { atomic.AddInt32(&calls[i], 1) })
+		for i, c := range calls {
+			if c != 1 {
+				t.Fatalf("n=%d: fn(%d) called %d times", n, i, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MinSessionPrefix is the shortest session ID prefix the resolver accepts,
+// so that short words are not mistaken for abbreviated IDs.
+const MinSessionPrefix = 4
+
+// AmbiguousSessionError is returned when a session ID prefix matches more
+// than one session. Candidates are ordered as the sessions were searched
+// (most recent first).
+type AmbiguousSessionError struct {
+	Prefix     string
+	Candidates []SessionInfo
+}
+
+func (e *AmbiguousSessionError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "session ID prefix '%s' is ambiguous; it matches %d sessions:", e.Prefix, len(e.Candidates))
+	for _, c := range e.Candidates {
+		started := "-"
+		if !c.StartedAt.IsZero() {
+			started = c.StartedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(&b, "\n  %s  %-8s %-20s %s", c.SessionID, c.Provider, c.ProjectName, started)
+	}
+	return b.String()
+}
+
+// resolveSessionPrefix finds the one session whose ID starts with prefix. It
+// returns (nil, nil) when the prefix is too short or matches nothing, and
+// *AmbiguousSessionError when it matches several distinct sessions. Among
+// entries for the same ID, one with a transcript wins.
+func resolveSessionPrefix(sessions []SessionInfo, prefix string) (*SessionInfo, error) {
+	if len(prefix) < MinSessionPrefix || strings.ContainsAny(prefix, "/\\") {
+		return nil, nil
+	}
+
+	var order []string
+	best := make(map[string]int)
+	for i, s := range sessions {
+		if !strings.HasPrefix(s.SessionID, prefix) {
+			continue
+		}
+		j, seen := best[s.SessionID]
+		if !seen {
+			order = append(order, s.SessionID)
+			best[s.SessionID] = i
+		} else if sessions[j].LogFilePath == "" && s.LogFilePath != "" {
+			best[s.SessionID] = i
+		}
+	}
+
+	switch len(order) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &sessions[best[order[0]]], nil
+	}
+	candidates := make([]SessionInfo, 0, len(order))
+	for _, id := range order {
+		candidates = append(candidates, sessions[best[id]])
+	}
+	return nil, &AmbiguousSessionError{Prefix: prefix, Candidates: candidates}
+}
+
+// MatchSpec finds the session spec names within sessions, which should be
+// sorted most recent first: an exact session ID, a "plan/job.md" spec, or
+// an unambiguous ID prefix. Unlike ResolveSessionInfo it never looks
+// beyond sessions, so file paths, job file paths and artifact specs don't
+// match.
+func MatchSpec(sessions []SessionInfo, spec string) (*SessionInfo, error) {
+	found := -1
+	for i, s := range sessions {
+		if s.SessionID == spec && (found < 0 || sessions[found].LogFilePath == "" && s.LogFilePath != "") {
+			found = i
+		}
+	}
+	if found >= 0 {
+		return &sessions[found], nil
+	}
+	if js, ok := ParseJobSpec(spec, ""); ok && js.PlanPath == "" {
+		matches, err := findJobSessions(sessions, spec, js, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range matches {
+			if sessions[i].LogFilePath != "" {
+				return &sessions[i], nil
+			}
+		}
+		if len(matches) > 0 {
+			return &sessions[matches[0]], nil
+		}
+	}
+	if info, err := resolveSessionPrefix(sessions, spec); info != nil || err != nil {
+		return info, err
+	}
+	return nil, fmt.Errorf("could not find session matching spec: %s", spec)
+}
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveSessionPrefix(t *testing.T) {
+	sessions := []SessionInfo{
+		{SessionID: "7f3a1c00-aaaa", Provider: "claude"},
+		{SessionID: "7f3a1c00-aaaa", Provider: "claude", LogFilePath: "/logs/7f3a1c00-aaaa.jsonl"},
+		{SessionID: "7f3b2d00-bbbb", Provider: "codex", LogFilePath: "/logs/b.jsonl"},
+		{SessionID: "ses_9z8y7x", Provider: "opencode", LogFilePath: "/storage/ses_9z8y7x.json"},
+	}
+
+	got, err := resolveSessionPrefix(sessions, "7f3a")
+	if err != nil || got == nil || got.LogFilePath != "/logs/7f3a1c00-aaaa.jsonl" {
+		t.Errorf("prefix 7f3a = %+v, %v; want the entry with a transcript", got, err)
+	}
+
+	got, err = resolveSessionPrefix(sessions, "ses_9z")
+	if err != nil || got == nil || got.Provider != "opencode" {
+		t.Errorf("prefix ses_9z = %+v, %v; want the opencode session", got, err)
+	}
+
+	_, err = resolveSessionPrefix(sessions, "7f3")
+	if err != nil {
+		t.Errorf("too-short prefix returned %v, want no match", err)
+	}
+
+	var amb *AmbiguousSessionError
+	if _, err := resolveSessionPrefix(append(sessions, SessionInfo{SessionID: "7f3a9999"}), "7f3a"); !errors.As(err, &amb) || len(amb.Candidates) != 2 {
+		t.Errorf("ambiguous prefix err = %v, want *AmbiguousSessionError with 2 candidates", err)
+	}
+
+	if got, err := resolveSessionPrefix(sessions, "zzzz"); got != nil || err != nil {
+		t.Errorf("unmatched prefix = %+v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestMatchSpec(t *testing.T) {
+	sessions := []SessionInfo{
+		{SessionID: "7f3a1c00-aaaa", LogFilePath: "/logs/a.jsonl", Jobs: []JobInfo{{Plan: "feature", Job: "01-impl.md", PlanPath: "/repo/plans/feature"}}},
+		{SessionID: "7f3b2d00-bbbb", LogFilePath: "/logs/b.jsonl"},
+	}
+	for _, spec := range []string{"7f3b2d00-bbbb", "7f3b", "feature/01-impl.md"} {
+		if got, err := MatchSpec(sessions, spec); err != nil || got == nil {
+			t.Errorf("MatchSpec(%q) = %v, %v; want a session", spec, got, err)
+		}
+	}
+	for _, spec := range []string{"/logs/a.jsonl", "/repo/plans/feature/01-impl.md", "./feature/01-impl.md", "7f3"} {
+		if got, err := MatchSpec(sessions, spec); err == nil {
+			t.Errorf("MatchSpec(%q) = %+v, want no match", spec, got)
+		}
+	}
+}
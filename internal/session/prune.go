@@ -0,0 +1,212 @@
+package session
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// PruneProviders are the providers whose transcripts prune can remove: the
+// ones that keep each session in a single JSONL file.
+var PruneProviders = []string{"claude", "codex"}
+
+// PruneOptions selects transcripts to prune.
+type PruneOptions struct {
+	// Before is the cutoff: transcripts last written before it are pruned.
+	Before time.Time
+	// Providers limits pruning to these providers (default PruneProviders).
+	Providers []string
+	// Compress selects transcripts to compress rather than delete, which
+	// leaves out those already compressed.
+	Compress bool
+}
+
+// PruneCandidate is a transcript file older than the cutoff.
+type PruneCandidate struct {
+	Path      string    `json:"path"`
+	Provider  string    `json:"provider"`
+	SessionID string    `json:"sessionId,omitempty"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"modTime"`
+	// Keep is set, with the reason in KeepReason, when the transcript must
+	// not be pruned.
+	Keep       bool   `json:"keep,omitempty"`
+	KeepReason string `json:"keepReason,omitempty"`
+}
+
+// FindPruneCandidates lists the transcript files of opts.Providers, under
+// their sources (see Sources), last written before opts.Before, oldest
+// first. sessions is a full scan, used to
+// keep transcripts of pinned sessions and of plan jobs that have not been
+// archived: deleting them would lose the job's history. Archived copies in
+// plan artifact directories are never candidates.
+func FindPruneCandidates(homeDir string, sessions []SessionInfo, opts PruneOptions) ([]PruneCandidate, error) {
+	providers := opts.Providers
+	if len(providers) == 0 {
+		providers = PruneProviders
+	}
+	sources := Sources(homeDir)
+	var paths []string
+	for _, p := range providers {
+		if !slices.Contains(PruneProviders, p) {
+			return nil, fmt.Errorf("cannot prune %s transcripts (supported: %s)", p, strings.Join(PruneProviders, ", "))
+		}
+		for _, src := range sources {
+			if src.Provider != p {
+				continue
+			}
+			matches, err := transcript.GlobTranscripts(src.TranscriptGlob())
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, matches...)
+		}
+	}
+
+	// Sessions whose live transcript is the only record of a plan job.
+	unarchived := make(map[string]bool)
+	pinned := make(map[string]bool)
+	for _, s := range sessions {
+		if s.Pinned {
+			pinned[s.SessionID] = true
+		}
+		if len(s.Jobs) == 0 || isArchivePath(s.LogFilePath) {
+			continue
+		}
+		for _, job := range s.Jobs {
+			if !IsJobArchived(job) {
+				unarchived[s.SessionID] = true
+				break
+			}
+		}
+	}
+
+	var candidates []PruneCandidate
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil || !fi.Mode().IsRegular() || !fi.ModTime().Before(opts.Before) {
+			continue
+		}
+		if opts.Compress && transcript.IsCompressedPath(path) {
+			continue
+		}
+		c := PruneCandidate{Path: path, Provider: ProviderForPath(path), Size: fi.Size(), ModTime: fi.ModTime()}
+		c.SessionID = transcriptSessionID(path, c.Provider)
+		switch {
+		case pinned[c.SessionID]:
+			c.Keep, c.KeepReason = true, "pinned"
+		case unarchived[c.SessionID]:
+			c.Keep, c.KeepReason = true, "plan job not archived"
+		}
+		candidates = append(candidates, c)
+	}
+	slices.SortFunc(candidates, func(a, b PruneCandidate) int { return a.ModTime.Compare(b.ModTime) })
+	return candidates, nil
+}
+
+// IsJobArchived reports whether job has an archive in its plan's artifact
+// directory.
+func IsJobArchived(job JobInfo) bool {
+	if job.PlanPath == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(job.PlanPath, ".artifacts", jobArtifactID(job.PlanPath, job.Job), "metadata.json"))
+	return err == nil
+}
+
+// transcriptSessionID reads the session a transcript file belongs to without
+// parsing it: the file name for Claude sessions and Codex rollouts, and the
+// sessionId of the first lines for Claude sub-agent (agent-*) files.
+func transcriptSessionID(path, provider string) string {
+	name := transcript.TrimTranscriptExt(filepath.Base(path))
+	if provider == "codex" {
+		// rollout-<timestamp>-<uuid>
+		if len(name) >= 36 {
+			return name[len(name)-36:]
+		}
+		return name
+	}
+	if !strings.HasPrefix(name, "agent-") {
+		return name
+	}
+	f, err := transcript.OpenTranscript(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := transcript.NewLineScanner(f)
+	for n := 0; n < 10 && sc.Scan(); n++ {
+		var line struct {
+			SessionID string `json:"sessionId"`
+		}
+		if json.Unmarshal(sc.Bytes(), &line) == nil && line.SessionID != "" {
+			return line.SessionID
+		}
+	}
+	return ""
+}
+
+// PruneTranscript removes a candidate's file, or with compress replaces it
+// by a gzip copy (<path>.gz, with the original modification time). It
+// returns the disk space reclaimed.
+func PruneTranscript(c PruneCandidate, compress bool) (int64, error) {
+	if c.Keep {
+		return 0, fmt.Errorf("%s must be kept: %s", c.Path, c.KeepReason)
+	}
+	if !compress {
+		if err := os.Remove(c.Path); err != nil {
+			return 0, err
+		}
+		return c.Size, nil
+	}
+	if transcript.IsCompressedPath(c.Path) {
+		return 0, fmt.Errorf("%s is already compressed", c.Path)
+	}
+
+	gzPath := c.Path + ".gz"
+	size, err := writeFileAtomic(gzPath, func(w io.Writer) (int, error) {
+		in, err := os.Open(c.Path)
+		if err != nil {
+			return 0, err
+		}
+		defer in.Close()
+		counter := &countingWriter{w: w}
+		zw := gzip.NewWriter(counter)
+		zw.Name = filepath.Base(c.Path)
+		zw.ModTime = c.ModTime
+		if _, err := io.Copy(zw, in); err != nil {
+			return 0, err
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+		return counter.n, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to compress %s: %w", c.Path, err)
+	}
+	_ = os.Chtimes(gzPath, c.ModTime, c.ModTime)
+	if err := os.Remove(c.Path); err != nil {
+		return 0, err
+	}
+	return c.Size - int64(size), nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,104 @@
+package session
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrune(t *testing.T) {
+	home := t.TempDir()
+	old := time.Now().AddDate(0, 0, -90)
+	write := func(rel, content string, mtime time.Time) string {
+		path := filepath.Join(home, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	plain := write(".claude/projects/-work-app/c-plain.jsonl", strings.Repeat(`{"type":"user"}`+"\n", 100), old)
+	write(".claude/projects/-work-app/c-recent.jsonl", "{}\n", time.Now())
+	sub := write(".claude/projects/-work-app/agent-1.jsonl", `{"sessionId":"c-job"}`+"\n", old)
+	job := write(".claude/projects/-work-app/c-job.jsonl", "{}\n", old)
+	codex := write(".codex/sessions/2025/01/02/rollout-2025-01-02T10-00-00-0199a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b.jsonl", "{}\n", old.Add(time.Hour))
+	archivedJob := write(".claude/projects/-work-app/c-archived.jsonl", "{}\n", old)
+	pinned := write(".claude/projects/-work-app/c-pinned.jsonl", "{}\n", old)
+
+	plan := filepath.Join(home, "plans", "p")
+	write("plans/p/01-a.md", "---\nid: a1\n---\n", old)
+	write("plans/p/.artifacts/a1/metadata.json", "{}", old)
+	sessions := []SessionInfo{
+		{SessionID: "c-job", LogFilePath: job, Jobs: []JobInfo{{Plan: "p", Job: "02-b.md", PlanPath: plan}}},
+		{SessionID: "c-archived", LogFilePath: archivedJob, Jobs: []JobInfo{{Plan: "p", Job: "01-a.md", PlanPath: plan}}},
+		{SessionID: "c-pinned", LogFilePath: pinned, Pinned: true},
+	}
+
+	candidates, err := FindPruneCandidates(home, sessions, PruneOptions{Before: time.Now().AddDate(0, 0, -60)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]PruneCandidate{}
+	for _, c := range candidates {
+		got[c.Path] = c
+	}
+	if len(got) != 6 {
+		t.Fatalf("got %d candidates, want 6: %+v", len(got), candidates)
+	}
+	for path, keep := range map[string]bool{plain: false, sub: true, job: true, codex: false, archivedJob: false, pinned: true} {
+		if c, ok := got[path]; !ok || c.Keep != keep {
+			t.Errorf("%s: candidate %+v, want keep=%v", filepath.Base(path), c, keep)
+		}
+	}
+	if reason := got[pinned].KeepReason; reason != "pinned" {
+		t.Errorf("pinned transcript kept because %q", reason)
+	}
+	if id := got[codex].SessionID; id != "0199a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b" {
+		t.Errorf("codex session ID = %q", id)
+	}
+	if _, err := FindPruneCandidates(home, nil, PruneOptions{Before: time.Now(), Providers: []string{"opencode"}}); err == nil {
+		t.Error("pruning opencode transcripts was accepted")
+	}
+
+	if _, err := PruneTranscript(got[job], false); err == nil {
+		t.Error("pruned a transcript that must be kept")
+	}
+	reclaimed, err := PruneTranscript(got[plain], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reclaimed <= 0 {
+		t.Errorf("compressing reclaimed %d bytes", reclaimed)
+	}
+	f, err := os.Open(plain + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := io.ReadAll(zr); len(data) != int(got[plain].Size) {
+		t.Errorf("decompressed %d bytes, want %d", len(data), got[plain].Size)
+	}
+	if _, err := os.Stat(plain); !os.IsNotExist(err) {
+		t.Errorf("original still exists: %v", err)
+	}
+
+	if n, err := PruneTranscript(got[codex], false); err != nil || n != got[codex].Size {
+		t.Errorf("delete = %d, %v", n, err)
+	}
+	if _, err := os.Stat(codex); !os.IsNotExist(err) {
+		t.Errorf("deleted transcript still exists: %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/core/pkg/paths"
+
+	"github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/archivestore"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// A remote archive holds job archives laid out like a plan's .artifacts
+// directory, one level deeper: <session-id>/<job-id>/ with metadata.json
+// and transcript.jsonl (or transcript.jsonl.gz). Listing one downloads
+// each job's metadata to a local cache; a transcript is only downloaded
+// when its session is read.
+
+// RemoteStores opens the remote archives the config's archive section
+// lists.
+func RemoteStores() ([]archivestore.Store, error) {
+	var stores []archivestore.Store
+	for _, u := range config.Load().Archive.Remotes {
+		s, err := archivestore.Open(u)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, s)
+	}
+	return stores, nil
+}
+
+// UploadJob archives info.Jobs[i] like ArchiveJob, but to store under
+// <session-id>/<job-id>/ instead of the plan's artifact directory. Sessions
+// already archived locally upload too, so a plan's archives can be moved
+// to long-term storage. The result's Dir and Transcript are object URLs.
+func UploadJob(ctx context.Context, info SessionInfo, i int, store archivestore.Store, opts ArchiveOptions) (ArchiveResult, error) {
+	job := info.Jobs[i]
+	res := ArchiveResult{Job: job}
+	if info.Provider == "opencode" {
+		return res, fmt.Errorf("OpenCode sessions are stored as directories, not a transcript file; use 'aglogs export' instead")
+	}
+	if job.PlanPath == "" {
+		return res, fmt.Errorf("plan directory of %s/%s is unknown", job.Plan, job.Job)
+	}
+	if isRemoteCachePath(info.LogFilePath) {
+		return res, fmt.Errorf("%s was fetched from a remote archive", info.LogFilePath)
+	}
+
+	key := path.Join(info.SessionID, jobArtifactID(job.PlanPath, job.Job))
+	existing, err := store.List(ctx, key+"/")
+	if err != nil {
+		return res, fmt.Errorf("failed to list %s: %w", store.URL(), err)
+	}
+	if slices.Contains(existing, key+"/metadata.json") && !opts.Force {
+		return res, fmt.Errorf("%s/%s: %w in %s/%s", job.Plan, job.Job, ErrAlreadyArchived, store.URL(), key)
+	}
+
+	staging, err := os.MkdirTemp("", "aglogs-archive-")
+	if err != nil {
+		return res, err
+	}
+	defer os.RemoveAll(staging)
+	res.Dir = staging
+	if res, err = writeJobArchive(res, info, i, opts); err != nil {
+		return res, err
+	}
+
+	// The metadata goes up last: a job directory without it is an
+	// unfinished upload, which listing skips.
+	name := filepath.Base(res.Transcript)
+	if err := store.Put(ctx, key+"/"+name, res.Transcript); err != nil {
+		return res, fmt.Errorf("failed to upload transcript: %w", err)
+	}
+	if err := store.Put(ctx, key+"/metadata.json", filepath.Join(staging, "metadata.json")); err != nil {
+		return res, fmt.Errorf("failed to upload metadata: %w", err)
+	}
+	// Like a forced local re-archive, drop the copy of the other
+	// compression.
+	for _, k := range existing {
+		if strings.HasPrefix(path.Base(k), "transcript.") && path.Base(k) != name {
+			if err := store.Delete(ctx, k); err != nil {
+				return res, err
+			}
+		}
+	}
+	res.Dir = store.URL() + "/" + key
+	res.Transcript = res.Dir + "/" + name
+	return res, nil
+}
+
+// RemoteSessions lists the jobs archived in store, one session per job as
+// for local archives. Their LogFilePath is where FetchRemote puts the
+// transcript, and Remote the job's archive URL.
+func RemoteSessions(ctx context.Context, store archivestore.Store) ([]SessionInfo, error) {
+	keys, err := store.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", store.URL(), err)
+	}
+	complete := make(map[string]bool)
+	transcripts := make(map[string]string)
+	for _, k := range keys {
+		dir, name := path.Split(k)
+		dir = strings.TrimSuffix(dir, "/")
+		if strings.Count(dir, "/") != 1 {
+			continue
+		}
+		switch {
+		case name == "metadata.json":
+			complete[dir] = true
+		case name == "transcript.jsonl" || (name == "transcript.jsonl"+transcript.CompressedExt && transcripts[dir] == ""):
+			transcripts[dir] = name
+		}
+	}
+	var dirs []string
+	for dir := range complete {
+		if transcripts[dir] != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+
+	cache := remoteCacheDir(store)
+	scanner := NewScannerWithoutDaemon()
+	var sessions []SessionInfo
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		local := filepath.Join(cache, filepath.FromSlash(dir))
+		metadataPath := filepath.Join(local, "metadata.json")
+		if _, err := os.Stat(metadataPath); err != nil {
+			if err := store.Get(ctx, dir+"/metadata.json", metadataPath); err != nil {
+				return nil, fmt.Errorf("failed to fetch %s/%s/metadata.json: %w", store.URL(), dir, err)
+			}
+		}
+		info, err := scanner.readArchivedSession(local)
+		if err != nil {
+			continue
+		}
+		info.LogFilePath = filepath.Join(local, transcripts[dir])
+		info.Remote = store.URL() + "/" + dir
+		sessions = append(sessions, info)
+	}
+	return sessions, nil
+}
+
+// FetchRemote downloads the transcript of a session listed by
+// RemoteSessions to its LogFilePath, unless an earlier fetch put it there.
+func FetchRemote(ctx context.Context, store archivestore.Store, info SessionInfo) error {
+	if _, err := os.Stat(info.LogFilePath); err == nil {
+		return nil
+	}
+	rel, err := filepath.Rel(remoteCacheDir(store), info.LogFilePath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("%s is not from %s", info.LogFilePath, store.URL())
+	}
+	if err := store.Get(ctx, filepath.ToSlash(rel), info.LogFilePath); err != nil {
+		return fmt.Errorf("failed to fetch %s/%s: %w", store.URL(), filepath.ToSlash(rel), err)
+	}
+	return nil
+}
+
+// resolveRemote finds a session by ID or ID prefix in the configured
+// remote archives and fetches its transcript. It returns nil when no
+// archive has it.
+func resolveRemote(ctx context.Context, spec string, opts ResolveOptions) (*SessionInfo, error) {
+	stores, err := RemoteStores()
+	if err != nil {
+		return nil, err
+	}
+	for _, store := range stores {
+		sessions, err := RemoteSessions(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		sessions = slices.DeleteFunc(sessions, func(s SessionInfo) bool { return !opts.allows(&s) })
+		var info *SessionInfo
+		for i := range sessions {
+			if sessions[i].SessionID == spec {
+				info = &sessions[i]
+				break
+			}
+		}
+		if info == nil {
+			if info, err = resolveSessionPrefix(sessions, spec); err != nil {
+				return nil, err
+			}
+		}
+		if info != nil {
+			return info, FetchRemote(ctx, store, *info)
+		}
+	}
+	return nil, nil
+}
+
+// remoteCacheRoot holds the copies fetched from every remote archive.
+func remoteCacheRoot() string {
+	return filepath.Join(paths.StateDir(), "aglogs", "archives")
+}
+
+// remoteCacheDir is where copies from store are kept, named by a hash of
+// its URL.
+func remoteCacheDir(store archivestore.Store) string {
+	sum := sha256.Sum256([]byte(store.URL()))
+	return filepath.Join(remoteCacheRoot(), hex.EncodeToString(sum[:8]))
+}
+
+// isRemoteCachePath reports whether path is a copy fetched from a remote
+// archive.
+func isRemoteCachePath(path string) bool {
+	return path != "" && strings.HasPrefix(path, remoteCacheRoot()+string(filepath.Separator))
+}
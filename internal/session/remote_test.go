@@ -0,0 +1,82 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/agentlogs/internal/archivestore"
+)
+
+func TestUploadJob(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	plan := filepath.Join(dir, "plans", "feature")
+	if err := os.MkdirAll(plan, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "c1.jsonl")
+	lines := []string{`{"type":"user","cwd":"/work/app","sessionId":"c1"}`, `{"type":"assistant","n":1}`, `{"type":"user","n":2}`}
+	if err := os.WriteFile(logPath, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info := SessionInfo{
+		SessionID: "c1", Provider: "claude", LogFilePath: logPath,
+		Jobs: []JobInfo{
+			{Plan: "feature", Job: "01-spec.md", LineIndex: 0, PlanPath: plan},
+			{Plan: "feature", Job: "02-impl.md", LineIndex: 2, PlanPath: plan},
+		},
+	}
+	store, err := archivestore.Open("file://" + filepath.ToSlash(filepath.Join(dir, "bucket")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := UploadJob(ctx, info, 0, store, ArchiveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := store.URL() + "/c1/01-spec"; res.Dir != want || res.Lines != 2 {
+		t.Errorf("UploadJob = %+v, want dir %s with 2 lines", res, want)
+	}
+	if _, err := UploadJob(ctx, info, 0, store, ArchiveOptions{}); !errors.Is(err, ErrAlreadyArchived) {
+		t.Errorf("second upload: err = %v, want ErrAlreadyArchived", err)
+	}
+	if _, err := UploadJob(ctx, info, 1, store, ArchiveOptions{Compress: true}); err != nil {
+		t.Fatal(err)
+	}
+	// A forced upload switching compression replaces the transcript.
+	if _, err := UploadJob(ctx, info, 0, store, ArchiveOptions{Force: true, Compress: true}); err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := store.List(ctx, "")
+	want := "c1/01-spec/metadata.json c1/01-spec/transcript.jsonl.gz c1/02-impl/metadata.json c1/02-impl/transcript.jsonl.gz"
+	if got := strings.Join(keys, " "); got != want {
+		t.Errorf("store holds %s, want %s", got, want)
+	}
+
+	remote, err := RemoteSessions(ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remote) != 2 || remote[0].SessionID != "c1" || remote[1].Jobs[0].Job != "02-impl.md" {
+		t.Fatalf("RemoteSessions = %+v", remote)
+	}
+	got := remote[1]
+	if got.Remote != store.URL()+"/c1/02-impl" || !got.Archived() {
+		t.Errorf("remote session = %+v", got)
+	}
+	if _, err := os.Stat(got.LogFilePath); err == nil {
+		t.Error("transcript fetched before it was read")
+	}
+	if err := FetchRemote(ctx, store, got); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(got.LogFilePath); err != nil {
+		t.Errorf("transcript not fetched: %v", err)
+	}
+}
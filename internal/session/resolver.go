@@ -3,32 +3,79 @@ package session
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
 	"sort"
-	"strings"
 
 	"github.com/grovetools/core/pkg/daemon"
 	"github.com/grovetools/core/pkg/models"
 )
 
+// ResolveOptions refines how ResolveSessionInfoWithOptions matches a spec.
+type ResolveOptions struct {
+	// PlanPath restricts a "plan/job.md" spec to jobs of the plan in this
+	// directory.
+	PlanPath string
+	// RejectAmbiguous makes a plan/job spec that matches jobs in more than
+	// one plan directory fail with *AmbiguousJobError, instead of resolving
+	// to the most recent session.
+	RejectAmbiguous bool
+	// Providers, when non-empty, limits matches to sessions from these
+	// providers ("claude", "codex", "pi", "opencode").
+	Providers []string
+}
+
+// allows reports whether info passes the Providers restriction. Sessions
+// without a recorded provider are Claude's, as everywhere else.
+func (o ResolveOptions) allows(info *SessionInfo) bool {
+	if len(o.Providers) == 0 {
+		return true
+	}
+	p := info.Provider
+	if p == "" {
+		p = "claude"
+	}
+	return slices.Contains(o.Providers, p)
+}
+
 // ResolveSessionInfo finds a session's metadata based on a specifier which can be a
 // plan/job string, a session ID, or a direct file path to a job file or log file.
 // It prioritizes the fastest lookup methods first.
 func ResolveSessionInfo(spec string) (*SessionInfo, error) {
-	// Try daemon lookup first (fastest path)
+	return ResolveSessionInfoWithOptions(spec, ResolveOptions{})
+}
+
+// ResolveSessionInfoWithOptions is ResolveSessionInfo with plan
+// disambiguation; see ResolveOptions.
+func ResolveSessionInfoWithOptions(spec string, opts ResolveOptions) (*SessionInfo, error) {
+	// Archived jobs named directly resolve from their artifact directory,
+	// without the daemon or a scan.
+	if IsArtifactSpec(spec) {
+		info, err := ResolveArtifact(spec)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.allows(info) {
+			return nil, fmt.Errorf("archived session %s is a %s session", info.SessionID, info.Provider)
+		}
+		return info, nil
+	}
+
+	// Try daemon lookup first (fastest path). The daemon resolves plan/job
+	// names without knowing plan directories, so it is skipped when the
+	// caller asked for a specific plan.
 	daemonClient := daemon.NewWithAutoStart()
 	defer daemonClient.Close()
 
-	if daemonClient.IsRunning() {
+	if daemonClient.IsRunning() && opts.PlanPath == "" {
 		// Try daemon job registry first — this is the primary source in the new architecture
 		if job, err := daemonClient.GetJob(context.Background(), spec); err == nil && job != nil {
 			if job.Type == "interactive_agent" || job.Type == "headless_agent" || job.Type == "isolated_agent" {
 				// For agent jobs, the true transcript is the provider's JSONL file.
 				// The daemon only has orchestrator launch output, not the actual transcript.
 				// Fall through to full scan so it matches via the session registry with LogFilePath.
-			} else {
-				return jobInfoToSessionInfo(job), nil
+			} else if info := jobInfoToSessionInfo(job); opts.allows(info) {
+				return info, nil
 			}
 		} else {
 			// Fall back to daemon session lookup (for sessions not managed as jobs)
@@ -37,8 +84,9 @@ func ResolveSessionInfo(spec string) (*SessionInfo, error) {
 				var jobs []JobInfo
 				if session.PlanName != "" && session.JobFilePath != "" {
 					jobs = append(jobs, JobInfo{
-						Plan: session.PlanName,
-						Job:  filepath.Base(session.JobFilePath),
+						Plan:     session.PlanName,
+						Job:      filepath.Base(session.JobFilePath),
+						PlanPath: planPathOf(session.JobFilePath),
 					})
 				}
 				info := &SessionInfo{
@@ -50,6 +98,7 @@ func ResolveSessionInfo(spec string) (*SessionInfo, error) {
 					Provider:    session.Provider,
 					Status:      session.Status,
 					PID:         session.PID,
+					User:        session.User,
 				}
 				// Daemon records don't carry LogFilePath. For opencode,
 				// follow the transcript pointer recorded in the session
@@ -64,7 +113,9 @@ func ResolveSessionInfo(spec string) (*SessionInfo, error) {
 				// Enrich from scanner so file-based providers can actually
 				// open the transcript.
 				enrichLogFilePath(info)
-				return info, nil
+				if opts.allows(info) {
+					return info, nil
+				}
 			}
 		}
 	}
@@ -74,7 +125,7 @@ func ResolveSessionInfo(spec string) (*SessionInfo, error) {
 	// the hooks session registry, so opencode specs (flow job id, native
 	// ses_* id, or plan/job) resolve without walking every provider's
 	// storage.
-	if info := resolveOpenCodePointer(spec); info != nil {
+	if info := resolveOpenCodePointer(spec); info != nil && opts.allows(info) {
 		return info, nil
 	}
 
@@ -84,7 +135,13 @@ func ResolveSessionInfo(spec string) (*SessionInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan for sessions: %w", err)
 	}
+	if len(opts.Providers) > 0 {
+		allSessions = slices.DeleteFunc(allSessions, func(s SessionInfo) bool { return !opts.allows(&s) })
+	}
 	if len(allSessions) == 0 {
+		if info, err := resolveRemote(context.Background(), spec, opts); info != nil || err != nil {
+			return info, err
+		}
 		return nil, fmt.Errorf("no sessions found")
 	}
 
@@ -104,68 +161,49 @@ func ResolveSessionInfo(spec string) (*SessionInfo, error) {
 		}
 	}
 
-	// Strategy 2: Check for session ID or plan/job spec.
+	// Strategy 2: Check for a session ID.
+	for i, s := range allSessions {
+		if s.SessionID == spec && s.LogFilePath != "" {
+			return &allSessions[i], nil
+		}
+	}
+
+	// Strategy 3: Check for a plan/job spec or a job file path.
 	// When multiple sessions match (e.g. a filesystem-backed entry and a
 	// daemon-only entry for the same job), prefer the one with LogFilePath
 	// set; otherwise fall back to the first match so callers still get a hit.
-	parts := strings.Split(spec, "/")
-	isPlanJobSpec := len(parts) == 2 && strings.HasSuffix(parts[1], ".md")
-
-	fallbackIdx := -1
-	for i, s := range allSessions {
-		matched := false
-		if s.SessionID == spec {
-			matched = true
-		} else if isPlanJobSpec {
-			planName := parts[0]
-			jobName := parts[1]
-			for _, job := range s.Jobs {
-				if job.Plan == planName && job.Job == jobName {
-					matched = true
-					break
-				}
+	if js, ok := ParseJobSpec(spec, opts.PlanPath); ok {
+		matches, err := findJobSessions(allSessions, spec, js, opts.RejectAmbiguous)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range matches {
+			if allSessions[i].LogFilePath != "" {
+				return &allSessions[i], nil
 			}
 		}
-		if !matched {
-			continue
+		if len(matches) > 0 {
+			return &allSessions[matches[0]], nil
 		}
-		if s.LogFilePath != "" {
+	}
+
+	// A session ID known only to the daemon has no transcript yet.
+	for i, s := range allSessions {
+		if s.SessionID == spec {
 			return &allSessions[i], nil
 		}
-		if fallbackIdx == -1 {
-			fallbackIdx = i
-		}
 	}
-	if fallbackIdx != -1 {
-		return &allSessions[fallbackIdx], nil
+
+	// Strategy 4: Treat the spec as an abbreviated session ID, like a git
+	// SHA prefix.
+	if info, err := resolveSessionPrefix(allSessions, spec); info != nil || err != nil {
+		return info, err
 	}
 
-	// Strategy 3: Check if spec is a job file path (which might not be part of a plan/job spec)
-	if _, err := os.Stat(spec); err == nil {
-		jobFilename := filepath.Base(spec)
-		planName := filepath.Base(filepath.Dir(spec))
-		fsFallbackIdx := -1
-		for i, s := range allSessions {
-			matched := false
-			for _, job := range s.Jobs {
-				if job.Plan == planName && job.Job == jobFilename {
-					matched = true
-					break
-				}
-			}
-			if !matched {
-				continue
-			}
-			if s.LogFilePath != "" {
-				return &allSessions[i], nil
-			}
-			if fsFallbackIdx == -1 {
-				fsFallbackIdx = i
-			}
-		}
-		if fsFallbackIdx != -1 {
-			return &allSessions[fsFallbackIdx], nil
-		}
+	// Strategy 5: Look in the remote archives, fetching the transcript of
+	// a session found there.
+	if info, err := resolveRemote(context.Background(), spec, opts); info != nil || err != nil {
+		return info, err
 	}
 
 	return nil, fmt.Errorf("could not find session matching spec: %s", spec)
@@ -188,13 +226,13 @@ func enrichLogFilePath(info *SessionInfo) {
 			continue
 		}
 		if s.SessionID == info.SessionID {
-			info.LogFilePath = s.LogFilePath
+			adoptTranscript(info, s)
 			return
 		}
 		for _, job := range s.Jobs {
 			for _, target := range info.Jobs {
 				if job.Plan == target.Plan && job.Job == target.Job {
-					info.LogFilePath = s.LogFilePath
+					adoptTranscript(info, s)
 					return
 				}
 			}
@@ -202,13 +240,23 @@ func enrichLogFilePath(info *SessionInfo) {
 	}
 }
 
+// adoptTranscript copies the transcript location from a scanned session, and
+// its provider when the daemon record did not carry one.
+func adoptTranscript(info *SessionInfo, scanned SessionInfo) {
+	info.LogFilePath = scanned.LogFilePath
+	if info.Provider == "" {
+		info.Provider = scanned.Provider
+	}
+}
+
 // jobInfoToSessionInfo converts a daemon JobInfo into a SessionInfo.
 func jobInfoToSessionInfo(job *models.JobInfo) *SessionInfo {
 	var jobs []JobInfo
 	if job.JobFile != "" {
 		jobs = append(jobs, JobInfo{
-			Plan: filepath.Base(job.PlanDir),
-			Job:  job.JobFile,
+			Plan:     filepath.Base(job.PlanDir),
+			Job:      job.JobFile,
+			PlanPath: planPathOf(filepath.Join(job.PlanDir, job.JobFile)),
 		})
 	}
 
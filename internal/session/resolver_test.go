@@ -0,0 +1,23 @@
+package session
+
+import "testing"
+
+func TestResolveOptionsAllows(t *testing.T) {
+	tests := []struct {
+		providers []string
+		provider  string
+		want      bool
+	}{
+		{nil, "codex", true},
+		{[]string{"codex"}, "codex", true},
+		{[]string{"codex"}, "opencode", false},
+		{[]string{"claude"}, "", true},
+		{[]string{"codex", "pi"}, "", false},
+	}
+	for _, tt := range tests {
+		opts := ResolveOptions{Providers: tt.providers}
+		if got := opts.allows(&SessionInfo{Provider: tt.provider}); got != tt.want {
+			t.Errorf("Providers %v allows(%q) = %v, want %v", tt.providers, tt.provider, got, tt.want)
+		}
+	}
+}
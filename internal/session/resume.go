@@ -0,0 +1,146 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// ResumeInfo is what a script needs to hand a session back to its agent:
+// the provider-native command line and the directory to run it in.
+type ResumeInfo struct {
+	Provider  string   `json:"provider"`
+	SessionID string   `json:"sessionId"`
+	WorkDir   string   `json:"workDir,omitempty"` // empty when the transcript does not record it
+	Argv      []string `json:"argv"`
+}
+
+// NewResumeInfo builds the resume command for a session:
+//
+//	claude --resume <id>
+//	codex resume <id>
+//	pi --session <transcript>
+//	opencode --session <id>
+func NewResumeInfo(info *SessionInfo) (*ResumeInfo, error) {
+	provider := info.Provider
+	if provider == "" {
+		provider = "claude"
+	}
+	r := &ResumeInfo{Provider: provider, SessionID: info.SessionID}
+	if info.LogFilePath != "" {
+		r.WorkDir = ReadWorkingDirectory(info.LogFilePath, provider)
+	}
+	if r.WorkDir == "" && info.Environment != nil {
+		r.WorkDir = info.Environment.Cwd
+	}
+
+	hasID := info.SessionID != "" && info.SessionID != "unknown"
+	switch provider {
+	case "claude":
+		if hasID {
+			r.Argv = []string{"claude", "--resume", info.SessionID}
+		}
+	case "codex":
+		if hasID {
+			r.Argv = []string{"codex", "resume", info.SessionID}
+		}
+	case "opencode":
+		if hasID {
+			r.Argv = []string{"opencode", "--session", info.SessionID}
+		}
+	case "pi":
+		// pi resumes from a session file rather than an ID.
+		if info.LogFilePath != "" {
+			r.Argv = []string{"pi", "--session", info.LogFilePath}
+		}
+	default:
+		return nil, fmt.Errorf("don't know how to resume %s sessions", provider)
+	}
+	if r.Argv == nil {
+		return nil, fmt.Errorf("session has no %s session ID to resume", provider)
+	}
+	return r, nil
+}
+
+// ShellCommand renders the resume command as one POSIX shell line, changing
+// to WorkDir first when it is known.
+func (r *ResumeInfo) ShellCommand() string {
+	quoted := make([]string, len(r.Argv))
+	for i, arg := range r.Argv {
+		quoted[i] = shellQuote(arg)
+	}
+	line := strings.Join(quoted, " ")
+	if r.WorkDir != "" {
+		line = "cd " + shellQuote(r.WorkDir) + " && " + line
+	}
+	return line
+}
+
+// shellQuote single-quotes s unless it consists only of characters that
+// are safe unquoted.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_./:@%+=,", c)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ReadWorkingDirectory returns the directory a session ran in, as recorded
+// by its transcript: the cwd on Claude lines and in the pi session header,
+// the session_meta payload for Codex, and the session info file's directory
+// for OpenCode. It yields "" when none is found.
+func ReadWorkingDirectory(logPath, provider string) string {
+	if provider == "opencode" {
+		data, err := os.ReadFile(logPath)
+		if err != nil {
+			return ""
+		}
+		var info struct {
+			Directory string `json:"directory"`
+		}
+		if json.Unmarshal(data, &info) != nil {
+			return ""
+		}
+		return info.Directory
+	}
+
+	file, err := transcript.OpenTranscript(logPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := transcript.NewLineScanner(file)
+	for lines := 0; scanner.Scan() && lines <= 100; lines++ {
+		var entry struct {
+			Cwd     string `json:"cwd"`
+			Type    string `json:"type"`
+			Payload struct {
+				Cwd string `json:"cwd"`
+			} `json:"payload"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		if entry.Cwd != "" {
+			return entry.Cwd
+		}
+		if entry.Type == "session_meta" && entry.Payload.Cwd != "" {
+			return entry.Payload.Cwd
+		}
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewResumeInfo(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	claude := write("claude.jsonl", `{"type":"summary"}`+"\n"+`{"type":"user","cwd":"/work/app","sessionId":"c1"}`+"\n")
+	codex := write("codex.jsonl", `{"type":"session_meta","payload":{"id":"x1","cwd":"/work/it's"}}`+"\n")
+	pi := write("pi.jsonl", `{"type":"session","id":"p1","cwd":"/work/pi"}`+"\n")
+	opencode := write("ses_1.json", `{"id":"ses_1","directory":"/work/oc"}`)
+
+	tests := []struct {
+		info  SessionInfo
+		argv  []string
+		dir   string
+		shell string
+	}{
+		{SessionInfo{SessionID: "c1", LogFilePath: claude}, []string{"claude", "--resume", "c1"}, "/work/app", "cd /work/app && claude --resume c1"},
+		{SessionInfo{SessionID: "x1", Provider: "codex", LogFilePath: codex}, []string{"codex", "resume", "x1"}, "/work/it's", `cd '/work/it'\''s' && codex resume x1`},
+		{SessionInfo{SessionID: "p1", Provider: "pi", LogFilePath: pi}, []string{"pi", "--session", pi}, "/work/pi", "cd /work/pi && pi --session " + pi},
+		{SessionInfo{SessionID: "ses_1", Provider: "opencode", LogFilePath: opencode}, []string{"opencode", "--session", "ses_1"}, "/work/oc", "cd /work/oc && opencode --session ses_1"},
+	}
+	for _, tt := range tests {
+		r, err := NewResumeInfo(&tt.info)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.info.SessionID, err)
+		}
+		if !reflect.DeepEqual(r.Argv, tt.argv) || r.WorkDir != tt.dir {
+			t.Errorf("%s: got argv %v dir %q, want %v %q", tt.info.SessionID, r.Argv, r.WorkDir, tt.argv, tt.dir)
+		}
+		if got := r.ShellCommand(); got != tt.shell {
+			t.Errorf("%s: ShellCommand = %q, want %q", tt.info.SessionID, got, tt.shell)
+		}
+	}
+
+	if _, err := NewResumeInfo(&SessionInfo{SessionID: "unknown", Provider: "codex"}); err == nil {
+		t.Error("expected an error for a session without an ID")
+	}
+}
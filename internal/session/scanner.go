@@ -2,16 +2,22 @@ package session
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/user"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
 
+	aglogs_config "github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/agentlogs/internal/progress"
 	"github.com/grovetools/agentlogs/pkg/transcript"
 	"github.com/grovetools/core/config"
 	"github.com/grovetools/core/logging"
@@ -28,6 +34,25 @@ type ScanOptions struct {
 	// in scan results. These are Claude's internal sub-agents (e.g. workflow
 	// agents), not main sessions, so they are excluded by default.
 	IncludeSubagents bool
+
+	// Since, when non-zero, drops sessions with no activity at or after this
+	// time. Transcript files last modified before Since are skipped without
+	// being parsed.
+	Since time.Time
+	// Until, when non-zero, drops sessions that started after this time.
+	Until time.Time
+
+	// Progress, when set, reports progress through the transcripts being
+	// parsed.
+	Progress *progress.Bar
+
+	// JobMatcher finds the jobs user prompts start. Nil uses the one the
+	// aglogs config describes (see JobMatcherFromConfig).
+	JobMatcher *JobMatcher
+
+	// Sources adjusts the transcript roots scanned, like the config's
+	// sources section (see ResolveSources). Nil uses the config's.
+	Sources []aglogs_config.SourceConfig
 }
 
 // Scanner is responsible for finding and parsing session transcript logs.
@@ -105,8 +130,9 @@ func (s *Scanner) loadSessionsFromDaemon() ([]SessionInfo, error) {
 		var jobs []JobInfo
 		if ds.PlanName != "" && ds.JobFilePath != "" {
 			jobs = append(jobs, JobInfo{
-				Plan: ds.PlanName,
-				Job:  filepath.Base(ds.JobFilePath),
+				Plan:     ds.PlanName,
+				Job:      filepath.Base(ds.JobFilePath),
+				PlanPath: planPathOf(ds.JobFilePath),
 			})
 		}
 
@@ -125,6 +151,7 @@ func (s *Scanner) loadSessionsFromDaemon() ([]SessionInfo, error) {
 			Provider:    ds.Provider,
 			Status:      ds.Status,
 			PID:         ds.PID,
+			User:        ds.User,
 		})
 	}
 
@@ -200,6 +227,12 @@ func (s *Scanner) loadSessionRegistry() (map[string]sessions.SessionMetadata, er
 
 // Scan searches for and parses all Claude and Codex session logs.
 func (s *Scanner) Scan() ([]SessionInfo, error) {
+	return s.ScanContext(context.Background())
+}
+
+// ScanContext is Scan, stopping with ctx's error when ctx is cancelled
+// while transcripts are being parsed.
+func (s *Scanner) ScanContext(ctx context.Context) ([]SessionInfo, error) {
 	logger := logging.NewLogger("aglogs-scan")
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -239,28 +272,42 @@ func (s *Scanner) Scan() ([]SessionInfo, error) {
 		}
 	}
 
-	claudePattern := filepath.Join(homeDir, ".claude", "projects", "*", "*.jsonl")
-	claudeMatchesRaw, _ := filepath.Glob(claudePattern)
-
-	// Filter out agent sidechain files (e.g., agent-*.jsonl) unless
-	// explicitly requested. These are Claude's internal sub-agents, not
-	// main sessions.
-	var claudeMatches []string
-	for _, match := range claudeMatchesRaw {
-		if !s.opts.IncludeSubagents && strings.HasPrefix(filepath.Base(match), "agent-") {
+	// The transcript roots are the providers' usual directories, as
+	// adjusted by the config's sources section.
+	sources := s.sources(homeDir)
+	providerOf := make(map[string]string) // transcript path -> its source's provider
+	var claudeMatches, codexMatches, piMatches []string
+	for _, src := range sources {
+		pattern := src.TranscriptGlob()
+		if pattern == "" {
 			continue
 		}
-		claudeMatches = append(claudeMatches, match)
+		found, _ := transcript.GlobTranscripts(pattern)
+		for _, match := range found {
+			if _, seen := providerOf[match]; seen {
+				continue
+			}
+			// Filter out agent sidechain files (e.g., agent-*.jsonl) unless
+			// explicitly requested. These are Claude's internal sub-agents,
+			// not main sessions.
+			if src.Provider == "claude" && !s.opts.IncludeSubagents && strings.HasPrefix(filepath.Base(match), "agent-") {
+				continue
+			}
+			providerOf[match] = src.Provider
+			switch src.Provider {
+			case "claude":
+				claudeMatches = append(claudeMatches, match)
+			case "codex":
+				codexMatches = append(codexMatches, match)
+			case "pi":
+				piMatches = append(piMatches, match)
+			}
+		}
 	}
 
-	codexPattern := transcript.CodexSessionsGlob(homeDir, "")
-	codexMatches, _ := filepath.Glob(codexPattern)
-
-	piPattern := transcript.PiSessionsGlob(homeDir, "")
-	piMatches, _ := filepath.Glob(piPattern)
-
 	matches := append(claudeMatches, codexMatches...)
 	matches = append(matches, piMatches...)
+	matches = s.modifiedSince(matches)
 	logger.WithFields(map[string]interface{}{
 		"claude_count": len(claudeMatches),
 		"codex_count":  len(codexMatches),
@@ -268,24 +315,30 @@ func (s *Scanner) Scan() ([]SessionInfo, error) {
 		"total":        len(matches),
 	}).Debug("Found transcript files")
 
+	// Parse the transcripts concurrently; the results are merged below in
+	// the order of matches, so the scan's output is deterministic.
+	parsed := make([]parsedLog, len(matches))
+	s.opts.Progress.SetTotal(len(matches))
+	ParallelEach(len(matches), func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+		parsed[i] = s.parseLog(matches[i], providerOf[matches[i]])
+		s.opts.Progress.Add(1)
+	})
+	s.opts.Progress.Finish()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	var sessions []SessionInfo
 	// Track which registry sessions we've already added to avoid duplicates
 	// (multiple .jsonl files like agent sidechains can have the same sessionID)
 	processedRegistrySessions := make(map[string]bool)
 
-	for _, logPath := range matches {
-		var sessionID, cwd string
-		var startedAt time.Time
-		var jobs []JobInfo
-		found := false
-
-		if strings.Contains(logPath, "/.codex/") {
-			sessionID, cwd, startedAt, jobs, found = s.parseCodexLog(logPath)
-		} else if strings.Contains(logPath, "/.pi/") {
-			sessionID, cwd, startedAt, jobs, found = s.parsePiLog(logPath)
-		} else {
-			sessionID, cwd, startedAt, jobs, found = s.parseClaudeLog(logPath)
-		}
+	for i, logPath := range matches {
+		p := parsed[i]
+		sessionID, startedAt, jobs, env, found := p.sessionID, p.startedAt, p.jobs, p.env, p.found
 
 		logger.WithFields(map[string]interface{}{
 			"transcript_file": filepath.Base(logPath),
@@ -330,6 +383,7 @@ func (s *Scanner) Scan() ([]SessionInfo, error) {
 					Plan:      metadata.PlanName,
 					Job:       filepath.Base(metadata.JobFilePath),
 					LineIndex: lineIndex,
+					PlanPath:  planPathOf(metadata.JobFilePath),
 				})
 			}
 
@@ -340,10 +394,19 @@ func (s *Scanner) Scan() ([]SessionInfo, error) {
 				transcriptPath = metadata.TranscriptPath
 			}
 
+			// The registry records the branch the agent was launched on.
+			gitBranch := metadata.Branch
+			if gitBranch == "" {
+				gitBranch = p.gitBranch
+			}
+
 			// Determine provider based on path
 			provider := metadata.Provider
 			if provider == "" {
-				provider = providerFromTranscriptPath(transcriptPath)
+				provider = providerOf[transcriptPath]
+			}
+			if provider == "" {
+				provider = ProviderForPath(transcriptPath)
 			}
 
 			sessions = append(sessions, SessionInfo{
@@ -356,6 +419,13 @@ func (s *Scanner) Scan() ([]SessionInfo, error) {
 				LogFilePath: transcriptPath,
 				StartedAt:   metadata.StartedAt,
 				Provider:    provider,
+				User:        metadata.User,
+				Environment: env,
+				Title:       p.title,
+				Preview:     p.preview,
+				Model:       p.model,
+				GitBranch:   gitBranch,
+				GitCommit:   p.gitCommit,
 			})
 			continue // Skip to next log file
 		}
@@ -373,34 +443,35 @@ func (s *Scanner) Scan() ([]SessionInfo, error) {
 			if err != nil {
 				continue
 			}
-			// Determine provider from path
-			provider := providerFromTranscriptPath(logPath)
 			sessions = append(sessions, SessionInfo{
-				SessionID:   strings.TrimSuffix(filepath.Base(logPath), ".jsonl"),
+				SessionID:   transcript.TrimTranscriptExt(filepath.Base(logPath)),
 				ProjectName: "unknown",
 				ProjectPath: "unknown",
 				Worktree:    "",
 				Jobs:        []JobInfo{},
 				LogFilePath: logPath,
 				StartedAt:   stat.ModTime(),
-				Provider:    provider,
+				Provider:    providerOf[logPath],
 			})
 			continue
 		}
 
-		projectPath, projectName, worktree, ecosystem := s.parseProjectPath(cwd)
-		// Determine provider from path
-		provider := providerFromTranscriptPath(logPath)
 		sessions = append(sessions, SessionInfo{
 			SessionID:   sessionID,
-			ProjectName: projectName,
-			ProjectPath: projectPath,
-			Worktree:    worktree,
-			Ecosystem:   ecosystem,
+			ProjectName: p.projectName,
+			ProjectPath: p.projectPath,
+			Worktree:    p.worktree,
+			Ecosystem:   p.ecosystem,
 			Jobs:        jobs,
 			LogFilePath: logPath,
 			StartedAt:   startedAt,
-			Provider:    provider,
+			Provider:    providerOf[logPath],
+			Environment: env,
+			Title:       p.title,
+			Preview:     p.preview,
+			Model:       p.model,
+			GitBranch:   p.gitBranch,
+			GitCommit:   p.gitCommit,
 		})
 	}
 
@@ -413,12 +484,20 @@ func (s *Scanner) Scan() ([]SessionInfo, error) {
 	}
 
 	// 6. Scan for OpenCode sessions.
-	opencodeSessions, err := s.scanOpenCodeSessions()
-	if err != nil {
-		logger.WithError(err).Warn("Could not scan for OpenCode sessions, proceeding without them")
-	} else {
+	for _, src := range sources {
+		if src.Provider != "opencode" {
+			continue
+		}
+		opencodeSessions, err := s.scanOpenCodeSessions(src.Path)
+		if err != nil {
+			logger.WithError(err).Warn("Could not scan for OpenCode sessions, proceeding without them")
+			continue
+		}
 		sessions = append(sessions, opencodeSessions...)
-		logger.WithField("opencode_count", len(opencodeSessions)).Debug("Added OpenCode sessions")
+		logger.WithFields(map[string]interface{}{
+			"storage":        src.Path,
+			"opencode_count": len(opencodeSessions),
+		}).Debug("Added OpenCode sessions")
 	}
 
 	// 7. Add daemon sessions that weren't already found via filesystem scanning.
@@ -433,16 +512,149 @@ func (s *Scanner) Scan() ([]SessionInfo, error) {
 		}
 	}
 
+	// 8. Attribute sessions without a recorded user. Transcripts under this
+	// home directory were written by the current OS user.
+	if osUser := localUsername(); osUser != "" {
+		for i := range sessions {
+			if sessions[i].User == "" && strings.HasPrefix(sessions[i].LogFilePath, homeDir+string(filepath.Separator)) {
+				sessions[i].User = osUser
+			}
+		}
+	}
+
+	// 9. Every session with a transcript carries a provider, so commands
+	// never need to re-derive it from the path.
+	for i := range sessions {
+		if sessions[i].Provider == "" && sessions[i].LogFilePath != "" {
+			sessions[i].Provider = ProviderForPath(sessions[i].LogFilePath)
+		}
+	}
+
+	// 10. Record when each JSONL transcript last saw activity.
+	ParallelEach(len(sessions), func(i int) {
+		if sessions[i].EndedAt.IsZero() && transcript.IsTranscriptFile(sessions[i].LogFilePath) {
+			sessions[i].EndedAt = transcriptEndTime(sessions[i].LogFilePath)
+		}
+	})
+
+	// 11. Apply the time window to sessions that did not come from a
+	// transcript file (registry, archives, OpenCode, daemon).
+	if !s.opts.Since.IsZero() || !s.opts.Until.IsZero() {
+		filtered := sessions[:0]
+		for _, session := range sessions {
+			if s.inTimeWindow(session) {
+				filtered = append(filtered, session)
+			}
+		}
+		sessions = filtered
+	}
+
 	return sessions, nil
 }
 
-// providerFromTranscriptPath infers a provider name from where a transcript
-// file lives on disk: ~/.codex/ -> codex, ~/.pi/ -> pi, anything else claude.
-func providerFromTranscriptPath(path string) string {
+// modifiedSince drops transcript paths last modified before opts.Since, so
+// stale files are never opened.
+func (s *Scanner) modifiedSince(paths []string) []string {
+	if s.opts.Since.IsZero() {
+		return paths
+	}
+	var kept []string
+	for _, path := range paths {
+		if st, err := os.Stat(path); err == nil && !st.ModTime().Before(s.opts.Since) {
+			kept = append(kept, path)
+		}
+	}
+	return kept
+}
+
+// inTimeWindow reports whether a session was active at or after opts.Since
+// and started no later than opts.Until. Running sessions count as active now.
+func (s *Scanner) inTimeWindow(info SessionInfo) bool {
+	if !s.opts.Until.IsZero() && info.StartedAt.After(s.opts.Until) {
+		return false
+	}
+	if s.opts.Since.IsZero() || info.Status == "running" {
+		return true
+	}
+	lastActive := info.EndedAt
+	if lastActive.IsZero() {
+		lastActive = info.StartedAt
+	}
+	return !lastActive.Before(s.opts.Since)
+}
+
+// endTimeTailBytes is how much of a transcript's tail is read to find its
+// last timestamp. Single lines can be larger (tool output), in which case
+// earlier complete lines in the window are used.
+const endTimeTailBytes = 256 * 1024
+
+// transcriptEndTime returns the timestamp of the last line in a JSONL
+// transcript that carries one, reading only the file's tail. It falls back to
+// the file's modification time when no timestamp is found. All supported
+// providers (Claude, Codex, pi) write an RFC 3339 top-level "timestamp".
+// A compressed transcript has no readable tail; its modification time is
+// used, which compression preserves.
+func transcriptEndTime(path string) time.Time {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return time.Time{}
+	}
+	if transcript.IsCompressedPath(path) {
+		return st.ModTime()
+	}
+
+	offset := st.Size() - endTimeTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, st.Size()-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil && !errors.Is(err, io.EOF) {
+		return st.ModTime()
+	}
+
+	lines := bytes.Split(buf, []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		var line struct {
+			Timestamp time.Time `json:"timestamp"`
+		}
+		if json.Unmarshal(lines[i], &line) == nil && !line.Timestamp.IsZero() {
+			return line.Timestamp
+		}
+	}
+	return st.ModTime()
+}
+
+// localUsername returns the login name of the current OS user, falling back
+// to $USER when the user database is unavailable (e.g. static builds).
+func localUsername() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// ProviderForPath infers a provider name from where a transcript file lives
+// on disk: a source from the config's sources section -> its provider,
+// ~/.codex/ -> codex, opencode storage -> opencode, a pi session layout ->
+// pi, anything else claude. It is the fallback for sessions whose registry
+// metadata does not record a provider, and for commands given a bare log
+// file path.
+func ProviderForPath(path string) string {
+	if provider := sourceProviderForPath(path); provider != "" {
+		return provider
+	}
+	slashed := filepath.ToSlash(path)
 	switch {
-	case strings.Contains(path, "/.codex/"):
+	case strings.Contains(slashed, "/.codex/") || strings.Contains(slashed, "/codex/sessions/"):
 		return "codex"
-	case strings.Contains(path, "/.pi/"):
+	case strings.Contains(slashed, "/opencode/storage/"):
+		return "opencode"
+	case transcript.IsPiSessionPath(path):
 		return "pi"
 	default:
 		return "claude"
@@ -556,46 +768,85 @@ func (s *Scanner) resolveJobFilenameByID(planDir, jobID string) string {
 	return result
 }
 
-func (s *Scanner) parsePlanInfo(content string) (plan, job string) {
-	if strings.Contains(content, "Read the file") && strings.Contains(content, "and execute the agent job") {
-		start := strings.Index(content, "/")
-		if start == -1 {
-			return "", ""
-		}
+// parsePlanInfo returns the plan job a user prompt starts, by its job marker
+// or the configured prompt patterns.
+func (s *Scanner) parsePlanInfo(content string) (plan, job, planPath string) {
+	m := s.opts.JobMatcher
+	if m == nil {
+		m = configuredJobMatcher()
+	}
+	return m.Match(content)
+}
 
-		end := strings.Index(content[start:], " and")
-		if end == -1 {
-			end = strings.Index(content[start:], " ")
-		}
-		if end == -1 {
-			return "", ""
-		}
+// sources returns the transcript roots to scan under homeDir.
+func (s *Scanner) sources(homeDir string) []Source {
+	if s.opts.Sources == nil {
+		return Sources(homeDir)
+	}
+	return ResolveSources(homeDir, s.opts.Sources)
+}
 
-		path := content[start : start+end]
+// parsedLog is what parseLog learns from one transcript file.
+type parsedLog struct {
+	sessionID string
+	startedAt time.Time
+	jobs      []JobInfo
+	env       *AgentEnvironment
+	found     bool
+	// title is the conversation title the transcript records, or else
+	// one made from prompt.
+	title string
+	// prompt is the first user prompt that promptTitle can title.
+	prompt string
+	// preview is prompt shortened onto one line.
+	preview string
+	// model is the first model the transcript names.
+	model string
+	// The git branch and commit the session started on (see ReadGitInfo).
+	gitBranch, gitCommit string
+	// The project the session's working directory belongs to; set when
+	// found.
+	projectPath, projectName, worktree, ecosystem string
+}
 
-		if strings.Contains(path, "/plans/") && strings.HasSuffix(path, ".md") {
-			parts := strings.Split(path, "/")
-			if len(parts) >= 2 {
-				job = parts[len(parts)-1]
-				plan = parts[len(parts)-2]
-			}
-		}
+// parseLog reads a transcript's identity and jobs with the parser for
+// provider, and resolves its working directory to a project. It is safe to
+// call concurrently.
+func (s *Scanner) parseLog(logPath, provider string) parsedLog {
+	var p parsedLog
+	var cwd string
+	switch provider {
+	case "codex":
+		p.sessionID, cwd, p.startedAt, p.jobs, p.env, p.prompt, p.model, p.found = s.parseCodexLog(logPath)
+		p.gitBranch, p.gitCommit = ReadGitInfo(logPath, "codex")
+	case "pi":
+		p.sessionID, cwd, p.startedAt, p.jobs, p.prompt, p.model, p.found = s.parsePiLog(logPath)
+	default:
+		p.sessionID, cwd, p.startedAt, p.jobs, p.title, p.prompt, p.model, p.found = s.parseClaudeLog(logPath)
+		p.gitBranch, p.gitCommit = ReadGitInfo(logPath, "claude")
+	}
+	if p.title == "" {
+		p.title = promptTitle(p.prompt)
 	}
-	return plan, job
+	p.preview = promptPreview(p.prompt)
+	if p.found {
+		p.projectPath, p.projectName, p.worktree, p.ecosystem = s.parseProjectPath(cwd)
+	}
+	return p
 }
 
-func (s *Scanner) parseClaudeLog(logPath string) (sessionID, cwd string, startedAt time.Time, jobs []JobInfo, found bool) {
-	file, err := os.Open(logPath)
+// parseClaudeLog reads a Claude transcript's identity, jobs, title, first
+// prompt and model from its first lines. The title is the last "summary"
+// line among them; the model is the first assistant message's.
+func (s *Scanner) parseClaudeLog(logPath string) (sessionID, cwd string, startedAt time.Time, jobs []JobInfo, title, prompt, model string, found bool) {
+	file, err := transcript.OpenTranscript(logPath)
 	if err != nil {
 		return
 	}
 	defer file.Close()
 
 	jobMap := make(map[string]bool)
-	scanner := bufio.NewScanner(file)
-	const maxScanTokenSize = 1024 * 1024 // 1MB
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, maxScanTokenSize)
+	scanner := transcript.NewLineScanner(file)
 	lineIndex := 0
 
 	for scanner.Scan() {
@@ -609,13 +860,27 @@ func (s *Scanner) parseClaudeLog(logPath string) (sessionID, cwd string, started
 			SessionID string    `json:"sessionId"`
 			Timestamp time.Time `json:"timestamp"`
 			Type      string    `json:"type"`
-			Message   struct {
-				Role    string `json:"role"`
-				Content string `json:"content"`
+			Summary   string    `json:"summary"`
+			// IsMeta marks messages Claude Code adds itself (the local
+			// command caveat).
+			IsMeta           bool `json:"isMeta"`
+			IsCompactSummary bool `json:"isCompactSummary"`
+			Message          struct {
+				Role    string          `json:"role"`
+				Model   string          `json:"model"`
+				Content json.RawMessage `json:"content"`
 			} `json:"message"`
 		}
 
 		if err := json.Unmarshal(scanner.Bytes(), &msg); err == nil {
+			// Claude Code writes its own notices (API errors, interrupts)
+			// as "<synthetic>" assistant messages.
+			if model == "" && msg.Type == "assistant" && msg.Message.Model != "<synthetic>" {
+				model = msg.Message.Model
+			}
+			if msg.Type == "summary" && strings.TrimSpace(msg.Summary) != "" {
+				title = strings.TrimSpace(msg.Summary)
+			}
 			if !found && msg.Cwd != "" && msg.SessionID != "" && !msg.Timestamp.IsZero() {
 				sessionID = msg.SessionID
 				cwd = msg.Cwd
@@ -624,18 +889,22 @@ func (s *Scanner) parseClaudeLog(logPath string) (sessionID, cwd string, started
 			}
 
 			if msg.Type == "user" && msg.Message.Role == "user" {
-				if plan, job := s.parsePlanInfo(msg.Message.Content); plan != "" && job != "" {
+				text := userText(msg.Message.Content)
+				if prompt == "" && !msg.IsMeta && !msg.IsCompactSummary && promptTitle(text) != "" {
+					prompt = text
+				}
+				if plan, job, planPath := s.parsePlanInfo(text); plan != "" && job != "" {
 					key := plan + ":" + job
 					if !jobMap[key] {
 						jobMap[key] = true
-						jobs = append(jobs, JobInfo{Plan: plan, Job: job, LineIndex: lineIndex})
+						jobs = append(jobs, JobInfo{Plan: plan, Job: job, LineIndex: lineIndex, PlanPath: planPath})
 					}
-				} else if planDir, planName, jobID := s.parseBriefingInfo(msg.Message.Content); jobID != "" {
+				} else if planDir, planName, jobID := s.parseBriefingInfo(text); jobID != "" {
 					if jobFilename := s.resolveJobFilenameByID(planDir, jobID); jobFilename != "" {
 						key := planName + ":" + jobFilename
 						if !jobMap[key] {
 							jobMap[key] = true
-							jobs = append(jobs, JobInfo{Plan: planName, Job: jobFilename, LineIndex: lineIndex})
+							jobs = append(jobs, JobInfo{Plan: planName, Job: jobFilename, LineIndex: lineIndex, PlanPath: planPathOf(filepath.Join(planDir, jobFilename))})
 						}
 					}
 				}
@@ -649,18 +918,15 @@ func (s *Scanner) parseClaudeLog(logPath string) (sessionID, cwd string, started
 	return
 }
 
-func (s *Scanner) parseCodexLog(logPath string) (sessionID, cwd string, startedAt time.Time, jobs []JobInfo, found bool) {
-	file, err := os.Open(logPath)
+func (s *Scanner) parseCodexLog(logPath string) (sessionID, cwd string, startedAt time.Time, jobs []JobInfo, env *AgentEnvironment, prompt, model string, found bool) {
+	file, err := transcript.OpenTranscript(logPath)
 	if err != nil {
 		return
 	}
 	defer file.Close()
 
 	jobMap := make(map[string]bool)
-	scanner := bufio.NewScanner(file)
-	const maxScanTokenSize = 1024 * 1024 // 1MB
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, maxScanTokenSize)
+	scanner := transcript.NewLineScanner(file)
 	lineIndex := 0
 
 	for scanner.Scan() {
@@ -686,6 +952,12 @@ func (s *Scanner) parseCodexLog(logPath string) (sessionID, cwd string, startedA
 			}
 		}
 
+		if entry["type"] == "turn_context" && model == "" {
+			if payload, ok := entry["payload"].(map[string]interface{}); ok {
+				model, _ = payload["model"].(string)
+			}
+		}
+
 		if entry["type"] == "response_item" {
 			if payload, ok := entry["payload"].(map[string]interface{}); ok {
 				if ptype, ok := payload["type"].(string); ok && ptype == "message" && payload["role"] == "user" {
@@ -693,18 +965,22 @@ func (s *Scanner) parseCodexLog(logPath string) (sessionID, cwd string, startedA
 						for _, c := range content {
 							if cMap, ok := c.(map[string]interface{}); ok && cMap["type"] == "input_text" {
 								if text, ok := cMap["text"].(string); ok {
-									if strings.Contains(text, "<environment_context>") {
-										re := regexp.MustCompile(`<cwd>(.*)</cwd>`)
-										matches := re.FindStringSubmatch(text)
-										if len(matches) > 1 {
-											cwd = matches[1]
+									if parsed, ok := ParseCodexEnvironment(text); ok {
+										if env == nil {
+											env = &parsed
+										}
+										if parsed.Cwd != "" {
+											cwd = parsed.Cwd
 										}
 									} else {
-										if plan, job := s.parsePlanInfo(text); plan != "" && job != "" {
+										if prompt == "" && promptTitle(text) != "" {
+											prompt = text
+										}
+										if plan, job, planPath := s.parsePlanInfo(text); plan != "" && job != "" {
 											key := plan + ":" + job
 											if !jobMap[key] {
 												jobMap[key] = true
-												jobs = append(jobs, JobInfo{Plan: plan, Job: job, LineIndex: lineIndex})
+												jobs = append(jobs, JobInfo{Plan: plan, Job: job, LineIndex: lineIndex, PlanPath: planPath})
 											}
 										}
 									}
@@ -733,18 +1009,15 @@ func (s *Scanner) parseCodexLog(logPath string) (sessionID, cwd string, startedA
 // ({"type":"session","id":...,"timestamp":...,"cwd":...}); conversation turns
 // are {"type":"message","message":{role,content}} entries whose user text may
 // embed a flow briefing instruction (session-manager.ts in the pi source).
-func (s *Scanner) parsePiLog(logPath string) (sessionID, cwd string, startedAt time.Time, jobs []JobInfo, found bool) {
-	file, err := os.Open(logPath)
+func (s *Scanner) parsePiLog(logPath string) (sessionID, cwd string, startedAt time.Time, jobs []JobInfo, prompt, model string, found bool) {
+	file, err := transcript.OpenTranscript(logPath)
 	if err != nil {
 		return
 	}
 	defer file.Close()
 
 	jobMap := make(map[string]bool)
-	scanner := bufio.NewScanner(file)
-	const maxScanTokenSize = 1024 * 1024 // 1MB
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, maxScanTokenSize)
+	scanner := transcript.NewLineScanner(file)
 	lineIndex := 0
 
 	for scanner.Scan() {
@@ -758,8 +1031,10 @@ func (s *Scanner) parsePiLog(logPath string) (sessionID, cwd string, startedAt t
 			ID        string `json:"id"`
 			Timestamp string `json:"timestamp"`
 			Cwd       string `json:"cwd"`
+			ModelID   string `json:"modelId"` // model_change
 			Message   struct {
 				Role    string          `json:"role"`
+				Model   string          `json:"model"`
 				Content json.RawMessage `json:"content"`
 			} `json:"message"`
 		}
@@ -774,26 +1049,36 @@ func (s *Scanner) parsePiLog(logPath string) (sessionID, cwd string, startedAt t
 			cwd = entry.Cwd
 			startedAt, _ = time.Parse(time.RFC3339Nano, entry.Timestamp)
 			found = sessionID != ""
+		case "model_change":
+			if model == "" {
+				model = entry.ModelID
+			}
 		case "message":
+			if model == "" && entry.Message.Role == "assistant" {
+				model = entry.Message.Model
+			}
 			if entry.Message.Role != "user" {
 				break
 			}
-			text := piUserText(entry.Message.Content)
+			text := userText(entry.Message.Content)
 			if text == "" {
 				break
 			}
-			if plan, job := s.parsePlanInfo(text); plan != "" && job != "" {
+			if prompt == "" && promptTitle(text) != "" {
+				prompt = text
+			}
+			if plan, job, planPath := s.parsePlanInfo(text); plan != "" && job != "" {
 				key := plan + ":" + job
 				if !jobMap[key] {
 					jobMap[key] = true
-					jobs = append(jobs, JobInfo{Plan: plan, Job: job, LineIndex: lineIndex})
+					jobs = append(jobs, JobInfo{Plan: plan, Job: job, LineIndex: lineIndex, PlanPath: planPath})
 				}
 			} else if planDir, planName, jobID := s.parseBriefingInfo(text); jobID != "" {
 				if jobFilename := s.resolveJobFilenameByID(planDir, jobID); jobFilename != "" {
 					key := planName + ":" + jobFilename
 					if !jobMap[key] {
 						jobMap[key] = true
-						jobs = append(jobs, JobInfo{Plan: planName, Job: jobFilename, LineIndex: lineIndex})
+						jobs = append(jobs, JobInfo{Plan: planName, Job: jobFilename, LineIndex: lineIndex, PlanPath: planPathOf(filepath.Join(planDir, jobFilename))})
 					}
 				}
 			}
@@ -807,9 +1092,40 @@ func (s *Scanner) parsePiLog(logPath string) (sessionID, cwd string, startedAt t
 	return
 }
 
-// piUserText flattens a pi user-message content payload (a plain string or an
-// array of {type:"text",text} blocks) into a single string.
-func piUserText(content json.RawMessage) string {
+// maxTitleRunes caps a title made from a prompt.
+const maxTitleRunes = 80
+
+// promptTitle titles a session by its first prompt: the prompt's first
+// line, with runs of whitespace collapsed. Prompts that are markup (slash
+// commands, injected <environment_context> or instructions) yield "".
+func promptTitle(prompt string) string {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" || strings.HasPrefix(prompt, "<") {
+		return ""
+	}
+	line, _, _ := strings.Cut(prompt, "\n")
+	line = strings.Join(strings.Fields(line), " ")
+	if runes := []rune(line); len(runes) > maxTitleRunes {
+		line = string(runes[:maxTitleRunes-1]) + "…"
+	}
+	return line
+}
+
+// maxPreviewRunes caps a prompt preview.
+const maxPreviewRunes = 200
+
+// promptPreview puts the start of a prompt on one line.
+func promptPreview(prompt string) string {
+	preview := strings.Join(strings.Fields(prompt), " ")
+	if runes := []rune(preview); len(runes) > maxPreviewRunes {
+		preview = string(runes[:maxPreviewRunes-1]) + "…"
+	}
+	return preview
+}
+
+// userText flattens a Claude or pi user-message content payload (a plain
+// string or an array of {type:"text",text} blocks) into a single string.
+func userText(content json.RawMessage) string {
 	if len(content) == 0 {
 		return ""
 	}
@@ -839,28 +1155,16 @@ func piUserText(content json.RawMessage) string {
 // scanForArchivedSessions finds sessions archived in plan artifact directories.
 func (s *Scanner) scanForArchivedSessions() ([]SessionInfo, error) {
 	var archivedSessions []SessionInfo
-	logger := logging.NewLogger("aglogs-archive-scan")
 
 	// 1. Use grove-core to find all plan directories.
-	coreCfg, err := config.LoadDefault()
-	if err != nil {
-		coreCfg = &config.Config{} // Proceed with defaults
-	}
-	discoveryService := workspace.NewDiscoveryService(logger.Logger)
-	discoveryResult, err := discoveryService.DiscoverAll()
-	if err != nil {
-		return nil, fmt.Errorf("workspace discovery failed: %w", err)
-	}
-	provider := workspace.NewProvider(discoveryResult)
-	locator := workspace.NewNotebookLocator(coreCfg)
-	scannedDirs, err := locator.ScanForAllPlans(provider)
+	planDirs, err := allPlanDirs()
 	if err != nil {
-		return nil, fmt.Errorf("failed to scan for plans: %w", err)
+		return nil, err
 	}
 
 	// 2. For each plan directory, search for archived sessions.
-	for _, scannedDir := range scannedDirs {
-		artifactsDir := filepath.Join(scannedDir.Path, ".artifacts")
+	for _, planDir := range planDirs {
+		artifactsDir := filepath.Join(planDir, ".artifacts")
 		jobDirs, err := os.ReadDir(artifactsDir)
 		if err != nil {
 			continue
@@ -870,69 +1174,98 @@ func (s *Scanner) scanForArchivedSessions() ([]SessionInfo, error) {
 			if !jobEntry.IsDir() {
 				continue
 			}
-
-			metadataPath := filepath.Join(artifactsDir, jobEntry.Name(), "metadata.json")
-			if _, err := os.Stat(metadataPath); os.IsNotExist(err) {
-				continue
+			if info, err := s.readArchivedSession(filepath.Join(artifactsDir, jobEntry.Name())); err == nil {
+				archivedSessions = append(archivedSessions, info)
 			}
+		}
+	}
+	return archivedSessions, nil
+}
 
-			// 3. Parse metadata and construct SessionInfo.
-			data, err := os.ReadFile(metadataPath)
-			if err != nil {
-				continue
-			}
-			var metadata sessions.SessionMetadata
-			if err := json.Unmarshal(data, &metadata); err != nil {
-				continue
-			}
+// allPlanDirs lists the plan directories of every workspace grove knows
+// about.
+func allPlanDirs() ([]string, error) {
+	logger := logging.NewLogger("aglogs-archive-scan")
+	coreCfg, err := config.LoadDefault()
+	if err != nil {
+		coreCfg = &config.Config{} // Proceed with defaults
+	}
+	discoveryService := workspace.NewDiscoveryService(logger.Logger)
+	discoveryResult, err := discoveryService.DiscoverAll()
+	if err != nil {
+		return nil, fmt.Errorf("workspace discovery failed: %w", err)
+	}
+	provider := workspace.NewProvider(discoveryResult)
+	locator := workspace.NewNotebookLocator(coreCfg)
+	scannedDirs, err := locator.ScanForAllPlans(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for plans: %w", err)
+	}
+	dirs := make([]string, 0, len(scannedDirs))
+	for _, d := range scannedDirs {
+		dirs = append(dirs, d.Path)
+	}
+	return dirs, nil
+}
 
-			transcriptPath := filepath.Join(artifactsDir, jobEntry.Name(), "transcript.jsonl")
+// readArchivedSession builds a session from a job's artifact directory:
+// its metadata.json, and transcript.jsonl (or transcript.jsonl.gz).
+func (s *Scanner) readArchivedSession(jobDir string) (SessionInfo, error) {
+	data, err := os.ReadFile(filepath.Join(jobDir, "metadata.json"))
+	if err != nil {
+		return SessionInfo{}, err
+	}
+	var metadata sessions.SessionMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return SessionInfo{}, fmt.Errorf("invalid metadata in %s: %w", jobDir, err)
+	}
 
-			// Construct a JobInfo from the metadata
-			jobInfo := []JobInfo{}
-			if metadata.PlanName != "" && metadata.JobFilePath != "" {
-				jobInfo = append(jobInfo, JobInfo{
-					Plan:      metadata.PlanName,
-					Job:       filepath.Base(metadata.JobFilePath),
-					LineIndex: 0, // Not relevant for archived sessions
-				})
-			}
+	transcriptPath := filepath.Join(jobDir, "transcript.jsonl")
+	if _, err := os.Stat(transcriptPath); err != nil {
+		if _, err := os.Stat(transcriptPath + transcript.CompressedExt); err == nil {
+			transcriptPath += transcript.CompressedExt
+		}
+	}
 
-			projectPath, projectName, worktree, ecosystem := s.parseProjectPath(metadata.WorkingDirectory)
+	// Construct a JobInfo from the metadata
+	jobInfo := []JobInfo{}
+	if metadata.PlanName != "" && metadata.JobFilePath != "" {
+		jobInfo = append(jobInfo, JobInfo{
+			Plan:      metadata.PlanName,
+			Job:       filepath.Base(metadata.JobFilePath),
+			LineIndex: 0, // Not relevant for archived sessions
+			PlanPath:  planPathOf(metadata.JobFilePath),
+		})
+	}
 
-			// Determine provider - archived sessions are typically Claude (the primary use case)
-			provider := metadata.Provider
-			if provider == "" {
-				provider = "claude"
-			}
+	projectPath, projectName, worktree, ecosystem := s.parseProjectPath(metadata.WorkingDirectory)
 
-			archivedSessions = append(archivedSessions, SessionInfo{
-				SessionID:   metadata.ClaudeSessionID, // Use the native agent ID
-				ProjectName: projectName,
-				ProjectPath: projectPath,
-				Worktree:    worktree,
-				Ecosystem:   ecosystem,
-				Jobs:        jobInfo,
-				LogFilePath: transcriptPath, // Point to the archived transcript
-				StartedAt:   metadata.StartedAt,
-				Provider:    provider,
-			})
-		}
+	provider := metadata.Provider
+	if provider == "" {
+		provider = ProviderForPath(transcriptPath)
 	}
-	return archivedSessions, nil
+
+	return SessionInfo{
+		SessionID:   metadata.ClaudeSessionID, // Use the native agent ID
+		ProjectName: projectName,
+		ProjectPath: projectPath,
+		Worktree:    worktree,
+		Ecosystem:   ecosystem,
+		Jobs:        jobInfo,
+		LogFilePath: transcriptPath, // Point to the archived transcript
+		StartedAt:   metadata.StartedAt,
+		Provider:    provider,
+		User:        metadata.User,
+		GitBranch:   metadata.Branch,
+	}, nil
 }
 
-// scanOpenCodeSessions scans for OpenCode sessions in ~/.local/share/opencode/storage/
-func (s *Scanner) scanOpenCodeSessions() ([]SessionInfo, error) {
+// scanOpenCodeSessions scans for OpenCode sessions in a storage directory
+// such as ~/.local/share/opencode/storage/.
+func (s *Scanner) scanOpenCodeSessions(storageDir string) ([]SessionInfo, error) {
 	logger := logging.NewLogger("aglogs-opencode-scan")
 	var sessions []SessionInfo
 
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("getting home directory: %w", err)
-	}
-
-	storageDir := filepath.Join(homeDir, ".local", "share", "opencode", "storage")
 	projectsDir := filepath.Join(storageDir, "project")
 	sessionsDir := filepath.Join(storageDir, "session")
 
@@ -1030,6 +1363,10 @@ func (s *Scanner) scanOpenCodeSessions() ([]SessionInfo, error) {
 
 			// Convert timestamp (milliseconds to time.Time)
 			startedAt := time.Unix(0, session.Time.Created*int64(time.Millisecond))
+			var endedAt time.Time
+			if session.Time.Updated > 0 {
+				endedAt = time.Unix(0, session.Time.Updated*int64(time.Millisecond))
+			}
 
 			// For OpenCode, the LogFilePath points to the session metadata file
 			// The actual transcript needs to be assembled from message/ and part/ directories
@@ -1042,7 +1379,10 @@ func (s *Scanner) scanOpenCodeSessions() ([]SessionInfo, error) {
 				Jobs:        []JobInfo{}, // OpenCode sessions don't track grove jobs the same way
 				LogFilePath: sessionPath, // Points to the session metadata file
 				StartedAt:   startedAt,
+				EndedAt:     endedAt,
 				Provider:    "opencode",
+				Title:       session.Title,
+				Model:       openCodeModel(storageDir, session.ID),
 			})
 		}
 	}
@@ -1050,3 +1390,30 @@ func (s *Scanner) scanOpenCodeSessions() ([]SessionInfo, error) {
 	logger.WithField("session_count", len(sessions)).Debug("Found OpenCode sessions")
 	return sessions, nil
 }
+
+// openCodeModel returns the model of an OpenCode session's first assistant
+// message, from the message files under storage/message/<sessionID>.
+func openCodeModel(storageDir, sessionID string) string {
+	messagesDir := filepath.Join(storageDir, "message", sessionID)
+	files, err := os.ReadDir(messagesDir)
+	if err != nil {
+		return ""
+	}
+	for _, f := range files {
+		if !strings.HasPrefix(f.Name(), "msg_") || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(messagesDir, f.Name()))
+		if err != nil {
+			continue
+		}
+		var msg struct {
+			Role    string `json:"role"`
+			ModelID string `json:"modelID"`
+		}
+		if json.Unmarshal(data, &msg) == nil && msg.Role == "assistant" && msg.ModelID != "" {
+			return msg.ModelID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/grovetools/agentlogs/config"
+	"github.com/grovetools/core/logging"
+)
+
+// Source is a directory one provider's transcripts are read from.
+type Source struct {
+	Provider string `json:"provider"`
+	Path     string `json:"path"`
+	// Builtin is true for the provider's usual location, false for a
+	// directory added in the config's sources section.
+	Builtin bool `json:"builtin,omitempty"`
+}
+
+// builtinSources are each provider's usual transcript roots, relative to the
+// home directory, in scan order.
+var builtinSources = []Source{
+	{Provider: "claude", Path: ".claude/projects"},
+	{Provider: "codex", Path: ".codex/sessions"},
+	{Provider: "pi", Path: ".pi/agent/sessions"},
+	{Provider: "opencode", Path: ".local/share/opencode/storage"},
+}
+
+// TranscriptGlob matches the transcript files under the source, or is ""
+// for OpenCode, whose sessions are assembled from several files.
+func (src Source) TranscriptGlob() string {
+	switch src.Provider {
+	case "claude", "pi":
+		return filepath.Join(src.Path, "*", "*.jsonl")
+	case "codex":
+		return filepath.Join(src.Path, "*", "*", "*", "*.jsonl")
+	}
+	return ""
+}
+
+// contains reports whether path lies under the source's directory.
+func (src Source) contains(path string) bool {
+	rel, err := filepath.Rel(src.Path, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// ResolveSources applies a sources config to the built-in roots under
+// homeDir. An entry without a path (or with a built-in root's path) turns
+// that provider's built-in root off when disabled; any other enabled entry
+// adds its directory. Entries for unknown providers are skipped.
+func ResolveSources(homeDir string, cfg []config.SourceConfig) []Source {
+	var sources []Source
+	for _, b := range builtinSources {
+		sources = append(sources, Source{Provider: b.Provider, Path: filepath.Join(homeDir, filepath.FromSlash(b.Path)), Builtin: true})
+	}
+	for _, c := range cfg {
+		if !knownSourceProvider(c.Provider) {
+			continue
+		}
+		enabled := c.Enabled == nil || *c.Enabled
+		path := expandSourcePath(homeDir, c.Path)
+		i := -1
+		for j, src := range sources {
+			if src.Provider == c.Provider && ((path == "" && src.Builtin) || path == src.Path) {
+				i = j
+				break
+			}
+		}
+		switch {
+		case i >= 0 && !enabled:
+			sources = append(sources[:i], sources[i+1:]...)
+		case i < 0 && enabled && path != "":
+			sources = append(sources, Source{Provider: c.Provider, Path: path})
+		}
+	}
+	return sources
+}
+
+// Sources returns the transcript roots to read under homeDir: the
+// built-in ones as adjusted by the config's sources section.
+func Sources(homeDir string) []Source {
+	return ResolveSources(homeDir, configuredSources())
+}
+
+// configuredSources is the config's sources section, read on first use.
+// Entries naming an unknown provider are dropped with a warning.
+var configuredSources = sync.OnceValue(func() []config.SourceConfig {
+	var valid []config.SourceConfig
+	for _, c := range config.Load().Sources {
+		if !knownSourceProvider(c.Provider) {
+			logging.NewLogger("aglogs-scan").WithError(fmt.Errorf("unknown provider %q", c.Provider)).Warn("Ignoring transcript source")
+			continue
+		}
+		valid = append(valid, c)
+	}
+	return valid
+})
+
+func knownSourceProvider(name string) bool {
+	for _, b := range builtinSources {
+		if b.Provider == name {
+			return true
+		}
+	}
+	return false
+}
+
+// expandSourcePath makes a configured path absolute, expanding a leading ~.
+func expandSourcePath(homeDir, path string) string {
+	if path == "" {
+		return ""
+	}
+	if path == "~" {
+		return homeDir
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		path = filepath.Join(homeDir, rest)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return filepath.Clean(path)
+}
+
+// sourceProviderForPath returns the provider of the configured (not
+// built-in) source holding path, or "" when none does. Built-in roots are
+// recognized by ProviderForPath's own rules.
+func sourceProviderForPath(path string) string {
+	cfg := configuredSources()
+	if len(cfg) == 0 {
+		return ""
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	for _, src := range ResolveSources(homeDir, cfg) {
+		if !src.Builtin && src.contains(path) {
+			return src.Provider
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/grovetools/agentlogs/config"
+)
+
+func TestResolveSources(t *testing.T) {
+	home := "/home/u"
+	off := false
+	got := ResolveSources(home, []config.SourceConfig{
+		{Provider: "codex", Enabled: &off},                     // built-in root turned off
+		{Provider: "claude", Path: "~/synced/laptop/claude"},   // added
+		{Provider: "claude", Path: "/home/u/.claude/projects"}, // the built-in root again
+		{Provider: "pi", Path: "/mnt/nfs/pi", Enabled: &off},   // kept in the config but off
+		{Provider: "cursor", Path: "/mnt/nfs/cursor"},          // unknown provider
+		{Provider: "opencode", Path: "/data/opencode/storage/../storage"},
+	})
+	want := []Source{
+		{Provider: "claude", Path: "/home/u/.claude/projects", Builtin: true},
+		{Provider: "pi", Path: "/home/u/.pi/agent/sessions", Builtin: true},
+		{Provider: "opencode", Path: "/home/u/.local/share/opencode/storage", Builtin: true},
+		{Provider: "claude", Path: "/home/u/synced/laptop/claude"},
+		{Provider: "opencode", Path: "/data/opencode/storage"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveSources =\n%+v\nwant\n%+v", got, want)
+	}
+	if !got[3].contains("/home/u/synced/laptop/claude/-repo/s1.jsonl") || got[3].contains("/home/u/synced/laptop/claude-old/s1.jsonl") {
+		t.Error("contains misjudges paths under the source")
+	}
+}
+
+func TestScanReadsConfiguredSources(t *testing.T) {
+	setupScanHome(t)
+
+	// A Codex root synced from another machine, outside any .codex directory.
+	root := filepath.Join(t.TempDir(), "box2", "codex-sessions")
+	dayDir := filepath.Join(root, "2026", "01", "02")
+	if err := os.MkdirAll(dayDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	lines := `{"type":"session_meta","payload":{"id":"remote-codex","timestamp":"2026-01-02T00:00:00Z","cwd":"/tmp/proj"}}` + "\n"
+	if err := os.WriteFile(filepath.Join(dayDir, "rollout-remote-codex.jsonl"), []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	off := false
+	scanner := &Scanner{opts: ScanOptions{Sources: []config.SourceConfig{
+		{Provider: "codex", Path: root},
+		{Provider: "claude", Enabled: &off},
+	}}}
+	sessions, err := scanner.Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := ""
+	providers := make(map[string]string)
+	for _, s := range sessions {
+		providers[s.SessionID] = s.Provider
+		if filepath.Dir(s.LogFilePath) == dayDir {
+			remote = s.Provider
+		}
+	}
+	if remote != "codex" {
+		t.Errorf("synced Codex transcript provider = %q, want codex (sessions %v)", remote, providers)
+	}
+	if _, ok := providers["sess-local"]; ok {
+		t.Error("the disabled Claude root was still scanned")
+	}
+}
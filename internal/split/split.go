@@ -0,0 +1,165 @@
+// Package split slices a JSONL transcript into derived transcript files,
+// either at plan-job boundaries or wherever the conversation went quiet for
+// longer than a gap. Slices are raw line ranges of the original file, so each
+// derived file is still a valid transcript for its provider and can be read
+// with every aglogs command that accepts a log file path.
+package split
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// Segment is a half-open range [Start, End) of transcript lines.
+type Segment struct {
+	Name  string
+	Start int
+	End   int
+}
+
+// ReadLines reads every line of a JSONL file, compressed or not, keeping
+// empty lines so that line indexes match the ones recorded in
+// session.JobInfo.LineIndex.
+func ReadLines(path string) ([][]byte, error) {
+	f, err := transcript.OpenTranscript(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := transcript.NewLineScanner(f)
+	var lines [][]byte
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return lines, nil
+}
+
+// ByJobs returns one segment per job, running from the job's first line to
+// the next job's first line. Lines before the first job become a "preamble"
+// segment. A job starting past the last line means the jobs were recorded
+// against another copy of the transcript, and is an error.
+func ByJobs(jobs []session.JobInfo, totalLines int) ([]Segment, error) {
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("session has no plan jobs to split on; try --by gap=30m")
+	}
+	for _, job := range jobs {
+		if job.LineIndex < 0 || job.LineIndex >= totalLines {
+			return nil, fmt.Errorf("job %s starts at line %d, but the transcript has %d lines", job.Job, job.LineIndex+1, totalLines)
+		}
+	}
+	var segs []Segment
+	if first := jobs[0].LineIndex; first > 0 {
+		segs = append(segs, Segment{Name: "preamble", Start: 0, End: first})
+	}
+	for i, job := range jobs {
+		end := totalLines
+		if i+1 < len(jobs) {
+			end = jobs[i+1].LineIndex
+		}
+		if end <= job.LineIndex {
+			continue
+		}
+		segs = append(segs, Segment{Name: strings.TrimSuffix(job.Job, filepath.Ext(job.Job)), Start: job.LineIndex, End: end})
+	}
+	return segs, nil
+}
+
+// ByGap starts a new segment whenever consecutive timestamped lines are more
+// than gap apart. Lines without a timestamp stay with the current segment.
+func ByGap(lines [][]byte, gap time.Duration) []Segment {
+	var segs []Segment
+	start := 0
+	var prev time.Time
+	for i, line := range lines {
+		ts := lineTimestamp(line)
+		if ts.IsZero() {
+			continue
+		}
+		if !prev.IsZero() && ts.Sub(prev) > gap && i > start {
+			segs = append(segs, Segment{Start: start, End: i})
+			start = i
+		}
+		prev = ts
+	}
+	if start < len(lines) {
+		segs = append(segs, Segment{Start: start, End: len(lines)})
+	}
+	for i := range segs {
+		segs[i].Name = fmt.Sprintf("part-%02d", i+1)
+	}
+	return segs
+}
+
+// lineTimestamp returns a line's top-level RFC 3339 "timestamp", as written
+// by Claude, Codex, and pi, or the zero time.
+func lineTimestamp(line []byte) time.Time {
+	var v struct {
+		Timestamp time.Time `json:"timestamp"`
+	}
+	if len(line) == 0 || json.Unmarshal(line, &v) != nil {
+		return time.Time{}
+	}
+	return v.Timestamp
+}
+
+// HeaderLine returns the session header line that must lead every derived
+// file for providers whose readers depend on it (Codex session_meta, pi
+// session), or nil.
+func HeaderLine(lines [][]byte, provider string) []byte {
+	if len(lines) == 0 {
+		return nil
+	}
+	var v struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(lines[0], &v) != nil {
+		return nil
+	}
+	if (provider == "codex" && v.Type == "session_meta") || (provider == "pi" && v.Type == "session") {
+		return lines[0]
+	}
+	return nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Write materializes each segment as <dir>/<prefix>.<NN>-<name>.jsonl and
+// returns the paths written. header, when non-nil, is prepended to every
+// segment that does not already start with it.
+func Write(dir, prefix string, lines [][]byte, segs []Segment, header []byte) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	var written []string
+	for i, seg := range segs {
+		name := fmt.Sprintf("%s.%02d-%s.jsonl", prefix, i+1, unsafeNameChars.ReplaceAllString(seg.Name, "_"))
+		path := filepath.Join(dir, name)
+
+		var b strings.Builder
+		if header != nil && seg.Start > 0 {
+			b.Write(header)
+			b.WriteByte('\n')
+		}
+		for _, line := range lines[seg.Start:seg.End] {
+			b.Write(line)
+			b.WriteByte('\n')
+		}
+		if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
@@ -0,0 +1,111 @@
+package split
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func line(ts string) []byte {
+	return []byte(`{"type":"user","timestamp":"` + ts + `"}`)
+}
+
+func TestByGap(t *testing.T) {
+	lines := [][]byte{
+		line("2026-01-01T09:00:00Z"),
+		line("2026-01-01T09:05:00Z"),
+		[]byte(`{"type":"summary"}`), // no timestamp: stays with its segment
+		line("2026-01-01T11:00:00Z"),
+		line("2026-01-01T11:01:00Z"),
+	}
+	segs := ByGap(lines, 30*time.Minute)
+	if len(segs) != 2 {
+		t.Fatalf("got %d segments, want 2: %+v", len(segs), segs)
+	}
+	if segs[0].Start != 0 || segs[0].End != 3 || segs[1].Start != 3 || segs[1].End != 5 {
+		t.Errorf("segments = %+v", segs)
+	}
+	if segs[1].Name != "part-02" {
+		t.Errorf("name = %q", segs[1].Name)
+	}
+}
+
+func TestByJobs(t *testing.T) {
+	jobs := []session.JobInfo{
+		{Plan: "p", Job: "01-spec.md", LineIndex: 2},
+		{Plan: "p", Job: "02-impl.md", LineIndex: 10},
+	}
+	segs, err := ByJobs(jobs, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Segment{{"preamble", 0, 2}, {"01-spec", 2, 10}, {"02-impl", 10, 15}}
+	if len(segs) != len(want) {
+		t.Fatalf("segments = %+v, want %+v", segs, want)
+	}
+	for i := range want {
+		if segs[i] != want[i] {
+			t.Errorf("segment %d = %+v, want %+v", i, segs[i], want[i])
+		}
+	}
+	if _, err := ByJobs(nil, 5); err == nil {
+		t.Error("expected error without jobs")
+	}
+	if _, err := ByJobs(jobs, 10); err == nil {
+		t.Error("expected error for a job starting past the last line")
+	}
+}
+
+func TestReadLinesCompressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(f)
+	zw.Write([]byte("{\"a\":1}\n\n{\"b\":2}\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	lines, err := ReadLines(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 3 || string(lines[2]) != `{"b":2}` {
+		t.Errorf("lines = %q, want the 3 decompressed lines", lines)
+	}
+}
+
+func TestWritePrependsHeader(t *testing.T) {
+	dir := t.TempDir()
+	lines := [][]byte{
+		[]byte(`{"type":"session_meta"}`),
+		line("2026-01-01T09:00:00Z"),
+		line("2026-01-01T12:00:00Z"),
+	}
+	header := HeaderLine(lines, "codex")
+	if header == nil {
+		t.Fatal("expected codex session_meta header")
+	}
+	paths, err := Write(dir, "rollout", lines, ByGap(lines, time.Hour), header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || filepath.Base(paths[1]) != "rollout.02-part-02.jsonl" {
+		t.Fatalf("paths = %v", paths)
+	}
+	data, err := os.ReadFile(paths[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Split(strings.TrimSpace(string(data)), "\n"); len(got) != 2 || !strings.Contains(got[0], "session_meta") {
+		t.Errorf("second slice = %q, want header + one line", got)
+	}
+}
@@ -0,0 +1,185 @@
+// Package spool decouples a producer of lines from a slow reader: lines
+// are handed to a background writer, and while the reader falls behind
+// they queue in memory and then in a bounded file on disk, so the
+// producer never blocks. Lines are dropped only once the disk buffer is
+// full.
+package spool
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	// DefaultMaxBytes bounds the disk buffer.
+	DefaultMaxBytes = 256 << 20
+	// memLines is how many lines queue in memory before spilling to disk.
+	memLines = 1024
+)
+
+// Stats reports what happened to the lines written.
+type Stats struct {
+	Written  int // lines passed on to the reader
+	Buffered int // lines that went through the disk buffer
+	Dropped  int // lines lost to a full disk buffer or a failed write
+}
+
+// Writer passes lines on to an io.Writer from a background goroutine.
+// WriteLine never blocks on the underlying writer; Close waits for
+// everything queued to be written.
+type Writer struct {
+	w        io.Writer
+	maxBytes int64
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	mem    [][]byte
+	file   *os.File
+	spill  []int // lengths of the lines in file not yet read
+	rdOff  int64
+	wrOff  int64
+	closed bool
+	err    error
+	stats  Stats
+	done   chan struct{}
+}
+
+// New returns a Writer passing lines on to w, buffering at most maxBytes
+// on disk (DefaultMaxBytes when maxBytes is not positive).
+func New(w io.Writer, maxBytes int64) *Writer {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	s := &Writer{w: w, maxBytes: maxBytes, done: make(chan struct{})}
+	s.cond = sync.NewCond(&s.mu)
+	go s.run()
+	return s
+}
+
+// Write queues p as one line, so a Writer can back a json.Encoder. It
+// never fails; lines that cannot be buffered are counted as dropped.
+func (s *Writer) Write(p []byte) (int, error) {
+	s.WriteLine(p)
+	return len(p), nil
+}
+
+// WriteLine queues line, which should end in a newline. The line is
+// copied, so the caller may reuse it.
+func (s *Writer) WriteLine(line []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.err != nil {
+		s.stats.Dropped++
+		return
+	}
+	// Once lines spill, later ones follow them to disk to keep the order.
+	if len(s.spill) == 0 && len(s.mem) < memLines {
+		s.mem = append(s.mem, append([]byte(nil), line...))
+		s.cond.Signal()
+		return
+	}
+	if err := s.spillLine(line); err != nil {
+		s.stats.Dropped++
+		return
+	}
+	s.stats.Buffered++
+	s.cond.Signal()
+}
+
+// spillLine appends line to the disk buffer, creating it on first use.
+func (s *Writer) spillLine(line []byte) error {
+	if s.wrOff+int64(len(line)) > s.maxBytes {
+		return fmt.Errorf("disk buffer full")
+	}
+	if s.file == nil {
+		f, err := os.CreateTemp("", "aglogs-stream-*")
+		if err != nil {
+			return err
+		}
+		s.file = f
+	}
+	if _, err := s.file.WriteAt(line, s.wrOff); err != nil {
+		return err
+	}
+	s.wrOff += int64(len(line))
+	s.spill = append(s.spill, len(line))
+	return nil
+}
+
+// next returns the oldest queued line, waiting for one, or false once
+// the Writer is closed and drained.
+func (s *Writer) next() ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.mem) == 0 && len(s.spill) == 0 {
+		if s.closed {
+			return nil, false
+		}
+		s.cond.Wait()
+	}
+	if len(s.mem) > 0 {
+		line := s.mem[0]
+		s.mem[0] = nil
+		s.mem = s.mem[1:]
+		return line, true
+	}
+	line := make([]byte, s.spill[0])
+	if _, err := s.file.ReadAt(line, s.rdOff); err != nil {
+		s.err = err
+		s.stats.Dropped += len(s.spill)
+		s.spill = nil
+		return nil, false
+	}
+	s.rdOff += int64(len(line))
+	s.spill = s.spill[1:]
+	if len(s.spill) == 0 {
+		// Drained: reuse the file from the start.
+		s.rdOff, s.wrOff = 0, 0
+		_ = s.file.Truncate(0)
+	}
+	return line, true
+}
+
+func (s *Writer) run() {
+	defer close(s.done)
+	for {
+		line, ok := s.next()
+		if !ok {
+			return
+		}
+		_, err := s.w.Write(line)
+		s.mu.Lock()
+		if err != nil {
+			// The reader is gone; nothing more can be delivered.
+			s.err = err
+			s.stats.Dropped += 1 + len(s.mem) + len(s.spill)
+			s.mem, s.spill = nil, nil
+			s.mu.Unlock()
+			return
+		}
+		s.stats.Written++
+		s.mu.Unlock()
+	}
+}
+
+// Close waits for the queued lines to be written, removes the disk
+// buffer, and returns what happened to the lines along with the first
+// write error, if any.
+func (s *Writer) Close() (Stats, error) {
+	s.mu.Lock()
+	s.closed = true
+	s.cond.Signal()
+	s.mu.Unlock()
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil {
+		_ = s.file.Close()
+		_ = os.Remove(s.file.Name())
+		s.file = nil
+	}
+	return s.stats, s.err
+}
@@ -0,0 +1,83 @@
+package spool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// gatedWriter blocks writes until released, standing in for a reader
+// that has fallen behind.
+type gatedWriter struct {
+	gate chan struct{}
+	mu   sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (g *gatedWriter) Write(p []byte) (int, error) {
+	<-g.gate
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.buf.Write(p)
+}
+
+func TestWriterSpillsInOrder(t *testing.T) {
+	g := &gatedWriter{gate: make(chan struct{})}
+	s := New(g, 0)
+	n := memLines * 3
+	var want strings.Builder
+	for i := range n {
+		line := fmt.Sprintf("line %d\n", i)
+		want.WriteString(line)
+		s.WriteLine([]byte(line))
+	}
+	close(g.gate)
+	stats, err := s.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if g.buf.String() != want.String() {
+		t.Error("lines were reordered or lost")
+	}
+	if stats.Written != n || stats.Dropped != 0 || stats.Buffered < n-memLines-1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestWriterDropsWhenDiskBufferFull(t *testing.T) {
+	g := &gatedWriter{gate: make(chan struct{})}
+	s := New(g, 100)
+	n := memLines + 50
+	for range n {
+		s.WriteLine([]byte("0123456789\n")) // 11 bytes: 9 fit on disk
+	}
+	close(g.gate)
+	stats, err := s.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if stats.Written+stats.Dropped != n || stats.Dropped == 0 || stats.Buffered > 10 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestWriterReportsWriteError(t *testing.T) {
+	s := New(failingWriter{}, 0)
+	for range 3 {
+		s.WriteLine([]byte("x\n"))
+	}
+	stats, err := s.Close()
+	if err == nil {
+		t.Fatal("Close returned no error")
+	}
+	if stats.Written != 0 || stats.Dropped != 3 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
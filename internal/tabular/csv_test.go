@@ -0,0 +1,20 @@
+package tabular
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	tbl := &Table{Name: "t", Columns: []Column{{"id", String}, {"n", Int}, {"at", Time}}}
+	tbl.Append("a,b", 3, time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("x", 3600)))
+	tbl.Append(nil, nil, time.Time{})
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, tbl); err != nil {
+		t.Fatal(err)
+	}
+	if want := "id,n,at\n\"a,b\",3,2026-01-02T02:04:05Z\n,,\n"; buf.String() != want {
+		t.Errorf("csv =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
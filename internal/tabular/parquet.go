@@ -0,0 +1,333 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// WriteParquet writes t as a Parquet file: one row group, one uncompressed
+// PLAIN-encoded data page per column, every column OPTIONAL. Strings are
+// UTF8 byte arrays and times TIMESTAMP_MILLIS int64s. That is the simplest
+// layout every reader (Arrow, DuckDB, Spark) accepts; tables exported here
+// are small enough not to need compression or dictionaries.
+func WriteParquet(w io.Writer, t *Table) error {
+	cw := &countWriter{w: w}
+	if _, err := io.WriteString(cw, parquetMagic); err != nil {
+		return err
+	}
+
+	var chunks []columnChunk
+	if len(t.Rows) > 0 {
+		for i, col := range t.Columns {
+			offset := cw.n
+			page := encodeColumnPage(t, i)
+			header := encodePageHeader(len(t.Rows), len(page))
+			if _, err := cw.Write(header); err != nil {
+				return err
+			}
+			if _, err := cw.Write(page); err != nil {
+				return err
+			}
+			chunks = append(chunks, columnChunk{
+				name:   col.Name,
+				typ:    col.Type,
+				offset: offset,
+				size:   int64(len(header) + len(page)),
+			})
+		}
+	}
+
+	footer := encodeFileMetaData(t, chunks)
+	if _, err := cw.Write(footer); err != nil {
+		return err
+	}
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(footer)))
+	if _, err := cw.Write(n[:]); err != nil {
+		return err
+	}
+	_, err := io.WriteString(cw, parquetMagic)
+	return err
+}
+
+const parquetMagic = "PAR1"
+
+// Parquet enum values (parquet.thrift).
+const (
+	ptBoolean   = 0
+	ptInt64     = 2
+	ptDouble    = 5
+	ptByteArray = 6
+
+	repOptional = 1
+
+	ctUTF8            = 0
+	ctTimestampMillis = 9
+
+	encPlain = 0
+	encRLE   = 3
+
+	codecUncompressed = 0
+	pageTypeData      = 0
+)
+
+type columnChunk struct {
+	name         string
+	typ          Type
+	offset, size int64
+}
+
+func physicalType(t Type) int32 {
+	switch t {
+	case Int, Time:
+		return ptInt64
+	case Float:
+		return ptDouble
+	case Bool:
+		return ptBoolean
+	default:
+		return ptByteArray
+	}
+}
+
+// encodeColumnPage encodes column i of t as a v1 data page body:
+// definition levels (1 = present), then the present values, PLAIN.
+func encodeColumnPage(t *Table, i int) []byte {
+	defined := make([]bool, len(t.Rows))
+	var values bytes.Buffer
+	var bits []bool
+	for r, row := range t.Rows {
+		v, ok := value(t, row, i)
+		defined[r] = ok
+		if !ok {
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			binary.Write(&values, binary.LittleEndian, uint32(len(v)))
+			values.WriteString(v)
+		case int64:
+			binary.Write(&values, binary.LittleEndian, v)
+		case float64:
+			binary.Write(&values, binary.LittleEndian, math.Float64bits(v))
+		case bool:
+			bits = append(bits, v)
+		case time.Time:
+			binary.Write(&values, binary.LittleEndian, v.UnixMilli())
+		}
+	}
+	if t.Columns[i].Type == Bool {
+		// Booleans are bit-packed, least significant bit first.
+		packed := make([]byte, (len(bits)+7)/8)
+		for j, b := range bits {
+			if b {
+				packed[j/8] |= 1 << (j % 8)
+			}
+		}
+		values.Write(packed)
+	}
+
+	levels := encodeLevels(defined)
+	var page bytes.Buffer
+	binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+	page.Write(levels)
+	page.Write(values.Bytes())
+	return page.Bytes()
+}
+
+// encodeLevels encodes 0/1 definition levels in the RLE/bit-packed hybrid
+// encoding, as RLE runs only (bit width 1, so one byte per run value).
+func encodeLevels(defined []bool) []byte {
+	var buf bytes.Buffer
+	for start := 0; start < len(defined); {
+		end := start
+		for end < len(defined) && defined[end] == defined[start] {
+			end++
+		}
+		putUvarint(&buf, uint64(end-start)<<1)
+		if defined[start] {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+		start = end
+	}
+	return buf.Bytes()
+}
+
+func encodePageHeader(numValues, pageSize int) []byte {
+	var tw thriftWriter
+	tw.i32(1, pageTypeData)
+	tw.i32(2, int32(pageSize)) // uncompressed_page_size
+	tw.i32(3, int32(pageSize)) // compressed_page_size
+	tw.beginStruct(5)          // data_page_header
+	tw.i32(1, int32(numValues))
+	tw.i32(2, encPlain)
+	tw.i32(3, encRLE) // definition_level_encoding
+	tw.i32(4, encRLE) // repetition_level_encoding
+	tw.endStruct()
+	tw.stop()
+	return tw.buf.Bytes()
+}
+
+func encodeFileMetaData(t *Table, chunks []columnChunk) []byte {
+	var tw thriftWriter
+	tw.i32(1, 1) // version
+
+	tw.list(2, thriftStruct, len(t.Columns)+1) // schema
+	tw.beginElem()
+	tw.binary(4, "schema")
+	tw.i32(5, int32(len(t.Columns)))
+	tw.endStruct()
+	for _, c := range t.Columns {
+		tw.beginElem()
+		tw.i32(1, physicalType(c.Type))
+		tw.i32(3, repOptional)
+		tw.binary(4, c.Name)
+		switch c.Type {
+		case String:
+			tw.i32(6, ctUTF8)
+		case Time:
+			tw.i32(6, ctTimestampMillis)
+		}
+		tw.endStruct()
+	}
+
+	tw.i64(3, int64(len(t.Rows))) // num_rows
+
+	groups := 0
+	if len(chunks) > 0 {
+		groups = 1
+	}
+	tw.list(4, thriftStruct, groups) // row_groups
+	if groups == 1 {
+		var total int64
+		tw.beginElem()
+		tw.list(1, thriftStruct, len(chunks)) // columns
+		for _, c := range chunks {
+			total += c.size
+			tw.beginElem()
+			tw.i64(2, c.offset) // file_offset
+			tw.beginStruct(3)   // meta_data
+			tw.i32(1, physicalType(c.typ))
+			tw.list(2, thriftI32, 2)
+			tw.elemI32(encPlain)
+			tw.elemI32(encRLE)
+			tw.list(3, thriftBinary, 1)
+			tw.elemBinary(c.name)
+			tw.i32(4, codecUncompressed)
+			tw.i64(5, int64(len(t.Rows))) // num_values
+			tw.i64(6, c.size)             // total_uncompressed_size
+			tw.i64(7, c.size)             // total_compressed_size
+			tw.i64(9, c.offset)           // data_page_offset
+			tw.endStruct()
+			tw.endStruct()
+		}
+		tw.i64(2, total)              // total_byte_size
+		tw.i64(3, int64(len(t.Rows))) // num_rows
+		tw.endStruct()
+	}
+
+	tw.binary(6, "aglogs") // created_by
+	tw.stop()
+	return tw.buf.Bytes()
+}
+
+// thriftWriter encodes structs in the Thrift compact protocol, which
+// Parquet uses for page headers and the file footer.
+type thriftWriter struct {
+	buf    bytes.Buffer
+	lastID int16
+	stack  []int16
+}
+
+// Compact protocol type ids.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	if delta := id - w.lastID; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		putUvarint(&w.buf, zigzag(int64(id)))
+	}
+	w.lastID = id
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	putUvarint(&w.buf, zigzag(int64(v)))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	putUvarint(&w.buf, zigzag(v))
+}
+
+func (w *thriftWriter) binary(id int16, s string) {
+	w.field(id, thriftBinary)
+	w.elemBinary(s)
+}
+
+func (w *thriftWriter) list(id int16, elemType byte, n int) {
+	w.field(id, thriftList)
+	if n < 15 {
+		w.buf.WriteByte(byte(n)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elemType)
+	putUvarint(&w.buf, uint64(n))
+}
+
+func (w *thriftWriter) elemI32(v int32) { putUvarint(&w.buf, zigzag(int64(v))) }
+
+func (w *thriftWriter) elemBinary(s string) {
+	putUvarint(&w.buf, uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+// beginStruct starts a struct-valued field; beginElem a struct list element.
+func (w *thriftWriter) beginStruct(id int16) {
+	w.field(id, thriftStruct)
+	w.beginElem()
+}
+
+func (w *thriftWriter) beginElem() {
+	w.stack = append(w.stack, w.lastID)
+	w.lastID = 0
+}
+
+func (w *thriftWriter) endStruct() {
+	w.stop()
+	w.lastID = w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+}
+
+func (w *thriftWriter) stop() { w.buf.WriteByte(0) }
+
+func zigzag(v int64) uint64 { return uint64(v<<1) ^ uint64(v>>63) }
+
+func putUvarint(buf *bytes.Buffer, v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
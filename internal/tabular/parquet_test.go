@@ -0,0 +1,196 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func sampleTable() *Table {
+	t := &Table{Name: "sample", Columns: []Column{
+		{"id", String}, {"n", Int}, {"cost", Float}, {"ok", Bool}, {"at", Time},
+	}}
+	at := time.Date(2026, 3, 4, 5, 6, 7, 8_000_000, time.UTC)
+	t.Append("a", int64(1), 0.5, true, at)
+	t.Append("", 2, nil, false, time.Time{})
+	t.Append(nil, nil, 1.25, nil, at.Add(time.Second))
+	for i := 0; i < 20; i++ { // long runs and lists of more than 15
+		t.Append(fmt.Sprint("row", i), int64(i), float64(i), i%3 == 0, at)
+	}
+	return t
+}
+
+func TestWriteParquetRoundTrip(t *testing.T) {
+	tbl := sampleTable()
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, tbl); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if string(data[:4]) != "PAR1" || string(data[len(data)-4:]) != "PAR1" {
+		t.Fatal("missing magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	meta := readStruct(t, bytes.NewReader(data[len(data)-8-footerLen:len(data)-8]))
+
+	if meta[3].(int64) != int64(len(tbl.Rows)) {
+		t.Errorf("num_rows = %v", meta[3])
+	}
+	schema := meta[2].([]any)
+	if len(schema) != len(tbl.Columns)+1 || schema[0].(map[int16]any)[5].(int64) != int64(len(tbl.Columns)) {
+		t.Fatalf("schema = %v", schema)
+	}
+	columns := meta[4].([]any)[0].(map[int16]any)[1].([]any)
+	for i, col := range tbl.Columns {
+		if name := string(schema[i+1].(map[int16]any)[4].([]byte)); name != col.Name {
+			t.Errorf("schema column %d = %q, want %q", i, name, col.Name)
+		}
+		md := columns[i].(map[int16]any)[3].(map[int16]any)
+		chunk := data[md[9].(int64):]
+		r := bytes.NewReader(chunk)
+		header := readStruct(t, r)
+		page := make([]byte, header[3].(int64))
+		r.Read(page)
+		if got := int64(len(chunk) - r.Len()); got != md[6].(int64) {
+			t.Errorf("%s: chunk size %d, metadata says %d", col.Name, got, md[6])
+		}
+		got := decodePage(t, page, col.Type, len(tbl.Rows))
+		for r, row := range tbl.Rows {
+			want, ok := value(tbl, row, i)
+			if !ok {
+				want = nil
+			}
+			if tm, isTime := want.(time.Time); isTime {
+				want = tm.UnixMilli()
+			}
+			if !reflect.DeepEqual(got[r], want) {
+				t.Errorf("%s row %d = %v, want %v", col.Name, r, got[r], want)
+			}
+		}
+	}
+}
+
+func TestWriteParquetEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, &Table{Name: "empty", Columns: []Column{{"id", String}}}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	meta := readStruct(t, bytes.NewReader(data[len(data)-8-footerLen:len(data)-8]))
+	if meta[3].(int64) != 0 || len(meta[4].([]any)) != 0 {
+		t.Errorf("meta = %v", meta)
+	}
+}
+
+// decodePage decodes a data page written by encodeColumnPage.
+func decodePage(t *testing.T, page []byte, typ Type, n int) []any {
+	t.Helper()
+	levelsLen := binary.LittleEndian.Uint32(page)
+	levels := bytes.NewReader(page[4 : 4+levelsLen])
+	var defined []bool
+	for levels.Len() > 0 {
+		h, _ := binary.ReadUvarint(levels)
+		if h&1 != 0 {
+			t.Fatal("bit-packed run not expected")
+		}
+		v, _ := levels.ReadByte()
+		for i := uint64(0); i < h>>1; i++ {
+			defined = append(defined, v == 1)
+		}
+	}
+	if len(defined) != n {
+		t.Fatalf("%d levels, want %d", len(defined), n)
+	}
+	values := bytes.NewReader(page[4+levelsLen:])
+	out := make([]any, n)
+	bit := 0
+	var boolBytes []byte
+	if typ == Bool {
+		boolBytes = page[4+levelsLen:]
+	}
+	for i, ok := range defined {
+		if !ok {
+			continue
+		}
+		switch typ {
+		case String:
+			var l uint32
+			binary.Read(values, binary.LittleEndian, &l)
+			s := make([]byte, l)
+			values.Read(s)
+			out[i] = string(s)
+		case Int, Time:
+			var v int64
+			binary.Read(values, binary.LittleEndian, &v)
+			out[i] = v
+		case Float:
+			var v uint64
+			binary.Read(values, binary.LittleEndian, &v)
+			out[i] = math.Float64frombits(v)
+		case Bool:
+			out[i] = boolBytes[bit/8]&(1<<(bit%8)) != 0
+			bit++
+		}
+	}
+	return out
+}
+
+// readStruct decodes a Thrift compact struct into field id -> value:
+// integers as int64, binaries as []byte, lists as []any, structs as maps.
+func readStruct(t *testing.T, r *bytes.Reader) map[int16]any {
+	t.Helper()
+	m := make(map[int16]any)
+	var last int16
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b == 0 {
+			return m
+		}
+		typ := b & 0x0f
+		if delta := int16(b >> 4); delta != 0 {
+			last += delta
+		} else {
+			id, _ := binary.ReadUvarint(r)
+			last = int16(unzigzag(id))
+		}
+		m[last] = readValue(t, r, typ)
+	}
+}
+
+func readValue(t *testing.T, r *bytes.Reader, typ byte) any {
+	switch typ {
+	case thriftI32, thriftI64:
+		v, _ := binary.ReadUvarint(r)
+		return unzigzag(v)
+	case thriftBinary:
+		l, _ := binary.ReadUvarint(r)
+		b := make([]byte, l)
+		r.Read(b)
+		return b
+	case thriftList:
+		h, _ := r.ReadByte()
+		n := uint64(h >> 4)
+		if n == 15 {
+			n, _ = binary.ReadUvarint(r)
+		}
+		list := []any{}
+		for i := uint64(0); i < n; i++ {
+			list = append(list, readValue(t, r, h&0x0f))
+		}
+		return list
+	case thriftStruct:
+		return readStruct(t, r)
+	}
+	t.Fatalf("unexpected thrift type %d", typ)
+	return nil
+}
+
+func unzigzag(v uint64) int64 { return int64(v>>1) ^ -int64(v&1) }
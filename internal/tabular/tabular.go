@@ -0,0 +1,107 @@
+// Package tabular writes flat tables of session data for analysis tools
+// (pandas, DuckDB, spreadsheets) as CSV or Parquet.
+package tabular
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Type is a column's value type.
+type Type int
+
+const (
+	String Type = iota
+	Int         // int64
+	Float       // float64
+	Bool
+	Time // time.Time, stored as UTC milliseconds
+)
+
+// Column describes one column of a Table.
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Table is a named set of rows. Each row holds one value per column, of the
+// column's Go type (string, int64, float64, bool, time.Time), or nil for a
+// missing value. A zero time.Time is missing too.
+type Table struct {
+	Name    string
+	Columns []Column
+	Rows    [][]any
+}
+
+// Append adds a row, which must have a value for every column.
+func (t *Table) Append(row ...any) {
+	if len(row) != len(t.Columns) {
+		panic(fmt.Sprintf("tabular: row of %d values for %d columns of %s", len(row), len(t.Columns), t.Name))
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// Format is an output file format.
+type Format string
+
+const (
+	CSV     Format = "csv"
+	Parquet Format = "parquet"
+)
+
+// FormatForPath picks the format from a file name's extension.
+func FormatForPath(path string) (Format, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return CSV, nil
+	case ".parquet":
+		return Parquet, nil
+	default:
+		return "", fmt.Errorf("cannot tell the format of %s: use a .csv or .parquet file name", path)
+	}
+}
+
+// Write writes t to w in format f.
+func Write(w io.Writer, t *Table, f Format) error {
+	switch f {
+	case CSV:
+		return WriteCSV(w, t)
+	case Parquet:
+		return WriteParquet(w, t)
+	default:
+		return fmt.Errorf("unknown format %q", f)
+	}
+}
+
+// value returns row[i] normalized for writing, and whether it is present.
+func value(t *Table, row []any, i int) (any, bool) {
+	v := row[i]
+	if v == nil {
+		return nil, false
+	}
+	switch t.Columns[i].Type {
+	case String:
+		return v.(string), true
+	case Int:
+		switch n := v.(type) {
+		case int:
+			return int64(n), true
+		case int64:
+			return n, true
+		}
+	case Float:
+		return v.(float64), true
+	case Bool:
+		return v.(bool), true
+	case Time:
+		tm := v.(time.Time)
+		if tm.IsZero() {
+			return nil, false
+		}
+		return tm.UTC(), true
+	}
+	panic(fmt.Sprintf("tabular: %T value in %s column %s", v, t.Name, t.Columns[i].Name))
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+// matchesFilter reports whether a session matches a filter query. The query
+// is a space-separated list of terms that must all match; "project:<x>" and
+// "plan:<x>" narrow a term to that field, bare terms match the project, plan,
+// session ID, or provider. Matching is case-insensitive substring.
+func matchesFilter(s session.SessionInfo, query string) bool {
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		field, value, scoped := strings.Cut(term, ":")
+		if !scoped {
+			value = term
+		}
+		switch {
+		case scoped && field == "project":
+			if !containsFold(s.ProjectName, value) {
+				return false
+			}
+		case scoped && field == "plan":
+			if !sessionHasPlan(s, value) {
+				return false
+			}
+		default:
+			if !containsFold(s.ProjectName, value) && !sessionHasPlan(s, value) &&
+				!containsFold(s.SessionID, value) && !containsFold(s.Provider, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func sessionHasPlan(s session.SessionInfo, value string) bool {
+	for _, job := range s.Jobs {
+		if containsFold(job.Plan, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold expects value to be lower-cased already.
+func containsFold(s, value string) bool {
+	return strings.Contains(strings.ToLower(s), value)
+}
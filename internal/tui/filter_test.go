@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/grovetools/agentlogs/internal/session"
+)
+
+func TestMatchesFilter(t *testing.T) {
+	s := session.SessionInfo{
+		SessionID:   "abc123",
+		ProjectName: "agentlogs",
+		Provider:    "claude",
+		Jobs:        []session.JobInfo{{Plan: "tui-browser", Job: "01-spec.md"}},
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"agent", true},
+		{"TUI", true},
+		{"abc", true},
+		{"claude", true},
+		{"project:agentlogs plan:tui", true},
+		{"project:tui", false},
+		{"plan:agentlogs", false},
+		{"agentlogs codex", false},
+	}
+	for _, tt := range tests {
+		if got := matchesFilter(s, tt.query); got != tt.want {
+			t.Errorf("matchesFilter(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
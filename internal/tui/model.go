@@ -0,0 +1,486 @@
+// Package tui implements the full-screen session browser behind `aglogs tui`:
+// a filterable session list on the left, the selected transcript on the right,
+// and live tailing of the selected session while it is still being written.
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/grovetools/core/tui/theme"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/display"
+)
+
+// pollInterval is how often the selected transcript is checked for growth.
+const pollInterval = 2 * time.Second
+
+type focusPane int
+
+const (
+	focusList focusPane = iota
+	focusViewer
+)
+
+type sessionsLoadedMsg struct {
+	sessions []session.SessionInfo
+	err      error
+}
+
+type transcriptLoadedMsg struct {
+	path   string
+	detail string
+	// subagents is whether sub-agent transcripts were nested in.
+	subagents bool
+	content   string
+	size      int64
+	modTime   time.Time
+	err       error
+}
+
+type tickMsg time.Time
+
+// Model is the Bubble Tea model for the session browser.
+type Model struct {
+	sessions []session.SessionInfo
+	visible  []int // indexes into sessions that match the filter
+	cursor   int   // index into visible
+
+	filter    textinput.Model
+	filtering bool
+
+	viewer      viewport.Model
+	focus       focusPane
+	detailLevel string
+	follow      bool
+	// subagents nests Claude Task sub-agent transcripts under their calls.
+	subagents bool
+
+	// State of the transcript currently shown in the viewer, used to skip
+	// reloads when the file has not changed.
+	loadedPath    string
+	loadedDetail  string
+	loadedSubs    bool
+	loadedSize    int64
+	loadedModTime time.Time
+	content       string // rendered transcript before wrapping
+	loading       bool
+
+	width, height int
+	err           error
+}
+
+// New returns a browser model. detailLevel is "summary" or "full".
+func New(detailLevel string) Model {
+	filter := textinput.New()
+	filter.Prompt = "/ "
+	filter.Placeholder = "filter (project:x plan:y or free text)"
+
+	if detailLevel == "" {
+		detailLevel = "summary"
+	}
+	return Model{
+		filter:      filter,
+		viewer:      viewport.New(0, 0),
+		detailLevel: detailLevel,
+		follow:      true,
+	}
+}
+
+// Run starts the browser in the alternate screen and blocks until it exits.
+func Run(detailLevel string) error {
+	_, err := tea.NewProgram(New(detailLevel), tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(loadSessions, tick())
+}
+
+func loadSessions() tea.Msg {
+	sessions, err := session.NewScanner().Scan()
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.After(sessions[j].StartedAt)
+	})
+	return sessionsLoadedMsg{sessions: sessions, err: err}
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(pollInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// loadTranscript reads and renders a session transcript at the given detail
+// level, with sub-agent transcripts nested in when subagents is set.
+// Rendering happens off the UI goroutine; wrapping to the viewer width
+// happens when the content is installed.
+func loadTranscript(info session.SessionInfo, detail string, subagents bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := transcriptLoadedMsg{path: info.LogFilePath, detail: detail, subagents: subagents}
+		if st, err := os.Stat(info.LogFilePath); err == nil {
+			msg.size, msg.modTime = st.Size(), st.ModTime()
+		}
+
+		src := provider.SelectSource(&info, nil)
+		entries, err := src.Read(context.Background(), &info, provider.ReadOptions{DetailLevel: detail, EndLine: -1, IncludeSubagents: subagents})
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+
+		var buf bytes.Buffer
+		opts := display.RenderOptions{Style: display.StyleTerminal, DetailLevel: detail}
+		if err := display.RenderUnifiedTranscript(&buf, entries, opts, display.DefaultToolFormatters()); err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.content = buf.String()
+		return msg
+	}
+}
+
+func (m Model) selected() (session.SessionInfo, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return session.SessionInfo{}, false
+	}
+	return m.sessions[m.visible[m.cursor]], true
+}
+
+func (m *Model) applyFilter() {
+	query := m.filter.Value()
+	m.visible = m.visible[:0]
+	for i, s := range m.sessions {
+		if matchesFilter(s, query) {
+			m.visible = append(m.visible, i)
+		}
+	}
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// loadSelected starts loading the selected transcript if it is not the one
+// already shown (or force is set, as for detail-level and sub-agent toggles).
+func (m *Model) loadSelected(force bool) tea.Cmd {
+	s, ok := m.selected()
+	if !ok || s.LogFilePath == "" {
+		return nil
+	}
+	if !force && s.LogFilePath == m.loadedPath && m.detailLevel == m.loadedDetail && m.subagents == m.loadedSubs {
+		return nil
+	}
+	m.loading = true
+	return loadTranscript(s, m.detailLevel, m.subagents)
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
+
+	case sessionsLoadedMsg:
+		m.sessions, m.err = msg.sessions, msg.err
+		m.applyFilter()
+		return m, m.loadSelected(false)
+
+	case transcriptLoadedMsg:
+		s, ok := m.selected()
+		if !ok || s.LogFilePath != msg.path || msg.detail != m.detailLevel || msg.subagents != m.subagents {
+			return m, nil // stale result for a session no longer selected
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.viewer.SetContent(fmt.Sprintf("Failed to read transcript: %v", msg.err))
+			return m, nil
+		}
+		sameSession := m.loadedPath == msg.path
+		atBottom := m.viewer.AtBottom()
+		offset := m.viewer.YOffset
+		m.loadedPath, m.loadedDetail, m.loadedSubs = msg.path, msg.detail, msg.subagents
+		m.loadedSize, m.loadedModTime = msg.size, msg.modTime
+		m.content = msg.content
+		m.viewer.SetContent(wrap(m.content, m.viewer.Width))
+		switch {
+		case m.follow && (!sameSession || atBottom):
+			m.viewer.GotoBottom()
+		case sameSession:
+			m.viewer.SetYOffset(offset)
+		default:
+			m.viewer.GotoTop()
+		}
+		return m, nil
+
+	case tickMsg:
+		cmds := []tea.Cmd{tick()}
+		if !m.loading && m.transcriptChanged() {
+			cmds = append(cmds, m.loadSelected(true))
+		}
+		return m, tea.Batch(cmds...)
+
+	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		return m.updateKeys(msg)
+	}
+	return m, nil
+}
+
+// transcriptChanged reports whether the transcript on screen has grown or
+// been rewritten since it was loaded.
+func (m Model) transcriptChanged() bool {
+	if m.loadedPath == "" {
+		return false
+	}
+	st, err := os.Stat(m.loadedPath)
+	if err != nil {
+		return false
+	}
+	return st.Size() != m.loadedSize || !st.ModTime().Equal(m.loadedModTime)
+}
+
+func (m Model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter":
+		m.filtering = false
+		m.filter.Blur()
+		return m, m.loadSelected(false)
+	case "esc":
+		m.filtering = false
+		m.filter.Blur()
+		m.filter.SetValue("")
+		m.applyFilter()
+		return m, m.loadSelected(false)
+	}
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.applyFilter()
+	return m, cmd
+}
+
+func (m Model) updateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "/":
+		m.filtering = true
+		m.focus = focusList
+		return m, m.filter.Focus()
+	case "tab":
+		if m.focus == focusList {
+			m.focus = focusViewer
+		} else {
+			m.focus = focusList
+		}
+		return m, nil
+	case "d":
+		if m.detailLevel == "full" {
+			m.detailLevel = "summary"
+		} else {
+			m.detailLevel = "full"
+		}
+		return m, m.loadSelected(true)
+	case "s":
+		m.subagents = !m.subagents
+		return m, m.loadSelected(true)
+	case "f":
+		m.follow = !m.follow
+		if m.follow {
+			m.viewer.GotoBottom()
+		}
+		return m, nil
+	case "r":
+		return m, loadSessions
+	}
+
+	if m.focus == focusViewer {
+		switch msg.String() {
+		case "g", "home":
+			m.viewer.GotoTop()
+			return m, nil
+		case "G", "end":
+			m.viewer.GotoBottom()
+			return m, nil
+		case "esc":
+			m.focus = focusList
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.viewer, cmd = m.viewer.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			return m, m.loadSelected(false)
+		}
+	case "down", "j":
+		if m.cursor < len(m.visible)-1 {
+			m.cursor++
+			return m, m.loadSelected(false)
+		}
+	case "g", "home":
+		m.cursor = 0
+		return m, m.loadSelected(false)
+	case "G", "end":
+		m.cursor = max(len(m.visible)-1, 0)
+		return m, m.loadSelected(false)
+	case "enter", "l", "right":
+		m.focus = focusViewer
+	}
+	return m, nil
+}
+
+// Layout: one header line (filter), the two bordered panes, one help line.
+func (m *Model) resize() {
+	listWidth := m.listWidth()
+	m.viewer.Width = max(m.width-listWidth-4, 10)
+	m.viewer.Height = max(m.height-4, 1)
+	m.filter.Width = max(listWidth-4, 10)
+	if m.content != "" {
+		m.viewer.SetContent(wrap(m.content, m.viewer.Width))
+	}
+}
+
+func (m Model) listWidth() int {
+	return min(max(m.width*2/5, 30), 60)
+}
+
+func wrap(content string, width int) string {
+	if width <= 0 {
+		return content
+	}
+	return ansi.Wrap(content, width, "")
+}
+
+var (
+	mutedStyle    = lipgloss.NewStyle().Foreground(theme.DefaultColors.MutedText)
+	selectedStyle = lipgloss.NewStyle().Background(theme.DefaultColors.SelectedBackground).Bold(true)
+	headerStyle   = lipgloss.NewStyle().Foreground(theme.DefaultColors.Violet).Bold(true)
+	runningStyle  = lipgloss.NewStyle().Foreground(theme.DefaultColors.Green)
+)
+
+func paneStyle(focused bool) lipgloss.Style {
+	color := theme.DefaultColors.Border
+	if focused {
+		color = theme.DefaultColors.Violet
+	}
+	return lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(color)
+}
+
+func (m Model) View() string {
+	if m.width == 0 {
+		return "Loading sessions..."
+	}
+
+	listWidth := m.listWidth()
+	paneHeight := max(m.height-4, 1)
+
+	var header string
+	if m.filtering || m.filter.Value() != "" {
+		header = m.filter.View()
+	} else {
+		header = headerStyle.Render("aglogs") + mutedStyle.Render(fmt.Sprintf("  %d sessions", len(m.visible)))
+	}
+
+	list := paneStyle(m.focus == focusList).
+		Width(listWidth).Height(paneHeight).
+		Render(m.renderList(listWidth, paneHeight))
+	viewer := paneStyle(m.focus == focusViewer).
+		Width(m.viewer.Width).Height(paneHeight).
+		Render(m.viewer.View())
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		lipgloss.JoinHorizontal(lipgloss.Top, list, viewer),
+		m.renderHelp(),
+	)
+}
+
+func (m Model) renderList(width, height int) string {
+	if m.err != nil && len(m.sessions) == 0 {
+		return mutedStyle.Render(fmt.Sprintf("Failed to scan sessions: %v", m.err))
+	}
+	if len(m.visible) == 0 {
+		if m.sessions == nil {
+			return mutedStyle.Render("Scanning...")
+		}
+		return mutedStyle.Render("No matching sessions")
+	}
+
+	// Keep the cursor in view.
+	start := 0
+	if m.cursor >= height {
+		start = m.cursor - height + 1
+	}
+	end := min(start+height, len(m.visible))
+
+	rows := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		row := ansi.Truncate(sessionRow(m.sessions[m.visible[i]]), width, "…")
+		if i == m.cursor {
+			row = selectedStyle.Width(width).Render(ansi.Strip(row))
+		}
+		rows = append(rows, row)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func sessionRow(s session.SessionInfo) string {
+	label := s.ProjectName
+	if len(s.Jobs) > 0 {
+		label = s.Jobs[len(s.Jobs)-1].Plan + "/" + s.Jobs[len(s.Jobs)-1].Job
+	}
+	if label == "" {
+		label = s.SessionID
+	}
+	marker := " "
+	if s.Status == "running" {
+		marker = runningStyle.Render(theme.IconBullet)
+	}
+	started := ""
+	if !s.StartedAt.IsZero() {
+		started = s.StartedAt.Local().Format("01-02 15:04")
+	}
+	return fmt.Sprintf("%s %s %s", marker, mutedStyle.Render(started), label)
+}
+
+func (m Model) renderHelp() string {
+	follow := "off"
+	if m.follow {
+		follow = "on"
+	}
+	subagents := "off"
+	if m.subagents {
+		subagents = "on"
+	}
+	help := fmt.Sprintf("↑/↓ select  tab switch pane  / filter  d detail (%s)  s sub-agents (%s)  f follow (%s)  r rescan  q quit",
+		m.detailLevel, subagents, follow)
+	if m.filtering {
+		help = "enter apply  esc clear"
+	}
+	if m.loading {
+		help = "loading…  " + help
+	}
+	return mutedStyle.Render(ansi.Truncate(help, m.width, "…"))
+}
@@ -1,18 +1,38 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"os"
+	"os/signal"
+	"syscall"
 
 	grovelogging "github.com/grovetools/core/logging"
 
 	"github.com/grovetools/agentlogs/cmd"
 )
 
+// exitInterrupted is the conventional exit status after SIGINT (128+2).
+const exitInterrupted = 130
+
 func main() {
 	// CLI output goes to stdout (stderr is for errors only)
 	grovelogging.SetGlobalOutput(os.Stdout)
 
-	if err := cmd.NewRootCmd().Execute(); err != nil {
+	// Ctrl-C cancels the command's context, so scans and exports stop
+	// between files and clean up after themselves. Once it has, a second
+	// Ctrl-C kills the process outright.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := cmd.NewRootCmd().ExecuteContext(ctx); err != nil {
+		if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+			os.Exit(exitInterrupted)
+		}
 		os.Exit(1)
 	}
 }
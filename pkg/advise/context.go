@@ -0,0 +1,375 @@
+// Package advise turns what a session spent its context on into concrete
+// suggestions for prompting and configuring agents: the directories whose
+// reads dominated the input tokens, the single calls that blew up the
+// context, and the files read over and over.
+//
+// Like pkg/stats, Context is a pure fold over already-loaded entries, and
+// the findings are worded heuristically; callers may rephrase them.
+package advise
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/shellcmds"
+	"github.com/grovetools/agentlogs/pkg/stats"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// bytesPerToken estimates tokens from text size, the usual rule of thumb
+// for English and code.
+const bytesPerToken = 4
+
+// Thresholds below which a pattern is not worth a finding.
+const (
+	minDirectoryShare = 0.10
+	minRepeatedReads  = 3
+	maxFindings       = 10
+)
+
+// Finding kinds.
+const (
+	KindDirectory    = "directory"
+	KindOutlier      = "outlier"
+	KindRepeatedRead = "repeated_read"
+)
+
+// skippableDirs are directories that usually hold generated or third-party
+// files an agent rarely needs to read.
+var skippableDirs = map[string]bool{
+	"vendor": true, "node_modules": true, "dist": true, "build": true, "target": true,
+	".git": true, "third_party": true, "testdata": true, "coverage": true, ".next": true,
+}
+
+// Finding is one suggestion.
+type Finding struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	// Tokens estimates the input tokens the finding's calls accounted for,
+	// and Share that as a fraction of the session's input tokens.
+	Tokens int64   `json:"tokens"`
+	Share  float64 `json:"share"`
+}
+
+// ContextReport is the advice for one session.
+type ContextReport struct {
+	// InputTokens totals the input tokens (including cache reads and
+	// writes) of the session's assistant turns; 0 when the provider records
+	// none.
+	InputTokens int64 `json:"input_tokens"`
+	// Estimated is set when shares are measured against the tool output
+	// alone, for want of token data.
+	Estimated bool      `json:"estimated,omitempty"`
+	Findings  []Finding `json:"findings"`
+}
+
+// contextCall is one tool call with what it put into the context.
+type contextCall struct {
+	tool   string
+	target string // command, file or pattern
+	file   string // the file or directory it read, if any
+	shell  bool   // set for shell commands
+	bytes  int64
+	// turn is the number of assistant turns before the call's output
+	// entered the context; every later turn carries it again.
+	turn int
+}
+
+// Context folds entries into context-usage advice. A call's output is
+// charged once for every later assistant turn that carried it, so an
+// early 2 MiB read counts for far more than a late one. Sidechain entries
+// are excluded, matching pkg/stats.
+func Context(entries []transcript.UnifiedEntry) ContextReport {
+	var report ContextReport
+	var calls []*contextCall
+	byID := make(map[string]*contextCall)
+	turns := 0
+	counted := make(map[string]bool)
+
+	for _, entry := range entries {
+		if entry.IsSidechain {
+			continue
+		}
+		if entry.Role == "assistant" && entry.Tokens != nil {
+			// Claude logs a message in several lines that repeat its usage.
+			if entry.MessageID == "" || !counted[entry.MessageID] {
+				counted[entry.MessageID] = true
+				t := entry.Tokens
+				report.InputTokens += int64(t.Input + t.CacheRead + t.CacheWrite)
+				turns++
+			}
+		}
+		for _, part := range entry.Parts {
+			switch part.Type {
+			case "tool_call":
+				c := transcript.ToolCallOf(part)
+				call := &contextCall{tool: c.Name, target: shellcmds.Script(c.Name, c.Input), file: filePath(c.Input), bytes: jsonSize(c.Input) + int64(len(c.Output)), turn: turns}
+				call.shell = call.target != ""
+				if !call.shell {
+					call.target = target(c.Input)
+				}
+				calls = append(calls, call)
+				if c.ID != "" {
+					byID[c.ID] = call
+				}
+			case "tool_result":
+				c := transcript.ToolResultOf(part)
+				if call, ok := byID[c.ToolCallID]; ok {
+					call.bytes += int64(len(c.Output))
+					call.turn = turns
+				}
+			}
+		}
+	}
+
+	// Each call's weight is its tokens times the turns that carried it.
+	// Without token data, calls weigh their own size and shares are of the
+	// total tool output.
+	weight := func(c *contextCall) int64 {
+		tokens := c.bytes / bytesPerToken
+		if report.InputTokens == 0 {
+			return tokens
+		}
+		return tokens * int64(turns-c.turn)
+	}
+	var total int64
+	if report.InputTokens > 0 {
+		total = report.InputTokens
+	} else {
+		report.Estimated = true
+		for _, c := range calls {
+			total += weight(c)
+		}
+	}
+	if total == 0 {
+		report.Findings = []Finding{}
+		return report
+	}
+	share := func(tokens int64) float64 { return min(float64(tokens)/float64(total), 1) }
+	of := "of input tokens"
+	if report.Estimated {
+		of = "of tool output"
+	}
+
+	var findings []Finding
+	findings = append(findings, directoryFindings(calls, weight, share, of)...)
+	findings = append(findings, outlierFindings(calls, weight, share, of)...)
+	findings = append(findings, repeatedReadFindings(calls, weight, share)...)
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Tokens > findings[j].Tokens })
+	if len(findings) > maxFindings {
+		findings = findings[:maxFindings]
+	}
+	if findings == nil {
+		findings = []Finding{}
+	}
+	report.Findings = findings
+	return report
+}
+
+// directoryFindings reports the top-level directories whose reads took at
+// least minDirectoryShare of the context.
+func directoryFindings(calls []*contextCall, weight func(*contextCall) int64, share func(int64) float64, of string) []Finding {
+	root := commonDir(calls)
+	type dirUse struct {
+		calls  int
+		tokens int64
+	}
+	dirs := make(map[string]*dirUse)
+	for _, c := range calls {
+		dir := topDir(root, c.file)
+		if dir == "" {
+			continue
+		}
+		if dirs[dir] == nil {
+			dirs[dir] = &dirUse{}
+		}
+		dirs[dir].calls++
+		dirs[dir].tokens += weight(c)
+	}
+	var findings []Finding
+	for dir, use := range dirs {
+		s := share(use.tokens)
+		if s < minDirectoryShare {
+			continue
+		}
+		msg := fmt.Sprintf("Reads of %s/ accounted for %s %s in this session (%d calls, ~%s tokens)", dir, percent(s), of, use.calls, formatTokens(use.tokens))
+		if skippableDirs[path.Base(dir)] {
+			msg += "; it usually holds generated or third-party code, so consider telling the agent to skip it or excluding it from its tools."
+		} else {
+			msg += "; consider pointing the agent at the specific files it needs."
+		}
+		findings = append(findings, Finding{Kind: KindDirectory, Message: msg, Tokens: use.tokens, Share: s})
+	}
+	return findings
+}
+
+// outlierFindings reports the single calls at least as large as the stats
+// outlier size.
+func outlierFindings(calls []*contextCall, weight func(*contextCall) int64, share func(int64) float64, of string) []Finding {
+	var findings []Finding
+	for _, c := range calls {
+		if c.bytes < stats.DefaultOutlierBytes {
+			continue
+		}
+		tokens := weight(c)
+		label := c.tool
+		if c.target != "" {
+			label += " of " + clip(c.target, 80)
+		}
+		msg := fmt.Sprintf("A single %s added ~%s tokens to the context and accounted for %s %s", label, formatTokens(c.bytes/bytesPerToken), percent(share(tokens)), of)
+		switch {
+		case c.file != "" && c.target == c.file:
+			msg += "; consider reading a line range or searching the file instead."
+		case c.shell:
+			msg += "; consider quieter flags, or piping the output through tail or grep."
+		default:
+			msg += "."
+		}
+		findings = append(findings, Finding{Kind: KindOutlier, Message: msg, Tokens: tokens, Share: share(tokens)})
+	}
+	return findings
+}
+
+// repeatedReadFindings reports files read minRepeatedReads times or more.
+func repeatedReadFindings(calls []*contextCall, weight func(*contextCall) int64, share func(int64) float64) []Finding {
+	type fileUse struct {
+		reads  int
+		tokens int64
+	}
+	files := make(map[string]*fileUse)
+	var order []string
+	for _, c := range calls {
+		if c.file == "" || c.target != c.file || !isRead(c.tool) {
+			continue
+		}
+		if files[c.file] == nil {
+			files[c.file] = &fileUse{}
+			order = append(order, c.file)
+		}
+		files[c.file].reads++
+		files[c.file].tokens += weight(c)
+	}
+	var findings []Finding
+	for _, f := range order {
+		use := files[f]
+		if use.reads < minRepeatedReads {
+			continue
+		}
+		msg := fmt.Sprintf("%s was read %d times (~%s tokens); consider asking the agent to keep notes on it, or splitting it into smaller files.", f, use.reads, formatTokens(use.tokens))
+		findings = append(findings, Finding{Kind: KindRepeatedRead, Message: msg, Tokens: use.tokens, Share: share(use.tokens)})
+	}
+	return findings
+}
+
+// commonDir is the deepest directory containing every absolute file the
+// calls touched.
+func commonDir(calls []*contextCall) string {
+	var root string
+	first := true
+	for _, c := range calls {
+		if !path.IsAbs(c.file) {
+			continue
+		}
+		dir := path.Dir(c.file)
+		if first {
+			root, first = dir, false
+			continue
+		}
+		for root != "/" && dir != root && !strings.HasPrefix(dir, root+"/") {
+			root = path.Dir(root)
+		}
+	}
+	return root
+}
+
+// topDir returns the first directory of file below root ("vendor" for
+// root/vendor/x/y.go), or "" for files directly in root.
+func topDir(root, file string) string {
+	if file == "" {
+		return ""
+	}
+	rel := file
+	if path.IsAbs(file) && root != "" {
+		rel = strings.TrimPrefix(strings.TrimPrefix(file, root), "/")
+	}
+	rel = strings.TrimPrefix(path.Clean(rel), "./")
+	dir, _, ok := strings.Cut(rel, "/")
+	if !ok || dir == ".." {
+		return ""
+	}
+	return dir
+}
+
+func isRead(tool string) bool {
+	switch strings.ToLower(tool) {
+	case "read", "read_file", "view", "cat":
+		return true
+	}
+	return false
+}
+
+// filePath is the file or directory a call acted on.
+func filePath(input map[string]interface{}) string {
+	for _, key := range []string{"file_path", "filePath", "path", "notebook_path"} {
+		if s, ok := input[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// target names what a call other than a shell command acted on.
+func target(input map[string]interface{}) string {
+	if f := filePath(input); f != "" {
+		if _, isSearch := input["pattern"]; !isSearch {
+			return f
+		}
+	}
+	for _, key := range []string{"command", "pattern", "url", "query"} {
+		if s := transcript.StringField(input, key); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func jsonSize(input map[string]interface{}) int64 {
+	if len(input) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
+func clip(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+func percent(f float64) string {
+	if f > 0 && f < 0.01 {
+		return "<1%"
+	}
+	return fmt.Sprintf("%.0f%%", f*100)
+}
+
+// formatTokens renders a token count compactly: 950, 12k, 1.4M.
+func formatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1000:
+		return fmt.Sprintf("%dk", n/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
@@ -0,0 +1,74 @@
+package advise
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func read(id, file string, output int) []transcript.UnifiedEntry {
+	return []transcript.UnifiedEntry{
+		{Role: "assistant", MessageID: "m-" + id, Tokens: &transcript.UnifiedTokens{Input: 1000}, Parts: []transcript.UnifiedPart{
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: id, Name: "Read", Input: map[string]interface{}{"file_path": file}}},
+		}},
+		{Role: "user", Parts: []transcript.UnifiedPart{
+			{Type: "tool_result", Content: transcript.UnifiedToolResult{ToolCallID: id, Output: strings.Repeat("x", output)}},
+		}},
+	}
+}
+
+func TestContext(t *testing.T) {
+	var entries []transcript.UnifiedEntry
+	entries = append(entries, read("v1", "/repo/vendor/lib/a.go", 200_000)...)
+	entries = append(entries, read("s1", "/repo/src/main.go", 400)...)
+	entries = append(entries, read("s2", "/repo/src/main.go", 400)...)
+	entries = append(entries, read("s3", "/repo/src/main.go", 400)...)
+	entries = append(entries, transcript.UnifiedEntry{Role: "assistant", MessageID: "last", Tokens: &transcript.UnifiedTokens{Input: 50_000, CacheRead: 150_000}})
+	// Repeated usage lines of one message count once.
+	entries = append(entries, transcript.UnifiedEntry{Role: "assistant", MessageID: "last", Tokens: &transcript.UnifiedTokens{Input: 50_000, CacheRead: 150_000}})
+
+	r := Context(entries)
+	if r.InputTokens != 204_000 || r.Estimated {
+		t.Fatalf("InputTokens = %d (estimated %v), want 204000", r.InputTokens, r.Estimated)
+	}
+	kinds := make(map[string]Finding)
+	for _, f := range r.Findings {
+		kinds[f.Kind] = f
+	}
+	vendor, ok := kinds[KindDirectory]
+	if !ok || !strings.HasPrefix(vendor.Message, "Reads of vendor/ accounted for") || !strings.Contains(vendor.Message, "third-party") {
+		t.Errorf("directory finding = %+v", vendor)
+	}
+	// 50k tokens carried by 4 later turns, of 204k input tokens.
+	if vendor.Tokens < 200_000 || vendor.Tokens > 200_100 || vendor.Share < 0.97 {
+		t.Errorf("vendor tokens = %d share %.2f", vendor.Tokens, vendor.Share)
+	}
+	if f, ok := kinds[KindOutlier]; !ok || !strings.Contains(f.Message, "A single Read of /repo/vendor/lib/a.go") {
+		t.Errorf("outlier finding = %+v", f)
+	}
+	if f, ok := kinds[KindRepeatedRead]; !ok || !strings.HasPrefix(f.Message, "/repo/src/main.go was read 3 times") {
+		t.Errorf("repeated read finding = %+v", f)
+	}
+	if r.Findings[0].Kind == KindRepeatedRead {
+		t.Error("findings are not ordered by tokens")
+	}
+}
+
+func TestContextWithoutTokens(t *testing.T) {
+	entries := read("v1", "node_modules/x/index.js", 8000)
+	entries = append(entries, read("s1", "src/app.js", 8000)...)
+	for i := range entries {
+		entries[i].Tokens = nil
+	}
+	r := Context(entries)
+	if !r.Estimated || len(r.Findings) != 2 {
+		t.Fatalf("report = %+v, want two estimated directory findings", r)
+	}
+	if !strings.Contains(r.Findings[0].Message, "of tool output") {
+		t.Errorf("message = %q", r.Findings[0].Message)
+	}
+	if empty := Context(nil); empty.Findings == nil || len(empty.Findings) != 0 {
+		t.Errorf("empty report = %+v", empty)
+	}
+}
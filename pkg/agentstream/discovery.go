@@ -1,7 +1,6 @@
 package agentstream
 
 import (
-	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -234,9 +233,7 @@ func getFirstTimestampFromFile(filePath string) (time.Time, error) {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
+	scanner := transcript.NewLineScanner(file)
 
 	for i := 0; i < 10 && scanner.Scan(); i++ {
 		var entry struct {
@@ -1,7 +1,6 @@
 package agentstream
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -84,8 +83,7 @@ func transcriptTailActivity(path, provider string) (activity, inFlightTool strin
 
 	normalizer := NormalizerForProvider(provider)
 
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	scanner := transcript.NewLineScanner(f)
 	first := true
 
 	pendingTools := make(map[string]string) // tool_call id -> name
@@ -0,0 +1,137 @@
+// Package aglogs is the Go API for finding and reading coding-agent
+// session transcripts (Claude, Codex, pi, OpenCode), for tools that would
+// otherwise shell out to the aglogs CLI. It is a thin seam over the scanner,
+// resolver and transcript readers the CLI itself uses: types are aliases,
+// so values pass freely between this package and pkg/transcript.
+//
+// It replaces the Claude-only package claudelogs.
+package aglogs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/grovetools/agentlogs/internal/provider"
+	"github.com/grovetools/agentlogs/internal/schedule"
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// SessionInfo describes a session: identity, provider, project, transcript
+// location and any flow jobs it served.
+type SessionInfo = session.SessionInfo
+
+// JobInfo identifies a flow plan job a session ran.
+type JobInfo = session.JobInfo
+
+// ScanOptions narrows ScanSessions to a time window, and can include Claude
+// sub-agent transcripts as sessions of their own.
+type ScanOptions = session.ScanOptions
+
+// UnifiedEntry is one transcript message, normalized across providers. Its
+// parts hold the content types below.
+type UnifiedEntry = transcript.UnifiedEntry
+
+// Part and content types of a UnifiedEntry.
+type (
+	UnifiedPart        = transcript.UnifiedPart
+	UnifiedTextContent = transcript.UnifiedTextContent
+	UnifiedToolCall    = transcript.UnifiedToolCall
+	UnifiedToolResult  = transcript.UnifiedToolResult
+	UnifiedReasoning   = transcript.UnifiedReasoning
+	UnifiedImage       = transcript.UnifiedImage
+	UnifiedTokens      = transcript.UnifiedTokens
+)
+
+// ReadOptions controls how a transcript is read. The zero value reads the
+// whole transcript in full detail.
+type ReadOptions struct {
+	// Detail is "full" (the default) or "summary", which shortens tool
+	// input and output the way 'aglogs read' does.
+	Detail string
+	// IncludeSubagents nests Claude Task sub-agent transcripts under the
+	// calls that spawned them.
+	IncludeSubagents bool
+}
+
+// ScanSessions finds the sessions of every provider, newest first. When the
+// grove daemon is running, live sessions and flow jobs come from it too.
+func ScanSessions(opts ScanOptions) ([]SessionInfo, error) {
+	sessions, err := session.NewScannerWithOptions(opts).Scan()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.After(sessions[j].StartedAt)
+	})
+	return sessions, nil
+}
+
+// ResolveSession finds a session from a spec: a session ID or unique prefix
+// of one, a flow job ID, a plan/job ("<plan>/<job>.md"), or the path of a
+// job file or transcript.
+func ResolveSession(spec string) (*SessionInfo, error) {
+	return session.ResolveSessionInfo(spec)
+}
+
+// ReadSession resolves spec (see ResolveSession) and reads the whole
+// transcript of the session in full detail.
+func ReadSession(spec string) ([]UnifiedEntry, error) {
+	info, err := ResolveSession(spec)
+	if err != nil {
+		return nil, err
+	}
+	return Read(context.Background(), info, ReadOptions{})
+}
+
+// Read reads a session's transcript with the reader for its provider.
+func Read(ctx context.Context, info *SessionInfo, opts ReadOptions) ([]UnifiedEntry, error) {
+	detail := opts.Detail
+	if detail == "" {
+		detail = "full"
+	}
+	return provider.SelectSource(info, nil).Read(ctx, info, provider.ReadOptions{
+		DetailLevel:      detail,
+		EndLine:          -1,
+		IncludeSubagents: opts.IncludeSubagents,
+	})
+}
+
+// ReadFile reads the transcript file at path, with the provider worked out
+// from where the file lives (~/.codex/sessions, a pi session directory,
+// OpenCode storage; Claude otherwise). For OpenCode, path is the session's
+// info file (<storage>/session/<project>/<id>.json).
+func ReadFile(ctx context.Context, path string, opts ReadOptions) ([]UnifiedEntry, error) {
+	info := &SessionInfo{LogFilePath: path, Provider: session.ProviderForPath(path)}
+	return Read(ctx, info, opts)
+}
+
+// TranscriptPath returns the transcript file of a session, of any
+// provider, from its ID or a unique prefix of one.
+func TranscriptPath(sessionID string) (string, error) {
+	info, err := ResolveSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+	if info.LogFilePath == "" {
+		return "", fmt.Errorf("session %s has no transcript file", sessionID)
+	}
+	return info.LogFilePath, nil
+}
+
+// Stream follows a live session, sending entries as the agent writes them.
+// The channel closes when ctx is cancelled; a compressed transcript is sent
+// whole and the channel closed.
+func Stream(ctx context.Context, info *SessionInfo) (<-chan UnifiedEntry, error) {
+	return provider.SelectSource(info, nil).Stream(ctx, info)
+}
+
+// RunScheduledTasks runs the tasks in the schedule section of the aglogs
+// config as they fall due, until ctx is cancelled, so a daemon can stand in
+// for 'aglogs schedule run --loop'; pass it to transcript.Monitor's
+// SetSchedule to run it with the monitor. It returns at once when no tasks
+// are configured.
+func RunScheduledTasks(ctx context.Context) error {
+	return schedule.RunConfigured(ctx)
+}
@@ -0,0 +1,46 @@
+package aglogs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sess-1.jsonl")
+	lines := `{"type":"user","sessionId":"sess-1","timestamp":"2026-01-01T00:00:00Z","message":{"role":"user","content":"hello"}}
+{"type":"assistant","sessionId":"sess-1","timestamp":"2026-01-01T00:00:01Z","message":{"id":"m1","role":"assistant","content":[{"type":"text","text":"hi there"}]}}
+`
+	if err := os.WriteFile(path, []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Read(context.Background(), &SessionInfo{SessionID: "sess-1", LogFilePath: path, Provider: "claude"}, ReadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Role != "assistant" {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if text, ok := entries[1].Parts[0].Content.(UnifiedTextContent); !ok || text.Text != "hi there" {
+		t.Errorf("assistant part = %+v", entries[1].Parts[0])
+	}
+}
+
+func TestReadFileCodex(t *testing.T) {
+	// The fixture lives under codex/sessions/, which marks it as Codex.
+	path := "../transcript/testdata/codex/sessions/2026/07/01/rollout-2026-07-01T10-00-00-5973b6c0-94b8-487b-a530-2aeb6098ae0e.jsonl"
+	entries, err := ReadFile(context.Background(), path, ReadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("no entries read")
+	}
+	for _, e := range entries {
+		if e.Provider != "codex" {
+			t.Fatalf("entry provider = %q, want codex", e.Provider)
+		}
+	}
+}
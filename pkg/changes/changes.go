@@ -0,0 +1,370 @@
+// Package changes reconstructs the file modifications an agent made from
+// the tool calls in a normalized transcript: Claude Write/Edit/MultiEdit,
+// pi and OpenCode write/edit, Codex apply_patch (as a tool or a shell
+// heredoc), and OpenCode snapshot patch parts.
+//
+// Transcripts record edits, not files, so what can be recovered varies.
+// Replacements and patches become unified-diff hunks located by their
+// content rather than by line number; files the agent created are tracked
+// in full, so later edits to them are folded into one creation diff.
+// Changes whose content is not recorded (deletions, OpenCode snapshots,
+// overwrites of files never seen in full) are listed but marked Incomplete.
+package changes
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/shellcmds"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// Kind is what happened to a file.
+type Kind string
+
+const (
+	Created  Kind = "created"
+	Modified Kind = "modified"
+	Deleted  Kind = "deleted"
+)
+
+// Edit is one mutation of a file by one tool call.
+type Edit struct {
+	Tool      string    `json:"tool"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	Kind      Kind      `json:"kind"`
+	// Hunks is the edit as unified-diff hunks without file headers; empty
+	// when the transcript does not record the content.
+	Hunks   string `json:"hunks,omitempty"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+}
+
+// FileChange is everything that happened to one file, in order.
+type FileChange struct {
+	Path string `json:"path"`
+	// Kind is the net effect over the session.
+	Kind    Kind   `json:"kind"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Edits   []Edit `json:"edits"`
+	// Incomplete is set when some edit's content was not recorded, so the
+	// counts and the patch cover only part of the change.
+	Incomplete bool `json:"incomplete,omitempty"`
+
+	// content is the full file as of the last edit, when every edit since
+	// its creation was recorded.
+	content *string
+}
+
+// Extract walks the tool calls of a transcript and returns the files they
+// changed, sorted by path. Paths under root (the session's working
+// directory) are made relative to it. Failed tool calls are skipped.
+func Extract(entries []transcript.UnifiedEntry, root string) []FileChange {
+	x := &extractor{root: root, files: make(map[string]*FileChange), read: make(map[string]bool)}
+
+	failed := make(map[string]bool)
+	for _, entry := range entries {
+		for _, part := range entry.Parts {
+			if part.Type == "tool_result" {
+				if r := transcript.ToolResultOf(part); r.IsError && r.ToolCallID != "" {
+					failed[r.ToolCallID] = true
+				}
+			}
+		}
+	}
+
+	var snapshots []string
+	for _, entry := range entries {
+		for _, part := range entry.Parts {
+			if part.Type != "tool_call" {
+				continue
+			}
+			call := transcript.ToolCallOf(part)
+			if call.Status == "error" || (call.ID != "" && failed[call.ID]) {
+				continue
+			}
+			if strings.ToLower(call.Name) == "patch" {
+				// OpenCode snapshot: which files a turn touched, no content.
+				snapshots = append(snapshots, stringList(call.Input["files"])...)
+				continue
+			}
+			x.observe(call, entry.Timestamp)
+		}
+	}
+
+	// Snapshots repeat what the edit tools already recorded; only files
+	// changed some other way (a shell command) are new information.
+	for _, path := range snapshots {
+		path = x.rel(path, nil)
+		if _, ok := x.files[path]; !ok {
+			x.add(path, Edit{Tool: "patch", Kind: Modified})
+		}
+	}
+
+	result := make([]FileChange, 0, len(x.files))
+	for _, fc := range x.files {
+		fc.finish()
+		result = append(result, *fc)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+type extractor struct {
+	root  string
+	files map[string]*FileChange
+	read  map[string]bool // files the agent read before changing them
+}
+
+func (x *extractor) file(path string) *FileChange {
+	fc, ok := x.files[path]
+	if !ok {
+		fc = &FileChange{Path: path}
+		x.files[path] = fc
+	}
+	return fc
+}
+
+func (x *extractor) add(path string, e Edit) {
+	fc := x.file(path)
+	fc.Edits = append(fc.Edits, e)
+	if e.Hunks == "" && e.Kind != Created {
+		fc.Incomplete = true
+	}
+}
+
+// patchBlockRe matches a Codex apply_patch envelope inside a shell command.
+var patchBlockRe = regexp.MustCompile(`(?s)\*\*\* Begin Patch\n.*?\*\*\* End Patch`)
+
+func (x *extractor) observe(call transcript.UnifiedToolCall, ts time.Time) {
+	name := strings.ToLower(call.Name)
+	in := call.Input
+
+	if cmd := shellcmds.Script(name, in); cmd != "" {
+		for _, patch := range patchBlockRe.FindAllString(cmd, -1) {
+			x.applyCodexPatch(patch, call, ts)
+		}
+		return
+	}
+
+	switch name {
+	case "apply_patch":
+		x.applyCodexPatch(inputString(in, "input", "patch", "arguments"), call, ts)
+	case "read", "view":
+		if path := inputString(in, "file_path", "filePath", "path"); path != "" {
+			x.read[x.rel(path, in)] = true
+		}
+	case "write":
+		path := inputString(in, "file_path", "filePath", "path")
+		content, ok := in["content"].(string)
+		if path == "" || !ok {
+			return
+		}
+		x.write(x.rel(path, in), content, call, ts)
+	case "edit", "multiedit":
+		path := inputString(in, "file_path", "filePath", "path")
+		if path == "" {
+			return
+		}
+		var reps [][2]string
+		if old, ok := firstString(in, "old_string", "oldString", "oldText"); ok {
+			reps = append(reps, [2]string{old, inputString(in, "new_string", "newString", "newText")})
+		}
+		if edits, ok := in["edits"].([]interface{}); ok {
+			for _, e := range edits {
+				if m, ok := e.(map[string]interface{}); ok {
+					old, _ := firstString(m, "old_string", "oldString", "oldText")
+					reps = append(reps, [2]string{old, inputString(m, "new_string", "newString", "newText")})
+				}
+			}
+		}
+		x.edit(x.rel(path, in), reps, call, ts)
+	}
+}
+
+// write records a full-content write.
+func (x *extractor) write(path, content string, call transcript.UnifiedToolCall, ts time.Time) {
+	fc, seen := x.files[path]
+	out := strings.ToLower(call.Output)
+	e := Edit{Tool: call.Name, Timestamp: ts, Kind: Created}
+	switch {
+	case strings.Contains(out, "created"):
+	case seen && fc.content != nil:
+		// Overwrite of a file whose content is known: diff against it.
+		e.Kind = Modified
+		e.Hunks, e.Added, e.Removed = lineHunk(*fc.content, content, true)
+	case seen || x.read[path] || strings.Contains(out, "updated"):
+		// Overwrite of a file never seen in full. Claude only lets an
+		// agent write a file it has read, so a write without an earlier
+		// read is a creation.
+		e.Kind = Modified
+	}
+	if e.Kind == Created {
+		e.Hunks, e.Added, e.Removed = lineHunk("", content, true)
+	}
+	x.add(path, e)
+	fc = x.files[path]
+	if e.Kind == Created || fc.content != nil {
+		fc.content = &content
+	}
+}
+
+// edit records string replacements.
+func (x *extractor) edit(path string, reps [][2]string, call transcript.UnifiedToolCall, ts time.Time) {
+	e := Edit{Tool: call.Name, Timestamp: ts, Kind: Modified}
+	var hunks []string
+	for _, r := range reps {
+		h, added, removed := lineHunk(r[0], r[1], false)
+		hunks = append(hunks, h)
+		e.Added += added
+		e.Removed += removed
+	}
+	e.Hunks = strings.Join(hunks, "")
+
+	fc := x.file(path)
+	if fc.content != nil {
+		content := *fc.content
+		for _, r := range reps {
+			if r[0] == "" || !strings.Contains(content, r[0]) {
+				fc.content = nil
+				break
+			}
+			content = strings.Replace(content, r[0], r[1], 1)
+		}
+		if fc.content != nil {
+			fc.content = &content
+		}
+	}
+	x.add(path, e)
+}
+
+// applyCodexPatch records the files of one apply_patch envelope:
+//
+//	*** Add File: <path>      followed by "+" lines
+//	*** Update File: <path>   optionally "*** Move to: <path>", then hunks
+//	*** Delete File: <path>
+func (x *extractor) applyCodexPatch(patch string, call transcript.UnifiedToolCall, ts time.Time) {
+	var path string
+	var kind Kind
+	var body []string
+	flush := func() {
+		if path == "" {
+			return
+		}
+		e := Edit{Tool: call.Name, Timestamp: ts, Kind: kind}
+		switch kind {
+		case Created:
+			content := ""
+			for _, l := range body {
+				content += strings.TrimPrefix(l, "+") + "\n"
+			}
+			e.Hunks, e.Added, e.Removed = lineHunk("", content, true)
+			x.add(path, e)
+			x.files[path].content = &content
+		case Modified:
+			e.Hunks, e.Added, e.Removed = codexHunks(body)
+			x.file(path).content = nil
+			x.add(path, e)
+		case Deleted:
+			x.add(path, e)
+			x.files[path].content = nil
+		}
+		path, body = "", nil
+	}
+
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "*** Add File: "):
+			flush()
+			path, kind = x.rel(strings.TrimSpace(strings.TrimPrefix(line, "*** Add File: ")), call.Input), Created
+		case strings.HasPrefix(line, "*** Update File: "):
+			flush()
+			path, kind = x.rel(strings.TrimSpace(strings.TrimPrefix(line, "*** Update File: ")), call.Input), Modified
+		case strings.HasPrefix(line, "*** Delete File: "):
+			flush()
+			path, kind = x.rel(strings.TrimSpace(strings.TrimPrefix(line, "*** Delete File: ")), call.Input), Deleted
+		case strings.HasPrefix(line, "*** Move to: "):
+			// The content lands under the new name; the old one goes away.
+			x.add(path, Edit{Tool: call.Name, Timestamp: ts, Kind: Deleted})
+			path = x.rel(strings.TrimSpace(strings.TrimPrefix(line, "*** Move to: ")), call.Input)
+		case strings.HasPrefix(line, "*** "):
+			// Begin/End Patch, End of File.
+		default:
+			if path != "" {
+				body = append(body, line)
+			}
+		}
+	}
+	flush()
+}
+
+// finish derives the net kind and totals.
+func (fc *FileChange) finish() {
+	for _, e := range fc.Edits {
+		fc.Added += e.Added
+		fc.Removed += e.Removed
+	}
+	first, last := fc.Edits[0].Kind, fc.Edits[len(fc.Edits)-1].Kind
+	switch {
+	case last == Deleted:
+		fc.Kind = Deleted
+	case first == Created:
+		fc.Kind = Created
+	default:
+		fc.Kind = Modified
+	}
+}
+
+// rel makes path relative to the session root, resolving relative paths
+// against the call's own working directory (Codex's workdir) when given.
+func (x *extractor) rel(path string, input map[string]interface{}) string {
+	if !filepath.IsAbs(path) {
+		wd := inputString(input, "workdir", "cwd")
+		if wd == "" {
+			return filepath.Clean(path)
+		}
+		path = filepath.Join(wd, path)
+	}
+	if x.root != "" {
+		if rel, err := filepath.Rel(x.root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return filepath.Clean(path)
+}
+
+func stringList(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func inputString(input map[string]interface{}, keys ...string) string {
+	s, _ := firstString(input, keys...)
+	return s
+}
+
+// firstString returns the first key present as a string, even if empty
+// (an edit may legitimately replace with "").
+func firstString(input map[string]interface{}, keys ...string) (string, bool) {
+	for _, key := range keys {
+		if s, ok := input[key].(string); ok {
+			return s, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,88 @@
+package changes
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func call(id, name string, input map[string]interface{}, output string) transcript.UnifiedEntry {
+	return transcript.UnifiedEntry{Role: "assistant", Parts: []transcript.UnifiedPart{
+		{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: id, Name: name, Input: input, Output: output}},
+	}}
+}
+
+func TestExtract(t *testing.T) {
+	entries := []transcript.UnifiedEntry{
+		call("1", "Read", map[string]interface{}{"file_path": "/w/main.go"}, ""),
+		call("2", "Edit", map[string]interface{}{"file_path": "/w/main.go", "old_string": "\tfmt.Println(\"hi\")", "new_string": "\tfmt.Println(\"hello\")\n\tos.Exit(0)"}, ""),
+		call("3", "Write", map[string]interface{}{"file_path": "/w/notes.md", "content": "one\ntwo\n"}, "File created successfully"),
+		call("4", "Edit", map[string]interface{}{"file_path": "/w/notes.md", "old_string": "two", "new_string": "three"}, ""),
+		call("5", "shell", map[string]interface{}{"command": []interface{}{"bash", "-lc", "apply_patch <<'EOF'\n*** Begin Patch\n*** Delete File: old.txt\n*** End Patch\nEOF"}, "workdir": "/w"}, ""),
+		{Role: "assistant", Parts: []transcript.UnifiedPart{
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: "6", Name: "Edit", Status: "error", Input: map[string]interface{}{"file_path": "/w/failed.go", "old_string": "a", "new_string": "b"}}},
+		}},
+	}
+
+	got := Extract(entries, "/w")
+	var summary []string
+	for _, fc := range got {
+		summary = append(summary, string(fc.Kind)+" "+fc.Path)
+	}
+	want := []string{"modified main.go", "created notes.md", "deleted old.txt"}
+	if !reflect.DeepEqual(summary, want) {
+		t.Fatalf("changes = %v, want %v", summary, want)
+	}
+	if main := got[0]; main.Added != 2 || main.Removed != 1 || main.Incomplete {
+		t.Errorf("main.go = +%d -%d incomplete=%v, want +2 -1", main.Added, main.Removed, main.Incomplete)
+	}
+	if notes := got[1]; notes.content == nil || *notes.content != "one\nthree\n" {
+		t.Errorf("notes.md content not tracked through the edit: %v", notes.content)
+	}
+	if !got[2].Incomplete {
+		t.Error("deletion should be incomplete")
+	}
+}
+
+func TestWritePatchApplies(t *testing.T) {
+	git, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.py"), []byte("import os\n\ndef main():\n    print('hi')\n    return 0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []transcript.UnifiedEntry{
+		call("1", "apply_patch", map[string]interface{}{"input": "*** Begin Patch\n*** Update File: app.py\n@@ def main():\n-    print('hi')\n+    print('hello')\n*** Add File: lib/util.py\n+def util():\n+    pass\n*** End Patch"}, ""),
+		call("2", "edit", map[string]interface{}{"path": "app.py", "oldText": "    return 0", "newText": "    return 1"}, ""),
+	}
+	var buf bytes.Buffer
+	if err := WritePatch(&buf, Extract(entries, dir)); err != nil {
+		t.Fatal(err)
+	}
+	patch := filepath.Join(t.TempDir(), "changes.patch")
+	if err := os.WriteFile(patch, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(git, "apply", "--unidiff-zero", patch)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git apply failed: %v\n%s\npatch:\n%s", err, out, buf.String())
+	}
+
+	app, _ := os.ReadFile(filepath.Join(dir, "app.py"))
+	if string(app) != "import os\n\ndef main():\n    print('hello')\n    return 1\n" {
+		t.Errorf("app.py = %q", app)
+	}
+	util, _ := os.ReadFile(filepath.Join(dir, "lib", "util.py"))
+	if string(util) != "def util():\n    pass\n" {
+		t.Errorf("lib/util.py = %q", util)
+	}
+}
@@ -0,0 +1,163 @@
+package changes
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+// in a comparison.
+const diffContext = 3
+
+// FileComparison puts what two runs of the same work did to one file side
+// by side.
+type FileComparison struct {
+	Path string      `json:"path"`
+	A    *FileChange `json:"a,omitempty"`
+	B    *FileChange `json:"b,omitempty"`
+	// Same is set when both runs recorded identical changes to the file.
+	Same bool `json:"same"`
+	// Diff is a unified diff from A's version to B's: of the resulting
+	// files when both runs recorded them in full (ContentDiff), otherwise
+	// of the two runs' patches for the file. Empty when the file was
+	// changed by only one run, or identically.
+	Diff        string `json:"diff,omitempty"`
+	ContentDiff bool   `json:"contentDiff,omitempty"`
+}
+
+// Compare pairs up the files changed by two runs, as returned by Extract,
+// sorted by path.
+func Compare(a, b []FileChange) []FileComparison {
+	byPath := make(map[string]*FileComparison)
+	for i := range a {
+		byPath[a[i].Path] = &FileComparison{Path: a[i].Path, A: &a[i]}
+	}
+	for i := range b {
+		c, ok := byPath[b[i].Path]
+		if !ok {
+			c = &FileComparison{Path: b[i].Path}
+			byPath[b[i].Path] = c
+		}
+		c.B = &b[i]
+	}
+
+	result := make([]FileComparison, 0, len(byPath))
+	for _, c := range byPath {
+		if c.A != nil && c.B != nil {
+			oldText, newText := filePatch(*c.A), filePatch(*c.B)
+			if c.A.content != nil && c.B.content != nil && c.A.Kind != Deleted && c.B.Kind != Deleted {
+				oldText, newText = *c.A.content, *c.B.content
+				c.ContentDiff = true
+			}
+			c.Same = oldText == newText
+			if !c.Same {
+				c.Diff = unifiedDiff(oldText, newText, diffContext)
+			}
+		}
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+// WriteComparison renders comparisons as text: a line per file saying which
+// run changed it, followed by the diff between the runs where both did.
+func WriteComparison(w io.Writer, cmps []FileComparison, labelA, labelB string) error {
+	var sb strings.Builder
+	stat := func(fc *FileChange) string {
+		s := fmt.Sprintf("%s +%d -%d", fc.Kind, fc.Added, fc.Removed)
+		if fc.Incomplete {
+			s += " (incomplete)"
+		}
+		return s
+	}
+	for _, c := range cmps {
+		switch {
+		case c.B == nil:
+			fmt.Fprintf(&sb, "only %s: %s (%s)\n", labelA, c.Path, stat(c.A))
+		case c.A == nil:
+			fmt.Fprintf(&sb, "only %s: %s (%s)\n", labelB, c.Path, stat(c.B))
+		case c.Same:
+			fmt.Fprintf(&sb, "same in both: %s (%s)\n", c.Path, stat(c.A))
+		default:
+			what := "edits differ"
+			if c.ContentDiff {
+				what = "results differ"
+			}
+			fmt.Fprintf(&sb, "%s: %s (%s: %s; %s: %s)\n", what, c.Path, labelA, stat(c.A), labelB, stat(c.B))
+			fmt.Fprintf(&sb, "--- %s/%s\n+++ %s/%s\n%s", labelA, c.Path, labelB, c.Path, c.Diff)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// unifiedDiff renders old -> new as unified-diff hunks with n lines of
+// context and real line numbers.
+func unifiedDiff(old, new string, n int) string {
+	a, b := splitLines(old, false), splitLines(new, false)
+	var ops []string
+	if len(a)*len(b) > maxDiffCells {
+		for _, l := range a {
+			ops = append(ops, "-"+l)
+		}
+		for _, l := range b {
+			ops = append(ops, "+"+l)
+		}
+	} else {
+		ops = diffLines(a, b)
+	}
+
+	var sb strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change and the end of its hunk: changes closer
+		// than 2n unchanged lines share a hunk.
+		first := start
+		for first < len(ops) && ops[first][0] == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		end, gap := first, 0
+		for i := first; i < len(ops); i++ {
+			if ops[i][0] == ' ' {
+				if gap++; gap > 2*n {
+					break
+				}
+				continue
+			}
+			gap = 0
+			end = i + 1
+		}
+		from, to := max(start, first-n), min(len(ops), end+n)
+
+		// Line numbers of the hunk start in a and b.
+		la, lb := 1, 1
+		for _, op := range ops[:from] {
+			if op[0] != '+' {
+				la++
+			}
+			if op[0] != '-' {
+				lb++
+			}
+		}
+		var na, nb int
+		for _, op := range ops[from:to] {
+			if op[0] != '+' {
+				na++
+			}
+			if op[0] != '-' {
+				nb++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", la, na, lb, nb)
+		for _, op := range ops[from:to] {
+			sb.WriteString(op)
+		}
+		start = to
+	}
+	return sb.String()
+}
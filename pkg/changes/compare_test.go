@@ -0,0 +1,71 @@
+package changes
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestCompare(t *testing.T) {
+	write := func(id, path, content string) transcript.UnifiedEntry {
+		return call(id, "Write", map[string]interface{}{"file_path": "/w/" + path, "content": content}, "")
+	}
+	a := Extract([]transcript.UnifiedEntry{
+		write("1", "notes.md", "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"),
+		write("2", "same.go", "package x\n"),
+		write("3", "only_a.go", "package a\n"),
+	}, "/w")
+	b := Extract([]transcript.UnifiedEntry{
+		write("1", "notes.md", "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nNINE\nten\n"),
+		write("2", "same.go", "package x\n"),
+		write("3", "only_b.go", "package b\n"),
+	}, "/w")
+
+	got := Compare(a, b)
+	var paths []string
+	for _, c := range got {
+		paths = append(paths, c.Path)
+	}
+	if strings.Join(paths, " ") != "notes.md only_a.go only_b.go same.go" {
+		t.Fatalf("paths = %v", paths)
+	}
+	notes := got[0]
+	if notes.Same || !notes.ContentDiff {
+		t.Fatalf("notes.md: same=%v contentDiff=%v", notes.Same, notes.ContentDiff)
+	}
+	want := "@@ -6,5 +6,5 @@\n six\n seven\n eight\n-nine\n+NINE\n ten\n"
+	if notes.Diff != want {
+		t.Errorf("notes.md diff =\n%s\nwant\n%s", notes.Diff, want)
+	}
+	if got[1].B != nil || got[2].A != nil || !got[3].Same {
+		t.Errorf("unexpected pairing: %+v", got[1:])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteComparison(&buf, got, "1-claude", "2-codex"); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"only 1-claude: only_a.go", "only 2-codex: only_b.go", "same in both: same.go", "--- 1-claude/notes.md"} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("comparison lacks %q:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	var a, b []string
+	for i := 1; i <= 20; i++ {
+		a = append(a, "l"+strings.Repeat("x", i))
+		b = append(b, "l"+strings.Repeat("x", i))
+	}
+	b[1], b[17] = "changed", "changed"
+	got := unifiedDiff(strings.Join(a, "\n")+"\n", strings.Join(b, "\n")+"\n", 3)
+	if n := strings.Count(got, "@@ -"); n != 2 {
+		t.Fatalf("got %d hunks, want 2:\n%s", n, got)
+	}
+	if !strings.HasPrefix(got, "@@ -1,5 +1,5 @@\n") || !strings.Contains(got, "@@ -15,6 +15,6 @@\n") {
+		t.Errorf("hunk headers wrong:\n%s", got)
+	}
+}
@@ -0,0 +1,214 @@
+package changes
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxDiffCells bounds the line LCS table; larger rewrites are rendered as
+// a full removal followed by a full addition.
+const maxDiffCells = 4 << 20
+
+// lineHunk renders old -> new as a single unified-diff hunk and counts the
+// added and removed lines. Edits are fragments whose position in the file
+// is unknown, so the hunk claims line 1 and relies on the patch tool
+// locating it by content: patch does so by default, git apply needs
+// --unidiff-zero since the hunk may have no context lines. A fragment
+// without a final newline is still treated as whole lines; for whole
+// files (whole=true) the missing newline is marked the way diff does.
+func lineHunk(old, new string, whole bool) (hunk string, added, removed int) {
+	a, b := splitLines(old, whole), splitLines(new, whole)
+
+	var ops []string // each line prefixed with ' ', '-' or '+'
+	if len(a)*len(b) > maxDiffCells {
+		for _, l := range a {
+			ops = append(ops, "-"+l)
+		}
+		for _, l := range b {
+			ops = append(ops, "+"+l)
+		}
+	} else {
+		ops = diffLines(a, b)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(len(a)), hunkRange(len(b)))
+	for _, op := range ops {
+		switch op[0] {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+		sb.WriteString(op)
+		if !strings.HasSuffix(op, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+	if added == 0 && removed == 0 {
+		return "", 0, 0
+	}
+	return sb.String(), added, removed
+}
+
+func hunkRange(n int) string {
+	if n == 0 {
+		return "0,0"
+	}
+	return fmt.Sprintf("1,%d", n)
+}
+
+// splitLines splits s into lines that keep their "\n". Unless whole is
+// set, a final line without one gets it.
+func splitLines(s string, whole bool) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else if !whole {
+		lines[len(lines)-1] += "\n"
+	}
+	return lines
+}
+
+// diffLines returns the edit script from a to b via a longest common
+// subsequence of lines.
+func diffLines(a, b []string) []string {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]string, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, " "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, "-"+a[i])
+			i++
+		default:
+			ops = append(ops, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, "+"+b[j])
+	}
+	return ops
+}
+
+// codexHunks converts the body of an apply_patch "Update File" section
+// into unified-diff hunks. Each "@@" line (optionally followed by a
+// context hint) starts a hunk of " ", "-" and "+" lines.
+func codexHunks(body []string) (hunks string, added, removed int) {
+	var sb strings.Builder
+	var chunk []string
+	flush := func() {
+		oldN, newN := 0, 0
+		for _, l := range chunk {
+			switch l[0] {
+			case '-':
+				oldN++
+			case '+':
+				newN++
+			default:
+				oldN++
+				newN++
+			}
+		}
+		if oldN+newN == 0 {
+			chunk = nil
+			return
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldN), hunkRange(newN))
+		for _, l := range chunk {
+			sb.WriteString(l)
+			sb.WriteString("\n")
+		}
+		chunk = nil
+	}
+	for _, line := range body {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+		case strings.HasPrefix(line, "+"):
+			added++
+			chunk = append(chunk, line)
+		case strings.HasPrefix(line, "-"):
+			removed++
+			chunk = append(chunk, line)
+		case strings.HasPrefix(line, " "):
+			chunk = append(chunk, line)
+		case line == "":
+			// Blank context lines often lose their leading space.
+			chunk = append(chunk, " ")
+		}
+	}
+	flush()
+	return sb.String(), added, removed
+}
+
+// WritePatch writes changes as one patch for patch -p1 or git apply
+// --unidiff-zero. Files created during the session whose content is fully
+// known become a single new-file diff; other files get one section per
+// edit, in order.
+// Changes whose content is not recorded are noted as "#" comment lines,
+// which both tools skip.
+func WritePatch(w io.Writer, changes []FileChange) error {
+	var sb strings.Builder
+	for _, fc := range changes {
+		sb.WriteString(filePatch(fc))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// filePatch is one file's section of WritePatch.
+func filePatch(fc FileChange) string {
+	var sb strings.Builder
+	p := fc.Path
+	if fc.Kind == Deleted && fc.Edits[0].Kind == Created {
+		fmt.Fprintf(&sb, "# %s: created and deleted during the session\n", p)
+		return sb.String()
+	}
+	if fc.Kind == Created && fc.content != nil {
+		hunk, _, _ := lineHunk("", *fc.content, true)
+		fmt.Fprintf(&sb, "diff --git a/%s b/%s\nnew file mode 100644\n--- /dev/null\n+++ b/%s\n%s", p, p, p, hunk)
+		return sb.String()
+	}
+	for _, e := range fc.Edits {
+		switch {
+		case e.Kind == Deleted:
+			fmt.Fprintf(&sb, "# %s: deleted by %s (content not recorded; remove it by hand)\n", p, e.Tool)
+		case e.Hunks == "" && e.Kind == Created:
+			fmt.Fprintf(&sb, "diff --git a/%s b/%s\nnew file mode 100644\n", p, p)
+		case e.Hunks == "":
+			fmt.Fprintf(&sb, "# %s: modified by %s (content not recorded)\n", p, e.Tool)
+		case e.Kind == Created:
+			fmt.Fprintf(&sb, "diff --git a/%s b/%s\nnew file mode 100644\n--- /dev/null\n+++ b/%s\n%s", p, p, p, e.Hunks)
+		default:
+			fmt.Fprintf(&sb, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n%s", p, p, p, p, e.Hunks)
+		}
+	}
+	return sb.String()
+}
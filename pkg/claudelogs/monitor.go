@@ -8,11 +8,15 @@ import (
 )
 
 // Monitor wraps the internal transcript monitor
+//
+// Deprecated: use aglogs.Stream to follow a live session of any provider.
 type Monitor struct {
 	*transcript.Monitor
 }
 
 // NewMonitor creates a new transcript monitor
+//
+// Deprecated: use aglogs.Stream.
 func NewMonitor(db *sql.DB, checkInterval time.Duration) *Monitor {
 	return &Monitor{
 		Monitor: transcript.NewMonitor(db, checkInterval),
@@ -20,6 +24,8 @@ func NewMonitor(db *sql.DB, checkInterval time.Duration) *Monitor {
 }
 
 // NewMonitorWithConfig creates a new transcript monitor with custom configuration
+//
+// Deprecated: use aglogs.Stream.
 func NewMonitorWithConfig(db *sql.DB, checkInterval time.Duration, summaryConfig SummaryConfig) *Monitor {
 	internalConfig := transcript.SummaryConfig{
 		Enabled:          summaryConfig.Enabled,
@@ -29,6 +35,11 @@ func NewMonitorWithConfig(db *sql.DB, checkInterval time.Duration, summaryConfig
 		RecentWindow:     summaryConfig.RecentWindow,
 		MaxInputTokens:   summaryConfig.MaxInputTokens,
 		MilestoneEnabled: summaryConfig.MilestoneEnabled,
+		Backend:          summaryConfig.Backend,
+		Model:            summaryConfig.Model,
+		Endpoint:         summaryConfig.Endpoint,
+		APIKeyEnv:        summaryConfig.APIKeyEnv,
+		MaxOutputTokens:  summaryConfig.MaxOutputTokens,
 	}
 
 	return &Monitor{
@@ -45,6 +56,14 @@ type SummaryConfig struct {
 	RecentWindow     int
 	MaxInputTokens   int
 	MilestoneEnabled bool
+	// Backend, Model, Endpoint, APIKeyEnv and MaxOutputTokens select the
+	// summarizer as in transcript.SummaryConfig; the zero values run
+	// LLMCommand.
+	Backend         string
+	Model           string
+	Endpoint        string
+	APIKeyEnv       string
+	MaxOutputTokens int
 }
 
 // Start begins monitoring for new transcript entries
@@ -1,3 +1,8 @@
+// Package claudelogs is the original, Claude-only API for parsing and
+// monitoring transcripts. Its names are kept as shims for existing callers.
+//
+// Deprecated: use package github.com/grovetools/agentlogs/pkg/aglogs, which
+// finds and reads Claude, Codex, pi and OpenCode sessions alike.
 package claudelogs
 
 import (
@@ -5,11 +10,16 @@ import (
 )
 
 // Parser wraps the internal transcript parser
+//
+// Deprecated: use aglogs.ReadFile, which reads any provider's transcript
+// into normalized entries.
 type Parser struct {
 	*transcript.Parser
 }
 
 // NewParser creates a new transcript parser
+//
+// Deprecated: use aglogs.ReadFile.
 func NewParser() *Parser {
 	return &Parser{
 		Parser: transcript.NewParser(),
@@ -28,6 +38,9 @@ func (p *Parser) ParseFileFromOffset(path string, offset int64) ([]transcript.Ex
 
 // GetTranscriptPath returns the path to a transcript file for a given session ID.
 // This function assumes Claude as the provider for backward compatibility.
+//
+// Deprecated: use aglogs.TranscriptPath, which finds sessions of every
+// provider.
 func GetTranscriptPath(sessionID string) (string, error) {
 	return transcript.GetTranscriptPath(sessionID, "claude")
 }
@@ -0,0 +1,216 @@
+// Package codelang detects the programming language of the code in
+// transcript entries: fenced code blocks in message text, and files the
+// agent wrote or edited through its tools. Each entry is tagged with its
+// dominant language, the one with the most lines of code in it.
+package codelang
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// aliases maps fence tags and file extensions to canonical language names.
+var aliases = map[string]string{
+	"go": "go", "golang": "go",
+	"py": "python", "python": "python", "python3": "python",
+	"js": "javascript", "javascript": "javascript", "jsx": "javascript", "mjs": "javascript", "cjs": "javascript", "node": "javascript",
+	"ts": "typescript", "typescript": "typescript", "tsx": "typescript",
+	"rs": "rust", "rust": "rust",
+	"rb": "ruby", "ruby": "ruby",
+	"java": "java", "kt": "kotlin", "kotlin": "kotlin", "swift": "swift", "scala": "scala",
+	"c": "c", "h": "c",
+	"cc": "cpp", "cpp": "cpp", "cxx": "cpp", "hpp": "cpp", "c++": "cpp",
+	"cs": "csharp", "csharp": "csharp",
+	"php": "php", "lua": "lua", "zig": "zig", "ex": "elixir", "exs": "elixir", "elixir": "elixir",
+	"sh": "shell", "bash": "shell", "zsh": "shell", "shell": "shell", "console": "shell", "fish": "shell",
+	"sql":  "sql",
+	"json": "json", "jsonl": "json",
+	"yaml": "yaml", "yml": "yaml", "toml": "toml",
+	"html": "html", "css": "css", "scss": "css",
+	"md": "markdown", "markdown": "markdown",
+	"dockerfile": "dockerfile", "makefile": "makefile", "mk": "makefile",
+	"proto": "protobuf", "protobuf": "protobuf",
+	"tf": "terraform", "hcl": "terraform",
+}
+
+// Normalize maps a fence tag or language name ("golang", "py", "tsx") to
+// its canonical name, or "" when it is not a known language.
+func Normalize(name string) string {
+	return aliases[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// signatures recognize untagged code blocks, tried in order.
+var signatures = []struct {
+	lang string
+	re   *regexp.Regexp
+}{
+	{"go", regexp.MustCompile(`(?m)^package \w+$|^func (\(\w+ \*?\w+\) )?\w+\(|:= `)},
+	{"rust", regexp.MustCompile(`(?m)^\s*(pub )?fn \w+|let mut |impl\b.*\{|use \w+::`)},
+	{"python", regexp.MustCompile(`(?m)^\s*def \w+\(.*\):|^\s*(from \w+(\.\w+)* )?import \w+$|^\s*class \w+.*:$|self\.`)},
+	{"typescript", regexp.MustCompile(`(?m)^\s*(export )?(interface|type) \w+|: (string|number|boolean)\b`)},
+	{"javascript", regexp.MustCompile(`(?m)\b(const|let) \w+ = |=> \{|function \w*\(|require\(`)},
+	{"java", regexp.MustCompile(`(?m)^\s*(public|private) (static )?(class|void)\b|System\.out\.`)},
+	{"cpp", regexp.MustCompile(`(?m)^#include <\w+>$|std::`)},
+	{"c", regexp.MustCompile(`(?m)^#include [<"]\w+\.h[>"]`)},
+	{"sql", regexp.MustCompile(`(?mi)^\s*(select .+ from|insert into|create table|update \w+ set)\b`)},
+	{"shell", regexp.MustCompile(`(?m)^#!/bin/(ba|z)?sh|^\$ \w+|^\s*(cd|ls|git|go|npm|make|echo|export|mkdir|rm|cat) `)},
+	{"yaml", regexp.MustCompile(`(?m)^[\w-]+:( .+)?$\n^  [\w-]+:`)},
+}
+
+// Detect returns the canonical language of a code block from its fence tag
+// when there is one, otherwise by recognizing its contents; "" when it
+// cannot tell.
+func Detect(tag, code string) string {
+	if fields := strings.Fields(tag); len(fields) > 0 {
+		// The tag's first word names the language: ```go title="main.go"
+		if lang := Normalize(fields[0]); lang != "" {
+			return lang
+		}
+	}
+	trimmed := strings.TrimSpace(code)
+	if trimmed == "" {
+		return ""
+	}
+	if (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid([]byte(trimmed)) {
+		return "json"
+	}
+	for _, sig := range signatures {
+		if sig.re.MatchString(code) {
+			return sig.lang
+		}
+	}
+	return ""
+}
+
+// ForPath returns the language of a file from its name or extension.
+func ForPath(path string) string {
+	base := filepath.Base(path)
+	if lang := Normalize(base); lang != "" { // Dockerfile, Makefile
+		return lang
+	}
+	return Normalize(strings.TrimPrefix(filepath.Ext(base), "."))
+}
+
+// Block is a fenced code block in message text.
+type Block struct {
+	Tag  string
+	Code string
+}
+
+// Blocks returns the ``` and ~~~ fenced code blocks in markdown text. An
+// unclosed fence runs to the end of the text.
+func Blocks(text string) []Block {
+	var blocks []Block
+	var fence string
+	var cur *Block
+	var code []string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if cur == nil {
+			for _, f := range []string{"```", "~~~"} {
+				if strings.HasPrefix(trimmed, f) {
+					fence, cur, code = f, &Block{Tag: strings.TrimSpace(strings.TrimLeft(trimmed, f[:1]))}, nil
+					break
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			cur.Code = strings.Join(code, "\n")
+			blocks = append(blocks, *cur)
+			cur = nil
+			continue
+		}
+		code = append(code, line)
+	}
+	if cur != nil {
+		cur.Code = strings.Join(code, "\n")
+		blocks = append(blocks, *cur)
+	}
+	return blocks
+}
+
+// fileTools are the tool calls whose input carries file contents, with the
+// input fields holding the path and the code.
+var fileTools = map[string][2]string{
+	"Write":     {"file_path", "content"},
+	"Edit":      {"file_path", "new_string"},
+	"MultiEdit": {"file_path", ""},
+	"write":     {"filePath", "content"},
+	"edit":      {"filePath", "newString"},
+}
+
+// Entry returns the dominant language of the code in an entry: the one with
+// the most lines across its fenced code blocks and the files its tool calls
+// write. It returns "" for entries without recognizable code.
+func Entry(entry transcript.UnifiedEntry) string {
+	lines := make(map[string]int)
+	add := func(lang, code string) {
+		if lang != "" {
+			lines[lang] += strings.Count(code, "\n") + 1
+		}
+	}
+	for _, part := range entry.Parts {
+		switch c := part.Content.(type) {
+		case transcript.UnifiedTextContent:
+			for _, b := range Blocks(c.Text) {
+				add(Detect(b.Tag, b.Code), b.Code)
+			}
+		case transcript.UnifiedToolCall:
+			fields, ok := fileTools[c.Name]
+			if !ok {
+				continue
+			}
+			path, _ := c.Input[fields[0]].(string)
+			code, _ := c.Input[fields[1]].(string)
+			add(ForPath(path), code)
+		}
+	}
+	best := ""
+	for lang, n := range lines {
+		if n > lines[best] || (n == lines[best] && lang < best) {
+			best = lang
+		}
+	}
+	return best
+}
+
+// Counts returns how many entries have each dominant language.
+func Counts(entries []transcript.UnifiedEntry) map[string]int {
+	counts := make(map[string]int)
+	for _, e := range entries {
+		if lang := Entry(e); lang != "" {
+			counts[lang]++
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	return counts
+}
+
+// Breakdown is one language's share of the tagged entries.
+type Breakdown struct {
+	Language string `json:"language"`
+	Entries  int    `json:"entries"`
+}
+
+// Sorted orders language counts by entries, most first, then by name.
+func Sorted(counts map[string]int) []Breakdown {
+	out := make([]Breakdown, 0, len(counts))
+	for lang, n := range counts {
+		out = append(out, Breakdown{Language: lang, Entries: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Entries != out[j].Entries {
+			return out[i].Entries > out[j].Entries
+		}
+		return out[i].Language < out[j].Language
+	})
+	return out
+}
@@ -0,0 +1,61 @@
+package codelang
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct{ tag, code, want string }{
+		{"golang", "x", "go"},
+		{"tsx title=app", "", "typescript"},
+		{"", "package main\n\nfunc main() {}", "go"},
+		{"", "def handler(event):\n    return 1", "python"},
+		{"", "fn main() {\n    let mut x = 1;\n}", "rust"},
+		{"", `{"a": [1, 2]}`, "json"},
+		{"", "$ go test ./...\nok", "shell"},
+		{"", "SELECT id FROM sessions WHERE x = 1", "sql"},
+		{"", "just some prose", ""},
+		{"text", "plain", ""},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.tag, tt.code); got != tt.want {
+			t.Errorf("Detect(%q, %q) = %q, want %q", tt.tag, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	text := "Here:\n```go\nfunc a() {}\n```\nand\n~~~\nls -la\n~~~\n```py\nunclosed"
+	want := []Block{{"go", "func a() {}"}, {"", "ls -la"}, {"py", "unclosed"}}
+	if got := Blocks(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("Blocks = %+v, want %+v", got, want)
+	}
+}
+
+func TestEntryAndCounts(t *testing.T) {
+	text := func(s string) transcript.UnifiedPart {
+		return transcript.UnifiedPart{Type: "text", Content: transcript.UnifiedTextContent{Text: s}}
+	}
+	write := transcript.UnifiedPart{Type: "tool_call", Content: transcript.UnifiedToolCall{
+		Name:  "Write",
+		Input: map[string]interface{}{"file_path": "/src/app.py", "content": "a = 1\nb = 2\nc = 3"},
+	}}
+	entries := []transcript.UnifiedEntry{
+		{Role: "assistant", Parts: []transcript.UnifiedPart{text("```go\nx := 1\n```"), write}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{text("```bash\ngo build\n```")}},
+		{Role: "user", Parts: []transcript.UnifiedPart{text("no code here")}},
+	}
+	if got := Entry(entries[0]); got != "python" {
+		t.Errorf("Entry = %q, want python (3 lines written beat 1 line shown)", got)
+	}
+	want := map[string]int{"python": 1, "shell": 1}
+	if got := Counts(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("Counts = %v, want %v", got, want)
+	}
+	if got := Sorted(want); got[0].Language != "python" || got[1].Language != "shell" {
+		t.Errorf("Sorted = %+v", got)
+	}
+}
@@ -0,0 +1,292 @@
+// Package digest condenses a session, or one job of it, into a structured
+// summary written by an LLM: what the session set out to do, the decisions
+// it made, the files it changed and what it left open.
+//
+// Prompt folds already-loaded entries into the LLM's input, windowed to a
+// token budget, and Parse reads the answer back. Running the LLM is left to
+// the caller. Digests are kept under Dir, one JSON file per session or job.
+package digest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grovetools/core/pkg/paths"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// bytesPerToken estimates tokens from text size, as the monitor's
+// summaries do.
+const bytesPerToken = 3
+
+// maxLineBytes caps one message in the prompt, so a single pasted log
+// doesn't use up the window.
+const maxLineBytes = 2000
+
+// Digest is the summary of a session or of one of its jobs.
+type Digest struct {
+	SessionID string `json:"sessionId"`
+	// Job is the plan/job the digest covers; empty for a whole session.
+	Job      string `json:"job,omitempty"`
+	Provider string `json:"provider"`
+
+	Goal          string   `json:"goal"`
+	KeyDecisions  []string `json:"keyDecisions"`
+	FilesChanged  []string `json:"filesChanged"`
+	OpenQuestions []string `json:"openQuestions"`
+
+	// Omitted is the number of messages left out of the LLM's input to
+	// fit the token budget.
+	Omitted int `json:"omitted,omitempty"`
+	// Summarizer is the LLM that wrote the digest: its backend and
+	// model or command.
+	Summarizer string    `json:"summarizer"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Prompt builds the LLM input for entries: instructions asking for a JSON
+// digest, the files the session changed, and the conversation in at most
+// maxTokens (estimated). When the conversation doesn't fit, the first user
+// message, which usually states the goal, is kept along with as many of
+// the latest messages as fit; the returned count says how many were left
+// out between them.
+func Prompt(entries []transcript.UnifiedEntry, files []string, maxTokens int) (string, int) {
+	var b strings.Builder
+	b.WriteString(`Summarize this AI coding agent session for someone picking up the work.
+
+Respond with only a JSON object with these keys:
+  "goal":           one sentence on what the session set out to do
+  "key_decisions":  the design and implementation choices it made, one short sentence each
+  "open_questions": what it left unresolved or unverified, one short sentence each
+
+Use empty lists when there is nothing to report. Do not invent details the conversation doesn't show.
+`)
+	if len(files) > 0 {
+		b.WriteString("\nFiles changed:\n")
+		for _, f := range files {
+			b.WriteString("- " + f + "\n")
+		}
+	}
+	b.WriteString("\nConversation:\n\n")
+
+	lines := conversationLines(entries)
+	budget := maxTokens*bytesPerToken - b.Len()
+	kept, omitted := window(lines, budget)
+	for _, line := range kept {
+		b.WriteString(line)
+	}
+	return b.String(), omitted
+}
+
+// conversationLines renders the main conversation as one line per prompt,
+// response and tool call; tool output, reasoning and sub-agent entries are
+// left out.
+func conversationLines(entries []transcript.UnifiedEntry) []string {
+	var lines []string
+	add := func(prefix, text string) {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return
+		}
+		if len(text) > maxLineBytes {
+			text = truncate(text, maxLineBytes) + " [...]"
+		}
+		lines = append(lines, prefix+text+"\n\n")
+	}
+	for _, e := range entries {
+		if e.IsSidechain || e.Depth > 0 {
+			continue
+		}
+		for _, part := range e.Parts {
+			switch part.Type {
+			case "text":
+				switch e.Role {
+				case "user":
+					add("User: ", transcript.TextOf(part))
+				case "assistant":
+					add("Agent: ", transcript.TextOf(part))
+				}
+			case "tool_call":
+				call := transcript.ToolCallOf(part)
+				add("Agent ran "+call.Name+": ", toolSubject(call))
+			case "summary":
+				add("[", summaryLine(transcript.SummaryOf(part))+"]")
+			}
+		}
+	}
+	return lines
+}
+
+// summaryLine describes a summary part: its kind and text.
+func summaryLine(s transcript.UnifiedSummary) string {
+	if s.Text == "" {
+		return s.Kind
+	}
+	return s.Kind + ": " + s.Text
+}
+
+// window fits lines into budget bytes: all of them when they fit, else the
+// first and as many of the last as fit, with a marker between them.
+func window(lines []string, budget int) ([]string, int) {
+	total := 0
+	for _, l := range lines {
+		total += len(l)
+	}
+	if total <= budget || len(lines) < 2 {
+		return lines, 0
+	}
+	first := lines[0]
+	if limit := budget / 4; len(first) > limit {
+		first = truncate(first, limit) + " [...]\n\n"
+	}
+	budget -= len(first) + len("[... 0000 messages omitted ...]\n\n")
+	start := len(lines)
+	for start > 1 && len(lines[start-1]) <= budget {
+		start--
+		budget -= len(lines[start])
+	}
+	omitted := start - 1
+	kept := []string{first, fmt.Sprintf("[... %d messages omitted ...]\n\n", omitted)}
+	return append(kept, lines[start:]...), omitted
+}
+
+// toolSubject is the file, command or pattern a tool call worked on.
+func toolSubject(call transcript.UnifiedToolCall) string {
+	for _, key := range []string{"file_path", "path", "command", "cmd", "pattern", "url", "description"} {
+		switch v := call.Input[key].(type) {
+		case string:
+			if v != "" {
+				return strings.Join(strings.Fields(v), " ")
+			}
+		case []interface{}:
+			var args []string
+			for _, a := range v {
+				if s, ok := a.(string); ok {
+					args = append(args, s)
+				}
+			}
+			// Codex runs shell scripts as ["bash", "-lc", script].
+			if len(args) == 3 && (args[1] == "-lc" || args[1] == "-c") {
+				return strings.Join(strings.Fields(args[2]), " ")
+			}
+			if len(args) > 0 {
+				return strings.Join(args, " ")
+			}
+		}
+	}
+	return call.Title
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 sequence.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && n < len(s) && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
+
+// answer is the JSON object the prompt asks for.
+type answer struct {
+	Goal          string   `json:"goal"`
+	KeyDecisions  []string `json:"key_decisions"`
+	OpenQuestions []string `json:"open_questions"`
+}
+
+// Parse reads the LLM's answer into a digest. The JSON object may be
+// wrapped in prose or a code fence.
+func Parse(output string) (Digest, error) {
+	start, end := strings.Index(output, "{"), strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return Digest{}, fmt.Errorf("LLM answer is not a JSON object: %s", truncate(strings.TrimSpace(output), 200))
+	}
+	var a answer
+	if err := json.Unmarshal([]byte(output[start:end+1]), &a); err != nil {
+		return Digest{}, fmt.Errorf("LLM answer is not the requested JSON: %w", err)
+	}
+	if strings.TrimSpace(a.Goal) == "" {
+		return Digest{}, fmt.Errorf("LLM answer has no goal")
+	}
+	d := Digest{
+		Goal:          strings.TrimSpace(a.Goal),
+		KeyDecisions:  a.KeyDecisions,
+		OpenQuestions: a.OpenQuestions,
+	}
+	if d.KeyDecisions == nil {
+		d.KeyDecisions = []string{}
+	}
+	if d.OpenQuestions == nil {
+		d.OpenQuestions = []string{}
+	}
+	return d, nil
+}
+
+// Dir is where digests are kept, beside the monitor's summaries.
+func Dir() string {
+	return filepath.Join(paths.StateDir(), "aglogs", "digests")
+}
+
+// Key names the digest of a session, or of one job of it when job (a job
+// file name) is set.
+func Key(sessionID, job string) string {
+	if job == "" {
+		return sessionID
+	}
+	return sessionID + "." + strings.TrimSuffix(filepath.Base(job), ".md")
+}
+
+// Save writes d to dir under key.
+func Save(dir, key string, d Digest) error {
+	path, err := digestPath(dir, key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create digest directory: %w", err)
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write digest: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads the digest kept in dir under key, or returns nil when there is
+// none.
+func Load(dir, key string) (*Digest, error) {
+	path, err := digestPath(dir, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var d Digest
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("invalid digest %s: %w", path, err)
+	}
+	return &d, nil
+}
+
+func digestPath(dir, key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("invalid digest key %q", key)
+	}
+	return filepath.Join(dir, key+".json"), nil
+}
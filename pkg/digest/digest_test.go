@@ -0,0 +1,98 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func text(role, s string) transcript.UnifiedEntry {
+	return transcript.UnifiedEntry{Role: role, Parts: []transcript.UnifiedPart{{Type: "text", Content: transcript.UnifiedTextContent{Text: s}}}}
+}
+
+func TestPrompt(t *testing.T) {
+	entries := []transcript.UnifiedEntry{
+		text("user", "Add retries to the HTTP client"),
+		{Role: "assistant", Parts: []transcript.UnifiedPart{
+			{Type: "reasoning", Content: transcript.UnifiedReasoning{Text: "thinking it over"}},
+			{Type: "tool_call", Content: map[string]interface{}{"name": "exec", "input": map[string]interface{}{"command": []interface{}{"bash", "-lc", "go  test ./..."}}}},
+		}},
+		{Role: "user", Parts: []transcript.UnifiedPart{{Type: "tool_result", Content: transcript.UnifiedToolResult{Output: "ok"}}}},
+		{Role: "assistant", IsSidechain: true, Parts: []transcript.UnifiedPart{{Type: "text", Content: transcript.UnifiedTextContent{Text: "sub-agent chatter"}}}},
+		text("assistant", "Retries now back off exponentially."),
+	}
+	prompt, omitted := Prompt(entries, []string{"client.go"}, 8000)
+	if omitted != 0 {
+		t.Errorf("omitted = %d, want 0", omitted)
+	}
+	for _, want := range []string{"- client.go", "User: Add retries", "Agent ran exec: go test ./...", "Agent: Retries now back off"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt lacks %q:\n%s", want, prompt)
+		}
+	}
+	for _, unwanted := range []string{"thinking it over", "sub-agent chatter", "ok\n"} {
+		if strings.Contains(prompt, unwanted) {
+			t.Errorf("prompt has %q", unwanted)
+		}
+	}
+}
+
+func TestPromptWindow(t *testing.T) {
+	entries := []transcript.UnifiedEntry{text("user", "The goal")}
+	for i := 0; i < 100; i++ {
+		entries = append(entries, text("assistant", strings.Repeat("x", 100)))
+	}
+	entries = append(entries, text("assistant", "The end"))
+	prompt, omitted := Prompt(entries, nil, 1000)
+	if omitted == 0 || omitted >= 100 {
+		t.Fatalf("omitted = %d, want some of the middle", omitted)
+	}
+	if len(prompt) > 1000*bytesPerToken {
+		t.Errorf("prompt is %d bytes, over the %d budget", len(prompt), 1000*bytesPerToken)
+	}
+	if !strings.Contains(prompt, "User: The goal") || !strings.HasSuffix(prompt, "Agent: The end\n\n") {
+		t.Errorf("window dropped the first or last message:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "messages omitted") {
+		t.Error("no omission marker")
+	}
+}
+
+func TestParse(t *testing.T) {
+	d, err := Parse("Here you go:\n```json\n{\"goal\": \" Add retries \", \"key_decisions\": [\"Exponential backoff\"]}\n```")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Goal != "Add retries" || len(d.KeyDecisions) != 1 || d.OpenQuestions == nil {
+		t.Errorf("Parse = %+v", d)
+	}
+	for _, bad := range []string{"no JSON here", `{"goal": ""}`, `{"goal": 1}`} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): want an error", bad)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	key := Key("s1", "01-spec.md")
+	if key != "s1.01-spec" {
+		t.Errorf("Key = %q", key)
+	}
+	if d, err := Load(dir, key); err != nil || d != nil {
+		t.Fatalf("Load before Save = %v, %v", d, err)
+	}
+	want := Digest{SessionID: "s1", Job: "plan/01-spec.md", Goal: "g", CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	if err := Save(dir, key, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(dir, key)
+	if err != nil || got == nil || got.Goal != "g" || !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("Load = %+v, %v", got, err)
+	}
+	if err := Save(dir, "../x", want); err == nil {
+		t.Error("Save accepted a key with a slash")
+	}
+}
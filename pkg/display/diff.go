@@ -0,0 +1,134 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/sessiondiff"
+)
+
+// RenderSessionDiff writes a turn-by-turn comparison of two transcripts.
+// Matching actions are prefixed "=", actions only in A "-" and only in B
+// "+". Turns that did the same thing are collapsed to one line unless
+// showSame is set.
+func RenderSessionDiff(w io.Writer, res sessiondiff.Result, labelA, labelB string, style RenderStyle, showSame bool) error {
+	md := style == StyleMarkdown
+	if md {
+		fmt.Fprintf(w, "## Session diff\n\n- **A:** %s\n- **B:** %s\n\n", labelA, labelB)
+	} else {
+		fmt.Fprintf(w, "A: %s\nB: %s\n\n", labelA, labelB)
+	}
+
+	for i, td := range res.Turns {
+		title := fmt.Sprintf("Turn %d", i+1)
+		var status string
+		switch {
+		case td.A == nil:
+			status = "prompt only in B"
+		case td.B == nil:
+			status = "prompt only in A"
+		case td.Diverged:
+			status = "diverged"
+		default:
+			status = fmt.Sprintf("same (%d actions)", len(td.A.Actions))
+		}
+		prompt := td.A
+		if prompt == nil {
+			prompt = td.B
+		}
+		if md {
+			fmt.Fprintf(w, "### %s — %s\n\n> %s\n\n", title, status, diffPromptPreview(prompt.Prompt))
+		} else {
+			fmt.Fprintf(w, "%s  %s  %q\n", title, status, diffPromptPreview(prompt.Prompt))
+		}
+		if !td.Diverged && !showSame {
+			if !md {
+				fmt.Fprintln(w)
+			}
+			continue
+		}
+
+		var lines []string
+		switch {
+		case td.A == nil:
+			lines = diffActionLines("+", td.B.Actions)
+		case td.B == nil:
+			lines = diffActionLines("-", td.A.Actions)
+		default:
+			for _, op := range td.Ops {
+				lines = append(lines, diffActionLine(op.Op, op.Action))
+			}
+		}
+		if md {
+			if len(lines) > 0 {
+				fmt.Fprintf(w, "```diff\n%s\n```\n\n", strings.Join(lines, "\n"))
+			}
+		} else {
+			for _, line := range lines {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+		writeFileList(w, md, "Edited only by A", td.EditedA)
+		writeFileList(w, md, "Edited only by B", td.EditedB)
+		if !md {
+			fmt.Fprintln(w)
+		}
+	}
+
+	bullet := "  "
+	if md {
+		fmt.Fprintf(w, "### Summary\n\n")
+		bullet = "- "
+	} else {
+		fmt.Fprintln(w, "Summary")
+	}
+	if res.DivergedAt < 0 {
+		fmt.Fprintf(w, "%sNo divergence across %d turns.\n", bullet, len(res.Turns))
+	} else {
+		fmt.Fprintf(w, "%sFirst divergence at turn %d of %d.\n", bullet, res.DivergedAt+1, len(res.Turns))
+	}
+	fmt.Fprintf(w, "%sFiles edited by both: %d, only A: %d, only B: %d\n", bullet, len(res.EditedBoth), len(res.EditedA), len(res.EditedB))
+	writeFileList(w, md, "Only A", res.EditedA)
+	writeFileList(w, md, "Only B", res.EditedB)
+	return nil
+}
+
+func diffActionLines(op string, actions []sessiondiff.Action) []string {
+	lines := make([]string, len(actions))
+	for i, a := range actions {
+		lines[i] = diffActionLine(op, a)
+	}
+	return lines
+}
+
+func diffActionLine(op string, a sessiondiff.Action) string {
+	target := a.Target
+	if len(target) > 100 {
+		target = target[:97] + "..."
+	}
+	return fmt.Sprintf("%s %-6s %s", op, a.Kind, target)
+}
+
+func writeFileList(w io.Writer, md bool, label string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	if md {
+		fmt.Fprintf(w, "- **%s:** %s\n", label, strings.Join(files, ", "))
+		return
+	}
+	fmt.Fprintf(w, "  %s: %s\n", label, strings.Join(files, ", "))
+}
+
+// diffPromptPreview is the first line of a prompt, truncated.
+func diffPromptPreview(prompt string) string {
+	if prompt == "" {
+		return "(before first prompt)"
+	}
+	line, _, _ := strings.Cut(prompt, "\n")
+	if r := []rune(line); len(r) > 80 {
+		line = string(r[:77]) + "..."
+	}
+	return line
+}
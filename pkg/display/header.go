@@ -0,0 +1,116 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/grovetools/agentlogs/internal/session"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// SessionHeader is what RenderSessionHeader describes: the session itself
+// plus facts that take a transcript read to learn.
+type SessionHeader struct {
+	Info   session.SessionInfo
+	Branch string
+	// Models, Tokens and CostUSD summarize the session's recorded usage;
+	// zero when it has none.
+	Models         []string
+	Tokens         int64
+	CostUSD        float64
+	MissingPricing bool
+}
+
+// RenderSessionHeader writes a summary of a session's metadata: identity,
+// models, project, timing, usage, jobs and, when the agent reported one, its
+// execution environment. Fields that are unknown are omitted.
+func RenderSessionHeader(w io.Writer, h SessionHeader, style RenderStyle) error {
+	info := h.Info
+	type field struct{ key, value string }
+	var fields []field
+	add := func(key, value string) {
+		if value != "" && value != "unknown" {
+			fields = append(fields, field{key, value})
+		}
+	}
+
+	add("Provider", info.Provider)
+	add("Models", strings.Join(h.Models, ", "))
+	add("Project", info.ProjectName)
+	add("Worktree", info.Worktree)
+	add("Branch", h.Branch)
+	add("Commit", info.GitCommit)
+	add("Ecosystem", info.Ecosystem)
+	add("User", info.User)
+	add("Status", info.Status)
+	if !info.StartedAt.IsZero() {
+		add("Started", info.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	if !info.EndedAt.IsZero() {
+		add("Ended", info.EndedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	if d := info.Duration(); d > 0 {
+		add("Duration", formatSessionDuration(d))
+	}
+	if h.Tokens > 0 {
+		usage := fmt.Sprintf("%d tokens, $%.2f", h.Tokens, h.CostUSD)
+		if h.MissingPricing {
+			usage += " (some models unpriced)"
+		}
+		add("Usage", usage)
+	}
+	jobs := make([]string, 0, len(info.Jobs))
+	for _, job := range info.Jobs {
+		jobs = append(jobs, job.Plan+"/"+job.Job)
+	}
+	add("Jobs", strings.Join(jobs, ", "))
+	if env := info.Environment; env != nil {
+		add("Cwd", env.Cwd)
+		add("Shell", env.Shell)
+		add("Approval", env.ApprovalPolicy)
+		add("Sandbox", env.SandboxMode)
+		add("Network", env.NetworkAccess)
+	}
+	add("Log file", info.LogFilePath)
+
+	if style == StyleMarkdown {
+		fmt.Fprintf(w, "## Session %s\n\n", info.SessionID)
+		for _, f := range fields {
+			fmt.Fprintf(w, "- **%s:** %s\n", f.key, f.value)
+		}
+		_, err := fmt.Fprintln(w)
+		return err
+	}
+
+	fmt.Fprintf(w, "Session %s\n", info.SessionID)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, f := range fields {
+		fmt.Fprintf(tw, "  %s:\t%s\n", f.key, f.value)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// RenderPlanDocument writes a proposed plan as a standalone Markdown
+// document: a title, one line of provenance, and the plan text.
+func RenderPlanDocument(w io.Writer, plan transcript.ProposedPlan, n int, info session.SessionInfo) error {
+	details := []string{"session " + info.SessionID}
+	for _, v := range []string{info.Provider, info.ProjectName} {
+		if v != "" && v != "unknown" {
+			details = append(details, v)
+		}
+	}
+	if !plan.Timestamp.IsZero() {
+		details = append(details, plan.Timestamp.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+	if plan.Outcome != "" {
+		details = append(details, plan.Outcome)
+	}
+	_, err := fmt.Fprintf(w, "# Plan %d\n\n_%s_\n\n%s\n", n, strings.Join(details, " · "), plan.Text)
+	return err
+}
@@ -0,0 +1,113 @@
+package display
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// QuoteSource identifies where a quoted excerpt came from. First and Last
+// are the 1-based entry indexes of the excerpt within the transcript.
+type QuoteSource struct {
+	SessionID   string
+	Provider    string
+	Project     string
+	LogFilePath string
+	First       int
+	Last        int
+}
+
+// RenderQuote writes entries as a Markdown blockquote followed by an
+// attribution line linking back to the session transcript, ready to paste
+// into docs. The excerpt itself uses the markdown render style.
+func RenderQuote(w io.Writer, entries []transcript.UnifiedEntry, src QuoteSource, detailLevel string) error {
+	var buf bytes.Buffer
+	opts := RenderOptions{Style: StyleMarkdown, DetailLevel: detailLevel}
+	for _, entry := range entries {
+		if err := renderMarkdownEntry(&buf, entry, opts); err != nil {
+			return err
+		}
+	}
+
+	var out strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		if line == "" {
+			out.WriteString(">\n")
+		} else {
+			out.WriteString("> " + line + "\n")
+		}
+	}
+	out.WriteString(">\n> — " + quoteAttribution(entries, src) + "\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func quoteAttribution(entries []transcript.UnifiedEntry, src QuoteSource) string {
+	label := "session " + shortSessionID(src.SessionID)
+	if src.LogFilePath != "" {
+		link := url.URL{Scheme: "file", Path: src.LogFilePath}
+		label = fmt.Sprintf("[%s](%s)", label, link.String())
+	}
+
+	details := []string{label}
+	if src.Provider != "" {
+		details = append(details, src.Provider)
+	}
+	if src.Project != "" {
+		details = append(details, src.Project)
+	}
+	for _, entry := range entries {
+		if !entry.Timestamp.IsZero() {
+			details = append(details, entry.Timestamp.UTC().Format("2006-01-02 15:04 UTC"))
+			break
+		}
+	}
+	if src.First == src.Last {
+		details = append(details, fmt.Sprintf("#%d", src.First))
+	} else {
+		details = append(details, fmt.Sprintf("#%d–#%d", src.First, src.Last))
+	}
+	return strings.Join(details, " · ")
+}
+
+func shortSessionID(id string) string {
+	if id == "" {
+		return "unknown"
+	}
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// QuotePreview returns a one-line summary of an entry for picking quote
+// indexes: the first line of its text, or the names of the tools it called.
+func QuotePreview(entry transcript.UnifiedEntry, width int) string {
+	var preview string
+	var tools []string
+	for _, part := range entry.Parts {
+		switch part.Type {
+		case "text":
+			if preview == "" {
+				preview = strings.TrimSpace(partText(part))
+			}
+		case "tool_call":
+			tools = append(tools, partToolCall(part).Name)
+		}
+	}
+	if preview == "" && len(tools) > 0 {
+		preview = "[" + strings.Join(tools, ", ") + "]"
+	}
+	if i := strings.IndexByte(preview, '\n'); i >= 0 {
+		preview = preview[:i]
+	}
+	if width > 0 && len([]rune(preview)) > width {
+		preview = string([]rune(preview)[:width-1]) + "…"
+	}
+	return preview
+}
@@ -39,6 +39,10 @@ type RenderOptions struct {
 	Style RenderStyle
 	// DetailLevel is "summary" or "full".
 	DetailLevel string
+	// RawEscapes prints transcript text as is. By default escape sequences
+	// and control characters in it are removed or made visible (see
+	// SanitizeText), so tool output cannot restyle the terminal.
+	RawEscapes bool
 }
 
 // ParseRenderStyle validates a style string (e.g. from a CLI flag).
@@ -63,6 +67,19 @@ func RenderUnifiedEntry(
 	opts RenderOptions,
 	toolFormatters map[string]formatters.ToolFormatter,
 ) error {
+	if entry.Depth > 0 {
+		// Sub-agent entries render indented under the call that spawned them.
+		var buf bytes.Buffer
+		nested := entry
+		nested.Depth = 0
+		if err := RenderUnifiedEntry(&buf, nested, opts, toolFormatters); err != nil {
+			return err
+		}
+		return writeNested(w, buf.String(), entry.Depth, opts.Style)
+	}
+	if !opts.RawEscapes {
+		entry = sanitizeEntry(entry)
+	}
 	switch opts.Style {
 	case StyleMarkdown:
 		return renderMarkdownEntry(w, entry, opts)
@@ -71,6 +88,37 @@ func RenderUnifiedEntry(
 	}
 }
 
+// writeNested writes rendered output indented depth levels: a muted bar
+// per level in terminal style, a blockquote level in markdown.
+func writeNested(w io.Writer, rendered string, depth int, style RenderStyle) error {
+	prefix := strings.Repeat(lipgloss.NewStyle().Foreground(theme.DefaultColors.MutedText).Render("│")+"  ", depth)
+	if style == StyleMarkdown {
+		prefix = strings.Repeat("> ", depth)
+	}
+	var buf strings.Builder
+	for _, line := range strings.SplitAfter(rendered, "\n") {
+		if line == "" {
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			buf.WriteString(strings.TrimRight(prefix, " ") + "\n")
+			continue
+		}
+		buf.WriteString(prefix + line)
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// subagentHeader labels the start of a sub-agent's nested entries.
+func subagentHeader(agentID string, style RenderStyle) string {
+	if style == StyleMarkdown {
+		return fmt.Sprintf("**Sub-agent %s**\n\n", agentID)
+	}
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.DefaultColors.MutedText)
+	return mutedStyle.Render("↳ sub-agent "+agentID) + "\n\n"
+}
+
 // RenderUnifiedTranscript renders a full transcript to w.
 func RenderUnifiedTranscript(
 	w io.Writer,
@@ -78,7 +126,17 @@ func RenderUnifiedTranscript(
 	opts RenderOptions,
 	toolFormatters map[string]formatters.ToolFormatter,
 ) error {
+	var agent string
 	for _, entry := range entries {
+		if entry.Depth > 0 && entry.AgentID != agent {
+			if err := writeNested(w, subagentHeader(entry.AgentID, opts.Style), entry.Depth, opts.Style); err != nil {
+				return err
+			}
+		}
+		agent = ""
+		if entry.Depth > 0 {
+			agent = entry.AgentID
+		}
 		if err := RenderUnifiedEntry(w, entry, opts, toolFormatters); err != nil {
 			return err
 		}
@@ -134,6 +192,9 @@ func renderTerminalEntry(
 		var hasToolResults bool
 
 		for _, part := range entry.Parts {
+			if renderTerminalEventPart(w, part, mutedStyle) {
+				continue
+			}
 			switch part.Type {
 			case "text":
 				if content, ok := part.Content.(transcript.UnifiedTextContent); ok && content.Text != "" {
@@ -143,6 +204,8 @@ func renderTerminalEntry(
 						textParts = append(textParts, text)
 					}
 				}
+			case "image":
+				textParts = append(textParts, partImage(part).Placeholder())
 			case "tool_result":
 				// Show tool results with tree connector (these belong to previous tool call)
 				output := partToolResultOutput(part)
@@ -166,6 +229,10 @@ func renderTerminalEntry(
 						}
 					}
 				}
+				if images := partToolResultImages(part); len(images) > 0 {
+					hasToolResults = true
+					writeImagePlaceholders(w, images, output == "", tree, mutedStyle)
+				}
 			}
 		}
 
@@ -179,8 +246,11 @@ func renderTerminalEntry(
 		return nil
 	}
 
-	// For assistant messages, render parts in order to preserve interleaving
+	// For assistant (and system) messages, render parts in order to preserve interleaving
 	for _, part := range entry.Parts {
+		if renderTerminalEventPart(w, part, mutedStyle) {
+			continue
+		}
 		switch part.Type {
 		case "text":
 			text := partText(part)
@@ -188,6 +258,9 @@ func renderTerminalEntry(
 				fmt.Fprintf(w, "%s %s\n\n", robotTextIcon, text)
 			}
 
+		case "image":
+			fmt.Fprintf(w, "%s %s\n\n", robotTextIcon, mutedStyle.Render(partImage(part).Placeholder()))
+
 		case "tool_call":
 			toolCall := partToolCall(part)
 
@@ -197,11 +270,12 @@ func renderTerminalEntry(
 			}
 
 			// Show output with tree connector (for embedded output like OpenCode or merged Claude)
-			if toolCall.Output != "" {
+			if toolCall.Output != "" || len(toolCall.Images) > 0 {
 				outputDisplay := formatToolOutput(toolCall.Name, toolCall.Output, mutedStyle)
 				if outputDisplay != "" {
 					fmt.Fprintf(w, "  %s  %s\n", tree, mutedStyle.Render(outputDisplay))
 				}
+				writeImagePlaceholders(w, toolCall.Images, outputDisplay == "", tree, mutedStyle)
 				// Add blank line after embedded output (OpenCode or merged Claude results)
 				fmt.Fprintln(w)
 			}
@@ -245,12 +319,156 @@ func renderTerminalEntry(
 					}
 				}
 			}
+			writeImagePlaceholders(w, partToolResultImages(part), output == "", tree, mutedStyle)
 			fmt.Fprintln(w) // Blank line after tool result (even if empty)
 		}
 	}
 	return nil
 }
 
+// writeImagePlaceholders writes a tool output's images as placeholder
+// lines under it, the first with the tree connector when there was no text
+// output above.
+func writeImagePlaceholders(w io.Writer, images []transcript.UnifiedImage, first bool, tree string, mutedStyle lipgloss.Style) {
+	for _, img := range images {
+		if first {
+			fmt.Fprintf(w, "  %s  %s\n", tree, mutedStyle.Render(img.Placeholder()))
+			first = false
+		} else {
+			fmt.Fprintf(w, "     %s\n", mutedStyle.Render(img.Placeholder()))
+		}
+	}
+}
+
+// renderTerminalEventPart renders slash commands, their local output, hook
+// runs and summary dividers as dimmed lines, since they are CLI events
+// rather than conversation. It reports false for other part types.
+func renderTerminalEventPart(w io.Writer, part transcript.UnifiedPart, mutedStyle lipgloss.Style) bool {
+	tree := mutedStyle.Render(treeChar)
+	switch part.Type {
+	case "command":
+		cmd := partCommand(part)
+		fmt.Fprintf(w, "%s\n\n", mutedStyle.Render(strings.TrimSpace("$ "+cmd.Name+" "+cmd.Args)))
+	case "command_output":
+		out := partCommandOutput(part)
+		for i, line := range strings.Split(strings.TrimSpace(out.Output), "\n") {
+			if i == 0 {
+				fmt.Fprintf(w, "  %s  %s\n", tree, mutedStyle.Render(line))
+			} else {
+				fmt.Fprintf(w, "     %s\n", mutedStyle.Render(line))
+			}
+		}
+		fmt.Fprintln(w)
+	case "hook":
+		hook := partHook(part)
+		line := "hook " + hook.Event
+		if hook.Command != "" {
+			line += " [" + hook.Command + "]"
+		}
+		if hook.IsError {
+			line += " (error)"
+		}
+		fmt.Fprintln(w, mutedStyle.Italic(true).Render(line))
+		if output := strings.TrimSpace(hook.Output); output != "" {
+			lines := strings.Split(output, "\n")
+			if len(lines) > 5 {
+				fmt.Fprintf(w, "  %s  %s\n", tree, mutedStyle.Render(fmt.Sprintf("(%d lines)", len(lines))))
+			} else {
+				fmt.Fprintf(w, "  %s  %s\n", tree, mutedStyle.Render(strings.Join(lines, "\n     ")))
+			}
+		}
+		fmt.Fprintln(w)
+	case "plan_mode":
+		fmt.Fprintf(w, "%s\n\n", mutedStyle.Render(planModeDivider(partPlanMode(part))))
+	case "summary":
+		summary := partSummary(part)
+		fmt.Fprintln(w, mutedStyle.Render(summaryDivider(summary)))
+		if summary.Kind == transcript.SummaryCompaction && summary.Text != "" {
+			lines := strings.Split(summary.Text, "\n")
+			fmt.Fprintf(w, "  %s  %s\n", tree, mutedStyle.Render(fmt.Sprintf("(%d-line summary)", len(lines))))
+		}
+		fmt.Fprintln(w)
+	case "tool_call":
+		tc := partToolCall(part)
+		switch tc.Name {
+		case transcript.ToolEnterPlanMode:
+			fmt.Fprintf(w, "%s\n\n", mutedStyle.Render(planDivider("planning")))
+		case transcript.ToolExitPlanMode:
+			fmt.Fprintf(w, "%s\n\n", mutedStyle.Render(planDivider("proposed plan")))
+			if text := transcript.PlanText(tc); text != "" {
+				fmt.Fprintf(w, "%s\n\n", text)
+			}
+			fmt.Fprintf(w, "%s\n\n", mutedStyle.Render(planEndDivider(transcript.PlanOutcome(tc))))
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// planDivider is the section rule marking plan-mode transitions.
+func planDivider(label string) string {
+	return "── " + label + " ──"
+}
+
+func planModeDivider(active bool) string {
+	if active {
+		return planDivider("planning")
+	}
+	return planDivider("planning ended")
+}
+
+// summaryDivider labels a conversation title or a compaction, e.g.
+// "── conversation compacted (auto, 155k tokens) ──".
+func summaryDivider(summary transcript.UnifiedSummary) string {
+	if summary.Kind != transcript.SummaryCompaction {
+		return planDivider(summary.Text)
+	}
+	var details []string
+	if summary.Trigger != "" {
+		details = append(details, summary.Trigger)
+	}
+	if summary.PreTokens > 0 {
+		details = append(details, fmt.Sprintf("%dk tokens", (summary.PreTokens+500)/1000))
+	}
+	if len(details) == 0 {
+		return planDivider("conversation compacted")
+	}
+	return planDivider("conversation compacted (" + strings.Join(details, ", ") + ")")
+}
+
+func planEndDivider(outcome string) string {
+	if outcome == "" {
+		return planDivider("planning ended")
+	}
+	return planDivider("planning ended (" + outcome + ")")
+}
+
+// RenderPlans writes the plans proposed in a session, each under a divider
+// with its time and outcome.
+func RenderPlans(w io.Writer, plans []transcript.ProposedPlan, style RenderStyle) error {
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.DefaultColors.MutedText)
+	for i, plan := range plans {
+		label := fmt.Sprintf("plan %d", i+1)
+		if !plan.Timestamp.IsZero() {
+			label += " · " + plan.Timestamp.Local().Format("2006-01-02 15:04")
+		}
+		if plan.Outcome != "" {
+			label += " · " + plan.Outcome
+		}
+		divider := planDivider(label)
+		if style != StyleMarkdown {
+			divider = mutedStyle.Render(divider)
+		}
+		if _, err := fmt.Fprintf(w, "%s\n\n%s\n\n", divider, plan.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // --- Markdown style ---
 
 // renderMarkdownEntry renders an entry as environment-independent markdown:
@@ -264,13 +482,41 @@ func renderMarkdownEntry(w io.Writer, entry transcript.UnifiedEntry, opts Render
 	}
 
 	for _, part := range entry.Parts {
+		if part.Type == "tool_call" {
+			switch tc := partToolCall(part); tc.Name {
+			case transcript.ToolEnterPlanMode:
+				fmt.Fprintf(w, "%s\n\n", planDivider("planning"))
+				continue
+			case transcript.ToolExitPlanMode:
+				fmt.Fprintf(w, "%s\n\n", planDivider("proposed plan"))
+				if text := transcript.PlanText(tc); text != "" {
+					fmt.Fprintf(w, "%s\n\n", text)
+				}
+				fmt.Fprintf(w, "%s\n\n", planEndDivider(transcript.PlanOutcome(tc)))
+				continue
+			}
+		}
 		switch part.Type {
+		case "plan_mode":
+			fmt.Fprintf(w, "%s\n\n", planModeDivider(partPlanMode(part)))
+
+		case "summary":
+			summary := partSummary(part)
+			fmt.Fprintf(w, "%s\n\n", summaryDivider(summary))
+			if summary.Kind == transcript.SummaryCompaction && summary.Text != "" {
+				writeIndentedBlock(w, summary.Text, opts.DetailLevel)
+				fmt.Fprintln(w)
+			}
+
 		case "text":
 			text := partText(part)
 			if text != "" {
 				fmt.Fprintf(w, "%s\n\n%s\n\n", roleLabel, text)
 			}
 
+		case "image":
+			fmt.Fprintf(w, "%s\n\n%s\n\n", roleLabel, partImage(part).Placeholder())
+
 		case "reasoning":
 			text := partReasoningText(part)
 			if text != "" {
@@ -297,6 +543,7 @@ func renderMarkdownEntry(w io.Writer, entry transcript.UnifiedEntry, opts Render
 				writeIndentedBlock(w, toolCall.Output, opts.DetailLevel)
 				fmt.Fprintln(w)
 			}
+			writeMarkdownImages(w, toolCall.Images)
 
 		case "tool_result":
 			output := partToolResultOutput(part)
@@ -305,11 +552,52 @@ func renderMarkdownEntry(w io.Writer, entry transcript.UnifiedEntry, opts Render
 				writeIndentedBlock(w, output, opts.DetailLevel)
 				fmt.Fprintln(w)
 			}
+			writeMarkdownImages(w, partToolResultImages(part))
+
+		case "command":
+			cmd := partCommand(part)
+			fmt.Fprintf(w, "*Command:*\n\n")
+			writeIndentedBlock(w, strings.TrimSpace(cmd.Name+" "+cmd.Args), opts.DetailLevel)
+			fmt.Fprintln(w)
+
+		case "command_output":
+			out := partCommandOutput(part)
+			label := "*Command Output:*"
+			if out.IsError {
+				label = "*Command Error:*"
+			}
+			fmt.Fprintf(w, "%s\n\n", label)
+			writeIndentedBlock(w, out.Output, opts.DetailLevel)
+			fmt.Fprintln(w)
+
+		case "hook":
+			hook := partHook(part)
+			label := "*Hook: " + hook.Event
+			if hook.IsError {
+				label += " (error)"
+			}
+			fmt.Fprintf(w, "%s*\n\n", label)
+			if text := strings.TrimSpace(strings.Join([]string{hook.Command, hook.Output}, "\n")); text != "" {
+				writeIndentedBlock(w, text, opts.DetailLevel)
+				fmt.Fprintln(w)
+			}
 		}
 	}
 	return nil
 }
 
+// writeMarkdownImages writes a tool output's images as placeholders.
+func writeMarkdownImages(w io.Writer, images []transcript.UnifiedImage) {
+	if len(images) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "**Tool Images:**\n\n")
+	for _, img := range images {
+		fmt.Fprintf(w, "    %s\n", img.Placeholder())
+	}
+	fmt.Fprintln(w)
+}
+
 // writeIndentedBlock writes text as a 4-space-indented preformatted markdown
 // block. Indenting (instead of fencing) is injection-safe: content containing
 // triple backticks cannot break out of the block. Output is capped at
@@ -379,6 +667,118 @@ func partToolResultOutput(part transcript.UnifiedPart) string {
 	return ""
 }
 
+// partToolResultImages extracts the images of a "tool_result" part.
+func partToolResultImages(part transcript.UnifiedPart) []transcript.UnifiedImage {
+	if content, ok := part.Content.(transcript.UnifiedToolResult); ok {
+		return content.Images
+	}
+	if contentMap, ok := part.Content.(map[string]interface{}); ok {
+		return mapImages(contentMap["images"])
+	}
+	return nil
+}
+
+// partImage extracts a UnifiedImage from an "image" part. After a JSON
+// round-trip only its media type and size remain.
+func partImage(part transcript.UnifiedPart) transcript.UnifiedImage {
+	if content, ok := part.Content.(transcript.UnifiedImage); ok {
+		return content
+	}
+	if contentMap, ok := part.Content.(map[string]interface{}); ok {
+		return mapImage(contentMap)
+	}
+	return transcript.UnifiedImage{}
+}
+
+func mapImage(m map[string]interface{}) transcript.UnifiedImage {
+	size, _ := m["size"].(float64)
+	return transcript.UnifiedImage{MediaType: getStringField(m, "mediaType"), Size: int(size)}
+}
+
+// mapImages decodes a JSON-decoded "images" list.
+func mapImages(v interface{}) []transcript.UnifiedImage {
+	list, _ := v.([]interface{})
+	var images []transcript.UnifiedImage
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			images = append(images, mapImage(m))
+		}
+	}
+	return images
+}
+
+// partCommand extracts a UnifiedCommand from a "command" part.
+func partCommand(part transcript.UnifiedPart) transcript.UnifiedCommand {
+	if content, ok := part.Content.(transcript.UnifiedCommand); ok {
+		return content
+	}
+	if contentMap, ok := part.Content.(map[string]interface{}); ok {
+		return transcript.UnifiedCommand{
+			Name: getStringField(contentMap, "name"),
+			Args: getStringField(contentMap, "args"),
+		}
+	}
+	return transcript.UnifiedCommand{}
+}
+
+// partCommandOutput extracts a UnifiedCommandOutput from a "command_output" part.
+func partCommandOutput(part transcript.UnifiedPart) transcript.UnifiedCommandOutput {
+	if content, ok := part.Content.(transcript.UnifiedCommandOutput); ok {
+		return content
+	}
+	if contentMap, ok := part.Content.(map[string]interface{}); ok {
+		isError, _ := contentMap["isError"].(bool)
+		return transcript.UnifiedCommandOutput{Output: getStringField(contentMap, "output"), IsError: isError}
+	}
+	return transcript.UnifiedCommandOutput{}
+}
+
+// partPlanMode reports whether a "plan_mode" part enters plan mode.
+func partPlanMode(part transcript.UnifiedPart) bool {
+	if content, ok := part.Content.(transcript.UnifiedPlanMode); ok {
+		return content.Active
+	}
+	if contentMap, ok := part.Content.(map[string]interface{}); ok {
+		active, _ := contentMap["active"].(bool)
+		return active
+	}
+	return false
+}
+
+// partSummary extracts a UnifiedSummary from a "summary" part.
+func partSummary(part transcript.UnifiedPart) transcript.UnifiedSummary {
+	if content, ok := part.Content.(transcript.UnifiedSummary); ok {
+		return content
+	}
+	if contentMap, ok := part.Content.(map[string]interface{}); ok {
+		preTokens, _ := contentMap["preTokens"].(float64)
+		return transcript.UnifiedSummary{
+			Kind:      getStringField(contentMap, "kind"),
+			Text:      getStringField(contentMap, "text"),
+			Trigger:   getStringField(contentMap, "trigger"),
+			PreTokens: int(preTokens),
+		}
+	}
+	return transcript.UnifiedSummary{}
+}
+
+// partHook extracts a UnifiedHook from a "hook" part.
+func partHook(part transcript.UnifiedPart) transcript.UnifiedHook {
+	if content, ok := part.Content.(transcript.UnifiedHook); ok {
+		return content
+	}
+	if contentMap, ok := part.Content.(map[string]interface{}); ok {
+		isError, _ := contentMap["isError"].(bool)
+		return transcript.UnifiedHook{
+			Event:   getStringField(contentMap, "event"),
+			Command: getStringField(contentMap, "command"),
+			Output:  getStringField(contentMap, "output"),
+			IsError: isError,
+		}
+	}
+	return transcript.UnifiedHook{}
+}
+
 // partToolCall extracts a UnifiedToolCall from a "tool_call" part.
 func partToolCall(part transcript.UnifiedPart) transcript.UnifiedToolCall {
 	if content, ok := part.Content.(transcript.UnifiedToolCall); ok {
@@ -396,6 +796,7 @@ func partToolCall(part transcript.UnifiedPart) transcript.UnifiedToolCall {
 		if input, ok := contentMap["input"].(map[string]interface{}); ok {
 			toolCall.Input = input
 		}
+		toolCall.Images = mapImages(contentMap["images"])
 		return toolCall
 	}
 	return transcript.UnifiedToolCall{}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/grovetools/agentlogs/internal/session"
 	"github.com/grovetools/agentlogs/pkg/transcript"
 )
 
@@ -228,3 +229,178 @@ func TestParseRenderStyle(t *testing.T) {
 		t.Errorf("expected error for unknown style")
 	}
 }
+
+// TestRenderQuote verifies every excerpt line is blockquoted and the
+// attribution links back to the transcript with the quoted index range.
+func TestRenderQuote(t *testing.T) {
+	user := transcript.UnifiedEntry{
+		Role:  "user",
+		Parts: []transcript.UnifiedPart{{Type: "text", Content: transcript.UnifiedTextContent{Text: "Why is the build red?"}}},
+	}
+	var buf bytes.Buffer
+	src := QuoteSource{
+		SessionID:   "0123456789abcdef",
+		Provider:    "claude",
+		Project:     "agentlogs",
+		LogFilePath: "/home/u/.claude/projects/x/0123456789abcdef.jsonl",
+		First:       4,
+		Last:        5,
+	}
+	if err := RenderQuote(&buf, []transcript.UnifiedEntry{user, sampleEntry()}, src, "summary"); err != nil {
+		t.Fatalf("RenderQuote failed: %v", err)
+	}
+	out := buf.String()
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if !strings.HasPrefix(line, ">") {
+			t.Errorf("line not blockquoted: %q", line)
+		}
+	}
+	if !strings.Contains(out, "> **User:**") || !strings.Contains(out, "Why is the build red?") {
+		t.Errorf("missing quoted user text:\n%s", out)
+	}
+	want := "> — [session 01234567](file:///home/u/.claude/projects/x/0123456789abcdef.jsonl) · claude · agentlogs · #4–#5"
+	if !strings.Contains(out, want) {
+		t.Errorf("attribution mismatch, want %q in:\n%s", want, out)
+	}
+}
+
+func TestRenderSessionHeader(t *testing.T) {
+	h := SessionHeader{
+		Info: session.SessionInfo{
+			SessionID:   "rollout-1",
+			Provider:    "codex",
+			ProjectName: "unknown",
+			Environment: &session.AgentEnvironment{Shell: "zsh", SandboxMode: "workspace-write", NetworkAccess: "restricted"},
+		},
+		Branch:  "main",
+		Models:  []string{"gpt-5-codex"},
+		Tokens:  1234,
+		CostUSD: 0.5,
+	}
+	var buf bytes.Buffer
+	if err := RenderSessionHeader(&buf, h, StyleMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"## Session rollout-1", "- **Provider:** codex", "- **Shell:** zsh", "- **Sandbox:** workspace-write", "- **Network:** restricted",
+		"- **Branch:** main", "- **Models:** gpt-5-codex", "- **Usage:** 1234 tokens, $0.50"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("header missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Project") {
+		t.Errorf("header shows unknown project:\n%s", out)
+	}
+}
+
+func TestMarkdownCommandAndHookParts(t *testing.T) {
+	entries := []transcript.UnifiedEntry{
+		{Role: "user", Parts: []transcript.UnifiedPart{
+			{Type: "command", Content: transcript.UnifiedCommand{Name: "/model", Args: "opus"}},
+		}},
+		{Role: "system", Parts: []transcript.UnifiedPart{
+			{Type: "hook", Content: map[string]interface{}{"event": "Stop", "command": "notify.sh", "isError": true}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := RenderUnifiedTranscript(&buf, entries, RenderOptions{Style: StyleMarkdown}, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"*Command:*\n\n    /model opus\n", "*Hook: Stop (error)*\n\n    notify.sh\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSummaryDividers(t *testing.T) {
+	entries := []transcript.UnifiedEntry{
+		{Role: "system", Parts: []transcript.UnifiedPart{
+			{Type: "summary", Content: transcript.UnifiedSummary{Kind: transcript.SummaryTitle, Text: "Refactor the scanner"}},
+		}},
+		{Role: "system", Parts: []transcript.UnifiedPart{
+			{Type: "summary", Content: map[string]interface{}{"kind": "compaction", "trigger": "auto", "preTokens": float64(155012)}},
+		}},
+		{Role: "system", Parts: []transcript.UnifiedPart{
+			{Type: "summary", Content: transcript.UnifiedSummary{Kind: transcript.SummaryCompaction, Text: "Earlier: split the scanner.\nNext: tests."}},
+		}},
+	}
+
+	var md bytes.Buffer
+	if err := RenderUnifiedTranscript(&md, entries, RenderOptions{Style: StyleMarkdown}, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"── Refactor the scanner ──\n",
+		"── conversation compacted (auto, 155k tokens) ──\n",
+		"── conversation compacted ──\n\n    Earlier: split the scanner.\n    Next: tests.\n",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var term bytes.Buffer
+	if err := RenderUnifiedTranscript(&term, entries, RenderOptions{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Refactor the scanner", "conversation compacted (auto, 155k tokens)", "(2-line summary)"} {
+		if !strings.Contains(term.String(), want) {
+			t.Errorf("terminal output missing %q:\n%s", want, term.String())
+		}
+	}
+}
+
+func TestImagePlaceholders(t *testing.T) {
+	entries := []transcript.UnifiedEntry{
+		{Role: "user", Parts: []transcript.UnifiedPart{
+			{Type: "image", Content: transcript.UnifiedImage{MediaType: "image/png", Size: 1_200_000}},
+		}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{
+			// A tool call after a JSON round-trip keeps only the metadata.
+			{Type: "tool_call", Content: map[string]interface{}{
+				"name":   "Screenshot",
+				"images": []interface{}{map[string]interface{}{"mediaType": "image/jpeg", "size": float64(48000)}},
+			}},
+		}},
+	}
+	for _, style := range []RenderStyle{StyleMarkdown, StyleTerminal} {
+		var buf bytes.Buffer
+		if err := RenderUnifiedTranscript(&buf, entries, RenderOptions{Style: style}, nil); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{"[image: 1.2MB png]", "[image: 48KB jpg]"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%v output missing %q:\n%s", style, want, buf.String())
+			}
+		}
+	}
+}
+
+func TestNestedSubagentEntries(t *testing.T) {
+	entries := []transcript.UnifiedEntry{
+		{Role: "assistant", Parts: []transcript.UnifiedPart{
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: "t1", Name: "Task", Input: map[string]interface{}{"description": "review"}}},
+		}},
+		{Role: "assistant", AgentID: "aaa", IsSidechain: true, Depth: 1, Parts: []transcript.UnifiedPart{
+			{Type: "text", Content: transcript.UnifiedTextContent{Text: "sub-agent reply"}},
+		}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{
+			{Type: "text", Content: transcript.UnifiedTextContent{Text: "main reply"}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := RenderUnifiedTranscript(&buf, entries, RenderOptions{Style: StyleMarkdown}, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"> **Sub-agent aaa**\n", "> sub-agent reply\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "> main reply") {
+		t.Errorf("main transcript entry nested:\n%s", out)
+	}
+}
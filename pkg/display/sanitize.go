@@ -0,0 +1,126 @@
+package display
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// SanitizeText makes transcript text safe to print to a terminal. Tool
+// output often carries ANSI escapes (colors, cursor movement, screen
+// clears) and sometimes binary garbage, which would restyle or wreck the
+// rendered transcript. Escape sequences are removed, invalid UTF-8 becomes
+// U+FFFD, carriage returns become line breaks, and other control
+// characters are shown as their Unicode control pictures (␀, ␇, ␡).
+// Newlines and tabs are kept.
+func SanitizeText(s string) string {
+	if isPlainText(s) {
+		return s
+	}
+	s = ansi.Strip(strings.ToValidUTF8(s, "�"))
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case r == '\r':
+			b.WriteByte('\n')
+		case r < 0x20:
+			b.WriteRune(0x2400 + r)
+		case r == 0x7f:
+			b.WriteRune('␡')
+		case r >= 0x80 && r < 0xa0:
+			// C1 controls have no picture; drop them.
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isPlainText reports whether s is valid UTF-8 without control characters
+// other than newlines and tabs, the common case that needs no copying.
+func isPlainText(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if (r < 0x20 && r != '\n' && r != '\t') || (r >= 0x7f && r < 0xa0) {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizeEntry returns a copy of entry with SanitizeText applied to every
+// string it renders.
+func sanitizeEntry(entry transcript.UnifiedEntry) transcript.UnifiedEntry {
+	parts := make([]transcript.UnifiedPart, len(entry.Parts))
+	for i, part := range entry.Parts {
+		part.Content = sanitizeContent(part.Content)
+		parts[i] = part
+	}
+	entry.Parts = parts
+	return entry
+}
+
+func sanitizeContent(content interface{}) interface{} {
+	switch c := content.(type) {
+	case string:
+		return SanitizeText(c)
+	case transcript.UnifiedTextContent:
+		c.Text = SanitizeText(c.Text)
+		return c
+	case transcript.UnifiedReasoning:
+		c.Text = SanitizeText(c.Text)
+		return c
+	case transcript.UnifiedToolCall:
+		c.Input = sanitizeMap(c.Input)
+		c.Output = SanitizeText(c.Output)
+		c.Title = SanitizeText(c.Title)
+		c.Diff = SanitizeText(c.Diff)
+		return c
+	case transcript.UnifiedToolResult:
+		c.Output = SanitizeText(c.Output)
+		return c
+	case transcript.UnifiedCommand:
+		c.Args = SanitizeText(c.Args)
+		return c
+	case transcript.UnifiedCommandOutput:
+		c.Output = SanitizeText(c.Output)
+		return c
+	case transcript.UnifiedHook:
+		c.Command = SanitizeText(c.Command)
+		c.Output = SanitizeText(c.Output)
+		return c
+	case transcript.UnifiedSummary:
+		c.Text = SanitizeText(c.Text)
+		return c
+	case map[string]interface{}:
+		return sanitizeMap(c)
+	case []interface{}:
+		out := make([]interface{}, len(c))
+		for i, v := range c {
+			out[i] = sanitizeContent(v)
+		}
+		return out
+	default:
+		return content
+	}
+}
+
+func sanitizeMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = sanitizeContent(v)
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package display
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"plain\ttext\nü", "plain\ttext\nü"},
+		{"\x1b[31mred\x1b[0m done", "red done"},
+		{"\x1b]0;title\x07after", "after"},
+		{"50%\r100%\r\n", "50%\n100%\n"},
+		{"bell\x07 nul\x00 del\x7f", "bell␇ nul␀ del␡"},
+		{"bad \xff\xfe bytes", "bad � bytes"},
+		{"c1 \u009b31m", "c1 31m"},
+	}
+	for _, tt := range tests {
+		if got := SanitizeText(tt.in); got != tt.want {
+			t.Errorf("SanitizeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRenderStripsEscapesUnlessRaw(t *testing.T) {
+	entry := transcript.UnifiedEntry{Role: "user", Parts: []transcript.UnifiedPart{
+		{Type: "text", Content: transcript.UnifiedTextContent{Text: "clear\x1b[2Jscreen"}},
+	}}
+	var buf bytes.Buffer
+	if err := RenderUnifiedEntry(&buf, entry, RenderOptions{Style: StyleMarkdown}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "\x1b") || !strings.Contains(buf.String(), "clearscreen") {
+		t.Errorf("sanitized render = %q", buf.String())
+	}
+	buf.Reset()
+	if err := RenderUnifiedEntry(&buf, entry, RenderOptions{Style: StyleMarkdown, RawEscapes: true}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "clear\x1b[2Jscreen") {
+		t.Errorf("raw render = %q", buf.String())
+	}
+}
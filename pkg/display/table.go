@@ -3,43 +3,167 @@ package display
 import (
 	"fmt"
 	"io"
-	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/grovetools/agentlogs/internal/session"
 )
 
+// SessionColumn is one column of the sessions table.
+type SessionColumn struct {
+	Header string
+	Value  func(session.SessionInfo) string
+}
+
+// SessionColumns are the columns the sessions table can show, by name.
+var SessionColumns = map[string]SessionColumn{
+	"id":        {"SESSION ID", sessionID},
+	"provider":  {"PROVIDER", sessionProvider},
+	"model":     {"MODEL", sessionModel},
+	"user":      {"USER", func(s session.SessionInfo) string { return s.User }},
+	"ecosystem": {"ECOSYSTEM", func(s session.SessionInfo) string { return s.Ecosystem }},
+	"project":   {"PROJECT", func(s session.SessionInfo) string { return s.ProjectName }},
+	"worktree":  {"WORKTREE", func(s session.SessionInfo) string { return s.Worktree }},
+	"branch":    {"GIT BRANCH", sessionBranch},
+	"jobs":      {"JOBS", sessionJobs},
+	"started":   {"STARTED", func(s session.SessionInfo) string { return s.StartedAt.Format("2006-01-02 15:04") }},
+	"duration":  {"DURATION", sessionDuration},
+	"status":    {"STATUS", func(s session.SessionInfo) string { return s.Status }},
+	"summary":   {"SUMMARY", sessionSummary},
+	"title":     {"TITLE", sessionTitle},
+	"preview":   {"PREVIEW", sessionPreview},
+}
+
+// DefaultSessionColumns is the table PrintSessionsTable prints.
+var DefaultSessionColumns = []string{"id", "provider", "model", "user", "ecosystem", "project", "worktree", "branch", "jobs", "started", "duration", "title"}
+
+// summaryWidth caps the SUMMARY column so one long line doesn't push the
+// table past the terminal.
+const summaryWidth = 80
+
 // PrintSessionsTable prints a list of sessions in a formatted table.
 func PrintSessionsTable(sessions []session.SessionInfo, writer io.Writer) {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "SESSION ID\tPROVIDER\tECOSYSTEM\tPROJECT\tWORKTREE\tJOBS\tSTARTED")
-	for _, s := range sessions {
-		jobsStr := ""
-		if len(s.Jobs) > 0 {
-			jobsStr = fmt.Sprintf("%s/%s", s.Jobs[0].Plan, s.Jobs[0].Job)
-			if len(s.Jobs) > 1 {
-				jobsStr += fmt.Sprintf(" (+%d more)", len(s.Jobs)-1)
-			}
+	_ = PrintSessionsTableColumns(sessions, writer, DefaultSessionColumns)
+}
+
+// PrintSessionsTableColumns prints sessions with the named columns, in
+// order. It fails on an unknown column name.
+func PrintSessionsTableColumns(sessions []session.SessionInfo, writer io.Writer, columns []string) error {
+	cols := make([]SessionColumn, len(columns))
+	headers := make([]string, len(columns))
+	for i, name := range columns {
+		col, ok := SessionColumns[name]
+		if !ok {
+			return fmt.Errorf("unknown column %q", name)
 		}
+		cols[i], headers[i] = col, col.Header
+	}
 
-		// Determine provider display
-		provider := s.Provider
-		if provider == "" {
-			// Infer provider from log file path for backwards compatibility
-			if s.LogFilePath != "" {
-				switch {
-				case strings.Contains(s.LogFilePath, "/.codex/"):
-					provider = "codex"
-				case strings.Contains(s.LogFilePath, "/.claude/"):
-					provider = "claude"
-				}
-			}
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	values := make([]string, len(cols))
+	for _, s := range sessions {
+		for i, col := range cols {
+			values[i] = col.Value(s)
 		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	return w.Flush()
+}
+
+// sessionID marks pinned sessions, which prune keeps, and sessions listed
+// from a remote archive.
+func sessionID(s session.SessionInfo) string {
+	id := s.SessionID
+	if s.Pinned {
+		id += " (pinned)"
+	}
+	if s.Remote != "" {
+		id += " (remote)"
+	}
+	return id
+}
+
+func sessionProvider(s session.SessionInfo) string {
+	if s.Provider == "" && s.LogFilePath != "" {
+		return session.ProviderForPath(s.LogFilePath)
+	}
+	return s.Provider
+}
+
+func sessionModel(s session.SessionInfo) string {
+	if s.Model == "" {
+		return "-"
+	}
+	return s.Model
+}
+
+func sessionBranch(s session.SessionInfo) string {
+	if s.GitBranch == "" {
+		return "-"
+	}
+	return s.GitBranch
+}
+
+func sessionJobs(s session.SessionInfo) string {
+	if len(s.Jobs) == 0 {
+		return ""
+	}
+	jobs := fmt.Sprintf("%s/%s", s.Jobs[0].Plan, s.Jobs[0].Job)
+	if len(s.Jobs) > 1 {
+		jobs += fmt.Sprintf(" (+%d more)", len(s.Jobs)-1)
+	}
+	return jobs
+}
+
+func sessionDuration(s session.SessionInfo) string {
+	if d := s.Duration(); d > 0 {
+		return formatSessionDuration(d)
+	}
+	return "-"
+}
+
+func sessionSummary(s session.SessionInfo) string {
+	if s.Activity == "" {
+		return "-"
+	}
+	if runes := []rune(s.Activity); len(runes) > summaryWidth {
+		return string(runes[:summaryWidth-1]) + "…"
+	}
+	return s.Activity
+}
+
+func sessionTitle(s session.SessionInfo) string {
+	if s.Title == "" {
+		return "-"
+	}
+	if runes := []rune(s.Title); len(runes) > summaryWidth {
+		return string(runes[:summaryWidth-1]) + "…"
+	}
+	return s.Title
+}
+
+func sessionPreview(s session.SessionInfo) string {
+	if s.Preview == "" {
+		return "-"
+	}
+	if runes := []rune(s.Preview); len(runes) > summaryWidth {
+		return string(runes[:summaryWidth-1]) + "…"
+	}
+	return s.Preview
+}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			s.SessionID, provider, s.Ecosystem, s.ProjectName, s.Worktree, jobsStr,
-			s.StartedAt.Format("2006-01-02 15:04"))
+// formatSessionDuration renders a session span compactly: 45s, 12m, 3h05m, 2d04h.
+func formatSessionDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%02dh", int(d.Hours())/24, int(d.Hours())%24)
 	}
-	w.Flush()
 }
@@ -0,0 +1,129 @@
+// Package export writes normalized transcripts in interchange formats that
+// do not depend on any single provider's on-disk log layout.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// Format identifies an export format.
+type Format string
+
+const (
+	// FormatUnifiedJSONL writes one transcript.UnifiedEntry JSON object per
+	// line. The shape is identical for every provider, so consumers can parse
+	// Claude, Codex, pi, and OpenCode transcripts with the same code.
+	FormatUnifiedJSONL Format = "unified-jsonl"
+
+	// FormatIPynb writes a Jupyter notebook: markdown cells for prompts and
+	// responses, %%bash / %%writefile code cells for shell and Write calls.
+	FormatIPynb Format = "ipynb"
+
+	// FormatHTML writes a self-contained HTML document of the session.
+	FormatHTML Format = "html"
+
+	// FormatPDF renders the HTML document to PDF with an external converter
+	// (wkhtmltopdf or a headless Chromium), for filing into document systems.
+	FormatPDF Format = "pdf"
+
+	// FormatICS writes an iCalendar event for the session, for laying out
+	// agent working time in a calendar. WriteCalendar writes many sessions
+	// into one calendar.
+	FormatICS Format = "ics"
+
+	// FormatSQLite writes a SQLite database with tables of sessions,
+	// messages, tool calls and token usage, for querying agent activity
+	// with SQL. It is built with the sqlite3 CLI. Database holds many
+	// sessions in one file.
+	FormatSQLite Format = "sqlite"
+)
+
+// Session describes the transcript being exported. Formats that carry a
+// document header (html, pdf, ipynb metadata) or a sessions table (sqlite)
+// use it; unified-jsonl does not.
+type Session struct {
+	SessionID   string
+	Title       string // the conversation title, when the transcript records one
+	Provider    string
+	Project     string
+	LogFilePath string
+	StartedAt   time.Time
+	EndedAt     time.Time
+	Worktree    string
+	Branch      string
+	Models      []string
+	Jobs        []string // "plan/job.md"
+	Tokens      int64
+	CostUSD     float64
+	// Chain lists the session IDs of the transcript files a resumed
+	// session was stitched together from, oldest first; empty for one file.
+	Chain []string
+}
+
+// Formats lists the supported export formats, for help text and validation.
+func Formats() []Format {
+	return []Format{FormatUnifiedJSONL, FormatIPynb, FormatHTML, FormatPDF, FormatICS, FormatSQLite}
+}
+
+// Ext returns the file extension for the format, without the dot.
+func (f Format) Ext() string {
+	if f == FormatUnifiedJSONL {
+		return "jsonl"
+	}
+	return string(f)
+}
+
+// ParseFormat validates a user-supplied format name.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats() {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	names := make([]string, 0, len(Formats()))
+	for _, f := range Formats() {
+		names = append(names, string(f))
+	}
+	return "", fmt.Errorf("unknown export format %q (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// Write encodes entries to w in the given format. ctx bounds the external
+// tools some formats run.
+func Write(ctx context.Context, w io.Writer, format Format, session Session, entries []transcript.UnifiedEntry) error {
+	switch format {
+	case FormatUnifiedJSONL:
+		return writeUnifiedJSONL(w, entries)
+	case FormatIPynb:
+		return writeIPynb(w, session, entries)
+	case FormatHTML:
+		return writeHTML(w, session, entries)
+	case FormatPDF:
+		return writePDF(ctx, w, session, entries)
+	case FormatICS:
+		return writeICS(w, session, entries)
+	case FormatSQLite:
+		return writeSQLite(w, session, entries)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// writeUnifiedJSONL writes each entry as a single JSON line.
+func writeUnifiedJSONL(w io.Writer, entries []transcript.UnifiedEntry) error {
+	enc := json.NewEncoder(w)
+	// Transcripts are full of code; keep <, > and & readable.
+	enc.SetEscapeHTML(false)
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return fmt.Errorf("failed to encode entry %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestWriteUnifiedJSONL(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []transcript.UnifiedEntry{
+		{Role: "user", Timestamp: ts, Provider: "claude", Parts: []transcript.UnifiedPart{
+			{Type: "text", Content: transcript.UnifiedTextContent{Text: "if a < b && c > d"}},
+		}},
+		{Role: "assistant", Timestamp: ts, Provider: "codex", Parts: []transcript.UnifiedPart{
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: "1", Name: "shell", Input: map[string]interface{}{"command": "ls"}}},
+		}, Tokens: &transcript.UnifiedTokens{Input: 10, Output: 2}},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(context.Background(), &buf, FormatUnifiedJSONL, Session{}, entries); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`\u003c`)) {
+		t.Errorf("expected HTML characters to be left unescaped, got %s", buf.String())
+	}
+
+	var got []transcript.UnifiedEntry
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var e transcript.UnifiedEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("line %d is not a UnifiedEntry: %v", len(got)+1, err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != len(entries) {
+		t.Fatalf("got %d lines, want %d", len(got), len(entries))
+	}
+	if got[1].Provider != "codex" || got[1].Tokens == nil || got[1].Tokens.Input != 10 {
+		t.Errorf("round-tripped entry = %+v", got[1])
+	}
+	if !got[0].Timestamp.Equal(ts) {
+		t.Errorf("timestamp = %v, want %v", got[0].Timestamp, ts)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("unified-jsonl"); err != nil || f != FormatUnifiedJSONL {
+		t.Errorf("ParseFormat(unified-jsonl) = %q, %v", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestWriteIPynb(t *testing.T) {
+	entries := []transcript.UnifiedEntry{
+		{Role: "user", Parts: []transcript.UnifiedPart{
+			{Type: "text", Content: transcript.UnifiedTextContent{Text: "list the files"}},
+		}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{
+			{Type: "text", Content: transcript.UnifiedTextContent{Text: "Sure."}},
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: "t1", Name: "Bash", Input: map[string]interface{}{"command": "ls"}}},
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: "t2", Name: "Write", Input: map[string]interface{}{"file_path": "a.txt", "content": "hello\n"}}},
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: "t3", Name: "Read", Input: map[string]interface{}{"file_path": "a.txt"}}},
+		}},
+		{Role: "user", Parts: []transcript.UnifiedPart{
+			{Type: "tool_result", Content: transcript.UnifiedToolResult{ToolCallID: "t1", Output: "a.txt\nb.txt"}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(context.Background(), &buf, FormatIPynb, Session{SessionID: "s1"}, entries); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	var nb struct {
+		NBFormat int `json:"nbformat"`
+		Cells    []struct {
+			CellType string          `json:"cell_type"`
+			Source   []string        `json:"source"`
+			Outputs  json.RawMessage `json:"outputs"`
+		} `json:"cells"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &nb); err != nil {
+		t.Fatalf("notebook is not valid JSON: %v", err)
+	}
+	if nb.NBFormat != 4 {
+		t.Errorf("nbformat = %d, want 4", nb.NBFormat)
+	}
+
+	var kinds []string
+	for _, c := range nb.Cells {
+		kinds = append(kinds, c.CellType)
+	}
+	want := []string{"markdown", "markdown", "code", "code"}
+	if len(kinds) != len(want) {
+		t.Fatalf("cell types = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("cell types = %v, want %v", kinds, want)
+		}
+	}
+
+	bash := nb.Cells[2]
+	if bash.Source[0] != "%%bash\n" || bash.Source[1] != "ls" {
+		t.Errorf("bash cell source = %q", bash.Source)
+	}
+	if !bytes.Contains(bash.Outputs, []byte(`"b.txt"`)) {
+		t.Errorf("bash cell outputs = %s, want recorded tool output", bash.Outputs)
+	}
+	if got := nb.Cells[3].Source[0]; got != "%%writefile a.txt\n" {
+		t.Errorf("write cell first line = %q", got)
+	}
+}
+
+func TestWriteHTMLEscapesContent(t *testing.T) {
+	entries := []transcript.UnifiedEntry{
+		{Role: "user", Parts: []transcript.UnifiedPart{
+			{Type: "text", Content: transcript.UnifiedTextContent{Text: "<script>alert(1)</script>"}},
+		}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: "t1", Name: "Bash", Input: map[string]interface{}{"command": "ls"}}},
+		}},
+		{Role: "user", Parts: []transcript.UnifiedPart{
+			{Type: "tool_result", Content: transcript.UnifiedToolResult{ToolCallID: "t1", Output: "boom", IsError: true}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	session := Session{SessionID: "abc-123", Provider: "claude", Branch: "feat/x", Models: []string{"claude-sonnet-4"}, Tokens: 42, CostUSD: 0.01}
+	if err := Write(context.Background(), &buf, FormatHTML, session, entries); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "<script>alert") {
+		t.Error("transcript text must be HTML-escaped")
+	}
+	for _, want := range []string{"Agent session abc-123", "&lt;script&gt;", "→ Bash", `class="error"`,
+		"<dt>Branch</dt><dd>feat/x</dd>", "<dt>Models</dt><dd>claude-sonnet-4</dd>", "<dt>Usage</dt><dd>42 tokens, $0.01</dd>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML output missing %q", want)
+		}
+	}
+}
+
+func TestWriteCalendar(t *testing.T) {
+	start := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	sessions := []Session{
+		{
+			SessionID: "abc-123",
+			Title:     "Fix the scanner; add tests, then docs",
+			Provider:  "claude",
+			Project:   "agentlogs",
+			StartedAt: start,
+			EndedAt:   start.Add(95 * time.Minute),
+			Jobs:      []string{"refactor/01-scanner.md"},
+		},
+		{SessionID: "def-456", Project: "core", StartedAt: start.Add(3 * time.Hour)},
+		{SessionID: "no-start"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCalendar(&buf, sessions); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Fatalf("not a CRLF calendar:\n%q", out)
+	}
+	if n := strings.Count(out, "BEGIN:VEVENT"); n != 2 {
+		t.Errorf("got %d events, want 2 (sessions without a start are skipped)", n)
+	}
+	for _, want := range []string{
+		"UID:abc-123@aglogs\r\n",
+		"DTSTART:20250304T090000Z\r\nDTEND:20250304T103500Z\r\n",
+		`SUMMARY:Fix the scanner\; add tests\, then docs` + "\r\n",
+		"LOCATION:agentlogs\r\n",
+		"SUMMARY:Agent session in core\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar missing %q:\n%s", want, out)
+		}
+	}
+
+	// Long lines fold at 75 octets; unfolding restores the description.
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, `DESCRIPTION:Project: agentlogs\nProvider: claude\nJobs: refactor/01-scanner.md\nDuration: 1h35m\nSession: abc-123`) {
+		t.Errorf("unexpected description:\n%s", unfolded)
+	}
+}
+
+func TestWriteMarksResumeBoundaries(t *testing.T) {
+	entries := []transcript.UnifiedEntry{
+		{Role: "user", Parts: []transcript.UnifiedPart{{Type: "text", Content: transcript.UnifiedTextContent{Text: "start"}}}},
+		{Role: "system", Parts: []transcript.UnifiedPart{{Type: "summary", Content: transcript.UnifiedSummary{Kind: transcript.SummaryResume, Text: "Resumed as session b2"}}}},
+		{Role: "system", Parts: []transcript.UnifiedPart{{Type: "summary", Content: transcript.UnifiedSummary{Kind: transcript.SummaryTitle, Text: "A title"}}}},
+		{Role: "user", Parts: []transcript.UnifiedPart{{Type: "text", Content: transcript.UnifiedTextContent{Text: "go on"}}}},
+	}
+	session := Session{SessionID: "a1", Provider: "claude", Chain: []string{"a1", "b2"}}
+
+	var html bytes.Buffer
+	if err := Write(context.Background(), &html, FormatHTML, session, entries); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`<div class="boundary">Resumed as session b2</div>`, "<dt>Resumed</dt><dd>a1 → b2</dd>"} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("HTML output missing %q", want)
+		}
+	}
+	if strings.Contains(html.String(), "A title") {
+		t.Error("HTML output shows a title summary")
+	}
+
+	var nb bytes.Buffer
+	if err := Write(context.Background(), &nb, FormatIPynb, session, entries); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Cells []struct {
+			Source []string `json:"source"`
+		} `json:"cells"`
+		Metadata struct {
+			Aglogs map[string]interface{} `json:"aglogs"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(nb.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Cells) != 3 || strings.Join(doc.Cells[1].Source, "") != "---\n\n*Resumed as session b2*" {
+		t.Errorf("notebook cells = %+v, want the boundary between the two prompts", doc.Cells)
+	}
+	if doc.Metadata.Aglogs["chain"] == nil {
+		t.Error("notebook metadata missing the chain")
+	}
+}
+
+func TestDatabase(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not on PATH")
+	}
+	start := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	exit := 1
+	entries := []transcript.UnifiedEntry{
+		{Role: "user", Timestamp: start, Parts: []transcript.UnifiedPart{{Type: "text", Content: transcript.UnifiedTextContent{Text: "Don't break it"}}}},
+		{Role: "assistant", Timestamp: start.Add(time.Minute), Tokens: &transcript.UnifiedTokens{Input: 100, Output: 20, CacheRead: 900}, Parts: []transcript.UnifiedPart{
+			{Type: "text", Content: transcript.UnifiedTextContent{Text: "Running the tests."}},
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: "t1", Name: "Bash", Input: map[string]interface{}{"command": "go test ./..."}}},
+		}},
+		{Role: "user", Parts: []transcript.UnifiedPart{{Type: "tool_result", Content: transcript.UnifiedToolResult{ToolCallID: "t1", Output: "FAIL", IsError: true, ExitCode: &exit}}}},
+	}
+
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	db, err := CreateDatabase(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Add(Session{SessionID: "a1", Provider: "claude", StartedAt: start, Jobs: []string{"p/01.md"}, Tokens: 1020}, entries); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Add(Session{SessionID: "b2", Provider: "codex"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	// The same session found twice, as a scan can list it.
+	if err := db.Add(Session{SessionID: "a1", Provider: "claude"}, entries); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateDatabase(path); err == nil {
+		t.Error("CreateDatabase overwrote an existing file")
+	}
+	if _, err := sqlValue(uint8(1)); err == nil {
+		t.Error("sqlValue accepted an unsupported type")
+	}
+
+	for query, want := range map[string]string{
+		"SELECT count(*) FROM sessions":                                          "2",
+		"SELECT started_at, tokens FROM sessions WHERE session_id = 'a1'":        "2025-03-04T09:00:00.000Z|1020",
+		"SELECT job FROM session_jobs":                                           "p/01.md",
+		"SELECT seq, role, text FROM messages WHERE seq < 2 ORDER BY seq":        "0|user|Don't break it\n1|assistant|Running the tests.",
+		"SELECT name, input, output, is_error, exit_code FROM tool_calls":        `Bash|{"command":"go test ./..."}|FAIL|1|1`,
+		"SELECT seq, input + cache_read, cost_usd IS NULL FROM token_usage":      "1|1000|1",
+		"SELECT count(*) FROM messages WHERE datetime(timestamp) > '2025-03-04'": "2",
+	} {
+		out, err := exec.Command("sqlite3", path, query).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: %v: %s", query, err, out)
+		}
+		if got := strings.TrimSpace(string(out)); got != want {
+			t.Errorf("%s = %q, want %q", query, got, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(context.Background(), &buf, FormatSQLite, Session{SessionID: "a1"}, entries); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("SQLite format 3\x00")) {
+		t.Errorf("Write(sqlite) did not produce a database: %q", buf.Bytes()[:min(buf.Len(), 16)])
+	}
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// htmlMessage is the template view of one transcript entry.
+type htmlMessage struct {
+	Role      string
+	Timestamp string
+	Blocks    []htmlBlock
+}
+
+// htmlBlock is one rendered part: prose, reasoning, a tool call, or its result.
+type htmlBlock struct {
+	Kind    string // "text", "reasoning", "tool_call", "tool_result", "boundary"
+	Title   string
+	Body    string
+	IsError bool
+}
+
+var htmlTemplate = template.Must(template.New("session").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 11pt; color: #1f2328; max-width: 60em; margin: 2em auto; padding: 0 1em; }
+header { border-bottom: 2px solid #d0d7de; margin-bottom: 1.5em; }
+header dl { display: grid; grid-template-columns: max-content auto; gap: 0.2em 1em; }
+header dt { font-weight: 600; }
+header dd { margin: 0; font-family: monospace; word-break: break-all; }
+.message { border-left: 4px solid #d0d7de; padding: 0.2em 0 0.2em 1em; margin: 1em 0; page-break-inside: avoid; }
+.message.user { border-color: #0969da; }
+.message.assistant { border-color: #8250df; }
+.role { font-weight: 600; text-transform: capitalize; }
+.time { color: #656d76; font-size: 9pt; margin-left: 0.5em; }
+.text { white-space: pre-wrap; word-wrap: break-word; }
+.reasoning { white-space: pre-wrap; color: #656d76; font-style: italic; }
+.tool-title { font-family: monospace; font-weight: 600; margin-top: 0.5em; }
+pre { background: #f6f8fa; padding: 0.6em; white-space: pre-wrap; word-wrap: break-word; font-size: 9pt; }
+pre.error { background: #ffebe9; }
+.boundary { border-top: 2px dashed #d0d7de; padding-top: 0.5em; color: #656d76; font-weight: 600; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<dl>
+{{- with .Session.SessionID}}<dt>Session</dt><dd>{{.}}</dd>{{end}}
+{{- with .Session.Provider}}<dt>Provider</dt><dd>{{.}}</dd>{{end}}
+{{- with .Models}}<dt>Models</dt><dd>{{.}}</dd>{{end}}
+{{- with .Session.Project}}<dt>Project</dt><dd>{{.}}</dd>{{end}}
+{{- with .Session.Worktree}}<dt>Worktree</dt><dd>{{.}}</dd>{{end}}
+{{- with .Session.Branch}}<dt>Branch</dt><dd>{{.}}</dd>{{end}}
+{{- with .Started}}<dt>Started</dt><dd>{{.}}</dd>{{end}}
+{{- with .Ended}}<dt>Ended</dt><dd>{{.}}</dd>{{end}}
+{{- with .Usage}}<dt>Usage</dt><dd>{{.}}</dd>{{end}}
+{{- with .Jobs}}<dt>Jobs</dt><dd>{{.}}</dd>{{end}}
+{{- with .Chain}}<dt>Resumed</dt><dd>{{.}}</dd>{{end}}
+{{- with .Session.LogFilePath}}<dt>Source</dt><dd>{{.}}</dd>{{end}}
+<dt>Messages</dt><dd>{{len .Messages}}</dd>
+</dl>
+</header>
+{{range .Messages}}<section class="message {{.Role}}">
+<div><span class="role">{{.Role}}</span>{{with .Timestamp}}<span class="time">{{.}}</span>{{end}}</div>
+{{range .Blocks}}{{if eq .Kind "text"}}<div class="text">{{.Body}}</div>
+{{else if eq .Kind "reasoning"}}<div class="reasoning">{{.Body}}</div>
+{{else if eq .Kind "boundary"}}<div class="boundary">{{.Body}}</div>
+{{else if eq .Kind "tool_call"}}<div class="tool-title">→ {{.Title}}</div>{{with .Body}}<pre>{{.}}</pre>{{end}}
+{{else}}<div class="tool-title">← result</div><pre{{if .IsError}} class="error"{{end}}>{{.Body}}</pre>
+{{end}}{{end}}</section>
+{{end}}</body>
+</html>
+`))
+
+// writeHTML renders a self-contained HTML document with a metadata header
+// and one section per message. All transcript content is escaped.
+func writeHTML(w io.Writer, session Session, entries []transcript.UnifiedEntry) error {
+	data := struct {
+		Title    string
+		Session  Session
+		Started  string
+		Ended    string
+		Models   string
+		Usage    string
+		Jobs     string
+		Chain    string
+		Messages []htmlMessage
+	}{
+		Title:    "Agent session transcript",
+		Session:  session,
+		Models:   strings.Join(session.Models, ", "),
+		Jobs:     strings.Join(session.Jobs, ", "),
+		Chain:    strings.Join(session.Chain, " → "),
+		Messages: htmlMessages(entries),
+	}
+	if session.SessionID != "" {
+		data.Title = "Agent session " + session.SessionID
+	}
+	if !session.StartedAt.IsZero() {
+		data.Started = session.StartedAt.UTC().Format(time.RFC3339)
+	}
+	if !session.EndedAt.IsZero() {
+		data.Ended = session.EndedAt.UTC().Format(time.RFC3339)
+	}
+	if session.Tokens > 0 {
+		data.Usage = fmt.Sprintf("%d tokens, $%.2f", session.Tokens, session.CostUSD)
+	}
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return nil
+}
+
+// htmlMessages converts entries into template views, dropping entries with
+// nothing to show.
+func htmlMessages(entries []transcript.UnifiedEntry) []htmlMessage {
+	var messages []htmlMessage
+	for _, entry := range entries {
+		msg := htmlMessage{Role: entry.Role}
+		if !entry.Timestamp.IsZero() {
+			msg.Timestamp = entry.Timestamp.UTC().Format(time.RFC3339)
+		}
+		for _, part := range entry.Parts {
+			switch part.Type {
+			case "text", "reasoning":
+				if text := strings.TrimSpace(transcript.TextOf(part)); text != "" {
+					msg.Blocks = append(msg.Blocks, htmlBlock{Kind: part.Type, Body: text})
+				}
+			case "image":
+				msg.Blocks = append(msg.Blocks, htmlBlock{Kind: "text", Body: transcript.ImageOf(part).Placeholder()})
+			case "summary":
+				if text := partBoundary(part); text != "" {
+					msg.Blocks = append(msg.Blocks, htmlBlock{Kind: "boundary", Body: text})
+				}
+			case "tool_call":
+				call := transcript.ToolCallOf(part)
+				block := htmlBlock{Kind: "tool_call", Title: call.Name}
+				if len(call.Input) > 0 {
+					if data, err := json.MarshalIndent(call.Input, "", "  "); err == nil {
+						block.Body = string(data)
+					}
+				}
+				msg.Blocks = append(msg.Blocks, block)
+				// OpenCode records the output on the call itself.
+				if output := withImagePlaceholders(call.Output, call.Images); output != "" {
+					msg.Blocks = append(msg.Blocks, htmlBlock{Kind: "tool_result", Body: output})
+				}
+			case "tool_result":
+				r := transcript.ToolResultOf(part)
+				msg.Blocks = append(msg.Blocks, htmlBlock{Kind: "tool_result", Body: withImagePlaceholders(r.Output, r.Images), IsError: r.IsError})
+			}
+		}
+		if len(msg.Blocks) > 0 {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
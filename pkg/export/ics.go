@@ -0,0 +1,138 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// icsTimeLayout is an RFC 5545 UTC date-time.
+const icsTimeLayout = "20060102T150405Z"
+
+// writeICS writes a calendar holding the one exported session.
+func writeICS(w io.Writer, session Session, _ []transcript.UnifiedEntry) error {
+	return WriteCalendar(w, []Session{session})
+}
+
+// WriteCalendar writes sessions as an iCalendar (RFC 5545) document with one
+// event per session, spanning its start to its last recorded activity, so
+// agent working time can be laid out in a calendar next to meetings. A
+// session whose end is unknown becomes an event without duration.
+func WriteCalendar(w io.Writer, sessions []Session) error {
+	cw := &icsWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//grovetools//aglogs//EN")
+	cw.line("CALSCALE:GREGORIAN")
+	for _, s := range sessions {
+		if s.StartedAt.IsZero() {
+			continue
+		}
+		stamp := s.StartedAt
+		if s.EndedAt.After(s.StartedAt) {
+			stamp = s.EndedAt
+		}
+		cw.line("BEGIN:VEVENT")
+		cw.line("UID:" + icsEscape(s.SessionID) + "@aglogs")
+		// The last activity stands in for when the event was written, so
+		// exporting the same sessions twice gives the same calendar.
+		cw.line("DTSTAMP:" + stamp.UTC().Format(icsTimeLayout))
+		cw.line("DTSTART:" + s.StartedAt.UTC().Format(icsTimeLayout))
+		if s.EndedAt.After(s.StartedAt) {
+			cw.line("DTEND:" + s.EndedAt.UTC().Format(icsTimeLayout))
+		}
+		cw.line("SUMMARY:" + icsEscape(eventTitle(s)))
+		if s.Project != "" {
+			cw.line("LOCATION:" + icsEscape(s.Project))
+		}
+		cw.line("DESCRIPTION:" + icsEscape(eventDescription(s)))
+		if s.Provider != "" {
+			cw.line("CATEGORIES:" + icsEscape(s.Provider))
+		}
+		cw.line("END:VEVENT")
+	}
+	cw.line("END:VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+// eventTitle is the session's title, else its first job, else the project
+// it ran in.
+func eventTitle(s Session) string {
+	switch {
+	case s.Title != "":
+		return s.Title
+	case len(s.Jobs) > 0:
+		return s.Jobs[0]
+	case s.Project != "":
+		return "Agent session in " + s.Project
+	}
+	return "Agent session"
+}
+
+// eventDescription lists what a calendar reader needs to find the session
+// again, one fact per line.
+func eventDescription(s Session) string {
+	var lines []string
+	add := func(label, value string) {
+		if value != "" {
+			lines = append(lines, label+": "+value)
+		}
+	}
+	add("Project", s.Project)
+	add("Worktree", s.Worktree)
+	add("Provider", s.Provider)
+	add("Jobs", strings.Join(s.Jobs, ", "))
+	if s.EndedAt.After(s.StartedAt) {
+		add("Duration", eventDuration(s.EndedAt.Sub(s.StartedAt)))
+	}
+	add("Session", s.SessionID)
+	return strings.Join(lines, "\n")
+}
+
+// eventDuration renders d to the minute: 45m, 1h35m.
+func eventDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+// icsEscape escapes an RFC 5545 TEXT value.
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// icsWriter writes content lines with CRLF endings, folding them at 75
+// octets without splitting a UTF-8 sequence. It keeps the first error.
+type icsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (cw *icsWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if _, cw.err = fmt.Fprintf(cw.w, "%s\r\n ", s[:cut]); cw.err != nil {
+			return
+		}
+		s = s[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	_, cw.err = fmt.Fprintf(cw.w, "%s\r\n", s)
+}
@@ -0,0 +1,202 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/shellcmds"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// notebook is the subset of the Jupyter nbformat v4 document that the
+// exporter emits.
+type notebook struct {
+	Cells         []notebookCell         `json:"cells"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	NBFormat      int                    `json:"nbformat"`
+	NBFormatMinor int                    `json:"nbformat_minor"`
+}
+
+// notebookCell is a generic cell. Code cells require "outputs" and
+// "execution_count" keys that markdown cells must not have, so cells are
+// built as maps rather than one struct with omitempty fields.
+type notebookCell map[string]interface{}
+
+type notebookOutput struct {
+	OutputType string   `json:"output_type"`
+	Name       string   `json:"name"`
+	Text       []string `json:"text"`
+}
+
+// writeIPynb converts a transcript into a Jupyter notebook. Prompts and
+// responses become markdown cells; shell commands become %%bash cells and
+// file writes become %%writefile cells, so individual agent steps can be
+// re-run from the notebook. Recorded tool output is attached to the cell.
+// Other tool calls and reasoning are omitted.
+func writeIPynb(w io.Writer, session Session, entries []transcript.UnifiedEntry) error {
+	results := toolResults(entries)
+	nb := notebook{
+		Cells: []notebookCell{},
+		Metadata: map[string]interface{}{
+			"kernelspec": map[string]string{
+				"name":         "python3",
+				"display_name": "Python 3",
+				"language":     "python",
+			},
+			"language_info": map[string]string{"name": "python"},
+			"aglogs":        ipynbSessionMetadata(session),
+		},
+		NBFormat:      4,
+		NBFormatMinor: 4,
+	}
+
+	for _, entry := range entries {
+		var text []string
+		flushText := func() {
+			if len(text) == 0 {
+				return
+			}
+			heading := "**Assistant**"
+			if entry.Role == "user" {
+				heading = "**User**"
+			}
+			nb.Cells = append(nb.Cells, markdownCell(heading+"\n\n"+strings.Join(text, "\n\n")))
+			text = nil
+		}
+
+		for _, part := range entry.Parts {
+			switch part.Type {
+			case "text":
+				if t := strings.TrimSpace(transcript.TextOf(part)); t != "" {
+					text = append(text, t)
+				}
+			case "image":
+				text = append(text, transcript.ImageOf(part).Placeholder())
+			case "summary":
+				if boundary := partBoundary(part); boundary != "" {
+					flushText()
+					nb.Cells = append(nb.Cells, markdownCell("---\n\n*"+boundary+"*"))
+				}
+			case "tool_call":
+				call := transcript.ToolCallOf(part)
+				source := toolCellSource(call)
+				if source == "" {
+					continue
+				}
+				flushText()
+				cell := codeCell(source)
+				output, isError := withImagePlaceholders(call.Output, call.Images), false
+				if r, ok := results[call.ID]; ok {
+					output, isError = withImagePlaceholders(r.Output, r.Images), r.IsError
+				}
+				if output != "" {
+					stream := "stdout"
+					if isError {
+						stream = "stderr"
+					}
+					cell["outputs"] = []notebookOutput{{OutputType: "stream", Name: stream, Text: splitLines(output)}}
+				}
+				nb.Cells = append(nb.Cells, cell)
+			}
+		}
+		flushText()
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	if err := enc.Encode(nb); err != nil {
+		return fmt.Errorf("failed to encode notebook: %w", err)
+	}
+	return nil
+}
+
+// toolCellSource returns the code cell source for a re-runnable tool call,
+// or "" when the tool has no notebook equivalent.
+func toolCellSource(call transcript.UnifiedToolCall) string {
+	if cmd := shellcmds.Script(call.Name, call.Input); cmd != "" {
+		return "%%bash\n" + cmd
+	}
+	switch strings.ToLower(call.Name) {
+	case "write":
+		path := transcript.StringField(call.Input, "file_path")
+		if path == "" {
+			path = transcript.StringField(call.Input, "filePath")
+		}
+		if path == "" {
+			path = transcript.StringField(call.Input, "path")
+		}
+		if path != "" {
+			return "%%writefile " + path + "\n" + transcript.StringField(call.Input, "content")
+		}
+	}
+	return ""
+}
+
+func markdownCell(source string) notebookCell {
+	return notebookCell{
+		"cell_type": "markdown",
+		"metadata":  map[string]interface{}{},
+		"source":    splitLines(source),
+	}
+}
+
+func codeCell(source string) notebookCell {
+	return notebookCell{
+		"cell_type":       "code",
+		"metadata":        map[string]interface{}{},
+		"source":          splitLines(source),
+		"execution_count": nil,
+		"outputs":         []notebookOutput{},
+	}
+}
+
+// splitLines splits text into nbformat's multiline-string form: a list of
+// lines, each keeping its trailing newline except the last.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+	return lines
+}
+
+// ipynbSessionMetadata is the notebook-level "aglogs" metadata describing the
+// source session. Unknown fields are left out.
+func ipynbSessionMetadata(session Session) map[string]interface{} {
+	meta := map[string]interface{}{
+		"session_id": session.SessionID,
+		"provider":   session.Provider,
+	}
+	set := func(key, value string) {
+		if value != "" {
+			meta[key] = value
+		}
+	}
+	set("project", session.Project)
+	set("worktree", session.Worktree)
+	set("branch", session.Branch)
+	if !session.StartedAt.IsZero() {
+		meta["started_at"] = session.StartedAt.UTC().Format(time.RFC3339)
+	}
+	if !session.EndedAt.IsZero() {
+		meta["ended_at"] = session.EndedAt.UTC().Format(time.RFC3339)
+	}
+	if len(session.Models) > 0 {
+		meta["models"] = session.Models
+	}
+	if len(session.Jobs) > 0 {
+		meta["jobs"] = session.Jobs
+	}
+	if len(session.Chain) > 0 {
+		meta["chain"] = session.Chain
+	}
+	if session.Tokens > 0 {
+		meta["tokens"] = session.Tokens
+		meta["cost_usd"] = session.CostUSD
+	}
+	return meta
+}
@@ -0,0 +1,47 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// partBoundary returns the text of a "summary" part marking where a
+// resumed session went on in a new transcript file, or "" for any other
+// summary.
+func partBoundary(part transcript.UnifiedPart) string {
+	if s := transcript.SummaryOf(part); s.Kind == transcript.SummaryResume {
+		return s.Text
+	}
+	return ""
+}
+
+// withImagePlaceholders appends a placeholder line per image to a tool's
+// output.
+func withImagePlaceholders(output string, images []transcript.UnifiedImage) string {
+	lines := []string{}
+	if output != "" {
+		lines = append(lines, output)
+	}
+	for _, img := range images {
+		lines = append(lines, img.Placeholder())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// toolResults indexes tool outputs by tool call ID. Providers that report
+// output inline on the call (OpenCode) are covered by UnifiedToolCall.Output.
+func toolResults(entries []transcript.UnifiedEntry) map[string]transcript.UnifiedToolResult {
+	results := make(map[string]transcript.UnifiedToolResult)
+	for _, entry := range entries {
+		for _, part := range entry.Parts {
+			if part.Type != "tool_result" {
+				continue
+			}
+			if r := transcript.ToolResultOf(part); r.ToolCallID != "" {
+				results[r.ToolCallID] = r
+			}
+		}
+	}
+	return results
+}
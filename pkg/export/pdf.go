@@ -0,0 +1,88 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// pdfTimeout bounds a single HTML-to-PDF conversion.
+const pdfTimeout = 2 * time.Minute
+
+// pdfConverters are the HTML-to-PDF tools tried in order. Each builds the
+// argument list for converting the HTML file at in to the PDF at out.
+var pdfConverters = []struct {
+	bin  string
+	args func(in, out string) []string
+}{
+	{"wkhtmltopdf", func(in, out string) []string {
+		return []string{"--quiet", "--encoding", "utf-8", in, out}
+	}},
+	{"chromium", chromeArgs},
+	{"chromium-browser", chromeArgs},
+	{"google-chrome", chromeArgs},
+	{"google-chrome-stable", chromeArgs},
+}
+
+func chromeArgs(in, out string) []string {
+	return []string{"--headless", "--disable-gpu", "--no-pdf-header-footer", "--print-to-pdf=" + out, "file://" + in}
+}
+
+// ErrNoPDFConverter is returned when no supported HTML-to-PDF tool is on PATH.
+var ErrNoPDFConverter = errors.New("no HTML-to-PDF converter found on PATH (install wkhtmltopdf or Chromium, or export --format html and convert it yourself)")
+
+// writePDF renders the session as HTML and converts it to PDF with the first
+// available converter, then copies the PDF to w.
+func writePDF(ctx context.Context, w io.Writer, session Session, entries []transcript.UnifiedEntry) error {
+	var bin string
+	var args func(in, out string) []string
+	for _, c := range pdfConverters {
+		if path, err := exec.LookPath(c.bin); err == nil {
+			bin, args = path, c.args
+			break
+		}
+	}
+	if bin == "" {
+		return ErrNoPDFConverter
+	}
+
+	dir, err := os.MkdirTemp("", "aglogs-pdf-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	htmlPath := filepath.Join(dir, "session.html")
+	pdfPath := filepath.Join(dir, "session.pdf")
+
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, session, entries); err != nil {
+		return err
+	}
+	if err := os.WriteFile(htmlPath, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write HTML: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pdfTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, bin, args(htmlPath, pdfPath)...) //nolint:gosec // converter chosen from a fixed list
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", filepath.Base(bin), err, bytes.TrimSpace(out))
+	}
+
+	f, err := os.Open(pdfPath)
+	if err != nil {
+		return fmt.Errorf("converter produced no PDF: %w", err)
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
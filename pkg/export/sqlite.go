@@ -0,0 +1,308 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// ErrNoSQLite is returned when the sqlite3 CLI, which builds sqlite exports,
+// is not on PATH.
+var ErrNoSQLite = errors.New("the sqlite format needs the sqlite3 CLI on PATH")
+
+// sqliteSchema is the relational layout of a sqlite export. Times are
+// ISO 8601 text in UTC, which SQLite's date functions read; seq numbers
+// a session's messages in transcript order.
+const sqliteSchema = `CREATE TABLE sessions (
+  session_id    TEXT PRIMARY KEY,
+  title         TEXT,
+  provider      TEXT,
+  project       TEXT,
+  worktree      TEXT,
+  branch        TEXT,
+  log_file_path TEXT,
+  started_at    TEXT,
+  ended_at      TEXT,
+  models        TEXT, -- comma-separated
+  tokens        INTEGER,
+  cost_usd      REAL
+);
+CREATE TABLE session_jobs (
+  session_id TEXT NOT NULL REFERENCES sessions(session_id),
+  job        TEXT NOT NULL -- plan/job.md
+);
+CREATE TABLE messages (
+  session_id   TEXT NOT NULL REFERENCES sessions(session_id),
+  seq          INTEGER NOT NULL,
+  message_id   TEXT,
+  role         TEXT,
+  timestamp    TEXT,
+  agent_id     TEXT,
+  is_sidechain INTEGER NOT NULL,
+  text         TEXT,
+  PRIMARY KEY (session_id, seq)
+);
+CREATE TABLE tool_calls (
+  session_id  TEXT NOT NULL REFERENCES sessions(session_id),
+  seq         INTEGER NOT NULL, -- the calling message
+  call_id     TEXT,
+  name        TEXT,
+  input       TEXT, -- JSON
+  output      TEXT,
+  is_error    INTEGER NOT NULL,
+  exit_code   INTEGER,
+  duration_ms INTEGER
+);
+CREATE TABLE token_usage (
+  session_id  TEXT NOT NULL REFERENCES sessions(session_id),
+  seq         INTEGER NOT NULL,
+  input       INTEGER NOT NULL,
+  output      INTEGER NOT NULL,
+  reasoning   INTEGER NOT NULL,
+  cache_read  INTEGER NOT NULL,
+  cache_write INTEGER NOT NULL,
+  cost_usd    REAL -- as the provider reported it, when it does
+);
+CREATE INDEX tool_calls_name ON tool_calls(name);
+CREATE INDEX messages_timestamp ON messages(timestamp);
+`
+
+// Database is a SQLite database being filled with sessions, one
+// transaction for all of them. It is built by feeding SQL to the sqlite3
+// CLI, so no database driver is linked in.
+type Database struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	w      *bufio.Writer
+	stderr bytes.Buffer
+	// added holds the IDs of the sessions inserted so far.
+	added map[string]bool
+}
+
+// CreateDatabase starts a new database at path, which must not exist.
+func CreateDatabase(path string) (*Database, error) {
+	bin, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return nil, ErrNoSQLite
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%s already exists", path)
+	}
+	db := &Database{added: make(map[string]bool)}
+	db.cmd = exec.Command(bin, "-bail", path) //nolint:gosec // fixed binary, path from the user
+	db.cmd.Stderr = &db.stderr
+	if db.stdin, err = db.cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	if err := db.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start sqlite3: %w", err)
+	}
+	db.w = bufio.NewWriterSize(db.stdin, 256*1024)
+	db.w.WriteString(sqliteSchema)
+	db.w.WriteString("BEGIN;\n")
+	return db, nil
+}
+
+// Add inserts a session and its entries. A session whose ID was already
+// added is skipped, as session_id is the sessions table's key. After an
+// error the database should be aborted.
+func (db *Database) Add(session Session, entries []transcript.UnifiedEntry) error {
+	if db.added[session.SessionID] {
+		return nil
+	}
+	db.added[session.SessionID] = true
+	if err := db.insert("sessions", session.SessionID, session.Title, session.Provider, session.Project,
+		session.Worktree, session.Branch, session.LogFilePath, sqlTime(session.StartedAt), sqlTime(session.EndedAt),
+		strings.Join(session.Models, ","), session.Tokens, session.CostUSD); err != nil {
+		return err
+	}
+	for _, job := range session.Jobs {
+		if err := db.insert("session_jobs", session.SessionID, job); err != nil {
+			return err
+		}
+	}
+
+	results := toolResults(entries)
+	for seq, e := range entries {
+		var text []string
+		for _, part := range e.Parts {
+			switch part.Type {
+			case "text":
+				text = append(text, transcript.TextOf(part))
+			case "tool_call":
+				call := transcript.ToolCallOf(part)
+				input, _ := json.Marshal(call.Input)
+				output, isError, exitCode, durationMs := call.Output, false, call.ExitCode, call.DurationMs
+				if r, ok := results[call.ID]; ok && call.ID != "" {
+					if output == "" {
+						output = r.Output
+					}
+					isError = r.IsError
+					if exitCode == nil {
+						exitCode = r.ExitCode
+					}
+					if durationMs == 0 {
+						durationMs = r.DurationMs
+					}
+				}
+				var code any
+				if exitCode != nil {
+					code = *exitCode
+				}
+				var duration any
+				if durationMs > 0 {
+					duration = durationMs
+				}
+				if err := db.insert("tool_calls", session.SessionID, seq, call.ID, call.Name, string(input), output, isError, code, duration); err != nil {
+					return err
+				}
+			}
+		}
+		if err := db.insert("messages", session.SessionID, seq, e.MessageID, e.Role, sqlTime(e.Timestamp),
+			e.AgentID, e.IsSidechain, strings.Join(text, "\n\n")); err != nil {
+			return err
+		}
+		if t := e.Tokens; t != nil {
+			var cost any
+			if t.Cost > 0 {
+				cost = t.Cost
+			}
+			if err := db.insert("token_usage", session.SessionID, seq, t.Input, t.Output, t.Reasoning, t.CacheRead, t.CacheWrite, cost); err != nil {
+				return err
+			}
+		}
+	}
+	if err := db.w.Flush(); err != nil {
+		return db.failed(err)
+	}
+	return nil
+}
+
+// Close commits the sessions added and waits for sqlite3 to finish.
+func (db *Database) Close() error {
+	db.w.WriteString("COMMIT;\n")
+	err := db.w.Flush()
+	if cerr := db.stdin.Close(); err == nil {
+		err = cerr
+	}
+	if werr := db.cmd.Wait(); werr != nil {
+		err = werr
+	}
+	if err != nil {
+		return db.failed(err)
+	}
+	return nil
+}
+
+// Abort stops sqlite3 without committing.
+func (db *Database) Abort() {
+	db.stdin.Close()
+	_ = db.cmd.Process.Kill()
+	_ = db.cmd.Wait()
+}
+
+// failed explains err with what sqlite3 printed, which is why it stopped
+// reading when a write to it failed.
+func (db *Database) failed(err error) error {
+	if msg := strings.TrimSpace(db.stderr.String()); msg != "" {
+		return fmt.Errorf("sqlite3 failed: %s", msg)
+	}
+	return fmt.Errorf("sqlite3 failed: %w", err)
+}
+
+// insert writes an INSERT of values into table. Errors writing to sqlite3
+// surface when the buffer is flushed.
+func (db *Database) insert(table string, values ...any) error {
+	var b strings.Builder
+	b.WriteString("INSERT INTO " + table + " VALUES (")
+	for i, v := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		lit, err := sqlValue(v)
+		if err != nil {
+			return fmt.Errorf("%s column %d: %w", table, i+1, err)
+		}
+		b.WriteString(lit)
+	}
+	b.WriteString(");\n")
+	db.w.WriteString(b.String())
+	return nil
+}
+
+// sqlValue renders v as an SQL literal. Empty strings and nil are NULL.
+func sqlValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		if v == "" {
+			return "NULL", nil
+		}
+		// SQL text literals can't hold NUL.
+		v = strings.ReplaceAll(v, "\x00", "")
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'", nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported SQL value type %T", v)
+	}
+}
+
+// sqlTime renders t as ISO 8601 text in UTC; the zero time is NULL.
+func sqlTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// writeSQLite builds a database of the one session and copies it to w.
+func writeSQLite(w io.Writer, session Session, entries []transcript.UnifiedEntry) error {
+	dir, err := os.MkdirTemp("", "aglogs-sqlite-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sessions.db")
+	db, err := CreateDatabase(path)
+	if err != nil {
+		return err
+	}
+	if err := db.Add(session, entries); err != nil {
+		db.Abort()
+		return err
+	}
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,83 @@
+// Package gitlog lists the commits made in a repository during a time
+// window, so agent sessions can be tied to the history they produced.
+package gitlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Commit is one commit from git log.
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"` // committer date
+	Subject string    `json:"subject"`
+}
+
+// Short is the commit's abbreviated hash.
+func (c Commit) Short() string {
+	if len(c.Hash) > 12 {
+		return c.Hash[:12]
+	}
+	return c.Hash
+}
+
+// fieldSep separates the fields of a --format line; subjects cannot
+// contain it.
+const fieldSep = "\x1f"
+
+// RefExists reports whether ref names a commit in the repository at dir.
+func RefExists(ctx context.Context, dir, ref string) bool {
+	return exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Run() == nil
+}
+
+// Between returns the commits reachable from ref whose committer date falls
+// in [since, until], newest first. An empty ref searches every ref in the
+// repository. A zero since or until leaves that end of the window open.
+func Between(ctx context.Context, dir, ref string, since, until time.Time) ([]Commit, error) {
+	args := []string{"-C", dir, "log", "--format=%H" + fieldSep + "%an" + fieldSep + "%cI" + fieldSep + "%s"}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		args = append(args, "--until="+until.Format(time.RFC3339))
+	}
+	if ref == "" {
+		args = append(args, "--all")
+	} else {
+		args = append(args, ref, "--")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git log in %s: %s", dir, msg)
+		}
+		return nil, fmt.Errorf("git log in %s: %w", dir, err)
+	}
+	return parse(out), nil
+}
+
+// parse reads the lines Between asks git log for, skipping any it cannot.
+func parse(out []byte) []Commit {
+	commits := []Commit{}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.SplitN(line, fieldSep, 4)
+		if len(fields) != 4 {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			continue
+		}
+		commits = append(commits, Commit{Hash: fields[0], Author: fields[1], Time: at, Subject: fields[3]})
+	}
+	return commits
+}
@@ -0,0 +1,78 @@
+package gitlog
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// commitAt makes an empty commit in dir with the given subject, dated at.
+func commitAt(t *testing.T, dir, subject string, at time.Time) {
+	t.Helper()
+	cmd := exec.Command("git", "-C", dir, "commit", "--allow-empty", "-q", "-m", subject)
+	date := at.Format(time.RFC3339)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Agent", "GIT_AUTHOR_EMAIL=agent@example.com", "GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME=Agent", "GIT_COMMITTER_EMAIL=agent@example.com", "GIT_COMMITTER_DATE="+date)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := filepath.Join(t.TempDir(), "repo")
+	git(t, t.TempDir(), "init", "-q", "-b", "main", dir)
+
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	commitAt(t, dir, "before the session", base.Add(-time.Hour))
+	git(t, dir, "checkout", "-q", "-b", "feat/login")
+	commitAt(t, dir, "add login form", base.Add(10*time.Minute))
+	commitAt(t, dir, "fix: validate passwords", base.Add(20*time.Minute))
+	git(t, dir, "checkout", "-q", "main")
+	commitAt(t, dir, "unrelated work on main", base.Add(15*time.Minute))
+
+	ctx := context.Background()
+	commits, err := Between(ctx, dir, "feat/login", base, base.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subjects []string
+	for _, c := range commits {
+		subjects = append(subjects, c.Subject)
+	}
+	if len(commits) != 2 || commits[0].Subject != "fix: validate passwords" || commits[1].Subject != "add login form" {
+		t.Fatalf("feat/login commits = %q, want the two session commits newest first", subjects)
+	}
+	if c := commits[1]; c.Author != "Agent" || !c.Time.Equal(base.Add(10*time.Minute)) || len(c.Short()) != 12 {
+		t.Errorf("commit = %+v", c)
+	}
+
+	// Without a ref every branch is searched.
+	all, err := Between(ctx, dir, "", base, base.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Errorf("all-refs commits = %d, want 3", len(all))
+	}
+
+	if !RefExists(ctx, dir, "feat/login") || RefExists(ctx, dir, "gone") {
+		t.Error("RefExists misreports branches")
+	}
+	if _, err := Between(ctx, t.TempDir(), "main", time.Time{}, time.Time{}); err == nil {
+		t.Error("Between outside a repository succeeded")
+	}
+}
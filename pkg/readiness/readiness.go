@@ -0,0 +1,278 @@
+// Package readiness judges from its transcript whether an agent session
+// looks finished enough to open a pull request from: the tests ran and
+// passed after its last file edit, its todo list is done, and no tool call
+// failed after the last edit. The checks are heuristics over what the
+// transcript records, meant as a gate for automated pipelines rather than
+// a substitute for review.
+//
+// Like pkg/stats, Evaluate is a pure fold over already-loaded entries, and
+// sidechain (subagent) entries are excluded.
+package readiness
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/shellcmds"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// Check names.
+const (
+	CheckTests    = "tests"    // tests ran and passed after the last edit
+	CheckTodos    = "todos"    // every item of the last todo list is done
+	CheckFailures = "failures" // no tool call failed after the last edit
+)
+
+// Check is the outcome of one finish criterion.
+type Check struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail"`
+}
+
+// Report is the outcome of every check for one session.
+type Report struct {
+	Passed bool    `json:"passed"`
+	Checks []Check `json:"checks"`
+	// LastEdit is when the agent last changed a file; zero when it changed
+	// none.
+	LastEdit time.Time `json:"lastEdit,omitzero"`
+}
+
+// Failed returns the checks that did not pass.
+func (r Report) Failed() []Check {
+	var out []Check
+	for _, c := range r.Checks {
+		if !c.Passed {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// maxListed caps how many todo items or failed calls a detail names.
+const maxListed = 3
+
+// testCommandRe finds a test runner invocation in a shell command.
+var testCommandRe = regexp.MustCompile(`(?:^|[\s;&|(])(?:go test|cargo (?:test|nextest)|(?:npm|pnpm|yarn|bun)(?: run)? test|npx (?:jest|vitest)|jest|vitest|py\.?test|python3? -m (?:pytest|unittest)|tox|nox|make (?:\S+ )*(?:test|check)|just test|mvn (?:\S+ )*(?:test|verify)|\./gradlew (?:\S+ )*test|gradle (?:\S+ )*test|dotnet test|mix test|rspec|bundle exec (?:rspec|rake test)|ctest|phpunit|deno test|swift test)\b`)
+
+// call is one tool call and how it ended.
+type call struct {
+	name    string
+	input   map[string]interface{}
+	at      time.Time
+	command string // the command text of shell calls
+	// finished is set once the call's result is in the transcript.
+	finished bool
+	failed   bool
+}
+
+// Evaluate runs every check over a session's entries.
+func Evaluate(entries []transcript.UnifiedEntry) Report {
+	calls := collectCalls(entries)
+	lastEdit := -1
+	for i, c := range calls {
+		if isEdit(c) && !c.failed {
+			lastEdit = i
+		}
+	}
+
+	var r Report
+	if lastEdit >= 0 {
+		r.LastEdit = calls[lastEdit].at
+	}
+	r.Checks = []Check{
+		checkTests(calls, lastEdit),
+		checkTodos(calls),
+		checkFailures(calls, lastEdit),
+	}
+	r.Passed = len(r.Failed()) == 0
+	return r
+}
+
+// collectCalls lists the main session's tool calls in order, each with
+// its result merged in.
+func collectCalls(entries []transcript.UnifiedEntry) []call {
+	var calls []call
+	byID := make(map[string]int) // call ID -> index into calls
+	for _, entry := range entries {
+		if entry.IsSidechain {
+			continue
+		}
+		for _, part := range entry.Parts {
+			switch part.Type {
+			case "tool_call":
+				tc := transcript.ToolCallOf(part)
+				c := call{name: tc.Name, input: tc.Input, at: entry.Timestamp, command: shellcmds.Script(tc.Name, tc.Input)}
+				// Claude and OpenCode merge the result into the call.
+				if tc.Output != "" || tc.ExitCode != nil || tc.Status == "error" || tc.Status == "completed" {
+					c.finish(tc.Output, tc.Status == "error", tc.ExitCode)
+				}
+				if tc.ID != "" {
+					byID[tc.ID] = len(calls)
+				}
+				calls = append(calls, c)
+			case "tool_result":
+				tr := transcript.ToolResultOf(part)
+				if i, ok := byID[tr.ToolCallID]; ok {
+					calls[i].finish(tr.Output, tr.IsError, tr.ExitCode)
+				}
+			}
+		}
+	}
+	return calls
+}
+
+func (c *call) finish(output string, isError bool, exitCode *int) {
+	c.finished = true
+	exitCode = shellcmds.ExitCode(output, isError, exitCode)
+	c.failed = c.failed || isError || (exitCode != nil && *exitCode != 0)
+}
+
+// isEdit reports whether a call changes files: an edit or write tool, or a
+// Codex patch applied through the shell.
+func isEdit(c call) bool {
+	switch strings.ToLower(c.name) {
+	case "edit", "write", "multiedit", "notebookedit", "apply_patch":
+		return true
+	}
+	return strings.Contains(c.command, "*** Begin Patch")
+}
+
+// checkTests passes when the last test run after the last edit passed.
+func checkTests(calls []call, lastEdit int) Check {
+	check := Check{Name: CheckTests}
+	if lastEdit < 0 {
+		check.Passed, check.Detail = true, "no file edits"
+		return check
+	}
+	last := -1
+	for i := lastEdit + 1; i < len(calls); i++ {
+		if testCommandRe.MatchString(calls[i].command) {
+			last = i
+		}
+	}
+	if last < 0 {
+		check.Detail = "no test run after the last edit"
+		if target := callTarget(calls[lastEdit].input); target != "" {
+			check.Detail += " (" + target + ")"
+		}
+		return check
+	}
+	run := calls[last]
+	switch {
+	case !run.finished:
+		check.Detail = fmt.Sprintf("%s has no recorded result", quoteCommand(run.command))
+	case run.failed:
+		check.Detail = fmt.Sprintf("%s failed after the last edit", quoteCommand(run.command))
+	default:
+		check.Passed = true
+		check.Detail = fmt.Sprintf("%s passed after the last edit", quoteCommand(run.command))
+	}
+	return check
+}
+
+// checkTodos passes when every item of the last todo list (Claude
+// TodoWrite, Codex update_plan) is completed or cancelled.
+func checkTodos(calls []call) Check {
+	check := Check{Name: CheckTodos}
+	var items []interface{}
+	var textKey string
+	found := false
+	for _, c := range calls {
+		switch strings.ToLower(c.name) {
+		case "todowrite":
+			items, _ = c.input["todos"].([]interface{})
+			textKey, found = "content", true
+		case transcript.ToolCodexUpdatePlan:
+			items, _ = c.input["plan"].([]interface{})
+			textKey, found = "step", true
+		}
+	}
+	if !found {
+		check.Passed, check.Detail = true, "no todo list"
+		return check
+	}
+	var open []string
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch transcript.StringField(m, "status") {
+		case "completed", "cancelled":
+			continue
+		}
+		open = append(open, transcript.StringField(m, textKey))
+	}
+	if len(open) == 0 {
+		check.Passed = true
+		check.Detail = fmt.Sprintf("all %d todo items completed", len(items))
+		return check
+	}
+	check.Detail = fmt.Sprintf("%d of %d todo items not completed: %s", len(open), len(items), listed(open))
+	return check
+}
+
+// checkFailures passes when no tool call failed after the last edit.
+func checkFailures(calls []call, lastEdit int) Check {
+	check := Check{Name: CheckFailures}
+	if lastEdit < 0 {
+		check.Passed, check.Detail = true, "no file edits"
+		return check
+	}
+	var failed []string
+	for _, c := range calls[lastEdit+1:] {
+		if !c.failed {
+			continue
+		}
+		label := c.name
+		if target := c.command; target != "" {
+			label += " " + quoteCommand(target)
+		} else if target := callTarget(c.input); target != "" {
+			label += " " + target
+		}
+		failed = append(failed, label)
+	}
+	if len(failed) == 0 {
+		check.Passed, check.Detail = true, "no failed tool calls after the last edit"
+		return check
+	}
+	check.Detail = fmt.Sprintf("%d failed tool call(s) after the last edit: %s", len(failed), listed(failed))
+	return check
+}
+
+// listed joins the first maxListed items, noting how many were left out.
+func listed(items []string) string {
+	if len(items) <= maxListed {
+		return strings.Join(items, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(items[:maxListed], "; "), len(items)-maxListed)
+}
+
+// quoteCommand backquotes the first line of a command, shortened to fit a
+// one-line detail.
+func quoteCommand(command string) string {
+	command, _, multiline := strings.Cut(strings.TrimSpace(command), "\n")
+	if runes := []rune(command); len(runes) > 60 {
+		command, multiline = string(runes[:60]), true
+	}
+	if multiline {
+		command += "…"
+	}
+	return "`" + command + "`"
+}
+
+// callTarget names what a call acted on, from the first of its input
+// fields that is set.
+func callTarget(input map[string]interface{}) string {
+	for _, key := range []string{"file_path", "filePath", "path", "notebook_path"} {
+		if s, ok := input[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
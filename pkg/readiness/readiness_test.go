@@ -0,0 +1,177 @@
+package readiness
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+var t0 = time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// claudeCall is a Claude tool call with its result merged in.
+func claudeCall(sec int, name string, input map[string]interface{}, output string, failed bool) transcript.UnifiedEntry {
+	tc := transcript.UnifiedToolCall{ID: name + output, Name: name, Input: input, Output: output}
+	if failed {
+		tc.Status = "error"
+	}
+	return transcript.UnifiedEntry{Role: "assistant", Timestamp: t0.Add(time.Duration(sec) * time.Second), Parts: []transcript.UnifiedPart{
+		{Type: "tool_call", Content: tc},
+	}}
+}
+
+func edit(sec int, path string) transcript.UnifiedEntry {
+	return claudeCall(sec, "Edit", map[string]interface{}{"file_path": path}, "The file has been updated.", false)
+}
+
+func bash(sec int, command, output string, failed bool) transcript.UnifiedEntry {
+	return claudeCall(sec, "Bash", map[string]interface{}{"command": command}, output, failed)
+}
+
+func todos(sec int, statuses ...string) transcript.UnifiedEntry {
+	var items []interface{}
+	for i, s := range statuses {
+		items = append(items, map[string]interface{}{"content": "step " + string(rune('A'+i)), "status": s})
+	}
+	return claudeCall(sec, "TodoWrite", map[string]interface{}{"todos": items}, "Todos have been modified successfully.", false)
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []transcript.UnifiedEntry
+		// want is the passed state of the tests, todos and failures checks.
+		want   [3]bool
+		detail string // in one of the details
+	}{
+		{
+			name: "ready",
+			entries: []transcript.UnifiedEntry{
+				todos(0, "in_progress", "pending"),
+				edit(10, "/repo/main.go"),
+				bash(20, "go test ./...", "FAIL", true),
+				edit(30, "/repo/main.go"),
+				bash(40, "cd /repo && go test ./...", "ok", false),
+				todos(50, "completed", "completed"),
+			},
+			want:   [3]bool{true, true, true},
+			detail: "`cd /repo && go test ./...` passed after the last edit",
+		},
+		{
+			name: "no tests after the last edit",
+			entries: []transcript.UnifiedEntry{
+				edit(10, "/repo/main.go"),
+				bash(20, "go test ./...", "ok", false),
+				edit(30, "/repo/README.md"),
+			},
+			want:   [3]bool{false, true, true},
+			detail: "no test run after the last edit (/repo/README.md)",
+		},
+		{
+			name: "last test run failed",
+			entries: []transcript.UnifiedEntry{
+				edit(10, "/repo/main.go"),
+				bash(20, "npm test", "1 failing", true),
+			},
+			want:   [3]bool{false, true, false},
+			detail: "`npm test` failed after the last edit",
+		},
+		{
+			name: "open todo items",
+			entries: []transcript.UnifiedEntry{
+				todos(0, "completed", "in_progress", "pending", "cancelled"),
+			},
+			want:   [3]bool{true, false, true},
+			detail: "2 of 4 todo items not completed: step B; step C",
+		},
+		{
+			name: "failed call after the last edit",
+			entries: []transcript.UnifiedEntry{
+				edit(10, "/repo/main.go"),
+				bash(20, "go test ./...", "ok", false),
+				bash(30, "git push", "rejected", true),
+			},
+			want:   [3]bool{true, true, false},
+			detail: "1 failed tool call(s) after the last edit: Bash `git push`",
+		},
+		{
+			name: "a failed edit is not the last edit",
+			entries: []transcript.UnifiedEntry{
+				edit(10, "/repo/main.go"),
+				bash(20, "go test ./...", "ok", false),
+				claudeCall(30, "Edit", map[string]interface{}{"file_path": "/repo/main.go"}, "String to replace not found", true),
+			},
+			// The tests still ran after the last change that landed, but the
+			// attempted one failed.
+			want:   [3]bool{true, true, false},
+			detail: "after the last edit: Edit /repo/main.go",
+		},
+		{
+			name: "no edits",
+			entries: []transcript.UnifiedEntry{
+				bash(20, "ls", "main.go", false),
+			},
+			want:   [3]bool{true, true, true},
+			detail: "no file edits",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Evaluate(tt.entries)
+			if len(r.Checks) != 3 {
+				t.Fatalf("checks = %+v", r.Checks)
+			}
+			var details []string
+			for i, c := range r.Checks {
+				if c.Passed != tt.want[i] {
+					t.Errorf("%s passed = %v, want %v (%s)", c.Name, c.Passed, tt.want[i], c.Detail)
+				}
+				details = append(details, c.Detail)
+			}
+			if r.Passed != (tt.want == [3]bool{true, true, true}) {
+				t.Errorf("Passed = %v", r.Passed)
+			}
+			if tt.detail != "" && !strings.Contains(strings.Join(details, "\n"), tt.detail) {
+				t.Errorf("details = %q, want one containing %q", details, tt.detail)
+			}
+		})
+	}
+}
+
+func TestEvaluateCodex(t *testing.T) {
+	zero, one := 0, 1
+	entry := func(sec int, part transcript.UnifiedPart) transcript.UnifiedEntry {
+		return transcript.UnifiedEntry{Timestamp: t0.Add(time.Duration(sec) * time.Second), Parts: []transcript.UnifiedPart{part}}
+	}
+	shell := func(id, script string) transcript.UnifiedPart {
+		return transcript.UnifiedPart{Type: "tool_call", Content: transcript.UnifiedToolCall{
+			ID: id, Name: "shell", Input: map[string]interface{}{"command": []interface{}{"bash", "-lc", script}},
+		}}
+	}
+	result := func(id string, code *int) transcript.UnifiedPart {
+		return transcript.UnifiedPart{Type: "tool_result", Content: transcript.UnifiedToolResult{ToolCallID: id, ExitCode: code}}
+	}
+	entries := []transcript.UnifiedEntry{
+		entry(0, shell("p1", "apply_patch <<'EOF'\n*** Begin Patch\n*** Update File: lib.rs\n*** End Patch\nEOF")),
+		entry(1, result("p1", &zero)),
+		entry(2, shell("t1", "cargo test")),
+		entry(9, result("t1", &one)),
+		entry(10, transcript.UnifiedPart{Type: "tool_call", Content: transcript.UnifiedToolCall{
+			ID: "u1", Name: "update_plan", Input: map[string]interface{}{"plan": []interface{}{
+				map[string]interface{}{"step": "fix lib.rs", "status": "completed"},
+			}},
+		}}),
+	}
+
+	r := Evaluate(entries)
+	if r.Passed || r.LastEdit.IsZero() {
+		t.Fatalf("report = %+v, want a failure after the patch", r)
+	}
+	if c := r.Checks[0]; c.Passed || c.Detail != "`cargo test` failed after the last edit" {
+		t.Errorf("tests check = %+v", c)
+	}
+	if c := r.Checks[1]; !c.Passed {
+		t.Errorf("todos check = %+v", c)
+	}
+}
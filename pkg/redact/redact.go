@@ -0,0 +1,255 @@
+// Package redact rewrites transcript text before it leaves aglogs through
+// export or serve. Redaction is pluggable: built-in regular expressions,
+// an external filter command (text on stdin, redacted text on stdout) such
+// as an organization's DLP tool, or a WASI module run as such a filter by
+// a WebAssembly runtime. Configured redactors are chained in that order.
+package redact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// Mask replaces text matched by a pattern.
+const Mask = "[REDACTED]"
+
+const (
+	// DefaultTimeout bounds one run of a filter command.
+	DefaultTimeout = 10 * time.Second
+	// DefaultWASMRuntime runs WASM modules: "<runtime> run <module>".
+	DefaultWASMRuntime = "wasmtime"
+	// filterCacheSize bounds the results a filter remembers; serve re-sends
+	// entries as they grow, and the same text must not be re-filtered.
+	filterCacheSize = 4096
+)
+
+// Redactor rewrites one piece of text.
+type Redactor interface {
+	Redact(ctx context.Context, text string) (string, error)
+}
+
+// Options selects the redactors to chain.
+type Options struct {
+	// Patterns are regular expressions whose matches become Mask.
+	Patterns []string
+	// Command is a filter's argv: it gets text on stdin and must print
+	// the redacted text on stdout and exit 0.
+	Command []string
+	// WASM is a WASI module that filters stdin to stdout like Command,
+	// run with WASMRuntime (default DefaultWASMRuntime).
+	WASM        string
+	WASMRuntime string
+	// Timeout bounds each filter run; zero means DefaultTimeout.
+	Timeout time.Duration
+}
+
+// New builds the redactor for opts, or returns nil when opts configure
+// none.
+func New(opts Options) (Redactor, error) {
+	var chain Chain
+	if len(opts.Patterns) > 0 {
+		p, err := NewPatterns(opts.Patterns)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, p)
+	}
+	if len(opts.Command) > 0 {
+		chain = append(chain, NewFilter(opts.Command, opts.Timeout))
+	}
+	if opts.WASM != "" {
+		runtime := opts.WASMRuntime
+		if runtime == "" {
+			runtime = DefaultWASMRuntime
+		}
+		if _, err := exec.LookPath(runtime); err != nil {
+			return nil, fmt.Errorf("WASM redaction needs the %q runtime: %w", runtime, err)
+		}
+		chain = append(chain, NewFilter([]string{runtime, "run", opts.WASM}, opts.Timeout))
+	}
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	return chain, nil
+}
+
+// Chain applies redactors in order.
+type Chain []Redactor
+
+func (c Chain) Redact(ctx context.Context, text string) (string, error) {
+	for _, r := range c {
+		var err error
+		if text, err = r.Redact(ctx, text); err != nil {
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+// Patterns masks every match of its expressions.
+type Patterns []*regexp.Regexp
+
+// NewPatterns compiles exprs.
+func NewPatterns(exprs []string) (Patterns, error) {
+	p := make(Patterns, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", expr, err)
+		}
+		p = append(p, re)
+	}
+	return p, nil
+}
+
+func (p Patterns) Redact(_ context.Context, text string) (string, error) {
+	for _, re := range p {
+		text = re.ReplaceAllLiteralString(text, Mask)
+	}
+	return text, nil
+}
+
+// Filter runs an external command per piece of text. A failing or
+// timed-out command is an error, never a pass-through: callers must not
+// release text that was not redacted.
+type Filter struct {
+	Argv    []string
+	Timeout time.Duration
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewFilter returns a filter running argv; a zero timeout means
+// DefaultTimeout.
+func NewFilter(argv []string, timeout time.Duration) *Filter {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Filter{Argv: argv, Timeout: timeout, cache: make(map[string]string)}
+}
+
+func (f *Filter) Redact(ctx context.Context, text string) (string, error) {
+	f.mu.Lock()
+	out, ok := f.cache[text]
+	f.mu.Unlock()
+	if ok {
+		return out, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, f.Argv[0], f.Argv[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", f.Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", fmt.Errorf("redaction filter %s failed: %w", f.Argv[0], err)
+	}
+	out = stdout.String()
+
+	f.mu.Lock()
+	if len(f.cache) >= filterCacheSize {
+		clear(f.cache)
+	}
+	f.cache[text] = out
+	f.mu.Unlock()
+	return out, nil
+}
+
+// structuralKeys are part content fields that identify rather than carry
+// content; they are never redacted so that results still match their
+// calls and renderers still recognize tools.
+var structuralKeys = map[string]bool{
+	"id": true, "toolCallID": true, "name": true, "status": true, "isError": true,
+	"durationMs": true, "exitCode": true, "active": true,
+}
+
+// Entry returns e with every text value in its parts redacted: message
+// and reasoning text, tool inputs (at any depth), outputs, diffs, and hook
+// and command output. Part content comes back in its JSON map shape.
+func Entry(ctx context.Context, r Redactor, e transcript.UnifiedEntry) (transcript.UnifiedEntry, error) {
+	if r == nil {
+		return e, nil
+	}
+	parts := make([]transcript.UnifiedPart, len(e.Parts))
+	for i, part := range e.Parts {
+		data, err := json.Marshal(part.Content)
+		if err != nil {
+			return e, err
+		}
+		var content interface{}
+		if err := json.Unmarshal(data, &content); err != nil {
+			return e, err
+		}
+		if content, err = redactValue(ctx, r, content, true); err != nil {
+			return e, err
+		}
+		parts[i] = transcript.UnifiedPart{Type: part.Type, Content: content}
+	}
+	e.Parts = parts
+	return e, nil
+}
+
+// Entries redacts each entry in turn.
+func Entries(ctx context.Context, r Redactor, entries []transcript.UnifiedEntry) ([]transcript.UnifiedEntry, error) {
+	if r == nil {
+		return entries, nil
+	}
+	out := make([]transcript.UnifiedEntry, len(entries))
+	for i, e := range entries {
+		var err error
+		if out[i], err = Entry(ctx, r, e); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// redactValue redacts the strings in a decoded JSON value in place. top
+// marks a part's content object, whose structural keys are kept.
+func redactValue(ctx context.Context, r Redactor, v interface{}, top bool) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return v, nil
+		}
+		return r.Redact(ctx, v)
+	case map[string]interface{}:
+		for key, item := range v {
+			if top && structuralKeys[key] {
+				continue
+			}
+			redacted, err := redactValue(ctx, r, item, false)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = redacted
+		}
+	case []interface{}:
+		for i, item := range v {
+			redacted, err := redactValue(ctx, r, item, false)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = redacted
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,68 @@
+package redact
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestEntryRedactsAllText(t *testing.T) {
+	r, err := New(Options{Patterns: []string{`sk-[a-z0-9]+`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := transcript.UnifiedEntry{Role: "assistant", Parts: []transcript.UnifiedPart{
+		{Type: "text", Content: transcript.UnifiedTextContent{Text: "key is sk-abc123"}},
+		{Type: "tool_call", Content: transcript.UnifiedToolCall{
+			ID: "sk-id1", Name: "Bash",
+			Input:  map[string]interface{}{"command": "curl -H 'Authorization: sk-def456'", "env": []interface{}{"K=sk-x9"}},
+			Output: "ok sk-ghi789",
+		}},
+	}}
+
+	got, err := Entry(context.Background(), r, entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := got.Parts[0].Content.(map[string]interface{})
+	call := got.Parts[1].Content.(map[string]interface{})
+	input := call["input"].(map[string]interface{})
+	if text["text"] != "key is [REDACTED]" || call["output"] != "ok [REDACTED]" ||
+		input["command"] != "curl -H 'Authorization: [REDACTED]'" || input["env"].([]interface{})[0] != "K=[REDACTED]" {
+		t.Errorf("not fully redacted: %+v", got.Parts)
+	}
+	// IDs link results to calls and must survive.
+	if call["id"] != "sk-id1" || call["name"] != "Bash" {
+		t.Errorf("structural fields changed: %+v", call)
+	}
+	// The original entry is untouched.
+	if entry.Parts[0].Content.(transcript.UnifiedTextContent).Text != "key is sk-abc123" {
+		t.Error("input entry was modified")
+	}
+}
+
+func TestFilterCommand(t *testing.T) {
+	if _, err := exec.LookPath("sed"); err != nil {
+		t.Skip("sed not installed")
+	}
+	r, err := New(Options{Patterns: []string{`alice`}, Command: []string{"sed", "s/[0-9]\\{3\\}-[0-9]\\{4\\}/XXX-XXXX/g"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Redact(context.Background(), "call alice at 555-1234")
+	if err != nil || got != "call [REDACTED] at XXX-XXXX" {
+		t.Errorf("Redact = %q, %v", got, err)
+	}
+
+	failing := NewFilter([]string{"sh", "-c", "echo 'dlp down' >&2; exit 3"}, 0)
+	if _, err := failing.Redact(context.Background(), "secret"); err == nil || !strings.Contains(err.Error(), "dlp down") {
+		t.Errorf("failing filter error = %v, want one mentioning stderr", err)
+	}
+
+	if r, err := New(Options{}); r != nil || err != nil {
+		t.Errorf("New with no options = %v, %v; want nil, nil", r, err)
+	}
+}
@@ -0,0 +1,404 @@
+// Package sessiondiff compares two normalized transcripts of the same task,
+// typically one plan job run by two different agents. Transcripts are split
+// into turns at each user prompt, turns are aligned by prompt text, and the
+// tool calls within each aligned pair are diffed after being reduced to what
+// they did (ran a command, read or edited a file), so that calls from
+// different providers compare equal when they had the same effect.
+package sessiondiff
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/agentlogs/pkg/shellcmds"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// ActionKind classifies a tool call.
+type ActionKind string
+
+const (
+	ActionShell  ActionKind = "shell"
+	ActionRead   ActionKind = "read"
+	ActionEdit   ActionKind = "edit"
+	ActionSearch ActionKind = "search"
+	ActionTool   ActionKind = "tool" // anything else, compared by tool name
+)
+
+// Action is one tool call reduced to its effect.
+type Action struct {
+	Kind   ActionKind `json:"kind"`
+	Target string     `json:"target"` // command, file path, pattern, or tool name
+	Tool   string     `json:"tool"`   // the provider's own tool name
+}
+
+func (a Action) key() string {
+	return string(a.Kind) + "\x00" + a.Target
+}
+
+// Turn is one user prompt and the agent activity that followed it, up to
+// the next prompt. Activity before the first prompt forms a turn with an
+// empty Prompt.
+type Turn struct {
+	Prompt  string   `json:"prompt"`
+	Actions []Action `json:"actions"`
+	Edited  []string `json:"edited,omitempty"` // distinct edited files, sorted
+}
+
+// Op is one step of an action-sequence diff: "=" in both, "-" only in A,
+// "+" only in B.
+type Op struct {
+	Op     string `json:"op"`
+	Action Action `json:"action"`
+}
+
+// TurnDiff is one aligned pair of turns. A or B is nil when a prompt
+// appears in only one transcript.
+type TurnDiff struct {
+	A        *Turn    `json:"a,omitempty"`
+	B        *Turn    `json:"b,omitempty"`
+	Diverged bool     `json:"diverged"`
+	Ops      []Op     `json:"ops,omitempty"`
+	EditedA  []string `json:"editedOnlyA,omitempty"`
+	EditedB  []string `json:"editedOnlyB,omitempty"`
+}
+
+// Result is the full comparison.
+type Result struct {
+	Turns []TurnDiff `json:"turns"`
+	// DivergedAt is the index in Turns of the first pair that differs, or
+	// -1 when the transcripts did the same things.
+	DivergedAt int      `json:"divergedAt"`
+	EditedBoth []string `json:"editedBoth,omitempty"`
+	EditedA    []string `json:"editedOnlyA,omitempty"`
+	EditedB    []string `json:"editedOnlyB,omitempty"`
+}
+
+// Options tunes Compare.
+type Options struct {
+	// RootA and RootB are the working directories of the two sessions.
+	// File paths under them are made relative so that runs in different
+	// worktrees still line up.
+	RootA, RootB string
+}
+
+// Compare splits both transcripts into turns, aligns them, and diffs the
+// actions of each aligned pair.
+func Compare(a, b []transcript.UnifiedEntry, opts Options) Result {
+	turnsA := Turns(a, opts.RootA)
+	turnsB := Turns(b, opts.RootB)
+
+	res := Result{DivergedAt: -1}
+	for _, pair := range alignTurns(turnsA, turnsB) {
+		td := TurnDiff{A: pair[0], B: pair[1]}
+		switch {
+		case td.A == nil || td.B == nil:
+			td.Diverged = true
+		default:
+			td.Ops = diffActions(td.A.Actions, td.B.Actions)
+			td.EditedA, td.EditedB, _ = splitSets(td.A.Edited, td.B.Edited)
+			for _, op := range td.Ops {
+				if op.Op != "=" {
+					td.Diverged = true
+					break
+				}
+			}
+		}
+		if td.Diverged && res.DivergedAt < 0 {
+			res.DivergedAt = len(res.Turns)
+		}
+		res.Turns = append(res.Turns, td)
+	}
+
+	res.EditedA, res.EditedB, res.EditedBoth = splitSets(editedFiles(turnsA), editedFiles(turnsB))
+	return res
+}
+
+// Turns splits a transcript at each user prompt. Paths under root are made
+// relative to it.
+func Turns(entries []transcript.UnifiedEntry, root string) []Turn {
+	var turns []Turn
+	var cur *Turn
+	for _, entry := range entries {
+		if entry.Role == "user" {
+			if prompt := entryText(entry); prompt != "" {
+				turns = append(turns, Turn{Prompt: prompt})
+				cur = &turns[len(turns)-1]
+				continue
+			}
+		}
+		for _, part := range entry.Parts {
+			if part.Type != "tool_call" {
+				continue
+			}
+			call := transcript.ToolCallOf(part)
+			if call.Name == "" {
+				continue
+			}
+			if cur == nil {
+				turns = append(turns, Turn{})
+				cur = &turns[len(turns)-1]
+			}
+			cur.Actions = append(cur.Actions, classify(call, root)...)
+		}
+	}
+	for i := range turns {
+		turns[i].Edited = editedFiles(turns[i : i+1])
+	}
+	return turns
+}
+
+func editedFiles(turns []Turn) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, t := range turns {
+		for _, a := range t.Actions {
+			if a.Kind == ActionEdit && !seen[a.Target] {
+				seen[a.Target] = true
+				files = append(files, a.Target)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// splitSets partitions two sorted string sets.
+func splitSets(a, b []string) (onlyA, onlyB, both []string) {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+	inA := make(map[string]bool, len(a))
+	for _, s := range a {
+		inA[s] = true
+		if inB[s] {
+			both = append(both, s)
+		} else {
+			onlyA = append(onlyA, s)
+		}
+	}
+	for _, s := range b {
+		if !inA[s] {
+			onlyB = append(onlyB, s)
+		}
+	}
+	return onlyA, onlyB, both
+}
+
+// --- Alignment ---
+
+// alignTurns pairs turns whose prompts match, keeping both orders (a
+// longest common subsequence). Unmatched turns are paired with nil.
+func alignTurns(a, b []Turn) [][2]*Turn {
+	match := func(i, j int) bool { return promptsMatch(a[i].Prompt, b[j].Prompt) }
+	var pairs [][2]*Turn
+	for _, step := range lcs(len(a), len(b), match) {
+		var pair [2]*Turn
+		if step.i >= 0 {
+			pair[0] = &a[step.i]
+		}
+		if step.j >= 0 {
+			pair[1] = &b[step.j]
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// diffActions diffs two action sequences.
+func diffActions(a, b []Action) []Op {
+	match := func(i, j int) bool { return a[i].key() == b[j].key() }
+	var ops []Op
+	for _, step := range lcs(len(a), len(b), match) {
+		switch {
+		case step.i >= 0 && step.j >= 0:
+			ops = append(ops, Op{Op: "=", Action: a[step.i]})
+		case step.i >= 0:
+			ops = append(ops, Op{Op: "-", Action: a[step.i]})
+		default:
+			ops = append(ops, Op{Op: "+", Action: b[step.j]})
+		}
+	}
+	return ops
+}
+
+// lcsStep is one step of an alignment: a matched pair, or an index on one
+// side with -1 on the other.
+type lcsStep struct{ i, j int }
+
+// lcs aligns two sequences of lengths n and m by their longest common
+// subsequence under match. Unmatched items from A come before those from
+// B within each gap.
+func lcs(n, m int, match func(i, j int) bool) []lcsStep {
+	// table[i][j] is the LCS length of a[i:] and b[j:].
+	table := make([][]int, n+1)
+	for i := range table {
+		table[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if match(i, j) {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = max(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+
+	var steps []lcsStep
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case match(i, j) && table[i][j] == table[i+1][j+1]+1:
+			steps = append(steps, lcsStep{i, j})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			steps = append(steps, lcsStep{i, -1})
+			i++
+		default:
+			steps = append(steps, lcsStep{-1, j})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		steps = append(steps, lcsStep{i, -1})
+	}
+	for ; j < m; j++ {
+		steps = append(steps, lcsStep{-1, j})
+	}
+	return steps
+}
+
+// promptsMatch reports whether two prompts are the same request. Providers
+// and harnesses wrap prompts differently, so beyond an exact match (after
+// normalizing case and whitespace) prompts that share most of their words
+// count as the same.
+func promptsMatch(a, b string) bool {
+	na, nb := normalizePrompt(a), normalizePrompt(b)
+	if na == nb {
+		return true
+	}
+	wa, wb := strings.Fields(na), strings.Fields(nb)
+	if len(wa) == 0 || len(wb) == 0 {
+		return false
+	}
+	set := make(map[string]bool, len(wa))
+	for _, w := range wa {
+		set[w] = true
+	}
+	shared, union := 0, len(set)
+	seen := make(map[string]bool, len(wb))
+	for _, w := range wb {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		if set[w] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared)/float64(union) >= 0.8
+}
+
+func normalizePrompt(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// --- Classification ---
+
+// patchFileRe matches the file headers of a Codex apply_patch envelope.
+var patchFileRe = regexp.MustCompile(`(?m)^\*\*\* (?:Add|Update|Delete) File: (.+)$`)
+
+// classify reduces one tool call to its effects. Codex patches can touch
+// several files, so a call may yield more than one action.
+func classify(call transcript.UnifiedToolCall, root string) []Action {
+	name := strings.ToLower(call.Name)
+	action := func(kind ActionKind, target string) []Action {
+		return []Action{{Kind: kind, Target: target, Tool: call.Name}}
+	}
+
+	if cmd := shellcmds.Script(name, call.Input); cmd != "" {
+		if files := patchFiles(cmd, root, call.Input); len(files) > 0 {
+			return editActions(call.Name, files)
+		}
+		return action(ActionShell, strings.Join(strings.Fields(cmd), " "))
+	}
+
+	switch name {
+	case "apply_patch":
+		if files := patchFiles(inputString(call.Input, "input", "patch", "arguments"), root, call.Input); len(files) > 0 {
+			return editActions(call.Name, files)
+		}
+	case "edit", "write", "multiedit", "notebookedit", "patch":
+		if path := inputString(call.Input, "file_path", "filePath", "path", "notebook_path"); path != "" {
+			return action(ActionEdit, relPath(path, root, call.Input))
+		}
+	case "read", "view":
+		if path := inputString(call.Input, "file_path", "filePath", "path"); path != "" {
+			return action(ActionRead, relPath(path, root, call.Input))
+		}
+	case "grep", "glob", "find", "ls", "list":
+		return action(ActionSearch, name+" "+inputString(call.Input, "pattern", "query", "path"))
+	}
+	return action(ActionTool, call.Name)
+}
+
+func editActions(tool string, files []string) []Action {
+	actions := make([]Action, len(files))
+	for i, f := range files {
+		actions[i] = Action{Kind: ActionEdit, Target: f, Tool: tool}
+	}
+	return actions
+}
+
+func patchFiles(patch, root string, input map[string]interface{}) []string {
+	var files []string
+	for _, m := range patchFileRe.FindAllStringSubmatch(patch, -1) {
+		files = append(files, relPath(strings.TrimSpace(m[1]), root, input))
+	}
+	return files
+}
+
+// relPath makes an absolute path relative to root, or to the call's own
+// working directory (Codex's workdir argument) when that is under root.
+func relPath(path, root string, input map[string]interface{}) string {
+	if !filepath.IsAbs(path) {
+		if wd := inputString(input, "workdir", "cwd"); wd != "" {
+			path = filepath.Join(wd, path)
+		} else {
+			return filepath.Clean(path)
+		}
+	}
+	if root != "" {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return filepath.Clean(path)
+}
+
+func inputString(input map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := input[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// entryText joins the text parts of entry.
+func entryText(entry transcript.UnifiedEntry) string {
+	var texts []string
+	for _, part := range entry.Parts {
+		if part.Type == "text" {
+			texts = append(texts, transcript.TextOf(part))
+		}
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n"))
+}
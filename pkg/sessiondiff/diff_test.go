@@ -0,0 +1,80 @@
+package sessiondiff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func prompt(text string) transcript.UnifiedEntry {
+	return transcript.UnifiedEntry{Role: "user", Parts: []transcript.UnifiedPart{
+		{Type: "text", Content: transcript.UnifiedTextContent{Text: text}},
+	}}
+}
+
+func call(name string, input map[string]interface{}) transcript.UnifiedEntry {
+	return transcript.UnifiedEntry{Role: "assistant", Parts: []transcript.UnifiedPart{
+		{Type: "tool_call", Content: transcript.UnifiedToolCall{Name: name, Input: input}},
+	}}
+}
+
+func TestCompareAcrossProviders(t *testing.T) {
+	claude := []transcript.UnifiedEntry{
+		prompt("Implement the login form"),
+		call("Read", map[string]interface{}{"file_path": "/wa/src/login.go"}),
+		call("Edit", map[string]interface{}{"file_path": "/wa/src/login.go"}),
+		call("Bash", map[string]interface{}{"command": "go test ./..."}),
+		prompt("Now add docs"),
+		call("Write", map[string]interface{}{"file_path": "/wa/README.md"}),
+	}
+	codex := []transcript.UnifiedEntry{
+		prompt("implement the  login form"),
+		call("read", map[string]interface{}{"path": "src/login.go", "workdir": "/wb"}),
+		call("apply_patch", map[string]interface{}{"input": "*** Begin Patch\n*** Update File: /wb/src/login.go\n@@\n*** Add File: /wb/src/login_test.go\n*** End Patch"}),
+		call("shell", map[string]interface{}{"command": []interface{}{"bash", "-lc", "go  test ./..."}}),
+	}
+
+	res := Compare(claude, codex, Options{RootA: "/wa", RootB: "/wb"})
+	if len(res.Turns) != 2 {
+		t.Fatalf("got %d turns, want 2: %+v", len(res.Turns), res.Turns)
+	}
+
+	first := res.Turns[0]
+	if first.A == nil || first.B == nil || !first.Diverged {
+		t.Fatalf("first turn should align both sides and diverge: %+v", first)
+	}
+	var ops []string
+	for _, op := range first.Ops {
+		ops = append(ops, op.Op+" "+string(op.Action.Kind)+" "+op.Action.Target)
+	}
+	want := []string{"= read src/login.go", "= edit src/login.go", "+ edit src/login_test.go", "= shell go test ./..."}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("ops = %v, want %v", ops, want)
+	}
+	if !reflect.DeepEqual(first.EditedB, []string{"src/login_test.go"}) || first.EditedA != nil {
+		t.Errorf("edited only A=%v B=%v", first.EditedA, first.EditedB)
+	}
+
+	if second := res.Turns[1]; second.A == nil || second.B != nil {
+		t.Errorf("second prompt should exist only in A: %+v", second)
+	}
+	if res.DivergedAt != 0 {
+		t.Errorf("DivergedAt = %d, want 0", res.DivergedAt)
+	}
+	if !reflect.DeepEqual(res.EditedBoth, []string{"src/login.go"}) ||
+		!reflect.DeepEqual(res.EditedA, []string{"README.md"}) ||
+		!reflect.DeepEqual(res.EditedB, []string{"src/login_test.go"}) {
+		t.Errorf("edited both=%v A=%v B=%v", res.EditedBoth, res.EditedA, res.EditedB)
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	entries := []transcript.UnifiedEntry{
+		prompt("fix it"),
+		call("Bash", map[string]interface{}{"command": "make"}),
+	}
+	if res := Compare(entries, entries, Options{}); res.DivergedAt != -1 || res.Turns[0].Diverged {
+		t.Errorf("identical transcripts diverged: %+v", res)
+	}
+}
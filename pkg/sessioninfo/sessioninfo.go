@@ -31,3 +31,10 @@ type JobInfo = session.JobInfo
 func Resolve(spec string) (*Info, error) {
 	return session.ResolveSessionInfo(spec)
 }
+
+// JobMarker returns the structured marker identifying a plan job. Runners
+// embed it in the prompt that starts the job so the session is attributed to
+// the job however the prompt is worded; path is the job file.
+func JobMarker(plan, job, path string) string {
+	return session.FormatJobMarker(plan, job, path)
+}
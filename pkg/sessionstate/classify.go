@@ -0,0 +1,131 @@
+package sessionstate
+
+import (
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// Session states, from Classify.
+const (
+	// StateRunning: the agent is working, by recent transcript writes.
+	StateRunning = "running"
+	// StateIdle: quiet in the middle of a turn, neither waiting on the
+	// user nor finished (a stalled tool, or an agent that was killed).
+	StateIdle = "idle"
+	// StateAwaitingInput: waiting on the user for an answer, an approval,
+	// or the next prompt.
+	StateAwaitingInput = "awaiting_input"
+	// StateCompleted: the agent ended its turn and nobody came back.
+	StateCompleted = "completed"
+	// StateErrored: the session stopped on an error.
+	StateErrored = "errored"
+)
+
+const (
+	// ActiveWindow is how recently a transcript must have been written for
+	// its session to count as running.
+	ActiveWindow = 2 * time.Minute
+	// CompletedAfter is how long a session that ended its turn waits for
+	// the next prompt before it counts as completed.
+	CompletedAfter = time.Hour
+)
+
+// errorStopReasons are the stop reasons of a model that did not finish its
+// turn normally.
+var errorStopReasons = map[string]bool{"error": true, "aborted": true, "max_tokens": true, "refusal": true}
+
+// Tail is what the end of a transcript says about its session: the part of
+// its state that doesn't depend on the clock, so it can be cached with the
+// transcript.
+type Tail struct {
+	// LastActivity is the time of the last entry.
+	LastActivity time.Time `json:"lastActivity,omitzero"`
+	// Wait is what the session waits on its user for (see AwaitingInput).
+	Wait *Wait `json:"wait,omitempty"`
+	// Error is set when the session stopped on an error: a stop reason
+	// like "max_tokens", or a failed tool call with nothing after it.
+	Error string `json:"error,omitempty"`
+}
+
+// Inspect reads the tail of a transcript.
+func Inspect(entries []transcript.UnifiedEntry) Tail {
+	var t Tail
+	if n := len(entries); n > 0 {
+		t.LastActivity = entries[n-1].Timestamp
+	}
+	e, ok := lastConversational(entries)
+	if !ok {
+		return t
+	}
+	if e.Role == "assistant" && errorStopReasons[e.StopReason] {
+		t.Error = "stopped: " + e.StopReason
+		return t
+	}
+	if msg, failed := failedLast(e); failed {
+		t.Error = msg
+		return t
+	}
+	t.Wait = AwaitingInput(entries)
+	return t
+}
+
+// failedLast reports whether an entry ends in a failed tool call.
+func failedLast(e transcript.UnifiedEntry) (string, bool) {
+	if len(e.Parts) == 0 {
+		return "", false
+	}
+	part := e.Parts[len(e.Parts)-1]
+	switch part.Type {
+	case "tool_call":
+		if call := transcript.ToolCallOf(part); call.Status == "error" {
+			return clip("tool failed: " + toolLabel(call)), true
+		}
+	case "tool_result":
+		switch c := part.Content.(type) {
+		case transcript.UnifiedToolResult:
+			if c.IsError {
+				return clip("tool failed: " + c.Output), true
+			}
+		case map[string]interface{}:
+			if isError, _ := c["isError"].(bool); isError {
+				output, _ := c["output"].(string)
+				return clip("tool failed: " + output), true
+			}
+		}
+	}
+	return "", false
+}
+
+// Classify returns a session's state as of now, from its tail and the last
+// time its transcript was written (which may be later than its last entry).
+func Classify(t Tail, lastWrite, now time.Time) string {
+	last := t.LastActivity
+	if lastWrite.After(last) {
+		last = lastWrite
+	}
+	recent := !last.IsZero() && now.Sub(last) < ActiveWindow
+	switch {
+	case t.Error != "":
+		// The agent may be about to retry.
+		if recent {
+			return StateRunning
+		}
+		return StateErrored
+	case t.Wait == nil:
+		if recent {
+			return StateRunning
+		}
+		return StateIdle
+	case t.Wait.Reason == ReasonTurnEnded:
+		if !last.IsZero() && now.Sub(last) >= CompletedAfter {
+			return StateCompleted
+		}
+		return StateAwaitingInput
+	case t.Wait.Reason == ReasonPermission && recent:
+		// A call awaiting approval looks like one still running.
+		return StateRunning
+	default:
+		return StateAwaitingInput
+	}
+}
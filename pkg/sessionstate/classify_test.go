@@ -0,0 +1,56 @@
+package sessionstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestClassify(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	reply := func(text, stop string) transcript.UnifiedEntry {
+		return transcript.UnifiedEntry{Role: "assistant", Timestamp: t0, StopReason: stop,
+			Parts: []transcript.UnifiedPart{{Type: "text", Content: transcript.UnifiedTextContent{Text: text}}}}
+	}
+	pending := transcript.UnifiedEntry{Role: "assistant", Timestamp: t0, Parts: []transcript.UnifiedPart{
+		{Type: "tool_call", Content: transcript.UnifiedToolCall{Name: "Bash", Input: map[string]interface{}{"command": "make"}}}}}
+	failed := transcript.UnifiedEntry{Role: "user", Timestamp: t0, Parts: []transcript.UnifiedPart{
+		{Type: "tool_result", Content: transcript.UnifiedToolResult{ToolCallID: "b1", Output: "exit 2", IsError: true}}}}
+	prompt := transcript.UnifiedEntry{Role: "user", Timestamp: t0, Parts: []transcript.UnifiedPart{
+		{Type: "text", Content: transcript.UnifiedTextContent{Text: "go on"}}}}
+
+	tests := []struct {
+		name    string
+		entries []transcript.UnifiedEntry
+		after   time.Duration
+		want    string
+	}{
+		{"working", []transcript.UnifiedEntry{prompt}, time.Minute, StateRunning},
+		{"quiet mid-turn", []transcript.UnifiedEntry{prompt}, 10 * time.Minute, StateIdle},
+		{"question", []transcript.UnifiedEntry{reply("Ship it?", "end_turn")}, 10 * time.Second, StateAwaitingInput},
+		{"ended turn", []transcript.UnifiedEntry{reply("Done.", "end_turn")}, 5 * time.Minute, StateAwaitingInput},
+		{"ended long ago", []transcript.UnifiedEntry{reply("Done.", "end_turn")}, 2 * time.Hour, StateCompleted},
+		{"tool running", []transcript.UnifiedEntry{pending}, 30 * time.Second, StateRunning},
+		{"approval", []transcript.UnifiedEntry{pending}, 5 * time.Minute, StateAwaitingInput},
+		{"max tokens", []transcript.UnifiedEntry{reply("The file is", "max_tokens")}, 5 * time.Minute, StateErrored},
+		{"failed tool", []transcript.UnifiedEntry{pending, failed}, 5 * time.Minute, StateErrored},
+		{"failed tool, retrying", []transcript.UnifiedEntry{pending, failed}, 10 * time.Second, StateRunning},
+	}
+	for _, tt := range tests {
+		tail := Inspect(tt.entries)
+		if got := Classify(tail, time.Time{}, t0.Add(tt.after)); got != tt.want {
+			t.Errorf("%s: Classify = %s, want %s (tail %+v)", tt.name, got, tt.want, tail)
+		}
+	}
+
+	// A transcript written after its last entry (a line still being
+	// streamed) counts as activity.
+	tail := Inspect([]transcript.UnifiedEntry{prompt})
+	if got := Classify(tail, t0.Add(9*time.Minute), t0.Add(10*time.Minute)); got != StateRunning {
+		t.Errorf("recent write: Classify = %s, want running", got)
+	}
+	if got := Inspect([]transcript.UnifiedEntry{pending, failed}).Error; got != "tool failed: exit 2" {
+		t.Errorf("Error = %q", got)
+	}
+}
@@ -0,0 +1,189 @@
+// Package sessionstate infers what a session is doing from the end of its
+// transcript, such as waiting on the user for an answer or a permission.
+package sessionstate
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// Reason says what a waiting session is waiting for.
+type Reason string
+
+const (
+	// ReasonQuestion: the assistant's last message asks the user something.
+	ReasonQuestion Reason = "question"
+	// ReasonPermission: the assistant's last tool call never got a result,
+	// which is how a call awaiting the user's approval looks in the log (a
+	// call that is still running looks the same).
+	ReasonPermission Reason = "permission"
+	// ReasonTurnEnded: the assistant finished its turn without asking
+	// anything, and the session sits at its prompt until the user writes.
+	ReasonTurnEnded Reason = "turn_ended"
+)
+
+// messageWidth caps Wait.Message.
+const messageWidth = 200
+
+// Wait describes a session waiting on its user.
+type Wait struct {
+	Reason Reason `json:"reason"`
+	// Since is when the session started waiting: the time of its last entry.
+	Since time.Time `json:"since"`
+	// Message is the question, or the tool call awaiting approval.
+	Message string `json:"message"`
+}
+
+// Stalled reports whether the session has been waiting for at least idle
+// as of now.
+func (w *Wait) Stalled(now time.Time, idle time.Duration) bool {
+	return w != nil && !w.Since.IsZero() && now.Sub(w.Since) >= idle
+}
+
+// Waiting returns what the session is waiting on its user for, or nil when
+// its last entry doesn't wait on the user. Hook and other system entries
+// are skipped; sub-agent entries don't count, since only the main agent
+// talks to the user.
+func Waiting(entries []transcript.UnifiedEntry) *Wait {
+	e, ok := lastConversational(entries)
+	if !ok || e.Role != "assistant" {
+		return nil
+	}
+	return assistantWait(e)
+}
+
+// endTurnReasons are the stop reasons recorded when the model finished its
+// turn, rather than stopping to call a tool or at a length limit.
+var endTurnReasons = map[string]bool{"end_turn": true, "stop": true, "stop_sequence": true}
+
+// AwaitingInput is Waiting, but also counts a session whose assistant
+// explicitly ended its turn, by its recorded stop reason, as waiting for
+// the next prompt. Every finished session ends that way, so this suits
+// watching live sessions rather than auditing old ones.
+func AwaitingInput(entries []transcript.UnifiedEntry) *Wait {
+	if w := Waiting(entries); w != nil {
+		return w
+	}
+	e, ok := lastConversational(entries)
+	if !ok || e.Role != "assistant" || !endTurnReasons[e.StopReason] {
+		return nil
+	}
+	var text string
+	for _, part := range e.Parts {
+		if t := strings.TrimSpace(transcript.TextOf(part)); part.Type == "text" && t != "" {
+			text = t
+		}
+	}
+	return &Wait{Reason: ReasonTurnEnded, Since: e.Timestamp, Message: clip(text)}
+}
+
+// lastConversational returns the last main-agent entry that is part of the
+// conversation.
+func lastConversational(entries []transcript.UnifiedEntry) (transcript.UnifiedEntry, bool) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Role == "system" || e.IsSidechain || !conversational(e) {
+			continue
+		}
+		return e, true
+	}
+	return transcript.UnifiedEntry{}, false
+}
+
+func conversational(e transcript.UnifiedEntry) bool {
+	for _, part := range e.Parts {
+		switch part.Type {
+		case "text", "image", "tool_call", "tool_result", "command", "command_output":
+			return true
+		}
+	}
+	return false
+}
+
+// questionTools ask the user directly; plan approval asks for permission.
+var questionTools = map[string]bool{"AskUserQuestion": true, "ask_user": true, "question": true}
+
+func assistantWait(e transcript.UnifiedEntry) *Wait {
+	var text string
+	for i := len(e.Parts) - 1; i >= 0; i-- {
+		part := e.Parts[i]
+		switch part.Type {
+		case "tool_call":
+			call := transcript.ToolCallOf(part)
+			if answered(call) {
+				return nil
+			}
+			if questionTools[call.Name] {
+				return &Wait{Reason: ReasonQuestion, Since: e.Timestamp, Message: clip(toolQuestion(call))}
+			}
+			return &Wait{Reason: ReasonPermission, Since: e.Timestamp, Message: clip(toolLabel(call))}
+		case "text":
+			if text = strings.TrimSpace(transcript.TextOf(part)); text != "" {
+				if q, ok := finalQuestion(text); ok {
+					return &Wait{Reason: ReasonQuestion, Since: e.Timestamp, Message: clip(q)}
+				}
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// answered reports whether a call's result is merged into it. Providers
+// that log results separately never leave a call last once it ran.
+func answered(call transcript.UnifiedToolCall) bool {
+	return call.Output != "" || call.ExitCode != nil || call.Status == "error" || call.Status == "completed"
+}
+
+var askPhraseRe = regexp.MustCompile(`(?i)\b(let me know|would you like|do you want|should i|shall i|please confirm|which (one|option))\b`)
+
+// finalQuestion returns the last paragraph of text when it asks the user
+// something: a sentence ending in "?" or a phrase like "let me know".
+func finalQuestion(text string) (string, bool) {
+	paragraphs := strings.Split(strings.TrimSpace(text), "\n\n")
+	last := strings.TrimSpace(paragraphs[len(paragraphs)-1])
+	for _, line := range strings.Split(last, "\n") {
+		line = strings.TrimRight(strings.TrimSpace(line), "*_)`\"' ")
+		if strings.HasSuffix(line, "?") {
+			return last, true
+		}
+	}
+	if askPhraseRe.MatchString(last) {
+		return last, true
+	}
+	return "", false
+}
+
+func toolQuestion(call transcript.UnifiedToolCall) string {
+	if q, ok := call.Input["question"].(string); ok && q != "" {
+		return q
+	}
+	if qs, ok := call.Input["questions"].([]interface{}); ok && len(qs) > 0 {
+		if q, ok := qs[0].(map[string]interface{}); ok {
+			if s, ok := q["question"].(string); ok {
+				return s
+			}
+		}
+	}
+	return call.Name
+}
+
+func toolLabel(call transcript.UnifiedToolCall) string {
+	for _, key := range []string{"command", "file_path", "path", "url", "pattern"} {
+		if s, ok := call.Input[key].(string); ok && s != "" {
+			return call.Name + ": " + s
+		}
+	}
+	return call.Name
+}
+
+func clip(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > messageWidth {
+		return string(runes[:messageWidth-1]) + "…"
+	}
+	return s
+}
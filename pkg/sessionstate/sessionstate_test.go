@@ -0,0 +1,84 @@
+package sessionstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestWaiting(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	text := func(role, s string) transcript.UnifiedEntry {
+		return transcript.UnifiedEntry{Role: role, Timestamp: t0, Parts: []transcript.UnifiedPart{{Type: "text", Content: transcript.UnifiedTextContent{Text: s}}}}
+	}
+	call := func(c transcript.UnifiedToolCall) transcript.UnifiedEntry {
+		return transcript.UnifiedEntry{Role: "assistant", Timestamp: t0, Parts: []transcript.UnifiedPart{{Type: "tool_call", Content: c}}}
+	}
+	hook := transcript.UnifiedEntry{Role: "system", Timestamp: t0.Add(time.Minute), Parts: []transcript.UnifiedPart{{Type: "hook", Content: transcript.UnifiedHook{Event: "Stop"}}}}
+
+	tests := []struct {
+		name    string
+		entries []transcript.UnifiedEntry
+		reason  Reason
+		message string
+	}{
+		{"question", []transcript.UnifiedEntry{text("user", "fix it"), text("assistant", "Done with the parser.\n\nShould I also update the **docs**?"), hook},
+			ReasonQuestion, "Should I also update the **docs**?"},
+		{"ask phrase", []transcript.UnifiedEntry{text("assistant", "Two options: A or B. Let me know which you prefer.")}, ReasonQuestion, "Two options: A or B. Let me know which you prefer."},
+		{"statement", []transcript.UnifiedEntry{text("assistant", "Why did it fail? A typo.\n\nAll tests pass now.")}, "", ""},
+		{"user last", []transcript.UnifiedEntry{text("assistant", "Continue?"), text("user", "yes")}, "", ""},
+		{"pending call", []transcript.UnifiedEntry{call(transcript.UnifiedToolCall{Name: "Bash", Input: map[string]interface{}{"command": "rm -rf build"}})},
+			ReasonPermission, "Bash: rm -rf build"},
+		{"answered call", []transcript.UnifiedEntry{call(transcript.UnifiedToolCall{Name: "Bash", Output: "ok"})}, "", ""},
+		{"ask tool", []transcript.UnifiedEntry{call(transcript.UnifiedToolCall{Name: "AskUserQuestion", Input: map[string]interface{}{
+			"questions": []interface{}{map[string]interface{}{"question": "Which database?"}}}})}, ReasonQuestion, "Which database?"},
+		{"map shape", []transcript.UnifiedEntry{{Role: "assistant", Timestamp: t0, Parts: []transcript.UnifiedPart{{Type: "text", Content: map[string]interface{}{"text": "Proceed?"}}}}},
+			ReasonQuestion, "Proceed?"},
+	}
+	for _, tt := range tests {
+		w := Waiting(tt.entries)
+		if tt.reason == "" {
+			if w != nil {
+				t.Errorf("%s: Waiting = %+v, want nil", tt.name, w)
+			}
+			continue
+		}
+		if w == nil || w.Reason != tt.reason || w.Message != tt.message || !w.Since.Equal(t0) {
+			t.Errorf("%s: Waiting = %+v, want %s %q", tt.name, w, tt.reason, tt.message)
+		}
+	}
+
+	w := &Wait{Since: t0}
+	if w.Stalled(t0.Add(5*time.Minute), 10*time.Minute) || !w.Stalled(t0.Add(10*time.Minute), 10*time.Minute) {
+		t.Error("Stalled threshold")
+	}
+	if (*Wait)(nil).Stalled(t0, 0) {
+		t.Error("nil Wait is stalled")
+	}
+}
+
+func TestAwaitingInput(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	reply := func(text, stop string) transcript.UnifiedEntry {
+		return transcript.UnifiedEntry{Role: "assistant", Timestamp: t0, StopReason: stop,
+			Parts: []transcript.UnifiedPart{{Type: "text", Content: transcript.UnifiedTextContent{Text: text}}}}
+	}
+
+	if w := AwaitingInput([]transcript.UnifiedEntry{reply("All tests pass now.", "end_turn")}); w == nil || w.Reason != ReasonTurnEnded || w.Message != "All tests pass now." {
+		t.Errorf("ended turn: AwaitingInput = %+v", w)
+	}
+	if w := AwaitingInput([]transcript.UnifiedEntry{reply("Shall I push?", "end_turn")}); w == nil || w.Reason != ReasonQuestion {
+		t.Errorf("question: AwaitingInput = %+v, want a question", w)
+	}
+	// Without a recorded stop reason the turn may still be under way.
+	if w := AwaitingInput([]transcript.UnifiedEntry{reply("Running the tests.", "")}); w != nil {
+		t.Errorf("no stop reason: AwaitingInput = %+v, want nil", w)
+	}
+	if w := AwaitingInput([]transcript.UnifiedEntry{reply("Running the tests.", "tool_use")}); w != nil {
+		t.Errorf("tool_use: AwaitingInput = %+v, want nil", w)
+	}
+	if w := Waiting([]transcript.UnifiedEntry{reply("All tests pass now.", "end_turn")}); w != nil {
+		t.Errorf("Waiting counted an ended turn: %+v", w)
+	}
+}
@@ -0,0 +1,230 @@
+// Package shellcmds lists the shell commands an agent ran, from the shell
+// tool calls in a normalized transcript (Claude Bash, Codex shell and
+// exec_command, OpenCode and pi bash), with their exit status and run time
+// where the log records them.
+package shellcmds
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// Command is one shell tool invocation.
+type Command struct {
+	// Seq is the position of the calling entry in the transcript.
+	Seq       int       `json:"seq"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	Tool      string    `json:"tool"`
+	Command   string    `json:"command"`
+	// Workdir is the directory the command ran in when the call names one
+	// (Codex); empty means the session's working directory.
+	Workdir string `json:"workdir,omitempty"`
+	// ExitCode is nil when the log records no status: the command is
+	// still running, was backgrounded, or the provider only reports
+	// success or failure.
+	ExitCode *int `json:"exitCode,omitempty"`
+	// Failed is set for a non-zero exit code, or a result marked as an
+	// error when the code itself is unknown.
+	Failed     bool   `json:"failed"`
+	DurationMs int64  `json:"durationMs,omitempty"`
+	Output     string `json:"output,omitempty"`
+	// Finished is set once the command's result is in the transcript.
+	Finished bool `json:"finished"`
+}
+
+var (
+	// exitedRe finds the status line Codex exec_command and pi append to
+	// output: "Process exited with code 2", "Command exited with code 2".
+	exitedRe = regexp.MustCompile(`(?:Process|Command) exited with code (-?\d+)`)
+	// exitCodeRe finds the status line of a failed Claude Bash call
+	// ("Exit code 2"); only trusted on results marked as errors, since a
+	// successful command may print anything.
+	exitCodeRe = regexp.MustCompile(`(?m)^(?:Error: )?Exit code:? (-?\d+)$`)
+)
+
+// Extract returns the shell commands in entries, in order.
+func Extract(entries []transcript.UnifiedEntry) []Command {
+	var cmds []Command
+	byID := make(map[string]int) // call ID -> index in cmds
+
+	for seq, entry := range entries {
+		for _, part := range entry.Parts {
+			switch part.Type {
+			case "tool_call":
+				call := transcript.ToolCallOf(part)
+				script := Script(call.Name, call.Input)
+				if script == "" {
+					continue
+				}
+				cmd := Command{
+					Seq:        seq,
+					Timestamp:  entry.Timestamp,
+					Tool:       call.Name,
+					Command:    script,
+					Workdir:    inputString(call.Input, "workdir", "cwd"),
+					DurationMs: call.DurationMs,
+					ExitCode:   call.ExitCode,
+				}
+				// Claude and OpenCode merge the result into the call.
+				if call.Output != "" || call.ExitCode != nil || call.Status == "error" || call.Status == "completed" {
+					cmd.finish(call.Output, call.Status == "error", call.ExitCode)
+				}
+				if call.ID != "" {
+					byID[call.ID] = len(cmds)
+				}
+				cmds = append(cmds, cmd)
+			case "tool_result":
+				result := transcript.ToolResultOf(part)
+				i, ok := byID[result.ToolCallID]
+				if !ok {
+					continue
+				}
+				cmd := &cmds[i]
+				cmd.finish(result.Output, result.IsError, result.ExitCode)
+				switch {
+				case result.DurationMs > 0:
+					cmd.DurationMs = result.DurationMs
+				case cmd.DurationMs == 0 && !cmd.Timestamp.IsZero() && entry.Timestamp.After(cmd.Timestamp):
+					cmd.DurationMs = entry.Timestamp.Sub(cmd.Timestamp).Milliseconds()
+				}
+			}
+		}
+	}
+	return cmds
+}
+
+func (c *Command) finish(output string, isError bool, exitCode *int) {
+	c.Finished = true
+	c.Output = output
+	c.ExitCode = ExitCode(output, isError, exitCode)
+	c.Failed = isError || (c.ExitCode != nil && *c.ExitCode != 0)
+}
+
+// ExitCode returns the exit code of a shell call whose result is output:
+// exitCode when the log records one, otherwise the code in the status line
+// the provider appended to output, or nil when there is none.
+func ExitCode(output string, isError bool, exitCode *int) *int {
+	if exitCode != nil {
+		return exitCode
+	}
+	m := exitedRe.FindStringSubmatch(output)
+	if m == nil && isError {
+		m = exitCodeRe.FindStringSubmatch(output)
+	}
+	if m == nil {
+		return nil
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// Failed returns only the commands that failed.
+func Failed(cmds []Command) []Command {
+	var out []Command
+	for _, c := range cmds {
+		if c.Failed {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// WriteScript writes cmds as a bash script that replays them in order.
+// Each command is preceded by a comment with when it ran and how it
+// ended, and a cd when its working directory differs from the previous
+// one. Commands run from root (or with no workdir) get no cd.
+func WriteScript(w io.Writer, cmds []Command, header, root string) error {
+	var sb strings.Builder
+	sb.WriteString("#!/usr/bin/env bash\n")
+	for _, line := range strings.Split(strings.TrimSpace(header), "\n") {
+		sb.WriteString("# " + line + "\n")
+	}
+	sb.WriteString("# Review every command before running this script.\n")
+	if root != "" {
+		fmt.Fprintf(&sb, "cd %s || exit 1\n", shellQuote(root))
+	}
+	dir := root
+	for _, c := range cmds {
+		sb.WriteString("\n# " + c.Status())
+		if !c.Timestamp.IsZero() {
+			sb.WriteString(", " + c.Timestamp.Local().Format("2006-01-02 15:04:05"))
+		}
+		sb.WriteString("\n")
+		if c.Workdir != "" && c.Workdir != dir {
+			fmt.Fprintf(&sb, "cd %s\n", shellQuote(c.Workdir))
+			dir = c.Workdir
+		}
+		sb.WriteString(strings.TrimRight(c.Command, "\n") + "\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Status describes how a command ended: "exit 0", "exit 2", "failed"
+// (error without a code), "ok" (success without a code), or "no result".
+func (c Command) Status() string {
+	switch {
+	case c.ExitCode != nil:
+		return fmt.Sprintf("exit %d", *c.ExitCode)
+	case c.Failed:
+		return "failed"
+	case c.Finished:
+		return "ok"
+	}
+	return "no result"
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:@%+=,") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Script returns the command text of a shell tool call, or "" for other
+// tools. Claude, pi and OpenCode pass a string; Codex passes an argv
+// array, usually ["bash", "-lc", script], whose script is returned.
+func Script(tool string, input map[string]interface{}) string {
+	switch strings.ToLower(tool) {
+	case "bash", "shell", "exec_command", "local_shell", "container.exec":
+	default:
+		return ""
+	}
+	switch cmd := input["command"].(type) {
+	case string:
+		return cmd
+	case []interface{}:
+		var argv []string
+		for _, a := range cmd {
+			if s, ok := a.(string); ok {
+				argv = append(argv, s)
+			}
+		}
+		if len(argv) == 3 && (argv[1] == "-lc" || argv[1] == "-c") && strings.HasSuffix(argv[0], "sh") {
+			return argv[2]
+		}
+		for i, a := range argv {
+			argv[i] = shellQuote(a)
+		}
+		return strings.Join(argv, " ")
+	}
+	return inputString(input, "cmd")
+}
+
+func inputString(input map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := input[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
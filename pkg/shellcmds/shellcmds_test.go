@@ -0,0 +1,60 @@
+package shellcmds
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func entry(ts time.Time, content interface{}) transcript.UnifiedEntry {
+	typ := "tool_call"
+	if _, ok := content.(transcript.UnifiedToolResult); ok {
+		typ = "tool_result"
+	}
+	return transcript.UnifiedEntry{Role: "assistant", Timestamp: ts, Parts: []transcript.UnifiedPart{{Type: typ, Content: content}}}
+}
+
+func TestExtract(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	zero := 0
+	entries := []transcript.UnifiedEntry{
+		// Claude: result merged into the call.
+		entry(t0, transcript.UnifiedToolCall{ID: "c1", Name: "Bash", Input: map[string]interface{}{"command": "go test ./..."},
+			Status: "error", Output: "Exit code 2\nFAIL", DurationMs: 1500}),
+		entry(t0, transcript.UnifiedToolCall{ID: "c2", Name: "Read", Input: map[string]interface{}{"file_path": "x"}}),
+		// Codex: separate result with structured metadata.
+		entry(t0, transcript.UnifiedToolCall{ID: "c3", Name: "shell", Input: map[string]interface{}{
+			"command": []interface{}{"bash", "-lc", "make build"}, "workdir": "/repo/sub"}}),
+		entry(t0.Add(time.Second), transcript.UnifiedToolResult{ToolCallID: "c3", ExitCode: &zero, DurationMs: 300}),
+		// pi: status only in the output text; duration from timestamps.
+		entry(t0, transcript.UnifiedToolCall{ID: "c4", Name: "bash", Input: map[string]interface{}{"command": "false"}}),
+		entry(t0.Add(2*time.Second), transcript.UnifiedToolResult{ToolCallID: "c4", IsError: true, Output: "\n\nCommand exited with code 1"}),
+		// Never answered.
+		entry(t0, transcript.UnifiedToolCall{ID: "c5", Name: "bash", Input: map[string]interface{}{"command": "sleep 100"}}),
+	}
+
+	cmds := Extract(entries)
+	var got []string
+	for _, c := range cmds {
+		got = append(got, c.Command+"|"+c.Status()+"|"+time.Duration(c.DurationMs*int64(time.Millisecond)).String())
+	}
+	want := []string{"go test ./...|exit 2|1.5s", "make build|exit 0|300ms", "false|exit 1|2s", "sleep 100|no result|0s"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if failed := Failed(cmds); len(failed) != 2 || failed[1].Command != "false" {
+		t.Errorf("Failed = %+v", failed)
+	}
+
+	var script strings.Builder
+	if err := WriteScript(&script, cmds[:2], "test session", "/repo"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"#!/usr/bin/env bash\n# test session\n", "cd /repo || exit 1\n", "# exit 2, ", "go test ./...\n", "cd /repo/sub\nmake build\n"} {
+		if !strings.Contains(script.String(), want) {
+			t.Errorf("script missing %q:\n%s", want, script.String())
+		}
+	}
+}
@@ -0,0 +1,359 @@
+// Package stats computes per-tool usage statistics from a normalized agent
+// transcript: call counts, failures, time spent in each tool, the bytes
+// each tool put into context, and the slowest shell commands and largest
+// calls. It answers "where did this job's time (and context) go?".
+//
+// Like pkg/metrics, Compute is a pure fold over already-loaded entries.
+// Durations run from the entry carrying a tool call to the entry carrying
+// its result. Claude merges results into the call, so there the normalizer's
+// UnifiedToolCall.DurationMs is used instead. OpenCode records neither.
+package stats
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/codelang"
+	"github.com/grovetools/agentlogs/pkg/metrics"
+	"github.com/grovetools/agentlogs/pkg/shellcmds"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// DefaultTopCommands is how many of the longest-running commands are kept.
+const DefaultTopCommands = 5
+
+// DefaultOutlierBytes is the size of a tool call's input plus output from
+// which it is reported as an outlier.
+const DefaultOutlierBytes = 100 * 1024
+
+// DefaultTopOutliers is how many outlier calls are kept.
+const DefaultTopOutliers = 10
+
+// DefaultIdleThreshold is the silence between consecutive messages above
+// which the gap counts as idle time rather than work.
+const DefaultIdleThreshold = 5 * time.Minute
+
+// Options tunes Compute. Zero values select the defaults.
+type Options struct {
+	TopCommands   int
+	IdleThreshold time.Duration
+	OutlierBytes  int64
+	TopOutliers   int
+}
+
+// IdleGap is a silence between two consecutive messages longer than the
+// idle threshold (the agent waiting on a human, or a stalled session).
+type IdleGap struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Seconds float64   `json:"seconds"`
+}
+
+// Timing summarizes when a session ran and how much of it was spent idle.
+type Timing struct {
+	StartedAt        time.Time `json:"started_at,omitzero"`
+	EndedAt          time.Time `json:"ended_at,omitzero"`
+	WallClockSeconds float64   `json:"wall_clock_seconds"`
+	// ActiveSeconds is wall clock minus the idle gaps.
+	ActiveSeconds        float64   `json:"active_seconds"`
+	IdleSeconds          float64   `json:"idle_seconds"`
+	IdleThresholdSeconds float64   `json:"idle_threshold_seconds"`
+	IdleGaps             []IdleGap `json:"idle_gaps,omitempty"`
+}
+
+// ToolStats aggregates every call to one tool.
+type ToolStats struct {
+	Name   string `json:"name"`
+	Calls  int    `json:"calls"`
+	Failed int    `json:"failed"`
+	// Timed counts the calls whose duration could be measured; the
+	// duration fields cover only those calls.
+	Timed        int     `json:"timed"`
+	TotalSeconds float64 `json:"total_seconds"`
+	MaxSeconds   float64 `json:"max_seconds"`
+	// InputBytes and OutputBytes total the JSON-encoded call inputs and the
+	// results' output text, roughly what the calls added to the context.
+	InputBytes     int64 `json:"input_bytes"`
+	OutputBytes    int64 `json:"output_bytes"`
+	MaxOutputBytes int64 `json:"max_output_bytes"`
+}
+
+// CommandRun is one timed shell command.
+type CommandRun struct {
+	Command   string    `json:"command"`
+	Seconds   float64   `json:"seconds"`
+	Failed    bool      `json:"failed,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// CallSize is the size of one tool call, for the outlier report.
+type CallSize struct {
+	Tool string `json:"tool"`
+	// Target is what the call acted on: its command, file, URL or pattern.
+	Target      string    `json:"target,omitempty"`
+	InputBytes  int64     `json:"input_bytes"`
+	OutputBytes int64     `json:"output_bytes"`
+	At          time.Time `json:"at,omitzero"`
+}
+
+// Bytes is the call's input plus output.
+func (c CallSize) Bytes() int64 { return c.InputBytes + c.OutputBytes }
+
+// Result is the statistics fold for one session.
+type Result struct {
+	SessionID     string       `json:"session_id"`
+	Provider      string       `json:"provider"`
+	ToolCalls     int          `json:"tool_calls"`
+	FailedResults int          `json:"failed_results"`
+	Tools         []ToolStats  `json:"tools"`
+	FilesTouched  []string     `json:"files_touched,omitempty"`
+	FilesEdited   []string     `json:"files_edited,omitempty"`
+	LongestRuns   []CommandRun `json:"longest_commands,omitempty"`
+	// Outliers are the calls of at least the outlier size, largest first:
+	// the reads and commands that blew up the context.
+	Outliers []CallSize `json:"outliers,omitempty"`
+	// FilesUnsupported is set when the provider's tools expose no structured
+	// file paths, so empty file lists mean "not measured" rather than none.
+	FilesUnsupported bool `json:"files_unsupported,omitempty"`
+	// Languages counts the entries whose code is mostly in each language,
+	// most first.
+	Languages []codelang.Breakdown `json:"languages,omitempty"`
+	Timing    Timing               `json:"timing"`
+}
+
+// pendingCall is a tool call waiting for its result.
+type pendingCall struct {
+	tool    string
+	command string
+	at      time.Time
+}
+
+// Compute folds entries into tool statistics and session timing, keeping
+// the opts.TopCommands slowest shell commands. Sidechain entries are
+// excluded, matching pkg/metrics.
+func Compute(entries []transcript.UnifiedEntry, opts Options) Result {
+	topN := opts.TopCommands
+	if topN <= 0 {
+		topN = DefaultTopCommands
+	}
+	idleThreshold := opts.IdleThreshold
+	if idleThreshold <= 0 {
+		idleThreshold = DefaultIdleThreshold
+	}
+	outlierBytes := opts.OutlierBytes
+	if outlierBytes <= 0 {
+		outlierBytes = DefaultOutlierBytes
+	}
+	topOutliers := opts.TopOutliers
+	if topOutliers <= 0 {
+		topOutliers = DefaultTopOutliers
+	}
+
+	var result Result
+	byName := make(map[string]*ToolStats)
+	var order []string
+	pending := make(map[string]pendingCall)
+	var runs []CommandRun
+	var sizes []CallSize
+	sizeOf := make(map[string]int) // call ID -> index into sizes
+	languages := make(map[string]int)
+
+	tool := func(name string) *ToolStats {
+		ts, ok := byName[name]
+		if !ok {
+			ts = &ToolStats{Name: name}
+			byName[name] = ts
+			order = append(order, name)
+		}
+		return ts
+	}
+
+	for _, entry := range entries {
+		if entry.IsSidechain {
+			continue
+		}
+		if result.Provider == "" && entry.Provider != "" {
+			result.Provider = entry.Provider
+		}
+		if lang := codelang.Entry(entry); lang != "" {
+			languages[lang]++
+		}
+		for _, part := range entry.Parts {
+			switch part.Type {
+			case "tool_call":
+				call := transcript.ToolCallOf(part)
+				name := call.Name
+				if name == "" {
+					name = "unknown"
+				}
+				ts := tool(name)
+				ts.Calls++
+				result.ToolCalls++
+				size := CallSize{Tool: name, Target: shellcmds.Script(name, call.Input), InputBytes: jsonSize(call.Input), At: entry.Timestamp}
+				if size.Target == "" {
+					size.Target = callTarget(call.Input)
+				}
+				ts.InputBytes += size.InputBytes
+				if call.ID != "" {
+					sizeOf[call.ID] = len(sizes)
+				}
+				sizes = append(sizes, size)
+				// Claude and OpenCode merge the output into the call.
+				addOutput(ts, &sizes[len(sizes)-1], int64(len(call.Output)))
+				// Claude and OpenCode report completion inline on the call.
+				if call.Status == "error" {
+					ts.Failed++
+					result.FailedResults++
+				}
+				if call.DurationMs > 0 {
+					secs := float64(call.DurationMs) / 1000
+					recordTiming(ts, secs)
+					if cmd := shellcmds.Script(name, call.Input); cmd != "" {
+						runs = append(runs, CommandRun{Command: cmd, Seconds: secs, Failed: call.Status == "error", StartedAt: entry.Timestamp})
+					}
+					continue
+				}
+				if call.ID != "" {
+					pending[call.ID] = pendingCall{tool: name, command: shellcmds.Script(name, call.Input), at: entry.Timestamp}
+				}
+			case "tool_result":
+				r := transcript.ToolResultOf(part)
+				if i, ok := sizeOf[r.ToolCallID]; ok {
+					addOutput(tool(sizes[i].Tool), &sizes[i], int64(len(r.Output)))
+				}
+				call, ok := pending[r.ToolCallID]
+				if r.IsError {
+					result.FailedResults++
+					if ok {
+						tool(call.tool).Failed++
+					}
+				}
+				if !ok {
+					continue
+				}
+				delete(pending, r.ToolCallID)
+				if call.at.IsZero() || entry.Timestamp.IsZero() || entry.Timestamp.Before(call.at) {
+					continue
+				}
+				secs := entry.Timestamp.Sub(call.at).Seconds()
+				recordTiming(tool(call.tool), secs)
+				if call.command != "" {
+					runs = append(runs, CommandRun{Command: call.command, Seconds: secs, Failed: r.IsError, StartedAt: call.at})
+				}
+			}
+		}
+	}
+
+	for _, name := range order {
+		result.Tools = append(result.Tools, *byName[name])
+	}
+	sort.SliceStable(result.Tools, func(i, j int) bool {
+		if result.Tools[i].TotalSeconds != result.Tools[j].TotalSeconds {
+			return result.Tools[i].TotalSeconds > result.Tools[j].TotalSeconds
+		}
+		return result.Tools[i].Calls > result.Tools[j].Calls
+	})
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Seconds > runs[j].Seconds })
+	if len(runs) > topN {
+		runs = runs[:topN]
+	}
+	result.LongestRuns = runs
+
+	for _, size := range sizes {
+		if size.Bytes() >= outlierBytes {
+			result.Outliers = append(result.Outliers, size)
+		}
+	}
+	sort.SliceStable(result.Outliers, func(i, j int) bool { return result.Outliers[i].Bytes() > result.Outliers[j].Bytes() })
+	if len(result.Outliers) > topOutliers {
+		result.Outliers = result.Outliers[:topOutliers]
+	}
+	if len(languages) > 0 {
+		result.Languages = codelang.Sorted(languages)
+	}
+
+	result.Timing = computeTiming(entries, idleThreshold)
+
+	// File touches use the pkg/metrics vocabulary so both commands agree.
+	m := metrics.Compute(entries)
+	result.FilesTouched = m.TouchedFiles
+	result.FilesEdited = m.EditedFiles
+	result.FilesUnsupported = len(m.Unsupported) > 0
+
+	return result
+}
+
+// computeTiming measures the session span and the idle gaps between
+// consecutive (non-sidechain) messages.
+func computeTiming(entries []transcript.UnifiedEntry, threshold time.Duration) Timing {
+	t := Timing{IdleThresholdSeconds: threshold.Seconds()}
+	var prev time.Time
+	for _, entry := range entries {
+		if entry.IsSidechain || entry.Timestamp.IsZero() {
+			continue
+		}
+		ts := entry.Timestamp
+		if t.StartedAt.IsZero() || ts.Before(t.StartedAt) {
+			t.StartedAt = ts
+		}
+		if ts.After(t.EndedAt) {
+			t.EndedAt = ts
+		}
+		if !prev.IsZero() {
+			if gap := ts.Sub(prev); gap > threshold {
+				t.IdleGaps = append(t.IdleGaps, IdleGap{Start: prev, End: ts, Seconds: gap.Seconds()})
+				t.IdleSeconds += gap.Seconds()
+			}
+		}
+		prev = ts
+	}
+	if !t.StartedAt.IsZero() {
+		t.WallClockSeconds = t.EndedAt.Sub(t.StartedAt).Seconds()
+		t.ActiveSeconds = t.WallClockSeconds - t.IdleSeconds
+	}
+	return t
+}
+
+// recordTiming adds one measured call duration to a tool's totals.
+func recordTiming(ts *ToolStats, secs float64) {
+	ts.Timed++
+	ts.TotalSeconds += secs
+	if secs > ts.MaxSeconds {
+		ts.MaxSeconds = secs
+	}
+}
+
+// addOutput adds n bytes of output to a call and its tool's totals.
+func addOutput(ts *ToolStats, size *CallSize, n int64) {
+	size.OutputBytes += n
+	ts.OutputBytes += n
+	if size.OutputBytes > ts.MaxOutputBytes {
+		ts.MaxOutputBytes = size.OutputBytes
+	}
+}
+
+// jsonSize is the length of a call input encoded as JSON, as it is sent to
+// the model.
+func jsonSize(input map[string]interface{}) int64 {
+	if len(input) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
+// callTarget names what a call acted on, from the first of its input
+// fields that is set.
+func callTarget(input map[string]interface{}) string {
+	for _, key := range []string{"command", "file_path", "filePath", "path", "url", "pattern", "query"} {
+		if s, ok := input[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/codelang"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func call(id, name string, input map[string]interface{}) transcript.UnifiedPart {
+	return transcript.UnifiedPart{Type: "tool_call", Content: transcript.UnifiedToolCall{ID: id, Name: name, Input: input}}
+}
+
+func result(id string, isError bool) transcript.UnifiedPart {
+	return transcript.UnifiedPart{Type: "tool_result", Content: transcript.UnifiedToolResult{ToolCallID: id, IsError: isError}}
+}
+
+func TestCompute(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return t0.Add(time.Duration(sec) * time.Second) }
+
+	entries := []transcript.UnifiedEntry{
+		{Role: "assistant", Provider: "claude", Timestamp: at(0), Parts: []transcript.UnifiedPart{
+			call("b1", "Bash", map[string]interface{}{"command": "go test ./..."}),
+		}},
+		{Role: "user", Provider: "claude", Timestamp: at(120), Parts: []transcript.UnifiedPart{result("b1", true)}},
+		{Role: "assistant", Provider: "claude", Timestamp: at(121), Parts: []transcript.UnifiedPart{
+			call("r1", "Read", map[string]interface{}{"file_path": "/repo/main.go"}),
+		}},
+		{Role: "user", Provider: "claude", Timestamp: at(122), Parts: []transcript.UnifiedPart{result("r1", false)}},
+		{Role: "assistant", Provider: "claude", Timestamp: at(130), Parts: []transcript.UnifiedPart{
+			call("b2", "Bash", map[string]interface{}{"command": "ls"}),
+		}},
+		{Role: "user", Provider: "claude", Timestamp: at(131), Parts: []transcript.UnifiedPart{result("b2", false)}},
+		// Subagent work is excluded.
+		{Role: "assistant", Provider: "claude", IsSidechain: true, Timestamp: at(140), Parts: []transcript.UnifiedPart{
+			call("s1", "Bash", map[string]interface{}{"command": "sleep 100"}),
+		}},
+	}
+
+	r := Compute(entries, Options{TopCommands: 1})
+	if r.ToolCalls != 3 || r.FailedResults != 1 {
+		t.Errorf("ToolCalls=%d FailedResults=%d, want 3 and 1", r.ToolCalls, r.FailedResults)
+	}
+	if len(r.Tools) != 2 || r.Tools[0].Name != "Bash" {
+		t.Fatalf("Tools = %+v, want Bash first", r.Tools)
+	}
+	bash := r.Tools[0]
+	if bash.Calls != 2 || bash.Failed != 1 || bash.Timed != 2 || bash.TotalSeconds != 121 || bash.MaxSeconds != 120 {
+		t.Errorf("Bash stats = %+v", bash)
+	}
+	if len(r.LongestRuns) != 1 || r.LongestRuns[0].Command != "go test ./..." || !r.LongestRuns[0].Failed {
+		t.Errorf("LongestRuns = %+v, want the failed go test run only", r.LongestRuns)
+	}
+	if len(r.FilesTouched) != 1 || r.FilesTouched[0] != "/repo/main.go" {
+		t.Errorf("FilesTouched = %v", r.FilesTouched)
+	}
+}
+
+func TestComputeUsesMergedClaudeDurations(t *testing.T) {
+	// Claude merges results into the call: no tool_result parts, but the
+	// normalizer records status and duration on the call itself.
+	entries := []transcript.UnifiedEntry{
+		{Role: "assistant", Provider: "claude", Parts: []transcript.UnifiedPart{
+			{Type: "tool_call", Content: transcript.UnifiedToolCall{
+				ID: "b1", Name: "Bash", Input: map[string]interface{}{"command": "make"},
+				Status: "error", DurationMs: 90_000,
+			}},
+		}},
+	}
+	r := Compute(entries, Options{})
+	if r.FailedResults != 1 || len(r.Tools) != 1 || r.Tools[0].TotalSeconds != 90 {
+		t.Fatalf("result = %+v", r)
+	}
+	if len(r.LongestRuns) != 1 || r.LongestRuns[0].Command != "make" {
+		t.Errorf("LongestRuns = %+v", r.LongestRuns)
+	}
+}
+
+func TestComputeTimingFindsIdleGaps(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	msg := func(offset time.Duration) transcript.UnifiedEntry {
+		return transcript.UnifiedEntry{Role: "assistant", Timestamp: t0.Add(offset)}
+	}
+	entries := []transcript.UnifiedEntry{
+		msg(0),
+		msg(2 * time.Minute),
+		msg(42 * time.Minute), // 40 minute wait for the human
+		msg(45 * time.Minute),
+	}
+
+	timing := Compute(entries, Options{IdleThreshold: 10 * time.Minute}).Timing
+	if timing.WallClockSeconds != 45*60 {
+		t.Errorf("WallClockSeconds = %v, want 2700", timing.WallClockSeconds)
+	}
+	if len(timing.IdleGaps) != 1 || timing.IdleSeconds != 40*60 {
+		t.Fatalf("idle = %v over %+v, want one 40m gap", timing.IdleSeconds, timing.IdleGaps)
+	}
+	if timing.ActiveSeconds != 5*60 {
+		t.Errorf("ActiveSeconds = %v, want 300", timing.ActiveSeconds)
+	}
+	if !timing.EndedAt.Equal(t0.Add(45 * time.Minute)) {
+		t.Errorf("EndedAt = %v", timing.EndedAt)
+	}
+}
+
+func TestComputeLanguages(t *testing.T) {
+	text := func(s string) transcript.UnifiedPart {
+		return transcript.UnifiedPart{Type: "text", Content: transcript.UnifiedTextContent{Text: s}}
+	}
+	entries := []transcript.UnifiedEntry{
+		{Role: "assistant", Parts: []transcript.UnifiedPart{text("```go\nfunc main() {}\n```")}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{
+			call("w1", "Write", map[string]interface{}{"file_path": "/repo/run.py", "content": "print(1)\n"}),
+		}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{text("```golang\npackage main\n```")}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{text("no code here")}},
+	}
+	r := Compute(entries, Options{})
+	want := []codelang.Breakdown{{Language: "go", Entries: 2}, {Language: "python", Entries: 1}}
+	if !reflect.DeepEqual(r.Languages, want) {
+		t.Errorf("Languages = %+v, want %+v", r.Languages, want)
+	}
+}
+
+func TestComputeSizesAndOutliers(t *testing.T) {
+	big := strings.Repeat("x", 2000)
+	entries := []transcript.UnifiedEntry{
+		{Role: "assistant", Parts: []transcript.UnifiedPart{call("r1", "Read", map[string]interface{}{"file_path": "/repo/huge.log"})}},
+		{Role: "user", Parts: []transcript.UnifiedPart{{Type: "tool_result", Content: transcript.UnifiedToolResult{ToolCallID: "r1", Output: big}}}},
+		{Role: "assistant", Parts: []transcript.UnifiedPart{call("r2", "Read", map[string]interface{}{"file_path": "/repo/a.go"})}},
+		{Role: "user", Parts: []transcript.UnifiedPart{{Type: "tool_result", Content: transcript.UnifiedToolResult{ToolCallID: "r2", Output: "ok"}}}},
+		// Claude merges the output into the call.
+		{Role: "assistant", Parts: []transcript.UnifiedPart{{Type: "tool_call", Content: transcript.UnifiedToolCall{
+			ID: "b1", Name: "Bash", Input: map[string]interface{}{"command": "cat big"}, Output: big + big}}}},
+	}
+	r := Compute(entries, Options{OutlierBytes: 1000})
+
+	read := r.Tools[0]
+	if read.Name != "Read" {
+		read = r.Tools[1]
+	}
+	if read.OutputBytes != 2002 || read.MaxOutputBytes != 2000 || read.InputBytes == 0 {
+		t.Errorf("Read sizes = %+v", read)
+	}
+	if len(r.Outliers) != 2 || r.Outliers[0].Target != "cat big" || r.Outliers[0].OutputBytes != 4000 ||
+		r.Outliers[1].Target != "/repo/huge.log" {
+		t.Errorf("Outliers = %+v, want the bash run then the huge read", r.Outliers)
+	}
+}
@@ -0,0 +1,228 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/shellcmds"
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+// DefaultBucket is the span of one row of a bucketed timeline.
+const DefaultBucket = time.Minute
+
+// TimelineOptions tunes BuildTimeline. Zero values select the defaults.
+type TimelineOptions struct {
+	Bucket        time.Duration
+	IdleThreshold time.Duration
+}
+
+// Event kinds.
+const (
+	EventPrompt = "prompt" // the user wrote to the agent
+	EventReply  = "reply"  // the agent wrote prose
+	EventTool   = "tool"   // the agent called a tool
+	EventIdle   = "idle"   // nothing happened for longer than the idle threshold
+)
+
+// TimelineEvent is one thing that happened in a session.
+type TimelineEvent struct {
+	Kind string    `json:"kind"`
+	At   time.Time `json:"at"`
+	// Seconds is how long a tool call ran or an idle gap lasted; 0 when
+	// unknown or for messages.
+	Seconds float64 `json:"seconds,omitempty"`
+	Tool    string  `json:"tool,omitempty"`
+	// Target is what a tool call acted on: its command, file, URL or
+	// pattern.
+	Target string `json:"target,omitempty"`
+	Failed bool   `json:"failed,omitempty"`
+}
+
+// ToolCount is how often a tool was called within a bucket.
+type ToolCount struct {
+	Tool  string `json:"tool"`
+	Calls int    `json:"calls"`
+}
+
+// TimelineBucket summarizes a span of the session. A run of spans with
+// nothing in them is merged into one idle bucket.
+type TimelineBucket struct {
+	Start   time.Time   `json:"start"`
+	End     time.Time   `json:"end"`
+	Prompts int         `json:"prompts,omitempty"`
+	Replies int         `json:"replies,omitempty"`
+	Tools   []ToolCount `json:"tools,omitempty"`
+	Failed  int         `json:"failed,omitempty"`
+	// Running is the target (or tool) of a call started in an earlier
+	// bucket and still running, such as a long test run.
+	Running string `json:"running,omitempty"`
+	Idle    bool   `json:"idle,omitempty"`
+}
+
+// Timeline is what a session was doing when, as a list of events and as
+// fixed-size buckets.
+type Timeline struct {
+	BucketSeconds float64          `json:"bucket_seconds"`
+	Events        []TimelineEvent  `json:"events"`
+	Buckets       []TimelineBucket `json:"buckets"`
+}
+
+// BuildTimeline lays out a session's main (non-sidechain) activity over
+// time: its prompts, replies and tool calls, with how long each call ran,
+// and the idle gaps between messages.
+func BuildTimeline(entries []transcript.UnifiedEntry, opts TimelineOptions) Timeline {
+	bucket := opts.Bucket
+	if bucket <= 0 {
+		bucket = DefaultBucket
+	}
+	idleThreshold := opts.IdleThreshold
+	if idleThreshold <= 0 {
+		idleThreshold = DefaultIdleThreshold
+	}
+
+	tl := Timeline{BucketSeconds: bucket.Seconds()}
+	pending := make(map[string]int) // call ID -> index into tl.Events
+	for _, entry := range entries {
+		if entry.IsSidechain || entry.Timestamp.IsZero() {
+			continue
+		}
+		wrote := false
+		for _, part := range entry.Parts {
+			switch part.Type {
+			case "text":
+				wrote = true
+			case "tool_call":
+				call := transcript.ToolCallOf(part)
+				name := call.Name
+				if name == "" {
+					name = "unknown"
+				}
+				target := shellcmds.Script(name, call.Input)
+				if target == "" {
+					target = callTarget(call.Input)
+				}
+				ev := TimelineEvent{
+					Kind:    EventTool,
+					At:      entry.Timestamp,
+					Seconds: float64(call.DurationMs) / 1000,
+					Tool:    name,
+					Target:  target,
+					Failed:  call.Status == "error",
+				}
+				if call.DurationMs == 0 && call.ID != "" {
+					pending[call.ID] = len(tl.Events)
+				}
+				tl.Events = append(tl.Events, ev)
+			case "tool_result":
+				r := transcript.ToolResultOf(part)
+				i, ok := pending[r.ToolCallID]
+				if !ok {
+					continue
+				}
+				delete(pending, r.ToolCallID)
+				ev := &tl.Events[i]
+				ev.Failed = ev.Failed || r.IsError
+				if entry.Timestamp.After(ev.At) {
+					ev.Seconds = entry.Timestamp.Sub(ev.At).Seconds()
+				}
+			}
+		}
+		if wrote {
+			kind := EventReply
+			if entry.Role == "user" {
+				kind = EventPrompt
+			}
+			tl.Events = append(tl.Events, TimelineEvent{Kind: kind, At: entry.Timestamp})
+		}
+	}
+
+	for _, gap := range computeTiming(entries, idleThreshold).IdleGaps {
+		tl.Events = append(tl.Events, TimelineEvent{Kind: EventIdle, At: gap.Start, Seconds: gap.Seconds})
+	}
+	sort.SliceStable(tl.Events, func(i, j int) bool { return tl.Events[i].At.Before(tl.Events[j].At) })
+
+	tl.Buckets = bucketEvents(tl.Events, bucket)
+	return tl
+}
+
+// bucketEvents counts events into spans of size bucket, from the span of
+// the first event to that of the last, merging runs of empty spans.
+func bucketEvents(events []TimelineEvent, bucket time.Duration) []TimelineBucket {
+	var first, last time.Time
+	for _, ev := range events {
+		if ev.Kind == EventIdle {
+			continue
+		}
+		if first.IsZero() {
+			first = ev.At
+		}
+		last = ev.At
+	}
+	if first.IsZero() {
+		return nil
+	}
+	start := first.Truncate(bucket)
+	n := int(last.Sub(start)/bucket) + 1
+	spans := make([]TimelineBucket, n)
+	for i := range spans {
+		spans[i].Start = start.Add(time.Duration(i) * bucket)
+		spans[i].End = spans[i].Start.Add(bucket)
+	}
+
+	counts := make([]map[string]int, n)
+	for _, ev := range events {
+		i := int(ev.At.Sub(start) / bucket)
+		if i < 0 || i >= n {
+			continue
+		}
+		b := &spans[i]
+		switch ev.Kind {
+		case EventPrompt:
+			b.Prompts++
+		case EventReply:
+			b.Replies++
+		case EventTool:
+			if counts[i] == nil {
+				counts[i] = make(map[string]int)
+			}
+			if counts[i][ev.Tool] == 0 {
+				b.Tools = append(b.Tools, ToolCount{Tool: ev.Tool})
+			}
+			counts[i][ev.Tool]++
+			if ev.Failed {
+				b.Failed++
+			}
+			// A long call keeps the buckets it runs through busy.
+			end := ev.At.Add(time.Duration(ev.Seconds * float64(time.Second)))
+			label := ev.Target
+			if label == "" {
+				label = ev.Tool
+			}
+			for j := i + 1; j < n && spans[j].Start.Before(end); j++ {
+				if spans[j].Running == "" {
+					spans[j].Running = label
+				}
+			}
+		}
+	}
+
+	var out []TimelineBucket
+	for i, b := range spans {
+		for k := range b.Tools {
+			b.Tools[k].Calls = counts[i][b.Tools[k].Tool]
+		}
+		empty := b.Prompts == 0 && b.Replies == 0 && len(b.Tools) == 0 && b.Running == ""
+		if !empty {
+			out = append(out, b)
+			continue
+		}
+		if len(out) > 0 && out[len(out)-1].Idle {
+			out[len(out)-1].End = b.End
+			continue
+		}
+		b.Idle = true
+		out = append(out, b)
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return t0.Add(time.Duration(sec) * time.Second) }
+	text := transcript.UnifiedPart{Type: "text", Content: transcript.UnifiedTextContent{Text: "hi"}}
+
+	entries := []transcript.UnifiedEntry{
+		{Role: "user", Timestamp: at(0), Parts: []transcript.UnifiedPart{text}},
+		{Role: "assistant", Timestamp: at(10), Parts: []transcript.UnifiedPart{
+			text,
+			call("b1", "Bash", map[string]interface{}{"command": "go test ./..."}),
+		}},
+		// The test run takes three minutes and fails.
+		{Role: "user", Timestamp: at(190), Parts: []transcript.UnifiedPart{result("b1", true)}},
+		{Role: "assistant", Timestamp: at(200), Parts: []transcript.UnifiedPart{
+			call("e1", "Edit", map[string]interface{}{"file_path": "/repo/main.go"}),
+		}},
+		{Role: "user", Timestamp: at(201), Parts: []transcript.UnifiedPart{result("e1", false)}},
+		// Then nobody answers for an hour.
+		{Role: "user", Timestamp: at(3800), Parts: []transcript.UnifiedPart{text}},
+	}
+
+	tl := BuildTimeline(entries, TimelineOptions{})
+
+	var kinds []string
+	for _, ev := range tl.Events {
+		kinds = append(kinds, ev.Kind)
+	}
+	wantKinds := []string{EventPrompt, EventTool, EventReply, EventTool, EventIdle, EventPrompt}
+	if len(kinds) != len(wantKinds) {
+		t.Fatalf("event kinds = %v, want %v", kinds, wantKinds)
+	}
+	for i := range kinds {
+		if kinds[i] != wantKinds[i] {
+			t.Fatalf("event kinds = %v, want %v", kinds, wantKinds)
+		}
+	}
+	bash := tl.Events[1]
+	if bash.Tool != "Bash" || bash.Target != "go test ./..." || bash.Seconds != 180 || !bash.Failed {
+		t.Errorf("Bash event = %+v", bash)
+	}
+
+	// 09:00 prompt+Bash, 09:01 and 09:02 running the tests, 09:03 result
+	// and Edit, then idle until the prompt at 10:03.
+	if len(tl.Buckets) != 6 {
+		t.Fatalf("buckets = %+v", tl.Buckets)
+	}
+	b := tl.Buckets
+	if b[0].Prompts != 1 || b[0].Replies != 1 || len(b[0].Tools) != 1 || b[0].Tools[0] != (ToolCount{Tool: "Bash", Calls: 1}) || b[0].Failed != 1 {
+		t.Errorf("bucket 0 = %+v", b[0])
+	}
+	if b[1].Running != "go test ./..." || b[2].Running != "go test ./..." {
+		t.Errorf("buckets 1-2 running = %q, %q", b[1].Running, b[2].Running)
+	}
+	if len(b[3].Tools) != 1 || b[3].Tools[0].Tool != "Edit" {
+		t.Errorf("bucket 3 = %+v", b[3])
+	}
+	if !b[4].Idle || !b[4].Start.Equal(at(240)) || !b[4].End.Equal(at(3780)) {
+		t.Errorf("bucket 4 = %+v, want idle 09:04-10:03", b[4])
+	}
+	if b[5].Prompts != 1 {
+		t.Errorf("bucket 5 = %+v", b[5])
+	}
+}
@@ -0,0 +1,165 @@
+package transcript
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Claude Code records slash commands and their local output as user
+// messages wrapped in pseudo-XML tags, e.g.
+//
+//	<command-name>/model</command-name>
+//	<command-message>model</command-message>
+//	<command-args>opus</command-args>
+//
+// and <local-command-stdout>...</local-command-stdout>. Newer versions write
+// the same content in {"type":"system","subtype":"local_command"} entries.
+var (
+	commandNamePattern    = regexp.MustCompile(`(?s)<command-name>(.*?)</command-name>`)
+	commandArgsPattern    = regexp.MustCompile(`(?s)<command-args>(.*?)</command-args>`)
+	localStdoutPattern    = regexp.MustCompile(`(?s)<local-command-stdout>(.*?)</local-command-stdout>`)
+	localStderrPattern    = regexp.MustCompile(`(?s)<local-command-stderr>(.*?)</local-command-stderr>`)
+	localCaveatPattern    = regexp.MustCompile(`(?s)^\s*<local-command-caveat>.*</local-command-caveat>\s*$`)
+	hookStatusLinePattern = regexp.MustCompile(`^(\S+) \[(.*)\] (.*)$`)
+)
+
+// parseCommandText recognizes slash-command and local-command-output
+// messages. It reports false for ordinary text. The caveat Claude Code adds
+// before local command output is recognized and yields no parts.
+func parseCommandText(text string) ([]UnifiedPart, bool) {
+	if localCaveatPattern.MatchString(text) {
+		return nil, true
+	}
+	if m := commandNamePattern.FindStringSubmatch(text); m != nil {
+		cmd := UnifiedCommand{Name: strings.TrimSpace(m[1])}
+		if a := commandArgsPattern.FindStringSubmatch(text); a != nil {
+			cmd.Args = strings.TrimSpace(a[1])
+		}
+		return []UnifiedPart{{Type: "command", Content: cmd}}, true
+	}
+	var parts []UnifiedPart
+	if m := localStdoutPattern.FindStringSubmatch(text); m != nil {
+		if out := strings.TrimSpace(ansi.Strip(m[1])); out != "" {
+			parts = append(parts, UnifiedPart{Type: "command_output", Content: UnifiedCommandOutput{Output: out}})
+		}
+	}
+	if m := localStderrPattern.FindStringSubmatch(text); m != nil {
+		if out := strings.TrimSpace(ansi.Strip(m[1])); out != "" {
+			parts = append(parts, UnifiedPart{Type: "command_output", Content: UnifiedCommandOutput{Output: out, IsError: true}})
+		}
+	}
+	if parts != nil {
+		return parts, true
+	}
+	if strings.Contains(text, "<local-command-stdout>") || strings.Contains(text, "<local-command-stderr>") {
+		return nil, true // empty output
+	}
+	return nil, false
+}
+
+// claudeEventLine is the subset of a Claude "system" or "attachment" line
+// needed to recover command and hook events.
+type claudeEventLine struct {
+	Type      string          `json:"type"`
+	Subtype   string          `json:"subtype"`
+	Content   json.RawMessage `json:"content"`
+	Level     string          `json:"level"`
+	HookInfos []struct {
+		Command string `json:"command"`
+	} `json:"hookInfos"`
+	HookErrors []json.RawMessage `json:"hookErrors"`
+	// CompactMetadata accompanies "compact_boundary" lines.
+	CompactMetadata *struct {
+		Trigger   string `json:"trigger"`
+		PreTokens int    `json:"preTokens"`
+	} `json:"compactMetadata"`
+	Attachment *struct {
+		Type      string `json:"type"`
+		HookName  string `json:"hookName"`
+		HookEvent string `json:"hookEvent"`
+		Command   string `json:"command"`
+		Content   string `json:"content"`
+		Stdout    string `json:"stdout"`
+		Stderr    string `json:"stderr"`
+	} `json:"attachment"`
+}
+
+// eventParts converts a Claude system or attachment line into command,
+// hook or compaction parts. Other lines (API errors, file attachments)
+// yield nil.
+func (e claudeEventLine) eventParts() []UnifiedPart {
+	if e.Type == "attachment" {
+		a := e.Attachment
+		if a == nil || !strings.HasPrefix(a.Type, "hook_") {
+			return nil
+		}
+		event := a.HookName
+		if event == "" {
+			event = a.HookEvent
+		}
+		output := a.Content
+		if output == "" {
+			output = strings.TrimSpace(a.Stdout + "\n" + a.Stderr)
+		}
+		return []UnifiedPart{{Type: "hook", Content: UnifiedHook{
+			Event:   event,
+			Command: a.Command,
+			Output:  output,
+			IsError: strings.Contains(a.Type, "error"),
+		}}}
+	}
+
+	var content string
+	_ = json.Unmarshal(e.Content, &content)
+	content = ansi.Strip(content)
+
+	switch {
+	case e.Subtype == "compact_boundary":
+		summary := UnifiedSummary{Kind: SummaryCompaction}
+		if m := e.CompactMetadata; m != nil {
+			summary.Trigger = m.Trigger
+			summary.PreTokens = m.PreTokens
+		}
+		return []UnifiedPart{{Type: "summary", Content: summary}}
+	case e.Subtype == "local_command":
+		parts, _ := parseCommandText(content)
+		return parts
+	case e.Subtype == "stop_hook_summary":
+		var parts []UnifiedPart
+		for _, info := range e.HookInfos {
+			parts = append(parts, UnifiedPart{Type: "hook", Content: UnifiedHook{
+				Event:   "Stop",
+				Command: info.Command,
+				IsError: len(e.HookErrors) > 0,
+			}})
+		}
+		return parts
+	}
+
+	// Hook status lines: "PostToolUse:Edit [prettier --write] completed successfully".
+	m := hookStatusLinePattern.FindStringSubmatch(strings.TrimSpace(content))
+	if m == nil || !isClaudeHookEvent(m[1]) {
+		return nil
+	}
+	return []UnifiedPart{{Type: "hook", Content: UnifiedHook{
+		Event:   m[1],
+		Command: m[2],
+		Output:  m[3],
+		IsError: (e.Level != "" && e.Level != "info") || strings.Contains(m[3], "failed") || strings.Contains(m[3], "blocking error"),
+	}}}
+}
+
+// isClaudeHookEvent reports whether name ("PreToolUse:Bash", "Stop") starts
+// with one of Claude Code's hook event names.
+func isClaudeHookEvent(name string) bool {
+	event, _, _ := strings.Cut(name, ":")
+	switch event {
+	case "PreToolUse", "PostToolUse", "UserPromptSubmit", "Notification", "Stop",
+		"SubagentStop", "PreCompact", "SessionStart", "SessionEnd":
+		return true
+	}
+	return false
+}
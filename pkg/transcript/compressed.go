@@ -0,0 +1,132 @@
+package transcript
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CompressedExt is the suffix of a gzipped transcript, as written by
+// 'aglogs prune --compress' and 'aglogs archive --compress':
+// <session>.jsonl.gz next to, or instead of, <session>.jsonl.
+const CompressedExt = ".gz"
+
+// IsCompressedPath reports whether path names a gzipped transcript.
+func IsCompressedPath(path string) bool {
+	return strings.HasSuffix(path, CompressedExt)
+}
+
+// TrimTranscriptExt strips ".jsonl" or ".jsonl.gz" from a transcript file
+// name, leaving the part that names the session.
+func TrimTranscriptExt(name string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(name, CompressedExt), ".jsonl")
+}
+
+// IsTranscriptFile reports whether name is a JSONL transcript, compressed
+// or not.
+func IsTranscriptFile(name string) bool {
+	return strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".jsonl"+CompressedExt)
+}
+
+// GlobTranscripts matches pattern, a glob ending in ".jsonl", along with
+// the compressed transcripts it would match once gzipped. When a session
+// has both, the uncompressed file wins.
+func GlobTranscripts(pattern string) ([]string, error) {
+	plain, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := filepath.Glob(pattern + CompressedExt)
+	if err != nil {
+		return nil, err
+	}
+	if len(compressed) == 0 {
+		return plain, nil
+	}
+	seen := make(map[string]bool, len(plain))
+	for _, p := range plain {
+		seen[p] = true
+	}
+	for _, c := range compressed {
+		if !seen[strings.TrimSuffix(c, CompressedExt)] {
+			plain = append(plain, c)
+		}
+	}
+	return plain, nil
+}
+
+// OpenTranscript opens a transcript for reading, decompressing it when its
+// name ends in ".gz".
+func OpenTranscript(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !IsCompressedPath(path) {
+		return f, nil
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	return &gzipFile{Reader: zr, f: f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g *gzipFile) Close() error {
+	err := g.Reader.Close()
+	if ferr := g.f.Close(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// openTranscriptAt opens a transcript positioned at offset, a byte offset
+// into its uncompressed content. pos reports the offset reached after
+// reading. Compressed transcripts cannot seek, so reaching the offset means
+// decompressing everything before it; they are written once and read whole
+// in practice.
+func openTranscriptAt(path string, offset int64) (r io.ReadCloser, pos func() (int64, error), err error) {
+	if !IsCompressedPath(path) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if offset > 0 {
+			if _, err := f.Seek(offset, io.SeekStart); err != nil {
+				f.Close()
+				return nil, nil, fmt.Errorf("failed to seek to offset %d: %w", offset, err)
+			}
+		}
+		return f, func() (int64, error) { return f.Seek(0, io.SeekCurrent) }, nil
+	}
+	rc, err := OpenTranscript(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := io.CopyN(io.Discard, rc, offset); err != nil && err != io.EOF {
+		rc.Close()
+		return nil, nil, fmt.Errorf("failed to skip to offset %d: %w", offset, err)
+	}
+	cr := &countingReadCloser{ReadCloser: rc, n: offset}
+	return cr, func() (int64, error) { return cr.n, nil }, nil
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,75 @@
+package transcript
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeGzip(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(f)
+	if _, err := zw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGlobTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.jsonl", "b.jsonl"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeGzip(t, filepath.Join(dir, "b.jsonl.gz"), "")
+	writeGzip(t, filepath.Join(dir, "c.jsonl.gz"), "")
+
+	got, err := GlobTranscripts(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range got {
+		names = append(names, filepath.Base(p))
+	}
+	if want := []string{"a.jsonl", "b.jsonl", "c.jsonl.gz"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("matches = %v, want %v", names, want)
+	}
+	if id := TrimTranscriptExt("c.jsonl.gz"); id != "c" {
+		t.Errorf("TrimTranscriptExt = %q", id)
+	}
+}
+
+func TestOpenTranscriptAtCompressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl.gz")
+	writeGzip(t, path, "line one\nline two\n")
+
+	r, pos, err := openTranscriptAt(path, int64(len("line one\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rest) != "line two\n" {
+		t.Errorf("read %q from offset", rest)
+	}
+	if n, _ := pos(); n != int64(len("line one\nline two\n")) {
+		t.Errorf("offset after reading = %d", n)
+	}
+}
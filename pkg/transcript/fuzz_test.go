@@ -0,0 +1,82 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Fuzz targets for the line normalizers. Malformed upstream lines must
+// come back as errors or skipped entries, never as panics or entries that
+// cannot be encoded. The seed corpus is every fixture line, whole and cut
+// in half (a torn write), plus the fixture files as multi-line inputs.
+// Run one with, for example:
+//
+//	go test ./pkg/transcript -run '^$' -fuzz FuzzClaudeNormalizeLines
+
+// addFixtureSeeds adds the lines of the fixture files matching pattern to
+// the corpus of f.
+func addFixtureSeeds(f *testing.F, pattern string) {
+	f.Helper()
+	paths, err := filepath.Glob(pattern)
+	if err != nil || len(paths) == 0 {
+		f.Fatalf("no fixtures match %s", pattern)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			if len(line) == 0 {
+				continue
+			}
+			f.Add(line)
+			f.Add(line[:len(line)/2])
+		}
+	}
+}
+
+// checkEntry fails when a normalized entry cannot be encoded, as stream,
+// read --json and the serve API all do.
+func checkEntry(t *testing.T, entry *UnifiedEntry) {
+	t.Helper()
+	if entry == nil {
+		return
+	}
+	if _, err := json.Marshal(entry); err != nil {
+		t.Errorf("normalized entry does not encode: %v", err)
+	}
+}
+
+func FuzzClaudeNormalizeLines(f *testing.F) {
+	addFixtureSeeds(f, "../claudelogs/testdata/*.jsonl")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		n := NewClaudeNormalizer()
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			entry, err := n.NormalizeLine(line)
+			if err == nil {
+				checkEntry(t, entry)
+			}
+		}
+		for _, entry := range n.Flush() {
+			checkEntry(t, entry)
+		}
+	})
+}
+
+func FuzzCodexNormalizeLines(f *testing.F) {
+	addFixtureSeeds(f, "testdata/codex/sessions/*/*/*/*.jsonl")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		n := NewCodexNormalizer()
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			entry, err := n.NormalizeLine(line)
+			if err == nil {
+				checkEntry(t, entry)
+			}
+		}
+	})
+}
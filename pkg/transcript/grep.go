@@ -0,0 +1,109 @@
+package transcript
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// GrepOptions tunes GrepEntries.
+type GrepOptions struct {
+	// Invert selects the entries that don't match.
+	Invert bool
+	// Before and After are how many entries around each selected one
+	// are kept for context, as in grep -B and -A.
+	Before, After int
+}
+
+// GrepEntries returns the entries with a part matching re (see
+// EntryMatches), or with opts.Invert those with none, along with the
+// context opts asks for, keeping their order.
+func GrepEntries(entries []UnifiedEntry, re *regexp.Regexp, opts GrepOptions) []UnifiedEntry {
+	match := func(i int) bool { return EntryMatches(entries[i], re) != opts.Invert }
+	var out []UnifiedEntry
+	for _, i := range WithContext(len(entries), match, opts.Before, opts.After) {
+		out = append(out, entries[i])
+	}
+	return out
+}
+
+// WithContext returns, in order, the indexes in [0, n) that match, each
+// with up to before indexes preceding it and after indexes following it.
+// Overlapping context is listed once.
+func WithContext(n int, match func(i int) bool, before, after int) []int {
+	var out []int
+	next := 0 // first index not yet listed
+	for i := 0; i < n; i++ {
+		if !match(i) {
+			continue
+		}
+		from := max(i-before, next)
+		to := min(i+after, n-1)
+		for j := from; j <= to; j++ {
+			out = append(out, j)
+		}
+		next = max(next, to+1)
+	}
+	return out
+}
+
+// EntryMatches reports whether re matches the entry's content: its text
+// and reasoning, the names, inputs (commands, file paths, patterns) and
+// outputs of its tool calls, tool results, slash commands and hooks, and
+// its summaries.
+func EntryMatches(entry UnifiedEntry, re *regexp.Regexp) bool {
+	for _, part := range entry.Parts {
+		for _, s := range partStrings(part) {
+			if s != "" && re.MatchString(s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// partStrings lists the strings of a part that grep looks at.
+func partStrings(part UnifiedPart) []string {
+	switch c := part.Content.(type) {
+	case UnifiedTextContent:
+		return []string{c.Text}
+	case UnifiedReasoning:
+		return []string{c.Text}
+	case UnifiedToolCall:
+		return inputStrings([]string{c.Name, c.Title, c.Output}, c.Input)
+	case UnifiedToolResult:
+		return []string{c.Output}
+	case UnifiedCommand:
+		return []string{c.Name, c.Args}
+	case UnifiedCommandOutput:
+		return []string{c.Output}
+	case UnifiedHook:
+		return []string{c.Event, c.Command, c.Output}
+	case UnifiedSummary:
+		return []string{c.Text}
+	case map[string]interface{}:
+		// A part decoded from JSON rather than built by a normalizer.
+		return inputStrings(nil, c)
+	}
+	return nil
+}
+
+// inputStrings appends the string values found anywhere in v (a tool
+// input, say) to out. Numbers and booleans are formatted, so a pattern
+// can match a line number or a flag.
+func inputStrings(out []string, v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return append(out, v)
+	case map[string]interface{}:
+		for _, item := range v {
+			out = inputStrings(out, item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			out = inputStrings(out, item)
+		}
+	case float64, bool:
+		return append(out, fmt.Sprint(v))
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package transcript
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestGrepEntries(t *testing.T) {
+	entries := []UnifiedEntry{
+		{MessageID: "prose", Parts: []UnifiedPart{{Type: "text", Content: UnifiedTextContent{Text: "Let me look at the parser."}}}},
+		{MessageID: "bash", Parts: []UnifiedPart{{Type: "tool_call", Content: UnifiedToolCall{
+			Name:  "Bash",
+			Input: map[string]interface{}{"command": "go test ./pkg/...", "timeout": float64(120000)},
+		}}}},
+		{MessageID: "edit", Parts: []UnifiedPart{{Type: "tool_call", Content: UnifiedToolCall{
+			Name:  "Edit",
+			Input: map[string]interface{}{"file_path": "/repo/pkg/parser.go"},
+		}}}},
+		{MessageID: "codex", Parts: []UnifiedPart{{Type: "tool_call", Content: UnifiedToolCall{
+			Name:  "shell",
+			Input: map[string]interface{}{"command": []interface{}{"bash", "-lc", "go test ./..."}},
+		}}}},
+		{MessageID: "result", Parts: []UnifiedPart{{Type: "tool_result", Content: UnifiedToolResult{Output: "FAIL TestParse"}}}},
+		{MessageID: "decoded", Parts: []UnifiedPart{{Type: "tool_call", Content: map[string]interface{}{
+			"name": "Read", "input": map[string]interface{}{"file_path": "/repo/README.md"},
+		}}}},
+	}
+
+	ids := func(es []UnifiedEntry) []string {
+		var out []string
+		for _, e := range es {
+			out = append(out, e.MessageID)
+		}
+		return out
+	}
+	tests := []struct {
+		pattern string
+		invert  bool
+		want    []string
+	}{
+		{"go test", false, []string{"bash", "codex"}},
+		{`parser\.go$`, false, []string{"edit"}},
+		{"^Edit$|^Read$", false, []string{"edit", "decoded"}},
+		{"FAIL", false, []string{"result"}},
+		{"120000", false, []string{"bash"}},
+		{"go test", true, []string{"prose", "edit", "result", "decoded"}},
+	}
+	for _, tt := range tests {
+		got := ids(GrepEntries(entries, regexp.MustCompile(tt.pattern), GrepOptions{Invert: tt.invert}))
+		if len(got) != len(tt.want) {
+			t.Errorf("grep %q invert=%v = %v, want %v", tt.pattern, tt.invert, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("grep %q invert=%v = %v, want %v", tt.pattern, tt.invert, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	matches := map[int]bool{2: true, 4: true, 9: true}
+	match := func(i int) bool { return matches[i] }
+	tests := []struct {
+		before, after int
+		want          []int
+	}{
+		{0, 0, []int{2, 4, 9}},
+		{1, 0, []int{1, 2, 3, 4, 8, 9}},
+		{0, 1, []int{2, 3, 4, 5, 9}},
+		{2, 2, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{5, 0, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+	}
+	for _, tt := range tests {
+		got := WithContext(10, match, tt.before, tt.after)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("WithContext(-B %d -A %d) = %v, want %v", tt.before, tt.after, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package transcript
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UnifiedImage is an image embedded in a transcript: pasted by the user
+// ("image" parts) or returned by a tool (UnifiedToolCall.Images). Data is
+// the base64 payload. It is left out of JSON, so images survive a JSON
+// round-trip (the daemon) only as placeholders; extracting them needs the
+// transcript read in-process.
+type UnifiedImage struct {
+	MediaType string `json:"mediaType"` // e.g. "image/png"
+	Size      int    `json:"size"`      // decoded size in bytes
+	Data      string `json:"-"`
+}
+
+// newBase64Image builds an image from a base64 payload, computing its
+// decoded size without decoding it.
+func newBase64Image(mediaType, data string) UnifiedImage {
+	trimmed := strings.TrimRight(data, "=")
+	return UnifiedImage{
+		MediaType: mediaType,
+		Size:      base64.RawStdEncoding.DecodedLen(len(trimmed)),
+		Data:      data,
+	}
+}
+
+// Decode returns the image's bytes.
+func (img UnifiedImage) Decode() ([]byte, error) {
+	if img.Data == "" {
+		return nil, fmt.Errorf("image data not available")
+	}
+	return base64.StdEncoding.DecodeString(img.Data)
+}
+
+// Ext returns the file extension for the image's media type, without the
+// dot: "png" for image/png, "jpg" for image/jpeg.
+func (img UnifiedImage) Ext() string {
+	sub, ok := strings.CutPrefix(img.MediaType, "image/")
+	if !ok || sub == "" {
+		return "bin"
+	}
+	switch sub {
+	case "jpeg":
+		return "jpg"
+	case "svg+xml":
+		return "svg"
+	}
+	return sub
+}
+
+// Placeholder is the text shown for the image where its content cannot be:
+// "[image: 1.2MB png]".
+func (img UnifiedImage) Placeholder() string {
+	return fmt.Sprintf("[image: %s %s]", formatImageSize(img.Size), img.Ext())
+}
+
+func formatImageSize(n int) string {
+	switch {
+	case n >= 1000*1000:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1000*1000))
+	case n >= 1000:
+		return fmt.Sprintf("%dKB", n/1000)
+	}
+	return fmt.Sprintf("%dB", n)
+}
+
+// parseClaudeImage reads a Claude image content block's source. Only base64
+// sources carry the image itself; URL sources are kept with no data.
+func parseClaudeImage(source claudeImageSource) UnifiedImage {
+	if source.Type == "base64" {
+		return newBase64Image(source.MediaType, source.Data)
+	}
+	return UnifiedImage{MediaType: source.MediaType}
+}
+
+type claudeImageSource struct {
+	Type      string `json:"type"` // "base64" or "url"
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+// Attachment is an image found in a transcript, with where it came from.
+type Attachment struct {
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	Role      string    `json:"role"`
+	// Tool is the name of the tool that returned the image, or "" for an
+	// image in the message itself (pasted by the user).
+	Tool  string       `json:"tool,omitempty"`
+	Image UnifiedImage `json:"image"`
+}
+
+// Attachments lists the images in entries in transcript order: those in
+// "image" parts and those returned by tools.
+func Attachments(entries []UnifiedEntry) []Attachment {
+	var found []Attachment
+	for _, entry := range entries {
+		for _, part := range entry.Parts {
+			switch content := part.Content.(type) {
+			case UnifiedImage:
+				found = append(found, Attachment{Timestamp: entry.Timestamp, Role: entry.Role, Image: content})
+			case UnifiedToolCall:
+				for _, img := range content.Images {
+					found = append(found, Attachment{Timestamp: entry.Timestamp, Role: entry.Role, Tool: content.Name, Image: img})
+				}
+			case UnifiedToolResult:
+				for _, img := range content.Images {
+					found = append(found, Attachment{Timestamp: entry.Timestamp, Role: entry.Role, Image: img})
+				}
+			}
+		}
+	}
+	return found
+}
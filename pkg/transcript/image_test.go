@@ -0,0 +1,64 @@
+package transcript
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestClaudeNormalizerImages(t *testing.T) {
+	png := base64.StdEncoding.EncodeToString([]byte("\x89PNG fake image bytes"))
+	lines := []string{
+		`{"type":"user","timestamp":"2026-01-01T00:00:00Z","message":{"role":"user","content":[{"type":"text","text":"what is this?"},{"type":"image","source":{"type":"base64","media_type":"image/png","data":"` + png + `"}}]}}`,
+		`{"type":"assistant","timestamp":"2026-01-01T00:00:01Z","message":{"id":"m1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Screenshot","input":{}}]}}`,
+		`{"type":"user","timestamp":"2026-01-01T00:00:02Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":[{"type":"text","text":"captured"},{"type":"image","source":{"type":"base64","media_type":"image/jpeg","data":"` + png + `"}}]}]}}`,
+	}
+	n := NewClaudeNormalizer()
+	var entries []UnifiedEntry
+	for _, line := range lines {
+		entry, err := n.NormalizeLine([]byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	call := entries[1].Parts[0].Content.(UnifiedToolCall)
+	if call.Output != "captured" || len(call.Images) != 1 {
+		t.Errorf("tool call = %+v, want output and one image", call)
+	}
+
+	found := Attachments(entries)
+	if len(found) != 2 || found[0].Role != "user" || found[1].Tool != "Screenshot" {
+		t.Fatalf("attachments = %+v", found)
+	}
+	data, err := found[0].Image.Decode()
+	if err != nil || string(data) != "\x89PNG fake image bytes" {
+		t.Errorf("Decode() = %q, %v", data, err)
+	}
+	if found[0].Image.Size != len(data) {
+		t.Errorf("Size = %d, want %d", found[0].Image.Size, len(data))
+	}
+	if got := found[1].Image.Ext(); got != "jpg" {
+		t.Errorf("Ext() = %q, want jpg", got)
+	}
+}
+
+func TestImagePlaceholder(t *testing.T) {
+	for _, tc := range []struct {
+		img  UnifiedImage
+		want string
+	}{
+		{UnifiedImage{MediaType: "image/png", Size: 1_234_567}, "[image: 1.2MB png]"},
+		{UnifiedImage{MediaType: "image/webp", Size: 48_300}, "[image: 48KB webp]"},
+		{newBase64Image("image/gif", strings.Repeat("A", 8)), "[image: 6B gif]"},
+	} {
+		if got := tc.img.Placeholder(); got != tc.want {
+			t.Errorf("Placeholder() = %q, want %q", got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package transcript
+
+import (
+	"bufio"
+	"io"
+	"math"
+)
+
+// initialLineBuffer is the line buffer a LineScanner starts with; it is
+// enough for almost every transcript line.
+const initialLineBuffer = 64 * 1024
+
+// NewLineScanner returns a scanner over the lines of a JSONL transcript
+// whose buffer grows to fit any line. Transcript lines have no practical
+// bound: a Claude message carrying a base64 image or a large tool result
+// can run to tens of megabytes, and a fixed limit would stop the scan there,
+// silently losing the rest of the file.
+func NewLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, initialLineBuffer), math.MaxInt)
+	return scanner
+}
@@ -0,0 +1,21 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewLineScannerLongLines(t *testing.T) {
+	long := `{"image":"` + strings.Repeat("A", 5*1024*1024) + `"}`
+	scanner := NewLineScanner(strings.NewReader("{}\n" + long + "\n{\"after\":true}\n"))
+	var lens []int
+	for scanner.Scan() {
+		lens = append(lens, len(scanner.Bytes()))
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(lens) != 3 || lens[1] != len(long) {
+		t.Errorf("line lengths = %v, want [2 %d 14]", lens, len(long))
+	}
+}
@@ -1,6 +1,7 @@
 package transcript
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -24,9 +25,11 @@ type Monitor struct {
 	checkInterval  time.Duration
 	fileOffsets    map[string]int64 // sessionID -> file offset
 	offsetsMutex   sync.RWMutex
-	stopChan       chan struct{}
+	ctx            context.Context // cancelled when the monitor stops
+	cancel         context.CancelFunc
 	wg             sync.WaitGroup
 	summaryManager *SummaryManager
+	schedule       func(ctx context.Context) error
 }
 
 // NewMonitor creates a new transcript monitor
@@ -36,7 +39,6 @@ func NewMonitor(db *sql.DB, checkInterval time.Duration) *Monitor {
 		parser:         NewParser(),
 		checkInterval:  checkInterval,
 		fileOffsets:    make(map[string]int64),
-		stopChan:       make(chan struct{}),
 		summaryManager: NewSummaryManager(db),
 	}
 }
@@ -48,14 +50,22 @@ func NewMonitorWithConfig(db *sql.DB, checkInterval time.Duration, summaryConfig
 		parser:         NewParser(),
 		checkInterval:  checkInterval,
 		fileOffsets:    make(map[string]int64),
-		stopChan:       make(chan struct{}),
 		summaryManager: NewSummaryManagerWithConfig(db, summaryConfig),
 	}
 }
 
 // Start begins the monitoring process
 func (m *Monitor) Start() {
+	m.StartContext(context.Background())
+}
+
+// StartContext begins the monitoring process, which runs until Stop is
+// called or ctx is cancelled. Cancelling stops a pass through the sessions
+// between transcripts, or mid-transcript without advancing its offset, so
+// no message is stored twice or skipped.
+func (m *Monitor) StartContext(ctx context.Context) {
 	log.Println("Starting transcript monitor...")
+	m.ctx, m.cancel = context.WithCancel(ctx)
 
 	// Load existing offsets from database
 	m.loadOffsets()
@@ -74,17 +84,37 @@ func (m *Monitor) Start() {
 			select {
 			case <-ticker.C:
 				m.processActiveSessions()
-			case <-m.stopChan:
+			case <-m.ctx.Done():
 				log.Println("Stopping transcript monitor...")
 				return
 			}
 		}
 	}()
+
+	if m.schedule != nil {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			if err := m.schedule(m.ctx); err != nil {
+				log.Printf("Not running scheduled tasks: %v", err)
+			}
+		}()
+	}
+}
+
+// SetSchedule has the monitor run schedule alongside its checks, from
+// Start until Stop, so a daemon running the monitor can run scheduled
+// tasks too, e.g. with aglogs.RunScheduledTasks. It must be called before
+// Start; a monitor runs no schedule by default.
+func (m *Monitor) SetSchedule(schedule func(ctx context.Context) error) {
+	m.schedule = schedule
 }
 
 // Stop gracefully stops the monitor
 func (m *Monitor) Stop() {
-	close(m.stopChan)
+	if m.cancel != nil {
+		m.cancel()
+	}
 	m.wg.Wait()
 }
 
@@ -137,6 +167,9 @@ func (m *Monitor) processActiveSessions() {
 
 	log.Printf("Processing %d active sessions", len(sessions))
 	for _, sessionWithProvider := range sessions {
+		if m.ctx.Err() != nil {
+			return
+		}
 		m.processSession(sessionWithProvider)
 	}
 }
@@ -236,9 +269,9 @@ func (m *Monitor) processSession(swp *SessionWithProvider) {
 	var messages []ExtractedMessage
 	var newOffset int64
 	if provider == "codex" {
-		messages, newOffset, err = m.parser.ParseCodexFileFromOffset(transcriptPath, offset)
+		messages, newOffset, err = m.parser.parseCodexFileFromOffset(m.ctx, transcriptPath, offset)
 	} else {
-		messages, newOffset, err = m.parser.ParseFileFromOffset(transcriptPath, offset)
+		messages, newOffset, err = m.parser.parseFileFromOffset(m.ctx, transcriptPath, offset)
 	}
 	if err != nil {
 		log.Printf("Failed to parse transcript for session %s (provider: %s): %v", session.ID, provider, err)
@@ -278,7 +311,11 @@ func (m *Monitor) processSession(swp *SessionWithProvider) {
 		log.Printf("Total messages for session %s: %d", session.ID, totalMessages)
 		if m.summaryManager.ShouldUpdateSummary(session.ID, totalMessages) {
 			log.Printf("Updating summary for session %s (message count: %d)", session.ID, totalMessages)
-			if err := m.summaryManager.UpdateSessionSummary(session.ID); err != nil {
+			var aliases []string
+			if transcriptSessionID != session.ID {
+				aliases = append(aliases, transcriptSessionID)
+			}
+			if err := m.summaryManager.UpdateSessionSummary(session.ID, aliases...); err != nil {
 				log.Printf("Failed to update summary for session %s: %v", session.ID, err)
 			} else {
 				log.Printf("Successfully updated summary for session %s", session.ID)
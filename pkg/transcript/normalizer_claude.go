@@ -1,7 +1,9 @@
 package transcript
 
 import (
+	"bytes"
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -12,6 +14,9 @@ type ClaudeNormalizer struct {
 	pendingToolCalls map[string]*pendingToolCallRef
 	// pendingEntries accumulates assistant entries with tool calls waiting for results
 	pendingEntries []*UnifiedEntry
+	// planMode is whether the session is in plan mode, as of the last user
+	// prompt or plan-mode tool call.
+	planMode bool
 }
 
 // pendingToolCallRef tracks where a tool call is located
@@ -58,11 +63,54 @@ func (n *ClaudeNormalizer) NormalizeLine(line []byte) (*UnifiedEntry, error) {
 		IsSidechain bool            `json:"isSidechain"`
 		PromptID    string          `json:"promptId"`
 		Message     json.RawMessage `json:"message"`
+		// PermissionMode is "plan" on prompts sent in plan mode.
+		PermissionMode string `json:"permissionMode"`
+		// Summary is the conversation title on "summary" lines.
+		Summary string `json:"summary"`
+		// IsCompactSummary marks the user message that carries the summary
+		// a compaction replaced the conversation with.
+		IsCompactSummary bool `json:"isCompactSummary"`
 	}
 	if err := json.Unmarshal(line, &raw); err != nil {
 		return nil, err
 	}
 
+	// Slash commands and hook runs recorded outside the conversation become
+	// dimmed "system" entries; other non-message lines are skipped.
+	if raw.Type == "system" || raw.Type == "attachment" {
+		var event claudeEventLine
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, err
+		}
+		parts := event.eventParts()
+		if len(parts) == 0 {
+			return nil, nil
+		}
+		return &UnifiedEntry{
+			Role:        "system",
+			Timestamp:   raw.Timestamp,
+			Provider:    "claude",
+			AgentID:     raw.AgentID,
+			IsSidechain: raw.IsSidechain,
+			Parts:       parts,
+		}, nil
+	}
+
+	// Conversation titles become summary dividers.
+	if raw.Type == "summary" {
+		if strings.TrimSpace(raw.Summary) == "" {
+			return nil, nil
+		}
+		return &UnifiedEntry{
+			Role:     "system",
+			Provider: "claude",
+			Parts: []UnifiedPart{{Type: "summary", Content: UnifiedSummary{
+				Kind: SummaryTitle,
+				Text: strings.TrimSpace(raw.Summary),
+			}}},
+		}, nil
+	}
+
 	// Only process user/assistant entries
 	if raw.Type != "user" && raw.Type != "assistant" {
 		return nil, nil
@@ -81,15 +129,41 @@ func (n *ClaudeNormalizer) NormalizeLine(line []byte) (*UnifiedEntry, error) {
 	// Parse message content
 	if raw.Message != nil {
 		var msg struct {
-			ID      string          `json:"id"`
-			Content json.RawMessage `json:"content"`
+			ID         string          `json:"id"`
+			Content    json.RawMessage `json:"content"`
+			StopReason string          `json:"stop_reason"`
 		}
 		if err := json.Unmarshal(raw.Message, &msg); err == nil {
 			entry.MessageID = msg.ID
+			entry.StopReason = msg.StopReason
 			entry.Parts = n.parseContent(msg.Content)
+			// The local-command caveat and empty command output carry
+			// nothing worth showing.
+			if len(entry.Parts) == 0 && bytes.Contains(msg.Content, []byte("<local-command-")) {
+				return nil, nil
+			}
+		}
+	}
+
+	// The summary a compaction left in place of the conversation is not
+	// something the user typed.
+	if raw.IsCompactSummary {
+		var text []string
+		for _, part := range entry.Parts {
+			if tc, ok := part.Content.(UnifiedTextContent); ok {
+				text = append(text, tc.Text)
+			}
 		}
+		entry.Role = "system"
+		entry.Parts = []UnifiedPart{{Type: "summary", Content: UnifiedSummary{
+			Kind: SummaryCompaction,
+			Text: strings.TrimSpace(strings.Join(text, "\n")),
+		}}}
+		return entry, nil
 	}
 
+	n.trackPlanMode(entry, raw.PermissionMode)
+
 	// Handle assistant messages
 	if raw.Type == "assistant" {
 		// Check if this entry has tool calls
@@ -134,6 +208,14 @@ func (n *ClaudeNormalizer) NormalizeLine(line []byte) (*UnifiedEntry, error) {
 							if ref.partIndex < len(pendingEntry.Parts) {
 								if tc, ok := pendingEntry.Parts[ref.partIndex].Content.(UnifiedToolCall); ok {
 									tc.Output = tr.Output
+									tc.ExitCode = tr.ExitCode
+									tc.Images = tr.Images
+									if tr.IsError {
+										tc.Status = "error"
+									}
+									if !pendingEntry.Timestamp.IsZero() && entry.Timestamp.After(pendingEntry.Timestamp) {
+										tc.DurationMs = entry.Timestamp.Sub(pendingEntry.Timestamp).Milliseconds()
+									}
 									pendingEntry.Parts[ref.partIndex].Content = tc
 								}
 							}
@@ -168,6 +250,8 @@ func (n *ClaudeNormalizer) NormalizeLine(line []byte) (*UnifiedEntry, error) {
 						if tc, ok := part.Content.(UnifiedTextContent); ok && tc.Text != "" {
 							return entry, nil
 						}
+					} else {
+						return entry, nil // slash command or its output
 					}
 				}
 			}
@@ -182,12 +266,48 @@ func (n *ClaudeNormalizer) NormalizeLine(line []byte) (*UnifiedEntry, error) {
 	return entry, nil
 }
 
+func hasPartType(parts []UnifiedPart, partType string) bool {
+	for _, part := range parts {
+		if part.Type == partType {
+			return true
+		}
+	}
+	return false
+}
+
+// trackPlanMode follows plan-mode transitions. Plan-mode tool calls switch
+// the mode themselves; otherwise a prompt whose permission mode differs from
+// the current one gets a leading plan_mode part. Tool-result lines are not
+// prompts and never change the mode.
+func (n *ClaudeNormalizer) trackPlanMode(entry *UnifiedEntry, permissionMode string) {
+	for _, part := range entry.Parts {
+		if tc, ok := part.Content.(UnifiedToolCall); ok && part.Type == "tool_call" {
+			switch tc.Name {
+			case ToolEnterPlanMode:
+				n.planMode = true
+			case ToolExitPlanMode:
+				n.planMode = false
+			}
+		}
+	}
+	if entry.Role != "user" || permissionMode == "" || !hasPartType(entry.Parts, "text") {
+		return
+	}
+	if active := permissionMode == "plan"; active != n.planMode {
+		n.planMode = active
+		entry.Parts = append([]UnifiedPart{{Type: "plan_mode", Content: UnifiedPlanMode{Active: active}}}, entry.Parts...)
+	}
+}
+
 func (n *ClaudeNormalizer) parseContent(content json.RawMessage) []UnifiedPart {
 	var parts []UnifiedPart
 
 	// Try string content first (user messages)
 	var strContent string
 	if err := json.Unmarshal(content, &strContent); err == nil {
+		if cmdParts, ok := parseCommandText(strContent); ok {
+			return cmdParts
+		}
 		if strContent != "" {
 			parts = append(parts, UnifiedPart{
 				Type:    "text",
@@ -205,14 +325,16 @@ func (n *ClaudeNormalizer) parseContent(content json.RawMessage) []UnifiedPart {
 
 	for _, rawItem := range contentArray {
 		var item struct {
-			Type      string          `json:"type"`
-			Text      string          `json:"text"`
-			Thinking  string          `json:"thinking"` // Claude's extended thinking
-			ID        string          `json:"id"`
-			Name      string          `json:"name"`
-			Input     json.RawMessage `json:"input"`
-			ToolUseID string          `json:"tool_use_id"`
-			Content   json.RawMessage `json:"content"`
+			Type      string            `json:"type"`
+			Text      string            `json:"text"`
+			Thinking  string            `json:"thinking"` // Claude's extended thinking
+			ID        string            `json:"id"`
+			Name      string            `json:"name"`
+			Input     json.RawMessage   `json:"input"`
+			ToolUseID string            `json:"tool_use_id"`
+			Content   json.RawMessage   `json:"content"`
+			IsError   bool              `json:"is_error"`
+			Source    claudeImageSource `json:"source"`
 		}
 		if err := json.Unmarshal(rawItem, &item); err != nil {
 			continue
@@ -220,12 +342,19 @@ func (n *ClaudeNormalizer) parseContent(content json.RawMessage) []UnifiedPart {
 
 		switch item.Type {
 		case "text":
-			if item.Text != "" {
+			if cmdParts, ok := parseCommandText(item.Text); ok {
+				parts = append(parts, cmdParts...)
+			} else if item.Text != "" {
 				parts = append(parts, UnifiedPart{
 					Type:    "text",
 					Content: UnifiedTextContent{Text: item.Text},
 				})
 			}
+		case "image":
+			parts = append(parts, UnifiedPart{
+				Type:    "image",
+				Content: parseClaudeImage(item.Source),
+			})
 		case "thinking":
 			// Claude's extended thinking - display as reasoning
 			if item.Thinking != "" {
@@ -246,13 +375,14 @@ func (n *ClaudeNormalizer) parseContent(content json.RawMessage) []UnifiedPart {
 				},
 			})
 		case "tool_result":
-			var output string
-			_ = json.Unmarshal(item.Content, &output)
+			output, images := parseClaudeToolResultContent(item.Content)
 			parts = append(parts, UnifiedPart{
 				Type: "tool_result",
 				Content: UnifiedToolResult{
 					ToolCallID: item.ToolUseID,
 					Output:     output,
+					IsError:    item.IsError,
+					Images:     images,
 				},
 			})
 		}
@@ -260,3 +390,32 @@ func (n *ClaudeNormalizer) parseContent(content json.RawMessage) []UnifiedPart {
 
 	return parts
 }
+
+// parseClaudeToolResultContent reads a tool_result's content: a string, or
+// an array of text and image blocks (screenshots, image file reads, and
+// sub-agent results), whose text blocks are joined into the output.
+func parseClaudeToolResultContent(content json.RawMessage) (string, []UnifiedImage) {
+	var output string
+	if err := json.Unmarshal(content, &output); err == nil {
+		return output, nil
+	}
+	var blocks []struct {
+		Type   string            `json:"type"`
+		Text   string            `json:"text"`
+		Source claudeImageSource `json:"source"`
+	}
+	if err := json.Unmarshal(content, &blocks); err != nil {
+		return "", nil
+	}
+	var texts []string
+	var images []UnifiedImage
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			texts = append(texts, b.Text)
+		case "image":
+			images = append(images, parseClaudeImage(b.Source))
+		}
+	}
+	return strings.Join(texts, "\n"), images
+}
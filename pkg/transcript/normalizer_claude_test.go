@@ -0,0 +1,135 @@
+package transcript
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClaudeNormalizerCommandsAndHooks(t *testing.T) {
+	lines := []string{
+		`{"type":"user","timestamp":"2026-01-01T00:00:00Z","message":{"role":"user","content":"<local-command-caveat>Caveat: The messages below were generated by the user while running local commands.</local-command-caveat>"}}`,
+		`{"type":"user","timestamp":"2026-01-01T00:00:01Z","message":{"role":"user","content":"<command-name>/model</command-name>\n<command-message>model</command-message>\n<command-args>opus</command-args>"}}`,
+		`{"type":"user","timestamp":"2026-01-01T00:00:02Z","message":{"role":"user","content":"<local-command-stdout>Set model to \u001b[1mopus\u001b[22m</local-command-stdout>"}}`,
+		`{"type":"system","timestamp":"2026-01-01T00:00:03Z","content":"\u001b[1mPostToolUse:Edit\u001b[22m [prettier --write] completed successfully","level":"info"}`,
+		`{"type":"system","timestamp":"2026-01-01T00:00:04Z","subtype":"stop_hook_summary","hookInfos":[{"command":"notify.sh"}],"hookErrors":[]}`,
+		`{"type":"attachment","timestamp":"2026-01-01T00:00:05Z","attachment":{"type":"hook_additional_context","hookName":"SessionStart:startup","content":"branch: main"}}`,
+		`{"type":"system","timestamp":"2026-01-01T00:00:06Z","subtype":"compact_boundary","content":"Conversation compacted"}`,
+	}
+
+	n := NewClaudeNormalizer()
+	var got []UnifiedPart
+	var roles []string
+	for _, line := range lines {
+		entry, err := n.NormalizeLine([]byte(line))
+		if err != nil {
+			t.Fatalf("NormalizeLine(%s): %v", line, err)
+		}
+		if entry == nil {
+			continue
+		}
+		roles = append(roles, entry.Role)
+		if len(entry.Parts) == 0 {
+			t.Errorf("entry with no parts emitted for %s", line)
+		}
+		got = append(got, entry.Parts...)
+	}
+
+	want := []UnifiedPart{
+		{Type: "command", Content: UnifiedCommand{Name: "/model", Args: "opus"}},
+		{Type: "command_output", Content: UnifiedCommandOutput{Output: "Set model to opus"}},
+		{Type: "hook", Content: UnifiedHook{Event: "PostToolUse:Edit", Command: "prettier --write", Output: "completed successfully"}},
+		{Type: "hook", Content: UnifiedHook{Event: "Stop", Command: "notify.sh"}},
+		{Type: "hook", Content: UnifiedHook{Event: "SessionStart:startup", Output: "branch: main"}},
+		{Type: "summary", Content: UnifiedSummary{Kind: SummaryCompaction}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parts =\n%+v\nwant\n%+v", got, want)
+	}
+	if wantRoles := []string{"user", "user", "system", "system", "system", "system"}; !reflect.DeepEqual(roles, wantRoles) {
+		t.Errorf("roles = %v, want %v", roles, wantRoles)
+	}
+}
+
+func TestClaudeNormalizerSummaries(t *testing.T) {
+	lines := []string{
+		`{"type":"summary","summary":"Refactor the session scanner","leafUuid":"u9"}`,
+		`{"type":"user","timestamp":"2026-01-01T00:00:00Z","message":{"role":"user","content":"split the scanner"}}`,
+		`{"type":"system","timestamp":"2026-01-01T00:10:00Z","subtype":"compact_boundary","content":"Conversation compacted","compactMetadata":{"trigger":"auto","preTokens":155012}}`,
+		`{"type":"user","timestamp":"2026-01-01T00:10:01Z","isCompactSummary":true,"message":{"role":"user","content":"This session is being continued from a previous conversation.\nSummary: the scanner was split."}}`,
+		`{"type":"summary","summary":"  ","leafUuid":"u10"}`,
+	}
+
+	n := NewClaudeNormalizer()
+	var got []UnifiedEntry
+	for _, line := range lines {
+		entry, err := n.NormalizeLine([]byte(line))
+		if err != nil {
+			t.Fatalf("NormalizeLine(%s): %v", line, err)
+		}
+		if entry != nil {
+			got = append(got, *entry)
+		}
+	}
+
+	want := []struct {
+		role string
+		part UnifiedPart
+	}{
+		{"system", UnifiedPart{Type: "summary", Content: UnifiedSummary{Kind: SummaryTitle, Text: "Refactor the session scanner"}}},
+		{"user", UnifiedPart{Type: "text", Content: UnifiedTextContent{Text: "split the scanner"}}},
+		{"system", UnifiedPart{Type: "summary", Content: UnifiedSummary{Kind: SummaryCompaction, Trigger: "auto", PreTokens: 155012}}},
+		{"system", UnifiedPart{Type: "summary", Content: UnifiedSummary{
+			Kind: SummaryCompaction,
+			Text: "This session is being continued from a previous conversation.\nSummary: the scanner was split.",
+		}}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Role != w.role || len(got[i].Parts) != 1 || !reflect.DeepEqual(got[i].Parts[0], w.part) {
+			t.Errorf("entry %d = %s %+v, want %s %+v", i, got[i].Role, got[i].Parts, w.role, w.part)
+		}
+	}
+}
+
+func TestClaudeNormalizerPlanMode(t *testing.T) {
+	lines := []string{
+		`{"type":"user","timestamp":"2026-01-01T00:00:00Z","permissionMode":"default","message":{"role":"user","content":"hello"}}`,
+		`{"type":"user","timestamp":"2026-01-01T00:00:01Z","permissionMode":"plan","message":{"role":"user","content":"plan the refactor"}}`,
+		`{"type":"assistant","timestamp":"2026-01-01T00:00:02Z","message":{"id":"m1","content":[{"type":"tool_use","id":"t1","name":"ExitPlanMode","input":{"plan":"1. Split the parser"}}]}}`,
+		`{"type":"user","timestamp":"2026-01-01T00:00:03Z","permissionMode":"plan","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"User has approved your plan. You can now start coding."}]}}`,
+		`{"type":"user","timestamp":"2026-01-01T00:00:04Z","permissionMode":"default","message":{"role":"user","content":"go"}}`,
+	}
+
+	n := NewClaudeNormalizer()
+	var entries []UnifiedEntry
+	for _, line := range lines {
+		entry, err := n.NormalizeLine([]byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+
+	var planModeParts []UnifiedPlanMode
+	for _, e := range entries {
+		for _, p := range e.Parts {
+			if p.Type == "plan_mode" {
+				planModeParts = append(planModeParts, p.Content.(UnifiedPlanMode))
+			}
+		}
+	}
+	// Entering is marked on the prompt; leaving happens via ExitPlanMode, so
+	// the following "default" prompt adds no second marker.
+	if want := []UnifiedPlanMode{{Active: true}}; !reflect.DeepEqual(planModeParts, want) {
+		t.Errorf("plan_mode parts = %+v, want %+v", planModeParts, want)
+	}
+
+	plans := ExtractPlans(entries)
+	if len(plans) != 1 || plans[0].Text != "1. Split the parser" || plans[0].Outcome != PlanApproved {
+		t.Errorf("ExtractPlans = %+v, want one approved plan", plans)
+	}
+}
@@ -160,22 +160,27 @@ func (n *CodexNormalizer) NormalizeLine(line []byte) (*UnifiedEntry, error) {
 			// Parse the output JSON
 			var outputData struct {
 				Output   string `json:"output"`
-				Metadata struct {
+				Metadata *struct {
 					ExitCode        int     `json:"exit_code"`
 					DurationSeconds float64 `json:"duration_seconds"`
 				} `json:"metadata"`
 			}
 			_ = json.Unmarshal([]byte(outputStr), &outputData)
 
-			isError := outputData.Metadata.ExitCode != 0
+			result := UnifiedToolResult{
+				ToolCallID: callID,
+				Output:     outputData.Output,
+			}
+			if md := outputData.Metadata; md != nil {
+				exitCode := md.ExitCode
+				result.ExitCode = &exitCode
+				result.IsError = exitCode != 0
+				result.DurationMs = int64(md.DurationSeconds * 1000)
+			}
 
 			entry.Parts = append(entry.Parts, UnifiedPart{
-				Type: "tool_result",
-				Content: UnifiedToolResult{
-					ToolCallID: callID,
-					Output:     outputData.Output,
-					IsError:    isError,
-				},
+				Type:    "tool_result",
+				Content: result,
 			})
 
 		default:
@@ -79,6 +79,23 @@ func TestCodexNormalizer_MalformedArgumentsKeptRaw(t *testing.T) {
 	}
 }
 
+func TestCodexNormalizer_FunctionCallOutputExitCode(t *testing.T) {
+	n := NewCodexNormalizer()
+	line := `{"timestamp":"2026-07-01T10:00:04.000Z","type":"response_item","payload":{"type":"function_call_output","call_id":"call_1","output":"{\"output\":\"boom\\n\",\"metadata\":{\"exit_code\":2,\"duration_seconds\":1.25}}"}}`
+
+	entry, err := n.NormalizeLine([]byte(line))
+	if err != nil || entry == nil || len(entry.Parts) != 1 {
+		t.Fatalf("NormalizeLine = %+v, %v", entry, err)
+	}
+	tr, ok := entry.Parts[0].Content.(UnifiedToolResult)
+	if !ok {
+		t.Fatalf("part content type %T, want UnifiedToolResult", entry.Parts[0].Content)
+	}
+	if tr.ExitCode == nil || *tr.ExitCode != 2 || !tr.IsError || tr.DurationMs != 1250 || tr.Output != "boom\n" {
+		t.Errorf("result = %+v, want exit 2 after 1250ms", tr)
+	}
+}
+
 func TestCodexNormalizer_TokenCount(t *testing.T) {
 	n := NewCodexNormalizer()
 	line := `{"timestamp":"2026-07-01T10:00:08.000Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1200,"cached_input_tokens":1000,"output_tokens":150,"reasoning_output_tokens":40,"total_tokens":1350},"last_token_usage":{"input_tokens":1200,"cached_input_tokens":1000,"output_tokens":150,"reasoning_output_tokens":40,"total_tokens":1350},"model_context_window":272000},"rate_limits":null}}`
@@ -61,13 +61,15 @@ func (n *OpenCodeNormalizer) NormalizeEntry(oc opencode.TranscriptEntry) *Unifie
 				entry.Parts = append(entry.Parts, UnifiedPart{
 					Type: "tool_call",
 					Content: UnifiedToolCall{
-						ID:     toolPart.CallID,
-						Name:   toolPart.Tool,
-						Status: toolPart.Status,
-						Input:  toolPart.Input,
-						Output: toolPart.Output,
-						Title:  toolPart.Title,
-						Diff:   toolPart.Diff,
+						ID:         toolPart.CallID,
+						Name:       toolPart.Tool,
+						Status:     toolPart.Status,
+						Input:      toolPart.Input,
+						Output:     toolPart.Output,
+						Title:      toolPart.Title,
+						Diff:       toolPart.Diff,
+						DurationMs: toolPart.DurationMs,
+						ExitCode:   toolPart.Exit,
 					},
 				})
 			}
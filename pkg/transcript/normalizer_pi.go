@@ -167,6 +167,7 @@ func normalizePiMessage(raw *piFileEntry) *UnifiedEntry {
 
 	case "assistant":
 		entry := newPiUnifiedEntry(raw, "assistant")
+		entry.StopReason = msg.StopReason
 		var blocks []piContentBlock
 		_ = json.Unmarshal(msg.Content, &blocks)
 		for _, b := range blocks {
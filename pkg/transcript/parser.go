@@ -1,11 +1,11 @@
 package transcript
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"time"
 )
 
@@ -95,37 +95,40 @@ func NewParser() *Parser {
 
 // ParseFile parses an entire JSONL file and extracts messages
 func (p *Parser) ParseFile(path string) ([]ExtractedMessage, error) {
-	file, err := os.Open(path)
+	return p.ParseFileContext(context.Background(), path)
+}
+
+// ParseFileContext is ParseFile, stopping with ctx's error when ctx is
+// cancelled.
+func (p *Parser) ParseFileContext(ctx context.Context, path string) ([]ExtractedMessage, error) {
+	file, err := OpenTranscript(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	return p.parseFromReader(file, 0)
+	return p.parseFromReader(ctx, file, 0)
 }
 
 // ParseFileFromOffset parses a JSONL file starting from a specific byte offset
 func (p *Parser) ParseFileFromOffset(path string, offset int64) ([]ExtractedMessage, int64, error) {
-	file, err := os.Open(path)
+	return p.parseFileFromOffset(context.Background(), path, offset)
+}
+
+func (p *Parser) parseFileFromOffset(ctx context.Context, path string, offset int64) ([]ExtractedMessage, int64, error) {
+	file, pos, err := openTranscriptAt(path, offset)
 	if err != nil {
 		return nil, offset, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	// Seek to the offset
-	if offset > 0 {
-		if _, err := file.Seek(offset, 0); err != nil {
-			return nil, offset, fmt.Errorf("failed to seek to offset %d: %w", offset, err)
-		}
-	}
-
-	messages, err := p.parseFromReader(file, offset)
+	messages, err := p.parseFromReader(ctx, file, offset)
 	if err != nil {
 		return nil, offset, err
 	}
 
 	// Get new offset
-	newOffset, err := file.Seek(0, 1) // Get current position
+	newOffset, err := pos()
 	if err != nil {
 		return messages, offset, fmt.Errorf("failed to get new offset: %w", err)
 	}
@@ -134,17 +137,15 @@ func (p *Parser) ParseFileFromOffset(path string, offset int64) ([]ExtractedMess
 }
 
 // parseFromReader parses JSONL from a reader
-func (p *Parser) parseFromReader(file *os.File, startOffset int64) ([]ExtractedMessage, error) {
+func (p *Parser) parseFromReader(ctx context.Context, file io.Reader, startOffset int64) ([]ExtractedMessage, error) {
 	var messages []ExtractedMessage
-	scanner := bufio.NewScanner(file)
-
-	// Increase buffer size for large JSON lines
-	const maxScanTokenSize = 1024 * 1024 // 1MB
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, maxScanTokenSize)
+	scanner := NewLineScanner(file)
 
 	lineNum := 0
 	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		lineNum++
 		line := scanner.Bytes()
 
@@ -183,17 +184,15 @@ func (p *Parser) parseFromReader(file *os.File, startOffset int64) ([]ExtractedM
 }
 
 // parseCodexFromReader parses Codex JSONL format from a reader
-func (p *Parser) parseCodexFromReader(file *os.File, startOffset int64) ([]ExtractedMessage, error) {
+func (p *Parser) parseCodexFromReader(ctx context.Context, file io.Reader, startOffset int64) ([]ExtractedMessage, error) {
 	var messages []ExtractedMessage
-	scanner := bufio.NewScanner(file)
-
-	// Increase buffer size for large JSON lines
-	const maxScanTokenSize = 1024 * 1024 // 1MB
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, maxScanTokenSize)
+	scanner := NewLineScanner(file)
 
 	lineNum := 0
 	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		lineNum++
 		line := scanner.Bytes()
 
@@ -247,26 +246,23 @@ func (p *Parser) parseCodexFromReader(file *os.File, startOffset int64) ([]Extra
 
 // ParseCodexFileFromOffset parses a Codex JSONL file starting from a specific byte offset
 func (p *Parser) ParseCodexFileFromOffset(path string, offset int64) ([]ExtractedMessage, int64, error) {
-	file, err := os.Open(path)
+	return p.parseCodexFileFromOffset(context.Background(), path, offset)
+}
+
+func (p *Parser) parseCodexFileFromOffset(ctx context.Context, path string, offset int64) ([]ExtractedMessage, int64, error) {
+	file, pos, err := openTranscriptAt(path, offset)
 	if err != nil {
 		return nil, offset, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	// Seek to the offset
-	if offset > 0 {
-		if _, err := file.Seek(offset, 0); err != nil {
-			return nil, offset, fmt.Errorf("failed to seek to offset %d: %w", offset, err)
-		}
-	}
-
-	messages, err := p.parseCodexFromReader(file, offset)
+	messages, err := p.parseCodexFromReader(ctx, file, offset)
 	if err != nil {
 		return nil, offset, err
 	}
 
 	// Get new offset
-	newOffset, err := file.Seek(0, 1) // Get current position
+	newOffset, err := pos()
 	if err != nil {
 		return messages, offset, fmt.Errorf("failed to get new offset: %w", err)
 	}
@@ -369,7 +365,7 @@ func GetTranscriptPath(sessionID, provider string) (string, error) {
 		pattern = fmt.Sprintf("%s/.claude/projects/*/%s.jsonl", homeDir, sessionID)
 	}
 
-	matches, err := filepath.Glob(pattern)
+	matches, err := GlobTranscripts(pattern)
 	if err != nil {
 		return "", err
 	}
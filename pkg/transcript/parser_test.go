@@ -0,0 +1,25 @@
+package transcript
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFileContextCancelled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	line := `{"type":"user","sessionId":"s","message":{"role":"user","content":"hi"}}` + "\n"
+	if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewParser().ParseFileContext(ctx, path); !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseFileContext = %v, want context.Canceled", err)
+	}
+	if msgs, err := NewParser().ParseFile(path); err != nil || len(msgs) != 1 {
+		t.Errorf("ParseFile = %d messages, %v; want 1", len(msgs), err)
+	}
+}
@@ -0,0 +1,127 @@
+package transcript
+
+// Part content is a typed struct when the normalizer ran in-process but a
+// map[string]interface{} after a JSON round-trip (the daemon, --json
+// output read back). These accessors accept both shapes, so consumers
+// needn't type-switch on UnifiedPart.Content themselves.
+
+// TextOf returns the text of a "text" or "reasoning" part.
+func TextOf(part UnifiedPart) string {
+	switch content := part.Content.(type) {
+	case UnifiedTextContent:
+		return content.Text
+	case UnifiedReasoning:
+		return content.Text
+	case map[string]interface{}:
+		return StringField(content, "text")
+	}
+	return ""
+}
+
+// ToolCallOf returns the tool call of a "tool_call" part.
+func ToolCallOf(part UnifiedPart) UnifiedToolCall {
+	switch content := part.Content.(type) {
+	case UnifiedToolCall:
+		return content
+	case map[string]interface{}:
+		call := UnifiedToolCall{
+			ID:         StringField(content, "id"),
+			Name:       StringField(content, "name"),
+			Status:     StringField(content, "status"),
+			Output:     StringField(content, "output"),
+			Title:      StringField(content, "title"),
+			Diff:       StringField(content, "diff"),
+			DurationMs: int64Field(content, "durationMs"),
+			ExitCode:   intPtrField(content, "exitCode"),
+			Images:     imagesField(content, "images"),
+		}
+		if input, ok := content["input"].(map[string]interface{}); ok {
+			call.Input = input
+		}
+		return call
+	}
+	return UnifiedToolCall{}
+}
+
+// ToolResultOf returns the result of a "tool_result" part.
+func ToolResultOf(part UnifiedPart) UnifiedToolResult {
+	switch content := part.Content.(type) {
+	case UnifiedToolResult:
+		return content
+	case map[string]interface{}:
+		isError, _ := content["isError"].(bool)
+		return UnifiedToolResult{
+			ToolCallID: StringField(content, "toolCallID"),
+			Output:     StringField(content, "output"),
+			IsError:    isError,
+			ExitCode:   intPtrField(content, "exitCode"),
+			DurationMs: int64Field(content, "durationMs"),
+			Images:     imagesField(content, "images"),
+		}
+	}
+	return UnifiedToolResult{}
+}
+
+// SummaryOf returns the summary of a "summary" part.
+func SummaryOf(part UnifiedPart) UnifiedSummary {
+	switch content := part.Content.(type) {
+	case UnifiedSummary:
+		return content
+	case map[string]interface{}:
+		return UnifiedSummary{
+			Kind:      StringField(content, "kind"),
+			Text:      StringField(content, "text"),
+			Trigger:   StringField(content, "trigger"),
+			PreTokens: int(int64Field(content, "preTokens")),
+		}
+	}
+	return UnifiedSummary{}
+}
+
+// ImageOf returns the image of an "image" part. After a JSON round-trip
+// only its media type and size remain.
+func ImageOf(part UnifiedPart) UnifiedImage {
+	switch content := part.Content.(type) {
+	case UnifiedImage:
+		return content
+	case map[string]interface{}:
+		return mapImage(content)
+	}
+	return UnifiedImage{}
+}
+
+func mapImage(m map[string]interface{}) UnifiedImage {
+	return UnifiedImage{MediaType: StringField(m, "mediaType"), Size: int(int64Field(m, "size"))}
+}
+
+func imagesField(m map[string]interface{}, key string) []UnifiedImage {
+	list, _ := m[key].([]interface{})
+	var images []UnifiedImage
+	for _, item := range list {
+		if im, ok := item.(map[string]interface{}); ok {
+			images = append(images, mapImage(im))
+		}
+	}
+	return images
+}
+
+// StringField returns m[key] when it is a string, or "". It reads tool
+// inputs and content decoded from JSON.
+func StringField(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func int64Field(m map[string]interface{}, key string) int64 {
+	n, _ := m[key].(float64)
+	return int64(n)
+}
+
+func intPtrField(m map[string]interface{}, key string) *int {
+	n, ok := m[key].(float64)
+	if !ok {
+		return nil
+	}
+	i := int(n)
+	return &i
+}
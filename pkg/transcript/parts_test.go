@@ -0,0 +1,66 @@
+package transcript
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// roundTrip returns parts as they come back from JSON, with map content.
+func roundTrip(t *testing.T, parts []UnifiedPart) []UnifiedPart {
+	t.Helper()
+	data, err := json.Marshal(parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []UnifiedPart
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestPartAccessorsAcceptBothShapes(t *testing.T) {
+	code := 2
+	call := UnifiedToolCall{
+		ID: "t1", Name: "bash", Input: map[string]interface{}{"command": "make"},
+		Status: "error", Output: "boom", Title: "Build", Diff: "-a\n+b",
+		DurationMs: 1500, ExitCode: &code,
+		Images: []UnifiedImage{{MediaType: "image/png", Size: 10}},
+	}
+	result := UnifiedToolResult{ToolCallID: "t1", Output: "done", IsError: true, ExitCode: &code, DurationMs: 20}
+	summary := UnifiedSummary{Kind: SummaryCompaction, Text: "so far", Trigger: "auto", PreTokens: 9000}
+	image := UnifiedImage{MediaType: "image/jpeg", Size: 42}
+	typed := []UnifiedPart{
+		{Type: "text", Content: UnifiedTextContent{Text: "hi"}},
+		{Type: "reasoning", Content: UnifiedReasoning{Text: "hmm"}},
+		{Type: "tool_call", Content: call},
+		{Type: "tool_result", Content: result},
+		{Type: "summary", Content: summary},
+		{Type: "image", Content: image},
+	}
+
+	for name, parts := range map[string][]UnifiedPart{"typed": typed, "decoded": roundTrip(t, typed)} {
+		if got := TextOf(parts[0]); got != "hi" {
+			t.Errorf("%s: TextOf(text) = %q", name, got)
+		}
+		if got := TextOf(parts[1]); got != "hmm" {
+			t.Errorf("%s: TextOf(reasoning) = %q", name, got)
+		}
+		if got := ToolCallOf(parts[2]); !reflect.DeepEqual(got, call) {
+			t.Errorf("%s: ToolCallOf = %+v, want %+v", name, got, call)
+		}
+		if got := ToolResultOf(parts[3]); !reflect.DeepEqual(got, result) {
+			t.Errorf("%s: ToolResultOf = %+v, want %+v", name, got, result)
+		}
+		if got := SummaryOf(parts[4]); got != summary {
+			t.Errorf("%s: SummaryOf = %+v, want %+v", name, got, summary)
+		}
+		if got := ImageOf(parts[5]); got != image {
+			t.Errorf("%s: ImageOf = %+v, want %+v", name, got, image)
+		}
+		if got := ToolCallOf(parts[0]); got.Name != "" {
+			t.Errorf("%s: ToolCallOf(text) = %+v, want zero", name, got)
+		}
+	}
+}
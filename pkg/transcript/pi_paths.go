@@ -66,7 +66,7 @@ func IsPiSessionPath(path string) bool {
 	if strings.Contains(slashed, "/.pi/") || strings.Contains(slashed, "/.grove-agent/") {
 		return true
 	}
-	if strings.ToLower(filepath.Ext(path)) != ".jsonl" {
+	if strings.ToLower(filepath.Ext(strings.TrimSuffix(path, CompressedExt))) != ".jsonl" {
 		return false
 	}
 	parent := filepath.Base(filepath.Dir(path))
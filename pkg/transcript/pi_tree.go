@@ -1,7 +1,6 @@
 package transcript
 
 import (
-	"bufio"
 	"encoding/json"
 	"io"
 )
@@ -68,8 +67,7 @@ type PiBranch struct {
 // The buffer sizing matters too: a 4MB max token guards long lines, and
 // shrinking it would silently drop exactly the large sessions worth measuring.
 func ParsePiSessionTree(r io.Reader) (*PiSessionTree, error) {
-	scanner := bufio.NewScanner(r)
-	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	scanner := NewLineScanner(r)
 
 	t := &PiSessionTree{
 		byID:     make(map[string]*piFileEntry),
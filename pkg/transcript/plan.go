@@ -0,0 +1,121 @@
+package transcript
+
+import (
+	"strings"
+	"time"
+)
+
+// Claude Code's plan-mode tools. ExitPlanMode carries the proposed plan in
+// its "plan" input; its result says whether the user accepted it.
+const (
+	ToolEnterPlanMode = "EnterPlanMode"
+	ToolExitPlanMode  = "ExitPlanMode"
+)
+
+// ToolCodexUpdatePlan is Codex's planning tool. Its input is a step list
+// ({"explanation", "plan": [{"step", "status"}]}) that the agent re-sends
+// as steps progress.
+const ToolCodexUpdatePlan = "update_plan"
+
+// Plan outcomes reported by PlanOutcome.
+const (
+	PlanApproved = "approved"
+	PlanRejected = "rejected"
+)
+
+// ProposedPlan is a plan the agent presented for approval in plan mode.
+type ProposedPlan struct {
+	Timestamp time.Time `json:"timestamp"`
+	Text      string    `json:"text"`
+	Outcome   string    `json:"outcome,omitempty"` // PlanApproved, PlanRejected, or "" when unanswered
+	Source    string    `json:"source"`            // the tool that proposed it
+}
+
+// PlanOutcome classifies the result of an ExitPlanMode call.
+func PlanOutcome(tc UnifiedToolCall) string {
+	switch {
+	case tc.Status == "error":
+		return PlanRejected
+	case strings.Contains(tc.Output, "approved"):
+		return PlanApproved
+	case tc.Output != "":
+		return PlanRejected
+	}
+	return ""
+}
+
+// PlanText returns the plan proposed by an ExitPlanMode call.
+func PlanText(tc UnifiedToolCall) string {
+	text, _ := tc.Input["plan"].(string)
+	return strings.TrimSpace(text)
+}
+
+// ExtractPlans returns the plans proposed in a transcript, in order. Codex
+// re-sends its plan on every status change; successive updates of the same
+// step list collapse into one plan showing the latest statuses.
+func ExtractPlans(entries []UnifiedEntry) []ProposedPlan {
+	var plans []ProposedPlan
+	lastCodexSteps := ""
+	for _, entry := range entries {
+		for _, part := range entry.Parts {
+			if part.Type != "tool_call" {
+				continue
+			}
+			tc, ok := part.Content.(UnifiedToolCall)
+			if !ok {
+				continue
+			}
+			switch tc.Name {
+			case ToolExitPlanMode:
+				if text := PlanText(tc); text != "" {
+					plans = append(plans, ProposedPlan{Timestamp: entry.Timestamp, Text: text, Outcome: PlanOutcome(tc), Source: tc.Name})
+				}
+			case ToolCodexUpdatePlan:
+				text, steps := codexPlanText(tc)
+				if text == "" {
+					continue
+				}
+				plan := ProposedPlan{Timestamp: entry.Timestamp, Text: text, Source: tc.Name}
+				if steps == lastCodexSteps && len(plans) > 0 && plans[len(plans)-1].Source == ToolCodexUpdatePlan {
+					plan.Timestamp = plans[len(plans)-1].Timestamp
+					plans[len(plans)-1] = plan
+				} else {
+					plans = append(plans, plan)
+				}
+				lastCodexSteps = steps
+			}
+		}
+	}
+	return plans
+}
+
+// codexPlanText renders an update_plan call as a Markdown checklist, and
+// returns the step names (without statuses) for comparing updates.
+func codexPlanText(tc UnifiedToolCall) (text, steps string) {
+	items, _ := tc.Input["plan"].([]interface{})
+	var b, names strings.Builder
+	if explanation, _ := tc.Input["explanation"].(string); strings.TrimSpace(explanation) != "" {
+		b.WriteString(strings.TrimSpace(explanation) + "\n\n")
+	}
+	for _, item := range items {
+		m, _ := item.(map[string]interface{})
+		step, _ := m["step"].(string)
+		if step == "" {
+			continue
+		}
+		status, _ := m["status"].(string)
+		switch status {
+		case "completed":
+			b.WriteString("- [x] " + step + "\n")
+		case "in_progress":
+			b.WriteString("- [ ] " + step + " *(in progress)*\n")
+		default:
+			b.WriteString("- [ ] " + step + "\n")
+		}
+		names.WriteString(step + "\n")
+	}
+	if names.Len() == 0 {
+		return "", ""
+	}
+	return strings.TrimSpace(b.String()), names.String()
+}
@@ -0,0 +1,41 @@
+package transcript
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExtractPlansCodexUpdates(t *testing.T) {
+	step := func(name, status string) interface{} {
+		return map[string]interface{}{"step": name, "status": status}
+	}
+	call := func(ts int, steps ...interface{}) UnifiedEntry {
+		return UnifiedEntry{
+			Role:      "assistant",
+			Timestamp: time.Date(2026, 1, 1, 0, 0, ts, 0, time.UTC),
+			Parts: []UnifiedPart{{Type: "tool_call", Content: UnifiedToolCall{
+				Name:  ToolCodexUpdatePlan,
+				Input: map[string]interface{}{"plan": steps},
+			}}},
+		}
+	}
+	entries := []UnifiedEntry{
+		call(1, step("Read code", "in_progress"), step("Fix bug", "pending")),
+		call(2, step("Read code", "completed"), step("Fix bug", "in_progress")),
+		call(3, step("Read code", "completed"), step("Fix bug", "completed"), step("Add test", "pending")),
+	}
+
+	plans := ExtractPlans(entries)
+	if len(plans) != 2 {
+		t.Fatalf("got %d plans, want 2 (updates of the same steps collapse): %+v", len(plans), plans)
+	}
+	if want := "- [x] Read code\n- [ ] Fix bug *(in progress)*"; plans[0].Text != want {
+		t.Errorf("first plan = %q, want %q", plans[0].Text, want)
+	}
+	if plans[0].Timestamp.Second() != 1 {
+		t.Errorf("collapsed plan timestamp = %v, want the first proposal's", plans[0].Timestamp)
+	}
+	if plans[1].Source != ToolCodexUpdatePlan {
+		t.Errorf("source = %q", plans[1].Source)
+	}
+}
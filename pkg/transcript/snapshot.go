@@ -0,0 +1,144 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// Agents append to a transcript a line at a time, but a large line can be
+// written in several chunks, so a reader racing the agent may see its first
+// half. OpenTranscriptSnapshot waits briefly for such a line to be finished
+// before leaving it out.
+const (
+	snapshotRetries = 5
+	snapshotWait    = 50 * time.Millisecond
+)
+
+// OpenTranscriptSnapshot opens a transcript for reading up to its last
+// complete line. A final line that is not yet valid JSON is still being
+// written: after waiting briefly for it to be finished, it is left out and
+// pending reports so. Compressed transcripts never grow and are read whole.
+func OpenTranscriptSnapshot(path string) (r io.ReadCloser, pending bool, err error) {
+	if IsCompressedPath(path) {
+		r, err = OpenTranscript(path)
+		return r, false, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	end, pending, err := completeEnd(f, snapshotRetries)
+	if err != nil {
+		f.Close()
+		return nil, false, err
+	}
+	return &limitedFile{Reader: io.LimitReader(f, end), f: f}, pending, nil
+}
+
+// PendingLine reports whether the transcript at path ends in a line that is
+// still being written, without waiting for it.
+func PendingLine(path string) bool {
+	if IsCompressedPath(path) {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	_, pending, _ := completeEnd(f, 0)
+	return pending
+}
+
+type limitedFile struct {
+	io.Reader
+	f *os.File
+}
+
+func (l *limitedFile) Close() error { return l.f.Close() }
+
+// completeEnd returns the offset just past f's last complete line, retrying
+// up to retries times while the final line is incomplete, and whether an
+// incomplete line was left out. A final line without a newline counts as
+// complete when it is valid JSON.
+func completeEnd(f *os.File, retries int) (int64, bool, error) {
+	for attempt := 0; ; attempt++ {
+		fi, err := f.Stat()
+		if err != nil {
+			return 0, false, err
+		}
+		size := fi.Size()
+		lineStart, tail, err := finalLine(f, size)
+		if err != nil {
+			return 0, false, err
+		}
+		if len(tail) == 0 || json.Valid(tail) {
+			return size, false, nil
+		}
+		if attempt >= retries {
+			return lineStart, true, nil
+		}
+		time.Sleep(snapshotWait)
+	}
+}
+
+// finalLine returns the offset and content of the text after the last
+// newline in the first size bytes of f.
+func finalLine(f *os.File, size int64) (int64, []byte, error) {
+	const chunk int64 = 64 * 1024
+	var tail []byte
+	end := size
+	for end > 0 {
+		start := end - chunk
+		if start < 0 {
+			start = 0
+		}
+		buf := make([]byte, end-start)
+		if _, err := f.ReadAt(buf, start); err != nil && err != io.EOF {
+			return 0, nil, err
+		}
+		if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+			return start + int64(i) + 1, append(buf[i+1:], tail...), nil
+		}
+		tail = append(buf, tail...)
+		end = start
+	}
+	return 0, tail, nil
+}
+
+// TailReader reads the lines of a transcript that is being appended to. A
+// final line without its newline is held back until the rest of it has been
+// written, so a follower never sees half a line.
+type TailReader struct {
+	r       *bufio.Reader
+	partial []byte
+}
+
+// NewTailReader returns a TailReader over r, typically a file positioned
+// where following should start.
+func NewTailReader(r io.Reader) *TailReader {
+	return &TailReader{r: bufio.NewReader(r)}
+}
+
+// ReadLine returns the next complete line, with its newline. It returns
+// io.EOF when no complete line is available yet; call it again once the
+// file has grown.
+func (t *TailReader) ReadLine() ([]byte, error) {
+	line, err := t.r.ReadBytes('\n')
+	if err == io.EOF {
+		t.partial = append(t.partial, line...)
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(t.partial) > 0 {
+		line = append(t.partial, line...)
+		t.partial = nil
+	}
+	return line, nil
+}
@@ -0,0 +1,71 @@
+package transcript
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenTranscriptSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	complete := `{"a":1}` + "\n" + `{"b":2}` + "\n"
+	tests := []struct {
+		name, content, want string
+		pending             bool
+	}{
+		{"complete", complete, complete, false},
+		{"no final newline", complete + `{"c":3}`, complete + `{"c":3}`, false},
+		{"half a line", complete + `{"c":"ha`, complete, true},
+		{"empty", "", "", false},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name+".jsonl")
+		if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		r, pending, err := OpenTranscriptSnapshot(path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got, _ := io.ReadAll(r)
+		r.Close()
+		if string(got) != tt.want || pending != tt.pending {
+			t.Errorf("%s: read %q, pending %v; want %q, %v", tt.name, got, pending, tt.want, tt.pending)
+		}
+		if PendingLine(path) != tt.pending {
+			t.Errorf("%s: PendingLine = %v", tt.name, !tt.pending)
+		}
+	}
+}
+
+func TestTailReaderHoldsPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	if err := os.WriteFile(path, []byte(`{"a":1}`+"\n"+`{"b":`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tr := NewTailReader(f)
+
+	if line, err := tr.ReadLine(); err != nil || strings.TrimSpace(string(line)) != `{"a":1}` {
+		t.Fatalf("first line = %q, %v", line, err)
+	}
+	if line, err := tr.ReadLine(); err != io.EOF {
+		t.Fatalf("partial line returned: %q, %v", line, err)
+	}
+
+	w, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteString("2}\n")
+	w.Close()
+	if line, err := tr.ReadLine(); err != nil || strings.TrimSpace(string(line)) != `{"b":2}` {
+		t.Errorf("completed line = %q, %v", line, err)
+	}
+}
@@ -0,0 +1,315 @@
+package transcript
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Subagent is a Claude Task sub-agent's sidechain transcript, linked to the
+// tool call that spawned it.
+type Subagent struct {
+	AgentID string
+	Path    string
+	// ToolCallID is the Task call that spawned the sub-agent, or "" when
+	// the parent transcript does not say.
+	ToolCallID string
+	Entries    []UnifiedEntry
+}
+
+// isTaskTool reports whether a Claude tool spawns a sub-agent. Newer Claude
+// versions call the Task tool Agent.
+func isTaskTool(name string) bool {
+	return name == "Task" || name == "Agent"
+}
+
+// FindClaudeSubagentFiles lists the sidechain transcripts of the Claude
+// session whose transcript is parentPath: agent-<id>.jsonl files in the
+// session's <session-id>/subagents/ directory, and, as older Claude versions
+// wrote them, next to the transcript with the session's id inside.
+func FindClaudeSubagentFiles(parentPath string) []string {
+	dir := filepath.Dir(parentPath)
+	sessionID := TrimTranscriptExt(filepath.Base(parentPath))
+
+	files, _ := GlobTranscripts(filepath.Join(dir, sessionID, "subagents", "agent-*.jsonl"))
+	siblings, _ := GlobTranscripts(filepath.Join(dir, "agent-*.jsonl"))
+	for _, path := range siblings {
+		if sidechainHeader(path).sessionID == sessionID {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// sidechainInfo is what links a sidechain transcript to its parent: read
+// from its first lines.
+type sidechainInfo struct {
+	sessionID  string
+	agentID    string
+	parentUUID string // of the root message
+	prompt     string // the first user message, the Task prompt
+}
+
+func sidechainHeader(path string) sidechainInfo {
+	var info sidechainInfo
+	f, err := OpenTranscript(path)
+	if err != nil {
+		return info
+	}
+	defer f.Close()
+	sc := NewLineScanner(f)
+	for n := 0; n < 20 && sc.Scan(); n++ {
+		var line struct {
+			Type       string          `json:"type"`
+			SessionID  string          `json:"sessionId"`
+			AgentID    string          `json:"agentId"`
+			ParentUUID *string         `json:"parentUuid"`
+			Message    json.RawMessage `json:"message"`
+		}
+		if json.Unmarshal(sc.Bytes(), &line) != nil {
+			continue
+		}
+		if info.sessionID == "" {
+			info.sessionID = line.SessionID
+		}
+		if info.agentID == "" {
+			info.agentID = line.AgentID
+		}
+		if line.Type != "user" || info.prompt != "" {
+			continue
+		}
+		if line.ParentUUID != nil {
+			info.parentUUID = *line.ParentUUID
+		}
+		var msg struct {
+			Content json.RawMessage `json:"content"`
+		}
+		if json.Unmarshal(line.Message, &msg) == nil {
+			info.prompt = strings.TrimSpace(messageText(msg.Content))
+		}
+		if info.agentID != "" {
+			break
+		}
+	}
+	return info
+}
+
+// messageText returns the text of a Claude message content: a string, or
+// the text blocks of a block array.
+func messageText(content json.RawMessage) string {
+	var s string
+	if json.Unmarshal(content, &s) == nil {
+		return s
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	json.Unmarshal(content, &blocks)
+	var texts []string
+	for _, b := range blocks {
+		if b.Type == "text" {
+			texts = append(texts, b.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// taskLinks indexes a parent transcript's Task calls three ways, strongest
+// first: by the sub-agent id recorded with the call's result, by the uuid
+// of the message that made the call, and by the call's prompt.
+type taskLinks struct {
+	byAgent  map[string]string
+	byUUID   map[string]string
+	byPrompt map[string]string
+}
+
+func readTaskLinks(parentPath string) taskLinks {
+	links := taskLinks{byAgent: map[string]string{}, byUUID: map[string]string{}, byPrompt: map[string]string{}}
+	f, err := OpenTranscript(parentPath)
+	if err != nil {
+		return links
+	}
+	defer f.Close()
+	sc := NewLineScanner(f)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if !strings.Contains(string(line), `"tool_use`) {
+			continue
+		}
+		var raw struct {
+			Type    string `json:"type"`
+			UUID    string `json:"uuid"`
+			Message struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"message"`
+			ToolUseResult json.RawMessage `json:"toolUseResult"`
+		}
+		if json.Unmarshal(line, &raw) != nil {
+			continue
+		}
+		var blocks []struct {
+			Type      string                 `json:"type"`
+			ID        string                 `json:"id"`
+			Name      string                 `json:"name"`
+			Input     map[string]interface{} `json:"input"`
+			ToolUseID string                 `json:"tool_use_id"`
+		}
+		if json.Unmarshal(raw.Message.Content, &blocks) != nil {
+			continue
+		}
+		for _, b := range blocks {
+			switch {
+			case b.Type == "tool_use" && isTaskTool(b.Name):
+				if raw.UUID != "" {
+					links.byUUID[raw.UUID] = b.ID
+				}
+				if prompt, _ := b.Input["prompt"].(string); prompt != "" {
+					links.byPrompt[strings.TrimSpace(prompt)] = b.ID
+				}
+			case b.Type == "tool_result" && len(raw.ToolUseResult) > 0:
+				var result struct {
+					AgentID string `json:"agentId"`
+				}
+				if json.Unmarshal(raw.ToolUseResult, &result) == nil && result.AgentID != "" {
+					links.byAgent[result.AgentID] = b.ToolUseID
+				}
+			}
+		}
+	}
+	return links
+}
+
+// ReadClaudeSubagents reads the sidechain transcripts of the Claude session
+// whose transcript is parentPath and links each to the Task call that
+// spawned it: by the agent id Claude records with the call's result, else
+// by the parentUuid of the sidechain's first message, else by its prompt.
+// Unreadable sidechain files are skipped.
+func ReadClaudeSubagents(parentPath string) []Subagent {
+	files := FindClaudeSubagentFiles(parentPath)
+	if len(files) == 0 {
+		return nil
+	}
+	links := readTaskLinks(parentPath)
+	var subagents []Subagent
+	for _, path := range files {
+		header := sidechainHeader(path)
+		agentID := header.agentID
+		if agentID == "" {
+			agentID = strings.TrimPrefix(TrimTranscriptExt(filepath.Base(path)), "agent-")
+		}
+		entries, err := readSidechain(path, agentID)
+		if err != nil || len(entries) == 0 {
+			continue
+		}
+		sub := Subagent{AgentID: agentID, Path: path, Entries: entries}
+		switch {
+		case links.byAgent[agentID] != "":
+			sub.ToolCallID = links.byAgent[agentID]
+		case links.byUUID[header.parentUUID] != "":
+			sub.ToolCallID = links.byUUID[header.parentUUID]
+		default:
+			sub.ToolCallID = links.byPrompt[header.prompt]
+		}
+		subagents = append(subagents, sub)
+	}
+	slices.SortFunc(subagents, func(a, b Subagent) int {
+		return a.Entries[0].Timestamp.Compare(b.Entries[0].Timestamp)
+	})
+	return subagents
+}
+
+func readSidechain(path, agentID string) ([]UnifiedEntry, error) {
+	f, err := OpenTranscript(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	normalizer := NewClaudeNormalizer()
+	sc := NewLineScanner(f)
+	var entries []UnifiedEntry
+	add := func(e *UnifiedEntry) {
+		e.AgentID = agentID
+		e.IsSidechain = true
+		entries = append(entries, *e)
+	}
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		if e, err := normalizer.NormalizeLine(sc.Bytes()); err == nil && e != nil {
+			add(e)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	for _, e := range normalizer.Flush() {
+		add(e)
+	}
+	return entries, nil
+}
+
+// NestSubagents splices each sub-agent's entries into entries right after
+// the entry holding the Task call that spawned it, one level deeper. A
+// sub-agent that could not be linked to a call goes where its first entry
+// falls in time, as long as that is within entries' time span, so reads of
+// one job's part of a transcript leave out other jobs' sub-agents.
+func NestSubagents(entries []UnifiedEntry, subagents []Subagent) []UnifiedEntry {
+	if len(subagents) == 0 || len(entries) == 0 {
+		return entries
+	}
+	after := make(map[int][]Subagent) // entry index -> sub-agents to insert after it
+	callEntry := make(map[string]int)
+	for i, e := range entries {
+		for _, part := range e.Parts {
+			if part.Type == "tool_call" {
+				if call, ok := part.Content.(UnifiedToolCall); ok && isTaskTool(call.Name) {
+					callEntry[call.ID] = i
+				}
+			}
+		}
+	}
+	first, last := entries[0].Timestamp, entries[len(entries)-1].Timestamp
+	for _, sub := range subagents {
+		if i, ok := callEntry[sub.ToolCallID]; ok && sub.ToolCallID != "" {
+			after[i] = append(after[i], sub)
+			continue
+		}
+		if sub.ToolCallID != "" {
+			continue // spawned outside this part of the transcript
+		}
+		start := sub.Entries[0].Timestamp
+		if start.Before(first) || start.After(last) {
+			continue
+		}
+		i := lastEntryBefore(entries, start)
+		after[i] = append(after[i], sub)
+	}
+
+	nested := make([]UnifiedEntry, 0, len(entries))
+	for i, e := range entries {
+		nested = append(nested, e)
+		for _, sub := range after[i] {
+			for _, se := range sub.Entries {
+				se.Depth = e.Depth + 1
+				nested = append(nested, se)
+			}
+		}
+	}
+	return nested
+}
+
+// lastEntryBefore returns the index of the last entry not after t.
+func lastEntryBefore(entries []UnifiedEntry, t time.Time) int {
+	idx := 0
+	for i, e := range entries {
+		if e.Timestamp.After(t) {
+			break
+		}
+		idx = i
+	}
+	return idx
+}
@@ -0,0 +1,87 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNestSubagents(t *testing.T) {
+	dir := t.TempDir()
+	parent := filepath.Join(dir, "sess-1.jsonl")
+	writeLines(t, parent,
+		`{"type":"user","uuid":"u1","sessionId":"sess-1","timestamp":"2026-01-01T00:00:00Z","message":{"role":"user","content":"review the code"}}`,
+		`{"type":"assistant","uuid":"a1","sessionId":"sess-1","timestamp":"2026-01-01T00:00:01Z","message":{"id":"m1","role":"assistant","content":[{"type":"tool_use","id":"task-1","name":"Task","input":{"description":"review","prompt":"Review main.go"}}]}}`,
+		`{"type":"assistant","uuid":"a2","sessionId":"sess-1","timestamp":"2026-01-01T00:00:02Z","message":{"id":"m2","role":"assistant","content":[{"type":"tool_use","id":"task-2","name":"Task","input":{"description":"tests","prompt":"Run the tests"}}]}}`,
+		`{"type":"user","uuid":"u2","sessionId":"sess-1","timestamp":"2026-01-01T00:00:10Z","toolUseResult":{"agentId":"bbb"},"message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"task-2","content":"tests pass"}]}}`,
+		`{"type":"user","uuid":"u3","sessionId":"sess-1","timestamp":"2026-01-01T00:00:11Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"task-1","content":"looks good"}]}}`,
+		`{"type":"assistant","uuid":"a3","sessionId":"sess-1","timestamp":"2026-01-01T00:00:12Z","message":{"id":"m3","role":"assistant","content":[{"type":"text","text":"All done."}]}}`,
+	)
+	// Linked by prompt, in the older layout next to the transcript.
+	writeLines(t, filepath.Join(dir, "agent-aaa.jsonl"),
+		`{"type":"user","isSidechain":true,"agentId":"aaa","sessionId":"sess-1","parentUuid":null,"timestamp":"2026-01-01T00:00:03Z","message":{"role":"user","content":"Review main.go"}}`,
+		`{"type":"assistant","isSidechain":true,"agentId":"aaa","sessionId":"sess-1","timestamp":"2026-01-01T00:00:04Z","message":{"id":"s1","role":"assistant","content":[{"type":"text","text":"main.go looks good"}]}}`,
+	)
+	// Another session's sub-agent is left out.
+	writeLines(t, filepath.Join(dir, "agent-ccc.jsonl"),
+		`{"type":"user","isSidechain":true,"agentId":"ccc","sessionId":"sess-2","timestamp":"2026-01-01T00:00:03Z","message":{"role":"user","content":"other"}}`,
+	)
+	// Linked by the agent id recorded with the Task result.
+	writeLines(t, filepath.Join(dir, "sess-1", "subagents", "agent-bbb.jsonl"),
+		`{"type":"user","isSidechain":true,"agentId":"bbb","sessionId":"sess-1","timestamp":"2026-01-01T00:00:05Z","message":{"role":"user","content":"Run the tests, please"}}`,
+		`{"type":"assistant","isSidechain":true,"agentId":"bbb","sessionId":"sess-1","timestamp":"2026-01-01T00:00:06Z","message":{"id":"s2","role":"assistant","content":[{"type":"text","text":"tests pass"}]}}`,
+	)
+
+	subagents := ReadClaudeSubagents(parent)
+	if len(subagents) != 2 {
+		t.Fatalf("got %d sub-agents, want 2: %+v", len(subagents), subagents)
+	}
+	if subagents[0].AgentID != "aaa" || subagents[0].ToolCallID != "task-1" ||
+		subagents[1].AgentID != "bbb" || subagents[1].ToolCallID != "task-2" {
+		t.Errorf("links = %s->%s, %s->%s", subagents[0].AgentID, subagents[0].ToolCallID, subagents[1].AgentID, subagents[1].ToolCallID)
+	}
+
+	n := NewClaudeNormalizer()
+	var entries []UnifiedEntry
+	data, _ := os.ReadFile(parent)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if e, _ := n.NormalizeLine([]byte(line)); e != nil {
+			entries = append(entries, *e)
+		}
+	}
+	for _, e := range n.Flush() {
+		entries = append(entries, *e)
+	}
+
+	nested := NestSubagents(entries, subagents)
+	if len(nested) != len(entries)+4 {
+		t.Fatalf("nested %d entries, want %d", len(nested), len(entries)+4)
+	}
+	// Each sub-agent follows the entry holding the call that spawned it.
+	spawnedBy := map[string]string{"aaa": "task-1", "bbb": "task-2"}
+	for i, e := range nested {
+		if e.Depth == 0 || nested[i-1].Depth > 0 {
+			continue
+		}
+		call, _ := nested[i-1].Parts[0].Content.(UnifiedToolCall)
+		if call.ID != spawnedBy[e.AgentID] || e.Role != "user" || !e.IsSidechain {
+			t.Errorf("sub-agent %s nested after %q", e.AgentID, call.ID)
+		}
+	}
+
+	// Sub-agents of calls outside the entries are left out.
+	if nested := NestSubagents(entries[:1], subagents); len(nested) != 1 {
+		t.Errorf("job-scoped nest kept %d entries, want 1", len(nested))
+	}
+}
@@ -0,0 +1,289 @@
+package transcript
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Summarizer backends, chosen by SummaryConfig.Backend.
+const (
+	BackendCommand   = "command"   // an LLM CLI reading the prompt on stdin
+	BackendAnthropic = "anthropic" // the Anthropic Messages API
+	BackendOpenAI    = "openai"    // the OpenAI Chat Completions API, or a compatible endpoint
+	BackendOllama    = "ollama"    // a local ollama server
+)
+
+// Summarizer answers a summarization prompt with an LLM.
+type Summarizer interface {
+	Summarize(ctx context.Context, prompt string) (string, error)
+}
+
+// summarizerTimeout bounds one request to an HTTP backend.
+const summarizerTimeout = 2 * time.Minute
+
+// NewSummarizer returns the summarizer config selects. The HTTP backends
+// read their API key from the environment variable APIKeyEnv names
+// (ANTHROPIC_API_KEY or OPENAI_API_KEY by default) and fill in a default
+// model and endpoint when none is configured.
+func NewSummarizer(config SummaryConfig) (Summarizer, error) {
+	switch config.Backend {
+	case "", BackendCommand:
+		if len(strings.Fields(config.LLMCommand)) == 0 {
+			return nil, fmt.Errorf("invalid LLM command %q", config.LLMCommand)
+		}
+		return &CommandSummarizer{Command: config.LLMCommand}, nil
+	case BackendAnthropic:
+		key, err := apiKey(config.APIKeyEnv, "ANTHROPIC_API_KEY")
+		if err != nil {
+			return nil, err
+		}
+		return &AnthropicSummarizer{
+			APIKey:    key,
+			Model:     orDefault(config.Model, "claude-3-5-haiku-latest"),
+			Endpoint:  orDefault(config.Endpoint, "https://api.anthropic.com"),
+			MaxTokens: config.MaxOutputTokens,
+		}, nil
+	case BackendOpenAI:
+		key, err := apiKey(config.APIKeyEnv, "OPENAI_API_KEY")
+		if err != nil {
+			return nil, err
+		}
+		return &OpenAISummarizer{
+			APIKey:    key,
+			Model:     orDefault(config.Model, "gpt-4o-mini"),
+			Endpoint:  orDefault(config.Endpoint, "https://api.openai.com"),
+			MaxTokens: config.MaxOutputTokens,
+		}, nil
+	case BackendOllama:
+		return &OllamaSummarizer{
+			Model:    orDefault(config.Model, "llama3.2"),
+			Endpoint: orDefault(config.Endpoint, "http://localhost:11434"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown summarizer backend %q (want %s, %s, %s or %s)",
+			config.Backend, BackendCommand, BackendAnthropic, BackendOpenAI, BackendOllama)
+	}
+}
+
+func apiKey(env, fallback string) (string, error) {
+	env = orDefault(env, fallback)
+	key := os.Getenv(env)
+	if key == "" {
+		return "", fmt.Errorf("summarizer API key not found: set %s", env)
+	}
+	return key, nil
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// CommandSummarizer runs an LLM CLI, like 'llm -m gpt-4o-mini', with the
+// prompt on stdin.
+type CommandSummarizer struct {
+	Command string
+}
+
+func (s *CommandSummarizer) Summarize(ctx context.Context, prompt string) (string, error) {
+	cmdParts := strings.Fields(s.Command)
+	if len(cmdParts) == 0 {
+		return "", fmt.Errorf("invalid LLM command")
+	}
+
+	cmd := exec.CommandContext(ctx, cmdParts[0], cmdParts[1:]...) //nolint:gosec // command comes from user config, not untrusted input
+	cmd.Stdin = strings.NewReader(prompt)
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("LLM command failed: %v, stderr: %s", err, strings.TrimSpace(errOut.String()))
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+func (s *CommandSummarizer) String() string { return BackendCommand + ": " + s.Command }
+
+// AnthropicSummarizer calls the Anthropic Messages API.
+type AnthropicSummarizer struct {
+	APIKey   string
+	Model    string
+	Endpoint string // API base URL, without /v1
+	// MaxTokens caps the answer; 0 means 1024.
+	MaxTokens int
+	Client    *http.Client
+}
+
+func (s *AnthropicSummarizer) Summarize(ctx context.Context, prompt string) (string, error) {
+	maxTokens := s.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 1024
+	}
+	req := map[string]any{
+		"model":      s.Model,
+		"max_tokens": maxTokens,
+		"messages":   []map[string]string{{"role": "user", "content": prompt}},
+	}
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	headers := map[string]string{"x-api-key": s.APIKey, "anthropic-version": "2023-06-01"}
+	if err := postJSON(ctx, s.Client, s.Endpoint+"/v1/messages", headers, req, &resp); err != nil {
+		return "", fmt.Errorf("anthropic: %w", err)
+	}
+	var text strings.Builder
+	for _, c := range resp.Content {
+		if c.Type == "text" {
+			text.WriteString(c.Text)
+		}
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+func (s *AnthropicSummarizer) String() string { return BackendAnthropic + ": " + s.Model }
+
+// OpenAISummarizer calls the OpenAI Chat Completions API, or an endpoint
+// compatible with it.
+type OpenAISummarizer struct {
+	APIKey   string
+	Model    string
+	Endpoint string // API base URL, without /v1
+	// MaxTokens caps the answer; 0 leaves it to the API.
+	MaxTokens int
+	Client    *http.Client
+}
+
+func (s *OpenAISummarizer) Summarize(ctx context.Context, prompt string) (string, error) {
+	req := map[string]any{
+		"model":    s.Model,
+		"messages": []map[string]string{{"role": "user", "content": prompt}},
+	}
+	if s.MaxTokens > 0 {
+		req["max_tokens"] = s.MaxTokens
+	}
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	headers := map[string]string{"Authorization": "Bearer " + s.APIKey}
+	if err := postJSON(ctx, s.Client, s.Endpoint+"/v1/chat/completions", headers, req, &resp); err != nil {
+		return "", fmt.Errorf("openai: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("openai: response has no choices")
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+func (s *OpenAISummarizer) String() string { return BackendOpenAI + ": " + s.Model }
+
+// OllamaSummarizer calls a local ollama server.
+type OllamaSummarizer struct {
+	Model    string
+	Endpoint string
+	Client   *http.Client
+}
+
+func (s *OllamaSummarizer) Summarize(ctx context.Context, prompt string) (string, error) {
+	req := map[string]any{"model": s.Model, "prompt": prompt, "stream": false}
+	var resp struct {
+		Response string `json:"response"`
+	}
+	if err := postJSON(ctx, s.Client, s.Endpoint+"/api/generate", nil, req, &resp); err != nil {
+		return "", fmt.Errorf("ollama: %w", err)
+	}
+	return strings.TrimSpace(resp.Response), nil
+}
+
+func (s *OllamaSummarizer) String() string { return BackendOllama + ": " + s.Model }
+
+// failedSummarizer stands in for a summarizer the config doesn't describe
+// properly, reporting why on every call.
+type failedSummarizer struct {
+	err error
+}
+
+func (s failedSummarizer) Summarize(context.Context, string) (string, error) { return "", s.err }
+
+// postJSON posts body as JSON to url and decodes the answer into out. A
+// non-2xx answer becomes an error carrying the API's message when it has
+// one.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: summarizerTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: %s", resp.Status, apiErrorMessage(respBody))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+// apiErrorMessage extracts the message of an API error response:
+// {"error": {"message": ...}} (Anthropic, OpenAI) or {"error": "..."}
+// (ollama), else the body itself.
+func apiErrorMessage(body []byte) string {
+	var e struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(body, &e) == nil && len(e.Error) > 0 {
+		var msg string
+		if json.Unmarshal(e.Error, &msg) == nil {
+			return msg
+		}
+		var obj struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(e.Error, &obj) == nil && obj.Message != "" {
+			return obj.Message
+		}
+	}
+	s := strings.TrimSpace(string(body))
+	if len(s) > 300 {
+		s = s[:300] + "..."
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewSummarizer(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("TEAM_OPENAI_KEY", "sk-team")
+
+	if s, err := NewSummarizer(SummaryConfig{LLMCommand: "llm -m gpt-4o-mini"}); err != nil || s.(*CommandSummarizer).Command != "llm -m gpt-4o-mini" {
+		t.Errorf("default backend = %v, %v; want the command", s, err)
+	}
+	if _, err := NewSummarizer(SummaryConfig{Backend: BackendAnthropic}); err == nil || !strings.Contains(err.Error(), "ANTHROPIC_API_KEY") {
+		t.Errorf("anthropic without a key: err = %v", err)
+	}
+	s, err := NewSummarizer(SummaryConfig{Backend: BackendOpenAI, APIKeyEnv: "TEAM_OPENAI_KEY", Endpoint: "http://proxy:8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := s.(*OpenAISummarizer); o.APIKey != "sk-team" || o.Model != "gpt-4o-mini" || o.Endpoint != "http://proxy:8080" {
+		t.Errorf("openai summarizer = %+v", o)
+	}
+	if _, err := NewSummarizer(SummaryConfig{Backend: "bard"}); err == nil {
+		t.Error("unknown backend accepted")
+	}
+}
+
+func TestHTTPSummarizers(t *testing.T) {
+	var got struct {
+		path, auth string
+		body       map[string]any
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path = r.URL.Path
+		got.auth = r.Header.Get("x-api-key") + r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got.body)
+		switch r.URL.Path {
+		case "/v1/messages":
+			_, _ = w.Write([]byte(`{"content":[{"type":"text","text":" from claude "}]}`))
+		case "/v1/chat/completions":
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"from gpt"}}]}`))
+		case "/api/generate":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"model \"llama3.2\" not found"}`))
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	out, err := (&AnthropicSummarizer{APIKey: "ak", Model: "m", Endpoint: srv.URL}).Summarize(ctx, "hi")
+	if err != nil || out != "from claude" {
+		t.Errorf("anthropic = %q, %v", out, err)
+	}
+	if got.auth != "ak" || got.body["max_tokens"] != float64(1024) {
+		t.Errorf("anthropic request: auth %q, body %v", got.auth, got.body)
+	}
+
+	out, err = (&OpenAISummarizer{APIKey: "ok", Model: "m", Endpoint: srv.URL}).Summarize(ctx, "hi")
+	if err != nil || out != "from gpt" || got.auth != "Bearer ok" {
+		t.Errorf("openai = %q, %v (auth %q)", out, err, got.auth)
+	}
+
+	_, err = (&OllamaSummarizer{Model: "llama3.2", Endpoint: srv.URL}).Summarize(ctx, "hi")
+	if err == nil || !strings.Contains(err.Error(), `model "llama3.2" not found`) {
+		t.Errorf("ollama error = %v, want the API's message", err)
+	}
+	if got.body["stream"] != false || got.body["prompt"] != "hi" {
+		t.Errorf("ollama request body = %v", got.body)
+	}
+}
@@ -1,13 +1,12 @@
 package transcript
 
 import (
-	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +19,7 @@ import (
 type SummaryManager struct {
 	db               *sql.DB
 	config           SummaryConfig
+	summarizer       Summarizer
 	lastSummaryAt    map[string]int // sessionID -> message count at last summary
 	lastSummaryMutex sync.RWMutex
 }
@@ -33,6 +33,15 @@ type SummaryConfig struct {
 	RecentWindow     int    `yaml:"recent_window"`   // Messages for recent context
 	MaxInputTokens   int    `yaml:"max_input_tokens"`
 	MilestoneEnabled bool   `yaml:"milestone_detection"`
+
+	// Backend selects the Summarizer: "command" (the default) runs
+	// LLMCommand; "anthropic", "openai" and "ollama" call those APIs
+	// directly with Model at Endpoint, each defaulting per backend.
+	Backend         string `yaml:"backend"`
+	Model           string `yaml:"model"`
+	Endpoint        string `yaml:"endpoint"`
+	APIKeyEnv       string `yaml:"api_key_env"`       // environment variable holding the API key
+	MaxOutputTokens int    `yaml:"max_output_tokens"` // 0 = backend default
 }
 
 // SessionSummary represents the AI-generated summary
@@ -46,25 +55,33 @@ type SessionSummary struct {
 
 // NewSummaryManager creates a new summary manager
 func NewSummaryManager(db *sql.DB) *SummaryManager {
-	return &SummaryManager{
-		db:            db,
-		config:        loadSummaryConfig(),
-		lastSummaryAt: make(map[string]int),
-	}
+	return NewSummaryManagerWithConfig(db, loadSummaryConfig())
 }
 
 // NewSummaryManagerWithConfig creates a new summary manager with provided config
 func NewSummaryManagerWithConfig(db *sql.DB, config SummaryConfig) *SummaryManager {
+	summarizer, err := NewSummarizer(config)
+	if err != nil {
+		summarizer = failedSummarizer{err: err}
+	}
+	return NewSummaryManagerWithSummarizer(db, config, summarizer)
+}
+
+// NewSummaryManagerWithSummarizer creates a summary manager that asks
+// summarizer rather than the backend config selects.
+func NewSummaryManagerWithSummarizer(db *sql.DB, config SummaryConfig, summarizer Summarizer) *SummaryManager {
 	return &SummaryManager{
 		db:            db,
 		config:        config,
+		summarizer:    summarizer,
 		lastSummaryAt: make(map[string]int),
 	}
 }
 
-// loadSummaryConfig loads configuration from the config file
-func loadSummaryConfig() SummaryConfig {
-	defaultConfig := SummaryConfig{
+// defaultSummaryConfig is the configuration used when the config file
+// has no conversation_summarization section.
+func defaultSummaryConfig() SummaryConfig {
+	return SummaryConfig{
 		Enabled:          false,
 		LLMCommand:       "llm -m gpt-4o-mini",
 		UpdateInterval:   10,
@@ -73,6 +90,11 @@ func loadSummaryConfig() SummaryConfig {
 		MaxInputTokens:   8000,
 		MilestoneEnabled: true,
 	}
+}
+
+// loadSummaryConfig loads configuration from the config file
+func loadSummaryConfig() SummaryConfig {
+	defaultConfig := defaultSummaryConfig()
 
 	// Try to load from config file
 	configPath := expandPath("~/.config/tmux-claude-hud/config.yaml")
@@ -96,6 +118,23 @@ func loadSummaryConfig() SummaryConfig {
 	return defaultConfig
 }
 
+// LoadSummaryConfig returns the conversation_summarization settings for
+// summaries made on demand, which don't need the monitor's summaries to be
+// enabled: the configured values over the defaults.
+func LoadSummaryConfig() SummaryConfig {
+	config := struct {
+		ConversationSummarization SummaryConfig `yaml:"conversation_summarization"`
+	}{defaultSummaryConfig()}
+	data, err := os.ReadFile(expandPath("~/.config/tmux-claude-hud/config.yaml"))
+	if err != nil {
+		return config.ConversationSummarization
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return defaultSummaryConfig()
+	}
+	return config.ConversationSummarization
+}
+
 // expandPath expands ~ to home directory
 func expandPath(path string) string {
 	if strings.HasPrefix(path, "~/") {
@@ -120,8 +159,10 @@ func (sm *SummaryManager) ShouldUpdateSummary(sessionID string, currentMessageCo
 	return currentMessageCount-lastCount >= sm.config.UpdateInterval
 }
 
-// UpdateSessionSummary generates and updates the summary for a session
-func (sm *SummaryManager) UpdateSessionSummary(sessionID string) error {
+// UpdateSessionSummary generates and updates the summary for a session. The
+// summary is also published to SummaryDir under sessionID and any aliases
+// (such as the agent's own session ID for a flow job).
+func (sm *SummaryManager) UpdateSessionSummary(sessionID string, aliases ...string) error {
 	if !sm.config.Enabled {
 		return nil
 	}
@@ -146,6 +187,11 @@ func (sm *SummaryManager) UpdateSessionSummary(sessionID string) error {
 	if err := sm.storeSummary(sessionID, summary); err != nil {
 		return fmt.Errorf("failed to store summary: %w", err)
 	}
+	for _, id := range append([]string{sessionID}, aliases...) {
+		if err := SaveSummary(SummaryDir(), id, summary); err != nil {
+			log.Printf("Failed to publish summary for session %s: %v", id, err)
+		}
+	}
 
 	// Update last summary count
 	sm.lastSummaryMutex.Lock()
@@ -292,26 +338,9 @@ func (sm *SummaryManager) formatMessagesForLLM(messages []ExtractedMessage) stri
 	return buffer.String()
 }
 
-// callLLM executes the LLM command with the given prompt
+// callLLM asks the summarizer to answer the prompt
 func (sm *SummaryManager) callLLM(prompt string) (string, error) {
-	cmdParts := strings.Fields(sm.config.LLMCommand)
-	if len(cmdParts) == 0 {
-		return "", fmt.Errorf("invalid LLM command")
-	}
-
-	cmd := exec.Command(cmdParts[0], cmdParts[1:]...) //nolint:gosec // command comes from user config, not untrusted input
-	cmd.Stdin = strings.NewReader(prompt)
-
-	var out bytes.Buffer
-	var errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("LLM command failed: %v, stderr: %s", err, errOut.String())
-	}
-
-	return strings.TrimSpace(out.String()), nil
+	return sm.summarizer.Summarize(context.Background(), prompt)
 }
 
 // getExistingSummary retrieves the current summary from the database
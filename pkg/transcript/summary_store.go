@@ -0,0 +1,110 @@
+package transcript
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/grovetools/core/pkg/paths"
+)
+
+// SummaryDir is where summaries are published as <session-id>.json, for
+// readers without access to the monitor's database such as `aglogs list`.
+func SummaryDir() string {
+	return filepath.Join(paths.StateDir(), "aglogs", "summaries")
+}
+
+// SaveSummary publishes summary under sessionID in dir.
+func SaveSummary(dir, sessionID string, summary *SessionSummary) error {
+	path, err := summaryPath(dir, sessionID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create summary directory: %w", err)
+	}
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write to a temp file and rename so readers never see a partial summary.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadSummary reads the summary published for sessionID in dir, or returns
+// nil when there is none.
+func LoadSummary(dir, sessionID string) (*SessionSummary, error) {
+	path, err := summaryPath(dir, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var summary SessionSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("invalid summary %s: %w", path, err)
+	}
+	return &summary, nil
+}
+
+// LoadSummaries reads every summary in dir, keyed by session ID. A missing
+// directory yields none; unreadable files are skipped.
+func LoadSummaries(dir string) (map[string]*SessionSummary, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]*SessionSummary{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	summaries := make(map[string]*SessionSummary, len(files))
+	for _, f := range files {
+		id, ok := strings.CutSuffix(f.Name(), ".json")
+		if !ok || f.IsDir() {
+			continue
+		}
+		if summary, err := LoadSummary(dir, id); err == nil && summary != nil {
+			summaries[id] = summary
+		}
+	}
+	return summaries, nil
+}
+
+func summaryPath(dir, sessionID string) (string, error) {
+	if sessionID == "" || sessionID == "." || sessionID == ".." || strings.ContainsAny(sessionID, `/\`) {
+		return "", fmt.Errorf("invalid session ID %q", sessionID)
+	}
+	return filepath.Join(dir, sessionID+".json"), nil
+}
+
+var summaryTagRe = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+
+// LatestActivity returns the summary's current-activity line as plain text:
+// the monitor's bullet and <strong> markup are removed. It falls back to the
+// newest history entry and is empty when the summary has neither.
+func (s *SessionSummary) LatestActivity() string {
+	if s == nil {
+		return ""
+	}
+	text := s.CurrentActivity
+	if strings.TrimSpace(text) == "" && len(s.History) > 0 {
+		text = s.History[len(s.History)-1].Summary
+	}
+	text = html.UnescapeString(summaryTagRe.ReplaceAllString(text, ""))
+	text = strings.TrimPrefix(strings.TrimSpace(text), "•")
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,45 @@
+package transcript
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grovetools/core/pkg/models"
+)
+
+func TestSummaryStore(t *testing.T) {
+	dir := t.TempDir()
+	if s, err := LoadSummary(dir, "missing"); err != nil || s != nil {
+		t.Fatalf("LoadSummary(missing) = %v, %v; want nil, nil", s, err)
+	}
+	summary := &SessionSummary{
+		CurrentActivity: "• Refactoring the <strong>auth middleware</strong> &amp; tests.",
+		LastUpdated:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := SaveSummary(dir, "s1", summary); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveSummary(dir, "s2", &SessionSummary{History: []models.Milestone{{Summary: "old"}, {Summary: "• Writing docs"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveSummary(dir, "../escape", summary); err == nil {
+		t.Error("SaveSummary accepted a path-like session ID")
+	}
+
+	all, err := LoadSummaries(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("LoadSummaries = %d summaries, want 2", len(all))
+	}
+	if got, want := all["s1"].LatestActivity(), "Refactoring the auth middleware & tests."; got != want {
+		t.Errorf("s1 activity = %q, want %q", got, want)
+	}
+	if got, want := all["s2"].LatestActivity(), "Writing docs"; got != want {
+		t.Errorf("s2 activity = %q, want %q", got, want)
+	}
+	if got := all["none"].LatestActivity(); got != "" {
+		t.Errorf("nil summary activity = %q", got)
+	}
+}
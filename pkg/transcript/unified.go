@@ -7,7 +7,7 @@ import (
 
 // UnifiedEntry represents a single transcript entry normalized across all providers.
 type UnifiedEntry struct {
-	Role        string         `json:"role"` // "user" or "assistant"
+	Role        string         `json:"role"` // "user", "assistant", or "system" (hooks)
 	Timestamp   time.Time      `json:"timestamp"`
 	MessageID   string         `json:"messageID"`
 	Parts       []UnifiedPart  `json:"parts"`
@@ -16,11 +16,18 @@ type UnifiedEntry struct {
 	AgentID     string         `json:"agentID,omitempty"`     // Subagent ID for sidechain/workflow transcripts
 	IsSidechain bool           `json:"isSidechain,omitempty"` // True for subagent (sidechain) entries
 	PromptID    string         `json:"promptID,omitempty"`    // Prompt ID linking sidechain entries to their spawning prompt
+	// Depth is how deeply the entry is nested under the tool calls that
+	// spawned sub-agents (see NestSubagents); 0 for the main transcript.
+	Depth int `json:"depth,omitempty"`
+	// StopReason is why the model stopped generating an assistant message,
+	// as the provider records it ("end_turn", "tool_use", "stop"); "" when
+	// it isn't recorded.
+	StopReason string `json:"stopReason,omitempty"`
 }
 
 // UnifiedPart represents a component of a message.
 type UnifiedPart struct {
-	Type    string      `json:"type"` // "text", "tool_call", "tool_result", "reasoning"
+	Type    string      `json:"type"` // "text", "image", "tool_call", "tool_result", "reasoning", "command", "command_output", "hook", "plan_mode", "summary"
 	Content interface{} `json:"content"`
 }
 
@@ -38,6 +45,16 @@ type UnifiedToolCall struct {
 	Output string                 `json:"output,omitempty"`
 	Title  string                 `json:"title,omitempty"`
 	Diff   string                 `json:"diff,omitempty"`
+	// DurationMs is the time between the call and its result, set by
+	// normalizers that merge results into the call (Claude) or whose logs
+	// record it (OpenCode). 0 = unknown.
+	DurationMs int64 `json:"durationMs,omitempty"`
+	// ExitCode is a shell command's exit status when the log records it
+	// (OpenCode bash). Nil = unknown.
+	ExitCode *int `json:"exitCode,omitempty"`
+	// Images are the images the tool returned alongside Output (Claude
+	// screenshots, image file reads).
+	Images []UnifiedImage `json:"images,omitempty"`
 }
 
 // UnifiedToolResult holds tool execution results.
@@ -45,6 +62,12 @@ type UnifiedToolResult struct {
 	ToolCallID string `json:"toolCallID"`
 	Output     string `json:"output"`
 	IsError    bool   `json:"isError,omitempty"`
+	// ExitCode and DurationMs are a shell command's exit status and run
+	// time when the log records them (Codex). Nil / 0 = unknown.
+	ExitCode   *int  `json:"exitCode,omitempty"`
+	DurationMs int64 `json:"durationMs,omitempty"`
+	// Images are the images in the result, besides its text Output.
+	Images []UnifiedImage `json:"images,omitempty"`
 }
 
 // UnifiedReasoning holds reasoning/thinking content (Codex agent_reasoning).
@@ -52,6 +75,52 @@ type UnifiedReasoning struct {
 	Text string `json:"text"`
 }
 
+// UnifiedCommand is a slash command the user ran in the agent CLI
+// (Claude "/model", "/compact", custom commands).
+type UnifiedCommand struct {
+	Name string `json:"name"` // including the leading slash
+	Args string `json:"args,omitempty"`
+}
+
+// UnifiedCommandOutput is the output of a command the CLI ran locally,
+// without involving the model (Claude <local-command-stdout>).
+type UnifiedCommandOutput struct {
+	Output  string `json:"output"`
+	IsError bool   `json:"isError,omitempty"`
+}
+
+// UnifiedHook is a hook execution recorded in the transcript.
+type UnifiedHook struct {
+	Event   string `json:"event"` // e.g. "PostToolUse:Edit", "Stop"
+	Command string `json:"command,omitempty"`
+	Output  string `json:"output,omitempty"`
+	IsError bool   `json:"isError,omitempty"`
+}
+
+// UnifiedPlanMode marks the session entering (Active) or leaving plan mode
+// outside of a plan-mode tool call, e.g. when the user toggles the mode.
+type UnifiedPlanMode struct {
+	Active bool `json:"active"`
+}
+
+// Summary kinds.
+const (
+	SummaryTitle      = "title"      // the conversation title the agent generated
+	SummaryCompaction = "compaction" // the context was compacted
+	SummaryResume     = "resume"     // a resumed session went on in a new transcript file
+)
+
+// UnifiedSummary marks a section boundary: a conversation title (Claude
+// "summary" lines) or a compaction of the context, with the summary that
+// replaced it when the transcript records one.
+type UnifiedSummary struct {
+	Kind    string `json:"kind"`
+	Text    string `json:"text,omitempty"`
+	Trigger string `json:"trigger,omitempty"` // compaction: "auto" or "manual"
+	// PreTokens is the context size before compaction, when recorded.
+	PreTokens int `json:"preTokens,omitempty"`
+}
+
 // UnifiedTokens captures token usage across providers.
 type UnifiedTokens struct {
 	Input      int `json:"input,omitempty"`
@@ -388,19 +388,10 @@ func ScanProjects(slugDirs []string, mode CostMode, since time.Time) (ScanResult
 // ScanProjects so the Claude scan path is unchanged.
 func collectClaudeEntries(slugDirs []string) ([]loadedEntry, error) {
 	if len(slugDirs) == 0 {
-		root, err := claudeProjectsDir()
-		if err != nil {
-			return nil, err
-		}
-		entries, err := os.ReadDir(root)
-		if err != nil {
+		var err error
+		if slugDirs, err = claudeSlugDirs(); err != nil {
 			return nil, err
 		}
-		for _, e := range entries {
-			if e.IsDir() {
-				slugDirs = append(slugDirs, filepath.Join(root, e.Name()))
-			}
-		}
 	}
 
 	var all []loadedEntry
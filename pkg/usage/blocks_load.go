@@ -117,19 +117,10 @@ func ProjectBlocks(slugDirs []string, mode CostMode, duration time.Duration) ([]
 	pm := DefaultPricing()
 
 	if len(slugDirs) == 0 {
-		root, err := claudeProjectsDir()
-		if err != nil {
-			return nil, err
-		}
-		dirEntries, err := os.ReadDir(root)
-		if err != nil {
+		var err error
+		if slugDirs, err = claudeSlugDirs(); err != nil {
 			return nil, err
 		}
-		for _, e := range dirEntries {
-			if e.IsDir() {
-				slugDirs = append(slugDirs, filepath.Join(root, e.Name()))
-			}
-		}
 	}
 
 	var paths []string
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/grovetools/agentlogs/internal/session"
 )
 
 // discoveredFile is a transcript file paired with the session/project it rolls
@@ -68,12 +70,13 @@ func extractSessionParts(path string) (string, string) {
 	return sessionID, project
 }
 
-// claudeProjectsDir returns the Claude projects directory. It honors
+// claudeProjectsDirs returns the Claude projects directories. It honors
 // CLAUDE_CONFIG_DIR (pointing either at a config dir that contains projects/, or
 // directly at the projects/ dir) the same way ccusage does, falling back to
-// ~/.claude/projects. The override lets the acceptance-gate script point both
-// tools at a frozen snapshot of the live directory.
-func claudeProjectsDir() (string, error) {
+// the Claude roots of the aglogs sources config (~/.claude/projects unless
+// configured otherwise). The override lets the acceptance-gate script point
+// both tools at a frozen snapshot of the live directory.
+func claudeProjectsDirs() ([]string, error) {
 	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
 		// ccusage allows a path-list; only the first entry is needed here.
 		if i := strings.IndexByte(dir, os.PathListSeparator); i >= 0 {
@@ -81,16 +84,81 @@ func claudeProjectsDir() (string, error) {
 		}
 		candidate := filepath.Join(dir, "projects")
 		if fi, err := os.Stat(candidate); err == nil && fi.IsDir() {
-			return candidate, nil
+			return []string{candidate}, nil
 		}
 		// CLAUDE_CONFIG_DIR may itself be the projects/ directory.
-		return dir, nil
+		return []string{dir}, nil
 	}
+	return sourceRoots("claude")
+}
+
+// sourceRoots returns the transcript roots of provider in the aglogs
+// sources config.
+func sourceRoots(provider string) ([]string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	var roots []string
+	for _, src := range session.Sources(home) {
+		if src.Provider == provider {
+			roots = append(roots, src.Path)
+		}
+	}
+	return roots, nil
+}
+
+// sourceTranscripts globs the transcript files under every root of
+// provider in the sources config.
+func sourceTranscripts(provider string) ([]string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, src := range session.Sources(home) {
+		if src.Provider != provider {
+			continue
+		}
+		m, err := filepath.Glob(src.TranscriptGlob())
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, m...)
+	}
+	return matches, nil
+}
+
+// claudeSlugDirs lists the project-slug directories under every Claude
+// projects directory. A missing directory is skipped unless none can be
+// read.
+func claudeSlugDirs() ([]string, error) {
+	roots, err := claudeProjectsDirs()
+	if err != nil {
+		return nil, err
+	}
+	var slugDirs []string
+	var firstErr error
+	read := 0
+	for _, root := range roots {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		read++
+		for _, e := range entries {
+			if e.IsDir() {
+				slugDirs = append(slugDirs, filepath.Join(root, e.Name()))
+			}
+		}
+	}
+	if read == 0 && firstErr != nil {
+		return nil, firstErr
 	}
-	return filepath.Join(home, ".claude", "projects"), nil
+	return slugDirs, nil
 }
 
 // discoverSessionFiles collects every transcript file that belongs to the given
@@ -105,19 +173,10 @@ func claudeProjectsDir() (string, error) {
 // is scanned.
 func discoverSessionFiles(slugDirs []string, sessionID string) ([]discoveredFile, error) {
 	if len(slugDirs) == 0 {
-		root, err := claudeProjectsDir()
-		if err != nil {
-			return nil, err
-		}
-		entries, err := os.ReadDir(root)
-		if err != nil {
+		var err error
+		if slugDirs, err = claudeSlugDirs(); err != nil {
 			return nil, err
 		}
-		for _, e := range entries {
-			if e.IsDir() {
-				slugDirs = append(slugDirs, filepath.Join(root, e.Name()))
-			}
-		}
 	}
 
 	var files []discoveredFile
@@ -1,19 +1,13 @@
 package usage
 
 import (
-	"bufio"
 	"encoding/json"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/grovetools/agentlogs/pkg/transcript"
 )
 
-// maxLineSize bounds a single JSONL line. Result-bearing lines can be large;
-// 16MB matches the workflow reader's journal buffer.
-const maxLineSize = 16 * 1024 * 1024
-
 // loadedEntry is one usage-bearing transcript line with the fields needed for
 // dedup, grouping, and cost. It is the Go analogue of ccusage's LoadedEntry.
 type loadedEntry struct {
@@ -75,14 +69,13 @@ type rawLine struct {
 // without message.usage are skipped (they carry no billing). Malformed lines are
 // skipped for format-drift tolerance; only an open error is returned.
 func loadFileEntries(path, sessionID, projectPath string) ([]loadedEntry, error) {
-	f, err := os.Open(path)
+	f, err := transcript.OpenTranscript(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	scanner := transcript.NewLineScanner(f)
 
 	var entries []loadedEntry
 	for scanner.Scan() {
@@ -138,14 +131,13 @@ type FileStats struct {
 // tokens` behaviour; latest_context_size is cache_read+cache_creation+input of
 // the last usage line. Malformed lines are skipped; only an open error returns.
 func FileTokenStats(path string) (FileStats, error) {
-	f, err := os.Open(path)
+	f, err := transcript.OpenTranscript(path)
 	if err != nil {
 		return FileStats{}, err
 	}
 	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	scanner := transcript.NewLineScanner(f)
 
 	var stats FileStats
 	for scanner.Scan() {
@@ -184,13 +176,12 @@ func FileTokenStats(path string) (FileStats, error) {
 // match a root agent-*.jsonl to its parent session (its inner sessionId equals
 // the parent session id). Returns "" when none is found in the first lines.
 func innerSessionID(path string) string {
-	f, err := os.Open(path)
+	f, err := transcript.OpenTranscript(path)
 	if err != nil {
 		return ""
 	}
 	defer f.Close()
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	scanner := transcript.NewLineScanner(f)
 	for i := 0; i < 50 && scanner.Scan(); i++ {
 		var raw struct {
 			SessionID string `json:"sessionId"`
@@ -1,8 +1,6 @@
 package usage
 
 import (
-	"bufio"
-	"os"
 	"strings"
 
 	"github.com/grovetools/agentlogs/pkg/transcript"
@@ -32,14 +30,13 @@ func FileTokenStatsForProvider(path, provider string) (FileStats, error) {
 // counts usage-bearing turns. Malformed lines are skipped; only an open
 // error returns.
 func codexFileTokenStats(path string) (FileStats, error) {
-	f, err := os.Open(path)
+	f, err := transcript.OpenTranscript(path)
 	if err != nil {
 		return FileStats{}, err
 	}
 	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	scanner := transcript.NewLineScanner(f)
 
 	var stats FileStats
 	var last transcript.CodexTokenCount
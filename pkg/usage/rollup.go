@@ -0,0 +1,195 @@
+package usage
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RollupDimensions lists the columns usage can be grouped by in a rollup, in
+// the order they are emitted when selected.
+var RollupDimensions = []string{"user", "host", "provider", "project", "model", "day", "week", "month"}
+
+// RollupOptions configures a usage rollup. User and Host identify the
+// machine the rollup ran on; they are supplied by the caller (not read from
+// the transcripts, which do not record them) so per-machine exports can be
+// concatenated and merged centrally.
+type RollupOptions struct {
+	GroupBy []string
+	User    string
+	Host    string
+	Since   time.Time
+	Mode    CostMode
+}
+
+// RollupRow is one group of a usage rollup.
+type RollupRow struct {
+	// Keys holds the group's dimension values, parallel to RollupOptions.GroupBy.
+	Keys           []string `json:"keys"`
+	Usage          Usage    `json:"usage"`
+	CostUSD        float64  `json:"cost_usd"`
+	MissingPricing bool     `json:"missing_pricing,omitempty"`
+	Sessions       int      `json:"sessions"`
+	MessageCount   int      `json:"message_count"`
+}
+
+// ParseRollupGroupBy validates a comma-separated --group-by value.
+func ParseRollupGroupBy(value string) ([]string, error) {
+	known := make(map[string]bool, len(RollupDimensions))
+	for _, d := range RollupDimensions {
+		known[d] = true
+	}
+	var dims []string
+	seen := make(map[string]bool)
+	for _, d := range strings.Split(value, ",") {
+		d = strings.TrimSpace(d)
+		if d == "" || seen[d] {
+			continue
+		}
+		if !known[d] {
+			return nil, fmt.Errorf("unknown group-by dimension %q (known: %s)", d, strings.Join(RollupDimensions, ", "))
+		}
+		seen[d] = true
+		dims = append(dims, d)
+	}
+	if len(dims) == 0 {
+		return nil, fmt.Errorf("at least one group-by dimension is required (known: %s)", strings.Join(RollupDimensions, ", "))
+	}
+	return dims, nil
+}
+
+// RollupUsage scans the given providers' stores (nil/empty = AllProviders)
+// and aggregates deduped, priced usage into one row per distinct combination
+// of the requested dimensions. Rows are sorted by their keys.
+func RollupUsage(providers []string, opts RollupOptions) ([]RollupRow, error) {
+	all, err := collectProviderEntries(providers)
+	if err != nil {
+		return nil, err
+	}
+	return rollupEntries(all, opts), nil
+}
+
+// rollupEntries applies the since filter and dedup (the same pipeline as
+// scanResultFromEntries) and then groups by the requested dimensions.
+func rollupEntries(all []loadedEntry, opts RollupOptions) []RollupRow {
+	pm := DefaultPricing()
+
+	if !opts.Since.IsZero() {
+		filtered := all[:0]
+		for _, e := range all {
+			if e.Timestamp.IsZero() || !e.Timestamp.Before(opts.Since) {
+				filtered = append(filtered, e)
+			}
+		}
+		all = filtered
+	}
+	all = dedupe(all)
+
+	rows := make(map[string]*RollupRow)
+	sessions := make(map[string]map[string]bool)
+	for _, e := range all {
+		keys := make([]string, len(opts.GroupBy))
+		for i, dim := range opts.GroupBy {
+			keys[i] = rollupKey(dim, e, opts)
+		}
+		id := strings.Join(keys, "\x00")
+		row, ok := rows[id]
+		if !ok {
+			row = &RollupRow{Keys: keys}
+			rows[id] = row
+			sessions[id] = make(map[string]bool)
+		}
+
+		cost, missing := EntryCost(e.Model, e.Usage, e.CostUSD, opts.Mode, pm)
+		row.Usage.Add(usageFromTranscript(e.Usage))
+		row.CostUSD += cost
+		row.MessageCount++
+		if missing != "" {
+			row.MissingPricing = true
+		}
+		sessions[id][e.Provider+"\x00"+e.ProjectPath+"\x00"+e.SessionID] = true
+	}
+
+	out := make([]RollupRow, 0, len(rows))
+	for id, row := range rows {
+		row.Sessions = len(sessions[id])
+		out = append(out, *row)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return strings.Join(out[i].Keys, "\x00") < strings.Join(out[j].Keys, "\x00")
+	})
+	return out
+}
+
+// rollupKey returns an entry's value for one grouping dimension. Time
+// buckets are computed in UTC so exports from machines in different time
+// zones merge cleanly.
+func rollupKey(dim string, e loadedEntry, opts RollupOptions) string {
+	ts := e.Timestamp.UTC()
+	switch dim {
+	case "user":
+		return opts.User
+	case "host":
+		return opts.Host
+	case "provider":
+		if e.Provider == "" {
+			return "claude"
+		}
+		return e.Provider
+	case "project":
+		return e.ProjectPath
+	case "model":
+		return e.Model
+	case "day":
+		if e.Timestamp.IsZero() {
+			return ""
+		}
+		return ts.Format("2006-01-02")
+	case "week":
+		if e.Timestamp.IsZero() {
+			return ""
+		}
+		year, week := ts.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case "month":
+		if e.Timestamp.IsZero() {
+			return ""
+		}
+		return ts.Format("2006-01")
+	}
+	return ""
+}
+
+// WriteRollupCSV writes rollup rows as CSV with a header row: the group-by
+// dimensions followed by the token classes, cost, and counts.
+func WriteRollupCSV(w io.Writer, groupBy []string, rows []RollupRow) error {
+	cw := csv.NewWriter(w)
+	header := append(append([]string{}, groupBy...),
+		"input_tokens", "output_tokens", "cache_read_tokens", "cache_write_5m_tokens",
+		"cache_write_1h_tokens", "total_tokens", "cost_usd", "sessions", "messages")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := append(append([]string{}, r.Keys...),
+			strconv.FormatInt(r.Usage.Input, 10),
+			strconv.FormatInt(r.Usage.Output, 10),
+			strconv.FormatInt(r.Usage.CacheRead, 10),
+			strconv.FormatInt(r.Usage.CacheWrite5m, 10),
+			strconv.FormatInt(r.Usage.CacheWrite1h, 10),
+			strconv.FormatInt(r.Usage.Total(), 10),
+			strconv.FormatFloat(r.CostUSD, 'f', 6, 64),
+			strconv.Itoa(r.Sessions),
+			strconv.Itoa(r.MessageCount),
+		)
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
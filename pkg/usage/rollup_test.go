@@ -0,0 +1,84 @@
+package usage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/agentlogs/pkg/transcript"
+)
+
+func TestRollupEntriesGroupsByWeekAndProject(t *testing.T) {
+	mon := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC) // ISO week 10
+	nextMon := mon.AddDate(0, 0, 7)                     // ISO week 11
+	cost := 0.5
+	mk := func(msgID, session, project string, ts time.Time, input int) loadedEntry {
+		return loadedEntry{
+			MessageID:   msgID,
+			RequestID:   "r-" + msgID,
+			SessionID:   session,
+			ProjectPath: project,
+			Provider:    "claude",
+			Timestamp:   ts,
+			Usage:       transcript.Usage{InputTokens: input},
+			CostUSD:     &cost,
+		}
+	}
+	entries := []loadedEntry{
+		mk("m1", "s1", "alpha", mon, 100),
+		mk("m2", "s1", "alpha", mon.Add(time.Hour), 50),
+		mk("m2", "s1", "alpha", mon.Add(time.Hour), 50), // duplicate response
+		mk("m3", "s2", "alpha", mon.Add(2*time.Hour), 10),
+		mk("m4", "s3", "beta", nextMon, 7),
+	}
+
+	rows := rollupEntries(entries, RollupOptions{GroupBy: []string{"user", "project", "week"}, User: "ada"})
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2: %+v", len(rows), rows)
+	}
+	alpha := rows[0]
+	if got := strings.Join(alpha.Keys, ","); got != "ada,alpha,2025-W10" {
+		t.Errorf("alpha keys = %s", got)
+	}
+	if alpha.Usage.Input != 160 || alpha.Sessions != 2 || alpha.MessageCount != 3 {
+		t.Errorf("alpha row = %+v, want input 160, 2 sessions, 3 messages", alpha)
+	}
+	if alpha.CostUSD != 1.5 {
+		t.Errorf("alpha cost = %v, want 1.5", alpha.CostUSD)
+	}
+	if got := strings.Join(rows[1].Keys, ","); got != "ada,beta,2025-W11" {
+		t.Errorf("beta keys = %s", got)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteRollupCSV(&buf, []string{"user", "project", "week"}, rows); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d CSV lines, want header + 2", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "user,project,week,input_tokens,") {
+		t.Errorf("header = %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "ada,alpha,2025-W10,160,") {
+		t.Errorf("row = %s", lines[1])
+	}
+}
+
+func TestParseRollupGroupBy(t *testing.T) {
+	dims, err := ParseRollupGroupBy("user, project,week,user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(dims, ",") != "user,project,week" {
+		t.Errorf("dims = %v", dims)
+	}
+	if _, err := ParseRollupGroupBy("team"); err == nil {
+		t.Error("expected error for unknown dimension")
+	}
+	if _, err := ParseRollupGroupBy(""); err == nil {
+		t.Error("expected error for empty group-by")
+	}
+}
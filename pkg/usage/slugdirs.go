@@ -44,24 +44,26 @@ func SlugDirsForSession(claudeSessionID, transcriptPath string) []string {
 
 // sessionDirsForID globs every …/projects/*/<claudeSessionID> per-session
 // directory. It mirrors core sessions.ResolveClaudeSessionDirs but honors this
-// package's claudeProjectsDir override (CLAUDE_CONFIG_DIR), keeping it in step
+// package's claudeProjectsDirs override (CLAUDE_CONFIG_DIR), keeping it in step
 // with the rest of the usage summarizer and avoiding a core import here.
 func sessionDirsForID(claudeSessionID string) []string {
 	if claudeSessionID == "" {
 		return nil
 	}
-	root, err := claudeProjectsDir()
-	if err != nil {
-		return nil
-	}
-	matches, err := filepath.Glob(filepath.Join(root, "*", claudeSessionID))
+	roots, err := claudeProjectsDirs()
 	if err != nil {
 		return nil
 	}
 	var dirs []string
-	for _, m := range matches {
-		if info, err := os.Stat(m); err == nil && info.IsDir() {
-			dirs = append(dirs, m)
+	for _, root := range roots {
+		matches, err := filepath.Glob(filepath.Join(root, "*", claudeSessionID))
+		if err != nil {
+			continue
+		}
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && info.IsDir() {
+				dirs = append(dirs, m)
+			}
 		}
 	}
 	return dirs
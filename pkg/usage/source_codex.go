@@ -1,9 +1,7 @@
 package usage
 
 import (
-	"bufio"
 	"encoding/json"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -12,8 +10,8 @@ import (
 )
 
 // codexUsageSource scans codex rollout transcripts under the nested
-// ~/.codex/sessions/YYYY/MM/DD layout (transcript.CodexSessionsGlob is the
-// single definition of that layout).
+// YYYY/MM/DD layout of each codex source root (~/.codex/sessions by
+// default).
 type codexUsageSource struct{}
 
 func (codexUsageSource) Provider() string { return "codex" }
@@ -21,13 +19,9 @@ func (codexUsageSource) Provider() string { return "codex" }
 // CollectEntries loads per-turn usage entries from every codex rollout file.
 // A missing ~/.codex/sessions store yields (nil, nil).
 func (codexUsageSource) CollectEntries() ([]loadedEntry, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
-	matches, err := filepath.Glob(transcript.CodexSessionsGlob(homeDir, ""))
+	matches, err := sourceTranscripts("codex")
 	if err != nil || len(matches) == 0 {
-		return nil, nil
+		return nil, err
 	}
 	var all []loadedEntry
 	for _, path := range matches {
@@ -65,7 +59,7 @@ type codexRolloutLine struct {
 // files. Session id prefers session_meta payload.id, falling back to the
 // rollout filename's trailing uuid.
 func codexTranscriptEntries(path string) ([]loadedEntry, error) {
-	f, err := os.Open(path)
+	f, err := transcript.OpenTranscript(path)
 	if err != nil {
 		return nil, err
 	}
@@ -75,8 +69,7 @@ func codexTranscriptEntries(path string) ([]loadedEntry, error) {
 	projectPath := ""
 	model := ""
 
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	scanner := transcript.NewLineScanner(f)
 
 	var entries []loadedEntry
 	var prevTotal transcript.UnifiedTokens
@@ -172,7 +165,7 @@ func codexUsageDelta(prev transcript.UnifiedTokens, tc transcript.CodexTokenCoun
 // filename (rollout-<timestamp>-<uuid>.jsonl), falling back to the whole base
 // name without extension.
 func codexSessionIDFromFilename(path string) string {
-	base := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	base := transcript.TrimTranscriptExt(filepath.Base(path))
 	// The uuid is the last 5 dash-separated groups (8-4-4-4-12 hex).
 	parts := strings.Split(base, "-")
 	if len(parts) >= 5 {
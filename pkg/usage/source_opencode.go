@@ -18,17 +18,25 @@ type opencodeUsageSource struct{}
 func (opencodeUsageSource) Provider() string { return "opencode" }
 
 // CollectEntries loads per-message usage entries for every opencode session
-// found under the default storage root. A missing store yields (nil, nil).
+// found under the storage roots of the sources config. A missing store
+// yields (nil, nil).
 func (opencodeUsageSource) CollectEntries() ([]loadedEntry, error) {
-	homeDir, err := os.UserHomeDir()
+	roots, err := sourceRoots("opencode")
 	if err != nil {
 		return nil, err
 	}
-	storageDir := filepath.Join(homeDir, ".local", "share", "opencode", "storage")
-	if _, err := os.Stat(storageDir); err != nil {
-		return nil, nil
+	var all []loadedEntry
+	for _, storageDir := range roots {
+		if _, err := os.Stat(storageDir); err != nil {
+			continue
+		}
+		entries, err := collectOpenCodeEntries(storageDir)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, entries...)
 	}
-	return collectOpenCodeEntries(storageDir)
+	return all, nil
 }
 
 // collectOpenCodeEntries walks <storage>/session/<projectID>/ses_*.json and
@@ -1,9 +1,7 @@
 package usage
 
 import (
-	"bufio"
 	"encoding/json"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -12,8 +10,8 @@ import (
 )
 
 // piUsageSource scans pi session transcripts under the munged-cwd
-// ~/.pi/agent/sessions/--<cwd>--/ layout (transcript.PiSessionsGlob is the
-// single definition of that layout).
+// --<cwd>--/ layout of each pi source root (~/.pi/agent/sessions by
+// default).
 type piUsageSource struct{}
 
 func (piUsageSource) Provider() string { return "pi" }
@@ -21,13 +19,9 @@ func (piUsageSource) Provider() string { return "pi" }
 // CollectEntries loads per-message usage entries from every pi session file.
 // A missing ~/.pi/agent/sessions store yields (nil, nil).
 func (piUsageSource) CollectEntries() ([]loadedEntry, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
-	matches, err := filepath.Glob(transcript.PiSessionsGlob(homeDir, ""))
+	matches, err := sourceTranscripts("pi")
 	if err != nil || len(matches) == 0 {
-		return nil, nil
+		return nil, err
 	}
 	var all []loadedEntry
 	for _, path := range matches {
@@ -75,20 +69,19 @@ type piSessionLine struct {
 // the filename (<timestamp>_<uuid>.jsonl); the project path is the header's
 // cwd, falling back to the munged per-cwd directory name.
 func piTranscriptEntries(path string) ([]loadedEntry, error) {
-	f, err := os.Open(path)
+	f, err := transcript.OpenTranscript(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
-	sessionID := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	sessionID := transcript.TrimTranscriptExt(filepath.Base(path))
 	if i := strings.LastIndex(sessionID, "_"); i >= 0 && i+1 < len(sessionID) {
 		sessionID = sessionID[i+1:]
 	}
 	projectPath := filepath.Base(filepath.Dir(path))
 
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	scanner := transcript.NewLineScanner(f)
 
 	var entries []loadedEntry
 	for scanner.Scan() {
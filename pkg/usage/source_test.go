@@ -4,6 +4,7 @@ import (
 	"math"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/grovetools/agentlogs/pkg/transcript"
 )
@@ -110,6 +111,23 @@ func TestPiTranscriptEntries_NativeCostAllBranches(t *testing.T) {
 	}
 }
 
+func TestSummarizeTranscriptWindow(t *testing.T) {
+	// The fixture's usage-bearing messages land at :02 (1200 in), :04 (10 in)
+	// and :06 (2000 in). A window over :03-:06 keeps the last two.
+	from := time.Date(2026, 7, 1, 10, 0, 3, 0, time.UTC)
+	to := time.Date(2026, 7, 1, 10, 0, 6, 0, time.UTC)
+	s, err := SummarizeTranscriptWindow(filepath.FromSlash(piFixture), "pi", CostModeCalculate, from, to)
+	if err != nil {
+		t.Fatalf("SummarizeTranscriptWindow: %v", err)
+	}
+	if s.Usage.Input != 2010 || s.MessageCount != 2 {
+		t.Errorf("windowed usage = %+v (%d messages), want input 2010 over 2 messages", s.Usage, s.MessageCount)
+	}
+	if math.Abs(s.CostUSD-0.0107) > 1e-9 {
+		t.Errorf("windowed cost = %v, want 0.0107", s.CostUSD)
+	}
+}
+
 func TestOpenCodeUsageSource_CollectEntries(t *testing.T) {
 	entries, err := collectOpenCodeEntries(filepath.FromSlash("testdata/opencode/storage"))
 	if err != nil {
@@ -3,6 +3,7 @@ package usage
 import (
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/grovetools/agentlogs/internal/opencode"
 )
@@ -26,6 +27,14 @@ import (
 //     through the fragment assembler (same derivation as
 //     opencodeFileTokenStats).
 func SummarizeSessionTranscript(path, provider string, mode CostMode) (Summary, error) {
+	return SummarizeTranscriptWindow(path, provider, mode, time.Time{}, time.Time{})
+}
+
+// SummarizeTranscriptWindow is SummarizeSessionTranscript restricted to the
+// usage entries timestamped within [from, to] (either bound may be zero to
+// leave that side open). It is how a single plan job's share of a session
+// is priced: the job's line range maps to the time span of its messages.
+func SummarizeTranscriptWindow(path, provider string, mode CostMode, from, to time.Time) (Summary, error) {
 	pm := DefaultPricing()
 
 	var entries []loadedEntry
@@ -51,6 +60,19 @@ func SummarizeSessionTranscript(path, provider string, mode CostMode) (Summary,
 		projectPath = entries[0].ProjectPath
 	}
 
+	if !from.IsZero() || !to.IsZero() {
+		windowed := entries[:0]
+		for _, e := range entries {
+			if e.Timestamp.IsZero() ||
+				(!from.IsZero() && e.Timestamp.Before(from)) ||
+				(!to.IsZero() && e.Timestamp.After(to)) {
+				continue
+			}
+			windowed = append(windowed, e)
+		}
+		entries = windowed
+	}
+
 	entries = dedupe(entries)
 	return summarize(sessionID, projectPath, entries, nil, mode, pm), nil
 }
@@ -4,7 +4,6 @@
 package workflow
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -15,11 +14,6 @@ import (
 	"github.com/grovetools/core/pkg/workflows"
 )
 
-// journalBufferSize is the max journal/transcript line size. Journal result
-// events embed full agent results on a single line and can be far larger
-// than bufio.Scanner's 64KB default token limit.
-const journalBufferSize = 16 * 1024 * 1024
-
 // AgentRun is one agent's record within a workflow run.
 type AgentRun struct {
 	// Started is true when the journal recorded a "started" event.
@@ -118,8 +112,7 @@ func readJournal(path string, run *WorkflowRun) error {
 	}
 	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 64*1024), journalBufferSize)
+	scanner := transcript.NewLineScanner(f)
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -169,8 +162,7 @@ func readAgentTranscript(path, agentID string) ([]transcript.UnifiedEntry, error
 	defer f.Close()
 
 	normalizer := transcript.NewClaudeNormalizer()
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 64*1024), journalBufferSize)
+	scanner := transcript.NewLineScanner(f)
 
 	var entries []transcript.UnifiedEntry
 	add := func(entry *transcript.UnifiedEntry) {